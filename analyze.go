@@ -0,0 +1,295 @@
+package gopresentation
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// LintSeverity is the severity of a lint issue.
+type LintSeverity int
+
+const (
+	LintInfo LintSeverity = iota
+	LintWarning
+	LintError
+)
+
+// String returns the severity name.
+func (s LintSeverity) String() string {
+	switch s {
+	case LintInfo:
+		return "info"
+	case LintWarning:
+		return "warning"
+	case LintError:
+		return "error"
+	}
+	return fmt.Sprintf("LintSeverity(%d)", int(s))
+}
+
+// Lint rule identifiers reported in LintIssue.Rule.
+const (
+	LintRuleMinFontSize  = "min-font-size"
+	LintRuleOffSlide     = "off-slide"
+	LintRuleMissingTitle = "missing-title"
+)
+
+// LintIssue is a problem found by Analyze.
+type LintIssue struct {
+	Severity LintSeverity
+	Rule     string
+	Slide    int    // 1-based slide number
+	Shape    string // shape name; empty for slide-level issues
+	Message  string
+}
+
+// String formats the issue as "slide N: severity: message".
+func (i LintIssue) String() string {
+	if i.Shape != "" {
+		return fmt.Sprintf("slide %d: %s: %s: %s", i.Slide, i.Severity, i.Shape, i.Message)
+	}
+	return fmt.Sprintf("slide %d: %s: %s", i.Slide, i.Severity, i.Message)
+}
+
+// SlideStats holds the statistics of one slide.
+type SlideStats struct {
+	Number     int // 1-based slide number
+	Shapes     int // top-level shapes
+	Words      int
+	Characters int
+	// TextDensity is the number of text characters per square inch of slide.
+	TextDensity float64
+}
+
+// DeckStats holds presentation-wide statistics.
+type DeckStats struct {
+	SlideCount int
+	// ShapeCounts counts shapes, including those inside groups, by type:
+	// "text", "placeholder", "picture", "table", "autoshape", "line",
+	// "chart" and "group".
+	ShapeCounts map[string]int
+	MediaCount  int
+	MediaBytes  int64
+	// Fonts lists the distinct font names used by text runs, sorted.
+	Fonts  []string
+	Slides []SlideStats
+}
+
+// Analysis is the result of Presentation.Analyze.
+type Analysis struct {
+	Stats  DeckStats
+	Issues []LintIssue
+}
+
+// AnalyzeOptions configures the lint rules of Analyze.
+type AnalyzeOptions struct {
+	// MinFontSize is the smallest font size in points not reported by the
+	// min-font-size rule. Default: 12.
+	MinFontSize int
+	// RequireTitles reports slides without a title placeholder.
+	// Default: true.
+	RequireTitles bool
+}
+
+// DefaultAnalyzeOptions returns the default analysis options.
+func DefaultAnalyzeOptions() *AnalyzeOptions {
+	return &AnalyzeOptions{
+		MinFontSize:   12,
+		RequireTitles: true,
+	}
+}
+
+// Analyze gathers deck statistics and runs the lint rules with the default
+// options.
+func (p *Presentation) Analyze() *Analysis {
+	return p.AnalyzeWithOptions(nil)
+}
+
+// AnalyzeWithOptions gathers deck statistics and runs the lint rules:
+// text smaller than opts.MinFontSize (warning), shapes partly (warning) or
+// entirely (error) outside the slide, and slides without a title
+// placeholder (warning). A nil opts uses DefaultAnalyzeOptions.
+func (p *Presentation) AnalyzeWithOptions(opts *AnalyzeOptions) *Analysis {
+	if opts == nil {
+		opts = DefaultAnalyzeOptions()
+	}
+	a := &Analysis{
+		Stats: DeckStats{
+			SlideCount:  len(p.slides),
+			ShapeCounts: make(map[string]int),
+		},
+	}
+	fonts := make(map[string]bool)
+
+	var slideArea float64
+	var slideW, slideH int64
+	if p.layout != nil {
+		slideW, slideH = p.layout.CX, p.layout.CY
+		slideArea = EMUToInch(slideW) * EMUToInch(slideH)
+	}
+
+	for i, slide := range p.slides {
+		st := SlideStats{Number: i + 1, Shapes: len(slide.shapes)}
+		hasTitle := false
+		for _, shape := range slide.shapes {
+			if ph, ok := shape.(*PlaceholderShape); ok && (ph.phType == PlaceholderTitle || ph.phType == PlaceholderCtrTitle) {
+				hasTitle = true
+			}
+			a.Stats.collectShape(shape, &st, fonts)
+
+			name := shapeDisplayName(shape)
+			if minSize, ok := smallestFontSize(shape); ok && minSize < opts.MinFontSize {
+				a.Issues = append(a.Issues, LintIssue{
+					Severity: LintWarning,
+					Rule:     LintRuleMinFontSize,
+					Slide:    i + 1,
+					Shape:    name,
+					Message:  fmt.Sprintf("text uses %dpt, below the %dpt minimum", minSize, opts.MinFontSize),
+				})
+			}
+			if slideW > 0 && slideH > 0 {
+				x, y := shape.GetOffsetX(), shape.GetOffsetY()
+				w, h := shape.GetWidth(), shape.GetHeight()
+				switch {
+				case x >= slideW || y >= slideH || x+w <= 0 || y+h <= 0:
+					a.Issues = append(a.Issues, LintIssue{
+						Severity: LintError,
+						Rule:     LintRuleOffSlide,
+						Slide:    i + 1,
+						Shape:    name,
+						Message:  "shape is entirely outside the slide",
+					})
+				case x < 0 || y < 0 || x+w > slideW || y+h > slideH:
+					a.Issues = append(a.Issues, LintIssue{
+						Severity: LintWarning,
+						Rule:     LintRuleOffSlide,
+						Slide:    i + 1,
+						Shape:    name,
+						Message:  "shape extends beyond the slide edge",
+					})
+				}
+			}
+		}
+		if opts.RequireTitles && !hasTitle {
+			a.Issues = append(a.Issues, LintIssue{
+				Severity: LintWarning,
+				Rule:     LintRuleMissingTitle,
+				Slide:    i + 1,
+				Message:  "slide has no title placeholder",
+			})
+		}
+		if slideArea > 0 {
+			st.TextDensity = float64(st.Characters) / slideArea
+		}
+		a.Stats.Slides = append(a.Stats.Slides, st)
+	}
+
+	for name := range fonts {
+		a.Stats.Fonts = append(a.Stats.Fonts, name)
+	}
+	sort.Strings(a.Stats.Fonts)
+	return a
+}
+
+// collectShape adds shape, and the children of groups, to the statistics.
+func (d *DeckStats) collectShape(shape Shape, st *SlideStats, fonts map[string]bool) {
+	countText := func(paras []*Paragraph) {
+		for _, para := range paras {
+			for _, elem := range para.elements {
+				tr, ok := elem.(*TextRun)
+				if !ok {
+					continue
+				}
+				st.Words += len(strings.Fields(tr.text))
+				st.Characters += utf8.RuneCountInString(tr.text)
+				if tr.font != nil {
+					if tr.font.Name != "" {
+						fonts[tr.font.Name] = true
+					}
+					if tr.font.NameEA != "" {
+						fonts[tr.font.NameEA] = true
+					}
+				}
+			}
+		}
+	}
+
+	switch s := shape.(type) {
+	case *RichTextShape:
+		d.ShapeCounts["text"]++
+		countText(s.paragraphs)
+	case *PlaceholderShape:
+		d.ShapeCounts["placeholder"]++
+		countText(s.paragraphs)
+	case *DrawingShape:
+		d.ShapeCounts["picture"]++
+		d.MediaCount++
+		if s.data != nil {
+			d.MediaBytes += int64(len(s.data))
+		} else if s.path != "" {
+			if info, err := os.Stat(s.path); err == nil {
+				d.MediaBytes += info.Size()
+			}
+		}
+	case *TableShape:
+		d.ShapeCounts["table"]++
+		for _, row := range s.rows {
+			for _, cell := range row {
+				countText(cell.paragraphs)
+			}
+		}
+	case *AutoShape:
+		d.ShapeCounts["autoshape"]++
+		if len(s.paragraphs) > 0 {
+			countText(s.paragraphs)
+		} else if s.text != "" {
+			st.Words += len(strings.Fields(s.text))
+			st.Characters += utf8.RuneCountInString(s.text)
+		}
+	case *LineShape:
+		d.ShapeCounts["line"]++
+	case *ChartShape:
+		d.ShapeCounts["chart"]++
+	case *GroupShape:
+		d.ShapeCounts["group"]++
+		for _, child := range s.shapes {
+			d.collectShape(child, st, fonts)
+		}
+	}
+}
+
+// smallestFontSize returns the smallest run font size in shape, including
+// group children, and whether the shape has any sized text.
+func smallestFontSize(shape Shape) (int, bool) {
+	minSize, found := 0, false
+	for _, para := range shapeParagraphs(shape) {
+		for _, elem := range para.elements {
+			tr, ok := elem.(*TextRun)
+			if !ok || tr.font == nil || tr.font.Size <= 0 || strings.TrimSpace(tr.text) == "" {
+				continue
+			}
+			if !found || tr.font.Size < minSize {
+				minSize, found = tr.font.Size, true
+			}
+		}
+	}
+	if g, ok := shape.(*GroupShape); ok {
+		for _, child := range g.shapes {
+			if size, ok := smallestFontSize(child); ok && (!found || size < minSize) {
+				minSize, found = size, true
+			}
+		}
+	}
+	return minSize, found
+}
+
+// shapeDisplayName returns the shape name, or its type when unnamed.
+func shapeDisplayName(shape Shape) string {
+	if name := shape.GetName(); name != "" {
+		return name
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", shape), "*gopresentation.")
+}
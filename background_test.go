@@ -0,0 +1,62 @@
+package gopresentation
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestInheritedBackgroundIsNotWritten(t *testing.T) {
+	p := New()
+	p.GetActiveSlide().CreateRichTextShape().CreateTextRun("text")
+	data := writePackage(t, p)
+	layout := string(packageParts(t, data, "ppt/slideLayouts/slideLayout1.xml")["ppt/slideLayouts/slideLayout1.xml"])
+	layout = strings.Replace(layout, `<p:cSld name="Blank">`,
+		`<p:cSld name="Blank"><p:bg><p:bgPr><a:solidFill><a:srgbClr val="0000FF"/></a:solidFill><a:effectLst/></p:bgPr></p:bg>`, 1)
+	data = replacePart(t, data, "ppt/slideLayouts/slideLayout1.xml", []byte(layout))
+
+	read := readPackage(t, data)
+	slide, err := read.GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bg := slide.GetBackground(); bg != nil {
+		t.Errorf("background = %+v, want none for a slide inheriting its layout's", bg)
+	}
+	slideXML := packageParts(t, writePackage(t, read), "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	if bytes.Contains(slideXML, []byte("<p:bg>")) {
+		t.Error("inherited background written to the slide")
+	}
+
+	var buf bytes.Buffer
+	if err := read.RenderSlide(0, &buf, &RenderOptions{Width: 96, Format: ImageFormatPNG}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := img.Bounds().Max
+	r, g, b, _ := img.At(end.X-1, end.Y-1).RGBA()
+	if r>>8 != 0 || g>>8 != 0 || b>>8 != 0xFF {
+		t.Errorf("corner pixel = %02X%02X%02X, want the layout's 0000FF", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestPictureBackgroundWriting(t *testing.T) {
+	p := New()
+	p.GetActiveSlide().SetBackground(NewFill().SetPicture(nil, "image/png"))
+	p.CreateSlide().SetBackground(NewFill().SetPicture([]byte("\x89PNG\r\n\x1a\n"), "image/png").SetTile(&FillTile{ScaleX: 50000, ScaleY: 50000}))
+	data := writePackage(t, p)
+	checkWellFormed(t, data)
+
+	parts := packageParts(t, data, "ppt/slides/slide")
+	if first := string(parts["ppt/slides/slide1.xml"]); strings.Contains(first, "<p:bg>") {
+		t.Errorf("picture background without data written: %s", first)
+	}
+	second := string(parts["ppt/slides/slide2.xml"])
+	if !strings.Contains(second, `<a:tile tx="0" ty="0" sx="50000" sy="50000"/>`) {
+		t.Errorf("tile with empty flip and alignment not written with their defaults: %s", second)
+	}
+}
@@ -0,0 +1,69 @@
+package gopresentation
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// newBenchmarkDeck returns a deck of text-heavy slides, each with shapes
+// text boxes and a table, the parts whose XML is streamed into pooled
+// buffers.
+func newBenchmarkDeck(slides, shapes int) *Presentation {
+	p := New()
+	for i := 0; i < slides; i++ {
+		slide := p.GetActiveSlide()
+		if i > 0 {
+			slide = p.CreateSlide()
+		}
+		for j := 0; j < shapes; j++ {
+			shape := slide.CreateRichTextShape()
+			shape.SetOffsetX(int64(j) * 100000).SetOffsetY(int64(j) * 500000).SetWidth(6000000).SetHeight(400000)
+			for k := 0; k < 4; k++ {
+				para := shape.CreateParagraph()
+				run := para.CreateTextRun(fmt.Sprintf("Slide %d, shape %d, paragraph %d & <more>", i, j, k))
+				run.GetFont().SetBold(k%2 == 0)
+			}
+		}
+		table := slide.CreateTableShape(6, 4)
+		for r := 0; r < 6; r++ {
+			for c := 0; c < 4; c++ {
+				table.GetCell(r, c).SetText(fmt.Sprintf("R%dC%d", r, c))
+			}
+		}
+	}
+	return p
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	p := newBenchmarkDeck(20, 5)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := p.WriteTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkWriteToLargeDeck writes a deck of 10,000 shapes.
+func BenchmarkWriteToLargeDeck(b *testing.B) {
+	p := newBenchmarkDeck(400, 24)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := p.WriteTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadFrom(b *testing.B) {
+	data := writePackage(b, newBenchmarkDeck(20, 5))
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ReadFrom(bytes.NewReader(data), int64(len(data))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -78,3 +78,108 @@ func (b *Bullet) SetSize(pct int) *Bullet {
 	b.Size = pct
 	return b
 }
+
+// ListLevelStyle holds the paragraph defaults a text body defines once per
+// list level in its lstStyle (a:lvl1pPr through a:lvl9pPr). Paragraphs at that
+// level inherit any of these they do not set themselves. Nil fields are not
+// defined for the level.
+type ListLevelStyle struct {
+	Alignment *Alignment // Horizontal, MarginLeft and Indent are used
+	Bullet    *Bullet
+	Font      *Font // default run properties; zero fields are not defined
+}
+
+// NewListLevelStyle creates an empty list level style.
+func NewListLevelStyle() *ListLevelStyle {
+	return &ListLevelStyle{}
+}
+
+// hasListLevels reports whether any list level style is defined.
+func hasListLevels(levels *[9]*ListLevelStyle) bool {
+	for _, l := range levels {
+		if l != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveListStyle returns copies of paragraphs with the list level styles
+// applied wherever a paragraph does not set the property itself. Run fonts
+// still at their NewFont defaults take the level's default run properties.
+// When fontsOnly is set, bullets and indents are left to be inherited by the
+// consumer (e.g. PowerPoint reading the written lstStyle).
+func resolveListStyle(levels *[9]*ListLevelStyle, paragraphs []*Paragraph, fontsOnly bool) []*Paragraph {
+	if !hasListLevels(levels) {
+		return paragraphs
+	}
+	out := make([]*Paragraph, len(paragraphs))
+	for i, para := range paragraphs {
+		lvl := 0
+		if para.alignment != nil {
+			lvl = para.alignment.Level
+		}
+		if lvl < 0 || lvl >= len(levels) || levels[lvl] == nil {
+			out[i] = para
+			continue
+		}
+		style := levels[lvl]
+		c := *para
+		if !fontsOnly {
+			if c.bullet == nil && style.Bullet != nil {
+				c.bullet = style.Bullet
+			}
+			if style.Alignment != nil {
+				a := NewAlignment()
+				if para.alignment != nil {
+					*a = *para.alignment
+				}
+				if a.MarginLeft == 0 && a.Indent == 0 {
+					a.MarginLeft = style.Alignment.MarginLeft
+					a.Indent = style.Alignment.Indent
+				}
+				if a.Horizontal == HorizontalLeft && style.Alignment.Horizontal != "" {
+					a.Horizontal = style.Alignment.Horizontal
+				}
+				c.alignment = a
+			}
+		}
+		if style.Font != nil {
+			c.elements = make([]ParagraphElement, len(para.elements))
+			for j, elem := range para.elements {
+				if tr, ok := elem.(*TextRun); ok && tr.font != nil {
+					r := *tr
+					r.font = inheritListFont(tr.font, style.Font)
+					c.elements[j] = &r
+				} else {
+					c.elements[j] = elem
+				}
+			}
+		}
+		out[i] = &c
+	}
+	return out
+}
+
+// inheritListFont returns f with the properties it leaves at their NewFont
+// defaults taken from the level font def.
+func inheritListFont(f, def *Font) *Font {
+	base := NewFont()
+	out := *f
+	if def.Size > 0 && f.Size == base.Size {
+		out.Size = def.Size
+	}
+	if def.Name != "" && f.Name == base.Name {
+		out.Name = def.Name
+	}
+	if def.Bold {
+		out.Bold = true
+	}
+	if def.Italic {
+		out.Italic = true
+	}
+	if def.Color.ARGB != "" && f.Color.ARGB == base.Color.ARGB && f.Color.nTransforms == 0 {
+		out.Color = def.Color
+	}
+	return &out
+}
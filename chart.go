@@ -10,7 +10,37 @@ type ChartShape struct {
 	legend      *ChartLegend
 	view3D      *View3D
 	displayBlankAs string
-}
+	// style is the chart style ID written to the chart's style part; 0
+	// writes no style parts.
+	style int
+	// dataTable shows the values in a table below the plot area, with
+	// the series legend keys when dataTableKeys is set.
+	dataTable     bool
+	dataTableKeys bool
+	// dataSource is where the series data is written.
+	dataSource ChartDataSource
+}
+
+// Chart style IDs. PowerPoint numbers its chart styles from 201; the
+// style and colors parts give new charts PowerPoint's current look rather
+// than the Office 2007 defaults.
+const (
+	ChartStyleNone    = 0
+	ChartStyleDefault = 201
+)
+
+// ChartDataSource is where a chart keeps its series data.
+type ChartDataSource int
+
+// Chart data sources.
+const (
+	// ChartDataCache writes the values only as caches in the chart part.
+	// PowerPoint shows the chart but cannot edit its data.
+	ChartDataCache ChartDataSource = iota
+	// ChartDataWorkbook also embeds a workbook with the values, which the
+	// series reference by range, so that Edit Data opens and updates them.
+	ChartDataWorkbook
+)
 
 // Chart display blank constants.
 const (
@@ -29,6 +59,7 @@ func NewChartShape() *ChartShape {
 		legend:         NewChartLegend(),
 		view3D:         NewView3D(),
 		displayBlankAs: ChartBlankAsZero,
+		style:          ChartStyleDefault,
 	}
 }
 
@@ -45,16 +76,54 @@ func (c *ChartShape) GetLegend() *ChartLegend { return c.legend }
 func (c *ChartShape) GetView3D() *View3D { return c.view3D }
 
 // SetDisplayBlankAs sets how blank values are displayed.
-func (c *ChartShape) SetDisplayBlankAs(mode string) { c.displayBlankAs = mode }
+func (c *ChartShape) SetDisplayBlankAs(mode string) *ChartShape { c.displayBlankAs = mode; return c }
 
 // GetDisplayBlankAs returns how blank values are displayed.
 func (c *ChartShape) GetDisplayBlankAs() string { return c.displayBlankAs }
 
+// SetStyle sets the chart style ID written with the chart, such as
+// ChartStyleDefault. ChartStyleNone writes the chart without style parts.
+func (c *ChartShape) SetStyle(id int) *ChartShape { c.style = id; return c }
+
+// GetStyle returns the chart style ID.
+func (c *ChartShape) GetStyle() int { return c.style }
+
+// SetDataSource sets where the chart keeps its series data. The default,
+// ChartDataCache, writes the values only in the chart part.
+func (c *ChartShape) SetDataSource(src ChartDataSource) *ChartShape { c.dataSource = src; return c }
+
+// GetDataSource returns where the chart keeps its series data.
+func (c *ChartShape) GetDataSource() ChartDataSource { return c.dataSource }
+
+// ShowDataTable shows the chart values in a data table below the plot
+// area, one row per series, with the series legend keys before the series
+// names when withLegendKeys is set. Pie and doughnut charts have no data
+// table.
+func (c *ChartShape) ShowDataTable(withLegendKeys bool) {
+	c.dataTable = true
+	c.dataTableKeys = withLegendKeys
+}
+
+// HideDataTable removes the data table.
+func (c *ChartShape) HideDataTable() {
+	c.dataTable = false
+	c.dataTableKeys = false
+}
+
+// HasDataTable reports whether the chart shows a data table, and whether
+// the table shows legend keys.
+func (c *ChartShape) HasDataTable() (shown, legendKeys bool) {
+	return c.dataTable, c.dataTableKeys
+}
+
 // ChartTitle represents a chart title.
 type ChartTitle struct {
 	Text    string
 	Visible bool
 	Font    *Font
+	// paragraphs, when set, replace Text and Font with rich text
+	// (multiple runs, colors and lines).
+	paragraphs []*Paragraph
 }
 
 // NewChartTitle creates a new chart title.
@@ -77,6 +146,42 @@ func (ct *ChartTitle) SetVisible(v bool) *ChartTitle {
 	return ct
 }
 
+// CreateParagraph appends a rich text paragraph to the title. Once the title
+// has paragraphs they are used instead of Text and Font.
+func (ct *ChartTitle) CreateParagraph() *Paragraph {
+	p := NewParagraph()
+	ct.paragraphs = append(ct.paragraphs, p)
+	return p
+}
+
+// GetParagraphs returns the rich text paragraphs of the title.
+func (ct *ChartTitle) GetParagraphs() []*Paragraph { return ct.paragraphs }
+
+// SetParagraphs replaces the rich text paragraphs of the title.
+func (ct *ChartTitle) SetParagraphs(paras []*Paragraph) *ChartTitle {
+	ct.paragraphs = paras
+	return ct
+}
+
+// centeredParagraphs returns the title paragraphs with the default left
+// alignment replaced by centering, as chart titles are laid out centered.
+func (ct *ChartTitle) centeredParagraphs() []*Paragraph {
+	paras := make([]*Paragraph, len(ct.paragraphs))
+	for i, para := range ct.paragraphs {
+		c := *para
+		if c.alignment == nil || c.alignment.Horizontal == HorizontalLeft {
+			a := NewAlignment()
+			if para.alignment != nil {
+				*a = *para.alignment
+			}
+			a.Horizontal = HorizontalCenter
+			c.alignment = a
+		}
+		paras[i] = &c
+	}
+	return paras
+}
+
 // PlotArea represents the chart plot area.
 type PlotArea struct {
 	chartType ChartType
@@ -274,6 +379,9 @@ type ChartLegend struct {
 	Visible  bool
 	Position LegendPosition
 	Font     *Font
+	// entryFonts overrides the text formatting of single legend entries,
+	// keyed by entry index.
+	entryFonts map[int]*Font
 }
 
 // LegendPosition represents the legend position.
@@ -296,6 +404,24 @@ func NewChartLegend() *ChartLegend {
 	}
 }
 
+// SetEntryFont overrides the text formatting of the legend entry at idx.
+func (l *ChartLegend) SetEntryFont(idx int, f *Font) *ChartLegend {
+	if l.entryFonts == nil {
+		l.entryFonts = make(map[int]*Font)
+	}
+	l.entryFonts[idx] = f
+	return l
+}
+
+// GetEntryFont returns the font of the legend entry at idx, falling back to
+// the legend font when the entry has no override.
+func (l *ChartLegend) GetEntryFont(idx int) *Font {
+	if f, ok := l.entryFonts[idx]; ok && f != nil {
+		return f
+	}
+	return l.Font
+}
+
 // View3D represents 3D view settings.
 type View3D struct {
 	RotX          int
@@ -343,6 +469,14 @@ type ChartSeries struct {
 	Font              *Font
 	Outline           *SeriesOutline
 	Marker            *SeriesMarker
+
+	// Smooth overrides the smoothing of the chart for this series of a
+	// line or scatter chart. Nil uses the chart's setting.
+	Smooth *bool
+
+	// NumberFormat is the Excel format code of the values and their data
+	// labels, e.g. "#,##0.0" or "0%". Empty means General.
+	NumberFormat string
 }
 
 // Series label position constants.
@@ -406,16 +540,64 @@ func (s *ChartSeries) SetLabelPosition(pos string) *ChartSeries {
 	return s
 }
 
+// SetSmooth sets whether the line of this series is smoothed, overriding
+// the chart's setting.
+func (s *ChartSeries) SetSmooth(v bool) *ChartSeries {
+	s.Smooth = &v
+	return s
+}
+
+// SetLine sets the series line, or the outline of bars and slices: its
+// width in points, color and dash style. An empty color keeps the color
+// the chart gives the series.
+func (s *ChartSeries) SetLine(width int, c Color, dash BorderStyle) *ChartSeries {
+	s.Outline = &SeriesOutline{Width: width, Color: c, Dash: dash}
+	return s
+}
+
+// SetMarker sets the marker symbol and size of the series points.
+func (s *ChartSeries) SetMarker(symbol string, size int) *ChartSeries {
+	if s.Marker == nil {
+		s.Marker = &SeriesMarker{}
+	}
+	s.Marker.Symbol = symbol
+	s.Marker.Size = size
+	return s
+}
+
+// SetMarkerColors sets the fill and border colors of the series markers.
+// Series without a marker get a circle marker.
+func (s *ChartSeries) SetMarkerColors(fill, border Color) *ChartSeries {
+	if s.Marker == nil {
+		s.Marker = &SeriesMarker{Symbol: MarkerCircle, Size: 5}
+	}
+	s.Marker.FillColor = fill
+	s.Marker.BorderColor = border
+	return s
+}
+
+// isSmooth reports whether the series line is smoothed in a chart whose
+// smoothing is chartSmooth.
+func (s *ChartSeries) isSmooth(chartSmooth bool) bool {
+	if s.Smooth != nil {
+		return *s.Smooth
+	}
+	return chartSmooth
+}
+
 // SeriesOutline represents a series outline.
 type SeriesOutline struct {
-	Width int
+	Width int // points; 0 keeps the default width
 	Color Color
+	Dash  BorderStyle // BorderNone hides the line
 }
 
 // SeriesMarker represents a series marker.
 type SeriesMarker struct {
-	Symbol string
-	Size   int
+	Symbol      string
+	Size        int
+	FillColor   Color
+	BorderColor Color
 }
 
 // Marker symbol constants.
@@ -486,6 +668,13 @@ func (b *BarChart) SetBarGrouping(g string) *BarChart {
 	return b
 }
 
+// SetBarDirection sets whether the bars are vertical columns
+// (BarDirectionVertical) or horizontal bars (BarDirectionHorizontal).
+func (b *BarChart) SetBarDirection(d string) *BarChart {
+	b.BarDirection = d
+	return b
+}
+
 // SetGapWidthPercent sets the gap width percentage (0-500).
 func (b *BarChart) SetGapWidthPercent(v int) *BarChart {
 	if v < 0 {
@@ -661,3 +850,101 @@ func (r *RadarChart) AddSeries(s *ChartSeries) *RadarChart {
 	r.Series = append(r.Series, s)
 	return r
 }
+
+// WaterfallChart represents a waterfall chart: floating columns that show
+// how each value adds to or subtracts from a running total. It is written as
+// a chartex part, which needs PowerPoint 2016 or later; older versions show
+// a placeholder instead.
+type WaterfallChart struct {
+	Series         []*ChartSeries
+	Subtotals      []int // category indexes whose value is a total drawn from zero
+	ConnectorLines bool
+}
+
+func (w *WaterfallChart) GetChartTypeName() string { return "waterfall" }
+
+// NewWaterfallChart creates a new waterfall chart with connector lines.
+func NewWaterfallChart() *WaterfallChart {
+	return &WaterfallChart{Series: make([]*ChartSeries, 0), ConnectorLines: true}
+}
+
+// AddSeries adds a data series.
+func (w *WaterfallChart) AddSeries(s *ChartSeries) *WaterfallChart {
+	w.Series = append(w.Series, s)
+	return w
+}
+
+// SetSubtotals sets the 0-based category indexes drawn as totals.
+func (w *WaterfallChart) SetSubtotals(idx ...int) *WaterfallChart {
+	w.Subtotals = idx
+	return w
+}
+
+// SetConnectorLines sets whether lines join the ends of adjacent columns.
+func (w *WaterfallChart) SetConnectorLines(v bool) *WaterfallChart {
+	w.ConnectorLines = v
+	return w
+}
+
+// waterfallStep is one column of a waterfall series.
+type waterfallStep struct {
+	Start, End float64
+	Total      bool
+}
+
+// steps returns the columns of series s: a subtotal runs from zero to its
+// value and resets the running total, any other value runs from the running
+// total to the running total plus the value.
+func (w *WaterfallChart) steps(s *ChartSeries) []waterfallStep {
+	subtotal := make(map[int]bool, len(w.Subtotals))
+	for _, i := range w.Subtotals {
+		subtotal[i] = true
+	}
+	steps := make([]waterfallStep, len(s.Categories))
+	running := 0.0
+	for i, cat := range s.Categories {
+		v := s.Values[cat]
+		if subtotal[i] {
+			steps[i] = waterfallStep{End: v, Total: true}
+			running = v
+			continue
+		}
+		steps[i] = waterfallStep{Start: running, End: running + v}
+		running += v
+	}
+	return steps
+}
+
+// FunnelChart represents a funnel chart: centered horizontal bars whose
+// widths are proportional to the values, typically of the stages of a
+// process. Like WaterfallChart it is written as a chartex part.
+type FunnelChart struct {
+	Series          []*ChartSeries
+	GapWidthPercent int
+}
+
+func (f *FunnelChart) GetChartTypeName() string { return "funnel" }
+
+// NewFunnelChart creates a new funnel chart.
+func NewFunnelChart() *FunnelChart {
+	return &FunnelChart{Series: make([]*ChartSeries, 0), GapWidthPercent: 6}
+}
+
+// AddSeries adds a data series.
+func (f *FunnelChart) AddSeries(s *ChartSeries) *FunnelChart {
+	f.Series = append(f.Series, s)
+	return f
+}
+
+// SetGapWidthPercent sets the gap between bars as a percentage of the bar
+// height (0-500).
+func (f *FunnelChart) SetGapWidthPercent(v int) *FunnelChart {
+	if v < 0 {
+		v = 0
+	}
+	if v > 500 {
+		v = 500
+	}
+	f.GapWidthPercent = v
+	return f
+}
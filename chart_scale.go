@@ -0,0 +1,248 @@
+package gopresentation
+
+import (
+	"math"
+	"strconv"
+)
+
+// defaultAxisTicks is the largest number of major intervals an automatic
+// value axis is divided into.
+const defaultAxisTicks = 8
+
+// maxAxisTicks is the largest number of major intervals Ticks returns the
+// tick marks of, so that a tiny major unit set on a wide axis does not
+// produce millions of ticks.
+const maxAxisTicks = 1000
+
+// AxisScale is the range and major unit of a value axis.
+type AxisScale struct {
+	Min       float64
+	Max       float64
+	MajorUnit float64
+}
+
+// Ticks returns the values of the major tick marks from Min to Max, or nil
+// when the scale is not finite, its major unit is not positive or it has
+// more than 1000 major intervals.
+func (s AxisScale) Ticks() []float64 {
+	if !isFinite(s.Min) || !isFinite(s.Max) || !isFinite(s.MajorUnit) || s.MajorUnit <= 0 || s.Max < s.Min {
+		return nil
+	}
+	steps := math.Round((s.Max - s.Min) / s.MajorUnit)
+	if steps > maxAxisTicks {
+		return nil
+	}
+	n := int(steps)
+	ticks := make([]float64, 0, n+1)
+	for i := 0; i <= n; i++ {
+		ticks = append(ticks, roundAxisValue(s.Min+float64(i)*s.MajorUnit))
+	}
+	return ticks
+}
+
+// NiceAxisScale returns a readable axis scale covering dataMin to dataMax
+// with at most maxTicks major intervals whose unit is 1, 2 or 5 times a
+// power of ten, e.g. 0 to 350 in steps of 50 for data up to 317. As in
+// PowerPoint, 5% headroom is left beyond the data and an all-positive range
+// starts at zero unless its minimum is close to its maximum, and likewise
+// for all-negative ranges. maxTicks of zero or less uses a default of 8,
+// and NaN or infinite bounds are taken as zero.
+func NiceAxisScale(dataMin, dataMax float64, maxTicks int) AxisScale {
+	return niceAxisScale(dataMin, dataMax, maxTicks, nil, nil)
+}
+
+// niceAxisScale is NiceAxisScale with optional fixed bounds.
+func niceAxisScale(dataMin, dataMax float64, maxTicks int, fixedMin, fixedMax *float64) AxisScale {
+	if maxTicks <= 0 {
+		maxTicks = defaultAxisTicks
+	}
+	if !isFinite(dataMin) {
+		dataMin = 0
+	}
+	if !isFinite(dataMax) {
+		dataMax = 0
+	}
+	if fixedMin != nil && !isFinite(*fixedMin) {
+		fixedMin = nil
+	}
+	if fixedMax != nil && !isFinite(*fixedMax) {
+		fixedMax = nil
+	}
+	if dataMin > dataMax {
+		dataMin, dataMax = dataMax, dataMin
+	}
+	switch {
+	case dataMin >= 0 && dataMin < dataMax*5/6:
+		dataMin = 0
+	case dataMax <= 0 && dataMax > dataMin*5/6:
+		dataMax = 0
+	}
+	if fixedMin != nil {
+		dataMin = *fixedMin
+	}
+	if fixedMax != nil {
+		dataMax = *fixedMax
+	}
+	if dataMax <= dataMin {
+		if fixedMax == nil {
+			dataMax = dataMin + 1
+		} else {
+			dataMin = dataMax - 1
+		}
+	}
+
+	// Leave 5% headroom beyond the data, as PowerPoint does
+	span := dataMax - dataMin
+	if fixedMax == nil && dataMax > 0 {
+		dataMax += span / 20
+	}
+	if fixedMin == nil && dataMin < 0 {
+		dataMin -= span / 20
+	}
+
+	unit := niceStep((dataMax - dataMin) / float64(maxTicks))
+	s := AxisScale{Min: dataMin, Max: dataMax, MajorUnit: unit}
+	if fixedMin == nil {
+		s.Min = roundAxisValue(math.Floor(dataMin/unit) * unit)
+	}
+	if fixedMax == nil {
+		s.Max = roundAxisValue(math.Ceil(dataMax/unit) * unit)
+	}
+	return s
+}
+
+// niceStep rounds x up to 1, 2 or 5 times a power of ten.
+func niceStep(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	exp := math.Floor(math.Log10(x))
+	pow := math.Pow(10, exp)
+	f := x / pow
+	switch {
+	case f <= 1:
+		f = 1
+	case f <= 2:
+		f = 2
+	case f <= 5:
+		f = 5
+	default:
+		f = 10
+	}
+	return roundAxisValue(f * pow)
+}
+
+// isFinite reports whether v is neither NaN nor infinite.
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// roundAxisValue removes floating point noise such as 0.30000000000000004.
+func roundAxisValue(v float64) float64 {
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	return r
+}
+
+// valueAxisScale returns the scale of the value axis of chart type ct: the
+// axis' fixed bounds and major unit when set, and a nice scale over the
+// series data otherwise. Stacked bars are scaled to their category totals
+// and waterfalls to their running totals; NaN and infinite values are left
+// out. ok is false for charts without a
+// value axis and percent-stacked bars.
+func valueAxisScale(ct ChartType, axis *ChartAxis) (scale AxisScale, ok bool) {
+	series := getChartSeries(ct)
+	switch c := ct.(type) {
+	case *PieChart, *Pie3DChart, *DoughnutChart, *FunnelChart, nil:
+		return AxisScale{}, false
+	case *BarChart:
+		if c.BarGrouping == BarGroupingPercentStacked {
+			return AxisScale{}, false
+		}
+	case *Bar3DChart:
+		if c.BarGrouping == BarGroupingPercentStacked {
+			return AxisScale{}, false
+		}
+	}
+	if len(series) == 0 {
+		return AxisScale{}, false
+	}
+
+	minVal, maxVal := math.Inf(1), math.Inf(-1)
+	if wf, ok := ct.(*WaterfallChart); ok {
+		for _, s := range series {
+			for _, st := range wf.steps(s) {
+				if !isFinite(st.Start) || !isFinite(st.End) {
+					continue
+				}
+				minVal = math.Min(minVal, math.Min(st.Start, st.End))
+				maxVal = math.Max(maxVal, math.Max(st.Start, st.End))
+			}
+		}
+	} else if barGrouping(ct) == BarGroupingStacked {
+		for _, cat := range series[0].Categories {
+			var pos, neg float64
+			for _, s := range series {
+				if v := s.Values[cat]; !isFinite(v) {
+					continue
+				} else if v >= 0 {
+					pos += v
+				} else {
+					neg += v
+				}
+			}
+			minVal, maxVal = math.Min(minVal, neg), math.Max(maxVal, pos)
+		}
+	} else {
+		for _, s := range series {
+			for _, v := range s.Values {
+				if !isFinite(v) {
+					continue
+				}
+				minVal, maxVal = math.Min(minVal, v), math.Max(maxVal, v)
+			}
+		}
+	}
+	if math.IsInf(minVal, 0) {
+		minVal, maxVal = 0, 0
+	}
+
+	var fixedMin, fixedMax *float64
+	if axis != nil {
+		fixedMin, fixedMax = axis.MinBounds, axis.MaxBounds
+	}
+	scale = niceAxisScale(minVal, maxVal, defaultAxisTicks, fixedMin, fixedMax)
+	if axis != nil && axis.MajorUnit != nil && isFinite(*axis.MajorUnit) && *axis.MajorUnit > 0 {
+		unit := *axis.MajorUnit
+		scale.MajorUnit = unit
+		if fixedMin == nil {
+			scale.Min = roundAxisValue(math.Floor(scale.Min/unit) * unit)
+		}
+		if fixedMax == nil {
+			scale.Max = roundAxisValue(math.Ceil(math.Max(maxVal, scale.Min)/unit) * unit)
+		}
+	}
+	return scale, true
+}
+
+// barGrouping returns the grouping of a bar chart type, or "".
+func barGrouping(ct ChartType) string {
+	switch c := ct.(type) {
+	case *BarChart:
+		return c.BarGrouping
+	case *Bar3DChart:
+		return c.BarGrouping
+	}
+	return ""
+}
+
+// isHorizontalBar reports whether ct is a bar chart with horizontal bars,
+// whose category axis is vertical.
+func isHorizontalBar(ct ChartType) bool {
+	switch c := ct.(type) {
+	case *BarChart:
+		return c.BarDirection == BarDirectionHorizontal
+	case *Bar3DChart:
+		return c.BarDirection == BarDirectionHorizontal
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package gopresentation
+
+import (
+	"math"
+	"regexp"
+	"testing"
+)
+
+func TestAxisScaleTicksNonFinite(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale AxisScale
+	}{
+		{"NaN unit", AxisScale{Min: 0, Max: 10, MajorUnit: math.NaN()}},
+		{"infinite unit", AxisScale{Min: 0, Max: 10, MajorUnit: math.Inf(1)}},
+		{"zero unit", AxisScale{Min: 0, Max: 10}},
+		{"negative unit", AxisScale{Min: 0, Max: 10, MajorUnit: -1}},
+		{"NaN max", AxisScale{Min: 0, Max: math.NaN(), MajorUnit: 1}},
+		{"infinite max", AxisScale{Min: 0, Max: math.Inf(1), MajorUnit: 1}},
+		{"infinite min", AxisScale{Min: math.Inf(-1), Max: 0, MajorUnit: 1}},
+		{"too many ticks", AxisScale{Min: 0, Max: 1e9, MajorUnit: 1}},
+	}
+	for _, tt := range tests {
+		if ticks := tt.scale.Ticks(); ticks != nil {
+			t.Errorf("%s: Ticks() = %d ticks, want nil", tt.name, len(ticks))
+		}
+	}
+	if got := (AxisScale{Min: 0, Max: 10, MajorUnit: 5}).Ticks(); len(got) != 3 {
+		t.Errorf("Ticks() = %v, want [0 5 10]", got)
+	}
+}
+
+func TestNiceAxisScaleNonFinite(t *testing.T) {
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		s := NiceAxisScale(v, 100, 0)
+		if !isFinite(s.Min) || !isFinite(s.Max) || !isFinite(s.MajorUnit) {
+			t.Errorf("NiceAxisScale(%v, 100) = %+v, want a finite scale", v, s)
+		}
+		if s.Ticks() == nil {
+			t.Errorf("NiceAxisScale(%v, 100) has no ticks", v)
+		}
+	}
+}
+
+func TestChartNonFiniteValues(t *testing.T) {
+	values := []float64{3, math.NaN(), math.Inf(1), math.Inf(-1), 7}
+	cats := []string{"a", "b", "c", "d", "e"}
+	stacked := NewBarChart().AddSeries(NewChartSeriesOrdered("s", cats, values))
+	stacked.BarGrouping = BarGroupingStacked
+	// The running total of a waterfall is NaN from the NaN value on, so
+	// only the first step counts.
+	charts := []struct {
+		name    string
+		ct      ChartType
+		dataMax float64
+	}{
+		{"bar", NewBarChart().AddSeries(NewChartSeriesOrdered("s", cats, values)), 7},
+		{"stacked", stacked, 7},
+		{"waterfall", NewWaterfallChart().AddSeries(NewChartSeriesOrdered("s", cats, values)), 3},
+	}
+
+	for _, c := range charts {
+		name, ct := c.name, c.ct
+		scale, ok := valueAxisScale(ct, nil)
+		if !ok {
+			t.Fatalf("%s: no value axis scale", name)
+		}
+		if scale.Min != 0 || scale.Max < c.dataMax || scale.Ticks() == nil {
+			t.Errorf("%s: scale %+v does not cover the finite values", name, scale)
+		}
+
+		p := New()
+		chart := p.GetActiveSlide().CreateChartShape()
+		chart.GetPlotArea().SetType(ct)
+		if _, err := p.SlideToImage(0, DefaultRenderOptions()); err != nil {
+			t.Fatalf("%s: SlideToImage: %v", name, err)
+		}
+		data := writePackage(t, p)
+		checkWellFormed(t, data)
+		unit := regexp.MustCompile(`(?i)(majorUnit|minorUnit|min|max)(="| val=")[^"]*(nan|inf)`)
+		for part, xml := range packageParts(t, data, "ppt/charts/") {
+			if m := unit.Find(xml); m != nil {
+				t.Errorf("%s: %s writes %s", name, part, m)
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package gopresentation
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestColorTransformsKeepARGBResolved(t *testing.T) {
+	base := NewColor("808080")
+	c := base.WithTransform(ColorTransformLumMod, 50000)
+	if c.ARGB == base.ARGB {
+		t.Fatalf("ARGB = %s, want the transformed color", c.ARGB)
+	}
+	if c.GetRed() != parseHexByte(c.ARGB, 2) {
+		t.Errorf("GetRed = %d, not the red of ARGB %s", c.GetRed(), c.ARGB)
+	}
+	if c.BaseARGB() != base.ARGB {
+		t.Errorf("BaseARGB = %s, want %s", c.BaseARGB(), base.ARGB)
+	}
+	want := []ColorTransform{{Type: ColorTransformLumMod, Value: 50000}}
+	if !slices.Equal(c.Transforms(), want) {
+		t.Errorf("Transforms = %v, want %v", c.Transforms(), want)
+	}
+	if r := c.Resolve(); r.ARGB != c.ARGB || len(r.Transforms()) != 0 {
+		t.Errorf("Resolve = %+v", r)
+	}
+
+	// Colors are comparable, and so usable as map keys.
+	seen := map[Color]bool{c: true}
+	if !seen[base.WithTransform(ColorTransformLumMod, 50000)] || seen[base] {
+		t.Error("colors with the same transforms differ, or differ from the base")
+	}
+	if *NewFont() != *NewFont() {
+		t.Error("default fonts differ")
+	}
+}
+
+func TestColorTransformRoundTrip(t *testing.T) {
+	color := NewThemeColor(ThemeColorAccent1).WithTransform(ColorTransformLumMod, 75000)
+	changed := color
+	changed.ARGB = "FF123456"
+
+	p := New()
+	shape := p.GetActiveSlide().CreateRichTextShape()
+	shape.CreateTextRun("kept").GetFont().SetColor(color)
+	shape.CreateParagraph().CreateTextRun("changed").GetFont().SetColor(changed)
+	data := writePackage(t, p)
+
+	slideXML := packageParts(t, data, "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	if !bytes.Contains(slideXML, []byte(`<a:schemeClr val="accent1"><a:lumMod val="75000"/></a:schemeClr>`)) {
+		t.Error("transformed theme color not written with its transform")
+	}
+	if !bytes.Contains(slideXML, []byte(`<a:srgbClr val="123456"/>`)) {
+		t.Error("color changed after its transforms not written as RGB")
+	}
+
+	slide, err := readPackage(t, data).GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paras := slide.GetShapes()[0].(*RichTextShape).GetParagraphs()
+	// The slide reader resolves scheme colors to RGB, keeping the
+	// transforms.
+	got := firstTextRun(t, paras).GetFont().Color
+	if got.ARGB != color.ARGB || got.BaseARGB() != color.BaseARGB() || !slices.Equal(got.Transforms(), color.Transforms()) {
+		t.Errorf("read color = %s (%s %v), want %s (%s %v)", got.ARGB, got.BaseARGB(), got.Transforms(),
+			color.ARGB, color.BaseARGB(), color.Transforms())
+	}
+	if got := firstTextRun(t, paras[1:]).GetFont().Color; got.ARGB != changed.ARGB {
+		t.Errorf("read changed color ARGB = %s, want %s", got.ARGB, changed.ARGB)
+	}
+}
+
+func TestLaserColorRoundTrip(t *testing.T) {
+	p := New()
+	p.GetPresentationProperties().SetLaserColor(NewColor("FF00FF00"))
+	data := writePackage(t, p)
+
+	props := packageParts(t, data, "ppt/presProps.xml")["ppt/presProps.xml"]
+	want := `<p14:laserClr xmlns:p14="` + nsP14 + `"><a:srgbClr val="00FF00"/></p14:laserClr>`
+	if !bytes.Contains(props, []byte(want)) {
+		t.Errorf("presProps = %s, want %s", props, want)
+	}
+	got := readPackage(t, data).GetPresentationProperties().GetLaserColor()
+	if got == nil || got.ARGB != "FF00FF00" {
+		t.Errorf("read laser color = %v, want FF00FF00", got)
+	}
+}
+
+func TestListLevelStyleRoundTrip(t *testing.T) {
+	p := New()
+	box := p.GetActiveSlide().CreateRichTextShape()
+	box.CreateTextRun("Level 1")
+	box.SetListLevelStyle(1, &ListLevelStyle{Font: &Font{Size: 20, Bold: true, Color: NewColor("FFC00000"), Name: "Georgia"}})
+	box.SetListLevelStyle(2, &ListLevelStyle{Font: &Font{Size: 16, Color: Color{ARGB: "FFED7D31", Scheme: "accent2"}, Name: "Verdana"}})
+
+	read := readPackage(t, writePackage(t, p))
+	slide, _ := read.GetSlide(0)
+	got := slide.GetShapes()[0].(*RichTextShape)
+	for _, want := range []struct {
+		level int
+		argb  string
+		name  string
+	}{
+		{1, "FFC00000", "Georgia"},
+		{2, "FFED7D31", "Verdana"},
+	} {
+		l := got.GetListLevelStyle(want.level)
+		if l == nil || l.Font == nil {
+			t.Fatalf("level %d has no font", want.level)
+		}
+		if l.Font.Color.ARGB != want.argb {
+			t.Errorf("level %d color = %s, want %s", want.level, l.Font.Color.ARGB, want.argb)
+		}
+		if l.Font.Name != want.name {
+			t.Errorf("level %d typeface = %q, want %q", want.level, l.Font.Name, want.name)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package gopresentation
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// CompareImages compares a and b pixel by pixel. Two pixels differ when any
+// of their 8-bit channels differ by more than tolerance, which absorbs
+// anti-aliasing noise. Pixels covered by only one of the images differ.
+//
+// It returns an image the size of both combined that shows a faded in gray
+// with the differing pixels in red, and the score: the fraction of pixels
+// that differ, from 0 for identical images to 1.
+func CompareImages(a, b image.Image, tolerance uint8) (*image.RGBA, float64) {
+	ab, bb := a.Bounds(), b.Bounds()
+	bounds := ab.Union(bb)
+	diff := image.NewRGBA(bounds)
+	if bounds.Empty() {
+		return diff, 0
+	}
+	red := color.RGBA{R: 255, A: 255}
+	differing := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			inA, inB := p.In(ab), p.In(bb)
+			if !inA || !inB {
+				diff.SetRGBA(x, y, red)
+				differing++
+				continue
+			}
+			ca := color.RGBAModel.Convert(a.At(x, y)).(color.RGBA)
+			cb := color.RGBAModel.Convert(b.At(x, y)).(color.RGBA)
+			if channelsDiffer(ca, cb, tolerance) {
+				diff.SetRGBA(x, y, red)
+				differing++
+				continue
+			}
+			// Fade a to light gray so the differences stand out.
+			gray := uint8((299*uint32(ca.R) + 587*uint32(ca.G) + 114*uint32(ca.B)) / 1000)
+			gray = 191 + gray/4
+			diff.SetRGBA(x, y, color.RGBA{R: gray, G: gray, B: gray, A: 255})
+		}
+	}
+	return diff, float64(differing) / float64(bounds.Dx()*bounds.Dy())
+}
+
+// channelsDiffer reports whether a channel of a and b differs by more than
+// tolerance.
+func channelsDiffer(a, b color.RGBA, tolerance uint8) bool {
+	d := func(x, y uint8) uint8 {
+		if x > y {
+			return x - y
+		}
+		return y - x
+	}
+	return d(a.R, b.R) > tolerance || d(a.G, b.G) > tolerance ||
+		d(a.B, b.B) > tolerance || d(a.A, b.A) > tolerance
+}
+
+// GoldenOptions controls comparisons against golden images.
+type GoldenOptions struct {
+	// Tolerance is the largest difference of a channel at which pixels
+	// still match; see CompareImages. Default: 8.
+	Tolerance uint8
+	// MaxDiff is the largest score at which an image still matches its
+	// golden image. Default: 0.0001 (0.01% of the pixels).
+	MaxDiff float64
+	// Update writes the image as the new golden image instead of comparing,
+	// typically set from a test flag such as -update.
+	Update bool
+}
+
+// DefaultGoldenOptions returns the default golden image options.
+func DefaultGoldenOptions() *GoldenOptions {
+	return &GoldenOptions{Tolerance: 8, MaxDiff: 0.0001}
+}
+
+// CompareWithGolden compares img with the PNG golden image at path and
+// returns the score; see CompareImages. When the golden image does not exist
+// or opts.Update is set, img is written as the golden image and the score is
+// 0. When the score exceeds opts.MaxDiff, the difference image is written
+// next to the golden image, with ".diff.png" replacing ".png", and an error
+// is returned. A nil opts uses DefaultGoldenOptions.
+func CompareWithGolden(img image.Image, path string, opts *GoldenOptions) (float64, error) {
+	if opts == nil {
+		opts = DefaultGoldenOptions()
+	}
+	pngOpts := &RenderOptions{Format: ImageFormatPNG}
+	if opts.Update {
+		return 0, saveImage(img, path, pngOpts)
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, saveImage(img, path, pngOpts)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("open golden image: %w", err)
+	}
+	golden, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return 0, fmt.Errorf("decode golden image %s: %w", path, err)
+	}
+
+	diff, score := CompareImages(golden, img, opts.Tolerance)
+	if score <= opts.MaxDiff {
+		return score, nil
+	}
+	diffPath := strings.TrimSuffix(path, ".png") + ".diff.png"
+	if err := saveImage(diff, diffPath, pngOpts); err != nil {
+		return score, fmt.Errorf("write difference image: %w", err)
+	}
+	return score, fmt.Errorf("image differs from golden image %s in %.3f%% of pixels (max %.3f%%), see %s",
+		path, score*100, opts.MaxDiff*100, diffPath)
+}
+
+// GoldenT is the part of testing.TB that AssertGolden uses.
+type GoldenT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// AssertGolden fails t when img does not match the golden image at path;
+// see CompareWithGolden.
+func AssertGolden(t GoldenT, img image.Image, path string, opts *GoldenOptions) {
+	t.Helper()
+	if _, err := CompareWithGolden(img, path, opts); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+// AssertSlideGolden renders the slide at slideIndex with renderOpts and
+// fails t when the image does not match the golden image at path; see
+// CompareWithGolden.
+func AssertSlideGolden(t GoldenT, p *Presentation, slideIndex int, path string, renderOpts *RenderOptions, opts *GoldenOptions) {
+	t.Helper()
+	img, err := p.SlideToImage(slideIndex, renderOpts)
+	if err != nil {
+		t.Fatalf("render slide %d: %v", slideIndex, err)
+	}
+	AssertGolden(t, img, path, opts)
+}
@@ -0,0 +1,77 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// rawEntries returns the compressed data of the entries of the package at
+// path, by name.
+func rawEntries(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	entries := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		r, err := f.OpenRaw()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[f.Name] = data
+	}
+	return entries
+}
+
+func TestSaveWithCompressionCache(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.pptx")
+	dst := filepath.Join(dir, "dst.pptx")
+
+	p := New()
+	p.GetActiveSlide().CreateRichTextShape().CreateTextRun("first")
+	p.CreateSlide().CreateRichTextShape().CreateTextRun("second")
+	// Compressed at a level the cache must not undo
+	if err := p.Save(src, WithCompressionLevel(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Open(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide, err := read.GetSlide(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide.CreateRichTextShape().CreateTextRun("added")
+	if err := read.SaveWithCompressionCache(src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	before, after := rawEntries(t, src), rawEntries(t, dst)
+	for _, name := range []string{"ppt/slides/slide1.xml", "ppt/theme/theme1.xml"} {
+		if !bytes.Equal(before[name], after[name]) {
+			t.Errorf("%s was compressed again although unchanged", name)
+		}
+	}
+	if bytes.Equal(before["ppt/slides/slide2.xml"], after["ppt/slides/slide2.xml"]) {
+		t.Error("changed slide2.xml was copied from the source")
+	}
+	got, err := Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetSlideCount() != 2 {
+		t.Errorf("slides = %d, want 2", got.GetSlideCount())
+	}
+}
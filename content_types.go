@@ -0,0 +1,93 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// contentTypeRegistry collects the content type of every part written to a
+// package, so that [Content_Types].xml describes exactly the parts present.
+// A part whose extension has no default yet makes its content type the
+// default for that extension (images, for example); XML parts and parts
+// that differ from their extension's default get an override.
+type contentTypeRegistry struct {
+	defaults  []xmlDefault
+	overrides []xmlOverride
+	byExt     map[string]string
+	parts     map[string]bool
+}
+
+func newContentTypeRegistry() *contentTypeRegistry {
+	r := &contentTypeRegistry{byExt: make(map[string]string), parts: make(map[string]bool)}
+	r.addDefault("rels", ctRels)
+	r.addDefault("xml", "application/xml")
+	return r
+}
+
+func (r *contentTypeRegistry) addDefault(ext, contentType string) {
+	r.byExt[ext] = contentType
+	r.defaults = append(r.defaults, xmlDefault{Extension: ext, ContentType: contentType})
+}
+
+// declare records the content type of the part name (without a leading
+// slash). Declaring a part twice is an error.
+func (r *contentTypeRegistry) declare(name, contentType string) error {
+	partName := "/" + name
+	if r.parts[strings.ToLower(partName)] {
+		return fmt.Errorf("duplicate package part %s", partName)
+	}
+	r.parts[strings.ToLower(partName)] = true
+
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+	def, ok := r.byExt[ext]
+	switch {
+	case ok && def == contentType:
+	case !ok && ext != "":
+		r.addDefault(ext, contentType)
+	default:
+		r.overrides = append(r.overrides, xmlOverride{PartName: partName, ContentType: contentType})
+	}
+	return nil
+}
+
+// createPart creates the part name in zw and declares its content type.
+func (w *PPTXWriter) createPart(zw *zip.Writer, name, contentType string) (io.Writer, error) {
+	if err := w.contentTypes.declare(name, contentType); err != nil {
+		return nil, err
+	}
+	fw, err := zw.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s in zip: %w", name, err)
+	}
+	return fw, nil
+}
+
+// writeContentTypes writes [Content_Types].xml for the parts declared so
+// far. It is written after every other part.
+func (w *PPTXWriter) writeContentTypes(zw *zip.Writer) error {
+	const name = "[Content_Types].xml"
+	var buf bytes.Buffer
+	err := encodeXML(&buf, name, xmlContentTypes{
+		Xmlns:     nsContentTypes,
+		Defaults:  w.contentTypes.defaults,
+		Overrides: w.contentTypes.overrides,
+	})
+	if err != nil {
+		return err
+	}
+	content, err := w.pkg.transform(name, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	fw, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s in zip: %w", name, err)
+	}
+	w.partWritten(name, len(content))
+	_, err = fw.Write(content)
+	return err
+}
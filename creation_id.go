@@ -0,0 +1,140 @@
+package gopresentation
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Creation IDs identify slides and shapes independently of their position
+// and name. PowerPoint uses them to merge changes made by co-authors, so
+// every written slide and shape carries one: the ID read with it or given
+// by GetCreationID, or else one derived from its position in the package.
+// Derived IDs are not stored, so writing leaves the presentation unchanged
+// and writes the same IDs every time.
+
+const (
+	// extURISlideCreationID is the extension holding p14:creationId.
+	extURISlideCreationID = "{BB962C8B-B14F-4D97-AF65-F5344CB8AC3E}"
+	// extURIShapeCreationID is the extension holding a16:creationId.
+	extURIShapeCreationID = "{FF2B5EF4-FFF2-40B4-BE49-F238E27FC236}"
+
+	nsP14 = "http://schemas.microsoft.com/office/powerpoint/2010/main"
+	nsA16 = "http://schemas.microsoft.com/office/drawing/2014/main"
+)
+
+// GetCreationID returns the creation ID of the slide, assigning a new
+// random one, kept from then on, if it has none yet.
+func (s *Slide) GetCreationID() uint32 {
+	for s.creationID == 0 {
+		var b [4]byte
+		rand.Read(b[:])
+		s.creationID = binary.LittleEndian.Uint32(b[:])
+	}
+	return s.creationID
+}
+
+// GetCreationID returns the creation ID of the shape, a GUID in braces,
+// assigning a new random one, kept from then on, if it has none yet.
+func (b *BaseShape) GetCreationID() string {
+	if b.creationID == "" {
+		var u [16]byte
+		rand.Read(u[:])
+		u[6] = u[6]&0x0F | 0x40 // version 4
+		u[8] = u[8]&0x3F | 0x80 // variant 1
+		b.creationID = fmt.Sprintf("{%X-%X-%X-%X-%X}", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+	}
+	return b.creationID
+}
+
+// setExtLsts sets the extension lists read with the shape, taking the
+// creation ID out of them.
+func (b *BaseShape) setExtLsts(ext extLsts) {
+	var id string
+	id, ext.cNvPr = splitExt(ext.cNvPr, extURIShapeCreationID, "id")
+	if id != "" {
+		b.creationID = id
+	}
+	b.extLst = ext
+}
+
+// setExtLst sets the extension list read with the slide, taking the
+// creation ID out of it.
+func (s *Slide) setExtLst(raw string) {
+	id, rest := splitExt(raw, extURISlideCreationID, "val")
+	if n, err := strconv.ParseUint(id, 10, 32); err == nil {
+		s.creationID = uint32(n)
+	}
+	s.extLst = rest
+}
+
+// splitExt removes the extension with uri from the extension list raw. It
+// returns attribute attr of the extension's first child and the rest of
+// the list, or "" when no extension remains.
+func splitExt(raw, uri, attr string) (string, string) {
+	if raw == "" {
+		return "", ""
+	}
+	var value string
+	var start, end int64 = -1, -1
+	exts := 0
+	depth := 0
+	decoder := xml.NewDecoder(strings.NewReader(raw))
+	for {
+		tokenStart := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 && t.Name.Local == "ext" {
+				exts++
+				if attrValue(t.Attr, "uri") == uri && start < 0 {
+					start = tokenStart
+				}
+			} else if depth == 3 && start >= 0 && end < 0 && value == "" {
+				value = attrValue(t.Attr, attr)
+			}
+		case xml.EndElement:
+			if depth == 2 && start >= 0 && end < 0 {
+				end = decoder.InputOffset()
+			}
+			depth--
+		}
+	}
+	if start < 0 || end < 0 {
+		return "", raw
+	}
+	if exts == 1 {
+		return value, ""
+	}
+	return value, raw[:start] + raw[end:]
+}
+
+// withExt returns the extension list raw, written with prefix, with ext
+// appended.
+func withExt(raw, prefix, ext string) string {
+	closing := "</" + prefix + ":extLst>"
+	if !strings.HasSuffix(raw, closing) {
+		return "<" + prefix + ":extLst>" + ext + closing
+	}
+	return strings.TrimSuffix(raw, closing) + ext + closing
+}
+
+// slideExtLstXML returns the p:extLst of the slide, holding creation ID id.
+func slideExtLstXML(s *Slide, id uint32) string {
+	return withExt(s.extLst, "p", fmt.Sprintf(`<p:ext uri="%s"><p14:creationId xmlns:p14="%s" val="%d"/></p:ext>`,
+		extURISlideCreationID, nsP14, id))
+}
+
+// shapeExtLstXML returns the a:extLst of the p:cNvPr of b, holding creation
+// ID id.
+func shapeExtLstXML(b *BaseShape, id string) string {
+	return withExt(b.extLst.cNvPr, "a", fmt.Sprintf(`<a:ext uri="%s"><a16:creationId xmlns:a16="%s" id="%s"/></a:ext>`,
+		extURIShapeCreationID, nsA16, xmlEscape(id)))
+}
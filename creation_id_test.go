@@ -0,0 +1,34 @@
+package gopresentation
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDoesNotAssignCreationIDs(t *testing.T) {
+	p := New()
+	slide := p.GetActiveSlide()
+	shape := slide.CreateRichTextShape()
+	shape.CreateTextRun("text")
+
+	first := writePackage(t, p)
+	if slide.creationID != 0 || shape.creationID != "" {
+		t.Errorf("write assigned creation IDs %d and %q", slide.creationID, shape.creationID)
+	}
+	second := writePackage(t, p)
+	a := packageParts(t, first, "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	b := packageParts(t, second, "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	if !bytes.Equal(a, b) {
+		t.Error("writing twice gave different slides")
+	}
+
+	// IDs read or asked for are kept.
+	id := shape.GetCreationID()
+	s, err := readPackage(t, writePackage(t, p)).GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetShapes()[0].(*RichTextShape).GetCreationID(); got != id {
+		t.Errorf("read creation ID = %s, want %s", got, id)
+	}
+}
@@ -0,0 +1,137 @@
+package gopresentation
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Colors of the debug overlay.
+var (
+	debugFrameColor    = color.RGBA{R: 255, B: 255, A: 255}
+	debugGroupColor    = color.RGBA{R: 255, G: 140, A: 255}
+	debugOriginColor   = color.RGBA{R: 230, A: 255}
+	debugBaselineColor = color.RGBA{G: 160, B: 255, A: 255}
+	debugLabelColor    = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	debugLabelBack     = color.RGBA{A: 170}
+)
+
+// debugOverlay collects what the renderer draws for
+// RenderOptions.DebugOverlay.
+type debugOverlay struct {
+	// baselines are the baselines of the text lines drawn, in pixels.
+	baselines []debugBaseline
+}
+
+// debugBaseline is the baseline of a text line, from x1 to x2 at y.
+type debugBaseline struct{ x1, x2, y int }
+
+// addBaseline records the baseline of a text line. It does nothing on a nil
+// overlay, so renderers without one need no check.
+func (d *debugOverlay) addBaseline(x1, x2, y int) {
+	if d == nil || x2 <= x1 {
+		return
+	}
+	d.baselines = append(d.baselines, debugBaseline{x1, x2, y})
+}
+
+// drawDebugOverlay draws the collected baselines and, for each of shapes
+// and the children of groups, its frame, the origin of its frame and a
+// label with its index and name. Children are labelled with the index of
+// their group, a dot and their index in it.
+func (r *renderer) drawDebugOverlay(shapes []Shape) {
+	for _, b := range r.debug.baselines {
+		r.drawLine(b.x1, b.y, b.x2, b.y, debugBaselineColor)
+	}
+	slide := func(x, y float64) (float64, float64) { return x, y }
+	for i, shape := range shapes {
+		r.drawDebugShape(shape, strconv.Itoa(i), slide)
+	}
+}
+
+// drawDebugShape draws the overlay of shape, whose container maps points to
+// slide coordinates with toSlide.
+func (r *renderer) drawDebugShape(shape Shape, label string, toSlide func(x, y float64) (float64, float64)) {
+	b := shape.base()
+	toContainer := func(x, y float64) (float64, float64) {
+		return toSlide(b.fromLocal(x, y))
+	}
+
+	x0, y0 := float64(b.offsetX), float64(b.offsetY)
+	x1, y1 := x0+float64(b.width), y0+float64(b.height)
+	var corners [4]image.Point
+	for i, c := range [4][2]float64{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}} {
+		x, y := toContainer(c[0], c[1])
+		corners[i] = image.Pt(int(math.Round(x*r.scaleX)), int(math.Round(y*r.scaleY)))
+	}
+
+	frameColor := debugFrameColor
+	g, isGroup := shape.(*GroupShape)
+	if isGroup {
+		frameColor = debugGroupColor
+	}
+	top := corners[0]
+	for i, p := range corners {
+		q := corners[(i+1)%4]
+		r.drawLine(p.X, p.Y, q.X, q.Y, frameColor)
+		if p.Y < top.Y || (p.Y == top.Y && p.X < top.X) {
+			top = p
+		}
+	}
+	origin := corners[0]
+	r.fillRectBlend(image.Rect(origin.X-2, origin.Y-2, origin.X+3, origin.Y+3), debugOriginColor)
+
+	text := label
+	if name := b.GetName(); name != "" {
+		text += " " + name
+	}
+	r.drawDebugLabel(top.X, top.Y, text)
+
+	if !isGroup {
+		return
+	}
+	for i, child := range g.shapes {
+		r.drawDebugShape(child, label+"."+strconv.Itoa(i), g.childToSlide(toSlide))
+	}
+}
+
+// drawDebugLabel draws text on a dark backing just above (x, y), or below
+// it at the top of the image, so that the label does not hide the shape's
+// first line of text.
+func (r *renderer) drawDebugLabel(x, y int, text string) {
+	face := basicfont.Face7x13
+	d := &font.Drawer{Dst: r.img, Src: image.NewUniform(debugLabelColor), Face: face}
+	m := face.Metrics()
+	w := d.MeasureString(text).Ceil()
+	h := (m.Ascent + m.Descent).Ceil()
+	if y-h-2 >= r.img.Bounds().Min.Y {
+		y -= h + 2
+	}
+	r.fillRectBlend(image.Rect(x, y, x+w+4, y+h+2), debugLabelBack)
+	d.Dot = fixed.P(x+2, y+1+m.Ascent.Ceil())
+	d.DrawString(text)
+}
+
+// fromLocal maps the point (x, y) of the shape's unrotated, unflipped frame
+// into the shape's container; it is the inverse of toLocal.
+func (b *BaseShape) fromLocal(x, y float64) (float64, float64) {
+	cx := float64(b.offsetX) + float64(b.width)/2
+	cy := float64(b.offsetY) + float64(b.height)/2
+	dx, dy := x-cx, y-cy
+	if b.flipHorizontal {
+		dx = -dx
+	}
+	if b.flipVertical {
+		dy = -dy
+	}
+	if b.rotation != 0 {
+		sin, cos := math.Sincos(float64(b.rotation) * math.Pi / 180)
+		dx, dy = dx*cos-dy*sin, dx*sin+dy*cos
+	}
+	return cx + dx, cy + dy
+}
@@ -0,0 +1,84 @@
+package gopresentation
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DocumentType is the kind of package a presentation is stored as. Each
+// kind has its own file extension and main part content type.
+type DocumentType string
+
+const (
+	DocumentTypePresentation          DocumentType = "pptx"
+	DocumentTypeTemplate              DocumentType = "potx"
+	DocumentTypeSlideshow             DocumentType = "ppsx"
+	DocumentTypeMacroEnabled          DocumentType = "pptm"
+	DocumentTypeMacroEnabledTemplate  DocumentType = "potm"
+	DocumentTypeMacroEnabledSlideshow DocumentType = "ppsm"
+)
+
+// documentContentTypes maps document types to the content type of their
+// presentation part.
+var documentContentTypes = map[DocumentType]string{
+	DocumentTypePresentation:          ctPresentation,
+	DocumentTypeTemplate:              "application/vnd.openxmlformats-officedocument.presentationml.template.main+xml",
+	DocumentTypeSlideshow:             "application/vnd.openxmlformats-officedocument.presentationml.slideshow.main+xml",
+	DocumentTypeMacroEnabled:          "application/vnd.ms-powerpoint.presentation.macroEnabled.main+xml",
+	DocumentTypeMacroEnabledTemplate:  "application/vnd.ms-powerpoint.template.macroEnabledTemplate.main+xml",
+	DocumentTypeMacroEnabledSlideshow: "application/vnd.ms-powerpoint.slideshow.macroEnabled.main+xml",
+}
+
+// IsMacroEnabled reports whether packages of this type can hold a VBA
+// project.
+func (t DocumentType) IsMacroEnabled() bool {
+	switch t {
+	case DocumentTypeMacroEnabled, DocumentTypeMacroEnabledTemplate, DocumentTypeMacroEnabledSlideshow:
+		return true
+	}
+	return false
+}
+
+// contentType returns the content type of the presentation part; unknown
+// types are written as presentations.
+func (t DocumentType) contentType() string {
+	if ct, ok := documentContentTypes[t]; ok {
+		return ct
+	}
+	return ctPresentation
+}
+
+// documentTypeForContentType returns the document type whose presentation
+// part has content type ct.
+func documentTypeForContentType(ct string) (DocumentType, bool) {
+	for t, c := range documentContentTypes {
+		if c == ct {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// documentTypeForPath returns the document type matching the extension of
+// path, such as DocumentTypeTemplate for "deck.potx".
+func documentTypeForPath(path string) (DocumentType, bool) {
+	t := DocumentType(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+	_, ok := documentContentTypes[t]
+	return t, ok
+}
+
+// GetDocumentType returns the kind of package the presentation is written
+// as. Presentations read from a file keep the type of that file.
+func (p *Presentation) GetDocumentType() DocumentType {
+	if p.documentType == "" {
+		return DocumentTypePresentation
+	}
+	return p.documentType
+}
+
+// SetDocumentType sets the kind of package the presentation is written as
+// by WriteTo. Save uses the type matching the file extension instead, when
+// there is one.
+func (p *Presentation) SetDocumentType(t DocumentType) {
+	p.documentType = t
+}
@@ -0,0 +1,131 @@
+package gopresentation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// emfRecord is a record of an EMF: its type and body.
+type emfRecord struct {
+	typ  uint32
+	body []byte
+}
+
+// readEMFRecords returns the records of data, failing the test when they
+// do not fill it exactly or disagree with the header.
+func readEMFRecords(t *testing.T, data []byte) []emfRecord {
+	t.Helper()
+	var recs []emfRecord
+	for off := 0; off < len(data); {
+		if len(data)-off < 8 {
+			t.Fatalf("truncated record at %d", off)
+		}
+		typ := binary.LittleEndian.Uint32(data[off:])
+		size := int(binary.LittleEndian.Uint32(data[off+4:]))
+		if size < 8 || size%4 != 0 || off+size > len(data) {
+			t.Fatalf("record %#x at %d has size %d", typ, off, size)
+		}
+		recs = append(recs, emfRecord{typ, data[off+8 : off+size]})
+		off += size
+	}
+	if len(recs) == 0 || recs[0].typ != emrHeader || recs[len(recs)-1].typ != emrEOF {
+		t.Fatal("EMF does not start with a header and end with EOF")
+	}
+	if n := binary.LittleEndian.Uint32(data[48:]); int(n) != len(data) {
+		t.Errorf("header nBytes = %d, want %d", n, len(data))
+	}
+	if n := binary.LittleEndian.Uint32(data[52:]); int(n) != len(recs) {
+		t.Errorf("header nRecords = %d, want %d", n, len(recs))
+	}
+	return recs
+}
+
+func TestRenderSlideEMFVectorShapes(t *testing.T) {
+	var pngData bytes.Buffer
+	pic := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range pic.Pix {
+		pic.Pix[i] = 0x80
+	}
+	if err := png.Encode(&pngData, pic); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	slide := p.GetActiveSlide()
+	slide.CreateAutoShape().SetGeometry(AutoShapeRectangle).SetSolidFill(NewColor("FFC00000")).
+		SetBorder(NewBorder().SetSolidFill(NewColor("FF000000")).SetWidth(2)).
+		SetText("Boxed").SetPosition(914400, 914400).SetSize(2743200, 1371600)
+	slide.CreateDrawingShape().SetImageData(pngData.Bytes(), "image/png")
+	drawing := slide.GetShapes()[1].(*DrawingShape)
+	drawing.SetPosition(2743200, 1828800).SetSize(1828800, 1828800)
+	line := slide.CreateLineShape()
+	line.SetPosition(457200, 4572000)
+	line.SetSize(5486400, 0)
+	slide.CreateRichTextShape().SetPosition(2743200, 2286000).SetSize(3657600, 457200).
+		CreateTextRun("Over the picture")
+
+	var buf bytes.Buffer
+	opts := DefaultRenderOptions()
+	opts.Width = 960
+	if err := p.RenderSlideEMF(0, &buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	recs := readEMFRecords(t, buf.Bytes())
+
+	index := func(typ uint32, from int) int {
+		for i := from; i < len(recs); i++ {
+			if recs[i].typ == typ {
+				return i
+			}
+		}
+		return -1
+	}
+	count := func(typ uint32) int {
+		n := 0
+		for _, rec := range recs {
+			if rec.typ == typ {
+				n++
+			}
+		}
+		return n
+	}
+	// The white background and the filled shape are rectangles.
+	if n := count(emrRectangle); n < 2 {
+		t.Errorf("rectangles = %d, want the background and the shape", n)
+	}
+	if count(emrPolyline) == 0 {
+		t.Error("line not written as a polyline")
+	}
+	if n := count(emrExtTextOutW); n != 2 {
+		t.Errorf("text records = %d, want 2", n)
+	}
+	// Only the picture is a bitmap, at its place, drawn over the shape
+	// and under the text after it.
+	if n := count(emrAlphaBlend); n != 1 {
+		t.Fatalf("bitmaps = %d, want the picture alone", n)
+	}
+	blend := index(emrAlphaBlend, 0)
+	body := recs[blend].body
+	x, y := int32(binary.LittleEndian.Uint32(body[16:])), int32(binary.LittleEndian.Uint32(body[20:]))
+	w, h := int32(binary.LittleEndian.Uint32(body[24:])), int32(binary.LittleEndian.Uint32(body[28:]))
+	// 960 pixels across 10 inches of slide.
+	if x < 287 || x > 289 || y < 191 || y > 193 || w < 190 || w > 194 || h < 190 || h > 194 {
+		t.Errorf("picture bitmap at %d,%d size %dx%d, want 288,192 size 192x192", x, y, w, h)
+	}
+	if first := index(emrExtTextOutW, 0); index(emrRectangle, 0) > blend || index(emrExtTextOutW, first+1) < blend {
+		t.Errorf("records out of z-order: rectangle %d, bitmap %d, text %d and %d",
+			index(emrRectangle, 0), blend, first, index(emrExtTextOutW, first+1))
+	}
+	var shapeFill bool
+	for _, rec := range recs {
+		if rec.typ == emrCreateBrushIndirect && binary.LittleEndian.Uint32(rec.body[8:]) == 0x0000C0 {
+			shapeFill = true
+		}
+	}
+	if !shapeFill {
+		t.Error("no brush in the shape's fill color")
+	}
+}
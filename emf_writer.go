@@ -0,0 +1,595 @@
+package gopresentation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"unicode/utf16"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// Slides are exported as Enhanced Metafiles (EMF) so that they can be
+// pasted into Word or Excel and stay sharp at any zoom. The slide is drawn
+// by the renderer as usual, but with a vectorDrawing collecting, in drawing
+// order, what can be written as EMF records instead of pixels: the
+// rectangles, rounded rectangles, ellipses, polygons and lines filled or
+// stroked in opaque colors, and the text drawn unrotated. What is left, such
+// as pictures, gradients, shadows, transparent fills and rotated shapes and
+// text, is drawn into pixels, which are taken as a bitmap, cropped to where
+// they were drawn, before the first record drawn over them, so that the
+// metafile keeps the z-order of the slide.
+
+// EMF record types.
+const (
+	emrHeader                 = 0x01
+	emrPolygon                = 0x03
+	emrPolyline               = 0x04
+	emrEOF                    = 0x0E
+	emrSetBkMode              = 0x12
+	emrSetTextAlign           = 0x16
+	emrSetTextColor           = 0x18
+	emrSelectObject           = 0x25
+	emrDeleteObject           = 0x28
+	emrCreateBrushIndirect    = 0x27
+	emrEllipse                = 0x2A
+	emrRectangle              = 0x2B
+	emrRoundRect              = 0x2C
+	emrExtCreateFontIndirectW = 0x52
+	emrExtTextOutW            = 0x54
+	emrExtCreatePen           = 0x5F
+	emrAlphaBlend             = 0x72
+)
+
+const (
+	emfSignature   = 0x464D4520 // " EMF"
+	emfVersion     = 0x10000
+	emfTransparent = 1          // SETBKMODE TRANSPARENT
+	emfTABaseline  = 24         // SETTEXTALIGN TA_BASELINE | TA_LEFT
+	emfSystemFont  = 0x8000000D // stock object SYSTEM_FONT
+	emfNullBrush   = 0x80000005 // stock object NULL_BRUSH
+	emfNullPen     = 0x80000008 // stock object NULL_PEN
+	emfCompatible  = 1          // GM_COMPATIBLE graphics mode
+
+	// PS_GEOMETRIC | PS_ENDCAP_FLAT | PS_JOIN_ROUND, a solid pen drawn
+	// centered on the outline; emfPenInside draws it inside the frame of
+	// a rectangle instead, PS_INSIDEFRAME with mitered corners.
+	emfPen       = 0x00010000 | 0x0200 | 0x0000
+	emfPenInside = 0x00010000 | 0x0200 | 0x2000 | 0x6
+)
+
+// EMF object table indexes of the objects a record creates and deletes.
+const (
+	emfObjFont = 1 + iota
+	emfObjBrush
+	emfObjPen
+)
+
+// vectorDrawing collects what the renderer draws of a slide as operations
+// written to an EMF, in drawing order.
+type vectorDrawing struct {
+	ops []vectorOp
+	// img is the image the renderer draws into; the pixels drawn into it
+	// are taken as bitmap operations.
+	img *image.RGBA
+}
+
+// vectorOp is an operation of a vectorDrawing.
+type vectorOp interface {
+	emit(e *emfWriter)
+}
+
+// add appends op, covering area, taking the pixels drawn so far as a
+// bitmap first if any of them lie in area, so that op is drawn over them.
+func (v *vectorDrawing) add(op vectorOp, area image.Rectangle) {
+	if drawnIn(v.img, area) {
+		v.flush()
+	}
+	v.ops = append(v.ops, op)
+}
+
+// flush appends the pixels drawn so far as a bitmap, cropped to where they
+// were drawn, and clears them.
+func (v *vectorDrawing) flush() {
+	area := drawnBounds(v.img)
+	if area.Empty() {
+		return
+	}
+	crop := image.NewRGBA(area)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		row := v.img.Pix[v.img.PixOffset(area.Min.X, y):v.img.PixOffset(area.Max.X, y)]
+		copy(crop.Pix[crop.PixOffset(area.Min.X, y):], row)
+		clear(row)
+	}
+	v.ops = append(v.ops, vectorBitmap{crop})
+}
+
+// drawnIn reports whether any pixel of img in area has been drawn.
+func drawnIn(img *image.RGBA, area image.Rectangle) bool {
+	area = area.Intersect(img.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(area.Min.X, y):img.PixOffset(area.Max.X, y)]
+		for i := 3; i < len(row); i += 4 {
+			if row[i] != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// drawnBounds returns the smallest rectangle holding the drawn pixels of
+// img.
+func drawnBounds(img *image.RGBA) image.Rectangle {
+	var area image.Rectangle
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		first, last := -1, -1
+		for i := 3; i < len(row); i += 4 {
+			if row[i] != 0 {
+				if first < 0 {
+					first = i / 4
+				}
+				last = i / 4
+			}
+		}
+		if first >= 0 {
+			area = area.Union(image.Rect(b.Min.X+first, y, b.Min.X+last+1, y+1))
+		}
+	}
+	return area
+}
+
+// vectorShapeKind is the geometry of a vectorShape.
+type vectorShapeKind int
+
+const (
+	vectorRect vectorShapeKind = iota
+	vectorRoundRect
+	vectorEllipse
+	vectorPolygon
+	vectorPolyline
+)
+
+// vectorShape is a filled or stroked shape, in pixels.
+type vectorShape struct {
+	kind vectorShapeKind
+	// box is the frame of rectangles and ellipses.
+	box image.Rectangle
+	// radius is the corner radius of rounded rectangles.
+	radius int
+	// pts are the points of polygons and polylines.
+	pts []image.Point
+	// fill is the fill color, transparent for none.
+	fill color.RGBA
+	// pen and width are the outline color and width, 0 for none; inside
+	// draws the outline of a rectangle inside its frame.
+	pen    color.RGBA
+	width  int
+	inside bool
+}
+
+// bounds returns the area covered by s.
+func (s *vectorShape) bounds() image.Rectangle {
+	area := s.box
+	if s.kind == vectorPolygon || s.kind == vectorPolyline {
+		area = image.Rectangle{}
+		for i, pt := range s.pts {
+			if i == 0 {
+				area = image.Rectangle{Min: pt, Max: pt}
+			}
+			area = area.Union(image.Rectangle{Min: pt, Max: pt.Add(image.Pt(1, 1))})
+		}
+	}
+	if !s.inside {
+		area = area.Inset(-(s.width + 1) / 2)
+	}
+	return area
+}
+
+// fillShape records s filled with c, reporting whether it did: only opaque
+// fills are recorded, as EMF brushes have no transparency.
+func (v *vectorDrawing) fillShape(s *vectorShape, c color.RGBA) bool {
+	if v == nil || c.A != 255 {
+		return false
+	}
+	s.fill = c
+	v.add(s, s.bounds())
+	return true
+}
+
+// strokeShape records the outline of s, drawn width pixels wide in c,
+// reporting whether it did: only opaque outlines are recorded. A line
+// starting where the last recorded one of the same pen ends continues it.
+func (v *vectorDrawing) strokeShape(s *vectorShape, c color.RGBA, width int) bool {
+	if v == nil || c.A != 255 {
+		return false
+	}
+	s.pen, s.width = c, max(width, 1)
+	area := s.bounds()
+	if s.kind == vectorPolyline && len(v.ops) > 0 {
+		last, ok := v.ops[len(v.ops)-1].(*vectorShape)
+		if ok && last.kind == vectorPolyline && last.pen == s.pen && last.width == s.width &&
+			last.pts[len(last.pts)-1] == s.pts[0] && !drawnIn(v.img, area) {
+			last.pts = append(last.pts, s.pts[1:]...)
+			return true
+		}
+	}
+	v.add(s, area)
+	return true
+}
+
+// vectorLine returns the line from (x1, y1) to (x2, y2), rounded to whole
+// pixels.
+func vectorLine(x1, y1, x2, y2 float64) *vectorShape {
+	return &vectorShape{kind: vectorPolyline, pts: []image.Point{
+		{int(math.Round(x1)), int(math.Round(y1))},
+		{int(math.Round(x2)), int(math.Round(y2))},
+	}}
+}
+
+// vectorPoints returns the shape kind through pts, rounded to whole pixels.
+func vectorPoints(kind vectorShapeKind, pts []fpoint) *vectorShape {
+	s := &vectorShape{kind: kind, pts: make([]image.Point, len(pts))}
+	for i, pt := range pts {
+		s.pts[i] = image.Pt(int(math.Round(pt.x)), int(math.Round(pt.y)))
+	}
+	return s
+}
+
+func (s *vectorShape) emit(e *emfWriter) {
+	brush, pen := uint32(emfNullBrush), uint32(emfNullPen)
+	if s.fill.A != 0 {
+		e.record(emrCreateBrushIndirect, le32(nil, emfObjBrush, 0, colorRef(s.fill), 0)) // BS_SOLID
+		brush = emfObjBrush
+	}
+	if s.width > 0 {
+		style := int32(emfPen)
+		if s.inside {
+			style = emfPenInside
+		}
+		// No brush bitmap, then a solid geometric pen with no dashes.
+		e.record(emrExtCreatePen, le32(nil, emfObjPen, 0, 0, 0, 0, style, int32(s.width), 0, colorRef(s.pen), 0, 0))
+		pen = emfObjPen
+	}
+	e.selectObject(brush)
+	e.selectObject(pen)
+
+	box := le32(nil, int32(s.box.Min.X), int32(s.box.Min.Y), int32(s.box.Max.X), int32(s.box.Max.Y))
+	switch s.kind {
+	case vectorRect:
+		e.record(emrRectangle, box)
+	case vectorRoundRect:
+		e.record(emrRoundRect, le32(box, int32(2*s.radius), int32(2*s.radius)))
+	case vectorEllipse:
+		e.record(emrEllipse, box)
+	case vectorPolygon, vectorPolyline:
+		b := s.bounds()
+		body := le32(nil, int32(b.Min.X), int32(b.Min.Y), int32(b.Max.X), int32(b.Max.Y), int32(len(s.pts)))
+		for _, pt := range s.pts {
+			body = le32(body, int32(pt.X), int32(pt.Y))
+		}
+		typ := uint32(emrPolygon)
+		if s.kind == vectorPolyline {
+			typ = emrPolyline
+		}
+		e.record(typ, body)
+	}
+
+	if brush == emfObjBrush {
+		e.selectObject(emfNullBrush)
+		e.record(emrDeleteObject, le32(nil, emfObjBrush))
+	}
+	if pen == emfObjPen {
+		e.selectObject(emfNullPen)
+		e.record(emrDeleteObject, le32(nil, emfObjPen))
+	}
+}
+
+// colorRef returns the COLORREF of c.
+func colorRef(c color.RGBA) int32 {
+	return int32(uint32(c.R) | uint32(c.G)<<8 | uint32(c.B)<<16)
+}
+
+// vectorBitmap is pixels the renderer drew, premultiplied, at their place
+// on the slide.
+type vectorBitmap struct {
+	img *image.RGBA
+}
+
+func (b vectorBitmap) emit(e *emfWriter) {
+	e.record(emrAlphaBlend, alphaBlendBody(b.img))
+}
+
+// vectorTextRun is a text run positioned on the rendered image.
+type vectorTextRun struct {
+	text  []uint16 // UTF-16
+	dx    []int32  // advance of each UTF-16 unit, in pixels
+	name  string
+	size  float64 // em size in pixels
+	bold  bool
+	ital  bool
+	x, y  int // start of the baseline
+	color color.RGBA
+}
+
+// addText records run, drawn by r at (x, baseline) in color c. The advances
+// of its characters are taken from the face the renderer laid it out with,
+// so that the text keeps the rendered line breaks and alignment.
+func (v *vectorDrawing) addText(r *renderer, run textRun, x, baseline int, c color.RGBA) {
+	f := run.font
+	if f == nil {
+		f = NewFont()
+	}
+	sizePt := float64(f.Size)
+	if sizePt <= 0 {
+		sizePt = 10
+	}
+	if r.fontScale > 0 && r.fontScale != 1.0 {
+		sizePt *= r.fontScale
+	}
+	name := f.Name
+	if f.NameEA != "" && containsCJK(run.text) {
+		name = f.NameEA
+	}
+
+	vr := &vectorTextRun{
+		name:  name,
+		size:  sizePt * 12700.0 * r.scaleX,
+		bold:  f.Bold,
+		ital:  f.Italic,
+		x:     x,
+		y:     baseline,
+		color: c,
+	}
+	// The advance of a character runs to the start of the next, kerning
+	// included; the second unit of a surrogate pair advances by nothing.
+	var pos fixed.Int26_6
+	prev := rune(-1)
+	last, lastStart := -1, 0
+	for _, ch := range run.text {
+		if prev >= 0 {
+			pos += run.face.Kern(prev, ch)
+		}
+		start := pos.Round()
+		if last >= 0 {
+			vr.dx[last] = int32(start - lastStart)
+		}
+		last, lastStart = len(vr.dx), start
+		units := utf16.Encode([]rune{ch})
+		vr.text = append(vr.text, units...)
+		vr.dx = append(vr.dx, make([]int32, len(units))...)
+		if a, ok := run.face.GlyphAdvance(ch); ok {
+			pos += a
+		}
+		prev = ch
+	}
+	if last >= 0 {
+		vr.dx[last] = int32(pos.Round() - lastStart)
+	}
+	if len(vr.text) > 0 {
+		v.add(vr, vr.bounds())
+	}
+}
+
+// bounds returns the area the glyphs of run may cover.
+func (run *vectorTextRun) bounds() image.Rectangle {
+	width := int32(0)
+	for _, dx := range run.dx {
+		width += dx
+	}
+	size := int(math.Ceil(run.size))
+	return image.Rect(run.x, run.y-size, run.x+int(width), run.y+size/2)
+}
+
+func (run *vectorTextRun) emit(e *emfWriter) {
+	e.record(emrSetTextColor, le32(nil, colorRef(run.color)))
+	e.record(emrExtCreateFontIndirectW, append(le32(nil, emfObjFont), logFontW(run)...))
+	e.selectObject(emfObjFont)
+
+	b := run.bounds()
+	const textOffset = 76 // record header, EMR_EXTTEXTOUTW fields and EMRTEXT
+	strLen := (len(run.text)*2 + 3) &^ 3
+	var body []byte
+	body = le32(body, int32(b.Min.X), int32(b.Min.Y), int32(b.Max.X), int32(b.Max.Y)) // rclBounds
+	body = le32(body, emfCompatible, int32(math.Float32bits(e.exScale)), int32(math.Float32bits(e.eyScale)))
+	body = le32(body, int32(run.x), int32(run.y), int32(len(run.text)), textOffset)
+	body = le32(body, 0, 0, 0, -1, -1)          // fOptions and an empty rcl
+	body = le32(body, int32(textOffset+strLen)) // offDx
+	for _, u := range run.text {
+		body = binary.LittleEndian.AppendUint16(body, u)
+	}
+	for len(body)+8 < textOffset+strLen {
+		body = append(body, 0)
+	}
+	body = le32(body, run.dx...)
+	e.record(emrExtTextOutW, body)
+
+	e.selectObject(emfSystemFont)
+	e.record(emrDeleteObject, le32(nil, emfObjFont))
+}
+
+// RenderSlideEMF writes the slide at slideIndex to w as an Enhanced
+// Metafile, the vector format Word and Excel paste at full quality. Shapes,
+// lines, table cells and charts filled and outlined in opaque colors are
+// written as vector records, and text as text records that the application
+// draws with its own fonts. Pictures, gradients, shadows, transparent fills
+// and rotated shapes and text are embedded as bitmaps, rendered with opts
+// at opts.Width pixels wide, in their place in the z-order; the vector
+// records are placed on the same grid of pixels, so larger widths also
+// place them more precisely. The metafile has the physical size of the
+// slide. Format and JPEGQuality are ignored.
+func (p *Presentation) RenderSlideEMF(slideIndex int, w io.Writer, opts *RenderOptions) error {
+	drawing := &vectorDrawing{}
+	img, err := p.renderSlideInto(nil, slideIndex, opts, drawing, nil)
+	if err != nil {
+		return err
+	}
+	drawing.flush()
+	_, err = w.Write(encodeSlideEMF(img.Bounds(), p.layout.CX, p.layout.CY, drawing))
+	return err
+}
+
+// SaveSlideAsEMF renders a slide and saves it to an EMF file; see
+// RenderSlideEMF.
+func (p *Presentation) SaveSlideAsEMF(slideIndex int, path string, opts *RenderOptions) error {
+	dir := filepath.Dir(path)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	renderErr := p.RenderSlideEMF(slideIndex, f, opts)
+	closeErr := f.Close()
+	if renderErr != nil {
+		return renderErr
+	}
+	return closeErr
+}
+
+// emfWriter builds the records of an EMF.
+type emfWriter struct {
+	buf     bytes.Buffer
+	records uint32
+	// exScale and eyScale are the size of a pixel in .01 mm, for text
+	// drawn in the compatible graphics mode.
+	exScale, eyScale float32
+}
+
+// record appends a record of type typ with the given body.
+func (e *emfWriter) record(typ uint32, body []byte) {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	var head [8]byte
+	binary.LittleEndian.PutUint32(head[0:], typ)
+	binary.LittleEndian.PutUint32(head[4:], uint32(8+len(body)))
+	e.buf.Write(head[:])
+	e.buf.Write(body)
+	e.records++
+}
+
+// selectObject appends the record selecting the object at index h of the
+// object table, or the stock object h.
+func (e *emfWriter) selectObject(h uint32) {
+	e.record(emrSelectObject, binary.LittleEndian.AppendUint32(nil, h))
+}
+
+// le32 appends the little-endian encoding of each of vs to b.
+func le32(b []byte, vs ...int32) []byte {
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	}
+	return b
+}
+
+// encodeSlideEMF returns the EMF of the operations of a slide of cx by cy
+// EMUs drawn in pixels within bounds.
+func encodeSlideEMF(bounds image.Rectangle, cx, cy int64, drawing *vectorDrawing) []byte {
+	w, h := int32(bounds.Dx()), int32(bounds.Dy())
+	// 1/100 mm is 360 EMUs.
+	frameW, frameH := int32(cx/360), int32(cy/360)
+
+	e := &emfWriter{
+		exScale: float32(frameW) / float32(w),
+		eyScale: float32(frameH) / float32(h),
+	}
+	var head []byte
+	head = le32(head, 0, 0, w-1, h-1)           // rclBounds, in pixels
+	head = le32(head, 0, 0, frameW-1, frameH-1) // rclFrame, in 1/100 mm
+	head = le32(head, emfSignature, emfVersion)
+	head = le32(head, 0, 0) // nBytes and nRecords, set below
+	head = binary.LittleEndian.AppendUint16(head, emfObjPen+1)
+	head = binary.LittleEndian.AppendUint16(head, 0)
+	head = le32(head, 0, 0, 0)                // no description or palette
+	head = le32(head, w, h)                   // szlDevice, in pixels
+	head = le32(head, frameW/100, frameH/100) // szlMillimeters
+	head = le32(head, 0, 0, 0)                // no pixel format or OpenGL
+	head = le32(head, frameW*10, frameH*10)   // szlMicrometers
+	e.record(emrHeader, head)
+
+	e.record(emrSetBkMode, le32(nil, emfTransparent))
+	e.record(emrSetTextAlign, le32(nil, emfTABaseline))
+	for _, op := range drawing.ops {
+		op.emit(e)
+	}
+	e.record(emrEOF, le32(nil, 0, 16, 20))
+
+	data := e.buf.Bytes()
+	binary.LittleEndian.PutUint32(data[48:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(data[52:], e.records)
+	return data
+}
+
+// alphaBlendBody returns the body of an EMR_ALPHABLEND record that draws
+// the premultiplied pixels of img at their place.
+func alphaBlendBody(img *image.RGBA) []byte {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	const bmiOffset, bmiSize = 108, 40
+	var body []byte
+	body = le32(body, int32(b.Min.X), int32(b.Min.Y), int32(b.Max.X-1), int32(b.Max.Y-1)) // rclBounds
+	body = le32(body, int32(b.Min.X), int32(b.Min.Y), int32(w), int32(h))
+	// AC_SRC_OVER with per-pixel alpha (AC_SRC_ALPHA) at full opacity.
+	body = append(body, 0, 0, 255, 1)
+	body = le32(body, 0, 0)
+	one := int32(math.Float32bits(1))
+	body = le32(body, one, 0, 0, one, 0, 0) // identity xformSrc
+	body = le32(body, 0, 0)                 // BkColorSrc, DIB_RGB_COLORS
+	body = le32(body, bmiOffset, bmiSize, bmiOffset+bmiSize, int32(w*h*4))
+	body = le32(body, int32(w), int32(h))
+
+	// BITMAPINFOHEADER of a bottom-up 32-bit DIB.
+	body = le32(body, bmiSize, int32(w), int32(h))
+	body = binary.LittleEndian.AppendUint16(body, 1)
+	body = binary.LittleEndian.AppendUint16(body, 32)
+	body = le32(body, 0, int32(w*h*4), 0, 0, 0, 0)
+
+	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		for x := 0; x < len(row); x += 4 {
+			body = append(body, row[x+2], row[x+1], row[x], row[x+3])
+		}
+	}
+	return body
+}
+
+// logFontW returns the LOGFONTW structure of the font of run.
+func logFontW(run *vectorTextRun) []byte {
+	weight := int32(400)
+	if run.bold {
+		weight = 700
+	}
+	var b []byte
+	b = le32(b, -int32(math.Round(run.size)), 0, 0, 0, weight)
+	italic := byte(0)
+	if run.ital {
+		italic = 1
+	}
+	// Italic, underline, strike-out, DEFAULT_CHARSET, then default
+	// precision, quality and pitch.
+	b = append(b, italic, 0, 0, 1, 0, 0, 0, 0)
+	name := utf16.Encode([]rune(run.name))
+	if len(name) > 31 {
+		name = name[:31]
+	}
+	for i := range 32 {
+		var u uint16
+		if i < len(name) {
+			u = name[i]
+		}
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return b
+}
@@ -0,0 +1,152 @@
+package gopresentation
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// hostile holds the XML special characters, characters XML does not
+// allow, and whitespace that attribute values normalize.
+const hostile = "a<b>&c\"d'e\x01f\x1fg\th\ni\uFFFEj"
+
+// xmlSafe returns s as it reads back: with the characters XML does not
+// allow replaced by U+FFFD.
+func xmlSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || r >= 0x20 && r != 0xFFFE && r != 0xFFFF {
+			return r
+		}
+		return '\uFFFD'
+	}, s)
+}
+
+// firstTextRun returns the first element of paras, which must be a text
+// run.
+func firstTextRun(t *testing.T, paras []*Paragraph) *TextRun {
+	t.Helper()
+	if len(paras) == 0 || len(paras[0].GetElements()) == 0 {
+		t.Fatal("no text")
+	}
+	tr, ok := paras[0].GetElements()[0].(*TextRun)
+	if !ok {
+		t.Fatalf("first element is %T", paras[0].GetElements()[0])
+	}
+	return tr
+}
+
+// attrValues returns the values of the attribute attr of the elements
+// with local name elem in the XML part, failing the test when there are
+// none.
+func attrValues(t *testing.T, part []byte, elem, attr string) []string {
+	t.Helper()
+	var vals []string
+	d := xml.NewDecoder(bytes.NewReader(part))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == elem {
+			vals = append(vals, attrValue(se.Attr, attr))
+		}
+	}
+	if len(vals) == 0 {
+		t.Fatalf("no %s elements", elem)
+	}
+	return vals
+}
+
+func TestHostileStringsRoundTrip(t *testing.T) {
+	p := New()
+	props := p.GetDocumentProperties()
+	props.Title = hostile
+	props.Creator = hostile
+
+	slide := p.GetActiveSlide()
+	slide.SetTag("NOTE", hostile)
+	slide.SetNotes(hostile)
+
+	shape := slide.CreateRichTextShape()
+	shape.SetName(hostile).SetDescription(hostile).SetTag("NOTE", hostile)
+	run := shape.CreateTextRun(hostile)
+	run.SetHyperlink(NewHyperlink("https://example.com/?q=" + hostile).SetTooltip(hostile))
+
+	table := slide.CreateTableShape(1, 1)
+	table.GetCell(0, 0).SetText(hostile)
+
+	chart := slide.CreateChartShape()
+	chart.GetTitle().SetText(hostile)
+	chart.GetPlotArea().SetType(NewBarChart().AddSeries(
+		NewChartSeriesOrdered(hostile, []string{hostile}, []float64{1})))
+
+	slide.AddComment(NewComment().SetAuthor(NewCommentAuthor(hostile, hostile)).SetText(hostile))
+
+	data := writePackage(t, p)
+	checkWellFormed(t, data)
+
+	got := readPackage(t, data)
+	want := xmlSafe(hostile)
+	check := func(what, s string) {
+		t.Helper()
+		if s != want {
+			t.Errorf("%s = %q, want %q", what, s, want)
+		}
+	}
+	gotProps := got.GetDocumentProperties()
+	check("title", gotProps.Title)
+	check("creator", gotProps.Creator)
+
+	s, err := got.GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Comment authors are not read back, so they are checked in the part
+	// written.
+	authors := packageParts(t, data, "ppt/commentAuthors.xml")["ppt/commentAuthors.xml"]
+	for _, attr := range []string{"name", "initials"} {
+		for _, v := range attrValues(t, authors, "cmAuthor", attr) {
+			check("author "+attr, v)
+		}
+	}
+	check("slide tag", s.GetTag("NOTE"))
+	check("notes", s.GetNotes())
+
+	var sawText, sawTable, sawChart bool
+	for _, sh := range s.GetShapes() {
+		switch sh := sh.(type) {
+		case *RichTextShape:
+			sawText = true
+			check("shape name", sh.GetName())
+			check("shape description", sh.GetDescription())
+			check("shape tag", sh.GetTag("NOTE"))
+			tr := firstTextRun(t, sh.GetParagraphs())
+			check("run text", tr.GetText())
+			if h := tr.GetHyperlink(); h == nil {
+				t.Error("hyperlink was not read")
+			} else {
+				check("tooltip", h.Tooltip)
+				check("URL", strings.TrimPrefix(h.URL, "https://example.com/?q="))
+			}
+		case *TableShape:
+			sawTable = true
+			check("cell text", firstTextRun(t, sh.GetCell(0, 0).GetParagraphs()).GetText())
+		case *ChartShape:
+			sawChart = true
+			check("chart title", sh.GetTitle().Text)
+			series := getChartSeries(sh.GetPlotArea().GetType())
+			check("series title", series[0].Title)
+			check("category", series[0].Categories[0])
+		}
+	}
+	if !sawText || !sawTable || !sawChart {
+		t.Errorf("shapes read: text %v, table %v, chart %v", sawText, sawTable, sawChart)
+	}
+
+	comments := s.GetComments()
+	if len(comments) != 1 {
+		t.Fatalf("comments = %v", comments)
+	}
+	check("comment text", comments[0].Text)
+}
@@ -0,0 +1,67 @@
+package gopresentation
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// Extension lists (a:extLst, p:extLst) carry features the schema does not
+// define, such as the creation IDs and design tags newer versions of
+// PowerPoint write. The reader keeps those of slides and of the non-visual
+// properties of shapes as raw XML and the writer emits them unchanged.
+
+// extLsts holds the raw extension lists of a shape's non-visual
+// properties.
+type extLsts struct {
+	// cNvPr is the a:extLst of p:cNvPr.
+	cNvPr string
+	// nvPr is the p:extLst of p:nvPr.
+	nvPr string
+}
+
+// cNvPrEndXML returns the end of the p:cNvPr element of b, written with
+// shape ID id: the end of the start tag, followed by the element's
+// extension list.
+func (w *PPTXWriter) cNvPrEndXML(b *BaseShape, id int) string {
+	return ">" + shapeExtLstXML(b, w.shapeCreationID(b, id)) + "</p:cNvPr>"
+}
+
+// keepExtLst returns raw, the XML of an extension list read from a part,
+// if it can be written back unchanged: it uses prefix for the list itself
+// and declares the namespaces of any other prefixes it uses. Otherwise it
+// returns "".
+func keepExtLst(raw []byte, prefix string) string {
+	if !bytes.HasPrefix(raw, []byte("<"+prefix+":extLst")) {
+		return ""
+	}
+	// Prefixes the decoder cannot resolve are left in Name.Space; only
+	// those the writer declares on the part's root may remain.
+	known := func(space string) bool {
+		switch space {
+		case "", "a", "p", "r", "xml", "xmlns":
+			return true
+		}
+		return strings.Contains(space, ":")
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		t, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !known(t.Name.Space) {
+			return ""
+		}
+		for _, attr := range t.Attr {
+			if !known(attr.Name.Space) {
+				return ""
+			}
+		}
+	}
+	return string(raw)
+}
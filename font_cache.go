@@ -1,12 +1,16 @@
 package gopresentation
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
@@ -28,9 +32,23 @@ type FontCache struct {
 	mu           sync.RWMutex
 	dirs         []string                  // directories to search for fonts
 	fonts        map[string]*opentype.Font // lowercase font name -> parsed font
-	faces        map[fontKey]font.Face     // cached render faces (HintingFull)
-	measureFaces map[fontKey]font.Face     // cached measure faces (HintingNone)
+	faces        map[fontKey]*faceEntry    // cached render faces (HintingFull)
+	measureFaces map[fontKey]*faceEntry    // cached measure faces (HintingNone)
 	scanned      bool
+
+	// maxFaces bounds each face map; 0 means unlimited. tick orders face
+	// use for least-recently-used eviction.
+	maxFaces int
+	tick     atomic.Uint64
+
+	// families maps lowercase family names to their display form.
+	families map[string]string
+}
+
+// faceEntry is a cached face and the tick at which it was last used.
+type faceEntry struct {
+	face font.Face
+	used atomic.Uint64
 }
 
 // NewFontCache creates a FontCache that searches the given directories
@@ -40,8 +58,100 @@ func NewFontCache(extraDirs ...string) *FontCache {
 	return &FontCache{
 		dirs:         dirs,
 		fonts:        make(map[string]*opentype.Font),
-		faces:        make(map[fontKey]font.Face),
-		measureFaces: make(map[fontKey]font.Face),
+		faces:        make(map[fontKey]*faceEntry),
+		measureFaces: make(map[fontKey]*faceEntry),
+	}
+}
+
+// SetMaxFaces limits the number of render faces, and separately measure
+// faces, kept in the cache. When the limit is reached the least recently
+// used face is evicted. Zero (the default) keeps every face, which suits
+// one-off renders; long-running services rendering many sizes should set a
+// limit so the cache does not grow without bound.
+func (fc *FontCache) SetMaxFaces(n int) *FontCache {
+	if n < 0 {
+		n = 0
+	}
+	fc.mu.Lock()
+	fc.maxFaces = n
+	fc.evictFaces(fc.faces, 0)
+	fc.evictFaces(fc.measureFaces, 0)
+	fc.mu.Unlock()
+	return fc
+}
+
+// GetMaxFaces returns the face limit set by SetMaxFaces.
+func (fc *FontCache) GetMaxFaces() int {
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+	return fc.maxFaces
+}
+
+// cachedFace returns the face stored under key in m and marks it used.
+func (fc *FontCache) cachedFace(m map[fontKey]*faceEntry, key fontKey) font.Face {
+	fc.mu.RLock()
+	e, ok := m[key]
+	fc.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	e.used.Store(fc.tick.Add(1))
+	return e.face
+}
+
+// storeFace adds face to m under key, evicting old faces beyond the limit.
+func (fc *FontCache) storeFace(m map[fontKey]*faceEntry, key fontKey, face font.Face) {
+	e := &faceEntry{face: face}
+	e.used.Store(fc.tick.Add(1))
+	fc.mu.Lock()
+	if _, ok := m[key]; !ok {
+		fc.evictFaces(m, 1)
+	}
+	m[key] = e
+	fc.mu.Unlock()
+}
+
+// evictFaces removes least recently used faces from m until room more faces
+// can be added within maxFaces. fc.mu must be held for writing.
+func (fc *FontCache) evictFaces(m map[fontKey]*faceEntry, room int) {
+	if fc.maxFaces <= 0 {
+		return
+	}
+	for len(m) > 0 && len(m)+room > fc.maxFaces {
+		var oldest fontKey
+		oldestUse := uint64(math.MaxUint64)
+		for k, e := range m {
+			if u := e.used.Load(); u < oldestUse {
+				oldest, oldestUse = k, u
+			}
+		}
+		delete(m, oldest)
+	}
+}
+
+// fork returns a cache sharing fc's parsed fonts with its own empty face
+// maps. Faces are not safe for concurrent use, so goroutines rendering in
+// parallel each use a fork.
+func (fc *FontCache) fork() *FontCache {
+	fc.ensureScanned()
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+	fonts := make(map[string]*opentype.Font, len(fc.fonts))
+	for k, f := range fc.fonts {
+		fonts[k] = f
+	}
+	families := make(map[string]string, len(fc.families))
+	for k, name := range fc.families {
+		families[k] = name
+	}
+	return &FontCache{
+		dirs:         append([]string(nil), fc.dirs...),
+		fonts:        fonts,
+		families:     families,
+		faces:        make(map[fontKey]*faceEntry),
+		measureFaces: make(map[fontKey]*faceEntry),
+		scanned:      true,
+		maxFaces:     fc.maxFaces,
 	}
 }
 
@@ -52,12 +162,9 @@ func (fc *FontCache) GetFace(name string, sizePt float64, bold, italic bool) fon
 
 	key := fontKey{name: strings.ToLower(name), size: sizePt, bold: bold, italic: italic}
 
-	fc.mu.RLock()
-	if face, ok := fc.faces[key]; ok {
-		fc.mu.RUnlock()
+	if face := fc.cachedFace(fc.faces, key); face != nil {
 		return face
 	}
-	fc.mu.RUnlock()
 
 	// Try to find the font with style variants
 	f := fc.findFont(name, bold, italic)
@@ -75,9 +182,7 @@ func (fc *FontCache) GetFace(name string, sizePt float64, bold, italic bool) fon
 		return nil
 	}
 
-	fc.mu.Lock()
-	fc.faces[key] = face
-	fc.mu.Unlock()
+	fc.storeFace(fc.faces, key, face)
 	return face
 }
 
@@ -91,12 +196,9 @@ func (fc *FontCache) GetMeasureFace(name string, sizePt float64, bold, italic bo
 
 	key := fontKey{name: strings.ToLower(name), size: sizePt, bold: bold, italic: italic}
 
-	fc.mu.RLock()
-	if face, ok := fc.measureFaces[key]; ok {
-		fc.mu.RUnlock()
+	if face := fc.cachedFace(fc.measureFaces, key); face != nil {
 		return face
 	}
-	fc.mu.RUnlock()
 
 	f := fc.findFont(name, bold, italic)
 	if f == nil {
@@ -112,9 +214,7 @@ func (fc *FontCache) GetMeasureFace(name string, sizePt float64, bold, italic bo
 		return nil
 	}
 
-	fc.mu.Lock()
-	fc.measureFaces[key] = face
-	fc.mu.Unlock()
+	fc.storeFace(fc.measureFaces, key, face)
 	return face
 }
 
@@ -229,6 +329,194 @@ func (fc *FontCache) LoadFontData(name string, data []byte) error {
 	return nil
 }
 
+// NewMemoryFontCache creates a FontCache that never searches the filesystem.
+// Fonts are only available once added with RegisterFont, RegisterFace or
+// RegisterEmbeddedFonts, which suits fonts bundled with //go:embed.
+func NewMemoryFontCache() *FontCache {
+	return &FontCache{
+		fonts:        make(map[string]*opentype.Font),
+		faces:        make(map[fontKey]*faceEntry),
+		measureFaces: make(map[fontKey]*faceEntry),
+		scanned:      true,
+	}
+}
+
+// RegisterFont registers an in-memory TrueType/OpenType font or font
+// collection under name as well as its internal family names. Registered
+// fonts take precedence over fonts of the same name found on disk.
+func (fc *FontCache) RegisterFont(name string, data []byte) error {
+	fonts, err := parseFontData(data)
+	if err != nil {
+		return err
+	}
+	fc.ensureScanned()
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if name != "" {
+		fc.fonts[strings.ToLower(name)] = fonts[0]
+		fc.addFamily(name)
+	}
+	for _, f := range fonts {
+		fc.registerByFamilyName(f)
+	}
+	fc.resetFaces()
+	return nil
+}
+
+// RegisterFace registers an in-memory font as the bold and/or italic
+// variant of family, so runs using that style of family render with it.
+// Registering with bold and italic false is the same as RegisterFont.
+func (fc *FontCache) RegisterFace(family string, bold, italic bool, data []byte) error {
+	if !bold && !italic {
+		return fc.RegisterFont(family, data)
+	}
+	if family == "" {
+		return fmt.Errorf("font family name is empty")
+	}
+	fonts, err := parseFontData(data)
+	if err != nil {
+		return err
+	}
+	suffix := " bold"
+	switch {
+	case bold && italic:
+		suffix = " bold italic"
+	case italic:
+		suffix = " italic"
+	}
+	fc.ensureScanned()
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	lower := strings.ToLower(family)
+	fc.fonts[lower+suffix] = fonts[0]
+	if _, ok := fc.fonts[lower]; !ok {
+		// Let a family with only styled faces still resolve for regular runs
+		fc.fonts[lower] = fonts[0]
+	}
+	fc.addFamily(family)
+	fc.resetFaces()
+	return nil
+}
+
+// RegisterEmbeddedFonts registers the fonts embedded in p (see
+// Presentation.GetEmbeddedFonts) under their typefaces. Fonts stored in a
+// format that cannot be decoded, such as MicroType Express compressed EOT,
+// are skipped. It returns the number of fonts registered.
+func (fc *FontCache) RegisterEmbeddedFonts(p *Presentation) int {
+	n := 0
+	for _, ef := range p.GetEmbeddedFonts() {
+		data, err := ef.FontData()
+		if err != nil {
+			continue
+		}
+		if fc.RegisterFace(ef.Typeface, ef.Bold, ef.Italic, data) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Families returns the sorted family names of the fonts available to the
+// cache, scanning the font directories first if needed.
+func (fc *FontCache) Families() []string {
+	fc.ensureScanned()
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+	names := make([]string, 0, len(fc.families))
+	for _, name := range fc.families {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// addFamily records name in the family list. fc.mu must be held for writing.
+func (fc *FontCache) addFamily(name string) {
+	if fc.families == nil {
+		fc.families = make(map[string]string)
+	}
+	lower := strings.ToLower(name)
+	if _, ok := fc.families[lower]; !ok {
+		fc.families[lower] = name
+	}
+}
+
+// resetFaces drops cached faces so newly registered fonts are picked up.
+// fc.mu must be held for writing.
+func (fc *FontCache) resetFaces() {
+	fc.faces = make(map[fontKey]*faceEntry)
+	fc.measureFaces = make(map[fontKey]*faceEntry)
+}
+
+// parseFontData parses a single font or every font of a collection.
+func parseFontData(data []byte) ([]*opentype.Font, error) {
+	if f, err := opentype.Parse(data); err == nil {
+		return []*opentype.Font{f}, nil
+	}
+	coll, err := opentype.ParseCollection(data)
+	if err != nil {
+		return nil, err
+	}
+	var fonts []*opentype.Font
+	for i := 0; i < coll.NumFonts(); i++ {
+		if f, err := coll.Font(i); err == nil {
+			fonts = append(fonts, f)
+		}
+	}
+	if len(fonts) == 0 {
+		return nil, fmt.Errorf("font collection contains no usable fonts")
+	}
+	return fonts, nil
+}
+
+// EmbeddedFont is a font embedded in a presentation (p:embeddedFont).
+// Data holds the raw part, usually an Embedded OpenType (.fntdata) file;
+// FontData returns the TrueType font inside it.
+type EmbeddedFont struct {
+	Typeface string
+	Bold     bool
+	Italic   bool
+	Data     []byte
+}
+
+// Embedded OpenType header flags.
+const (
+	eotFlagCompressed = 0x00000004 // TTEMBED_TTCOMPRESSED (MicroType Express)
+	eotFlagXOR        = 0x10000000 // TTEMBED_XORENCRYPTDATA
+)
+
+// FontData returns the TrueType/OpenType data of the embedded font,
+// unwrapping it from its Embedded OpenType container when needed.
+func (ef *EmbeddedFont) FontData() ([]byte, error) {
+	data := ef.Data
+	if _, err := opentype.Parse(data); err == nil {
+		return data, nil
+	}
+	if len(data) < 16 {
+		return nil, fmt.Errorf("embedded font %q is too short", ef.Typeface)
+	}
+	eotSize := binary.LittleEndian.Uint32(data[0:4])
+	fontSize := binary.LittleEndian.Uint32(data[4:8])
+	flags := binary.LittleEndian.Uint32(data[12:16])
+	if uint64(eotSize) != uint64(len(data)) || fontSize == 0 || fontSize > eotSize-16 {
+		return nil, fmt.Errorf("embedded font %q is not a font or EOT file", ef.Typeface)
+	}
+	if flags&eotFlagCompressed != 0 {
+		return nil, fmt.Errorf("embedded font %q uses unsupported MTX compression", ef.Typeface)
+	}
+	// The font data is the last field of every EOT version
+	out := make([]byte, fontSize)
+	copy(out, data[eotSize-fontSize:])
+	if flags&eotFlagXOR != 0 {
+		for i := range out {
+			out[i] ^= 0x50
+		}
+	}
+	return out, nil
+}
+
 func (fc *FontCache) ensureScanned() {
 	fc.mu.RLock()
 	scanned := fc.scanned
@@ -366,6 +654,7 @@ func (fc *FontCache) registerByFamilyName(f *opentype.Font) {
 	familyName, err := f.Name(nil, sfnt.NameIDFamily)
 	if err == nil && familyName != "" {
 		fc.fonts[strings.ToLower(familyName)] = f
+		fc.addFamily(familyName)
 	}
 	// Also register by full name (e.g. "Microsoft YaHei Bold")
 	fullName, err := f.Name(nil, sfnt.NameIDFull)
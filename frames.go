@@ -0,0 +1,197 @@
+package gopresentation
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// FrameOptions controls the frame sequence written by ExportFrames.
+type FrameOptions struct {
+	// Render controls how slides are rendered, including the image format
+	// of the frames. Nil uses DefaultRenderOptions.
+	Render *RenderOptions
+	// FPS is the number of frames per second. Default: 30.
+	FPS int
+	// SlideDuration is the time in milliseconds a slide is shown when it
+	// has no timing of its own, or when IgnoreTimings is set. Default: 5000.
+	SlideDuration int
+	// IgnoreTimings shows every slide for SlideDuration instead of the time
+	// set with Slide.SetAdvanceAfter.
+	IgnoreTimings bool
+	// Transitions adds the transition of each slide before it, drawn as a
+	// crossfade from the previous slide whatever the effect, for the
+	// transition's Duration or the time of its Speed.
+	Transitions bool
+	// IncludeHidden includes hidden slides, which a slideshow skips.
+	IncludeHidden bool
+	// Pattern is the name of the frame files in the directory, with a verb
+	// for the 1-based frame number. Default: "frame%06d" and the extension
+	// of the image format.
+	Pattern string
+	// Encoder, when set, is called with each frame in order, for instance to
+	// feed a video encoder. The image is reused for following frames and
+	// must not be kept. Returning an error stops the export.
+	Encoder func(frame int, img *image.RGBA) error
+}
+
+// DefaultFrameOptions returns the default frame options.
+func DefaultFrameOptions() *FrameOptions {
+	return &FrameOptions{FPS: 30, SlideDuration: 5000}
+}
+
+// transitionSpeedDurations are the times in milliseconds of transitions
+// without an explicit duration, as PowerPoint plays them.
+var transitionSpeedDurations = map[TransitionSpeed]int{
+	TransitionSpeedSlow:   1000,
+	TransitionSpeedMedium: 750,
+	TransitionSpeedFast:   500,
+}
+
+// ExportFrames renders the slides as a numbered sequence of frames for
+// slide-to-video pipelines, each slide repeated for its duration at
+// opts.FPS. Frames are written to dir, unless it is empty, and passed to
+// opts.Encoder when it is set. A nil opts uses DefaultFrameOptions. It
+// returns the number of frames.
+func (p *Presentation) ExportFrames(dir string, opts *FrameOptions) (int, error) {
+	if opts == nil {
+		opts = DefaultFrameOptions()
+	}
+	fps := opts.FPS
+	if fps <= 0 {
+		fps = 30
+	}
+	slideDuration := opts.SlideDuration
+	if slideDuration <= 0 {
+		slideDuration = 5000
+	}
+	ro := DefaultRenderOptions()
+	if opts.Render != nil {
+		copied := *opts.Render
+		ro = &copied
+	}
+	if ro.FontCache == nil {
+		ro.FontCache = NewFontCache(ro.FontDirs...)
+	}
+	if ro.ImageCache == nil {
+		ro.ImageCache = NewImageCache()
+	}
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = "frame%06d" + imageFormatExtension(ro.Format)
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return 0, fmt.Errorf("create directory: %w", err)
+		}
+	}
+
+	ex := &frameExporter{dir: dir, pattern: pattern, opts: opts, render: ro}
+	var prev *image.RGBA
+	for i, slide := range p.slides {
+		if !slide.visible && !opts.IncludeHidden {
+			continue
+		}
+		img, err := p.SlideToImage(i, ro)
+		if err != nil {
+			return ex.frames, fmt.Errorf("slide %d: %w", i+1, err)
+		}
+		cur := img.(*image.RGBA)
+
+		if t := slide.transition; opts.Transitions && prev != nil && t != nil && t.Type != TransitionNone {
+			ms := t.Duration
+			if ms <= 0 {
+				ms = transitionSpeedDurations[t.Speed]
+			}
+			if ms <= 0 {
+				ms = transitionSpeedDurations[TransitionSpeedFast]
+			}
+			n := frameCount(ms, fps)
+			blend := image.NewRGBA(cur.Rect)
+			for f := range n {
+				crossfade(blend, prev, cur, float64(f+1)/float64(n+1))
+				if err := ex.emit(blend, 1); err != nil {
+					return ex.frames, err
+				}
+			}
+		}
+
+		ms := slideDuration
+		if adv := slide.GetAdvanceAfter(); adv > 0 && !opts.IgnoreTimings {
+			ms = adv
+		}
+		if err := ex.emit(cur, frameCount(ms, fps)); err != nil {
+			return ex.frames, err
+		}
+		prev = cur
+	}
+	return ex.frames, nil
+}
+
+// frameExporter writes the frames of ExportFrames.
+type frameExporter struct {
+	dir     string
+	pattern string
+	opts    *FrameOptions
+	render  *RenderOptions
+	frames  int
+}
+
+// emit adds img as the next n frames. The image is encoded once for the n
+// files.
+func (ex *frameExporter) emit(img *image.RGBA, n int) error {
+	var data []byte
+	if ex.dir != "" && n > 0 {
+		var buf bytes.Buffer
+		if err := encodeImage(&buf, alphaImage(img, ex.render), ex.render.Format, ex.render.JPEGQuality); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+	for range n {
+		ex.frames++
+		if data != nil {
+			path := filepath.Join(ex.dir, fmt.Sprintf(ex.pattern, ex.frames))
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("frame %d: %w", ex.frames, err)
+			}
+		}
+		if ex.opts.Encoder != nil {
+			if err := ex.opts.Encoder(ex.frames, img); err != nil {
+				return fmt.Errorf("frame %d: %w", ex.frames, err)
+			}
+		}
+	}
+	return nil
+}
+
+// frameCount returns the number of frames lasting ms at fps, at least one.
+func frameCount(ms, fps int) int {
+	return max(int(math.Round(float64(ms)*float64(fps)/1000)), 1)
+}
+
+// crossfade sets dst to from blended into to by t, from 0 to 1. The images
+// have the same bounds.
+func crossfade(dst, from, to *image.RGBA, t float64) {
+	w := uint32(math.Round(t * 256))
+	for i := range dst.Pix {
+		dst.Pix[i] = uint8((uint32(from.Pix[i])*(256-w) + uint32(to.Pix[i])*w) >> 8)
+	}
+}
+
+// imageFormatExtension returns the file extension of format.
+func imageFormatExtension(format ImageFormat) string {
+	switch format {
+	case ImageFormatJPEG:
+		return ".jpg"
+	case ImageFormatBMP:
+		return ".bmp"
+	case ImageFormatWebP:
+		return ".webp"
+	default:
+		return ".png"
+	}
+}
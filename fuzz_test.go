@@ -0,0 +1,94 @@
+package gopresentation
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// fuzzSeedDecks returns small decks covering the parts the reader parses:
+// text, pictures, tables, charts, hyperlinks, notes and comments.
+func fuzzSeedDecks(f *testing.F) [][]byte {
+	f.Helper()
+	decks := [][]byte{writePackage(f, New())}
+
+	p := New()
+	slide := p.GetActiveSlide()
+	shape := slide.CreateRichTextShape()
+	shape.SetOffsetX(100).SetOffsetY(100).SetWidth(4000000).SetHeight(1000000)
+	shape.CreateTextRun("Hello").SetHyperlink(NewHyperlink("https://example.com"))
+	table := slide.CreateTableShape(2, 2)
+	table.GetCell(0, 0).SetText("cell")
+	chart := slide.CreateChartShape()
+	chart.GetPlotArea().SetType(NewLineChart().AddSeries(
+		NewChartSeriesOrdered("S", []string{"a", "b"}, []float64{1, 2})))
+	chart.SetDataSource(ChartDataWorkbook)
+	slide.AddComment(NewComment().SetAuthor(NewCommentAuthor("A", "A")).SetText("note"))
+	p.CreateSlide().CreateAutoShape().CreateTextRun("Next").SetHyperlink(NewInternalHyperlink(1))
+	decks = append(decks, writePackage(f, p))
+	return decks
+}
+
+func FuzzReadFromReader(f *testing.F) {
+	for _, deck := range fuzzSeedDecks(f) {
+		f.Add(deck)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := ReadFrom(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return
+		}
+		if err := p.WriteTo(io.Discard); err != nil {
+			t.Fatalf("WriteTo of a read deck: %v", err)
+		}
+	})
+}
+
+func FuzzParseSlideXML(f *testing.F) {
+	for _, deck := range fuzzSeedDecks(f) {
+		for _, part := range packageParts(f, deck, "ppt/slides/slide") {
+			f.Add(part)
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		slide, err := ParseSlideXML(data)
+		if err != nil {
+			return
+		}
+		p := New()
+		p.AddSlide(slide)
+		if err := p.WriteTo(io.Discard); err != nil {
+			t.Fatalf("WriteTo of a parsed slide: %v", err)
+		}
+	})
+}
+
+func FuzzParseChartXML(f *testing.F) {
+	for _, deck := range fuzzSeedDecks(f) {
+		for _, part := range packageParts(f, deck, "ppt/charts/chart") {
+			f.Add(part)
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		chart, err := ParseChartXML(data)
+		if err != nil {
+			return
+		}
+		p := New()
+		p.GetActiveSlide().AddShape(chart)
+		if err := p.WriteTo(io.Discard); err != nil {
+			t.Fatalf("WriteTo of a parsed chart: %v", err)
+		}
+	})
+}
+
+func FuzzParseCommentsXML(f *testing.F) {
+	for _, deck := range fuzzSeedDecks(f) {
+		for _, part := range packageParts(f, deck, "ppt/comments/") {
+			f.Add(part)
+		}
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = ParseCommentsXML(data)
+	})
+}
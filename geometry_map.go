@@ -0,0 +1,130 @@
+package gopresentation
+
+import (
+	"image"
+	"math"
+)
+
+// ShapeBox is where a shape is drawn on a rendered slide image, for tools
+// that overlay hotspots or annotations on the image.
+type ShapeBox struct {
+	// ID is the shape ID written for the shape (the id of its p:cNvPr):
+	// shapes are numbered from 2 in document order, a group before its
+	// children.
+	ID int
+	// ParentID is the ID of the group holding the shape, or 0 for a shape
+	// placed on the slide itself.
+	ParentID int
+	Name     string
+	Type     ShapeType
+	// Bounds is the axis-aligned box enclosing the shape as drawn, after
+	// rotation, in pixels of the slide image.
+	Bounds image.Rectangle
+}
+
+// GeometryMapOptions sets the slide image ExportGeometryMap measures shapes
+// in.
+type GeometryMapOptions struct {
+	// Width is the width in pixels of the slide image, as in
+	// RenderOptions. Default: 960.
+	Width int
+	// Layout is the slide size of the presentation holding the slide, from
+	// Presentation.GetLayout. Default: the 4:3 layout of a new
+	// presentation.
+	Layout *DocumentLayout
+}
+
+// DefaultGeometryMapOptions returns the options of the boxes of a slide of
+// a new presentation rendered with DefaultRenderOptions.
+func DefaultGeometryMapOptions() *GeometryMapOptions {
+	return &GeometryMapOptions{Width: 960, Layout: NewDocumentLayout()}
+}
+
+// ExportGeometryMap returns the box of each shape of the slide in the
+// image SlideToImage renders at opts.Width, the children of a group
+// following it. Shapes are listed bottom to top, so the last box containing
+// a point is that of the shape drawn on top. A nil opts uses the defaults.
+func (s *Slide) ExportGeometryMap(opts *GeometryMapOptions) []ShapeBox {
+	var boxes []ShapeBox
+	s.forEachShapeBox(opts, func(_ Shape, box ShapeBox) {
+		boxes = append(boxes, box)
+	})
+	return boxes
+}
+
+// forEachShapeBox calls fn with each shape of the slide and its box, in the
+// order of ExportGeometryMap.
+func (s *Slide) forEachShapeBox(opts *GeometryMapOptions, fn func(shape Shape, box ShapeBox)) {
+	scaleX, scaleY, ok := geometryMapScale(opts)
+	if !ok {
+		return
+	}
+	id := 2
+	var walk func(shapes []Shape, parent int, toSlide func(x, y float64) (float64, float64))
+	walk = func(shapes []Shape, parent int, toSlide func(x, y float64) (float64, float64)) {
+		for _, shape := range shapes {
+			b := shape.base()
+			box := ShapeBox{ID: id, ParentID: parent, Name: b.name, Type: shape.GetType()}
+			box.Bounds = b.pixelBounds(toSlide, scaleX, scaleY)
+			id++
+			fn(shape, box)
+			if g, ok := shape.(*GroupShape); ok {
+				walk(g.shapes, box.ID, g.childToSlide(toSlide))
+			}
+		}
+	}
+	walk(s.shapes, 0, func(x, y float64) (float64, float64) { return x, y })
+}
+
+// geometryMapScale returns the pixels per EMU of the slide image opts
+// describes, computed as SlideToImage does, or false for an empty layout.
+func geometryMapScale(opts *GeometryMapOptions) (scaleX, scaleY float64, ok bool) {
+	if opts == nil {
+		opts = DefaultGeometryMapOptions()
+	}
+	width, layout := opts.Width, opts.Layout
+	if width <= 0 {
+		width = 960
+	}
+	if layout == nil {
+		layout = NewDocumentLayout()
+	}
+	if layout.CX <= 0 || layout.CY <= 0 {
+		return 0, 0, false
+	}
+	height := int(float64(width) * float64(layout.CY) / float64(layout.CX))
+	return float64(width) / float64(layout.CX), float64(height) / float64(layout.CY), true
+}
+
+// pixelBounds returns the pixels covered by the shape's frame, whose
+// container toSlide maps to slide coordinates, at scaleX and scaleY pixels
+// per EMU.
+func (b *BaseShape) pixelBounds(toSlide func(x, y float64) (float64, float64), scaleX, scaleY float64) image.Rectangle {
+	x0, y0 := float64(b.offsetX), float64(b.offsetY)
+	x1, y1 := x0+float64(b.width), y0+float64(b.height)
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range [4][2]float64{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}} {
+		x, y := toSlide(b.fromLocal(c[0], c[1]))
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+	return image.Rect(
+		int(math.Floor(minX*scaleX)), int(math.Floor(minY*scaleY)),
+		int(math.Ceil(maxX*scaleX)), int(math.Ceil(maxY*scaleY)),
+	)
+}
+
+// childToSlide returns the mapping of the group's child space to slide
+// coordinates, given toSlide, that of the group's container.
+func (g *GroupShape) childToSlide(toSlide func(x, y float64) (float64, float64)) func(x, y float64) (float64, float64) {
+	chOffX, chOffY, chExtX, chExtY := g.childSpace()
+	return func(x, y float64) (float64, float64) {
+		if chExtX <= 0 || chExtY <= 0 {
+			return toSlide(g.fromLocal(x, y))
+		}
+		x = float64(g.offsetX) + (x-float64(chOffX))*float64(g.width)/float64(chExtX)
+		y = float64(g.offsetY) + (y-float64(chOffY))*float64(g.height)/float64(chExtY)
+		return toSlide(g.fromLocal(x, y))
+	}
+}
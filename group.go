@@ -1,6 +1,9 @@
 package gopresentation
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
 
 // GroupShape represents a group of shapes.
 type GroupShape struct {
@@ -57,6 +60,66 @@ func (g *GroupShape) RemoveShape(index int) error {
 	return nil
 }
 
+// childSpace returns the group's child coordinate space. Groups built with
+// AddShape have none and use the group's own offset and extent.
+func (g *GroupShape) childSpace() (offX, offY, extX, extY int64) {
+	if g.childExtX > 0 && g.childExtY > 0 {
+		return g.childOffX, g.childOffY, g.childExtX, g.childExtY
+	}
+	return g.offsetX, g.offsetY, g.width, g.height
+}
+
+// Ungroup removes all shapes from the group and returns them with their
+// position, size, rotation and flips converted from the group's child
+// coordinate space to the space the group itself is placed in, so they keep
+// their on-slide appearance. Use Slide.Ungroup to also replace the group on
+// its slide.
+func (g *GroupShape) Ungroup() []Shape {
+	chOffX, chOffY, chExtX, chExtY := g.childSpace()
+	scaleX, scaleY := 1.0, 1.0
+	if chExtX > 0 && chExtY > 0 {
+		scaleX = float64(g.width) / float64(chExtX)
+		scaleY = float64(g.height) / float64(chExtY)
+	}
+	gcx := float64(g.offsetX) + float64(g.width)/2
+	gcy := float64(g.offsetY) + float64(g.height)/2
+	sin, cos := math.Sincos(float64(g.rotation) * math.Pi / 180)
+
+	children := g.shapes
+	for _, child := range children {
+		b := child.base()
+		w := float64(b.width) * scaleX
+		h := float64(b.height) * scaleY
+		cx := float64(g.offsetX) + float64(b.offsetX-chOffX)*scaleX + w/2
+		cy := float64(g.offsetY) + float64(b.offsetY-chOffY)*scaleY + h/2
+		rot := b.rotation
+		// The group's flips apply before its rotation.
+		if g.flipHorizontal {
+			cx = 2*gcx - cx
+			b.flipHorizontal = !b.flipHorizontal
+			rot = -rot
+		}
+		if g.flipVertical {
+			cy = 2*gcy - cy
+			b.flipVertical = !b.flipVertical
+			rot = -rot
+		}
+		if g.rotation != 0 {
+			dx, dy := cx-gcx, cy-gcy
+			cx = gcx + dx*cos - dy*sin
+			cy = gcy + dx*sin + dy*cos
+			rot += g.rotation
+		}
+		b.width = int64(math.Round(w))
+		b.height = int64(math.Round(h))
+		b.offsetX = int64(math.Round(cx - w/2))
+		b.offsetY = int64(math.Round(cy - h/2))
+		b.SetRotation(rot)
+	}
+	g.shapes = make([]Shape, 0)
+	return children
+}
+
 // PlaceholderShape represents a placeholder shape (title, body, etc.).
 type PlaceholderShape struct {
 	RichTextShape
@@ -96,8 +159,9 @@ func (p *PlaceholderShape) GetPlaceholderType() PlaceholderType {
 }
 
 // SetPlaceholderIndex sets the placeholder index.
-func (p *PlaceholderShape) SetPlaceholderIndex(idx int) {
+func (p *PlaceholderShape) SetPlaceholderIndex(idx int) *PlaceholderShape {
 	p.phIdx = idx
+	return p
 }
 
 // GetPlaceholderIndex returns the placeholder index.
@@ -106,10 +170,11 @@ func (p *PlaceholderShape) GetPlaceholderIndex() int {
 }
 
 // SetText sets the placeholder text, replacing all existing content with a single paragraph.
-func (p *PlaceholderShape) SetText(text string) {
+func (p *PlaceholderShape) SetText(text string) *PlaceholderShape {
 	p.paragraphs = []*Paragraph{NewParagraph()}
 	p.paragraphs[0].CreateTextRun(text)
 	p.activeParagraph = 0
+	return p
 }
 
 // Clear clears the placeholder content and adds a single empty paragraph.
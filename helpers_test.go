@@ -0,0 +1,103 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// writePackage writes p and returns the package bytes.
+func writePackage(t testing.TB, p *Presentation, opts ...WriterOption) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := p.WriteTo(&buf, opts...); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// readPackage reads the package data.
+func readPackage(t testing.TB, data []byte) *Presentation {
+	t.Helper()
+	p, err := ReadFrom(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	return p
+}
+
+// packageParts returns the contents of the parts of the package data whose
+// names start with prefix, by name.
+func packageParts(t testing.TB, data []byte, prefix string) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	parts := map[string][]byte{}
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, prefix) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		parts[f.Name] = b
+	}
+	return parts
+}
+
+// removePart returns the package data without the part name.
+func removePart(t testing.TB, data []byte, name string) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if f.Name == name {
+			continue
+		}
+		if err := zw.Copy(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// checkWellFormed fails the test for each XML part of the package data
+// that does not parse.
+func checkWellFormed(t testing.TB, data []byte) {
+	t.Helper()
+	for name, part := range packageParts(t, data, "") {
+		if !strings.HasSuffix(name, ".xml") && !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		d := xml.NewDecoder(bytes.NewReader(part))
+		for {
+			_, err := d.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Errorf("%s is not well-formed: %v", name, err)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package gopresentation
+
+import "math"
+
+// Rect is an axis-aligned rectangle in EMU.
+type Rect struct {
+	X, Y          int64
+	Width, Height int64
+}
+
+// Contains reports whether the point (x, y) lies inside r, edges included.
+func (r Rect) Contains(x, y int64) bool {
+	return x >= r.X && x <= r.X+r.Width && y >= r.Y && y <= r.Y+r.Height
+}
+
+// Intersects reports whether r and o share an area larger than zero.
+func (r Rect) Intersects(o Rect) bool {
+	return !r.Intersect(o).Empty()
+}
+
+// Intersect returns the largest rectangle contained in both r and o. It is
+// empty when they do not overlap.
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.Width, o.X+o.Width), min(r.Y+r.Height, o.Y+o.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Union returns the smallest rectangle containing both r and o. An empty
+// rectangle does not contribute.
+func (r Rect) Union(o Rect) Rect {
+	if r.Empty() {
+		return o
+	}
+	if o.Empty() {
+		return r
+	}
+	x0, y0 := min(r.X, o.X), min(r.Y, o.Y)
+	x1, y1 := max(r.X+r.Width, o.X+o.Width), max(r.Y+r.Height, o.Y+o.Height)
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Empty reports whether r has no area.
+func (r Rect) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// BoundingBox returns the axis-aligned box enclosing the shape as drawn,
+// that is after rotation about its center, in the coordinate space of its
+// container (the slide, or the child space of its group). Flips mirror the
+// shape within its frame and so do not change the box.
+func (b *BaseShape) BoundingBox() Rect {
+	if b.rotation%180 == 0 {
+		return Rect{X: b.offsetX, Y: b.offsetY, Width: b.width, Height: b.height}
+	}
+	sin, cos := math.Sincos(float64(b.rotation) * math.Pi / 180)
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	w := float64(b.width)*cos + float64(b.height)*sin
+	h := float64(b.width)*sin + float64(b.height)*cos
+	cx := float64(b.offsetX) + float64(b.width)/2
+	cy := float64(b.offsetY) + float64(b.height)/2
+	return Rect{
+		X:      int64(math.Round(cx - w/2)),
+		Y:      int64(math.Round(cy - h/2)),
+		Width:  int64(math.Round(w)),
+		Height: int64(math.Round(h)),
+	}
+}
+
+// toLocal maps the point (x, y) of the shape's container into the shape's
+// unrotated, unflipped frame.
+func (b *BaseShape) toLocal(x, y int64) (float64, float64) {
+	cx := float64(b.offsetX) + float64(b.width)/2
+	cy := float64(b.offsetY) + float64(b.height)/2
+	dx, dy := float64(x)-cx, float64(y)-cy
+	if b.rotation != 0 {
+		sin, cos := math.Sincos(-float64(b.rotation) * math.Pi / 180)
+		dx, dy = dx*cos-dy*sin, dx*sin+dy*cos
+	}
+	if b.flipHorizontal {
+		dx = -dx
+	}
+	if b.flipVertical {
+		dy = -dy
+	}
+	return cx + dx, cy + dy
+}
+
+// shapeContains reports whether the point (x, y) of the shape's container
+// falls on the shape's rotated frame. A group contains the point only where
+// one of its children does.
+func shapeContains(shape Shape, x, y int64) bool {
+	b := shape.base()
+	lx, ly := b.toLocal(x, y)
+	if lx < float64(b.offsetX) || lx > float64(b.offsetX+b.width) ||
+		ly < float64(b.offsetY) || ly > float64(b.offsetY+b.height) {
+		return false
+	}
+	g, ok := shape.(*GroupShape)
+	if !ok {
+		return true
+	}
+	chOffX, chOffY, chExtX, chExtY := g.childSpace()
+	if g.width <= 0 || g.height <= 0 {
+		return false
+	}
+	cx := float64(chOffX) + (lx-float64(g.offsetX))*float64(chExtX)/float64(g.width)
+	cy := float64(chOffY) + (ly-float64(g.offsetY))*float64(chExtY)/float64(g.height)
+	for i := len(g.shapes) - 1; i >= 0; i-- {
+		if shapeContains(g.shapes[i], int64(math.Round(cx)), int64(math.Round(cy))) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShapeAt returns the topmost shape on the slide under the point (x, y) in
+// EMU, taking rotation and flips into account, or nil when there is none.
+// A group is returned when the point falls on one of its children.
+func (s *Slide) ShapeAt(x, y int64) Shape {
+	for i := len(s.shapes) - 1; i >= 0; i-- {
+		if shapeContains(s.shapes[i], x, y) {
+			return s.shapes[i]
+		}
+	}
+	return nil
+}
+
+// ShapesOverlap reports whether the bounding boxes of a and b overlap.
+// Both shapes must be in the same coordinate space.
+func ShapesOverlap(a, b Shape) bool {
+	return a.base().BoundingBox().Intersects(b.base().BoundingBox())
+}
+
+// ShapeOverlap is a pair of overlapping shapes and the intersection of their
+// bounding boxes.
+type ShapeOverlap struct {
+	A, B Shape
+	Area Rect
+}
+
+// OverlappingShapes returns every pair of top-level shapes whose bounding
+// boxes overlap, in z-order (A below B).
+func (s *Slide) OverlappingShapes() []ShapeOverlap {
+	boxes := make([]Rect, len(s.shapes))
+	for i, shape := range s.shapes {
+		boxes[i] = shape.base().BoundingBox()
+	}
+	var overlaps []ShapeOverlap
+	for i := range s.shapes {
+		for j := i + 1; j < len(s.shapes); j++ {
+			if area := boxes[i].Intersect(boxes[j]); !area.Empty() {
+				overlaps = append(overlaps, ShapeOverlap{A: s.shapes[i], B: s.shapes[j], Area: area})
+			}
+		}
+	}
+	return overlaps
+}
@@ -0,0 +1,96 @@
+package gopresentation
+
+import "image"
+
+// HyperlinkRegion is a hyperlink of a slide and an area of the rendered
+// slide image that follows it, for clickable image maps.
+type HyperlinkRegion struct {
+	Hyperlink *Hyperlink
+	// ShapeID is the ID of the shape holding the link, as in
+	// ExportGeometryMap.
+	ShapeID int
+	// Text is the text of the run holding the link, or "" for the link of
+	// the shape itself.
+	Text string
+	// Bounds is the area in pixels of the slide image: the box of the shape
+	// for the link of a shape, that of one line of its text for the link of
+	// a text run.
+	Bounds image.Rectangle
+}
+
+// ExportHyperlinkMap returns the hyperlinks of the slide at slideIndex and
+// where they are in the image SlideToImage renders with opts. The link of a
+// shape covers the shape's box; the link of a text run covers the run's
+// text, with a region for each line the run wraps onto. Text drawn rotated,
+// that of rotated shapes and vertical text, is not located, so its links
+// cover the box of its shape. Regions are listed bottom to top, as in
+// ExportGeometryMap: in an HTML image map, where the first area containing
+// a point wins, write them in reverse order.
+func (p *Presentation) ExportHyperlinkMap(slideIndex int, opts *RenderOptions) ([]HyperlinkRegion, error) {
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	runs := runRects{}
+	if _, err := p.renderSlideInto(nil, slideIndex, opts, nil, runs); err != nil {
+		return nil, err
+	}
+	var regions []HyperlinkRegion
+	geometry := &GeometryMapOptions{Width: opts.Width, Layout: p.layout}
+	p.slides[slideIndex].forEachShapeBox(geometry, func(shape Shape, box ShapeBox) {
+		if h := shape.base().hyperlink; h != nil {
+			regions = append(regions, HyperlinkRegion{Hyperlink: h, ShapeID: box.ID, Bounds: box.Bounds})
+		}
+		for _, tr := range linkedRuns(shape) {
+			rects := runs[tr]
+			if len(rects) == 0 {
+				rects = []image.Rectangle{box.Bounds}
+			}
+			for _, rect := range rects {
+				regions = append(regions, HyperlinkRegion{Hyperlink: tr.hyperlink, ShapeID: box.ID, Text: tr.text, Bounds: rect})
+			}
+		}
+	})
+	return regions, nil
+}
+
+// linkedRuns returns the text runs with hyperlinks of shape, not counting
+// those of the children of a group.
+func linkedRuns(shape Shape) []*TextRun {
+	var runs []*TextRun
+	for _, para := range shapeParagraphs(shape) {
+		for _, elem := range para.elements {
+			if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil && tr.text != "" {
+				runs = append(runs, tr)
+			}
+		}
+	}
+	return runs
+}
+
+// runRects holds the rectangles, in pixels, the renderer drew each text run
+// with a hyperlink in.
+type runRects map[*TextRun][]image.Rectangle
+
+// add records that part of run was drawn in rect, joining it to the
+// previous part when they are adjacent on the same line, and once when it
+// is drawn again. It does nothing on
+// a nil map or run, so renderers need no check.
+func (m runRects) add(run *TextRun, rect image.Rectangle) {
+	if m == nil || run == nil || rect.Empty() {
+		return
+	}
+	rects := m[run]
+	for _, r := range rects {
+		if r == rect {
+			return
+		}
+	}
+	if n := len(rects); n > 0 {
+		last := &rects[n-1]
+		if last.Min.Y == rect.Min.Y && last.Max.Y == rect.Max.Y && last.Max.X == rect.Min.X {
+			last.Max.X = rect.Max.X
+			return
+		}
+	}
+	m[run] = append(rects, rect)
+}
@@ -0,0 +1,227 @@
+package gopresentation
+
+import (
+	"bytes"
+	"hash/maphash"
+	"image"
+	"image/draw"
+	"sync"
+)
+
+// ImageCache keeps the pictures decoded while rendering, so that a picture
+// used on several slides, or rendered again, is decoded once. Pictures much
+// larger than they are drawn are kept reduced to about twice the drawn size,
+// which is all the scaling to the slide needs. Share one cache across
+// renders through RenderOptions.ImageCache. An ImageCache is safe for
+// concurrent use.
+type ImageCache struct {
+	mu      sync.Mutex
+	seed    maphash.Seed
+	entries map[imageCacheKey]*imageCacheEntry
+	// maxBytes bounds the pixel bytes kept; 0 means unlimited. tick orders
+	// use for least-recently-used eviction.
+	maxBytes int64
+	bytes    int64
+	tick     uint64
+}
+
+// imageCacheKey identifies a picture by the hash and length of its data and
+// the size it was reduced for; 0×0 is the full size.
+type imageCacheKey struct {
+	sum  uint64
+	n    int
+	w, h int
+}
+
+type imageCacheEntry struct {
+	img  *image.RGBA // nil when the data could not be decoded
+	used uint64
+}
+
+// NewImageCache creates an empty ImageCache.
+func NewImageCache() *ImageCache {
+	return &ImageCache{seed: maphash.MakeSeed(), entries: make(map[imageCacheKey]*imageCacheEntry)}
+}
+
+// SetMaxBytes bounds the memory of the decoded pictures kept, in bytes of
+// pixels; beyond it the least recently used picture is dropped. Zero (the
+// default) keeps every picture, which suits rendering a known set of decks.
+func (c *ImageCache) SetMaxBytes(n int64) *ImageCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = n
+	c.evict(0)
+	return c
+}
+
+// GetMaxBytes returns the bound set with SetMaxBytes.
+func (c *ImageCache) GetMaxBytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxBytes
+}
+
+// Len returns the number of decoded pictures kept.
+func (c *ImageCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Clear drops every decoded picture.
+func (c *ImageCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.bytes = 0
+}
+
+// decode returns data decoded as an RGBA image, reduced when it is more than
+// twice as large as w×h in both directions; w or h 0 keeps the full size.
+// A nil cache decodes without keeping the result. The image is shared and
+// must not be modified. Decodes are counted in m.
+func (c *ImageCache) decode(data []byte, w, h int, fc *FontCache, m Metrics) *image.RGBA {
+	if c == nil {
+		countMetric(m, MetricImagesDecoded, 1)
+		img := decodePicture(data, fc)
+		if img == nil {
+			return nil
+		}
+		return reducePicture(img, w, h)
+	}
+
+	sum := maphash.Bytes(c.seed, data)
+	full := imageCacheKey{sum: sum, n: len(data)}
+	reduced := imageCacheKey{sum: sum, n: len(data), w: w, h: h}
+	if w > 0 && h > 0 {
+		if img, ok := c.get(reduced); ok {
+			return img
+		}
+	}
+	img, ok := c.get(full)
+	if !ok {
+		countMetric(m, MetricImagesDecoded, 1)
+		if img = decodePicture(data, fc); img == nil {
+			c.put(full, nil)
+			return nil
+		}
+	}
+	if w <= 0 || h <= 0 || !needsReduction(img, w, h) {
+		if !ok {
+			c.put(full, img)
+		}
+		return img
+	}
+	// Keep only the reduced picture of a large one decoded for this size
+	img = reducePicture(img, w, h)
+	c.put(reduced, img)
+	return img
+}
+
+func (c *ImageCache) get(key imageCacheKey) (*image.RGBA, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.tick++
+	e.used = c.tick
+	return e.img, true
+}
+
+func (c *ImageCache) put(key imageCacheKey, img *image.RGBA) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok {
+		c.bytes -= pictureBytes(old.img)
+	}
+	c.tick++
+	c.entries[key] = &imageCacheEntry{img: img, used: c.tick}
+	c.bytes += pictureBytes(img)
+	c.evict(1)
+}
+
+// evict drops least recently used pictures until the cache is within
+// maxBytes, keeping the keep most recent ones. c.mu must be held.
+func (c *ImageCache) evict(keep int) {
+	if c.maxBytes <= 0 {
+		return
+	}
+	for c.bytes > c.maxBytes && len(c.entries) > keep {
+		var oldest imageCacheKey
+		var oldestUsed uint64
+		first := true
+		for k, e := range c.entries {
+			if first || e.used < oldestUsed {
+				oldest, oldestUsed, first = k, e.used, false
+			}
+		}
+		c.bytes -= pictureBytes(c.entries[oldest].img)
+		delete(c.entries, oldest)
+	}
+}
+
+func pictureBytes(img *image.RGBA) int64 {
+	if img == nil {
+		return 0
+	}
+	return int64(len(img.Pix))
+}
+
+// decodePicture decodes data as an RGBA image, extracting the bitmap of
+// WMF and EMF metafiles. It returns nil when data cannot be decoded.
+func decodePicture(data []byte, fc *FontCache) *image.RGBA {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		if src = decodeMetafileBitmap(data, fc); src == nil {
+			return nil
+		}
+	}
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// needsReduction reports whether img is more than twice as large as w×h in
+// both directions.
+func needsReduction(img *image.RGBA, w, h int) bool {
+	return img.Rect.Dx() > 2*w && img.Rect.Dy() > 2*h
+}
+
+// reducePicture returns img shrunk by a whole factor, averaging each block
+// of pixels, to no less than twice w×h, or img itself when it is not that
+// large.
+func reducePicture(img *image.RGBA, w, h int) *image.RGBA {
+	if w <= 0 || h <= 0 || !needsReduction(img, w, h) {
+		return img
+	}
+	f := min(img.Rect.Dx()/(2*w), img.Rect.Dy()/(2*h))
+	dw, dh := img.Rect.Dx()/f, img.Rect.Dy()/f
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	n := uint32(f * f)
+	for y := range dh {
+		for x := range dw {
+			var sum [4]uint32
+			for sy := y * f; sy < (y+1)*f; sy++ {
+				off := sy*img.Stride + x*f*4
+				for range f {
+					sum[0] += uint32(img.Pix[off])
+					sum[1] += uint32(img.Pix[off+1])
+					sum[2] += uint32(img.Pix[off+2])
+					sum[3] += uint32(img.Pix[off+3])
+					off += 4
+				}
+			}
+			o := y*dst.Stride + x*4
+			for ch := range 4 {
+				dst.Pix[o+ch] = uint8((sum[ch] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,110 @@
+package gopresentation
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"os"
+)
+
+// CropStrategy chooses the region of a picture to keep when it fills a
+// frame of a different shape, for example around a face found by a
+// detector. It receives the decoded image and the frame's aspect ratio
+// (width / height) and returns the region to keep in image coordinates.
+// The region should have that aspect ratio; it is clipped to the image.
+type CropStrategy func(img image.Image, aspect float64) image.Rectangle
+
+// CenterCrop is the CropStrategy keeping the largest centered region.
+func CenterCrop(img image.Image, aspect float64) image.Rectangle {
+	b := img.Bounds()
+	return cropAround(b, aspect, float64(b.Min.X+b.Max.X)/2, float64(b.Min.Y+b.Max.Y)/2)
+}
+
+// CropAround returns a CropStrategy keeping the largest region centered as
+// closely as the image allows on the point (x, y) in image coordinates.
+func CropAround(x, y int) CropStrategy {
+	return func(img image.Image, aspect float64) image.Rectangle {
+		return cropAround(img.Bounds(), aspect, float64(x), float64(y))
+	}
+}
+
+// cropAround returns the largest region of b with the given aspect ratio
+// whose center is as close as possible to (cx, cy).
+func cropAround(b image.Rectangle, aspect, cx, cy float64) image.Rectangle {
+	w, h := float64(b.Dx()), float64(b.Dy())
+	if aspect <= 0 || w <= 0 || h <= 0 {
+		return b
+	}
+	if w/h > aspect {
+		w = h * aspect
+	} else {
+		h = w / aspect
+	}
+	x0 := math.Max(float64(b.Min.X), math.Min(cx-w/2, float64(b.Max.X)-w))
+	y0 := math.Max(float64(b.Min.Y), math.Min(cy-h/2, float64(b.Max.Y)-h))
+	return image.Rect(int(math.Round(x0)), int(math.Round(y0)),
+		int(math.Round(x0+w)), int(math.Round(y0+h)))
+}
+
+// FitWithin places the picture in rect (in EMU) without distorting it.
+// With a nil crop the whole picture is scaled to fit inside rect and
+// centered in it. Otherwise the picture fills rect exactly and crop chooses
+// the region of the image that is kept, which is stored as the picture's
+// crop so the full image remains in the file.
+func (d *DrawingShape) FitWithin(rect Rect, crop CropStrategy) error {
+	if rect.Empty() {
+		return fmt.Errorf("fit picture: empty target %dx%d", rect.Width, rect.Height)
+	}
+	data := d.data
+	if len(data) == 0 && d.path != "" {
+		var err error
+		if data, err = os.ReadFile(d.path); err != nil {
+			return fmt.Errorf("fit picture: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("fit picture: no image data")
+	}
+
+	if crop == nil {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("fit picture: %w", err)
+		}
+		if cfg.Width <= 0 || cfg.Height <= 0 {
+			return fmt.Errorf("fit picture: empty image")
+		}
+		scale := math.Min(float64(rect.Width)/float64(cfg.Width), float64(rect.Height)/float64(cfg.Height))
+		w := int64(math.Round(float64(cfg.Width) * scale))
+		h := int64(math.Round(float64(cfg.Height) * scale))
+		d.offsetX = rect.X + (rect.Width-w)/2
+		d.offsetY = rect.Y + (rect.Height-h)/2
+		d.width, d.height = w, h
+		d.SetCrop(0, 0, 0, 0)
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("fit picture: %w", err)
+	}
+	b := img.Bounds()
+	keep := crop(img, float64(rect.Width)/float64(rect.Height)).Intersect(b)
+	if keep.Empty() {
+		return fmt.Errorf("fit picture: crop region %v outside image %v", keep, b)
+	}
+	// srcRect values are in 1/1000 of a percent of the image size
+	pct := func(n, total int) int {
+		return int(math.Round(float64(n) * 100000 / float64(total)))
+	}
+	d.SetCrop(
+		pct(keep.Min.X-b.Min.X, b.Dx()),
+		pct(keep.Min.Y-b.Min.Y, b.Dy()),
+		pct(b.Max.X-keep.Max.X, b.Dx()),
+		pct(b.Max.Y-keep.Max.Y, b.Dy()),
+	)
+	d.offsetX, d.offsetY = rect.X, rect.Y
+	d.width, d.height = rect.Width, rect.Height
+	return nil
+}
@@ -0,0 +1,102 @@
+package gopresentation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// numberLocale holds the symbols and digits numbers are shown with in a
+// locale.
+type numberLocale struct {
+	decimal rune // decimal separator
+	group   rune // thousands separator
+	zero    rune // zero of the native digits; 0 for ASCII digits
+}
+
+// Number symbols shared by many locales.
+var (
+	pointComma      = numberLocale{decimal: '.', group: ','}
+	commaPoint      = numberLocale{decimal: ',', group: '.'}
+	commaSpace      = numberLocale{decimal: ',', group: '\u00a0'}
+	pointApostrophe = numberLocale{decimal: '.', group: '’'}
+)
+
+// numberLocales maps lowercase language and language-region tags to their
+// number symbols. A region tag is tried before its language.
+var numberLocales = map[string]numberLocale{
+	"en": pointComma, "ja": pointComma, "ko": pointComma, "zh": pointComma,
+	"he": pointComma, "hi": pointComma, "th": pointComma, "ur": pointComma,
+	"es-mx": pointComma, "es-us": pointComma,
+
+	"de": commaPoint, "es": commaPoint, "it": commaPoint, "nl": commaPoint,
+	"pt": commaPoint, "id": commaPoint, "tr": commaPoint, "da": commaPoint,
+	"el": commaPoint, "ro": commaPoint, "vi": commaPoint,
+	"ar-ma": commaPoint, "ar-dz": commaPoint, "ar-tn": commaPoint,
+
+	"ru": commaSpace, "uk": commaSpace, "pl": commaSpace, "cs": commaSpace,
+	"sk": commaSpace, "sv": commaSpace, "fi": commaSpace, "nb": commaSpace,
+	"no": commaSpace, "hu": commaSpace, "bg": commaSpace, "pt-pt": commaSpace,
+	"fr": {decimal: ',', group: '\u202f'},
+
+	"de-ch": pointApostrophe, "fr-ch": pointApostrophe, "it-ch": pointApostrophe,
+
+	// Arabic-Indic and Persian digits
+	"ar": {decimal: '٫', group: '٬', zero: '٠'},
+	"fa": {decimal: '٫', group: '٬', zero: '۰'},
+}
+
+// lookupNumberLocale returns the number symbols of a BCP 47 language tag
+// such as "de-DE" or "ar_EG", or nil when the tag is empty, unknown or
+// shows numbers as the format codes write them.
+func lookupNumberLocale(tag string) *numberLocale {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" {
+		return nil
+	}
+	lang, rest, _ := strings.Cut(tag, "-")
+	// Skip a script subtag, as in "zh-Hant-TW"
+	if script, region, ok := strings.Cut(rest, "-"); ok && len(script) == 4 {
+		rest = region
+	}
+	loc, ok := numberLocales[lang+"-"+rest]
+	if !ok {
+		loc, ok = numberLocales[lang]
+	}
+	if !ok || loc == pointComma {
+		return nil
+	}
+	return &loc
+}
+
+// localize replaces the decimal point, thousands separators and ASCII
+// digits of s, a number formatted without a locale, with those of l.
+func (l *numberLocale) localize(s string) string {
+	if l == nil {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '.':
+			return l.decimal
+		case r == ',':
+			return l.group
+		case r >= '0' && r <= '9' && l.zero != 0:
+			return l.zero + r - '0'
+		}
+		return r
+	}, s)
+}
+
+// hasNonZeroDigit reports whether s has a digit other than zero, ASCII or
+// in the native digits of l.
+func (l *numberLocale) hasNonZeroDigit(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsDigit(r) && r != '0' && (l == nil || r != l.zero)
+	}) >= 0
+}
+
+// formatNumber formats v with an Excel number format code, as FormatNumber
+// does, in the locale of the render.
+func (r *renderer) formatNumber(v float64, code string) string {
+	return formatNumber(v, code, r.locale)
+}
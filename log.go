@@ -0,0 +1,92 @@
+package gopresentation
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Readers, writers and renders log to the *slog.Logger of their options,
+// if any: at debug level the parts written and how long each stage took,
+// at info level a summary of each read, write and slide render and the
+// fonts text falls back to, and at warn level content the reader skips,
+// which is lost when the presentation is written back.
+
+// loggerOr returns l, or a logger discarding everything when l is nil.
+func loggerOr(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return l
+}
+
+// stageTimer logs how long the stages of a read, write or render take.
+// A nil stageTimer logs nothing.
+type stageTimer struct {
+	log         *slog.Logger
+	args        []any
+	start, last time.Time
+}
+
+// newStageTimer starts timing on log; args are added to every record.
+func newStageTimer(log *slog.Logger, args ...any) *stageTimer {
+	now := time.Now()
+	return &stageTimer{log: log, args: args, start: now, last: now}
+}
+
+// stage logs at debug level that the stage name ended and how long it
+// took.
+func (t *stageTimer) stage(name string) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.log.Debug("stage done", append([]any{"stage", name, "duration", now.Sub(t.last)}, t.args...)...)
+	t.last = now
+}
+
+// done logs msg at info level with args and the time since the timer
+// started.
+func (t *stageTimer) done(msg string, args ...any) {
+	if t == nil {
+		return
+	}
+	args = append(args, t.args...)
+	t.log.Info(msg, append(args, "duration", time.Since(t.start))...)
+}
+
+// renderLog is the logging of a slide render, shared by the renderers
+// drawing parts of it. A nil renderLog logs nothing.
+type renderLog struct {
+	log   *slog.Logger
+	slide int
+	// fallbacks records the fonts already reported as unavailable.
+	fallbacks map[string]bool
+}
+
+// newRenderLog returns the logging of the render of slide slideIndex to l,
+// or nil when l is nil.
+func newRenderLog(l *slog.Logger, slideIndex int) *renderLog {
+	if l == nil {
+		return nil
+	}
+	return &renderLog{log: l, slide: slideIndex, fallbacks: make(map[string]bool)}
+}
+
+// timer returns a stageTimer for the render.
+func (l *renderLog) timer() *stageTimer {
+	if l == nil {
+		return nil
+	}
+	return newStageTimer(l.log, "slide", l.slide)
+}
+
+// fontFallback logs, once per font, that text in the font name is drawn
+// with the font used instead.
+func (l *renderLog) fontFallback(name, used string) {
+	if l == nil || l.fallbacks[strings.ToLower(name)] {
+		return
+	}
+	l.fallbacks[strings.ToLower(name)] = true
+	l.log.Info("font not available", "font", name, "fallback", used, "slide", l.slide)
+}
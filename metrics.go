@@ -0,0 +1,42 @@
+package gopresentation
+
+import "time"
+
+// Metrics receives the counters and timings of reads, writes and renders,
+// for wiring to a metrics system such as Prometheus. Set it in the
+// ReaderOptions, WriterOptions or RenderOptions of the calls to measure.
+// Implementations must be safe for concurrent use: slides are read and
+// may be rendered on several goroutines.
+type Metrics interface {
+	// Count adds delta to the counter name.
+	Count(name string, delta int64)
+	// Observe records d for the timer name.
+	Observe(name string, d time.Duration)
+}
+
+// Names of the counters and timers reported to Metrics.
+const (
+	MetricSlidesRead     = "slides_read"     // counter: slides read
+	MetricReadDuration   = "read"            // timer: each read of a package
+	MetricPartsWritten   = "parts_written"   // counter: parts written
+	MetricBytesWritten   = "bytes_written"   // counter: bytes of the parts written, before compression
+	MetricWriteDuration  = "write"           // timer: each write of a package
+	MetricImagesDecoded  = "images_decoded"  // counter: pictures decoded while rendering
+	MetricSlidesRendered = "slides_rendered" // counter: slides rendered
+	MetricSlideRender    = "slide_render"    // timer: each slide render
+)
+
+// countMetric adds delta to the counter name of m, if any.
+func countMetric(m Metrics, name string, delta int64) {
+	if m != nil {
+		m.Count(name, delta)
+	}
+}
+
+// observeMetric records the time since start for the timer name of m, if
+// any.
+func observeMetric(m Metrics, name string, start time.Time) {
+	if m != nil {
+		m.Observe(name, time.Since(start))
+	}
+}
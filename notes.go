@@ -0,0 +1,366 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Speaker notes are the paragraphs of the body placeholder of a slide's
+// notes slide. A notes slide read from a file is written back as it was,
+// with its other placeholders and its formatting, for as long as its notes
+// are unchanged; otherwise it is written anew from the paragraphs.
+
+// ctNotesMaster is the content type of the notes master part.
+const ctNotesMaster = "application/vnd.openxmlformats-officedocument.presentationml.notesMaster+xml"
+
+// notesSource is a notes slide as read.
+type notesSource struct {
+	xml []byte
+	// rels are the relationships of the part: to its slide, to the notes
+	// master and to external hyperlinks.
+	rels []xmlRelForRead
+	// slideRelID is the ID of the relationship to its slide.
+	slideRelID string
+	// read is a copy of the notes as read, to tell whether they changed.
+	read []*Paragraph
+}
+
+// unchanged reports whether notes are still the notes src was read with.
+func (src *notesSource) unchanged(notes []*Paragraph) bool {
+	return src != nil && reflect.DeepEqual(src.read, notes)
+}
+
+// notesMasterSource is the notes master read with a presentation, and the
+// theme it uses.
+type notesMasterSource struct {
+	xml        []byte
+	theme      []byte
+	themeRelID string
+}
+
+// GetNotes returns the text of the slide notes, one line per paragraph.
+func (s *Slide) GetNotes() string {
+	lines := make([]string, len(s.notes))
+	for i, para := range s.notes {
+		var sb strings.Builder
+		for _, elem := range para.elements {
+			switch e := elem.(type) {
+			case *TextRun:
+				sb.WriteString(e.text)
+			case *BreakElement:
+				sb.WriteByte('\n')
+			}
+		}
+		lines[i] = sb.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetNotes sets the slide notes to plain text, a paragraph per line,
+// formatted by the notes master. Empty text removes the notes.
+func (s *Slide) SetNotes(notes string) {
+	s.notes = nil
+	if notes == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(notes, "\r\n", "\n"), "\n") {
+		para := NewParagraph()
+		para.CreateTextRun(line)
+		s.notes = append(s.notes, para)
+	}
+}
+
+// HasNotes reports whether the slide has notes with text.
+func (s *Slide) HasNotes() bool {
+	return s.GetNotes() != ""
+}
+
+// GetNotesParagraphs returns the paragraphs of the slide notes, with their
+// formatting. Changes to them are saved. Runs whose font is unchanged from
+// NewFont are formatted by the notes master.
+func (s *Slide) GetNotesParagraphs() []*Paragraph {
+	return s.notes
+}
+
+// CreateNotesParagraph appends a paragraph to the slide notes.
+func (s *Slide) CreateNotesParagraph() *Paragraph {
+	para := NewParagraph()
+	s.notes = append(s.notes, para)
+	return para
+}
+
+// SetNotesParagraphs replaces the slide notes with paras.
+func (s *Slide) SetNotesParagraphs(paras ...*Paragraph) {
+	s.notes = append([]*Paragraph(nil), paras...)
+}
+
+// cloneParagraphs returns deep copies of paras, or nil.
+func cloneParagraphs(paras []*Paragraph) []*Paragraph {
+	if paras == nil {
+		return nil
+	}
+	c := make([]*Paragraph, len(paras))
+	for i, para := range paras {
+		c[i] = para.Clone()
+	}
+	return c
+}
+
+// readNotesSlide reads the notes of the slide at slidePath from the notes
+// slide at notesPath. The part is kept to be written back when its
+// relationships can be carried over to the written package.
+func (r *PPTXReader) readNotesSlide(zr *zip.Reader, slide *Slide, notesPath, slidePath string, pres *Presentation) {
+	data, err := readFileFromZip(zr, notesPath)
+	if err != nil {
+		return
+	}
+	dir := path.Dir(notesPath)
+	rels, _ := r.readRelationships(zr, dir+"/_rels/"+path.Base(notesPath)+".rels")
+
+	notes := newSlide()
+	if r.parseSlideXML(xml.NewDecoder(bytes.NewReader(data)), data, notes, rels, zr, notesPath, pres, nil) == nil {
+		if body := notes.GetPlaceholder(PlaceholderBody); body != nil {
+			slide.notes = body.paragraphs
+		}
+	}
+	if slide.notes == nil {
+		slide.SetNotes(r.parseNotesXML(data))
+	}
+	// Runs without a size of their own are read in the 18 pt default of
+	// slides. Give those without other formatting the default font, so that
+	// the notes master keeps formatting them, and the others the 12 pt of
+	// notes text.
+	for _, para := range slide.notes {
+		for _, elem := range para.elements {
+			if tr, ok := elem.(*TextRun); ok && tr.font != nil && tr.font.Size == 18 {
+				tr.font.Size = NewFont().Size
+				if !isDefaultFont(tr.font) {
+					tr.font.Size = 12
+				}
+			}
+		}
+	}
+
+	src := &notesSource{xml: data}
+	for _, rel := range rels {
+		switch {
+		case rel.TargetMode == "External", rel.Type == relTypeNotesMaster:
+		case rel.Type == relTypeSlide && resolveRelativePath(dir, rel.Target) == slidePath && src.slideRelID == "":
+			src.slideRelID = rel.ID
+		default:
+			// Pictures and links to other slides would need their targets
+			// written and renumbered too
+			return
+		}
+		src.rels = append(src.rels, rel)
+	}
+	slide.notesSource = src
+}
+
+// readNotesMaster keeps the notes master of the presentation and its theme
+// to write them back. A master with relationships other than its theme,
+// or whose theme has relationships, is not kept.
+func (r *PPTXReader) readNotesMaster(zr *zip.Reader, presRels []xmlRelForRead, pres *Presentation) {
+	for _, rel := range presRels {
+		if rel.Type != relTypeNotesMaster || rel.TargetMode == "External" {
+			continue
+		}
+		masterPath := resolveRelativePath("ppt", rel.Target)
+		data, err := readFileFromZip(zr, masterPath)
+		if err != nil {
+			return
+		}
+		dir := path.Dir(masterPath)
+		rels, _ := r.readRelationships(zr, dir+"/_rels/"+path.Base(masterPath)+".rels")
+		src := &notesMasterSource{xml: data}
+		for _, mrel := range rels {
+			if mrel.Type != relTypeTheme || mrel.TargetMode == "External" || src.theme != nil {
+				return
+			}
+			themePath := resolveRelativePath(dir, mrel.Target)
+			theme, err := readFileFromZip(zr, themePath)
+			if err != nil {
+				return
+			}
+			if themeRels, _ := r.readRelationships(zr, path.Dir(themePath)+"/_rels/"+path.Base(themePath)+".rels"); len(themeRels) > 0 {
+				return
+			}
+			src.theme, src.themeRelID = theme, mrel.ID
+		}
+		if src.theme != nil {
+			pres.notesMaster = src
+		}
+		return
+	}
+}
+
+// hasNotes reports whether a slide of the presentation has notes.
+func (p *Presentation) hasNotes() bool {
+	for _, slide := range p.slides {
+		if slide.HasNotes() {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNotesMaster writes the notes master and its theme: the ones read
+// with the presentation, or defaults.
+func (w *PPTXWriter) writeNotesMaster(zw *zip.Writer) error {
+	var content, theme []byte
+	themeID := "rId1"
+	if src := w.presentation.notesMaster; src != nil {
+		content, theme, themeID = src.xml, src.theme, src.themeRelID
+	} else {
+		content, theme = []byte(w.defaultNotesMasterXML()), []byte(w.themeXML())
+	}
+	if err := w.writePartBytes(zw, "ppt/notesMasters/notesMaster1.xml", ctNotesMaster, content); err != nil {
+		return err
+	}
+	rels := newRelRegistry()
+	if err := rels.reserve(relTypeTheme, themeID, relTypeTheme, "../theme/theme2.xml"); err != nil {
+		return err
+	}
+	if err := w.writeRels(zw, "ppt/notesMasters/_rels/notesMaster1.xml.rels", rels); err != nil {
+		return err
+	}
+	return w.writePartBytes(zw, "ppt/theme/theme2.xml", ctTheme, theme)
+}
+
+// defaultNotesMasterXML returns a notes master with the slide image above
+// the notes on a portrait page, and 12 pt notes text.
+func (w *PPTXWriter) defaultNotesMasterXML() string {
+	layout := w.presentation.layout
+	// Notes pages are the slide size turned to portrait
+	pageW, pageH := layout.CY, layout.CX
+	imgW := pageW * 8 / 10
+	imgH := imgW * layout.CY / layout.CX
+	if maxH := pageH * 4 / 10; imgH > maxH {
+		imgW, imgH = maxH*layout.CX/layout.CY, maxH
+	}
+	imgX, imgY := (pageW-imgW)/2, pageH/15
+	bodyX, bodyY := pageW/10, imgY+imgH+pageH/30
+	bodyW, bodyH := pageW*8/10, pageH*9/10-bodyY
+
+	var levels strings.Builder
+	for i := 1; i <= 9; i++ {
+		fmt.Fprintf(&levels, `    <a:lvl%dpPr marL="%d" algn="l" defTabSz="914400" rtl="0" eaLnBrk="1" latinLnBrk="0" hangingPunct="1">
+      <a:defRPr sz="1200" kern="1200"><a:solidFill><a:schemeClr val="tx1"/></a:solidFill><a:latin typeface="+mn-lt"/><a:ea typeface="+mn-ea"/><a:cs typeface="+mn-cs"/></a:defRPr>
+    </a:lvl%dpPr>
+`, i, (i-1)*457200, i)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:notesMaster xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
+  <p:cSld>
+    <p:bg>
+      <p:bgRef idx="1001">
+        <a:schemeClr val="bg1"/>
+      </p:bgRef>
+    </p:bg>
+    <p:spTree>
+      <p:nvGrpSpPr>
+        <p:cNvPr id="1" name=""/>
+        <p:cNvGrpSpPr/>
+        <p:nvPr/>
+      </p:nvGrpSpPr>
+      <p:grpSpPr>
+        <a:xfrm>
+          <a:off x="0" y="0"/>
+          <a:ext cx="0" cy="0"/>
+          <a:chOff x="0" y="0"/>
+          <a:chExt cx="0" cy="0"/>
+        </a:xfrm>
+      </p:grpSpPr>
+      <p:sp>
+        <p:nvSpPr>
+          <p:cNvPr id="2" name="Slide Image Placeholder 1"/>
+          <p:cNvSpPr>
+            <a:spLocks noGrp="1" noRot="1" noChangeAspect="1"/>
+          </p:cNvSpPr>
+          <p:nvPr>
+            <p:ph type="sldImg" idx="2"/>
+          </p:nvPr>
+        </p:nvSpPr>
+        <p:spPr>
+          <a:xfrm>
+            <a:off x="%d" y="%d"/>
+            <a:ext cx="%d" cy="%d"/>
+          </a:xfrm>
+          <a:prstGeom prst="rect">
+            <a:avLst/>
+          </a:prstGeom>
+          <a:noFill/>
+          <a:ln w="12700">
+            <a:solidFill>
+              <a:prstClr val="black"/>
+            </a:solidFill>
+          </a:ln>
+        </p:spPr>
+      </p:sp>
+      <p:sp>
+        <p:nvSpPr>
+          <p:cNvPr id="3" name="Notes Placeholder 2"/>
+          <p:cNvSpPr>
+            <a:spLocks noGrp="1"/>
+          </p:cNvSpPr>
+          <p:nvPr>
+            <p:ph type="body" sz="quarter" idx="3"/>
+          </p:nvPr>
+        </p:nvSpPr>
+        <p:spPr>
+          <a:xfrm>
+            <a:off x="%d" y="%d"/>
+            <a:ext cx="%d" cy="%d"/>
+          </a:xfrm>
+          <a:prstGeom prst="rect">
+            <a:avLst/>
+          </a:prstGeom>
+        </p:spPr>
+        <p:txBody>
+          <a:bodyPr vert="horz" lIns="91440" tIns="45720" rIns="91440" bIns="45720" rtlCol="0"/>
+          <a:lstStyle/>
+          <a:p>
+            <a:pPr lvl="0"/>
+            <a:r>
+              <a:rPr lang="en-US"/>
+              <a:t>Click to edit Master text styles</a:t>
+            </a:r>
+          </a:p>
+        </p:txBody>
+      </p:sp>
+    </p:spTree>
+  </p:cSld>
+  <p:clrMap bg1="lt1" tx1="dk1" bg2="lt2" tx2="dk2" accent1="accent1" accent2="accent2" accent3="accent3" accent4="accent4" accent5="accent5" accent6="accent6" hlink="hlink" folHlink="folHlink"/>
+  <p:notesStyle>
+%s  </p:notesStyle>
+</p:notesMaster>`, nsDrawingML, nsOfficeDocRels, nsPresentationML,
+		imgX, imgY, imgW, imgH,
+		bodyX, bodyY, bodyW, bodyH,
+		levels.String())
+}
+
+// appendNotesRunXML writes the a:r element for tr, a run of the notes, to
+// sb. Runs in the default font are left to the notes master to format.
+func (w *PPTXWriter) appendNotesRunXML(sb *strings.Builder, tr *TextRun) {
+	if tr.font != nil && !isDefaultFont(tr.font) {
+		w.appendTextRunXML(sb, tr)
+		return
+	}
+	sb.WriteString("            <a:r>\n              <a:rPr lang=\"en-US\" dirty=\"0\"")
+	if w.slideRels.id(tr) != "" && w.hasHyperlinkRel(tr.hyperlink) {
+		sb.WriteByte('>')
+		w.appendHyperlinkClickXML(sb, tr)
+		sb.WriteString("\n              </a:rPr>")
+	} else {
+		sb.WriteString("/>")
+	}
+	sb.WriteString("\n              <a:t>")
+	writeXMLEscaped(sb, w.normalizeText(tr.text))
+	sb.WriteString("</a:t>\n            </a:r>\n")
+}
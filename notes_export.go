@@ -0,0 +1,58 @@
+package gopresentation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NotesFormat selects the output format of ExportNotes.
+type NotesFormat int
+
+const (
+	// NotesFormatText writes plain text with a "Slide N: Title" line per slide.
+	NotesFormatText NotesFormat = iota
+	// NotesFormatMarkdown writes a level-2 heading per slide and the notes
+	// as Markdown paragraphs.
+	NotesFormatMarkdown
+)
+
+// ExportNotes writes the speaker notes of every slide to w, each preceded by
+// the slide number and title, for use as a talk script. Slides without notes
+// are listed with their heading only; hidden slides are marked as hidden.
+func (p *Presentation) ExportNotes(w io.Writer, format NotesFormat) error {
+	if format != NotesFormatText && format != NotesFormatMarkdown {
+		return fmt.Errorf("unsupported notes format: %d", format)
+	}
+	bw := bufio.NewWriter(w)
+	for i, slide := range p.slides {
+		heading := fmt.Sprintf("Slide %d", i+1)
+		if title := slide.GetTitle(); title != "" {
+			heading += ": " + title
+		}
+		if !slide.visible {
+			heading += " (hidden)"
+		}
+		notes := strings.TrimSpace(strings.ReplaceAll(slide.GetNotes(), "\r\n", "\n"))
+
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		switch format {
+		case NotesFormatText:
+			bw.WriteString(heading + "\n")
+			if notes != "" {
+				bw.WriteString(notes + "\n")
+			}
+		case NotesFormatMarkdown:
+			bw.WriteString("## " + heading + "\n")
+			for _, para := range strings.Split(notes, "\n") {
+				if para = strings.TrimSpace(para); para != "" {
+					bw.WriteString("\n" + para + "\n")
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
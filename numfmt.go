@@ -0,0 +1,452 @@
+package gopresentation
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatNumber formats v with an Excel number format code the way
+// PowerPoint displays chart data labels and table numbers. Supported are
+// "General", the digit placeholders 0, # and ?, the decimal point,
+// thousands separators and trailing-comma scaling, percent, scientific
+// notation (E+00), fractions (# ?/?, # ??/16), the text placeholder @,
+// which shows the number as General does, quoted and escaped literals,
+// currency symbols including [$€-407] and positive;negative;zero sections.
+// Colors and conditions in brackets are ignored. Date and time codes are
+// not supported. NaN and infinities are shown as Excel's #NUM! error.
+func FormatNumber(v float64, code string) string {
+	return formatNumber(v, code, nil)
+}
+
+// formatNumber implements FormatNumber, showing the number with the
+// symbols and digits of loc; nil uses those of the format code.
+func formatNumber(v float64, code string, loc *numberLocale) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "#NUM!"
+	}
+	if code == "" || strings.EqualFold(code, "General") {
+		return loc.localize(formatGeneral(v))
+	}
+	sections := splitFormatSections(code)
+	section := sections[0]
+	neg := false
+	switch {
+	case v < 0 && len(sections) >= 2:
+		// The negative section carries its own sign, e.g. (#,##0)
+		section = sections[1]
+		v = -v
+	case v == 0 && len(sections) >= 3:
+		section = sections[2]
+	case v < 0:
+		neg = true
+		v = -v
+	}
+	s := formatSection(v, section, loc)
+	if neg && loc.hasNonZeroDigit(s) {
+		s = "-" + s
+	}
+	return s
+}
+
+// formatGeneral formats v like Excel's General format in a cell of the
+// standard width: integers below 1e11 in full, other numbers rounded to
+// fit 11 characters, switching to scientific notation for very large or
+// very small magnitudes.
+func formatGeneral(v float64) string {
+	if v == 0 {
+		return "0"
+	}
+	abs := math.Abs(v)
+	if abs >= 1e11 || abs < 1e-9 {
+		s := strconv.FormatFloat(v, 'E', 5, 64)
+		mant, exp, _ := strings.Cut(s, "E")
+		if strings.Contains(mant, ".") {
+			mant = strings.TrimRight(strings.TrimRight(mant, "0"), ".")
+		}
+		return mant + "E" + exp
+	}
+	if abs == math.Trunc(abs) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	// The digits of the integer part and the decimal point take up the
+	// width left to the decimals.
+	intLen := len(strconv.FormatFloat(math.Trunc(abs), 'f', 0, 64))
+	s := roundDecimal(abs, max(10-intLen, 0))
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if v < 0 && s != "0" {
+		s = "-" + s
+	}
+	return s
+}
+
+// splitFormatSections splits a format code at the semicolons that are not
+// quoted or escaped.
+func splitFormatSections(code string) []string {
+	var sections []string
+	start, quoted := 0, false
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			i++
+		case ';':
+			if !quoted {
+				sections = append(sections, code[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(sections, code[start:])
+}
+
+// numberPattern is a parsed format code section.
+type numberPattern struct {
+	prefix, suffix strings.Builder
+	intDigits      []byte // 0, # and ? placeholders before the decimal point
+	fracDigits     []byte // placeholders after the decimal point
+	expDigits      []byte // placeholders of the exponent
+	hasPoint       bool
+	grouping       bool // thousands separators
+	scale          int  // trailing commas, each dividing by 1000
+	percent        int  // percent signs, each multiplying by 100
+	expSign        bool // E+ rather than E-
+	scientific     bool
+	general        bool
+
+	// Fractions such as # ?/? keep the placeholders of the whole number
+	// in wholeDigits, empty for improper fractions (?/?), and those of the
+	// numerator and denominator in numDigits and denDigits. A fixed
+	// denominator (# ?/8) is in denominator.
+	fraction    bool
+	wholeDigits []byte
+	numDigits   []byte
+	denDigits   []byte
+	denominator int
+}
+
+// parseNumberPattern parses one section of a format code.
+func parseNumberPattern(section string) *numberPattern {
+	p := &numberPattern{}
+	inNumber, afterNumber := false, false
+	lit := func() *strings.Builder {
+		if inNumber || afterNumber {
+			afterNumber = true
+			inNumber = false
+			return &p.suffix
+		}
+		return &p.prefix
+	}
+	pendingCommas := 0
+	runes := []rune(section)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			lit().WriteString(string(runes[i+1 : min(j, len(runes))]))
+			i = j
+		case c == '\\' && i+1 < len(runes):
+			lit().WriteRune(runes[i+1])
+			i++
+		case c == '_' && i+1 < len(runes):
+			lit().WriteByte(' ')
+			i++
+		case c == '*' && i+1 < len(runes):
+			i++
+		case c == '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			inner := string(runes[i+1 : min(j, len(runes))])
+			if sym, ok := strings.CutPrefix(inner, "$"); ok {
+				sym, _, _ = strings.Cut(sym, "-")
+				lit().WriteString(sym)
+			}
+			i = j
+		case c == ' ' && inNumber && !p.hasPoint && !p.scientific && !p.fraction &&
+			len(p.wholeDigits) == 0 && fractionAhead(runes[i+1:]):
+			// The space between the whole number and the fraction
+			p.wholeDigits, p.intDigits = p.intDigits, nil
+		case c == '/' && inNumber && !p.hasPoint && !p.scientific && !p.fraction:
+			p.fraction = true
+			p.numDigits, p.intDigits = p.intDigits, nil
+		case p.fraction && inNumber && len(p.denDigits) == 0 && p.denominator == 0 && c >= '1' && c <= '9':
+			j := i
+			for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+				j++
+			}
+			p.denominator, _ = strconv.Atoi(string(runes[i:j]))
+			i = j - 1
+		case (c == '0' || c == '#' || c == '?') && !afterNumber:
+			inNumber = true
+			if pendingCommas > 0 {
+				p.grouping = true
+				pendingCommas = 0
+			}
+			switch {
+			case p.fraction:
+				p.denDigits = append(p.denDigits, byte(c))
+			case p.scientific:
+				p.expDigits = append(p.expDigits, byte(c))
+			case p.hasPoint:
+				p.fracDigits = append(p.fracDigits, byte(c))
+			default:
+				p.intDigits = append(p.intDigits, byte(c))
+			}
+		case c == '.' && !afterNumber && !p.hasPoint && !p.scientific &&
+			(inNumber || (i+1 < len(runes) && strings.ContainsRune("0#?", runes[i+1]))):
+			inNumber = true
+			p.hasPoint = true
+			p.scale += pendingCommas
+			pendingCommas = 0
+		case c == ',' && inNumber:
+			pendingCommas++
+		case (c == 'E' || c == 'e') && inNumber && !p.scientific && i+1 < len(runes) &&
+			(runes[i+1] == '+' || runes[i+1] == '-'):
+			p.scientific = true
+			p.expSign = runes[i+1] == '+'
+			p.scale += pendingCommas
+			pendingCommas = 0
+			i++
+		case c == '%':
+			p.percent++
+			p.scale += pendingCommas
+			pendingCommas = 0
+			lit().WriteRune(c)
+		case c == '@' && !p.general:
+			// The text placeholder shows the number as text, which is
+			// what General shows
+			p.general = true
+			inNumber = true
+		case (c == 'G' || c == 'g') && strings.EqualFold(string(runes[i:min(i+7, len(runes))]), "General"):
+			p.general = true
+			inNumber = true
+			i += 6
+		default:
+			p.scale += pendingCommas
+			pendingCommas = 0
+			lit().WriteRune(c)
+		}
+	}
+	p.scale += pendingCommas
+	return p
+}
+
+// formatSection formats a non-negative v with one format code section,
+// in the symbols and digits of loc.
+func formatSection(v float64, section string, loc *numberLocale) string {
+	p := parseNumberPattern(section)
+	for i := 0; i < p.percent; i++ {
+		v *= 100
+	}
+	for i := 0; i < p.scale; i++ {
+		v /= 1000
+	}
+	var body string
+	switch {
+	case p.general:
+		body = formatGeneral(v)
+	case p.fraction:
+		body = p.formatFraction(v)
+	case len(p.intDigits) == 0 && len(p.fracDigits) == 0 && !p.hasPoint:
+		// Literal-only section such as "-" for zero
+	case p.scientific:
+		body = p.formatScientific(v)
+	default:
+		body = p.formatFixed(v)
+	}
+	return p.prefix.String() + loc.localize(body) + p.suffix.String()
+}
+
+// formatFixed formats v with the integer and fraction placeholders.
+func (p *numberPattern) formatFixed(v float64) string {
+	s := roundDecimal(v, len(p.fracDigits))
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	intStr := formatIntDigits(intPart, p.intDigits, p.grouping)
+	if !p.hasPoint {
+		return intStr
+	}
+	return intStr + "." + formatFracDigits(fracPart, p.fracDigits)
+}
+
+// fractionAhead reports whether runes start with the placeholders of a
+// fraction's numerator, as after the space in # ?/?.
+func fractionAhead(runes []rune) bool {
+	n := 0
+	for n < len(runes) && strings.ContainsRune("0#?", runes[n]) {
+		n++
+	}
+	return n > 0 && n < len(runes) && runes[n] == '/'
+}
+
+// formatFraction formats v as a fraction, with the whole number apart
+// when the pattern has placeholders for it. The denominator is the fixed
+// one or the one, with as many digits as its placeholders, that comes
+// closest to v.
+func (p *numberPattern) formatFraction(v float64) string {
+	whole := 0.0
+	if len(p.wholeDigits) > 0 {
+		whole = math.Floor(v)
+		v -= whole
+	}
+	num, den := 0.0, float64(p.denominator)
+	if den > 0 {
+		num = math.Round(v * den)
+	} else {
+		maxDen := math.Pow(10, float64(min(len(p.denDigits), 4))) - 1
+		num, den = math.Round(v), 1
+		for d := 2.0; d <= maxDen; d++ {
+			n := math.Round(v * d)
+			if math.Abs(v-n/d) < math.Abs(v-num/den)-1e-12 {
+				num, den = n, d
+			}
+		}
+	}
+	if len(p.wholeDigits) > 0 && num >= den {
+		// The fraction rounded up to a whole number
+		whole += math.Floor(num / den)
+		num = math.Mod(num, den)
+	}
+	numStr := formatIntDigits(strconv.FormatFloat(num, 'f', 0, 64), p.numDigits, false)
+	denStr := strconv.FormatFloat(den, 'f', 0, 64)
+	if p.denominator == 0 {
+		// Denominators are aligned left, ? placeholders padding them
+		// with spaces
+		if pad := len(p.denDigits) - len(denStr); pad > 0 {
+			denStr += strings.Repeat(" ", pad)
+		}
+	}
+	frac := numStr + "/" + denStr
+	if len(p.wholeDigits) == 0 {
+		return frac
+	}
+	wholeStr := formatIntDigits(strconv.FormatFloat(whole, 'f', 0, 64), p.wholeDigits, p.grouping)
+	if num == 0 {
+		// A whole number shows no fraction, keeping its width with spaces
+		if wholeStr == "" {
+			wholeStr = "0"
+		}
+		return wholeStr + strings.Repeat(" ", len(frac)+1)
+	}
+	return wholeStr + " " + frac
+}
+
+// formatScientific formats v in scientific notation. With more than one
+// integer placeholder the exponent is a multiple of their count
+// (engineering notation, e.g. ##0.0E+0).
+func (p *numberPattern) formatScientific(v float64) string {
+	exp := 0
+	if v != 0 {
+		exp = int(math.Floor(math.Log10(v)))
+		if step := len(p.intDigits); step > 1 && strings.Contains(string(p.intDigits), "#") {
+			exp = int(math.Floor(float64(exp)/float64(step))) * step
+		} else if n := len(p.intDigits); n > 1 {
+			exp -= n - 1
+		}
+	}
+	mant := v / math.Pow(10, float64(exp))
+	// Rounding may carry the mantissa to the next power of ten
+	if r, _ := strconv.ParseFloat(roundDecimal(mant, len(p.fracDigits)), 64); r >= 10 && len(p.intDigits) <= 1 {
+		exp++
+		mant = v / math.Pow(10, float64(exp))
+	}
+	body := p.formatFixed(mant)
+	sign := ""
+	if exp < 0 {
+		sign = "-"
+		exp = -exp
+	} else if p.expSign {
+		sign = "+"
+	}
+	expStr := strconv.Itoa(exp)
+	for len(expStr) < strings.Count(string(p.expDigits), "0") {
+		expStr = "0" + expStr
+	}
+	return body + "E" + sign + expStr
+}
+
+// roundDecimal formats a non-negative v with the given number of decimals,
+// rounding half away from zero on its shortest decimal representation as
+// Excel does (1.005 rounds to 1.01, 2.5 to 3).
+func roundDecimal(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if len(fracPart) <= decimals {
+		fracPart += strings.Repeat("0", decimals-len(fracPart))
+		if decimals == 0 {
+			return intPart
+		}
+		return intPart + "." + fracPart
+	}
+	roundUp := fracPart[decimals] >= '5'
+	digits := []byte(intPart + fracPart[:decimals])
+	if roundUp {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	n := len(digits) - decimals
+	if decimals == 0 {
+		return string(digits)
+	}
+	return string(digits[:n]) + "." + string(digits[n:])
+}
+
+// formatIntDigits pads digits to the required integer placeholders and
+// inserts thousands separators.
+func formatIntDigits(digits string, pattern []byte, grouping bool) string {
+	required := strings.Count(string(pattern), "0")
+	spaces := strings.Count(string(pattern), "?")
+	if digits == "0" && required == 0 {
+		digits = ""
+	}
+	for len(digits) < required {
+		digits = "0" + digits
+	}
+	if grouping && len(digits) > 3 {
+		var sb strings.Builder
+		for i, d := range digits {
+			if i > 0 && (len(digits)-i)%3 == 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteRune(d)
+		}
+		digits = sb.String()
+	}
+	if pad := required + spaces - len(digits); spaces > 0 && pad > 0 {
+		digits = strings.Repeat(" ", pad) + digits
+	}
+	return digits
+}
+
+// formatFracDigits drops the trailing zeros that fall on optional (#)
+// placeholders and replaces those on ? placeholders with spaces.
+func formatFracDigits(digits string, pattern []byte) string {
+	b := []byte(digits)
+	for i := len(b) - 1; i >= 0 && i < len(pattern) && b[i] == '0'; i-- {
+		switch pattern[i] {
+		case '#':
+			b = b[:i]
+			continue
+		case '?':
+			b[i] = ' '
+			continue
+		}
+		break
+	}
+	return string(b)
+}
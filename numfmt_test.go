@@ -0,0 +1,54 @@
+package gopresentation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		v    float64
+		code string
+		want string
+	}{
+		{1234.5, "General", "1234.5"},
+		{12345678901, "General", "12345678901"},
+		{-12345678901, "", "-12345678901"},
+		{1234.56789012, "General", "1234.56789"},
+		{0.123456789012, "General", "0.123456789"},
+		{123456789012, "General", "1.23457E+11"},
+		{1.5e-10, "General", "1.5E-10"},
+		{0.1 + 0.2, "General", "0.3"},
+
+		{1.5, "@", "1.5"},
+		{-2, "@", "-2"},
+		{1.5, `"Total: "@`, "Total: 1.5"},
+		{1, "0;-0;0;@", "1"},
+
+		{1.5, "# ?/?", "1 1/2"},
+		{0.5, "# ?/?", " 1/2"},
+		{2, "# ?/?", "2    "},
+		{0, "# ?/?", "0    "},
+		{-1.25, "# ?/?", "-1 1/4"},
+		{1.999, "# ?/?", "2    "},
+		{3.14159, "# ??/??", "3 14/99"},
+		{0.333, "# ??/??", "  1/3 "},
+		{1.5, "?/?", "3/2"},
+		{1.3, "# ?/8", "1 2/8"},
+		{2.5, "0 ?/10", "2 5/10"},
+
+		{1234.5, "#,##0.00", "1,234.50"},
+		{-1234.5, "#,##0;(#,##0)", "(1,235)"},
+		{0.256, "0.0%", "25.6%"},
+		{12345, "0.00E+00", "1.23E+04"},
+
+		{math.NaN(), "0.00", "#NUM!"},
+		{math.Inf(1), "General", "#NUM!"},
+		{math.Inf(-1), "# ?/?", "#NUM!"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.v, tt.code); got != tt.want {
+			t.Errorf("FormatNumber(%v, %q) = %q, want %q", tt.v, tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ValidatePackage checks the Open Packaging Conventions structure of the
+// PPTX package in r: that part names are unique, that every part has a
+// content type and every content type override a part, that relationship
+// IDs are unique within each .rels part, and that internal relationship
+// targets exist. It returns the problems found; the error is set only when
+// the package cannot be read at all.
+func ValidatePackage(r io.ReaderAt, size int64) ([]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip: %w", err)
+	}
+	var issues []string
+	parts := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		key := strings.ToLower(f.Name)
+		if _, dup := parts[key]; dup {
+			issues = append(issues, fmt.Sprintf("duplicate part name %s", f.Name))
+		}
+		parts[key] = f
+	}
+
+	// Content types
+	ctFile, ok := parts[strings.ToLower("[Content_Types].xml")]
+	if !ok {
+		return append(issues, "missing [Content_Types].xml"), nil
+	}
+	var types xmlContentTypes
+	if err := decodePackageXML(ctFile, &types); err != nil {
+		return append(issues, fmt.Sprintf("[Content_Types].xml: %v", err)), nil
+	}
+	defaults := make(map[string]bool)
+	for _, d := range types.Defaults {
+		defaults[strings.ToLower(d.Extension)] = true
+	}
+	overrides := make(map[string]bool)
+	for _, o := range types.Overrides {
+		name := strings.ToLower(strings.TrimPrefix(o.PartName, "/"))
+		overrides[name] = true
+		if _, ok := parts[name]; !ok {
+			issues = append(issues, fmt.Sprintf("content type override for missing part %s", o.PartName))
+		}
+	}
+
+	names := make([]string, 0, len(parts))
+	for key := range parts {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		name := parts[key].Name
+		if key == strings.ToLower("[Content_Types].xml") {
+			continue
+		}
+		ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+		if !overrides[key] && !defaults[ext] {
+			issues = append(issues, fmt.Sprintf("part %s has no content type", name))
+		}
+		if strings.HasSuffix(key, ".rels") {
+			issues = append(issues, validateRelsPart(parts[key], parts)...)
+		}
+	}
+
+	// The package must point at its main document
+	hasMain := false
+	if f, ok := parts["_rels/.rels"]; ok {
+		var rels xmlRelationships
+		if decodePackageXML(f, &rels) == nil {
+			for _, rel := range rels.Relationships {
+				if rel.Type == relTypeOfficeDoc {
+					hasMain = true
+				}
+			}
+		}
+	}
+	if !hasMain {
+		issues = append(issues, "package has no officeDocument relationship")
+	}
+	return issues, nil
+}
+
+// validateRelsPart checks the relationships part f against the parts of
+// the package.
+func validateRelsPart(f *zip.File, parts map[string]*zip.File) []string {
+	var issues []string
+	var rels xmlRelationships
+	if err := decodePackageXML(f, &rels); err != nil {
+		return []string{fmt.Sprintf("%s: %v", f.Name, err)}
+	}
+
+	// dir/_rels/name.rels describes dir/name; targets are relative to dir
+	dir, file := path.Split(f.Name)
+	sourceDir := strings.TrimSuffix(strings.TrimSuffix(dir, "/"), "_rels")
+	source := sourceDir + strings.TrimSuffix(file, ".rels")
+	if source != "" {
+		if _, ok := parts[strings.ToLower(source)]; !ok {
+			issues = append(issues, fmt.Sprintf("%s: source part %s does not exist", f.Name, source))
+		}
+	}
+
+	ids := make(map[string]bool)
+	for _, rel := range rels.Relationships {
+		if rel.ID == "" {
+			issues = append(issues, fmt.Sprintf("%s: relationship without an ID", f.Name))
+		} else if ids[rel.ID] {
+			issues = append(issues, fmt.Sprintf("%s: duplicate relationship ID %s", f.Name, rel.ID))
+		}
+		ids[rel.ID] = true
+		if rel.TargetMode == "External" {
+			continue
+		}
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(sourceDir, target)
+		}
+		if _, ok := parts[strings.ToLower(target)]; !ok {
+			issues = append(issues, fmt.Sprintf("%s: %s target %s does not exist", f.Name, rel.ID, rel.Target))
+		}
+	}
+	return issues
+}
+
+// decodePackageXML unmarshals the part f into v.
+func decodePackageXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(io.LimitReader(rc, int64(maxZipEntrySize))).Decode(v)
+}
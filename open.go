@@ -8,8 +8,8 @@ import (
 
 // Open reads a PPTX file from disk and returns a Presentation.
 // This is a convenience wrapper around NewReader + Read.
-func Open(path string) (*Presentation, error) {
-	reader, err := NewReader(ReaderPowerPoint2007)
+func Open(path string, opts ...ReaderOption) (*Presentation, error) {
+	reader, err := NewReader(ReaderPowerPoint2007, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -17,8 +17,8 @@ func Open(path string) (*Presentation, error) {
 }
 
 // ReadFrom reads a PPTX from an io.ReaderAt with the given size.
-func ReadFrom(r io.ReaderAt, size int64) (*Presentation, error) {
-	reader, err := NewReader(ReaderPowerPoint2007)
+func ReadFrom(r io.ReaderAt, size int64, opts ...ReaderOption) (*Presentation, error) {
+	reader, err := NewReader(ReaderPowerPoint2007, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,20 +41,43 @@ func OpenTemplate(path string) (*Presentation, error) {
 
 // Save writes the presentation to a PPTX file.
 // This is a convenience wrapper around NewWriter + Save.
-func (p *Presentation) Save(path string) error {
-	writer, err := NewWriter(p, WriterPowerPoint2007)
+func (p *Presentation) Save(path string, opts ...WriterOption) error {
+	writer, err := NewWriter(p, WriterPowerPoint2007, opts...)
 	if err != nil {
 		return err
 	}
 	return writer.Save(path)
 }
 
-// WriteTo writes the presentation to a writer in PPTX format.
-func (p *Presentation) WriteTo(w io.Writer) error {
+// SaveAsSlideshow writes the presentation as a slideshow (.ppsx) that opens
+// straight into the show. See PPTXWriter.SaveAsSlideshow.
+func (p *Presentation) SaveAsSlideshow(path string, opts ...WriterOption) error {
+	writer, err := NewWriter(p, WriterPowerPoint2007, opts...)
+	if err != nil {
+		return err
+	}
+	return writer.(*PPTXWriter).SaveAsSlideshow(path)
+}
+
+// SaveWithCompressionCache saves the presentation to dstPath as Save does,
+// but takes the compressed data of the parts that come out the same as in
+// the package at srcPath from it instead of compressing them again. This
+// pays off when saving again a package this library wrote; parts of other
+// packages rarely match. srcPath and dstPath may be the same file.
+func (p *Presentation) SaveWithCompressionCache(srcPath, dstPath string) error {
 	writer, err := NewWriter(p, WriterPowerPoint2007)
 	if err != nil {
 		return err
 	}
+	return writer.(*PPTXWriter).SaveWithCompressionCache(srcPath, dstPath)
+}
+
+// WriteTo writes the presentation to a writer in PPTX format.
+func (p *Presentation) WriteTo(w io.Writer, opts ...WriterOption) error {
+	writer, err := NewWriter(p, WriterPowerPoint2007, opts...)
+	if err != nil {
+		return err
+	}
 	return writer.WriteTo(w)
 }
 
@@ -144,7 +167,8 @@ func (p *Presentation) CopySlide(index int) (*Slide, error) {
 	src := p.slides[index]
 	dst := newSlide()
 	dst.name = src.name
-	dst.notes = src.notes
+	dst.notes = cloneParagraphs(src.notes)
+	dst.notesSource = src.notesSource
 	dst.visible = src.visible
 	if src.transition != nil {
 		t := *src.transition
@@ -154,6 +178,13 @@ func (p *Presentation) CopySlide(index int) (*Slide, error) {
 		bg := *src.background
 		dst.background = &bg
 	}
+	if src.backgroundRef != nil {
+		ref := *src.backgroundRef
+		dst.backgroundRef = &ref
+	}
+	dst.inheritedBackground = src.inheritedBackground
+	dst.tags = copyTags(src.tags)
+	dst.extLst = src.extLst
 	// Copy shapes slice (shapes are reference types)
 	dst.shapes = make([]Shape, len(src.shapes))
 	copy(dst.shapes, src.shapes)
@@ -173,8 +204,8 @@ func (p *Presentation) ExtractText() string {
 		if text := slide.ExtractText(); text != "" {
 			parts = append(parts, text)
 		}
-		if slide.notes != "" {
-			parts = append(parts, slide.notes)
+		if slide.HasNotes() {
+			parts = append(parts, slide.GetNotes())
 		}
 	}
 	return joinNonEmpty(parts, "\n")
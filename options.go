@@ -0,0 +1,400 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/sha1"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Option configures a presentation created with New.
+type Option func(*Presentation)
+
+// WithLayout sets the slide size to a predefined layout such as
+// LayoutScreen16x9.
+func WithLayout(name string) Option {
+	return func(p *Presentation) { p.layout.SetLayout(name) }
+}
+
+// WithTheme sets the colors and fonts of the written theme; see SetTheme.
+func WithTheme(t *Theme) Option {
+	return func(p *Presentation) { p.SetTheme(t) }
+}
+
+// WithDefaultFont sets the font of text runs that keep the NewFont
+// defaults; see SetDefaultFont.
+func WithDefaultFont(f *Font) Option {
+	return func(p *Presentation) { p.SetDefaultFont(f) }
+}
+
+// SetTheme sets the theme colors of the slots t defines and, where t names
+// them, the Latin typefaces of its heading and body fonts. A nil theme
+// changes nothing.
+func (p *Presentation) SetTheme(t *Theme) {
+	if t == nil {
+		return
+	}
+	for _, slot := range themeColorSlots {
+		if c, ok := t.GetColor(string(slot)); ok {
+			p.SetThemeColor(slot, c)
+		}
+	}
+	if t.MajorFont.Latin != "" {
+		p.majorFont = t.MajorFont
+	}
+	if t.MinorFont.Latin != "" {
+		p.minorFont = t.MinorFont
+	}
+}
+
+// themeFontXML returns the typefaces of a theme font, with latin as the
+// Latin typeface when f has none.
+func themeFontXML(f ThemeFont, latin string) string {
+	if f.Latin != "" {
+		latin = f.Latin
+	}
+	return fmt.Sprintf(`        <a:latin typeface="%s"/>
+        <a:ea typeface="%s"/>
+        <a:cs typeface="%s"/>
+`, xmlEscape(latin), xmlEscape(f.EastAsian), xmlEscape(f.ComplexScript))
+}
+
+// SetDefaultFont sets the font of text runs whose font still has the
+// NewFont defaults, as written and rendered. A nil font leaves runs as
+// they are.
+func (p *Presentation) SetDefaultFont(f *Font) {
+	if f == nil {
+		p.defaultFont = nil
+		return
+	}
+	font := *f
+	p.defaultFont = &font
+}
+
+// GetDefaultFont returns the font set with SetDefaultFont, or nil.
+func (p *Presentation) GetDefaultFont() *Font {
+	return p.defaultFont
+}
+
+// runFont returns the font f of a text run is written and rendered with.
+func runFont(f, defaultFont *Font) *Font {
+	if f == nil {
+		f = NewFont()
+	}
+	if defaultFont != nil && isDefaultFont(f) {
+		return defaultFont
+	}
+	return f
+}
+
+// WriterOptions controls how a PPTXWriter writes packages.
+type WriterOptions struct {
+	// CompressionLevel is the Deflate level of the parts, from
+	// flate.NoCompression to flate.BestCompression. Default:
+	// flate.DefaultCompression.
+	CompressionLevel int
+	// Strict checks the written package with ValidatePackage and fails the
+	// write, writing nothing, when it finds a problem.
+	Strict bool
+	// TextNormalization cleans up the typography of the text written; nil
+	// writes the text as it is.
+	TextNormalization *TextNormalization
+	// Logger receives the parts written and the time each write takes;
+	// nil logs nothing.
+	Logger *slog.Logger
+	// Metrics counts the parts and bytes written and times each write;
+	// nil reports nothing.
+	Metrics Metrics
+}
+
+// DefaultWriterOptions returns the default writer options.
+func DefaultWriterOptions() *WriterOptions {
+	return &WriterOptions{CompressionLevel: flate.DefaultCompression}
+}
+
+// WriterOption configures a writer created with NewWriter.
+type WriterOption func(*WriterOptions)
+
+// WithCompressionLevel sets the Deflate level of the written parts.
+func WithCompressionLevel(level int) WriterOption {
+	return func(o *WriterOptions) { o.CompressionLevel = level }
+}
+
+// WithStrictValidation makes writes fail when the written package does not
+// pass ValidatePackage.
+func WithStrictValidation() WriterOption {
+	return func(o *WriterOptions) { o.Strict = true }
+}
+
+// WithTextNormalization applies n to the text written; nil uses
+// DefaultTextNormalization.
+func WithTextNormalization(n *TextNormalization) WriterOption {
+	if n == nil {
+		n = DefaultTextNormalization()
+	}
+	return func(o *WriterOptions) { o.TextNormalization = n }
+}
+
+// WithWriterLogger logs the writes to l; see WriterOptions.Logger.
+func WithWriterLogger(l *slog.Logger) WriterOption {
+	return func(o *WriterOptions) { o.Logger = l }
+}
+
+// WithWriterMetrics reports the writes to m; see WriterOptions.Metrics.
+func WithWriterMetrics(m Metrics) WriterOption {
+	return func(o *WriterOptions) { o.Metrics = m }
+}
+
+// logger returns the logger of the writer.
+func (w *PPTXWriter) logger() *slog.Logger {
+	return loggerOr(w.options().Logger)
+}
+
+// SetOptions sets the options of the writer. A nil opts restores the
+// defaults.
+func (w *PPTXWriter) SetOptions(opts *WriterOptions) {
+	if opts == nil {
+		w.opts = nil
+		return
+	}
+	o := *opts
+	w.opts = &o
+}
+
+// GetOptions returns a copy of the options of the writer.
+func (w *PPTXWriter) GetOptions() *WriterOptions {
+	if w.opts == nil {
+		return DefaultWriterOptions()
+	}
+	o := *w.opts
+	return &o
+}
+
+// options returns the options in effect.
+func (w *PPTXWriter) options() *WriterOptions {
+	if w.opts == nil {
+		return DefaultWriterOptions()
+	}
+	return w.opts
+}
+
+// writeOutput writes the package to out with the writer's compression,
+// checking it first in strict mode.
+func (w *PPTXWriter) writeOutput(out io.Writer) error {
+	opts := w.options()
+	dst := out
+	var staged bytes.Buffer
+	if opts.Strict {
+		dst = &staged
+	}
+
+	start := time.Now()
+	defer observeMetric(opts.Metrics, MetricWriteDuration, start)
+	timer := newStageTimer(w.logger())
+	zw := newPartsZipWriter(dst, opts.CompressionLevel)
+	if err := w.writePackage(zw); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	timer.stage("package")
+	if !opts.Strict {
+		timer.done("wrote presentation", "slides", len(w.presentation.slides))
+		return nil
+	}
+
+	data := staged.Bytes()
+	issues, err := ValidatePackage(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("strict validation: %w", err)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("strict validation: %s", strings.Join(issues, "; "))
+	}
+	timer.stage("validation")
+	if _, err = out.Write(data); err != nil {
+		return err
+	}
+	timer.done("wrote presentation", "slides", len(w.presentation.slides), "bytes", len(data))
+	return nil
+}
+
+// newPartsZipWriter returns a zip writer that compresses parts at level.
+func newPartsZipWriter(out io.Writer, level int) *zip.Writer {
+	zw := zip.NewWriter(out)
+	if level != flate.DefaultCompression {
+		zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, level)
+		})
+	}
+	return zw
+}
+
+// slideCreationID returns the creation ID of slide, the slideNum-th slide
+// written: its own, or one derived from slideNum, which is not stored.
+func (w *PPTXWriter) slideCreationID(slide *Slide, slideNum int) uint32 {
+	if slide.creationID != 0 {
+		return slide.creationID
+	}
+	return max(crc32.ChecksumIEEE([]byte(fmt.Sprintf("slide%d", slideNum))), 1)
+}
+
+// shapeCreationID returns the creation ID of the shape written with ID id
+// in w.shapePart: its own, or one derived from the two, which is not
+// stored.
+func (w *PPTXWriter) shapeCreationID(b *BaseShape, id int) string {
+	if b.creationID != "" {
+		return b.creationID
+	}
+	u := sha1.Sum([]byte(fmt.Sprintf("%s#%d", w.shapePart, id)))
+	u[6] = u[6]&0x0F | 0x50 // version 5
+	u[8] = u[8]&0x3F | 0x80 // variant 1
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// ReaderOption configures a reader created with NewReader.
+type ReaderOption func(*PPTXReader)
+
+// WithElementHandler registers an element handler; see
+// PPTXReader.HandleElement.
+func WithElementHandler(space, local string, h ElementHandler) ReaderOption {
+	return func(r *PPTXReader) { r.HandleElement(space, local, h) }
+}
+
+// ReaderOptions controls how a PPTXReader reads packages.
+type ReaderOptions struct {
+	// LowMemory keeps the footprint of large decks down: pictures that use
+	// the same media part, such as a logo on every layout, share its data
+	// instead of each holding a copy, and the fonts of the runs share their
+	// typeface and color strings. The data that DrawingShape.GetImageData
+	// and Fill.ImageData hold for pictures read this way must not be
+	// modified in place; replace it with SetImageData or SetPicture.
+	LowMemory bool
+	// Workers is the number of slides parsed at the same time. 0 means
+	// GOMAXPROCS; 1 parses the slides one after another. The slides keep
+	// their order either way.
+	Workers int
+	// Logger receives the time each stage of a read takes and the content
+	// the reader skips; nil logs nothing.
+	Logger *slog.Logger
+	// Metrics counts the slides read and times each read; nil reports
+	// nothing.
+	Metrics Metrics
+	// MissingMedia is what the reader does with pictures whose media part
+	// is missing from the package. Either way the problem is reported by
+	// Presentation.GetReadIssues.
+	MissingMedia MissingMediaPolicy
+}
+
+// MissingMediaPolicy is what the reader does with a picture referencing a
+// media part that is missing from the package, or too large to read. With
+// MissingMediaSkip and MissingMediaError an empty media part counts as
+// missing; MissingMediaPlaceholder keeps it as read.
+// Picture fills of shapes, table cells and backgrounds whose media is
+// missing are left out with any policy but MissingMediaError.
+type MissingMediaPolicy int
+
+const (
+	// MissingMediaPlaceholder keeps the picture without its image: it is
+	// drawn as a placeholder showing its alt text, and written back with an
+	// empty media part, as it was read.
+	MissingMediaPlaceholder MissingMediaPolicy = iota
+	// MissingMediaSkip leaves the picture out.
+	MissingMediaSkip
+	// MissingMediaError fails the read with an error wrapping
+	// ErrMissingMedia.
+	MissingMediaError
+)
+
+// DefaultReaderOptions returns the default reader options.
+func DefaultReaderOptions() *ReaderOptions {
+	return &ReaderOptions{}
+}
+
+// WithLowMemory makes the reader share media and strings between the
+// objects it reads; see ReaderOptions.LowMemory.
+func WithLowMemory() ReaderOption {
+	return func(r *PPTXReader) {
+		opts := r.GetOptions()
+		opts.LowMemory = true
+		r.opts = opts
+	}
+}
+
+// WithWorkers sets the number of slides the reader parses at the same
+// time; see ReaderOptions.Workers.
+func WithWorkers(n int) ReaderOption {
+	return func(r *PPTXReader) {
+		opts := r.GetOptions()
+		opts.Workers = n
+		r.opts = opts
+	}
+}
+
+// WithReaderLogger logs the reads to l; see ReaderOptions.Logger.
+func WithReaderLogger(l *slog.Logger) ReaderOption {
+	return func(r *PPTXReader) {
+		opts := r.GetOptions()
+		opts.Logger = l
+		r.opts = opts
+	}
+}
+
+// WithReaderMetrics reports the reads to m; see ReaderOptions.Metrics.
+func WithReaderMetrics(m Metrics) ReaderOption {
+	return func(r *PPTXReader) {
+		opts := r.GetOptions()
+		opts.Metrics = m
+		r.opts = opts
+	}
+}
+
+// WithMissingMediaPolicy sets what the reader does with pictures whose
+// media part is missing; see ReaderOptions.MissingMedia.
+func WithMissingMediaPolicy(p MissingMediaPolicy) ReaderOption {
+	return func(r *PPTXReader) {
+		opts := r.GetOptions()
+		opts.MissingMedia = p
+		r.opts = opts
+	}
+}
+
+// logger returns the logger of the reader.
+func (r *PPTXReader) logger() *slog.Logger {
+	return loggerOr(r.options().Logger)
+}
+
+// SetOptions sets the options of the reader. A nil opts restores the
+// defaults.
+func (r *PPTXReader) SetOptions(opts *ReaderOptions) {
+	if opts == nil {
+		r.opts = nil
+		return
+	}
+	o := *opts
+	r.opts = &o
+}
+
+// GetOptions returns a copy of the options of the reader.
+func (r *PPTXReader) GetOptions() *ReaderOptions {
+	if r.opts == nil {
+		return DefaultReaderOptions()
+	}
+	o := *r.opts
+	return &o
+}
+
+// options returns the options in effect.
+func (r *PPTXReader) options() *ReaderOptions {
+	if r.opts == nil {
+		return DefaultReaderOptions()
+	}
+	return r.opts
+}
@@ -0,0 +1,204 @@
+package gopresentation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Outline is the title and bullet structure of a deck as shown in
+// PowerPoint's Outline View: one entry per slide holding the slide title and
+// the nested paragraphs of its body placeholder.
+type Outline struct {
+	Slides []*OutlineSlide
+}
+
+// OutlineSlide is one slide of an Outline.
+type OutlineSlide struct {
+	Title string
+	Items []*OutlineItem
+}
+
+// OutlineItem is a bullet of an outline slide with its sub-bullets.
+type OutlineItem struct {
+	Text     string
+	Children []*OutlineItem
+}
+
+// maxOutlineDepth is the number of list levels a text body supports.
+const maxOutlineDepth = 9
+
+// ExportOutline returns the outline of the presentation. As in Outline
+// View, only placeholder text is included: the title (or centered title)
+// placeholder gives the slide title and the body or subtitle placeholder
+// gives the items, nested by paragraph level.
+func (p *Presentation) ExportOutline() *Outline {
+	o := &Outline{}
+	for _, slide := range p.slides {
+		entry := &OutlineSlide{Title: slide.GetTitle()}
+		body := slide.GetPlaceholder(PlaceholderBody)
+		if body == nil {
+			body = slide.GetPlaceholder(PlaceholderSubTitle)
+		}
+		if body != nil {
+			// stack[i] is the last item added at level i
+			var stack []*OutlineItem
+			for _, para := range body.paragraphs {
+				text := joinNonEmpty(extractParagraphsText([]*Paragraph{para}), "")
+				if strings.TrimSpace(text) == "" {
+					continue
+				}
+				level := 0
+				if para.alignment != nil {
+					level = para.alignment.Level
+				}
+				if level > len(stack) {
+					level = len(stack)
+				}
+				item := &OutlineItem{Text: text}
+				if level == 0 {
+					entry.Items = append(entry.Items, item)
+				} else {
+					parent := stack[level-1]
+					parent.Children = append(parent.Children, item)
+				}
+				stack = append(stack[:level], item)
+			}
+		}
+		o.Slides = append(o.Slides, entry)
+	}
+	return o
+}
+
+// ImportOutline appends one slide per outline slide, each with a title
+// placeholder and, when it has items, a bulleted body placeholder whose
+// paragraph levels follow the item nesting. It returns the slides added.
+func (p *Presentation) ImportOutline(o *Outline) []*Slide {
+	if o == nil {
+		return nil
+	}
+	cx, cy := p.slideSizeOrDefault()
+	area := titleContentArea(cx, cy)
+
+	var added []*Slide
+	for _, entry := range o.Slides {
+		slide := p.CreateSlide()
+		addTitlePlaceholder(slide, entry.Title, cx, cy)
+
+		if len(entry.Items) > 0 {
+			body := slide.CreatePlaceholderShape(PlaceholderBody)
+			body.SetPlaceholderIndex(1)
+			body.SetOffsetX(area.X).SetOffsetY(area.Y).SetWidth(area.Width).SetHeight(area.Height)
+			body.ClearAll()
+			for level := 0; level < maxOutlineDepth; level++ {
+				body.SetListLevelStyle(level+1, outlineLevelStyle(level))
+			}
+			var addItems func(items []*OutlineItem, level int)
+			addItems = func(items []*OutlineItem, level int) {
+				for _, item := range items {
+					para := body.CreateParagraph()
+					para.alignment.Level = level
+					para.CreateTextRun(item.Text)
+					if level+1 < maxOutlineDepth {
+						addItems(item.Children, level+1)
+					} else {
+						addItems(item.Children, level)
+					}
+				}
+			}
+			addItems(entry.Items, 0)
+		}
+		added = append(added, slide)
+	}
+	return added
+}
+
+// outlineLevelStyle returns the bullet, indent and font size of an imported
+// outline body at the given 0-based level.
+func outlineLevelStyle(level int) *ListLevelStyle {
+	const step = 342900 // 0.375in
+	size := 28 - 4*level
+	if size < 14 {
+		size = 14
+	}
+	style := NewListLevelStyle()
+	style.Alignment = &Alignment{
+		Horizontal: HorizontalLeft,
+		MarginLeft: int64(level+1) * step,
+		Indent:     -step,
+	}
+	bullet := "•"
+	if level%2 == 1 {
+		bullet = "–"
+	}
+	style.Bullet = NewBullet().SetCharBullet(bullet)
+	style.Font = &Font{Size: size}
+	return style
+}
+
+// ParseOutline reads an outline in PowerPoint's plain text outline format:
+// each unindented line starts a slide with that title and lines indented by
+// tabs are items, one level per tab. Blank lines are ignored.
+func ParseOutline(r io.Reader) (*Outline, error) {
+	o := &Outline{}
+	var current *OutlineSlide
+	var stack []*OutlineItem
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimRight(sc.Text(), " \r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		depth := len(line) - len(strings.TrimLeft(line, "\t"))
+		text := strings.TrimSpace(line)
+		if depth == 0 {
+			current = &OutlineSlide{Title: text}
+			o.Slides = append(o.Slides, current)
+			stack = stack[:0]
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("outline line %d: item before the first slide title", lineNo)
+		}
+		level := depth - 1
+		if level > len(stack) {
+			level = len(stack)
+		}
+		item := &OutlineItem{Text: text}
+		if level == 0 {
+			current.Items = append(current.Items, item)
+		} else {
+			stack[level-1].Children = append(stack[level-1].Children, item)
+		}
+		stack = append(stack[:level], item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// WriteTo writes the outline in PowerPoint's plain text outline format,
+// readable by ParseOutline and PowerPoint's "Slides from Outline".
+func (o *Outline) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	var writeItems func(items []*OutlineItem, depth int)
+	writeItems = func(items []*OutlineItem, depth int) {
+		for _, item := range items {
+			sb.WriteString(strings.Repeat("\t", depth))
+			sb.WriteString(item.Text)
+			sb.WriteString("\n")
+			writeItems(item.Children, depth+1)
+		}
+	}
+	for _, s := range o.Slides {
+		sb.WriteString(s.Title)
+		sb.WriteString("\n")
+		writeItems(s.Items, 1)
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
@@ -0,0 +1,157 @@
+package gopresentation
+
+import (
+	"image"
+	"slices"
+)
+
+// AutoPaginate moves paragraphs that overflow their text box onto
+// continuation slides inserted directly after the overflowing slide. Text is
+// measured with the renderer's layout engine using opts for fonts and
+// resolution (nil uses DefaultRenderOptions). Splits happen at paragraph
+// boundaries; a single paragraph taller than its box is left in place.
+// Continuation slides repeat a copy of the slide's title placeholder and
+// hold copies of the overflowing shapes, with the remaining paragraphs, at
+// the same position; the copies share nothing with the slide and get their
+// own creation IDs. Internal links to the slides after an overflowing slide
+// are renumbered.
+// Title placeholders are never paginated. It returns the number of slides added.
+func (p *Presentation) AutoPaginate(opts *RenderOptions) int {
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	if p.layout == nil || p.layout.CX <= 0 || p.layout.CY <= 0 {
+		return 0
+	}
+	imgW := opts.Width
+	if imgW <= 0 {
+		imgW = 960
+	}
+	imgH := int(float64(imgW) * float64(p.layout.CY) / float64(p.layout.CX))
+	fc := opts.FontCache
+	if fc == nil {
+		fc = NewFontCache(opts.FontDirs...)
+	}
+	dpi := opts.DPI
+	if dpi <= 0 {
+		dpi = 96
+	}
+	r := &renderer{
+		img:         image.NewRGBA(image.Rect(0, 0, 1, 1)),
+		scaleX:      float64(imgW) / float64(p.layout.CX),
+		scaleY:      float64(imgH) / float64(p.layout.CY),
+		fontCache:   fc,
+		dpi:         dpi,
+		fontSubs:    fontSubstitutionMap(opts.FontSubstitutions),
+		defaultFont: p.defaultFont,
+	}
+
+	orig := slices.Clone(p.slides)
+	added := 0
+	for i := 0; i < len(p.slides); i++ {
+		cont := r.paginateSlide(p.slides[i])
+		if cont == nil {
+			continue
+		}
+		// Insert after the current slide; it is examined on the next iteration
+		p.slides = append(p.slides, nil)
+		copy(p.slides[i+2:], p.slides[i+1:])
+		p.slides[i+1] = cont
+		added++
+	}
+	if added > 0 {
+		pos := make(map[*Slide]int, len(p.slides))
+		for i, slide := range p.slides {
+			pos[slide] = i
+		}
+		newIndex := make([]int, len(orig))
+		for i, slide := range orig {
+			newIndex[i] = pos[slide]
+		}
+		remapSlideLinks(p.slides, newIndex)
+	}
+	return added
+}
+
+// paginateSlide truncates overflowing text shapes on slide and returns a
+// continuation slide holding the remainder, or nil when nothing overflows.
+func (r *renderer) paginateSlide(slide *Slide) *Slide {
+	var contShapes []Shape
+	overflowed := false
+	for _, shape := range slide.shapes {
+		switch s := shape.(type) {
+		case *PlaceholderShape:
+			if s.phType == PlaceholderTitle || s.phType == PlaceholderCtrTitle {
+				contShapes = append(contShapes, s.clone())
+				continue
+			}
+			if rest := r.splitOverflow(&s.RichTextShape); rest != nil {
+				c := s.clone()
+				c.paragraphs = rest
+				c.activeParagraph = 0
+				contShapes = append(contShapes, c)
+				overflowed = true
+			}
+		case *RichTextShape:
+			if rest := r.splitOverflow(s); rest != nil {
+				c := s.clone()
+				c.paragraphs = rest
+				c.activeParagraph = 0
+				contShapes = append(contShapes, c)
+				overflowed = true
+			}
+		}
+	}
+	if !overflowed {
+		return nil
+	}
+	cont := newSlide()
+	cont.name = slide.name
+	cont.visible = slide.visible
+	cont.background = cloneFill(slide.background)
+	cont.inheritedBackground = slide.inheritedBackground
+	if slide.backgroundRef != nil {
+		ref := *slide.backgroundRef
+		cont.backgroundRef = &ref
+	}
+	cont.shapes = contShapes
+	return cont
+}
+
+// splitOverflow keeps the paragraphs of s that fit within its text area and
+// returns the ones that do not, or nil when everything fits.
+func (r *renderer) splitOverflow(s *RichTextShape) []*Paragraph {
+	if len(s.paragraphs) < 2 || s.width <= 0 || s.height <= 0 {
+		return nil
+	}
+	lIns, rIns, tIns, bIns := int64(91440), int64(91440), int64(45720), int64(45720)
+	if s.insetsSet {
+		lIns, rIns, tIns, bIns = s.insetLeft, s.insetRight, s.insetTop, s.insetBottom
+	}
+	tw := r.emuToPixelX(s.width - lIns - rIns)
+	th := r.emuToPixelY(s.height - tIns - bIns)
+	if tw <= 0 || th <= 0 {
+		return nil
+	}
+
+	prevFontScale := r.fontScale
+	if s.fontScale > 0 && s.fontScale != 100000 {
+		r.fontScale = float64(s.fontScale) / 100000.0
+	}
+	defer func() { r.fontScale = prevFontScale }()
+
+	paras := resolveListStyle(&s.listStyle, s.paragraphs, false)
+	if r.measureParagraphsHeight(paras, tw, th, s.textAnchor, s.wordWrap) <= th {
+		return nil
+	}
+	fit := 1
+	for fit < len(paras) && r.measureParagraphsHeight(paras[:fit+1], tw, th, s.textAnchor, s.wordWrap) <= th {
+		fit++
+	}
+	rest := s.paragraphs[fit:]
+	s.paragraphs = s.paragraphs[:fit:fit]
+	if s.activeParagraph >= fit {
+		s.activeParagraph = fit - 1
+	}
+	return rest
+}
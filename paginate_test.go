@@ -0,0 +1,182 @@
+package gopresentation
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestAutoPaginateCopiesShapes(t *testing.T) {
+	p := New()
+	slide := p.GetActiveSlide()
+	title := slide.CreatePlaceholderShape(PlaceholderTitle)
+	title.SetText("Title")
+	title.SetTag("KIND", "title")
+
+	body := slide.CreateRichTextShape()
+	body.SetOffsetX(0).SetOffsetY(0).SetWidth(4000000).SetHeight(600000)
+	body.SetTag("KIND", "body")
+	body.SetUserData("key", "value")
+	body.SetHyperlink(NewHyperlink("https://example.com"))
+	body.GetActiveParagraph().CreateTextRun("Paragraph 0")
+	for i := 1; i < 12; i++ {
+		body.CreateParagraph().CreateTextRun(fmt.Sprintf("Paragraph %d", i))
+	}
+	titleID, bodyID := title.GetCreationID(), body.GetCreationID()
+
+	if added := p.AutoPaginate(nil); added == 0 {
+		t.Fatal("nothing was paginated")
+	}
+	cont, err := p.GetSlide(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contTitle *PlaceholderShape
+	var contBody *RichTextShape
+	for _, s := range cont.GetShapes() {
+		switch s := s.(type) {
+		case *PlaceholderShape:
+			contTitle = s
+		case *RichTextShape:
+			contBody = s
+		}
+	}
+	if contTitle == nil || contBody == nil {
+		t.Fatalf("continuation shapes = %v", cont.GetShapes())
+	}
+	if contTitle == title || contBody == body {
+		t.Fatal("continuation slide holds the slide's own shapes")
+	}
+
+	contTitle.SetTag("KIND", "changed")
+	contTitle.GetParagraphs()[0].GetElements()[0].(*TextRun).SetText("Changed")
+	contBody.SetTag("KIND", "changed")
+	contBody.SetUserData("key", "changed")
+	contBody.GetHyperlink().URL = "https://changed.example.com"
+	if title.GetTag("KIND") != "title" || body.GetTag("KIND") != "body" {
+		t.Error("changing the copies' tags changed the originals")
+	}
+	if got := title.GetParagraphs()[0].GetElements()[0].(*TextRun).GetText(); got != "Title" {
+		t.Errorf("title text = %q after changing the copy", got)
+	}
+	if body.GetUserData("key") != "value" {
+		t.Error("changing the copy's user data changed the original")
+	}
+	if body.GetHyperlink().URL != "https://example.com" {
+		t.Error("changing the copy's hyperlink changed the original")
+	}
+
+	if id := contTitle.GetCreationID(); id == titleID {
+		t.Errorf("title copy has the title's creation ID %s", id)
+	}
+	if id := contBody.GetCreationID(); id == bodyID {
+		t.Errorf("body copy has the body's creation ID %s", id)
+	}
+	data := writePackage(t, p)
+	seen := map[string]string{}
+	re := regexp.MustCompile(`a16:creationId [^>]*id="([^"]+)"`)
+	for name, part := range packageParts(t, data, "ppt/slides/slide") {
+		for _, m := range re.FindAllSubmatch(part, -1) {
+			id := string(m[1])
+			if other, ok := seen[id]; ok {
+				t.Errorf("creation ID %s written in %s and %s", id, other, name)
+			}
+			seen[id] = name
+		}
+	}
+	if len(seen) == 0 {
+		t.Error("no creation IDs written")
+	}
+}
+
+func TestSplitTableAcrossSlidesCopiesShapes(t *testing.T) {
+	p := New()
+	slide := p.GetActiveSlide()
+	table := slide.CreateTableShape(7, 2)
+	table.SetFirstRow(true)
+	table.GetCell(0, 0).SetText("Header")
+	for i := 1; i < 7; i++ {
+		table.GetCell(i, 0).SetText(fmt.Sprintf("Row %d", i))
+	}
+	box := slide.CreateAutoShape()
+	box.SetTag("KIND", "box")
+	box.SetHyperlink(NewInternalHyperlink(3))
+	p.CreateSlide()
+	last := p.CreateSlide()
+	back := last.CreateRichTextShape()
+	back.CreateTextRun("Back").SetHyperlink(NewInternalHyperlink(1))
+	tableID, boxID := table.GetCreationID(), box.GetCreationID()
+
+	added, err := p.SplitTableAcrossSlides(slide, table, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 2 || p.GetSlideCount() != 5 {
+		t.Fatalf("added %d slides, have %d", len(added), p.GetSlideCount())
+	}
+	first := slide.GetShapes()[0].(*TableShape)
+	if id := first.GetCreationID(); id != tableID {
+		t.Errorf("first part creation ID = %s, want %s", id, tableID)
+	}
+	for n, cont := range added {
+		part := cont.GetShapes()[0].(*TableShape)
+		contBox := cont.GetShapes()[1].(*AutoShape)
+		if contBox == box {
+			t.Fatalf("continuation slide %d holds the slide's own shape", n)
+		}
+		contBox.SetTag("KIND", "changed")
+		part.SetTag("KIND", "changed")
+		part.GetCell(0, 0).GetParagraphs()[0].GetElements()[0].(*TextRun).SetText("Changed")
+		if id := contBox.GetCreationID(); id == boxID {
+			t.Errorf("continuation slide %d box has the box's creation ID", n)
+		}
+		if id := part.GetCreationID(); id == tableID {
+			t.Errorf("continuation slide %d table has the table's creation ID", n)
+		}
+		if got := contBox.GetHyperlink().SlideNumber; got != 5 {
+			t.Errorf("continuation slide %d box links to slide %d, want 5", n, got)
+		}
+	}
+	if box.GetTag("KIND") != "box" || first.GetTag("KIND") != "" {
+		t.Error("changing the copies' tags changed the originals")
+	}
+	if got := first.GetCell(0, 0).GetParagraphs()[0].GetElements()[0].(*TextRun).GetText(); got != "Header" {
+		t.Errorf("header text = %q after changing a copy", got)
+	}
+	if got := box.GetHyperlink().SlideNumber; got != 5 {
+		t.Errorf("box links to slide %d, want 5", got)
+	}
+	if got := back.GetParagraphs()[0].GetElements()[0].(*TextRun).GetHyperlink().SlideNumber; got != 1 {
+		t.Errorf("back link points to slide %d, want 1", got)
+	}
+}
+
+func TestAutoPaginateRenumbersSlideLinks(t *testing.T) {
+	p := New()
+	body := p.GetActiveSlide().CreateRichTextShape()
+	body.SetOffsetX(0).SetOffsetY(0).SetWidth(4000000).SetHeight(600000)
+	body.SetHyperlink(NewInternalHyperlink(2))
+	body.GetActiveParagraph().CreateTextRun("Paragraph 0")
+	for i := 1; i < 12; i++ {
+		body.CreateParagraph().CreateTextRun(fmt.Sprintf("Paragraph %d", i))
+	}
+	back := p.CreateSlide().CreateRichTextShape()
+	back.CreateTextRun("Back").SetHyperlink(NewInternalHyperlink(1))
+
+	added := p.AutoPaginate(nil)
+	if added == 0 {
+		t.Fatal("nothing was paginated")
+	}
+	want := added + 2
+	for i := 0; i <= added; i++ {
+		slide, _ := p.GetSlide(i)
+		for _, s := range slide.GetShapes() {
+			if got := s.(*RichTextShape).GetHyperlink().SlideNumber; got != want {
+				t.Errorf("slide %d links to slide %d, want %d", i+1, got, want)
+			}
+		}
+	}
+	if got := back.GetParagraphs()[0].GetElements()[0].(*TextRun).GetHyperlink().SlideNumber; got != 1 {
+		t.Errorf("back link points to slide %d, want 1", got)
+	}
+}
@@ -0,0 +1,94 @@
+package gopresentation
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// ImageSource is a picture for AddPhotoAlbum, given either as data or as a
+// file path. An empty MimeType is detected from the data.
+type ImageSource struct {
+	Data     []byte
+	MimeType string
+	Path     string
+	Caption  string // defaults to the file name without extension
+}
+
+// AddPhotoAlbum appends slides showing images perSlide at a time, like
+// PowerPoint's Photo Album: the pictures of a slide are laid out in a grid
+// of equal cells, each scaled to fit its cell without distortion and, when
+// captions is true, labeled with its caption below. It returns the slides
+// added; on error no slides are added.
+func (p *Presentation) AddPhotoAlbum(images []ImageSource, perSlide int, captions bool) ([]*Slide, error) {
+	if perSlide <= 0 {
+		perSlide = 1
+	}
+	pictures := make([]*DrawingShape, len(images))
+	for i, src := range images {
+		d, err := src.drawingShape()
+		if err != nil {
+			return nil, fmt.Errorf("photo album image %d: %w", i, err)
+		}
+		d.SetName(fmt.Sprintf("Picture %d", i+1))
+		pictures[i] = d
+	}
+
+	cx, cy := p.slideSizeOrDefault()
+	margin := cx / 20
+	gap := margin / 2
+	captionH := int64(0)
+	if captions {
+		captionH = cy / 14
+	}
+
+	// Placement happens before any slide is created so that a picture
+	// that cannot be decoded leaves the presentation unchanged.
+	var pages [][]*DrawingShape
+	for start := 0; start < len(pictures); start += perSlide {
+		page := pictures[start:min(start+perSlide, len(pictures))]
+		cols := int(math.Ceil(math.Sqrt(float64(len(page)))))
+		rows := (len(page) + cols - 1) / cols
+		cellW := (cx - 2*margin - int64(cols-1)*gap) / int64(cols)
+		cellH := (cy - 2*margin - int64(rows-1)*gap) / int64(rows)
+		for j, d := range page {
+			cellX := margin + int64(j%cols)*(cellW+gap)
+			cellY := margin + int64(j/cols)*(cellH+gap)
+			cell := Rect{X: cellX, Y: cellY, Width: cellW, Height: cellH - captionH}
+			if err := d.FitWithin(cell, nil); err != nil {
+				return nil, fmt.Errorf("photo album image %d: %w", start+j, err)
+			}
+		}
+		pages = append(pages, page)
+	}
+
+	var added []*Slide
+	for pi, page := range pages {
+		slide := p.CreateSlide()
+		for j, d := range page {
+			slide.AddShape(d)
+			if !captions {
+				continue
+			}
+			src := images[pi*perSlide+j]
+			text := src.Caption
+			if text == "" && src.Path != "" {
+				base := filepath.Base(src.Path)
+				text = strings.TrimSuffix(base, filepath.Ext(base))
+			}
+			if text == "" {
+				continue
+			}
+			caption := slide.CreateRichTextShape()
+			caption.SetName(fmt.Sprintf("Caption %d", pi*perSlide+j+1))
+			caption.SetOffsetX(d.offsetX).SetOffsetY(d.offsetY + d.height)
+			caption.SetWidth(d.width).SetHeight(captionH)
+			para := caption.GetActiveParagraph()
+			para.GetAlignment().SetHorizontal(HorizontalCenter)
+			para.CreateTextRun(text).GetFont().SetSize(14)
+		}
+		added = append(added, slide)
+	}
+	return added, nil
+}
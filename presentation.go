@@ -23,10 +23,49 @@ type Presentation struct {
 	// themeColors maps scheme color names (dk1, dk2, lt1, lt2, accent1..accent6,
 	// hlink, folHlink) to ARGB hex strings (e.g. "FF000000").
 	themeColors map[string]string
+	// themeFillStyles and themeBgFillStyles hold the theme format scheme fills
+	// referenced by style indexes such as p:bgRef idx.
+	themeFillStyles   []*themeFillStyle
+	themeBgFillStyles []*themeFillStyle
+
+	// embeddedFonts holds the fonts embedded in a presentation that was read.
+	embeddedFonts []*EmbeddedFont
+	// themes holds the themes of a presentation that was read.
+	themes []*Theme
+
+	// firstSlideNumber is the number of the first slide; 0 means 1.
+	firstSlideNumber  int
+	slideNumberFormat string
+
+	// documentType is the kind of package the presentation is written as;
+	// "" means DocumentTypePresentation.
+	documentType DocumentType
+	// vbaProject is the VBA project of a macro-enabled presentation.
+	vbaProject *VBAProject
+	// notesMaster is the notes master read with the presentation, or nil.
+	notesMaster *notesMasterSource
+	// readStats describes the read that produced the presentation, or nil.
+	readStats *ReadStats
+	// readIssues are the problems worked around by that read.
+	readIssues []ReadIssue
+
+	// textStyles and shapeStyles are the named styles registered by the
+	// caller; they are not written.
+	textStyles  map[string]TextStyle
+	shapeStyles map[string]ShapeStyle
+
+	// defaultFont replaces the font of text runs that keep the NewFont
+	// defaults; nil leaves them as they are.
+	defaultFont *Font
+	// majorFont and minorFont are the heading and body fonts of the written
+	// theme; an empty Latin typeface means the Office default.
+	majorFont ThemeFont
+	minorFont ThemeFont
 }
 
-// New creates a new Presentation with one default blank slide.
-func New() *Presentation {
+// New creates a new Presentation with one default blank slide, configured
+// by opts.
+func New(opts ...Option) *Presentation {
 	p := &Presentation{
 		properties:             NewDocumentProperties(),
 		presentationProperties: NewPresentationProperties(),
@@ -35,6 +74,9 @@ func New() *Presentation {
 		activeSlideIndex:       0,
 		layout:                 NewDocumentLayout(),
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
 	// Add a default slide
 	p.CreateSlide()
 	return p
@@ -158,6 +200,40 @@ func (p *Presentation) MoveSlide(fromIndex, toIndex int) error {
 	return nil
 }
 
+// GetSlideTimings returns the time in milliseconds each slide is shown
+// before the slideshow advances, 0 for slides that advance on click.
+func (p *Presentation) GetSlideTimings() []int {
+	timings := make([]int, len(p.slides))
+	for i, s := range p.slides {
+		timings[i] = s.GetAdvanceAfter()
+	}
+	return timings
+}
+
+// SetSlideTimings sets the time in milliseconds each slide is shown, in
+// slide order, such as the durations of a narration. Slides beyond
+// timings keep theirs.
+func (p *Presentation) SetSlideTimings(timings []int) {
+	for i, ms := range timings {
+		if i >= len(p.slides) {
+			break
+		}
+		p.slides[i].SetAdvanceAfter(ms)
+	}
+}
+
+// GetEmbeddedFonts returns the fonts embedded in the presentation file it
+// was read from. Register them for rendering with FontCache.RegisterEmbeddedFonts.
+func (p *Presentation) GetEmbeddedFonts() []*EmbeddedFont {
+	return p.embeddedFonts
+}
+
+// GetThemes returns the themes of the presentation file it was read from,
+// in the order their slide masters are listed.
+func (p *Presentation) GetThemes() []*Theme {
+	return p.themes
+}
+
 // GetSlideMasters returns all slide masters.
 func (p *Presentation) GetSlideMasters() []*SlideMaster {
 	return p.slideMasters
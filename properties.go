@@ -9,6 +9,14 @@ type PresentationProperties struct {
 	markedAsFinal  bool
 	thumbnailPath  string
 	thumbnailData  []byte
+	loop           bool
+	showNarration  bool
+	showAnimation  bool
+	useTimings     bool
+	penColor       *Color
+	laserColor     *Color
+	rangeStart     int // 1-based first slide of the show range, 0 means all slides
+	rangeEnd       int // 1-based last slide of the show range
 }
 
 // ViewType represents the last view type.
@@ -40,6 +48,9 @@ func NewPresentationProperties() *PresentationProperties {
 		slideshowType:  SlideshowTypePresent,
 		commentVisible: false,
 		markedAsFinal:  false,
+		showNarration:  true,
+		showAnimation:  true,
+		useTimings:     true,
 	}
 }
 
@@ -123,11 +134,95 @@ func (pp *PresentationProperties) GetThumbnailData() []byte {
 	return pp.thumbnailData
 }
 
+// IsLoop returns whether the slideshow loops until Esc is pressed.
+func (pp *PresentationProperties) IsLoop() bool {
+	return pp.loop
+}
+
+// SetLoop sets whether the slideshow loops until Esc is pressed.
+func (pp *PresentationProperties) SetLoop(loop bool) {
+	pp.loop = loop
+}
+
+// IsShowNarration returns whether narration is played during the slideshow.
+func (pp *PresentationProperties) IsShowNarration() bool {
+	return pp.showNarration
+}
+
+// SetShowNarration sets whether narration is played during the slideshow.
+func (pp *PresentationProperties) SetShowNarration(show bool) {
+	pp.showNarration = show
+}
+
+// IsShowAnimation returns whether animations are played during the slideshow.
+func (pp *PresentationProperties) IsShowAnimation() bool {
+	return pp.showAnimation
+}
+
+// SetShowAnimation sets whether animations are played during the slideshow.
+func (pp *PresentationProperties) SetShowAnimation(show bool) {
+	pp.showAnimation = show
+}
+
+// IsUseTimings returns whether the slideshow advances slides after their
+// timings.
+func (pp *PresentationProperties) IsUseTimings() bool {
+	return pp.useTimings
+}
+
+// SetUseTimings sets whether the slideshow advances slides after their
+// timings; when false they advance on click only, keeping the timings.
+func (pp *PresentationProperties) SetUseTimings(use bool) {
+	pp.useTimings = use
+}
+
+// GetPenColor returns the slideshow pen color, or nil for the application default.
+func (pp *PresentationProperties) GetPenColor() *Color {
+	return pp.penColor
+}
+
+// SetPenColor sets the slideshow pen color.
+func (pp *PresentationProperties) SetPenColor(c Color) {
+	pp.penColor = &c
+}
+
+// GetLaserColor returns the slideshow laser pointer color, or nil for the application default.
+func (pp *PresentationProperties) GetLaserColor() *Color {
+	return pp.laserColor
+}
+
+// SetLaserColor sets the slideshow laser pointer color.
+func (pp *PresentationProperties) SetLaserColor(c Color) {
+	pp.laserColor = &c
+}
+
+// GetSlideRange returns the 1-based slide range shown in the slideshow.
+// Both values are 0 when all slides are shown.
+func (pp *PresentationProperties) GetSlideRange() (start, end int) {
+	return pp.rangeStart, pp.rangeEnd
+}
+
+// SetSlideRange limits the slideshow to slides start..end (1-based, inclusive).
+// Passing a start of 0 or less shows all slides.
+func (pp *PresentationProperties) SetSlideRange(start, end int) {
+	if start <= 0 {
+		pp.rangeStart, pp.rangeEnd = 0, 0
+		return
+	}
+	if end < start {
+		end = start
+	}
+	pp.rangeStart, pp.rangeEnd = start, end
+}
+
 // DocumentLayout represents the slide dimensions.
 type DocumentLayout struct {
 	CX   int64 // width in EMU (English Metric Units)
 	CY   int64 // height in EMU
 	Name string
+	// Portrait turns the preset dimensions applied by SetLayout so that
+	// slides are taller than wide.
+	Portrait bool
 }
 
 // Standard layout constants (in EMU: 1 inch = 914400 EMU).
@@ -138,8 +233,36 @@ const (
 	LayoutA4          = "A4"
 	LayoutLetter      = "letter"
 	LayoutCustom      = "custom"
+	LayoutA3          = "A3"
+	LayoutB4ISO       = "B4ISO"
+	LayoutB5ISO       = "B5ISO"
+	LayoutLedger      = "ledger"
+	Layout35mm        = "35mm"
+	LayoutOverhead    = "overhead"
+	LayoutBanner      = "banner"
+	// LayoutWidescreen is PowerPoint's default 13.333 x 7.5 inch size. It
+	// has no slide size type of its own and is written as a custom size.
+	LayoutWidescreen = "widescreen"
 )
 
+// layoutPresets holds the landscape dimensions of the preset layouts, as
+// PowerPoint defines them.
+var layoutPresets = map[string][2]int64{
+	LayoutScreen4x3:   {9144000, 6858000},
+	LayoutScreen16x9:  {12192000, 6858000},
+	LayoutScreen16x10: {10972800, 6858000},
+	LayoutA4:          {9906000, 6858000},
+	LayoutLetter:      {9144000, 6858000},
+	LayoutA3:          {12801600, 9601200},
+	LayoutB4ISO:       {10826750, 8120063},
+	LayoutB5ISO:       {7169150, 5376863},
+	LayoutLedger:      {12179300, 9134475},
+	Layout35mm:        {10287000, 6858000},
+	LayoutOverhead:    {9144000, 6858000},
+	LayoutBanner:      {7315200, 914400},
+	LayoutWidescreen:  {12192000, 6858000},
+}
+
 // NewDocumentLayout creates a default 4:3 layout.
 func NewDocumentLayout() *DocumentLayout {
 	return &DocumentLayout{
@@ -149,28 +272,50 @@ func NewDocumentLayout() *DocumentLayout {
 	}
 }
 
-// SetLayout sets a predefined layout.
+// SetLayout sets a predefined layout. The dimensions are swapped when the
+// layout is portrait; unknown names only change the name.
 func (dl *DocumentLayout) SetLayout(name string) {
 	dl.Name = name
-	switch name {
-	case LayoutScreen4x3:
-		dl.CX = 9144000
-		dl.CY = 6858000
-	case LayoutScreen16x9:
-		dl.CX = 12192000
-		dl.CY = 6858000
-	case LayoutScreen16x10:
-		dl.CX = 10972800
-		dl.CY = 6858000
-	case LayoutA4:
-		dl.CX = 9906000
-		dl.CY = 6858000
-	case LayoutLetter:
-		dl.CX = 9144000
-		dl.CY = 6858000
+	if size, ok := layoutPresets[name]; ok {
+		dl.CX, dl.CY = size[0], size[1]
+		if dl.Portrait {
+			dl.CX, dl.CY = dl.CY, dl.CX
+		}
 	}
 }
 
+// SetOrientation sets whether slides are portrait, swapping the current
+// dimensions if they do not match.
+func (dl *DocumentLayout) SetOrientation(portrait bool) {
+	dl.Portrait = portrait
+	if portrait != (dl.CY > dl.CX) && dl.CX != dl.CY {
+		dl.CX, dl.CY = dl.CY, dl.CX
+	}
+}
+
+// slideSizeType returns the type attribute of sldSz for the layout, or ""
+// when the layout has no slide size type and is described by its
+// dimensions alone.
+func (dl *DocumentLayout) slideSizeType() string {
+	switch dl.Name {
+	case LayoutScreen4x3, LayoutScreen16x9, LayoutScreen16x10, LayoutA4, LayoutLetter,
+		LayoutCustom, LayoutA3, LayoutB4ISO, LayoutB5ISO, LayoutLedger, Layout35mm,
+		LayoutOverhead, LayoutBanner, "B4JIS", "B5JIS", "hagakiCard":
+		return dl.Name
+	}
+	return ""
+}
+
+// layoutNameForSize returns the layout name of a slide size read without a
+// type attribute.
+func layoutNameForSize(cx, cy int64) string {
+	w := layoutPresets[LayoutWidescreen]
+	if (cx == w[0] && cy == w[1]) || (cx == w[1] && cy == w[0]) {
+		return LayoutWidescreen
+	}
+	return LayoutCustom
+}
+
 // SetCustomLayout sets custom dimensions in EMU. Both values must be positive.
 func (dl *DocumentLayout) SetCustomLayout(cx, cy int64) {
 	if cx <= 0 {
@@ -182,12 +327,17 @@ func (dl *DocumentLayout) SetCustomLayout(cx, cy int64) {
 	dl.CX = cx
 	dl.CY = cy
 	dl.Name = LayoutCustom
+	dl.Portrait = cy > cx
 }
 
 // SlideMaster represents a slide master.
 type SlideMaster struct {
 	Name         string
 	SlideLayouts []*SlideLayout
+	// Theme is the theme the master uses; nil when unknown.
+	Theme *Theme
+
+	shapes []Shape
 }
 
 // SlideLayout represents a slide layout.
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Reader is the interface for presentation readers.
@@ -23,17 +26,29 @@ const (
 )
 
 // NewReader creates a reader for the given format.
-func NewReader(format ReaderType) (Reader, error) {
+func NewReader(format ReaderType, opts ...ReaderOption) (Reader, error) {
 	switch format {
 	case ReaderPowerPoint2007:
-		return &PPTXReader{}, nil
+		r := &PPTXReader{}
+		for _, opt := range opts {
+			opt(r)
+		}
+		return r, nil
 	default:
 		return nil, fmt.Errorf("unsupported reader format: %s", format)
 	}
 }
 
 // PPTXReader reads PPTX files.
-type PPTXReader struct{}
+type PPTXReader struct {
+	// elementHandlers are registered with HandleElement.
+	elementHandlers map[xml.Name]ElementHandler
+	// opts holds the reader options; nil means the defaults.
+	opts *ReaderOptions
+	// read holds the state of the read in progress, on the copy of the
+	// reader made for it by newRead.
+	read *readState
+}
 
 // zipIndex builds a map from file name to *zip.File for O(1) lookups.
 func zipIndex(zr *zip.Reader) map[string]*zip.File {
@@ -78,19 +93,18 @@ func (r *PPTXReader) ReadFromReader(reader io.ReaderAt, size int64) (*Presentati
 		return nil, fmt.Errorf("zip archive contains too many entries (%d > %d)", len(zr.File), maxZipEntries)
 	}
 
-	pres := &Presentation{
-		properties:             NewDocumentProperties(),
-		presentationProperties: NewPresentationProperties(),
-		slides:                 make([]*Slide, 0),
-		slideMasters:           make([]*SlideMaster, 0),
-		layout:                 NewDocumentLayout(),
-	}
+	r = r.newRead()
+	start := time.Now()
+	timer := newStageTimer(r.logger())
+	pres := newReadPresentation()
 
 	// Read core properties (non-fatal: missing properties are acceptable)
 	_ = r.readCoreProperties(zr, pres)
 
 	// Read theme colors (non-fatal)
 	r.readThemeColors(zr, pres)
+	r.readThemeFormatScheme(zr, pres)
+	timer.stage("properties and theme")
 
 	// Read presentation.xml to get slide list and layout
 	slideRels, err := r.readPresentation(zr, pres)
@@ -104,7 +118,22 @@ func (r *PPTXReader) ReadFromReader(reader io.ReaderAt, size int64) (*Presentati
 		return nil, err
 	}
 
+	// Read the package kind and the VBA project of macro-enabled files
+	r.readDocumentType(zr, presRels, pres)
+
+	// Read the slideshow settings, last view and zoom (non-fatal)
+	r.readPresentationProperties(zr, presRels, pres)
+	timer.stage("presentation")
+
+	// Read slide masters, their layouts and themes (non-fatal)
+	r.readSlideMasters(zr, presRels, pres)
+
+	// Keep the notes master to write it back (non-fatal)
+	r.readNotesMaster(zr, presRels, pres)
+	timer.stage("masters")
+
 	// Read slides
+	targets := make([]string, 0, len(slideRels))
 	for _, relID := range slideRels {
 		target := ""
 		for _, rel := range presRels {
@@ -122,16 +151,103 @@ func (r *PPTXReader) ReadFromReader(reader io.ReaderAt, size int64) (*Presentati
 			target = "ppt/" + target
 		}
 
-		slide, err := r.readSlide(zr, target, pres)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read slide %s: %w", target, err)
-		}
+		targets = append(targets, target)
+	}
+	slides, err := r.readSlides(zr, targets, pres)
+	if err != nil {
+		return nil, err
+	}
+	timer.stage("slides")
+	if err := r.read.mediaErr; err != nil {
+		return nil, err
+	}
+	slidePaths := make(map[string]int, len(slides))
+	for i, slide := range slides {
+		slidePaths[targets[i]] = len(pres.slides)
 		pres.slides = append(pres.slides, slide)
 	}
 
+	// Resolve slide-jump hyperlinks now that every slide has an index
+	resolve := func(h *Hyperlink) {
+		if h.targetPart == "" {
+			return
+		}
+		if idx, ok := slidePaths[h.targetPart]; ok {
+			h.SlideNumber = idx + 1
+		} else {
+			h.SlideNumber = 0
+		}
+		h.targetPart = ""
+	}
+	for _, slide := range pres.slides {
+		forEachHyperlink(slide.shapes, resolve)
+		for _, para := range slide.notes {
+			for _, elem := range para.elements {
+				if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil {
+					resolve(tr.hyperlink)
+				}
+			}
+		}
+		// Remember the notes as read, to write the notes slide back
+		// unchanged unless they are modified
+		if slide.notesSource != nil {
+			slide.notesSource.read = cloneParagraphs(slide.notes)
+		}
+	}
+
+	r.finishRead(zr, pres)
+	timer.done("read presentation", "slides", len(pres.slides), "parts", len(zr.File))
+	m := r.options().Metrics
+	countMetric(m, MetricSlidesRead, int64(len(pres.slides)))
+	observeMetric(m, MetricReadDuration, start)
 	return pres, nil
 }
 
+// newReadPresentation returns the empty presentation a read fills in.
+func newReadPresentation() *Presentation {
+	return &Presentation{
+		properties:             NewDocumentProperties(),
+		presentationProperties: NewPresentationProperties(),
+		slides:                 make([]*Slide, 0),
+		slideMasters:           make([]*SlideMaster, 0),
+		layout:                 NewDocumentLayout(),
+	}
+}
+
+// readSlides reads the slide parts at paths, as many at a time as the
+// Workers option allows, and returns the slides in the order of paths.
+func (r *PPTXReader) readSlides(zr *zip.Reader, paths []string, pres *Presentation) ([]*Slide, error) {
+	slides := make([]*Slide, len(paths))
+	errs := make([]error, len(paths))
+	workers := r.options().Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(paths))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range next {
+				slides[i], errs[i] = r.readSlide(zr, paths[i], pres)
+			}
+		})
+	}
+	for i := range paths {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to read slide %s: %w", paths[i], err)
+		}
+	}
+	return slides, nil
+}
+
 // maxZipEntrySize is the maximum allowed size for a single file extracted from a ZIP.
 // This prevents zip bomb attacks. 50 MB is generous for any legitimate PPTX part.
 const maxZipEntrySize = 50 << 20 // 50 MB
@@ -142,6 +258,11 @@ const maxZipTotalSize = 200 << 20 // 200 MB
 // maxZipEntries is the maximum number of files allowed in a ZIP archive.
 const maxZipEntries = 10000
 
+// maxGroupDepth is the deepest nesting of group shapes allowed in a slide.
+// Writing nested groups takes time growing with the square of their depth,
+// so a small part could otherwise hold up the writer for minutes.
+const maxGroupDepth = 100
+
 func readFileFromZip(zr *zip.Reader, name string) ([]byte, error) {
 	if len(zr.File) > maxZipEntries {
 		return nil, fmt.Errorf("zip archive contains too many entries (%d > %d)", len(zr.File), maxZipEntries)
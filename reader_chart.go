@@ -0,0 +1,744 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Chart parts are read into the chart model: the chart type with its
+// series, the title, the legend and the value axis bounds. A series' values
+// come from the caches in the chart part; when a cache is empty, as in the
+// charts some tools write, they come from the cells of the embedded
+// workbook its formula refers to.
+
+// nsChart is the URI of the graphic data of a chart frame.
+const nsChart = "http://schemas.openxmlformats.org/drawingml/2006/chart"
+
+// maxChartPoints is the most points read for a series, so that a point
+// index or a workbook range in an untrusted file cannot make the reader
+// allocate without bound.
+const maxChartPoints = 1 << 16
+
+type xmlChartVal struct {
+	Val string `xml:"val,attr"`
+}
+
+type xmlChartSpace struct {
+	Chart struct {
+		Title            *xmlChartTitle `xml:"title"`
+		AutoTitleDeleted *xmlChartVal   `xml:"autoTitleDeleted"`
+		PlotArea         struct {
+			Groups []xmlChartGroup `xml:",any"`
+		} `xml:"plotArea"`
+		Legend *struct {
+			Pos *xmlChartVal `xml:"legendPos"`
+		} `xml:"legend"`
+		DispBlanksAs *xmlChartVal `xml:"dispBlanksAs"`
+	} `xml:"chart"`
+	ExternalData *struct {
+		ID string `xml:"id,attr"`
+	} `xml:"externalData"`
+}
+
+type xmlChartTitle struct {
+	Paragraphs []struct {
+		Runs []struct {
+			Props struct {
+				Size string `xml:"sz,attr"`
+				Bold string `xml:"b,attr"`
+			} `xml:"rPr"`
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"tx>rich>p"`
+}
+
+// text returns the title's paragraphs joined by newlines.
+func (t *xmlChartTitle) text() string {
+	lines := make([]string, 0, len(t.Paragraphs))
+	for _, p := range t.Paragraphs {
+		var sb strings.Builder
+		for _, r := range p.Runs {
+			sb.WriteString(r.Text)
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// xmlChartGroup is a child of the plot area: a chart type such as
+// c:barChart with its series, or an axis.
+type xmlChartGroup struct {
+	XMLName  xml.Name
+	BarDir   *xmlChartVal   `xml:"barDir"`
+	Grouping *xmlChartVal   `xml:"grouping"`
+	GapWidth *xmlChartVal   `xml:"gapWidth"`
+	Overlap  *xmlChartVal   `xml:"overlap"`
+	HoleSize *xmlChartVal   `xml:"holeSize"`
+	Series   []xmlChartSer  `xml:"ser"`
+	Delete   *xmlChartVal   `xml:"delete"`
+	Title    *xmlChartTitle `xml:"title"`
+	Scaling  struct {
+		Orientation *xmlChartVal `xml:"orientation"`
+		Min         *xmlChartVal `xml:"min"`
+		Max         *xmlChartVal `xml:"max"`
+	} `xml:"scaling"`
+}
+
+type xmlChartSer struct {
+	Tx *struct {
+		StrRef *xmlChartRef `xml:"strRef"`
+		V      string       `xml:"v"`
+	} `xml:"tx"`
+	DLbls *struct {
+		NumFmt *struct {
+			FormatCode string `xml:"formatCode,attr"`
+		} `xml:"numFmt"`
+		ShowVal     *xmlChartVal `xml:"showVal"`
+		ShowCatName *xmlChartVal `xml:"showCatName"`
+		ShowPercent *xmlChartVal `xml:"showPercent"`
+		ShowSerName *xmlChartVal `xml:"showSerName"`
+		Separator   *string      `xml:"separator"`
+		Pos         *xmlChartVal `xml:"dLblPos"`
+	} `xml:"dLbls"`
+	Cat    *xmlChartData `xml:"cat"`
+	Val    *xmlChartData `xml:"val"`
+	XVal   *xmlChartData `xml:"xVal"`
+	YVal   *xmlChartData `xml:"yVal"`
+	Smooth *xmlChartVal  `xml:"smooth"`
+}
+
+// xmlChartData is the data of a series: a reference to workbook cells
+// with a cache of their values, or literal values.
+type xmlChartData struct {
+	StrRef *xmlChartRef   `xml:"strRef"`
+	NumRef *xmlChartRef   `xml:"numRef"`
+	StrLit *xmlChartCache `xml:"strLit"`
+	NumLit *xmlChartCache `xml:"numLit"`
+}
+
+type xmlChartRef struct {
+	F        string         `xml:"f"`
+	StrCache *xmlChartCache `xml:"strCache"`
+	NumCache *xmlChartCache `xml:"numCache"`
+}
+
+type xmlChartCache struct {
+	FormatCode string `xml:"formatCode"`
+	Points     []struct {
+		Idx int    `xml:"idx,attr"`
+		V   string `xml:"v"`
+	} `xml:"pt"`
+}
+
+// values returns the cached values by point index, "" for the points
+// missing from the cache.
+func (c *xmlChartCache) values() []string {
+	n := 0
+	for _, pt := range c.Points {
+		if pt.Idx >= 0 && pt.Idx < maxChartPoints {
+			n = max(n, pt.Idx+1)
+		}
+	}
+	vals := make([]string, n)
+	for _, pt := range c.Points {
+		if pt.Idx >= 0 && pt.Idx < n {
+			vals[pt.Idx] = pt.V
+		}
+	}
+	return vals
+}
+
+// chartPartReader reads the data of one chart part, opening its embedded
+// workbook the first time a series needs it.
+type chartPartReader struct {
+	r         *PPTXReader
+	zr        *zip.Reader
+	chartPath string
+	rels      []xmlRelForRead
+	wbLoaded  bool
+	wb        *chartWorkbook
+}
+
+// readSlideChart reads the chart or chartex part that the slide
+// relationship rID of the frame named shape points to, or returns nil when
+// there is none or it cannot be read, which is reported as a read issue.
+func (r *PPTXReader) readSlideChart(zr *zip.Reader, rels []xmlRelForRead, slidePath, shape, rID string) *ChartShape {
+	if rID == "" {
+		return nil
+	}
+	for _, rel := range rels {
+		if rel.ID != rID || rel.TargetMode == "External" {
+			continue
+		}
+		chartPath := rel.Target
+		if !strings.HasPrefix(chartPath, "ppt/") {
+			chartPath = resolveRelativePath(path.Dir(slidePath), chartPath)
+		}
+		read := r.readChart
+		if rel.Type == relTypeChartEx {
+			read = r.readChartEx
+		}
+		chart, err := read(zr, chartPath)
+		if err != nil {
+			r.logger().Warn("unreadable chart", "part", slidePath, "chart", chartPath, "error", err)
+			r.readIssue(ReadIssue{Part: slidePath, Shape: shape, Message: "unreadable chart " + chartPath + ": " + err.Error()})
+			return nil
+		}
+		return chart
+	}
+	return nil
+}
+
+// readChart reads the chart part at chartPath.
+func (r *PPTXReader) readChart(zr *zip.Reader, chartPath string) (*ChartShape, error) {
+	data, err := readFileFromZip(zr, chartPath)
+	if err != nil {
+		return nil, err
+	}
+	return r.parseChart(zr, chartPath, data)
+}
+
+// parseChart parses data, the chart part at chartPath, reading the
+// embedded workbook from zr when the caches are empty.
+func (r *PPTXReader) parseChart(zr *zip.Reader, chartPath string, data []byte) (*ChartShape, error) {
+	var cs xmlChartSpace
+	if err := xml.Unmarshal(data, &cs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", chartPath, err)
+	}
+	dir := path.Dir(chartPath)
+	rels, _ := r.readRelationships(zr, dir+"/_rels/"+path.Base(chartPath)+".rels")
+	cr := &chartPartReader{r: r, zr: zr, chartPath: chartPath, rels: rels}
+
+	chart := NewChartShape()
+	switch {
+	case cs.Chart.Title != nil:
+		chart.title.Text = cs.Chart.Title.text()
+		if ps := cs.Chart.Title.Paragraphs; len(ps) > 0 && len(ps[0].Runs) > 0 {
+			props := ps[0].Runs[0].Props
+			if sz, err := strconv.Atoi(props.Size); err == nil && sz > 0 {
+				chart.title.Font.Size = sz / 100
+			}
+			chart.title.Font.Bold = props.Bold == "1" || props.Bold == "true"
+		}
+	case cs.Chart.AutoTitleDeleted != nil && xmlBool(cs.Chart.AutoTitleDeleted.Val):
+		chart.title.Visible = false
+	}
+	if cs.Chart.Legend == nil {
+		chart.legend.Visible = false
+	} else if cs.Chart.Legend.Pos != nil {
+		chart.legend.Position = LegendPosition(cs.Chart.Legend.Pos.Val)
+	}
+	if cs.Chart.DispBlanksAs != nil {
+		chart.displayBlankAs = cs.Chart.DispBlanksAs.Val
+	}
+	if cs.ExternalData != nil && cr.workbookRel(cs.ExternalData.ID) != nil {
+		chart.dataSource = ChartDataWorkbook
+	}
+
+	var valAxes []*xmlChartGroup
+	for i := range cs.Chart.PlotArea.Groups {
+		g := &cs.Chart.PlotArea.Groups[i]
+		switch g.XMLName.Local {
+		case "catAx", "dateAx":
+			readChartAxis(chart.plotArea.axisX, g)
+			continue
+		case "valAx":
+			valAxes = append(valAxes, g)
+			continue
+		}
+		ct := cr.chartType(g)
+		if ct == nil {
+			continue
+		}
+		if chart.plotArea.chartType != nil {
+			r.logger().Warn("skipped chart type of combination chart", "part", chartPath, "type", g.XMLName.Local)
+			continue
+		}
+		chart.plotArea.chartType = ct
+	}
+	if chart.plotArea.chartType == nil {
+		return nil, fmt.Errorf("%s has no supported chart type", chartPath)
+	}
+	// A scatter chart has two value axes, X then Y.
+	if _, ok := chart.plotArea.chartType.(*ScatterChart); ok && len(valAxes) > 1 {
+		readChartAxis(chart.plotArea.axisX, valAxes[0])
+		valAxes = valAxes[1:]
+	}
+	if len(valAxes) > 0 {
+		readChartAxis(chart.plotArea.axisY, valAxes[0])
+	}
+	return chart, nil
+}
+
+// chartType returns the chart type of the plot area child g, or nil when
+// it is not one the model holds.
+func (cr *chartPartReader) chartType(g *xmlChartGroup) ChartType {
+	switch g.XMLName.Local {
+	case "barChart", "bar3DChart":
+		var bar *BarChart
+		var ct ChartType
+		if g.XMLName.Local == "bar3DChart" {
+			b3 := NewBar3DChart()
+			bar, ct = &b3.BarChart, b3
+		} else {
+			bar = NewBarChart()
+			ct = bar
+		}
+		if g.BarDir != nil {
+			bar.SetBarDirection(g.BarDir.Val)
+		}
+		if g.Grouping != nil && g.Grouping.Val != "standard" {
+			bar.SetBarGrouping(g.Grouping.Val)
+		}
+		if v, ok := chartValInt(g.GapWidth); ok {
+			bar.SetGapWidthPercent(v)
+		}
+		if v, ok := chartValInt(g.Overlap); ok {
+			bar.SetOverlapPercent(v)
+		}
+		bar.Series = cr.series(g.Series, false)
+		return ct
+	case "lineChart", "line3DChart":
+		line := NewLineChart()
+		line.Series = cr.series(g.Series, false)
+		return line
+	case "areaChart", "area3DChart":
+		area := NewAreaChart()
+		area.Series = cr.series(g.Series, false)
+		return area
+	case "pieChart", "ofPieChart":
+		pie := NewPieChart()
+		pie.Series = cr.series(g.Series, false)
+		return pie
+	case "pie3DChart":
+		pie := NewPie3DChart()
+		pie.Series = cr.series(g.Series, false)
+		return pie
+	case "doughnutChart":
+		d := NewDoughnutChart()
+		if v, ok := chartValInt(g.HoleSize); ok {
+			d.HoleSize = v
+		}
+		d.Series = cr.series(g.Series, false)
+		return d
+	case "scatterChart":
+		sc := NewScatterChart()
+		sc.Series = cr.series(g.Series, true)
+		return sc
+	case "radarChart":
+		radar := NewRadarChart()
+		radar.Series = cr.series(g.Series, false)
+		return radar
+	}
+	return nil
+}
+
+// series reads the series of a chart type; scatter series have X and Y
+// values in place of categories and values.
+func (cr *chartPartReader) series(sers []xmlChartSer, scatter bool) []*ChartSeries {
+	out := make([]*ChartSeries, 0, len(sers))
+	for _, ser := range sers {
+		catData, valData := ser.Cat, ser.Val
+		if scatter {
+			catData, valData = ser.XVal, ser.YVal
+		}
+		vals := cr.data(valData)
+		cats := cr.data(catData)
+		if len(cats) == 0 {
+			cats = make([]string, len(vals))
+			for i := range cats {
+				cats[i] = strconv.Itoa(i + 1)
+			}
+		}
+		nums := make([]float64, len(vals))
+		for i, v := range vals {
+			nums[i], _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+		}
+
+		title := ""
+		if ser.Tx != nil {
+			title = ser.Tx.V
+			if ser.Tx.StrRef != nil {
+				if t := cr.data(&xmlChartData{StrRef: ser.Tx.StrRef}); len(t) > 0 {
+					title = t[0]
+				}
+			}
+		}
+
+		s := NewChartSeriesOrdered(title, cats, nums)
+		if valData != nil && valData.NumRef != nil && valData.NumRef.NumCache != nil {
+			if fc := valData.NumRef.NumCache.FormatCode; fc != "" && fc != "General" {
+				s.NumberFormat = fc
+			}
+		}
+		if l := ser.DLbls; l != nil {
+			if l.NumFmt != nil {
+				s.NumberFormat = l.NumFmt.FormatCode
+			}
+			s.ShowValue = l.ShowVal != nil && xmlBool(l.ShowVal.Val)
+			s.ShowCategoryName = l.ShowCatName != nil && xmlBool(l.ShowCatName.Val)
+			s.ShowPercentage = l.ShowPercent != nil && xmlBool(l.ShowPercent.Val)
+			s.ShowSeriesName = l.ShowSerName != nil && xmlBool(l.ShowSerName.Val)
+			if l.Separator != nil {
+				s.Separator = *l.Separator
+			}
+			if l.Pos != nil {
+				s.LabelPosition = l.Pos.Val
+			}
+		}
+		if ser.Smooth != nil {
+			smooth := xmlBool(ser.Smooth.Val)
+			s.Smooth = &smooth
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// data returns the values of d: the cached or literal ones, or those of
+// the workbook cells its formula refers to when the cache is empty.
+func (cr *chartPartReader) data(d *xmlChartData) []string {
+	if d == nil {
+		return nil
+	}
+	for _, lit := range []*xmlChartCache{d.StrLit, d.NumLit} {
+		if lit != nil {
+			return lit.values()
+		}
+	}
+	for _, ref := range []*xmlChartRef{d.StrRef, d.NumRef} {
+		if ref == nil {
+			continue
+		}
+		for _, cache := range []*xmlChartCache{ref.StrCache, ref.NumCache} {
+			if cache != nil && len(cache.Points) > 0 {
+				return cache.values()
+			}
+		}
+		if ref.F == "" {
+			continue
+		}
+		wb := cr.workbook()
+		if wb == nil {
+			return nil
+		}
+		vals, err := wb.rangeValues(ref.F)
+		if err != nil {
+			cr.r.logger().Warn("unreadable chart data", "part", cr.chartPath, "formula", ref.F, "error", err)
+			return nil
+		}
+		return vals
+	}
+	return nil
+}
+
+// workbookRel returns the relationship of the chart part to the embedded
+// workbook with ID id, or nil.
+func (cr *chartPartReader) workbookRel(id string) *xmlRelForRead {
+	for i, rel := range cr.rels {
+		if rel.ID == id && rel.Type == relTypePackage && rel.TargetMode != "External" {
+			return &cr.rels[i]
+		}
+	}
+	return nil
+}
+
+// workbook returns the workbook embedded in the chart, or nil when it has
+// none or it cannot be read.
+func (cr *chartPartReader) workbook() *chartWorkbook {
+	if cr.wbLoaded {
+		return cr.wb
+	}
+	cr.wbLoaded = true
+	for _, rel := range cr.rels {
+		if rel.Type != relTypePackage || rel.TargetMode == "External" {
+			continue
+		}
+		name := resolveRelativePath(path.Dir(cr.chartPath), rel.Target)
+		data, err := readFileFromZip(cr.zr, name)
+		if err == nil {
+			cr.wb, err = openChartWorkbook(data)
+		}
+		if err != nil {
+			cr.r.logger().Warn("unreadable chart workbook", "part", cr.chartPath, "workbook", name, "error", err)
+		}
+		return cr.wb
+	}
+	return nil
+}
+
+// readChartAxis reads the visibility, title, orientation and bounds of
+// the axis g into a.
+func readChartAxis(a *ChartAxis, g *xmlChartGroup) {
+	if g.Delete != nil {
+		a.Visible = !xmlBool(g.Delete.Val)
+	}
+	if g.Title != nil {
+		a.Title = g.Title.text()
+	}
+	if o := g.Scaling.Orientation; o != nil {
+		a.ReversedOrder = o.Val == "maxMin"
+	}
+	if v, ok := chartValFloat(g.Scaling.Min); ok {
+		a.SetMinBounds(v)
+	}
+	if v, ok := chartValFloat(g.Scaling.Max); ok {
+		a.SetMaxBounds(v)
+	}
+}
+
+func chartValInt(v *xmlChartVal) (int, bool) {
+	if v == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(v.Val, "%"))
+	return n, err == nil
+}
+
+func chartValFloat(v *xmlChartVal) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v.Val, 64)
+	return f, err == nil && isFinite(f)
+}
+
+// xmlBool reports whether an xsd:boolean attribute is true. An empty
+// value is the schema default of c:CT_Boolean, true.
+func xmlBool(v string) bool {
+	return v == "" || v == "1" || v == "true"
+}
+
+// --- Embedded workbooks ---
+
+// chartWorkbook is a workbook embedded in a chart, read for the values of
+// the cells the series refer to.
+type chartWorkbook struct {
+	zr *zip.Reader
+	// sheets maps the sheet names to their parts.
+	sheets  map[string]string
+	strings []string
+	// cells holds the cells of the sheets read so far, by sheet name.
+	cells map[string]map[[2]int]string
+}
+
+type xmlWorkbookText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xmlWorkbookText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+// openChartWorkbook opens the xlsx package data.
+func openChartWorkbook(data []byte) (*chartWorkbook, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	wbPath := "xl/workbook.xml"
+	if rels, err := readWorkbookRels(zr, "_rels/.rels"); err == nil {
+		for _, rel := range rels {
+			if rel.Type == relTypeOfficeDoc {
+				wbPath = strings.TrimPrefix(rel.Target, "/")
+			}
+		}
+	}
+	wbData, err := readFileFromZip(zr, wbPath)
+	if err != nil {
+		return nil, err
+	}
+	var wbXML struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(wbData, &wbXML); err != nil {
+		return nil, err
+	}
+	dir := path.Dir(wbPath)
+	rels, _ := readWorkbookRels(zr, dir+"/_rels/"+path.Base(wbPath)+".rels")
+	target := func(rel xmlRelForRead) string {
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join(dir, rel.Target)
+	}
+
+	wb := &chartWorkbook{zr: zr, sheets: map[string]string{}, cells: map[string]map[[2]int]string{}}
+	for _, s := range wbXML.Sheets {
+		for _, rel := range rels {
+			if rel.ID == s.ID {
+				wb.sheets[s.Name] = target(rel)
+			}
+		}
+	}
+	for _, rel := range rels {
+		if !strings.HasSuffix(rel.Type, "/sharedStrings") {
+			continue
+		}
+		ssData, err := readFileFromZip(zr, target(rel))
+		if err != nil {
+			return nil, err
+		}
+		var sst struct {
+			Items []xmlWorkbookText `xml:"si"`
+		}
+		if err := xml.Unmarshal(ssData, &sst); err != nil {
+			return nil, err
+		}
+		for _, si := range sst.Items {
+			wb.strings = append(wb.strings, si.String())
+		}
+	}
+	return wb, nil
+}
+
+func readWorkbookRels(zr *zip.Reader, name string) ([]xmlRelForRead, error) {
+	data, err := readFileFromZip(zr, name)
+	if err != nil {
+		return nil, err
+	}
+	var rels struct {
+		Rels []xmlRelForRead `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, err
+	}
+	return rels.Rels, nil
+}
+
+// sheetCells returns the values of the cells of the sheet by one-based
+// row and column.
+func (wb *chartWorkbook) sheetCells(sheet string) (map[[2]int]string, error) {
+	if cells, ok := wb.cells[sheet]; ok {
+		return cells, nil
+	}
+	name, ok := wb.sheets[sheet]
+	if !ok {
+		return nil, fmt.Errorf("no sheet %q", sheet)
+	}
+	data, err := readFileFromZip(wb.zr, name)
+	if err != nil {
+		return nil, err
+	}
+	var ws struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R      string          `xml:"r,attr"`
+				T      string          `xml:"t,attr"`
+				V      string          `xml:"v"`
+				Inline xmlWorkbookText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(data, &ws); err != nil {
+		return nil, err
+	}
+	cells := map[[2]int]string{}
+	row := 0
+	for _, r := range ws.Rows {
+		row++
+		if r.R > 0 {
+			row = r.R
+		}
+		col := 0
+		for _, c := range r.Cells {
+			col++
+			if cr, cc, ok := parseCellRef(c.R); ok {
+				row, col = cr, cc
+			}
+			v := c.V
+			switch c.T {
+			case "s":
+				if i, err := strconv.Atoi(v); err == nil && i >= 0 && i < len(wb.strings) {
+					v = wb.strings[i]
+				}
+			case "inlineStr":
+				v = c.Inline.String()
+			}
+			cells[[2]int{row, col}] = v
+		}
+	}
+	wb.cells[sheet] = cells
+	return cells, nil
+}
+
+// rangeValues returns the values of the cells of the formula f, a cell or
+// a range of one sheet such as Sheet1!$B$2:$B$5, row by row.
+func (wb *chartWorkbook) rangeValues(f string) ([]string, error) {
+	f = strings.TrimSpace(f)
+	f = strings.TrimSuffix(strings.TrimPrefix(f, "("), ")")
+	bang := strings.LastIndex(f, "!")
+	if bang < 0 {
+		return nil, fmt.Errorf("no sheet in %q", f)
+	}
+	sheet, area := f[:bang], f[bang+1:]
+	if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") && len(sheet) >= 2 {
+		sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+	}
+	if i := strings.Index(sheet, "]"); strings.HasPrefix(sheet, "[") && i > 0 {
+		sheet = sheet[i+1:]
+	}
+	first, last, _ := strings.Cut(area, ":")
+	if last == "" {
+		last = first
+	}
+	r0, c0, ok0 := parseCellRef(first)
+	r1, c1, ok1 := parseCellRef(last)
+	if !ok0 || !ok1 {
+		return nil, fmt.Errorf("unsupported range %q", area)
+	}
+	r0, r1 = min(r0, r1), max(r0, r1)
+	c0, c1 = min(c0, c1), max(c0, c1)
+	if (r1-r0+1)*(c1-c0+1) > maxChartPoints {
+		return nil, fmt.Errorf("range %q has more than %d cells", area, maxChartPoints)
+	}
+	cells, err := wb.sheetCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]string, 0, (r1-r0+1)*(c1-c0+1))
+	for row := r0; row <= r1; row++ {
+		for col := c0; col <= c1; col++ {
+			vals = append(vals, cells[[2]int{row, col}])
+		}
+	}
+	return vals, nil
+}
+
+// parseCellRef parses a cell reference such as B2 or $B$2 into its
+// one-based row and column.
+func parseCellRef(ref string) (row, col int, ok bool) {
+	ref = strings.ReplaceAll(ref, "$", "")
+	i := 0
+	for i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z' {
+		col = col*26 + int(ref[i]-'A'+1)
+		i++
+		if i > 3 {
+			return 0, 0, false
+		}
+	}
+	row, err := strconv.Atoi(ref[i:])
+	if i == 0 || err != nil || row < 1 || row > 1<<20 {
+		return 0, 0, false
+	}
+	return row, col, true
+}
@@ -0,0 +1,247 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// replacePart returns the package data with the part name set to content.
+func replacePart(t *testing.T, data []byte, name string, content []byte) []byte {
+	t.Helper()
+	parts := packageParts(t, data, "")
+	parts[name] = content
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		fw, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(parts[f.Name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestChartDeck(src ChartDataSource) *Presentation {
+	p := New()
+	chart := p.GetActiveSlide().CreateChartShape()
+	chart.SetDataSource(src)
+	chart.GetTitle().Text = "Sales"
+	bar := NewBarChart().SetBarDirection(BarDirectionHorizontal)
+	bar.AddSeries(NewChartSeriesOrdered("North", []string{"Q1", "Q2", "Q3"}, []float64{1.5, 2, 3}))
+	bar.AddSeries(NewChartSeriesOrdered("South", []string{"Q1", "Q2", "Q3"}, []float64{4, 5, 6.25}))
+	chart.GetPlotArea().SetType(bar)
+	return p
+}
+
+// readChartOf returns the only chart of the first slide of the package data.
+func readChartOf(t *testing.T, data []byte) *ChartShape {
+	t.Helper()
+	slide, err := readPackage(t, data).GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range slide.GetShapes() {
+		if c, ok := s.(*ChartShape); ok {
+			return c
+		}
+	}
+	t.Fatal("chart was not read")
+	return nil
+}
+
+func checkTestChart(t *testing.T, chart *ChartShape) {
+	t.Helper()
+	if got := chart.GetTitle().Text; got != "Sales" {
+		t.Errorf("title = %q, want Sales", got)
+	}
+	bar, ok := chart.GetPlotArea().GetType().(*BarChart)
+	if !ok {
+		t.Fatalf("chart type = %T, want *BarChart", chart.GetPlotArea().GetType())
+	}
+	if bar.BarDirection != BarDirectionHorizontal {
+		t.Errorf("bar direction = %q, want %q", bar.BarDirection, BarDirectionHorizontal)
+	}
+	want := []struct {
+		title  string
+		values []float64
+	}{
+		{"North", []float64{1.5, 2, 3}},
+		{"South", []float64{4, 5, 6.25}},
+	}
+	if len(bar.Series) != len(want) {
+		t.Fatalf("%d series, want %d", len(bar.Series), len(want))
+	}
+	for i, w := range want {
+		s := bar.Series[i]
+		if s.Title != w.title {
+			t.Errorf("series %d title = %q, want %q", i, s.Title, w.title)
+		}
+		if !slices.Equal(s.Categories, []string{"Q1", "Q2", "Q3"}) {
+			t.Errorf("series %d categories = %q", i, s.Categories)
+		}
+		for j, cat := range s.Categories {
+			if s.Values[cat] != w.values[j] {
+				t.Errorf("series %d %s = %g, want %g", i, cat, s.Values[cat], w.values[j])
+			}
+		}
+	}
+}
+
+func TestChartRoundTrip(t *testing.T) {
+	chart := readChartOf(t, writePackage(t, newTestChartDeck(ChartDataCache)))
+	checkTestChart(t, chart)
+	if chart.GetDataSource() != ChartDataCache {
+		t.Errorf("data source = %v, want ChartDataCache", chart.GetDataSource())
+	}
+}
+
+func TestChartWorkbookFallback(t *testing.T) {
+	data := writePackage(t, newTestChartDeck(ChartDataWorkbook))
+	const name = "ppt/charts/chart1.xml"
+	part, ok := packageParts(t, data, name)[name]
+	if !ok {
+		t.Fatalf("no %s", name)
+	}
+	// Drop the cached points, as the tools that write only the workbook do.
+	stripped := regexp.MustCompile(`<c:pt idx="\d+"><c:v>[^<]*</c:v></c:pt>`).ReplaceAll(part, nil)
+	if bytes.Equal(stripped, part) {
+		t.Fatal("no cached points to strip")
+	}
+	chart := readChartOf(t, replacePart(t, data, name, stripped))
+	checkTestChart(t, chart)
+	if chart.GetDataSource() != ChartDataWorkbook {
+		t.Errorf("data source = %v, want ChartDataWorkbook", chart.GetDataSource())
+	}
+}
+
+func TestChartRangeValues(t *testing.T) {
+	xlsx, err := chartWorkbookXLSX([]*ChartSeries{
+		NewChartSeriesOrdered("A", []string{"x", "y"}, []float64{1, 2}),
+	}, []string{"x", "y"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wb, err := openChartWorkbook(xlsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		f    string
+		want []string
+	}{
+		{"Sheet1!$B$1", []string{"A"}},
+		{"Sheet1!$A$2:$A$3", []string{"x", "y"}},
+		{"'Sheet1'!B2:B3", []string{"1", "2"}},
+		{"(Sheet1!$A$2:$B$2)", []string{"x", "1"}},
+	}
+	for _, tt := range tests {
+		got, err := wb.rangeValues(tt.f)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("rangeValues(%q) = %q, %v, want %q", tt.f, got, err, tt.want)
+		}
+	}
+	for _, f := range []string{"Sheet2!$A$1", "$A$1", "Sheet1!$A$1:$A$1048576"} {
+		if _, err := wb.rangeValues(f); err == nil {
+			t.Errorf("rangeValues(%q) succeeded", f)
+		}
+	}
+}
+
+func TestChartExRoundTrip(t *testing.T) {
+	waterfall := NewWaterfallChart().SetSubtotals(3).SetConnectorLines(false).AddSeries(
+		NewChartSeriesOrdered("Cash", []string{"Start", "In", "Out", "End"}, []float64{100, 40, -30, 110}))
+	funnel := NewFunnelChart().SetGapWidthPercent(20).AddSeries(
+		NewChartSeriesOrdered("Leads", []string{"Visits", "Trials", "Sales"}, []float64{1000, 200, 50}))
+
+	for _, ct := range []ChartType{waterfall, funnel} {
+		p := New()
+		chart := p.GetActiveSlide().CreateChartShape()
+		chart.GetTitle().SetText("Flow")
+		chart.GetPlotArea().SetType(ct)
+		data := writePackage(t, p)
+
+		read := readPackage(t, data)
+		slide, err := read.GetSlide(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shapes := slide.GetShapes(); len(shapes) != 1 {
+			t.Fatalf("%s: shapes = %v, want the chart alone, without its fallback", ct.GetChartTypeName(), shapes)
+		}
+		if issues := read.GetReadIssues(); len(issues) != 0 {
+			t.Errorf("%s: read issues = %v", ct.GetChartTypeName(), issues)
+		}
+		got := readChartOf(t, data)
+		if got.GetTitle().Text != "Flow" {
+			t.Errorf("%s: title = %q", ct.GetChartTypeName(), got.GetTitle().Text)
+		}
+		switch want := ct.(type) {
+		case *WaterfallChart:
+			wf, ok := got.GetPlotArea().GetType().(*WaterfallChart)
+			if !ok {
+				t.Fatalf("chart type = %T, want *WaterfallChart", got.GetPlotArea().GetType())
+			}
+			if wf.ConnectorLines || len(wf.Subtotals) != 1 || wf.Subtotals[0] != 3 {
+				t.Errorf("waterfall = %+v, want %+v", wf, want)
+			}
+		case *FunnelChart:
+			f, ok := got.GetPlotArea().GetType().(*FunnelChart)
+			if !ok {
+				t.Fatalf("chart type = %T, want *FunnelChart", got.GetPlotArea().GetType())
+			}
+			if f.GapWidthPercent != want.GapWidthPercent {
+				t.Errorf("gap width = %d, want %d", f.GapWidthPercent, want.GapWidthPercent)
+			}
+		}
+		want, series := getChartSeries(ct)[0], getChartSeries(got.GetPlotArea().GetType())
+		if len(series) != 1 || series[0].Title != want.Title ||
+			!slices.Equal(series[0].Categories, want.Categories) || !maps.Equal(series[0].Values, want.Values) {
+			t.Errorf("%s: series = %+v, want %+v", ct.GetChartTypeName(), series, want)
+		}
+
+		parts := packageParts(t, writePackage(t, read), "ppt/charts/chartEx")
+		if len(parts) != 1 {
+			t.Errorf("%s: chartex parts written back = %d, want 1", ct.GetChartTypeName(), len(parts))
+		}
+	}
+}
+
+func TestUnreadableChartIsReported(t *testing.T) {
+	p := New()
+	p.GetActiveSlide().CreateChartShape().GetPlotArea().SetType(NewWaterfallChart().AddSeries(
+		NewChartSeriesOrdered("S", []string{"a"}, []float64{1})))
+	data := writePackage(t, p)
+	var name string
+	for n := range packageParts(t, data, "ppt/charts/chartEx") {
+		name = n
+	}
+	data = replacePart(t, data, name, []byte("<cx:chartSpace"))
+
+	read := readPackage(t, data)
+	issues := read.GetReadIssues()
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, name) {
+		t.Errorf("read issues = %v, want the unreadable chart", issues)
+	}
+	slide, err := read.GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shapes := slide.GetShapes(); len(shapes) != 1 {
+		t.Errorf("shapes = %v, want the fallback", shapes)
+	}
+}
@@ -0,0 +1,235 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Chartex parts are read into the waterfall and funnel charts they are
+// written from: the series with their data, data labels and waterfall
+// layout, the title, the legend and the value axis.
+
+type xmlChartExSpace struct {
+	Data []struct {
+		ID     int             `xml:"id,attr"`
+		StrDim []xmlChartExDim `xml:"strDim"`
+		NumDim []xmlChartExDim `xml:"numDim"`
+	} `xml:"chartData>data"`
+	Chart struct {
+		Title *struct {
+			Text string `xml:"tx>txData>v"`
+			xmlChartTitle
+		} `xml:"title"`
+		Series []xmlChartExSeries `xml:"plotArea>plotAreaRegion>series"`
+		Axes   []struct {
+			Hidden     string `xml:"hidden,attr"`
+			CatScaling *struct {
+				GapWidth string `xml:"gapWidth,attr"`
+			} `xml:"catScaling"`
+			ValScaling *struct {
+				Min       string `xml:"min,attr"`
+				Max       string `xml:"max,attr"`
+				MajorUnit string `xml:"majorUnit,attr"`
+				MinorUnit string `xml:"minorUnit,attr"`
+			} `xml:"valScaling"`
+			MajorGridlines *struct{} `xml:"majorGridlines"`
+		} `xml:"plotArea>axis"`
+		Legend *struct {
+			Pos string `xml:"pos,attr"`
+		} `xml:"legend"`
+	} `xml:"chart"`
+}
+
+// xmlChartExDim is a dimension of a chartex data block: the categories
+// (strDim) or the values (numDim).
+type xmlChartExDim struct {
+	Type string `xml:"type,attr"`
+	Lvl  []struct {
+		FormatCode string `xml:"formatCode,attr"`
+		Points     []struct {
+			Idx int    `xml:"idx,attr"`
+			V   string `xml:",chardata"`
+		} `xml:"pt"`
+	} `xml:"lvl"`
+}
+
+// values returns the points of the first level by index, "" for the
+// points missing from it, as xmlChartCache.values does.
+func (d *xmlChartExDim) values() []string {
+	if len(d.Lvl) == 0 {
+		return nil
+	}
+	n := 0
+	for _, pt := range d.Lvl[0].Points {
+		if pt.Idx >= 0 && pt.Idx < maxChartPoints {
+			n = max(n, pt.Idx+1)
+		}
+	}
+	vals := make([]string, n)
+	for _, pt := range d.Lvl[0].Points {
+		if pt.Idx >= 0 && pt.Idx < n {
+			vals[pt.Idx] = pt.V
+		}
+	}
+	return vals
+}
+
+type xmlChartExSeries struct {
+	LayoutID   string `xml:"layoutId,attr"`
+	Title      string `xml:"tx>txData>v"`
+	DataLabels *struct {
+		Pos    string `xml:"pos,attr"`
+		NumFmt *struct {
+			FormatCode string `xml:"formatCode,attr"`
+		} `xml:"numFmt"`
+		Visibility struct {
+			SeriesName   string `xml:"seriesName,attr"`
+			CategoryName string `xml:"categoryName,attr"`
+			Value        string `xml:"value,attr"`
+		} `xml:"visibility"`
+	} `xml:"dataLabels"`
+	DataID struct {
+		Val int `xml:"val,attr"`
+	} `xml:"dataId"`
+	LayoutPr *struct {
+		Visibility *struct {
+			ConnectorLines string `xml:"connectorLines,attr"`
+		} `xml:"visibility"`
+		Subtotals []struct {
+			Val int `xml:"val,attr"`
+		} `xml:"subtotals>idx"`
+	} `xml:"layoutPr"`
+}
+
+// readChartEx reads the chartex part at chartPath.
+func (r *PPTXReader) readChartEx(zr *zip.Reader, chartPath string) (*ChartShape, error) {
+	data, err := readFileFromZip(zr, chartPath)
+	if err != nil {
+		return nil, err
+	}
+	var cs xmlChartExSpace
+	if err := xml.Unmarshal(data, &cs); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", chartPath, err)
+	}
+	if len(cs.Chart.Series) == 0 {
+		return nil, fmt.Errorf("%s has no series", chartPath)
+	}
+
+	chart := NewChartShape()
+	var ct ChartType
+	switch layout := cs.Chart.Series[0].LayoutID; layout {
+	case "waterfall":
+		wf := NewWaterfallChart()
+		if lp := cs.Chart.Series[0].LayoutPr; lp != nil {
+			if lp.Visibility != nil {
+				wf.ConnectorLines = xmlBool(lp.Visibility.ConnectorLines)
+			}
+			for _, idx := range lp.Subtotals {
+				wf.Subtotals = append(wf.Subtotals, idx.Val)
+			}
+		}
+		ct = wf
+	case "funnel":
+		ct = NewFunnelChart()
+	default:
+		return nil, fmt.Errorf("%s has unsupported chart layout %q", chartPath, layout)
+	}
+
+	for _, ser := range cs.Chart.Series {
+		var cats, vals []string
+		numFmt := ""
+		for _, d := range cs.Data {
+			if d.ID != ser.DataID.Val {
+				continue
+			}
+			for i := range d.StrDim {
+				if d.StrDim[i].Type == "cat" {
+					cats = d.StrDim[i].values()
+				}
+			}
+			for i := range d.NumDim {
+				if d.NumDim[i].Type == "val" {
+					vals = d.NumDim[i].values()
+					if lvl := d.NumDim[i].Lvl; len(lvl) > 0 && lvl[0].FormatCode != "General" {
+						numFmt = lvl[0].FormatCode
+					}
+				}
+			}
+			break
+		}
+		for len(cats) < len(vals) {
+			cats = append(cats, strconv.Itoa(len(cats)+1))
+		}
+		nums := make([]float64, len(cats))
+		for i := range nums {
+			if i < len(vals) {
+				nums[i], _ = strconv.ParseFloat(strings.TrimSpace(vals[i]), 64)
+			}
+		}
+		s := NewChartSeriesOrdered(ser.Title, cats, nums)
+		s.NumberFormat = numFmt
+		if l := ser.DataLabels; l != nil {
+			if l.NumFmt != nil {
+				s.NumberFormat = l.NumFmt.FormatCode
+			}
+			s.ShowValue = l.Visibility.Value == "1" || l.Visibility.Value == "true"
+			s.ShowCategoryName = l.Visibility.CategoryName == "1" || l.Visibility.CategoryName == "true"
+			s.ShowSeriesName = l.Visibility.SeriesName == "1" || l.Visibility.SeriesName == "true"
+			s.LabelPosition = l.Pos
+		}
+		switch c := ct.(type) {
+		case *WaterfallChart:
+			c.AddSeries(s)
+		case *FunnelChart:
+			c.AddSeries(s)
+		}
+	}
+	chart.plotArea.chartType = ct
+
+	if t := cs.Chart.Title; t == nil {
+		chart.title.Visible = false
+	} else if t.Text != "" {
+		chart.title.Text = t.Text
+	} else {
+		chart.title.Text = t.text()
+	}
+	if cs.Chart.Legend == nil {
+		chart.legend.Visible = false
+	} else if cs.Chart.Legend.Pos != "" {
+		chart.legend.Position = LegendPosition(cs.Chart.Legend.Pos)
+	}
+	for _, ax := range cs.Chart.Axes {
+		if f, ok := ct.(*FunnelChart); ok && ax.CatScaling != nil {
+			if v, err := strconv.ParseFloat(ax.CatScaling.GapWidth, 64); err == nil && isFinite(v) {
+				f.SetGapWidthPercent(int(v*100 + 0.5))
+			}
+		}
+		if ax.ValScaling == nil {
+			continue
+		}
+		axY := chart.plotArea.axisY
+		axY.Visible = ax.Hidden != "1" && ax.Hidden != "true"
+		for _, b := range []struct {
+			val string
+			set func(float64) *ChartAxis
+		}{
+			{ax.ValScaling.Min, axY.SetMinBounds},
+			{ax.ValScaling.Max, axY.SetMaxBounds},
+			{ax.ValScaling.MajorUnit, axY.SetMajorUnit},
+			{ax.ValScaling.MinorUnit, axY.SetMinorUnit},
+		} {
+			if v, err := strconv.ParseFloat(b.val, 64); err == nil && isFinite(v) {
+				b.set(v)
+			}
+		}
+		if ax.MajorGridlines == nil {
+			axY.MajorGridlines = nil
+		} else if axY.MajorGridlines == nil {
+			axY.MajorGridlines = NewGridlines()
+		}
+	}
+	return chart, nil
+}
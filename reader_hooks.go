@@ -0,0 +1,152 @@
+package gopresentation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sync"
+)
+
+// ElementHandler is called for each element registered with
+// PPTXReader.HandleElement that the reader meets while parsing a slide,
+// for example to pick up a vendor payload in an extension list. Returning
+// an error stops the read.
+type ElementHandler func(ctx *ElementContext) error
+
+// ElementContext describes an element passed to an ElementHandler.
+type ElementContext struct {
+	// Name is the element's name, with its namespace URI in Name.Space.
+	Name xml.Name
+	// Raw is the element's XML as it appears in the slide part. Namespace
+	// prefixes declared on its ancestors are not repeated in it.
+	Raw []byte
+	// Slide is the slide being read.
+	Slide *Slide
+	// Shape is the shape the element belongs to, or nil for elements
+	// outside any shape and in shapes the reader does not keep. Handlers
+	// can attach what they extract with SetShapeUserData.
+	Shape Shape
+}
+
+// HandleElement registers h for the elements named local in the namespace
+// with URI space, or in any namespace when space is "". Elements inside a
+// shape are handed over once the shape has been read, so that ctx.Shape is
+// set; the reader still parses them as usual. Slides are parsed
+// concurrently, but handlers are called one at a time.
+func (r *PPTXReader) HandleElement(space, local string, h ElementHandler) {
+	if r.elementHandlers == nil {
+		r.elementHandlers = make(map[xml.Name]ElementHandler)
+	}
+	r.elementHandlers[xml.Name{Space: space, Local: local}] = h
+}
+
+// isShapeElement reports whether local names an element of the shape tree
+// that the slide reader turns into a shape.
+func isShapeElement(local string) bool {
+	switch local {
+	case "sp", "pic", "cxnSp", "graphicFrame", "grpSp":
+		return true
+	}
+	return false
+}
+
+// elementHooks runs a reader's element handlers over one slide part.
+type elementHooks struct {
+	handlers map[xml.Name]ElementHandler
+	slide    *Slide
+	data     []byte
+	depth    int
+	// mu runs the handlers of the slides parsed concurrently one at a time
+	mu *sync.Mutex
+
+	// captures are the handled elements being read, innermost last
+	captures []elementCapture
+	// shapes holds, for each shape element being read, the handler calls
+	// waiting for its shape
+	shapes [][]*ElementContext
+}
+
+type elementCapture struct {
+	name  xml.Name
+	start int64
+	depth int
+}
+
+// newElementHooks returns the hooks for a slide part holding data, or nil
+// when the reader has no handlers.
+func (r *PPTXReader) newElementHooks(slide *Slide, data []byte) *elementHooks {
+	if len(r.elementHandlers) == 0 {
+		return nil
+	}
+	hooks := &elementHooks{handlers: r.elementHandlers, slide: slide, data: data, mu: new(sync.Mutex)}
+	if r.read != nil {
+		hooks.mu = &r.read.handlerMu
+	}
+	return hooks
+}
+
+func (h *elementHooks) handler(name xml.Name) ElementHandler {
+	if fn, ok := h.handlers[name]; ok {
+		return fn
+	}
+	return h.handlers[xml.Name{Local: name.Local}]
+}
+
+// observe follows token, which spans data[start:end]. inSpTree reports
+// whether the parser is inside the shape tree.
+func (h *elementHooks) observe(token xml.Token, start, end int64, inSpTree bool) error {
+	switch t := token.(type) {
+	case xml.StartElement:
+		h.depth++
+		if inSpTree && isShapeElement(t.Name.Local) {
+			h.shapes = append(h.shapes, nil)
+		}
+		if h.handler(t.Name) != nil {
+			h.captures = append(h.captures, elementCapture{name: t.Name, start: start, depth: h.depth})
+		}
+	case xml.EndElement:
+		if n := len(h.captures); n > 0 && h.captures[n-1].depth == h.depth {
+			c := h.captures[n-1]
+			h.captures = h.captures[:n-1]
+			ctx := &ElementContext{Name: c.name, Raw: h.data[c.start:end], Slide: h.slide}
+			if n := len(h.shapes); n > 0 {
+				h.shapes[n-1] = append(h.shapes[n-1], ctx)
+			} else if err := h.call(ctx); err != nil {
+				return err
+			}
+		}
+		h.depth--
+	}
+	return nil
+}
+
+// inShape reports whether a shape element is being read.
+func (h *elementHooks) inShape() bool {
+	return len(h.shapes) > 0
+}
+
+// endShape runs the handler calls waiting for the shape element that just
+// ended, which the parser turned into s (nil when it kept no shape).
+func (h *elementHooks) endShape(s Shape) error {
+	n := len(h.shapes)
+	if n == 0 {
+		return nil
+	}
+	pending := h.shapes[n-1]
+	h.shapes = h.shapes[:n-1]
+	for _, ctx := range pending {
+		ctx.Shape = s
+		if err := h.call(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *elementHooks) call(ctx *ElementContext) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.handler(ctx.Name)(ctx); err != nil {
+		return fmt.Errorf("element handler for %s: %w", ctx.Name.Local, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package gopresentation
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrMissingMedia is returned, wrapped, by reads with the MissingMediaError
+// policy of a package referencing a media part it does not contain.
+var ErrMissingMedia = errors.New("missing media part")
+
+// ReadIssue is a problem in a package that the reader worked around, such
+// as a picture whose media part is missing.
+type ReadIssue struct {
+	Part    string // the part with the problem, such as "ppt/slides/slide2.xml"
+	Shape   string // the name of the shape concerned, if any
+	Message string
+}
+
+// String formats the issue as "part: shape: message".
+func (i ReadIssue) String() string {
+	if i.Shape != "" {
+		return fmt.Sprintf("%s: %s: %s", i.Part, i.Shape, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Part, i.Message)
+}
+
+// GetReadIssues returns the problems the read that produced the
+// presentation worked around, by part, or nil when there were none.
+func (p *Presentation) GetReadIssues() []ReadIssue {
+	return slices.Clone(p.readIssues)
+}
+
+// readIssue records issue.
+func (r *PPTXReader) readIssue(issue ReadIssue) {
+	st := r.read
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.issues = append(st.issues, issue)
+}
+
+// missingMedia records that the media part media, referenced by the shape
+// named shape of part, could not be read because of err. With the
+// MissingMediaError policy the read fails once the parts are read.
+func (r *PPTXReader) missingMedia(part, media, shape string, err error) {
+	issue := ReadIssue{Part: part, Shape: shape, Message: "missing media part " + media}
+	r.logger().Warn("missing media", "part", part, "media", media, "shape", shape, "error", err)
+	st := r.read
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.issues = append(st.issues, issue)
+	if st.mediaErr == nil && r.options().MissingMedia == MissingMediaError {
+		st.mediaErr = fmt.Errorf("%w: %s referenced by %s", ErrMissingMedia, media, part)
+	}
+}
+
+// sortedReadIssues returns the issues recorded by the read in the order of
+// their parts: slides are read concurrently, so they are recorded in any
+// order, but those of one part in document order.
+func (st *readState) sortedReadIssues() []ReadIssue {
+	if len(st.issues) == 0 {
+		return nil
+	}
+	issues := slices.Clone(st.issues)
+	// Shorter names first puts slide10.xml after slide9.xml
+	slices.SortStableFunc(issues, func(a, b ReadIssue) int {
+		return cmp.Or(cmp.Compare(len(a.Part), len(b.Part)), cmp.Compare(a.Part, b.Part))
+	})
+	return issues
+}
@@ -0,0 +1,185 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ReadStats describes the size of a presentation as read and the memory
+// the read took, for services that read many presentations to tune their
+// footprint.
+type ReadStats struct {
+	// Parts is the number of parts in the package and PartBytes their
+	// uncompressed size.
+	Parts     int
+	PartBytes int64
+	// MediaBytes is the size of the media read for pictures, picture fills
+	// and backgrounds. SharedMediaBytes is the size of the media that
+	// pictures share with an earlier picture instead of holding a copy of
+	// their own, in LowMemory mode.
+	MediaBytes       int64
+	SharedMediaBytes int64
+	// Slides, Shapes, Paragraphs and TextRuns count what was read. Shapes
+	// include the shapes inside groups; Paragraphs and TextRuns those of
+	// text shapes, table cells and notes.
+	Slides     int
+	Shapes     int
+	Paragraphs int
+	TextRuns   int
+	// InternedStrings is the number of distinct typefaces and colors that
+	// the fonts of the runs share, in LowMemory mode.
+	InternedStrings int
+	// Allocated is the number of heap bytes allocated during the read. It is
+	// measured for the whole process, so work running alongside the read
+	// adds to it.
+	Allocated uint64
+}
+
+// GetReadStats returns the statistics of the read that produced the
+// presentation, or nil for a presentation that was not read.
+func (p *Presentation) GetReadStats() *ReadStats {
+	if p.readStats == nil {
+		return nil
+	}
+	s := *p.readStats
+	return &s
+}
+
+// readState holds what the parts of one read share. Slides are parsed
+// concurrently, so mu guards media and stats while they are, and
+// handlerMu runs the element handlers one at a time.
+type readState struct {
+	mu        sync.Mutex
+	handlerMu sync.Mutex
+	// media holds the media parts read so far by part name, in LowMemory
+	// mode.
+	media map[string][]byte
+	// strings holds one instance of each interned string, in LowMemory
+	// mode.
+	strings map[string]string
+	stats   ReadStats
+	// allocated is the process's allocation count when the read started.
+	allocated uint64
+	// issues are the problems worked around so far, and mediaErr the error
+	// failing the read for missing media, if any.
+	issues   []ReadIssue
+	mediaErr error
+}
+
+// newRead returns a copy of the reader with the state of a new read, so
+// that one reader can read several packages at a time.
+func (r *PPTXReader) newRead() *PPTXReader {
+	rd := *r
+	rd.read = &readState{}
+	if rd.options().LowMemory {
+		rd.read.media = make(map[string][]byte)
+		rd.read.strings = make(map[string]string)
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	rd.read.allocated = ms.TotalAlloc
+	return &rd
+}
+
+// readMedia returns the media part name, or an error when it is missing,
+// or empty with a missing media policy other than MissingMediaPlaceholder,
+// which keeps empty media as read. In LowMemory mode pictures that use the
+// same part share its data.
+func (r *PPTXReader) readMedia(zr *zip.Reader, name string) ([]byte, error) {
+	st := r.read
+	if st == nil {
+		return readFileFromZip(zr, name)
+	}
+	st.mu.Lock()
+	data, ok := st.media[name]
+	st.mu.Unlock()
+	if !ok {
+		var err error
+		if data, err = readFileFromZip(zr, name); err != nil {
+			return nil, err
+		}
+		if len(data) == 0 && r.options().MissingMedia != MissingMediaPlaceholder {
+			return nil, fmt.Errorf("media part %s is empty", name)
+		}
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	// Another slide may have read the part in the meantime
+	if shared, found := st.media[name]; found {
+		st.stats.SharedMediaBytes += int64(len(shared))
+		return shared, nil
+	}
+	st.stats.MediaBytes += int64(len(data))
+	if st.media != nil {
+		st.media[name] = data
+	}
+	return data, nil
+}
+
+// intern returns the shared instance of s.
+func (st *readState) intern(s string) string {
+	if s == "" {
+		return s
+	}
+	if v, ok := st.strings[s]; ok {
+		return v
+	}
+	st.strings[s] = s
+	return s
+}
+
+// finishRead counts what was read into the read statistics of pres and,
+// in LowMemory mode, makes the fonts of the runs share their strings.
+func (r *PPTXReader) finishRead(zr *zip.Reader, pres *Presentation) {
+	st := r.read
+	stats := &st.stats
+	stats.Parts = len(zr.File)
+	for _, f := range zr.File {
+		stats.PartBytes += int64(f.UncompressedSize64)
+	}
+	stats.Slides = len(pres.slides)
+
+	visitParagraphs := func(paras []*Paragraph) {
+		stats.Paragraphs += len(paras)
+		for _, para := range paras {
+			for _, elem := range para.elements {
+				tr, ok := elem.(*TextRun)
+				if !ok {
+					continue
+				}
+				stats.TextRuns++
+				if st.strings != nil && tr.font != nil {
+					tr.font.Name = st.intern(tr.font.Name)
+					tr.font.NameEA = st.intern(tr.font.NameEA)
+					tr.font.Color.ARGB = st.intern(tr.font.Color.ARGB)
+					tr.font.Color.Scheme = st.intern(tr.font.Color.Scheme)
+				}
+			}
+		}
+	}
+	var visitShapes func(shapes []Shape)
+	visitShapes = func(shapes []Shape) {
+		stats.Shapes += len(shapes)
+		for _, shape := range shapes {
+			visitParagraphs(shapeParagraphs(shape))
+			if g, ok := shape.(*GroupShape); ok {
+				visitShapes(g.shapes)
+			}
+		}
+	}
+	for _, slide := range pres.slides {
+		visitShapes(slide.shapes)
+		visitParagraphs(slide.notes)
+	}
+	stats.InternedStrings = len(st.strings)
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	stats.Allocated = ms.TotalAlloc - st.allocated
+	s := *stats
+	pres.readStats = &s
+	pres.readIssues = st.sortedReadIssues()
+}
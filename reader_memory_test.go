@@ -0,0 +1,75 @@
+package gopresentation
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEmptyMediaPolicy(t *testing.T) {
+	p := New()
+	p.GetActiveSlide().CreateDrawingShape().SetImageData([]byte("\x89PNG\r\n\x1a\n"), "image/png")
+	data := writePackage(t, p)
+	media := packageParts(t, data, "ppt/media/")
+	if len(media) != 1 {
+		t.Fatalf("media parts = %d, want 1", len(media))
+	}
+	for name := range media {
+		data = replacePart(t, data, name, nil)
+	}
+
+	// The default policy keeps pictures with empty media as read.
+	for _, opts := range [][]ReaderOption{nil, {WithLowMemory()}} {
+		got, err := ReadFrom(bytes.NewReader(data), int64(len(data)), opts...)
+		if err != nil {
+			t.Fatalf("ReadFrom: %v", err)
+		}
+		if issues := got.GetReadIssues(); len(issues) != 0 {
+			t.Errorf("read issues = %v", issues)
+		}
+		slide, err := got.GetSlide(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shapes := slide.GetShapes()
+		if len(shapes) != 1 {
+			t.Fatalf("shapes = %v", shapes)
+		}
+		if d, ok := shapes[0].(*DrawingShape); !ok || len(d.GetImageData()) != 0 {
+			t.Errorf("shape = %#v, want a picture with no data", shapes[0])
+		}
+	}
+
+	_, err := ReadFrom(bytes.NewReader(data), int64(len(data)), WithMissingMediaPolicy(MissingMediaError))
+	if !errors.Is(err, ErrMissingMedia) {
+		t.Errorf("MissingMediaError read error = %v, want ErrMissingMedia", err)
+	}
+}
+
+func TestMissingMediaRewrite(t *testing.T) {
+	p := New()
+	p.GetActiveSlide().CreateDrawingShape().SetImageData([]byte("\x89PNG\r\n\x1a\n"), "image/png")
+	data := writePackage(t, p)
+	for name := range packageParts(t, data, "ppt/media/") {
+		data = removePart(t, data, name)
+	}
+
+	read := readPackage(t, data)
+	if issues := read.GetReadIssues(); len(issues) != 1 {
+		t.Fatalf("read issues = %v, want the missing media", issues)
+	}
+	out := writePackage(t, read)
+	slide := packageParts(t, out, "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	embeds := attrValues(t, slide, "blip", "embed")
+	if len(embeds) != 1 || embeds[0] == "" {
+		t.Fatalf("blip embeds = %q, want one relationship", embeds)
+	}
+	rels := string(packageParts(t, out, "ppt/slides/_rels/slide1.xml.rels")["ppt/slides/_rels/slide1.xml.rels"])
+	if !strings.Contains(rels, `Id="`+embeds[0]+`"`) || !strings.Contains(rels, "../media/image1.png") {
+		t.Errorf("slide relationships = %s, want %s to the picture", rels, embeds[0])
+	}
+	if media, ok := packageParts(t, out, "ppt/media/")["ppt/media/image1.png"]; !ok || len(media) != 0 {
+		t.Errorf("media part = %q (present %v), want an empty part", media, ok)
+	}
+}
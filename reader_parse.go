@@ -0,0 +1,52 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// ParseSlideXML parses a slide part on its own, as the reader does for
+// each slide of a package, and is meant as a fuzzing entry point for
+// untrusted input. Without the package, relationships are not resolved:
+// pictures, hyperlinks, charts and layout inheritance are left out.
+func ParseSlideXML(data []byte) (*Slide, error) {
+	if len(data) > maxZipEntrySize {
+		return nil, fmt.Errorf("slide part exceeds maximum size %d", maxZipEntrySize)
+	}
+	r := (&PPTXReader{}).newRead()
+	slide := newSlide()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	hooks := r.newElementHooks(slide, data)
+	if err := r.parseSlideXML(decoder, data, slide, nil, &zip.Reader{}, "ppt/slides/slide1.xml", newReadPresentation(), hooks); err != nil {
+		return nil, err
+	}
+	slide.setExtLst(slide.extLst)
+	slide.transition = parseSlideTransition(data)
+	return slide, nil
+}
+
+// ParseChartXML parses a chart part on its own, for fuzzing as
+// ParseSlideXML. Without the package, the embedded workbook is not read:
+// series without cached values are left empty.
+func ParseChartXML(data []byte) (*ChartShape, error) {
+	if len(data) > maxZipEntrySize {
+		return nil, fmt.Errorf("chart part exceeds maximum size %d", maxZipEntrySize)
+	}
+	return (&PPTXReader{}).newRead().parseChart(&zip.Reader{}, "ppt/charts/chart1.xml", data)
+}
+
+// ParseCommentsXML parses a comments part on its own and returns its
+// comments, for fuzzing as ParseSlideXML. Comment authors are read from
+// another part, so only their IDs are set.
+func ParseCommentsXML(data []byte) ([]*Comment, error) {
+	if len(data) > maxZipEntrySize {
+		return nil, fmt.Errorf("comments part exceeds maximum size %d", maxZipEntrySize)
+	}
+	slide := newSlide()
+	if err := (&PPTXReader{}).parseCommentsXML(data, slide); err != nil {
+		return nil, err
+	}
+	return slide.comments, nil
+}
@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"encoding/xml"
 	"fmt"
+	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -113,6 +114,16 @@ func (r *PPTXReader) readPresentation(zr *zip.Reader, pres *Presentation) ([]str
 	decoder := xml.NewDecoder(strings.NewReader(string(data)))
 	var slideRelIDs []string
 
+	// Embedded fonts: typeface of the current p:embeddedFont and the
+	// relationship ID of each style part
+	type embeddedFontRef struct {
+		typeface     string
+		bold, italic bool
+		relID        string
+	}
+	var fontRefs []embeddedFontRef
+	fontTypeface := ""
+
 	for {
 		token, err := decoder.Token()
 		if err != nil {
@@ -122,7 +133,12 @@ func (r *PPTXReader) readPresentation(zr *zip.Reader, pres *Presentation) ([]str
 		switch t := token.(type) {
 		case xml.StartElement:
 			switch t.Name.Local {
+			case "presentation":
+				if v, err := strconv.Atoi(attrValue(t.Attr, "firstSlideNum")); err == nil {
+					pres.firstSlideNumber = v
+				}
 			case "sldSz":
+				pres.layout.Name = ""
 				for _, attr := range t.Attr {
 					switch attr.Name.Local {
 					case "cx":
@@ -137,6 +153,10 @@ func (r *PPTXReader) readPresentation(zr *zip.Reader, pres *Presentation) ([]str
 						pres.layout.Name = attr.Value
 					}
 				}
+				if pres.layout.Name == "" {
+					pres.layout.Name = layoutNameForSize(pres.layout.CX, pres.layout.CY)
+				}
+				pres.layout.Portrait = pres.layout.CY > pres.layout.CX
 			case "sldId":
 				for _, attr := range t.Attr {
 					if attr.Name.Local == "id" && attr.Name.Space != "" {
@@ -145,6 +165,25 @@ func (r *PPTXReader) readPresentation(zr *zip.Reader, pres *Presentation) ([]str
 						// This is the numeric ID, not the relationship ID
 					}
 				}
+			case "embeddedFont":
+				fontTypeface = ""
+			case "font":
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "typeface" {
+						fontTypeface = attr.Value
+					}
+				}
+			case "regular", "bold", "italic", "boldItalic":
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "id" && attr.Name.Space != "" && fontTypeface != "" {
+						fontRefs = append(fontRefs, embeddedFontRef{
+							typeface: fontTypeface,
+							bold:     t.Name.Local == "bold" || t.Name.Local == "boldItalic",
+							italic:   t.Name.Local == "italic" || t.Name.Local == "boldItalic",
+							relID:    attr.Value,
+						})
+					}
+				}
 			}
 		}
 	}
@@ -161,9 +200,376 @@ func (r *PPTXReader) readPresentation(zr *zip.Reader, pres *Presentation) ([]str
 		}
 	}
 
+	if len(fontRefs) > 0 {
+		rels, err := r.readRelationships(zr, "ppt/_rels/presentation.xml.rels")
+		if err == nil {
+			for _, ref := range fontRefs {
+				for _, rel := range rels {
+					if rel.ID != ref.relID {
+						continue
+					}
+					target := rel.Target
+					if !strings.HasPrefix(target, "ppt/") {
+						target = "ppt/" + strings.TrimPrefix(target, "/")
+					}
+					if fontData, err := readFileFromZip(zr, target); err == nil {
+						pres.embeddedFonts = append(pres.embeddedFonts, &EmbeddedFont{
+							Typeface: ref.typeface,
+							Bold:     ref.bold,
+							Italic:   ref.italic,
+							Data:     fontData,
+						})
+					}
+					break
+				}
+			}
+		}
+	}
+
 	return slideRelIDs, nil
 }
 
+// --- Presentation and View Properties ---
+
+// readPresentationProperties reads the slideshow settings of presProps.xml
+// and the last view and zoom of viewProps.xml into the presentation
+// properties. Missing or malformed parts keep the defaults.
+func (r *PPTXReader) readPresentationProperties(zr *zip.Reader, presRels []xmlRelForRead, pres *Presentation) {
+	pp := pres.presentationProperties
+	for _, rel := range presRels {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		switch rel.Type {
+		case relTypePresProps:
+			if data, err := readFileFromZip(zr, resolveRelativePath("ppt", rel.Target)); err == nil {
+				parsePresProps(data, pres.themeColors, pp)
+			}
+		case relTypeViewProps:
+			if data, err := readFileFromZip(zr, resolveRelativePath("ppt", rel.Target)); err == nil {
+				parseViewProps(data, pp)
+			}
+		}
+	}
+}
+
+// parsePresProps reads the p:showPr element of a presProps part into pp,
+// resolving scheme colors with themeColors.
+func parsePresProps(data []byte, themeColors map[string]string, pp *PresentationProperties) {
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	inShowPr := false
+	var colorDst **Color // pen or laser color being read
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "showPr" {
+				inShowPr = true
+				if v := attrValue(t.Attr, "loop"); v != "" {
+					pp.loop = xmlBoolValue(v)
+				}
+				if v := attrValue(t.Attr, "showNarration"); v != "" {
+					pp.showNarration = xmlBoolValue(v)
+				}
+				if v := attrValue(t.Attr, "showAnimation"); v != "" {
+					pp.showAnimation = xmlBoolValue(v)
+				}
+				if v := attrValue(t.Attr, "useTimings"); v != "" {
+					pp.useTimings = xmlBoolValue(v)
+				}
+				continue
+			}
+			if !inShowPr {
+				continue
+			}
+			switch t.Name.Local {
+			case "present":
+				pp.slideshowType = SlideshowTypePresent
+			case "browse":
+				pp.slideshowType = SlideshowTypeBrowse
+			case "kiosk":
+				pp.slideshowType = SlideshowTypeKiosk
+			case "sldRg":
+				st, _ := strconv.Atoi(attrValue(t.Attr, "st"))
+				end, _ := strconv.Atoi(attrValue(t.Attr, "end"))
+				pp.SetSlideRange(st, end)
+			case "penClr":
+				colorDst = &pp.penColor
+			case "laserClr":
+				if t.Name.Space == nsP14 {
+					colorDst = &pp.laserColor
+				}
+			case "srgbClr", "schemeClr", "sysClr", "prstClr":
+				if colorDst != nil {
+					c := readColorElement(decoder, t, themeColors)
+					*colorDst = &c
+					colorDst = nil
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "showPr":
+				inShowPr = false
+			case "penClr", "laserClr":
+				colorDst = nil
+			}
+		}
+	}
+}
+
+// parseViewProps reads the last view and the zoom of the slide view of a
+// viewProps part into pp.
+func parseViewProps(data []byte, pp *PresentationProperties) {
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	inSlideView := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "viewPr":
+				switch attrValue(t.Attr, "lastView") {
+				case "notesView", "notesMasterView":
+					pp.lastView = ViewNotes
+				case "handoutView":
+					pp.lastView = ViewHandout
+				case "outlineView":
+					pp.lastView = ViewOutline
+				case "sldMasterView":
+					pp.lastView = ViewSlideMaster
+				case "sldSorterView":
+					pp.lastView = ViewSlideSorter
+				default:
+					pp.lastView = ViewSlide
+				}
+			case "slideViewPr":
+				inSlideView = true
+			case "sx":
+				if !inSlideView {
+					continue
+				}
+				n, errN := strconv.Atoi(attrValue(t.Attr, "n"))
+				d, errD := strconv.Atoi(attrValue(t.Attr, "d"))
+				if errN == nil && errD == nil && n > 0 && d > 0 {
+					pp.SetZoom(float64(n) / float64(d))
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "slideViewPr" {
+				inSlideView = false
+			}
+		}
+	}
+}
+
+// readColorElement reads the DrawingML color element start, whose start
+// tag the decoder has just returned, and its transforms. Scheme colors
+// take their value from themeColors. An alpha transform becomes the alpha
+// of the color, as colorXML writes it.
+func readColorElement(decoder *xml.Decoder, start xml.StartElement, themeColors map[string]string) Color {
+	val := attrValue(start.Attr, "val")
+	var c Color
+	switch start.Name.Local {
+	case "srgbClr":
+		c = NewColor(val)
+	case "sysClr":
+		c, _ = systemColor(val, attrValue(start.Attr, "lastClr"))
+	case "prstClr":
+		c = NewPresetColor(val)
+	case "schemeClr":
+		c = ColorBlack
+		if argb, ok := themeColors[val]; ok && argb != "" {
+			c = NewColor(argb)
+		}
+		c.Scheme = val
+	}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return c
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			v, err := strconv.Atoi(attrValue(t.Attr, "val"))
+			if err != nil {
+				continue
+			}
+			if t.Name.Local == "alpha" && c.nTransforms == 0 {
+				a := max(0, min(255, (v*255+50000)/100000))
+				c.ARGB = fmt.Sprintf("%02X", a) + colorRGB(c)
+			} else {
+				c = c.WithTransform(ColorTransformType(t.Name.Local), v)
+			}
+		case xml.EndElement:
+			if t.Name == start.Name {
+				return c
+			}
+		}
+	}
+}
+
+// xmlBoolValue reports whether v is an XML schema true value.
+func xmlBoolValue(v string) bool {
+	return v == "1" || v == "true"
+}
+
+// --- Slide Masters ---
+
+// readDocumentType sets the document type of pres from the content type
+// of its presentation part, and keeps the VBA project of macro-enabled
+// presentations.
+func (r *PPTXReader) readDocumentType(zr *zip.Reader, presRels []xmlRelForRead, pres *Presentation) {
+	data, err := readFileFromZip(zr, "[Content_Types].xml")
+	if err != nil {
+		return
+	}
+	var types xmlContentTypes
+	if xml.Unmarshal(data, &types) != nil {
+		return
+	}
+	for _, o := range types.Overrides {
+		if strings.EqualFold(o.PartName, "/ppt/presentation.xml") {
+			if t, ok := documentTypeForContentType(o.ContentType); ok {
+				pres.documentType = t
+			}
+		}
+	}
+	if !pres.GetDocumentType().IsMacroEnabled() {
+		return
+	}
+	for _, rel := range presRels {
+		if rel.Type == relTypeVBAProject && rel.TargetMode != "External" {
+			pres.vbaProject = r.readVBAProject(zr, resolveRelativePath("ppt", rel.Target), &types)
+		}
+	}
+}
+
+// readVBAProject reads the VBA project part name and the parts it relates
+// to, or returns nil when the project cannot be read.
+func (r *PPTXReader) readVBAProject(zr *zip.Reader, name string, types *xmlContentTypes) *VBAProject {
+	data, err := readFileFromZip(zr, name)
+	if err != nil {
+		return nil
+	}
+	vba := &VBAProject{data: data}
+	dir := path.Dir(name)
+	rels, _ := r.readRelationships(zr, dir+"/_rels/"+path.Base(name)+".rels")
+	for _, rel := range rels {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		partName := resolveRelativePath(dir, rel.Target)
+		partData, err := readFileFromZip(zr, partName)
+		if err != nil {
+			continue
+		}
+		vba.parts = append(vba.parts, vbaPart{
+			// Related parts are written next to ppt/vbaProject.bin
+			name:        "ppt/" + path.Base(partName),
+			target:      path.Base(partName),
+			relType:     rel.Type,
+			contentType: types.contentTypeOf(partName),
+			data:        partData,
+		})
+	}
+	return vba
+}
+
+// contentTypeOf returns the content type of the part name, from its
+// override or the default for its extension.
+func (t *xmlContentTypes) contentTypeOf(name string) string {
+	for _, o := range t.Overrides {
+		if strings.EqualFold(strings.TrimPrefix(o.PartName, "/"), name) {
+			return o.ContentType
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(name), ".")
+	for _, d := range t.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return d.ContentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// readSlideMasters reads the slide masters listed in the presentation
+// relationships together with the names and types of their layouts and the
+// themes they use. Themes shared by several masters are read once.
+func (r *PPTXReader) readSlideMasters(zr *zip.Reader, presRels []xmlRelForRead, pres *Presentation) {
+	themes := make(map[string]*Theme)
+	for _, rel := range presRels {
+		if rel.Type != relTypeSlideMaster {
+			continue
+		}
+		masterPath := resolveRelativePath("ppt", rel.Target)
+		data, err := readFileFromZip(zr, masterPath)
+		if err != nil {
+			continue
+		}
+		master := &SlideMaster{Name: cSldName(data)}
+
+		dir := strings.TrimSuffix(masterPath, "/"+lastPathComponent(masterPath))
+		masterRels, _ := r.readRelationships(zr, dir+"/_rels/"+lastPathComponent(masterPath)+".rels")
+		for _, mrel := range masterRels {
+			target := resolveRelativePath(dir, mrel.Target)
+			switch mrel.Type {
+			case relTypeSlideLayout:
+				layoutData, err := readFileFromZip(zr, target)
+				if err != nil {
+					continue
+				}
+				layout := &SlideLayout{Name: cSldName(layoutData)}
+				decoder := xml.NewDecoder(strings.NewReader(string(layoutData)))
+				for {
+					token, err := decoder.Token()
+					if err != nil {
+						break
+					}
+					if t, ok := token.(xml.StartElement); ok && t.Name.Local == "sldLayout" {
+						layout.Type = attrValue(t.Attr, "type")
+						break
+					}
+				}
+				master.SlideLayouts = append(master.SlideLayouts, layout)
+			case relTypeTheme:
+				theme, ok := themes[target]
+				if !ok {
+					themeData, err := readFileFromZip(zr, target)
+					if err != nil {
+						continue
+					}
+					theme = parseThemeXML(themeData)
+					themes[target] = theme
+					pres.themes = append(pres.themes, theme)
+				}
+				master.Theme = theme
+			}
+		}
+		pres.slideMasters = append(pres.slideMasters, master)
+	}
+}
+
+// cSldName returns the name attribute of the p:cSld element of a slide,
+// layout or master part.
+func cSldName(data []byte) string {
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if t, ok := token.(xml.StartElement); ok && t.Name.Local == "cSld" {
+			return attrValue(t.Attr, "name")
+		}
+	}
+}
+
 // --- Theme Colors ---
 
 // readThemeColors reads the theme XML and extracts the color scheme.
@@ -211,11 +617,18 @@ func (r *PPTXReader) readThemeColors(zr *zip.Reader, pres *Presentation) {
 				}
 			case "sysClr":
 				if currentSchemeColor != "" {
+					var sysName, sysLastClr string
 					for _, attr := range t.Attr {
-						if attr.Name.Local == "lastClr" {
-							pres.themeColors[currentSchemeColor] = "FF" + strings.ToUpper(attr.Value)
+						switch attr.Name.Local {
+						case "val":
+							sysName = attr.Value
+						case "lastClr":
+							sysLastClr = attr.Value
 						}
 					}
+					if c, ok := systemColor(sysName, sysLastClr); ok {
+						pres.themeColors[currentSchemeColor] = c.ARGB
+					}
 				}
 			}
 		case xml.EndElement:
@@ -243,3 +656,239 @@ func (r *PPTXReader) readThemeColors(zr *zip.Reader, pres *Presentation) {
 		}
 	}
 }
+
+// --- Theme Format Scheme ---
+
+// themeFillStyle is a fill from the theme format scheme (fillStyleLst or
+// bgFillStyleLst). Colors marked as placeholders take the color supplied by
+// the referencing element (phClr).
+type themeFillStyle struct {
+	fillType FillType
+	stops    []themeStyleColor
+	angle    int // linear gradient angle in degrees
+	picture  []byte
+	mimeType string
+	tile     *FillTile
+}
+
+// themeStyleColor is a color inside a theme fill style.
+type themeStyleColor struct {
+	placeholder bool
+	color       Color
+}
+
+// masterThemePath returns the name of the theme part of the first slide
+// master, or "" when there is none.
+func (r *PPTXReader) masterThemePath(zr *zip.Reader) string {
+	presRels, _ := r.readRelationships(zr, "ppt/_rels/presentation.xml.rels")
+	for _, rel := range presRels {
+		if rel.Type != relTypeSlideMaster || rel.TargetMode == "External" {
+			continue
+		}
+		masterPath := resolveRelativePath("ppt", rel.Target)
+		dir := strings.TrimSuffix(masterPath, "/"+lastPathComponent(masterPath))
+		masterRels, _ := r.readRelationships(zr, dir+"/_rels/"+lastPathComponent(masterPath)+".rels")
+		for _, mrel := range masterRels {
+			if mrel.Type == relTypeTheme && mrel.TargetMode != "External" {
+				return resolveRelativePath(dir, mrel.Target)
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// readThemeFormatScheme reads the fill and background fill styles of the theme,
+// used to resolve style references such as p:bgRef.
+func (r *PPTXReader) readThemeFormatScheme(zr *zip.Reader, pres *Presentation) {
+	themePath := r.masterThemePath(zr)
+	if themePath == "" {
+		return
+	}
+	data, err := readFileFromZip(zr, themePath)
+	if err != nil {
+		return
+	}
+	rels, _ := r.readRelationships(zr, strings.Replace(themePath, "theme/", "theme/_rels/", 1)+".rels")
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	var list *[]*themeFillStyle
+	var current *themeFillStyle
+	var lastStyleColor *themeStyleColor
+	depth := 0 // element depth below the style list
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if list == nil {
+				switch t.Name.Local {
+				case "fillStyleLst":
+					list = &pres.themeFillStyles
+				case "bgFillStyleLst":
+					list = &pres.themeBgFillStyles
+				}
+				depth = 0
+				continue
+			}
+			depth++
+			if depth == 1 {
+				current = &themeFillStyle{}
+				*list = append(*list, current)
+				switch t.Name.Local {
+				case "solidFill":
+					current.fillType = FillSolid
+				case "gradFill":
+					current.fillType = FillGradientLinear
+				case "blipFill":
+					current.fillType = FillPicture
+				default:
+					current.fillType = FillNone
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "srgbClr", "schemeClr", "sysClr", "prstClr":
+				sc := themeStyleColor{color: ColorBlack}
+				var sysName, sysLastClr string
+				for _, attr := range t.Attr {
+					switch {
+					case t.Name.Local == "srgbClr" && attr.Name.Local == "val":
+						sc.color = NewColor("FF" + attr.Value)
+					case t.Name.Local == "sysClr" && attr.Name.Local == "val":
+						sysName = attr.Value
+					case t.Name.Local == "sysClr" && attr.Name.Local == "lastClr":
+						sysLastClr = attr.Value
+					case t.Name.Local == "prstClr" && attr.Name.Local == "val":
+						sc.color = NewPresetColor(attr.Value)
+					case t.Name.Local == "schemeClr" && attr.Name.Local == "val":
+						if attr.Value == "phClr" {
+							sc.placeholder = true
+						} else if argb, ok := pres.themeColors[attr.Value]; ok && argb != "" {
+							sc.color = NewColor(argb)
+						}
+					}
+				}
+				if t.Name.Local == "sysClr" {
+					sc.color, _ = systemColor(sysName, sysLastClr)
+				}
+				current.stops = append(current.stops, sc)
+				lastStyleColor = &current.stops[len(current.stops)-1]
+			case "lumMod", "lumOff", "tint", "shade", "satMod", "alpha":
+				if lastStyleColor != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "val" {
+							if v, err := strconv.Atoi(attr.Value); err == nil {
+								lastStyleColor.color = lastStyleColor.color.WithTransform(ColorTransformType(t.Name.Local), v)
+							}
+						}
+					}
+				}
+			case "lin":
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "ang" {
+						if v, err := strconv.Atoi(attr.Value); err == nil {
+							current.angle = v / 60000
+						}
+					}
+				}
+			case "tile":
+				current.tile = parseFillTile(t.Attr)
+			case "blip":
+				for _, attr := range t.Attr {
+					if attr.Name.Local != "embed" {
+						continue
+					}
+					for _, rel := range rels {
+						if rel.ID == attr.Value {
+							imgPath := rel.Target
+							if !strings.HasPrefix(imgPath, "ppt/") {
+								imgPath = resolveRelativePath("ppt/theme", imgPath)
+							}
+							if img, err := r.readMedia(zr, imgPath); err == nil {
+								current.picture = img
+								current.mimeType = guessMimeType(imgPath)
+							} else {
+								r.missingMedia(themePath, imgPath, "", err)
+							}
+							break
+						}
+					}
+				}
+			}
+		case xml.EndElement:
+			if list == nil {
+				if t.Name.Local == "fmtScheme" {
+					return
+				}
+				continue
+			}
+			if depth == 0 {
+				list = nil
+				continue
+			}
+			switch t.Name.Local {
+			case "srgbClr", "schemeClr", "sysClr", "prstClr":
+				lastStyleColor = nil
+			}
+			depth--
+		}
+	}
+}
+
+// resolve builds a concrete fill, substituting phClr with the given color.
+func (s *themeFillStyle) resolve(phClr Color) *Fill {
+	colorAt := func(i int) Color {
+		sc := s.stops[i]
+		if !sc.placeholder {
+			return sc.color
+		}
+		c := phClr
+		for _, t := range sc.color.Transforms() {
+			c = c.WithTransform(t.Type, t.Value)
+		}
+		return c
+	}
+	switch s.fillType {
+	case FillSolid:
+		if len(s.stops) == 0 {
+			return NewFill().SetSolid(phClr)
+		}
+		return NewFill().SetSolid(colorAt(0))
+	case FillGradientLinear:
+		if len(s.stops) == 0 {
+			return NewFill().SetSolid(phClr)
+		}
+		return NewFill().SetGradientLinear(colorAt(0), colorAt(len(s.stops)-1), s.angle)
+	case FillPicture:
+		if len(s.picture) == 0 {
+			return nil
+		}
+		return NewFill().SetPicture(s.picture, s.mimeType).SetTile(s.tile)
+	}
+	return nil
+}
+
+// resolveBackgroundRef resolves a p:bgRef against the theme format scheme.
+// Indexes 1001 and up select background fill styles; 1–999 select fill styles.
+// Without theme styles the reference color is used as a solid fill, matching
+// the theme written by this library.
+func (p *Presentation) resolveBackgroundRef(ref *BackgroundRef) *Fill {
+	if ref == nil || ref.Index == 0 {
+		return nil
+	}
+	styles := p.themeFillStyles
+	idx := ref.Index - 1
+	if ref.Index >= 1001 {
+		styles = p.themeBgFillStyles
+		idx = ref.Index - 1001
+	}
+	if idx < 0 || idx >= len(styles) {
+		return NewFill().SetSolid(ref.Color)
+	}
+	return styles[idx].resolve(ref.Color)
+}
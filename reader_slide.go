@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -22,9 +23,12 @@ func (r *PPTXReader) readSlide(zr *zip.Reader, path string, pres *Presentation)
 	relsPath := strings.Replace(path, "slides/", "slides/_rels/", 1) + ".rels"
 	slideRels, _ := r.readRelationships(zr, relsPath)
 
-	if err := r.parseSlideXML(decoder, slide, slideRels, zr, path, pres); err != nil {
+	hooks := r.newElementHooks(slide, data)
+	if err := r.parseSlideXML(decoder, data, slide, slideRels, zr, path, pres, hooks); err != nil {
 		return nil, err
 	}
+	slide.setExtLst(slide.extLst)
+	slide.transition = parseSlideTransition(data)
 
 	// Apply slide layout inheritance for placeholders with missing position/size
 	r.applyLayoutInheritance(zr, slide, slideRels, path, pres)
@@ -33,11 +37,65 @@ func (r *PPTXReader) readSlide(zr *zip.Reader, path string, pres *Presentation)
 	r.readSlideComments(zr, slide, slideRels, path)
 
 	// Read notes if relationship exists
-	r.readSlideNotes(zr, slide, slideRels, path)
+	r.readSlideNotes(zr, slide, slideRels, path, pres)
 
 	return slide, nil
 }
 
+// parseSlideTransition reads the p:transition element of a slide part, or
+// returns nil when it has none. PowerPoint writes transitions of newer
+// versions inside mc:AlternateContent, with a fallback using one of the
+// original effects; the first effect known is kept.
+func parseSlideTransition(data []byte) *Transition {
+	if !bytes.Contains(data, []byte("transition")) {
+		return nil
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var t *Transition
+	inTransition := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return t
+		}
+		switch tok := token.(type) {
+		case xml.StartElement:
+			if tok.Name.Local == "cSld" {
+				_ = decoder.Skip()
+				continue
+			}
+			if tok.Name.Local == "transition" {
+				inTransition = true
+				if t == nil {
+					t = &Transition{}
+				}
+				for _, attr := range tok.Attr {
+					switch attr.Name.Local {
+					case "spd":
+						t.Speed = TransitionSpeed(attr.Value)
+					case "advTm":
+						t.AdvanceAfter, _ = strconv.Atoi(attr.Value)
+					case "dur":
+						t.Duration, _ = strconv.Atoi(attr.Value)
+					}
+				}
+				continue
+			}
+			if inTransition && t.Type == TransitionNone {
+				for typ, elem := range transitionElements {
+					if elem == tok.Name.Local {
+						t.Type = typ
+					}
+				}
+			}
+		case xml.EndElement:
+			if tok.Name.Local == "transition" {
+				inTransition = false
+			}
+		}
+	}
+}
+
 func (r *PPTXReader) readSlideComments(zr *zip.Reader, slide *Slide, rels []xmlRelForRead, slidePath string) {
 	for _, rel := range rels {
 		if rel.Type == relTypeComment {
@@ -50,20 +108,26 @@ func (r *PPTXReader) readSlideComments(zr *zip.Reader, slide *Slide, rels []xmlR
 			if err != nil {
 				continue
 			}
-			r.parseCommentsXML(data, slide)
+			// Keep the comments read before any error
+			_ = r.parseCommentsXML(data, slide)
 		}
 	}
 }
 
-func (r *PPTXReader) parseCommentsXML(data []byte, slide *Slide) {
+// parseCommentsXML adds the comments of a comments part to slide. It
+// returns the error that stopped the parse, if the part is not well-formed.
+func (r *PPTXReader) parseCommentsXML(data []byte, slide *Slide) error {
 	decoder := xml.NewDecoder(bytes.NewReader(data))
 	var currentComment *Comment
 	var inText bool
 
 	for {
 		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
-			break
+			return err
 		}
 		switch t := token.(type) {
 		case xml.StartElement:
@@ -116,7 +180,7 @@ func (r *PPTXReader) parseCommentsXML(data []byte, slide *Slide) {
 	}
 }
 
-func (r *PPTXReader) readSlideNotes(zr *zip.Reader, slide *Slide, rels []xmlRelForRead, slidePath string) {
+func (r *PPTXReader) readSlideNotes(zr *zip.Reader, slide *Slide, rels []xmlRelForRead, slidePath string, pres *Presentation) {
 	for _, rel := range rels {
 		if rel.Type == relTypeNotesSlide {
 			target := rel.Target
@@ -124,11 +188,7 @@ func (r *PPTXReader) readSlideNotes(zr *zip.Reader, slide *Slide, rels []xmlRelF
 				dir := strings.TrimSuffix(slidePath, "/"+lastPathComponent(slidePath))
 				target = resolveRelativePath(dir, target)
 			}
-			data, err := readFileFromZip(zr, target)
-			if err != nil {
-				continue
-			}
-			slide.notes = r.parseNotesXML(data)
+			r.readNotesSlide(zr, slide, target, slidePath, pres)
 		}
 	}
 }
@@ -184,7 +244,7 @@ func (r *PPTXReader) parseNotesXML(data []byte) string {
 	return strings.Join(texts, "")
 }
 
-func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xmlRelForRead, zr *zip.Reader, slidePath string, pres *Presentation) error {
+func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, data []byte, slide *Slide, rels []xmlRelForRead, zr *zip.Reader, slidePath string, pres *Presentation, hooks *elementHooks) error {
 	type parseState struct {
 		inSpTree       bool
 		inSp           bool
@@ -196,6 +256,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 		inParagraph    bool
 		inRun          bool
 		inRunProps     bool
+		inSlideNumFld  bool // inRun is set too
 		inText         bool
 		inTbl          bool
 		inTr           bool
@@ -216,6 +277,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 		inBg           bool
 		inBgPr         bool
 		inBgSolidFill  bool
+		inBgRef        bool
 		inBuClr        bool
 
 		// Spacing context tracking
@@ -239,6 +301,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 		// p:style / fontRef tracking
 		inStyle   bool
 		inFontRef bool
+		inLnRef   bool
 
 		// extLst tracking (to ignore hiddenFill etc.)
 		inExtLst bool
@@ -291,6 +354,30 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 	// lstStyle-level default font (from <a:lstStyle>/<a:lvl1pPr>/<a:defRPr>)
 	var lstStyleFont *Font
 
+	// runHyperlink is the hlinkClick of the run being parsed
+	var runHyperlink *Hyperlink
+
+	// lvlStyle is the list level being parsed inside <a:lstStyle>/<a:lvlNpPr>
+	var lvlStyle *ListLevelStyle
+
+	// bulletTarget returns the bullet being parsed: the current paragraph's
+	// inside pPr, or the list level style's inside lstStyle.
+	bulletTarget := func() *Bullet {
+		if state.inPPr && currentParagraph != nil {
+			if currentParagraph.bullet == nil {
+				currentParagraph.bullet = NewBullet()
+			}
+			return currentParagraph.bullet
+		}
+		if lvlStyle != nil {
+			if lvlStyle.Bullet == nil {
+				lvlStyle.Bullet = NewBullet()
+			}
+			return lvlStyle.Bullet
+		}
+		return nil
+	}
+
 	// lastColor tracks the most recently parsed srgbClr/schemeClr so that child
 	// elements like <a:alpha> can modify it.
 	var lastColor *Color
@@ -298,6 +385,24 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 	var offX, offY, extCX, extCY int64
 	var chOffX, chOffY, chExtCX, chExtCY int64
 	var shapeName, shapeDescr string
+	var shapeLocks *ShapeLocks
+	// shapeTags holds the tags of the shape being parsed until it is added
+	var shapeTags map[string]string
+	// shapeExt holds the extension lists of the shape being parsed until it
+	// is added
+	var shapeExt extLsts
+	// nvElem is the non-visual properties element being parsed, "cNvPr" or
+	// "nvPr"
+	var nvElem string
+	// depth is the element depth of the current token. extDepth is the depth
+	// of the extension list being kept, starting at extStart, and extSite
+	// where it is stored, or 0 when none is.
+	var depth, extDepth int
+	var extStart int64
+	var extSite *string
+	var extPrefix string
+	var shapeScene *Scene3D
+	var inCamera bool
 	var flipH, flipV bool
 	var shapeRotation int
 	var prstGeom string
@@ -307,6 +412,17 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 	// Font color from <p:style>/<a:fontRef>/<a:schemeClr> (default text color for shape)
 	var fontRefColor *Color
 
+	// Connector line color from <p:style>/<a:lnRef>, used when its <a:ln>
+	// has no color of its own
+	var lnRefColor *Color
+	var lineColorSet bool
+
+	// Kind of the graphic frame being read, to report frames other than
+	// tables and charts, which are skipped
+	var graphicURI string
+	// Relationship ID of the chart part of the graphic frame being read
+	var chartRID string
+
 	// Deferred shape-level fill (spPr solidFill comes before txBody)
 	var pendingShapeFill *Fill
 
@@ -333,11 +449,11 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 	var pendingBlipFillData []byte
 	var pendingBlipFillMime string
 
-	// Background blipFill image data (bgPr blipFill)
-	// TODO: use these to set slide.background as an image fill
-	var bgBlipFillData []byte
-	var bgBlipFillMime string
-	_, _ = bgBlipFillData, bgBlipFillMime
+	// Background picture fill (bgPr blipFill)
+	var bgPictureFill *Fill
+
+	// Background style reference (p:bgRef)
+	var pendingBgRef *BackgroundRef
 
 	// Group shape nesting
 	grpDepth := 0
@@ -347,6 +463,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 		group    *GroupShape
 		name     string
 		descr    string
+		tags     map[string]string
+		ext      extLsts
 		offX     int64
 		offY     int64
 		extCX    int64
@@ -362,17 +480,132 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 	}
 	var grpStack []*grpSaved
 
+	// Markup compatibility content: the fallback is skipped when shapes
+	// were read from the choice, such as the frame of a chartex chart.
+	mcChoiceStart := -1 // the shapes read before the current mc:Choice
+	mcChoiceRead := false
+	mcSkipDepth := 0
+	shapesRead := func() int {
+		n := len(slide.shapes)
+		if currentGroup != nil {
+			n += len(currentGroup.shapes)
+		}
+		return n
+	}
+
 	for {
+		tokenStart := decoder.InputOffset()
 		token, err := decoder.Token()
 		if err != nil {
 			break
 		}
 
+		if mcSkipDepth > 0 {
+			switch token.(type) {
+			case xml.StartElement:
+				mcSkipDepth++
+			case xml.EndElement:
+				mcSkipDepth--
+			}
+			continue
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space == nsMarkupCompat {
+				switch t.Name.Local {
+				case "AlternateContent":
+					mcChoiceRead = false
+				case "Choice":
+					mcChoiceStart = shapesRead()
+				case "Fallback":
+					if mcChoiceRead {
+						mcSkipDepth = 1
+						continue
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space == nsMarkupCompat && t.Name.Local == "Choice" && mcChoiceStart >= 0 {
+				mcChoiceRead = mcChoiceRead || shapesRead() > mcChoiceStart
+				mcChoiceStart = -1
+			}
+		}
+
+		// Element handlers see each token first. When a shape element ends,
+		// remember where its shape will be added so that handlers waiting
+		// for it, and the tags and extension lists read for it, can be
+		// given the shape afterwards.
+		var shapeDest *[]Shape
+		var shapeDestLen int
+		if end, ok := token.(xml.EndElement); ok && isShapeElement(end.Name.Local) && (shapeTags != nil || shapeExt != extLsts{} || hooks != nil && hooks.inShape()) {
+			shapeDest = &slide.shapes
+			parent := currentGroup
+			if end.Name.Local == "grpSp" {
+				parent = nil
+				if n := len(grpStack); n >= 2 {
+					parent = grpStack[n-2].group
+				}
+			}
+			if state.inGrpSp && parent != nil {
+				shapeDest = &parent.shapes
+			}
+			shapeDestLen = len(*shapeDest)
+		}
+		if hooks != nil {
+			if err := hooks.observe(token, tokenStart, decoder.InputOffset(), state.inSpTree); err != nil {
+				return err
+			}
+		}
+
+		// Keep the extension lists of the slide and of the non-visual
+		// properties of shapes.
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case t.Name.Local == "cNvPr" || t.Name.Local == "nvPr":
+				if state.inNvSpPr {
+					nvElem = t.Name.Local
+				}
+			case t.Name.Local == "extLst" && extDepth == 0:
+				extSite = nil
+				if depth == 2 {
+					extSite, extPrefix = &slide.extLst, "p"
+				} else if state.inNvSpPr && nvElem == "cNvPr" {
+					extSite, extPrefix = &shapeExt.cNvPr, "a"
+				} else if state.inNvSpPr && nvElem == "nvPr" {
+					extSite, extPrefix = &shapeExt.nvPr, "p"
+				}
+				if extSite != nil {
+					extDepth, extStart = depth, tokenStart
+				}
+			}
+		case xml.EndElement:
+			if depth == extDepth {
+				*extSite = keepExtLst(data[extStart:decoder.InputOffset()], extPrefix)
+				extDepth = 0
+			}
+			if t.Name.Local == nvElem {
+				nvElem = ""
+			}
+			depth--
+		}
+
 		switch t := token.(type) {
 		case xml.StartElement:
 			switch t.Name.Local {
 			case "bg":
 				state.inBg = true
+			case "bgRef":
+				if state.inBg {
+					state.inBgRef = true
+					pendingBgRef = &BackgroundRef{Color: ColorWhite}
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "idx" {
+							pendingBgRef.Index, _ = strconv.Atoi(attr.Value)
+						}
+					}
+				}
 			case "bgPr":
 				if state.inBg {
 					state.inBgPr = true
@@ -381,6 +614,9 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				state.inSpTree = true
 			case "grpSp":
 				if state.inSpTree {
+					if len(grpStack) >= maxGroupDepth {
+						return fmt.Errorf("group shapes nested deeper than %d", maxGroupDepth)
+					}
 					state.inGrpSp = true
 					grpDepth++
 					newGroup := NewGroupShape()
@@ -389,7 +625,11 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					offX, offY, extCX, extCY = 0, 0, 0, 0
 					chOffX, chOffY, chExtCX, chExtCY = 0, 0, 0, 0
 					shapeName = ""
+					shapeTags = nil
+					shapeExt = extLsts{}
 					shapeDescr = ""
+					shapeLocks = nil
+					shapeScene = nil
 					prstGeom = ""
 					shapeRotation = 0
 					flipH, flipV = false, false
@@ -404,7 +644,11 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					state.phIdx = 0
 					offX, offY, extCX, extCY = 0, 0, 0, 0
 					shapeName = ""
+					shapeTags = nil
+					shapeExt = extLsts{}
 					shapeDescr = ""
+					shapeLocks = nil
+					shapeScene = nil
 					prstGeom = ""
 					shapeRotation = 0
 					textAnchor = TextAnchorNone
@@ -426,7 +670,11 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					currentDrawing = NewDrawingShape()
 					offX, offY, extCX, extCY = 0, 0, 0, 0
 					shapeName = ""
+					shapeTags = nil
+					shapeExt = extLsts{}
 					shapeDescr = ""
+					shapeLocks = nil
+					shapeScene = nil
 					prstGeom = ""
 					shapeRotation = 0
 				}
@@ -436,25 +684,60 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					currentLine = NewLineShape()
 					offX, offY, extCX, extCY = 0, 0, 0, 0
 					shapeName = ""
+					shapeTags = nil
+					shapeExt = extLsts{}
+					shapeLocks = nil
+					shapeScene = nil
 					prstGeom = ""
 					shapeRotation = 0
 					pendingCustomPath = nil
+					lnRefColor = nil
+					lineColorSet = false
 				}
 			case "graphicFrame":
 				if state.inSpTree {
 					state.inGraphicFrame = true
 					offX, offY, extCX, extCY = 0, 0, 0, 0
 					shapeName = ""
+					shapeTags = nil
+					shapeExt = extLsts{}
+					shapeLocks = nil
 					prstGeom = ""
 					shapeRotation = 0
+					graphicURI = ""
+					chartRID = ""
+				}
+			case "graphicData":
+				if state.inGraphicFrame {
+					graphicURI = attrValue(t.Attr, "uri")
+				}
+			case "chart":
+				if state.inGraphicFrame && (graphicURI == nsChart || graphicURI == nsChartEx) {
+					chartRID = attrValue(t.Attr, "id")
 				}
 			case "tbl":
 				if state.inGraphicFrame {
 					state.inTbl = true
 					currentTable = NewTableShape(0, 0)
 					currentTable.rows = nil
+					currentTable.firstRow = false
+					currentTable.bandRow = false
 					currentTableRow = -1
 				}
+			case "tblPr":
+				if state.inTbl && currentTable != nil {
+					for _, attr := range t.Attr {
+						v := attr.Value == "1" || attr.Value == "true"
+						switch attr.Name.Local {
+						case "firstRow":
+							currentTable.firstRow = v
+						case "lastRow":
+							currentTable.lastRow = v
+						case "bandRow":
+							currentTable.bandRow = v
+						}
+					}
+				}
 			case "gridCol":
 				if state.inTbl && currentTable != nil {
 					currentTable.numCols++
@@ -513,6 +796,34 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				if state.inTc && currentTable != nil && currentTableRow >= 0 && currentTableCol >= 0 &&
 					currentTableRow < len(currentTable.rows) && currentTableCol < len(currentTable.rows[currentTableRow]) {
 					state.inTcPr = true
+					cell := currentTable.rows[currentTableRow][currentTableCol]
+					for _, attr := range t.Attr {
+						switch attr.Name.Local {
+						case "vert":
+							cell.textDirection = attr.Value
+						case "anchor":
+							cell.anchor = TextAnchorType(attr.Value)
+						case "marL", "marR", "marT", "marB":
+							v, err := strconv.ParseInt(attr.Value, 10, 64)
+							if err != nil {
+								continue
+							}
+							if !cell.marginsSet {
+								cell.marginLeft, cell.marginTop, cell.marginRight, cell.marginBottom = cell.GetMargins()
+								cell.marginsSet = true
+							}
+							switch attr.Name.Local {
+							case "marL":
+								cell.marginLeft = v
+							case "marR":
+								cell.marginRight = v
+							case "marT":
+								cell.marginTop = v
+							default:
+								cell.marginBottom = v
+							}
+						}
+					}
 				}
 			case "lnL":
 				if state.inTcPr {
@@ -578,6 +889,10 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						}
 					}
 				}
+			case "hlinkClick":
+				if state.inRunProps {
+					runHyperlink = parseHyperlinkClick(t.Attr, rels, slidePath)
+				}
 			case "cNvPr":
 				if state.inNvSpPr {
 					for _, attr := range t.Attr {
@@ -589,6 +904,48 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						}
 					}
 				}
+			case "spLocks", "picLocks", "cxnSpLocks", "graphicFrameLocks":
+				if state.inNvSpPr {
+					shapeLocks = parseShapeLocks(t.Attr)
+				}
+			case "tags":
+				tags := r.readTags(zr, rels, slidePath, attrValue(t.Attr, "id"))
+				if state.inNvSpPr {
+					shapeTags = tags
+				} else if !state.inSpTree {
+					slide.tags = tags
+				}
+			case "scene3d":
+				if (state.inSp || state.inPic || state.inCxnSp) && !state.inTxBody {
+					shapeScene = &Scene3D{}
+				}
+			case "camera":
+				if shapeScene != nil {
+					inCamera = true
+					shapeScene.Camera = CameraPreset(attrValue(t.Attr, "prst"))
+				}
+			case "rot":
+				if inCamera {
+					for _, attr := range t.Attr {
+						v, err := strconv.Atoi(attr.Value)
+						if err != nil {
+							continue
+						}
+						switch attr.Name.Local {
+						case "lat":
+							shapeScene.Lat = v / 60000
+						case "lon":
+							shapeScene.Lon = v / 60000
+						case "rev":
+							shapeScene.Rev = v / 60000
+						}
+					}
+				}
+			case "lightRig":
+				if shapeScene != nil && !inCamera {
+					shapeScene.LightRig = attrValue(t.Attr, "rig")
+					shapeScene.LightDir = attrValue(t.Attr, "dir")
+				}
 			case "ph":
 				if state.inNvSpPr && state.inSp {
 					state.isPlaceholder = true
@@ -626,9 +983,42 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				if state.inTxBody {
 					state.inLstStyle = true
 				}
-			case "lvl1pPr":
+			case "lvl1pPr", "lvl2pPr", "lvl3pPr", "lvl4pPr", "lvl5pPr", "lvl6pPr", "lvl7pPr", "lvl8pPr", "lvl9pPr":
 				if state.inLstStyle {
-					state.inLstStyleLvl1 = true
+					if t.Name.Local == "lvl1pPr" {
+						state.inLstStyleLvl1 = true
+					}
+					var target *RichTextShape
+					if currentPlaceholder != nil {
+						target = &currentPlaceholder.RichTextShape
+					} else if currentRichText != nil {
+						target = currentRichText
+					}
+					if target != nil {
+						lvlStyle = NewListLevelStyle()
+						for _, attr := range t.Attr {
+							switch attr.Name.Local {
+							case "marL", "indent":
+								if v, err := strconv.ParseInt(attr.Value, 10, 64); err == nil {
+									if lvlStyle.Alignment == nil {
+										lvlStyle.Alignment = &Alignment{}
+									}
+									if attr.Name.Local == "marL" {
+										lvlStyle.Alignment.MarginLeft = v
+									} else {
+										lvlStyle.Alignment.Indent = v
+									}
+								}
+							case "algn":
+								if lvlStyle.Alignment == nil {
+									lvlStyle.Alignment = &Alignment{}
+								}
+								lvlStyle.Alignment.Horizontal = HorizontalAlignment(attr.Value)
+							}
+						}
+						level := int(t.Name.Local[3] - '0')
+						target.listStyle[level-1] = lvlStyle
+					}
 				}
 			case "bodyPr":
 				if state.inTxBody {
@@ -805,6 +1195,10 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							if v, err := strconv.ParseInt(attr.Value, 10, 64); err == nil {
 								currentParagraph.alignment.Indent = v
 							}
+						case "lvl":
+							if v, err := strconv.Atoi(attr.Value); err == nil {
+								currentParagraph.alignment.Level = v
+							}
 						}
 					}
 				}
@@ -813,56 +1207,48 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					b := NewBullet()
 					b.Type = BulletTypeNone
 					currentParagraph.bullet = b
+				} else if lvlStyle != nil {
+					b := NewBullet()
+					b.Type = BulletTypeNone
+					lvlStyle.Bullet = b
 				}
 			case "buChar":
-				if state.inPPr && currentParagraph != nil {
-					if currentParagraph.bullet == nil {
-						currentParagraph.bullet = NewBullet()
-					}
-					currentParagraph.bullet.Type = BulletTypeChar
+				if b := bulletTarget(); b != nil {
+					b.Type = BulletTypeChar
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "char" {
-							currentParagraph.bullet.Style = attr.Value
+							b.Style = attr.Value
 						}
 					}
 				}
 			case "buAutoNum":
-				if state.inPPr && currentParagraph != nil {
-					if currentParagraph.bullet == nil {
-						currentParagraph.bullet = NewBullet()
-					}
-					currentParagraph.bullet.Type = BulletTypeNumeric
+				if b := bulletTarget(); b != nil {
+					b.Type = BulletTypeNumeric
 					for _, attr := range t.Attr {
 						switch attr.Name.Local {
 						case "type":
-							currentParagraph.bullet.NumFormat = attr.Value
+							b.NumFormat = attr.Value
 						case "startAt":
 							if v, err := strconv.Atoi(attr.Value); err == nil {
-								currentParagraph.bullet.StartAt = v
+								b.StartAt = v
 							}
 						}
 					}
 				}
 			case "buFont":
-				if state.inPPr && currentParagraph != nil {
-					if currentParagraph.bullet == nil {
-						currentParagraph.bullet = NewBullet()
-					}
+				if b := bulletTarget(); b != nil {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "typeface" {
-							currentParagraph.bullet.Font = attr.Value
+							b.Font = attr.Value
 						}
 					}
 				}
 			case "buSzPct":
-				if state.inPPr && currentParagraph != nil {
-					if currentParagraph.bullet == nil {
-						currentParagraph.bullet = NewBullet()
-					}
+				if b := bulletTarget(); b != nil {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
 							if v, err := strconv.Atoi(attr.Value); err == nil {
-								currentParagraph.bullet.Size = v / 1000
+								b.Size = v / 1000
 							}
 						}
 					}
@@ -924,7 +1310,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						}
 					}
 				}
+			case "fld":
+				if attrValue(t.Attr, "type") != "slidenum" || !state.inParagraph || state.inTcParagraph {
+					break
+				}
+				state.inSlideNumFld = true
+				fallthrough
 			case "r":
+				runHyperlink = nil
 				if state.inTcParagraph {
 					state.inTcRun = true
 					currentFont = NewFont()
@@ -941,7 +1334,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						currentFont.Color = *fontRefColor
 					}
 					// Apply lstStyle-level default font properties first
-					if lstStyleFont != nil {
+					// (lvl1pPr only covers paragraphs at the top level)
+					if lstStyleFont != nil && (currentParagraph == nil || currentParagraph.alignment.Level == 0) {
 						if lstStyleFont.Size > 0 {
 							currentFont.Size = lstStyleFont.Size
 						}
@@ -1000,10 +1394,32 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							currentFont.Underline = UnderlineType(attr.Value)
 						case "strike":
 							currentFont.Strikethrough = attr.Value == "sngStrike"
+						case "baseline":
+							// Positive offsets (e.g. 30000) are superscript, negative subscript
+							if v, err := strconv.Atoi(attr.Value); err == nil {
+								currentFont.Superscript = v > 0
+								currentFont.Subscript = v < 0
+							}
 						}
 					}
 				}
 			case "defRPr":
+				if lvlStyle != nil && !state.inPPr {
+					lvlStyle.Font = &Font{}
+					for _, attr := range t.Attr {
+						switch attr.Name.Local {
+						case "sz":
+							if v, err := strconv.Atoi(attr.Value); err == nil {
+								lvlStyle.Font.Size = v / 100
+							}
+						case "b":
+							lvlStyle.Font.Bold = attr.Value == "1"
+						case "i":
+							lvlStyle.Font.Italic = attr.Value == "1"
+						}
+					}
+					state.inDefRPr = true
+				}
 				if state.inPPr || state.inLstStyleLvl1 {
 					state.inDefRPr = true
 					if state.inLstStyleLvl1 && !state.inPPr {
@@ -1070,6 +1486,18 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						pendingShapeFill.Type = FillNone
 					}
 				}
+				// <a:noFill/> inside spPr/ln means the line is not drawn
+				if state.inSpPr && state.inLn && !state.inExtLst {
+					if state.inCxnSp && currentLine != nil {
+						currentLine.lineStyle = BorderNone
+						lineColorSet = true
+					} else if state.inSp {
+						if pendingBorder == nil {
+							pendingBorder = &Border{}
+						}
+						pendingBorder.Style = BorderNone
+					}
+				}
 				// <a:noFill/> inside tcPr means the cell has no fill
 				if state.inTcPr && !state.inTcPrLn {
 					if currentTable != nil && currentTableRow >= 0 && currentTableCol >= 0 &&
@@ -1126,6 +1554,12 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					gradStopColors = nil
 					gradStopPositions = nil
 					gradAngle = 0
+				} else if state.inTcPr && !state.inTcPrLn {
+					// Table cell gradient fill
+					state.inGradFill = true
+					gradStopColors = nil
+					gradStopPositions = nil
+					gradAngle = 0
 				}
 			case "gsLst":
 				if state.inGradFill {
@@ -1168,7 +1602,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 			case "srgbClr":
 				state.inSrgbClr = true
 				lastColor = nil
-				if state.inGs {
+				if state.inBgRef && pendingBgRef != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "val" {
+							pendingBgRef.Color = NewColor("FF" + attr.Value)
+							lastColor = &pendingBgRef.Color
+						}
+					}
+				} else if state.inGs {
 					// Gradient stop color
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
@@ -1235,6 +1676,15 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							lastColor = fontRefColor
 						}
 					}
+				} else if state.inLnRef {
+					// <p:style>/<a:lnRef>/<a:srgbClr> — default line color
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "val" {
+							c := NewColor("FF" + attr.Value)
+							lnRefColor = &c
+							lastColor = lnRefColor
+						}
+					}
 				} else if state.inSolidFill && state.inRunProps && currentFont != nil {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
@@ -1250,6 +1700,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							if state.inCxnSp && currentLine != nil {
 								currentLine.lineColor = c
 								lastColor = &currentLine.lineColor
+								lineColorSet = true
 							} else if state.inSp {
 								if pendingBorder == nil {
 									pendingBorder = &Border{Style: BorderSolid}
@@ -1302,6 +1753,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							lastColor = currentParagraph.bullet.Color
 						}
 					}
+				} else if state.inDefRPr && state.inSolidFill && lvlStyle != nil && lvlStyle.Font != nil {
+					// lstStyle list level defRPr solidFill srgbClr
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "val" {
+							lvlStyle.Font.Color = NewColor("FF" + attr.Value)
+							lastColor = &lvlStyle.Font.Color
+						}
+					}
 				} else if state.inDefRPr && state.inSolidFill && state.inLstStyleLvl1 && lstStyleFont != nil {
 					// lstStyle defRPr solidFill srgbClr
 					for _, attr := range t.Attr {
@@ -1328,17 +1787,31 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						prstName = attr.Value
 					}
 				}
-				c := presetColorToColor(prstName)
-				if state.inGs {
+				c := NewPresetColor(prstName)
+				if state.inBgRef && pendingBgRef != nil {
+					pendingBgRef.Color = c
+					lastColor = &pendingBgRef.Color
+				} else if state.inGs {
 					gradStopColors = append(gradStopColors, c)
 					gradStopPositions = append(gradStopPositions, state.gradFillPos)
 					lastColor = &gradStopColors[len(gradStopColors)-1]
 				} else if state.inOuterShdw && pendingShadow != nil {
 					pendingShadow.Color = c
 					lastColor = &pendingShadow.Color
+				} else if state.inTcPrSolidFill && !state.inTcPrLn {
+					if currentTable != nil && currentTableRow >= 0 && currentTableCol >= 0 &&
+						currentTableRow < len(currentTable.rows) && currentTableCol < len(currentTable.rows[currentTableRow]) {
+						cell := currentTable.rows[currentTableRow][currentTableCol]
+						cell.fill = NewFill()
+						cell.fill.SetSolid(c)
+						lastColor = &cell.fill.Color
+					}
 				} else if state.inFontRef {
 					fontRefColor = &c
 					lastColor = fontRefColor
+				} else if state.inLnRef {
+					lnRefColor = &c
+					lastColor = lnRefColor
 				} else if state.inSolidFill && state.inRunProps && currentFont != nil && !state.inLn {
 					currentFont.Color = c
 					lastColor = &currentFont.Color
@@ -1346,6 +1819,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					if state.inCxnSp && currentLine != nil {
 						currentLine.lineColor = c
 						lastColor = &currentLine.lineColor
+						lineColorSet = true
 					} else if state.inSp {
 						if pendingBorder == nil {
 							pendingBorder = &Border{Style: BorderSolid}
@@ -1379,6 +1853,9 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					cc := c
 					currentParagraph.bullet.Color = &cc
 					lastColor = currentParagraph.bullet.Color
+				} else if state.inDefRPr && state.inSolidFill && lvlStyle != nil && lvlStyle.Font != nil {
+					lvlStyle.Font.Color = c
+					lastColor = &lvlStyle.Font.Color
 				} else if state.inDefRPr && state.inSolidFill && state.inLstStyleLvl1 && lstStyleFont != nil {
 					lstStyleFont.Color = c
 					lastColor = &lstStyleFont.Color
@@ -1398,7 +1875,11 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					}
 					if argb, ok := pres.themeColors[schemeName]; ok && argb != "" {
 						c := NewColor(argb)
-						if state.inGs {
+						if state.inBgRef && pendingBgRef != nil {
+							pendingBgRef.SchemeColor = schemeName
+							pendingBgRef.Color = c
+							lastColor = &pendingBgRef.Color
+						} else if state.inGs {
 							gradStopColors = append(gradStopColors, c)
 							gradStopPositions = append(gradStopPositions, state.gradFillPos)
 							lastColor = &gradStopColors[len(gradStopColors)-1]
@@ -1441,6 +1922,9 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							// <p:style>/<a:fontRef>/<a:schemeClr> — default text color
 							fontRefColor = &c
 							lastColor = fontRefColor
+						} else if state.inLnRef {
+							lnRefColor = &c
+							lastColor = lnRefColor
 						} else if state.inSolidFill && state.inRunProps && currentFont != nil {
 							currentFont.Color = c
 							lastColor = &currentFont.Color
@@ -1448,6 +1932,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							if state.inCxnSp && currentLine != nil {
 								currentLine.lineColor = c
 								lastColor = &currentLine.lineColor
+								lineColorSet = true
 							} else if state.inSp {
 								if pendingBorder == nil {
 									pendingBorder = &Border{Style: BorderSolid}
@@ -1480,6 +1965,9 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						} else if state.inBuClr && currentParagraph != nil && currentParagraph.bullet != nil {
 							currentParagraph.bullet.Color = &c
 							lastColor = currentParagraph.bullet.Color
+						} else if state.inDefRPr && state.inSolidFill && lvlStyle != nil && lvlStyle.Font != nil {
+							lvlStyle.Font.Color = c
+							lastColor = &lvlStyle.Font.Color
 						} else if state.inDefRPr && state.inSolidFill && state.inLstStyleLvl1 && lstStyleFont != nil {
 							lstStyleFont.Color = c
 							lastColor = &lstStyleFont.Color
@@ -1493,15 +1981,24 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				// <a:sysClr val="window" lastClr="FFFFFF"/> — system color
 				state.inSrgbClr = true // reuse for alpha/lumMod child handling
 				lastColor = nil
-				var sysLastClr string
+				var sysName, sysLastClr string
 				for _, attr := range t.Attr {
-					if attr.Name.Local == "lastClr" {
+					switch attr.Name.Local {
+					case "val":
+						sysName = attr.Value
+					case "lastClr":
 						sysLastClr = attr.Value
 					}
 				}
-				if sysLastClr != "" {
-					c := NewColor("FF" + sysLastClr)
-					if state.inOuterShdw && pendingShadow != nil {
+				if c, ok := systemColor(sysName, sysLastClr); ok {
+					if state.inBgRef && pendingBgRef != nil {
+						pendingBgRef.Color = c
+						lastColor = &pendingBgRef.Color
+					} else if state.inGs {
+						gradStopColors = append(gradStopColors, c)
+						gradStopPositions = append(gradStopPositions, state.gradFillPos)
+						lastColor = &gradStopColors[len(gradStopColors)-1]
+					} else if state.inOuterShdw && pendingShadow != nil {
 						pendingShadow.Color = c
 						lastColor = &pendingShadow.Color
 					} else if state.inTcPrSolidFill {
@@ -1539,6 +2036,9 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					} else if state.inFontRef {
 						fontRefColor = &c
 						lastColor = fontRefColor
+					} else if state.inLnRef {
+						lnRefColor = &c
+						lastColor = lnRefColor
 					} else if state.inSolidFill && state.inRunProps && currentFont != nil {
 						currentFont.Color = c
 						lastColor = &currentFont.Color
@@ -1546,6 +2046,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						if state.inCxnSp && currentLine != nil {
 							currentLine.lineColor = c
 							lastColor = &currentLine.lineColor
+							lineColorSet = true
 						} else if state.inSp {
 							if pendingBorder == nil {
 								pendingBorder = &Border{Style: BorderSolid}
@@ -1578,6 +2079,9 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					} else if state.inBuClr && currentParagraph != nil && currentParagraph.bullet != nil {
 						currentParagraph.bullet.Color = &c
 						lastColor = currentParagraph.bullet.Color
+					} else if state.inDefRPr && state.inSolidFill && lvlStyle != nil && lvlStyle.Font != nil {
+						lvlStyle.Font.Color = c
+						lastColor = &lvlStyle.Font.Color
 					} else if state.inDefRPr && state.inSolidFill && state.inLstStyleLvl1 && lstStyleFont != nil {
 						lstStyleFont.Color = c
 						lastColor = &lstStyleFont.Color
@@ -1600,10 +2104,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 								if v <= 0 && (state.inRunProps || state.inDefRPr) {
 									continue
 								}
-								alpha := uint8(v * 255 / 100000)
-								// Replace the alpha byte in the ARGB string
-								alphaHex := fmt.Sprintf("%02X", alpha)
-								lastColor.ARGB = alphaHex + lastColor.ARGB[2:]
+								*lastColor = lastColor.WithTransform(ColorTransformAlpha, v)
 								// Also update shadow Alpha when inside outerShdw
 								if state.inOuterShdw && pendingShadow != nil {
 									pendingShadow.Alpha = v / 1000 // convert to 0-100
@@ -1612,46 +2113,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						}
 					}
 				}
-			case "lumMod":
-				// Luminance modulation: multiply luminance by val/100000
-				if state.inSrgbClr && lastColor != nil {
-					for _, attr := range t.Attr {
-						if attr.Name.Local == "val" {
-							if v, err := strconv.Atoi(attr.Value); err == nil {
-								applyLumMod(lastColor, float64(v)/100000.0)
-							}
-						}
-					}
-				}
-			case "lumOff":
-				// Luminance offset: add val/100000 to luminance
+			case "lumMod", "lumOff", "tint", "shade", "satMod":
+				// Luminance/saturation modulation and tint/shade, recorded as
+				// transforms on the color and resolved when the color is used
 				if state.inSrgbClr && lastColor != nil {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
 							if v, err := strconv.Atoi(attr.Value); err == nil {
-								applyLumOff(lastColor, float64(v)/100000.0)
-							}
-						}
-					}
-				}
-			case "tint":
-				// Tint: blend toward white by val/100000
-				if state.inSrgbClr && lastColor != nil {
-					for _, attr := range t.Attr {
-						if attr.Name.Local == "val" {
-							if v, err := strconv.Atoi(attr.Value); err == nil {
-								applyTint(lastColor, float64(v)/100000.0)
-							}
-						}
-					}
-				}
-			case "shade":
-				// Shade: blend toward black by val/100000
-				if state.inSrgbClr && lastColor != nil {
-					for _, attr := range t.Attr {
-						if attr.Name.Local == "val" {
-							if v, err := strconv.Atoi(attr.Value); err == nil {
-								applyShade(lastColor, float64(v)/100000.0)
+								*lastColor = lastColor.WithTransform(ColorTransformType(t.Name.Local), v)
 							}
 						}
 					}
@@ -1663,6 +2132,12 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							currentFont.Name = attr.Value
 						}
 					}
+				} else if state.inDefRPr && lvlStyle != nil && lvlStyle.Font != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "typeface" && !strings.HasPrefix(attr.Value, "+") {
+							lvlStyle.Font.Name = attr.Value
+						}
+					}
 				} else if state.inDefRPr && state.inLstStyleLvl1 && lstStyleFont != nil {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "typeface" && !strings.HasPrefix(attr.Value, "+") {
@@ -1684,6 +2159,12 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							currentFont.NameEA = attr.Value
 						}
 					}
+				} else if state.inDefRPr && lvlStyle != nil && lvlStyle.Font != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "typeface" && !strings.HasPrefix(attr.Value, "+") {
+							lvlStyle.Font.NameEA = attr.Value
+						}
+					}
 				} else if state.inDefRPr && state.inLstStyleLvl1 && lstStyleFont != nil {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "typeface" && !strings.HasPrefix(attr.Value, "+") {
@@ -1787,10 +2268,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 										dir := strings.TrimSuffix(slidePath, "/"+lastPathComponent(slidePath))
 										imgPath = resolveRelativePath(dir, imgPath)
 									}
-									imgData, err := readFileFromZip(zr, imgPath)
+									imgData, err := r.readMedia(zr, imgPath)
 									if err == nil {
 										currentDrawing.data = imgData
 										currentDrawing.mimeType = guessMimeType(imgPath)
+									} else {
+										r.missingMedia(slidePath, imgPath, shapeName, err)
+										currentDrawing.missingMedia = imgPath
+										currentDrawing.mimeType = guessMimeType(imgPath)
 									}
 									break
 								}
@@ -1808,16 +2293,37 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 										dir := strings.TrimSuffix(slidePath, "/"+lastPathComponent(slidePath))
 										imgPath = resolveRelativePath(dir, imgPath)
 									}
-									imgData, err := readFileFromZip(zr, imgPath)
+									imgData, err := r.readMedia(zr, imgPath)
 									if err == nil {
 										pendingBlipFillData = imgData
 										pendingBlipFillMime = guessMimeType(imgPath)
+									} else {
+										r.missingMedia(slidePath, imgPath, shapeName, err)
 									}
 									break
 								}
 							}
 						}
 					}
+				} else if state.inTcPr && currentTable != nil && currentTableRow >= 0 && currentTableCol >= 0 &&
+					currentTableRow < len(currentTable.rows) && currentTableCol < len(currentTable.rows[currentTableRow]) {
+					// <a:blip> inside <a:blipFill> inside <a:tcPr> — cell picture fill
+					cell := currentTable.rows[currentTableRow][currentTableCol]
+					for _, rel := range rels {
+						if rel.ID == attrValue(t.Attr, "embed") {
+							imgPath := rel.Target
+							if !strings.HasPrefix(imgPath, "ppt/") {
+								dir := strings.TrimSuffix(slidePath, "/"+lastPathComponent(slidePath))
+								imgPath = resolveRelativePath(dir, imgPath)
+							}
+							if imgData, err := r.readMedia(zr, imgPath); err == nil {
+								cell.fill = NewFill().SetPicture(imgData, guessMimeType(imgPath))
+							} else {
+								r.missingMedia(slidePath, imgPath, shapeName, err)
+							}
+							break
+						}
+					}
 				} else if state.inBgBlipFill {
 					// <a:blip> inside <a:blipFill> inside <p:bgPr> — slide background image
 					for _, attr := range t.Attr {
@@ -1829,10 +2335,16 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 										dir := strings.TrimSuffix(slidePath, "/"+lastPathComponent(slidePath))
 										imgPath = resolveRelativePath(dir, imgPath)
 									}
-									imgData, err := readFileFromZip(zr, imgPath)
+									imgData, err := r.readMedia(zr, imgPath)
 									if err == nil {
-										bgBlipFillData = imgData
-										bgBlipFillMime = guessMimeType(imgPath)
+										if bgPictureFill == nil {
+											bgPictureFill = NewFill()
+										}
+										tile := bgPictureFill.Tile
+										bgPictureFill.SetPicture(imgData, guessMimeType(imgPath))
+										bgPictureFill.Tile = tile
+									} else {
+										r.missingMedia(slidePath, imgPath, "", err)
 									}
 									break
 								}
@@ -1840,6 +2352,13 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						}
 					}
 				}
+			case "tile":
+				if state.inBgBlipFill {
+					if bgPictureFill == nil {
+						bgPictureFill = NewFill()
+					}
+					bgPictureFill.Tile = parseFillTile(t.Attr)
+				}
 			case "alphaModFix":
 				if state.inPic && currentDrawing != nil {
 					for _, attr := range t.Attr {
@@ -1879,11 +2398,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				}
 				if state.inCxnSp && currentLine != nil {
 					for _, attr := range t.Attr {
-						if attr.Name.Local == "w" {
+						switch attr.Name.Local {
+						case "w":
 							if v, err := strconv.Atoi(attr.Value); err == nil {
 								currentLine.lineWidthEMU = v
 								currentLine.lineWidth = v / 12700
 							}
+						case "cap":
+							currentLine.lineCap = LineCap(attr.Value)
 						}
 					}
 				} else if state.inSp && state.inSpPr {
@@ -1963,11 +2485,12 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					}
 				}
 			case "prstDash":
-				if state.inLn && state.inCxnSp && currentLine != nil {
+				if state.inLn && state.inCxnSp && currentLine != nil && currentLine.lineStyle != BorderNone {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
 							switch attr.Value {
-							case "dash", "lgDash", "sysDash":
+							case "dash", "lgDash", "sysDash", "dashDot", "lgDashDot", "lgDashDotDot",
+								"sysDashDot", "sysDashDotDot":
 								currentLine.lineStyle = BorderDash
 							case "dot", "sysDot":
 								currentLine.lineStyle = BorderDot
@@ -1976,11 +2499,12 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							}
 						}
 					}
-				} else if state.inLn && state.inSp {
+				} else if state.inLn && state.inSp && (pendingBorder == nil || pendingBorder.Style != BorderNone) {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
 							switch attr.Value {
-							case "dash", "lgDash", "sysDash":
+							case "dash", "lgDash", "sysDash", "dashDot", "lgDashDot", "lgDashDotDot",
+								"sysDashDot", "sysDashDotDot":
 								if pendingBorder == nil {
 									pendingBorder = &Border{Style: BorderDash}
 								} else {
@@ -2157,8 +2681,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					}
 				}
 			case "style":
-				// <p:style> element inside <p:sp> — provides default styling
-				if state.inSp && !state.inSpPr && !state.inTxBody {
+				// <p:style> element inside <p:sp> or <p:cxnSp> — provides default styling
+				if (state.inSp || state.inCxnSp) && !state.inSpPr && !state.inTxBody {
 					state.inStyle = true
 				}
 			case "fontRef":
@@ -2166,6 +2690,15 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				if state.inStyle {
 					state.inFontRef = true
 				}
+			case "lnRef":
+				// <a:lnRef> inside <p:style> — provides the default line color;
+				// idx 0 refers to no line at all
+				if state.inStyle && state.inCxnSp {
+					state.inLnRef = true
+					if attrValue(t.Attr, "idx") == "0" && currentLine != nil && !lineColorSet {
+						currentLine.lineStyle = BorderNone
+					}
+				}
 			}
 
 		case xml.CharData:
@@ -2175,11 +2708,18 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				if currentFont != nil {
 					tr.font = currentFont
 				}
+				tr.hyperlink = runHyperlink
+			} else if state.inText && state.inSlideNumFld && currentParagraph != nil {
+				f := currentParagraph.CreateSlideNumber()
+				if currentFont != nil {
+					f.font = currentFont
+				}
 			} else if state.inText && currentParagraph != nil {
 				tr := currentParagraph.CreateTextRun(text)
 				if currentFont != nil {
 					tr.font = currentFont
 				}
+				tr.hyperlink = runHyperlink
 			}
 
 		case xml.EndElement:
@@ -2190,6 +2730,14 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 				state.inBgPr = false
 				state.inBgSolidFill = false
 				state.inBgBlipFill = false
+			case "bgRef":
+				if state.inBgRef && pendingBgRef != nil {
+					slide.backgroundRef = pendingBgRef
+					if pres != nil && slide.background == nil {
+						slide.background = pres.resolveBackgroundRef(pendingBgRef)
+					}
+				}
+				state.inBgRef = false
 			case "spTree":
 				state.inSpTree = false
 			case "grpSp":
@@ -2203,6 +2751,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						if g != nil {
 							g.name = top.name
 							g.description = top.descr
+							g.tags = top.tags
+							g.setExtLsts(top.ext)
 							g.offsetX = top.offX
 							g.offsetY = top.offY
 							g.width = top.extCX
@@ -2238,6 +2788,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					if state.isPlaceholder && currentPlaceholder != nil {
 						currentPlaceholder.name = shapeName
 						currentPlaceholder.description = shapeDescr
+						currentPlaceholder.locks = shapeLocks
+						currentPlaceholder.scene3d = shapeScene
 						currentPlaceholder.offsetX = offX
 						currentPlaceholder.offsetY = offY
 						currentPlaceholder.width = extCX
@@ -2256,6 +2808,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						autoShape := NewAutoShape()
 						autoShape.name = shapeName
 						autoShape.description = shapeDescr
+						autoShape.locks = shapeLocks
+						autoShape.scene3d = shapeScene
 						autoShape.offsetX = offX
 						autoShape.offsetY = offY
 						autoShape.width = extCX
@@ -2298,6 +2852,7 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 							autoShape.paragraphs = currentRichText.paragraphs
 							autoShape.textAnchor = textAnchor
 							autoShape.textDirection = textDir
+							autoShape.wordWrap = currentRichText.wordWrap
 							autoShape.fontScale = currentRichText.fontScale
 							// Copy text insets from richtext body properties
 							if currentRichText.insetsSet {
@@ -2331,6 +2886,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						ds := NewDrawingShape()
 						ds.name = shapeName
 						ds.description = shapeDescr
+						ds.locks = shapeLocks
+						ds.scene3d = shapeScene
 						ds.offsetX = offX
 						ds.offsetY = offY
 						ds.width = extCX
@@ -2350,6 +2907,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 					} else if currentRichText != nil {
 						currentRichText.name = shapeName
 						currentRichText.description = shapeDescr
+						currentRichText.locks = shapeLocks
+						currentRichText.scene3d = shapeScene
 						currentRichText.offsetX = offX
 						currentRichText.offsetY = offY
 						currentRichText.width = extCX
@@ -2398,6 +2957,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						rt := NewRichTextShape()
 						rt.name = shapeName
 						rt.description = shapeDescr
+						rt.locks = shapeLocks
+						rt.scene3d = shapeScene
 						rt.offsetX = offX
 						rt.offsetY = offY
 						rt.width = extCX
@@ -2438,6 +2999,8 @@ func (r *PPTXReader) parseSlideXML(decoder *xml.Decoder, slide *Slide, rels []xm
 						autoShape := NewAutoShape()
 						autoShape.name = shapeName
 						autoShape.description = shapeDescr
+						autoShape.locks = shapeLocks
+						autoShape.scene3d = shapeScene
 						autoShape.offsetX = offX
 						autoShape.offsetY = offY
 						autoShape.width = extCX
@@ -2483,9 +3046,14 @@ if state.inGrpSp && currentGroup != nil {
 			case "pic":
 				if state.inPic {
 					state.inPic = false
+					if currentDrawing != nil && currentDrawing.missingMedia != "" && r.options().MissingMedia == MissingMediaSkip {
+						currentDrawing = nil
+					}
 					if currentDrawing != nil {
 						currentDrawing.name = shapeName
 						currentDrawing.description = shapeDescr
+						currentDrawing.locks = shapeLocks
+						currentDrawing.scene3d = shapeScene
 						currentDrawing.offsetX = offX
 						currentDrawing.offsetY = offY
 						currentDrawing.width = extCX
@@ -2506,6 +3074,8 @@ if state.inGrpSp && currentGroup != nil {
 					state.inCxnSp = false
 					if currentLine != nil {
 						currentLine.name = shapeName
+						currentLine.locks = shapeLocks
+						currentLine.scene3d = shapeScene
 						currentLine.offsetX = offX
 						currentLine.offsetY = offY
 						currentLine.width = extCX
@@ -2514,6 +3084,9 @@ if state.inGrpSp && currentGroup != nil {
 						currentLine.flipVertical = flipV
 						currentLine.rotation = shapeRotation
 						currentLine.connectorType = prstGeom
+						if lnRefColor != nil && !lineColorSet {
+							currentLine.lineColor = *lnRefColor
+						}
 						if pendingAdjustValues != nil {
 							currentLine.adjustValues = pendingAdjustValues
 							pendingAdjustValues = nil
@@ -2530,16 +3103,36 @@ if state.inGrpSp && currentGroup != nil {
 					}
 					currentLine = nil
 				}
+			case "camera":
+				inCamera = false
 			case "graphicFrame":
 				if state.inGraphicFrame {
 					state.inGraphicFrame = false
 					if currentTable != nil {
 						currentTable.name = shapeName
+						currentTable.locks = shapeLocks
 						currentTable.offsetX = offX
 						currentTable.offsetY = offY
 						currentTable.width = extCX
 						currentTable.height = extCY
+						currentTable.flipHorizontal = flipH
+						currentTable.flipVertical = flipV
+						currentTable.rotation = shapeRotation
+						currentTable.padRows()
 						slide.shapes = append(slide.shapes, currentTable)
+					} else if chart := r.readSlideChart(zr, rels, slidePath, shapeName, chartRID); chart != nil {
+						chart.name = shapeName
+						chart.locks = shapeLocks
+						chart.offsetX = offX
+						chart.offsetY = offY
+						chart.width = extCX
+						chart.height = extCY
+						chart.flipHorizontal = flipH
+						chart.flipVertical = flipV
+						chart.rotation = shapeRotation
+						slide.shapes = append(slide.shapes, chart)
+					} else {
+						r.logger().Warn("skipped graphic frame", "part", slidePath, "shape", shapeName, "uri", graphicURI)
 					}
 					currentTable = nil
 				}
@@ -2601,18 +3194,38 @@ if state.inGrpSp && currentGroup != nil {
 					state.inRun = false
 				}
 				currentFont = nil
+			case "fld":
+				if state.inSlideNumFld {
+					state.inSlideNumFld = false
+					state.inRun = false
+					currentFont = nil
+				}
 			case "rPr":
 				state.inRunProps = false
 				state.inSolidFill = false
 				state.inRunPropsGradFill = false
 			case "defRPr":
+				if lvlStyle != nil && lvlStyle.Font != nil && state.inLstStyleLvl1 && lstStyleFont != nil {
+					// The first level's font is also the text body's default
+					if lvlStyle.Font.Color.ARGB != "" {
+						lstStyleFont.Color = lvlStyle.Font.Color
+					}
+					if lvlStyle.Font.Name != "" {
+						lstStyleFont.Name = lvlStyle.Font.Name
+					}
+					if lvlStyle.Font.NameEA != "" {
+						lstStyleFont.NameEA = lvlStyle.Font.NameEA
+					}
+				}
 				state.inDefRPr = false
 				state.inSolidFill = false
 			case "lstStyle":
 				state.inLstStyle = false
 				state.inLstStyleLvl1 = false
-			case "lvl1pPr":
+				lvlStyle = nil
+			case "lvl1pPr", "lvl2pPr", "lvl3pPr", "lvl4pPr", "lvl5pPr", "lvl6pPr", "lvl7pPr", "lvl8pPr", "lvl9pPr":
 				state.inLstStyleLvl1 = false
+				lvlStyle = nil
 			case "solidFill":
 				state.inSolidFill = false
 				state.inBgSolidFill = false
@@ -2639,6 +3252,13 @@ if state.inGrpSp && currentGroup != nil {
 					} else if state.inSpPr && state.inSp {
 						pendingShapeFill = NewFill()
 						pendingShapeFill.SetGradientLinear(startColor, endColor, gradAngle)
+					} else if state.inTcPr && !state.inTcPrLn {
+						if currentTable != nil && currentTableRow >= 0 && currentTableCol >= 0 &&
+							currentTableRow < len(currentTable.rows) && currentTableCol < len(currentTable.rows[currentTableRow]) {
+							cell := currentTable.rows[currentTableRow][currentTableCol]
+							cell.fill = NewFill()
+							cell.fill.SetGradientLinear(startColor, endColor, gradAngle)
+						}
 					}
 				}
 				state.inGradFill = false
@@ -2697,8 +3317,11 @@ if state.inGrpSp && currentGroup != nil {
 			case "style":
 				state.inStyle = false
 				state.inFontRef = false
+				state.inLnRef = false
 			case "fontRef":
 				state.inFontRef = false
+			case "lnRef":
+				state.inLnRef = false
 			case "t":
 				state.inText = false
 				state.inTcText = false
@@ -2712,26 +3335,117 @@ if state.inGrpSp && currentGroup != nil {
 						top.name = shapeName
 						top.descr = shapeDescr
 					}
+					if top.tags == nil {
+						top.tags = shapeTags
+						shapeTags = nil
+					}
+					top.ext = shapeExt
+					shapeExt = extLsts{}
+				}
+			}
+		}
+
+		if shapeDest != nil {
+			var added Shape
+			if len(*shapeDest) > shapeDestLen {
+				added = (*shapeDest)[len(*shapeDest)-1]
+			}
+			if added != nil && shapeTags != nil {
+				added.base().tags = shapeTags
+			}
+			if added != nil {
+				added.base().setExtLsts(shapeExt)
+			}
+			shapeTags = nil
+			shapeExt = extLsts{}
+			if hooks != nil {
+				if err := hooks.endShape(added); err != nil {
+					return err
 				}
 			}
 		}
 	}
 
-	// If slide has a blipFill background image, prepend as full-slide drawing
-	if len(bgBlipFillData) > 0 && pres != nil {
-		ds := NewDrawingShape()
-		ds.data = bgBlipFillData
-		ds.mimeType = bgBlipFillMime
-		ds.offsetX = 0
-		ds.offsetY = 0
-		ds.width = pres.layout.CX
-		ds.height = pres.layout.CY
-		slide.shapes = append([]Shape{ds}, slide.shapes...)
+	// A blipFill background becomes a picture fill on the slide
+	if bgPictureFill != nil && len(bgPictureFill.ImageData) > 0 {
+		slide.background = bgPictureFill
 	}
 
 	return nil
 }
 
+// parseHyperlinkClick builds a Hyperlink from an <a:hlinkClick> element.
+// External targets must use an allowed URL scheme. Slide jumps record the
+// target slide part, resolved to a slide index once all slides are read.
+// Returns nil for links that cannot be represented.
+func parseHyperlinkClick(attrs []xml.Attr, rels []xmlRelForRead, slidePath string) *Hyperlink {
+	var rid, action string
+	h := &Hyperlink{}
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "id":
+			rid = attr.Value
+		case "action":
+			action = attr.Value
+		case "tooltip":
+			h.Tooltip = attr.Value
+		case "tgtFrame":
+			h.TargetFrame = attr.Value
+		}
+	}
+	if rid == "" {
+		return nil
+	}
+	for _, rel := range rels {
+		if rel.ID != rid {
+			continue
+		}
+		switch {
+		case rel.TargetMode == "External":
+			if !isValidHyperlinkURL(rel.Target) {
+				return nil
+			}
+			h.URL = rel.Target
+			return h
+		case rel.Type == relTypeSlide && strings.HasPrefix(action, "ppaction://hlinksldjump"):
+			h.IsInternal = true
+			h.targetPart = rel.Target
+			if !strings.HasPrefix(h.targetPart, "ppt/") {
+				dir := strings.TrimSuffix(slidePath, "/"+lastPathComponent(slidePath))
+				h.targetPart = resolveRelativePath(dir, rel.Target)
+			}
+			return h
+		}
+	}
+	return nil
+}
+
+// parseFillTile reads the attributes of an <a:tile> element.
+func parseFillTile(attrs []xml.Attr) *FillTile {
+	tile := NewFillTile()
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "tx":
+			tile.OffsetX, _ = strconv.ParseInt(attr.Value, 10, 64)
+		case "ty":
+			tile.OffsetY, _ = strconv.ParseInt(attr.Value, 10, 64)
+		case "sx":
+			if v, err := strconv.Atoi(attr.Value); err == nil {
+				tile.ScaleX = v
+			}
+		case "sy":
+			if v, err := strconv.Atoi(attr.Value); err == nil {
+				tile.ScaleY = v
+			}
+		case "flip":
+			tile.Flip = attr.Value
+		case "algn":
+			tile.Alignment = attr.Value
+		}
+	}
+	return tile
+}
+
 func lastPathComponent(path string) string {
 	parts := strings.Split(path, "/")
 	return parts[len(parts)-1]
@@ -2856,27 +3570,27 @@ func (r *PPTXReader) applyLayoutInheritance(zr *zip.Reader, slide *Slide, rels [
 	layoutPHs := r.parseLayoutPlaceholders(data, pres)
 
 	// Also parse layout background
-	layoutBg, bgImage := r.parseLayoutBackground(data, layoutRels, zr, layoutPath, pres)
+	layoutBg := r.parseLayoutBackground(data, layoutRels, zr, layoutPath, pres)
 
-	// Apply layout background if slide has no background
-	if slide.background == nil && layoutBg != nil {
-		slide.background = layoutBg
-	}
-	// If layout has a blipFill background image, prepend as full-slide drawing.
-	// Skip if slide already has a background image (first shape is a full-slide DrawingShape).
-	if bgImage != nil && slide.background == nil {
-		hasSlideBgImage := false
-		if len(slide.shapes) > 0 {
-			if ds, ok := slide.shapes[0].(*DrawingShape); ok && ds.offsetX == 0 && ds.offsetY == 0 && ds.width == pres.layout.CX && ds.height == pres.layout.CY {
-				hasSlideBgImage = true
+	// Slides without a background of their own are drawn with the layout's,
+	// or else the master's
+	slide.inheritedBackground = layoutBg
+	if slide.inheritedBackground == nil {
+		for _, rel := range layoutRels {
+			if rel.Type != relTypeSlideMaster {
+				continue
 			}
-		}
-		if !hasSlideBgImage {
-			bgImage.offsetX = 0
-			bgImage.offsetY = 0
-			bgImage.width = pres.layout.CX
-			bgImage.height = pres.layout.CY
-			slide.shapes = append([]Shape{bgImage}, slide.shapes...)
+			masterPath := rel.Target
+			if !strings.HasPrefix(masterPath, "ppt/") {
+				dir := strings.TrimSuffix(layoutPath, "/"+lastPathComponent(layoutPath))
+				masterPath = resolveRelativePath(dir, masterPath)
+			}
+			if masterData, err := readFileFromZip(zr, masterPath); err == nil {
+				masterRelsPath := strings.Replace(masterPath, "slideMasters/", "slideMasters/_rels/", 1) + ".rels"
+				masterRels, _ := r.readRelationships(zr, masterRelsPath)
+				slide.inheritedBackground = r.parseLayoutBackground(masterData, masterRels, zr, masterPath, pres)
+			}
+			break
 		}
 	}
 
@@ -3132,11 +3846,18 @@ func (r *PPTXReader) parseLayoutPlaceholders(data []byte, pres *Presentation) []
 				}
 			case "sysClr":
 				if inDefSolidFill {
+					var sysName, sysLastClr string
 					for _, attr := range t.Attr {
-						if attr.Name.Local == "lastClr" {
-							fontColor = NewColor("FF" + attr.Value)
+						switch attr.Name.Local {
+						case "val":
+							sysName = attr.Value
+						case "lastClr":
+							sysLastClr = attr.Value
 						}
 					}
+					if c, ok := systemColor(sysName, sysLastClr); ok {
+						fontColor = c
+					}
 				}
 			case "schemeClr":
 				// Handle scheme colors in layout placeholder defRPr
@@ -3221,13 +3942,15 @@ func (r *PPTXReader) parseLayoutPlaceholders(data []byte, pres *Presentation) []
 }
 
 // parseLayoutBackground extracts the background fill from a slide layout XML.
-// It also handles blipFill backgrounds by returning an image shape via bgImage.
-func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr *zip.Reader, layoutPath string, pres *Presentation) (*Fill, *DrawingShape) {
+// blipFill backgrounds are returned as picture fills.
+func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr *zip.Reader, layoutPath string, pres *Presentation) *Fill {
 	decoder := xml.NewDecoder(bytes.NewReader(data))
 	inBg := false
 	inBgPr := false
 	inSolidFill := false
 	inBlipFill := false
+	var picture *Fill
+	var bgRef *BackgroundRef
 
 	for {
 		token, err := decoder.Token()
@@ -3244,6 +3967,15 @@ func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr
 				if inBg {
 					inBgPr = true
 				}
+			case "bgRef":
+				if inBg {
+					bgRef = &BackgroundRef{Color: ColorWhite}
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "idx" {
+							bgRef.Index, _ = strconv.Atoi(attr.Value)
+						}
+					}
+				}
 			case "solidFill":
 				if inBgPr {
 					inSolidFill = true
@@ -3252,6 +3984,10 @@ func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr
 				if inBgPr {
 					inBlipFill = true
 				}
+			case "tile":
+				if inBlipFill && picture != nil {
+					picture.Tile = parseFillTile(t.Attr)
+				}
 			case "blip":
 				if inBlipFill {
 					for _, attr := range t.Attr {
@@ -3263,12 +3999,11 @@ func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr
 										dir := strings.TrimSuffix(layoutPath, "/"+lastPathComponent(layoutPath))
 										imgPath = resolveRelativePath(dir, imgPath)
 									}
-									imgData, err := readFileFromZip(zr, imgPath)
+									imgData, err := r.readMedia(zr, imgPath)
 									if err == nil {
-										ds := NewDrawingShape()
-										ds.data = imgData
-										ds.mimeType = guessMimeType(imgPath)
-										return nil, ds
+										picture = NewFill().SetPicture(imgData, guessMimeType(imgPath))
+									} else {
+										r.missingMedia(layoutPath, imgPath, "", err)
 									}
 									break
 								}
@@ -3277,17 +4012,34 @@ func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr
 					}
 				}
 			case "srgbClr":
-				if inSolidFill {
+				if bgRef != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "val" {
+							bgRef.Color = NewColor("FF" + attr.Value)
+						}
+					}
+				} else if inSolidFill {
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
 							fill := NewFill()
 							fill.SetSolid(NewColor("FF" + attr.Value))
-							return fill, nil
+							return fill
 						}
 					}
 				}
 			case "schemeClr":
-				if inSolidFill {
+				if bgRef != nil {
+					for _, attr := range t.Attr {
+						if attr.Name.Local == "val" {
+							bgRef.SchemeColor = attr.Value
+							if pres != nil && pres.themeColors != nil {
+								if argb, ok := pres.themeColors[attr.Value]; ok && argb != "" {
+									bgRef.Color = NewColor(argb)
+								}
+							}
+						}
+					}
+				} else if inSolidFill {
 					var schemeName string
 					for _, attr := range t.Attr {
 						if attr.Name.Local == "val" {
@@ -3298,21 +4050,25 @@ func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr
 						if argb, ok := pres.themeColors[schemeName]; ok && argb != "" {
 							fill := NewFill()
 							fill.SetSolid(NewColor(argb))
-							return fill, nil
+							return fill
 						}
 					}
 					// Fallback: treat bg1 as white
 					if schemeName == "bg1" {
 						fill := NewFill()
 						fill.SetSolid(ColorWhite)
-						return fill, nil
+						return fill
 					}
 				}
 			}
 		case xml.EndElement:
 			switch t.Name.Local {
 			case "bg":
-				return nil, nil // bg found but no recognized fill
+				return picture // nil when bg has no recognized fill
+			case "bgRef":
+				if bgRef != nil && pres != nil {
+					return pres.resolveBackgroundRef(bgRef)
+				}
 			case "bgPr":
 				inBgPr = false
 			case "solidFill":
@@ -3322,7 +4078,7 @@ func (r *PPTXReader) parseLayoutBackground(data []byte, rels []xmlRelForRead, zr
 			}
 		}
 	}
-	return nil, nil
+	return nil
 }
 
 // parseLayoutImages extracts image shapes and non-placeholder text shapes from a slide layout XML.
@@ -3703,7 +4459,7 @@ func (r *PPTXReader) parseLayoutImages(data []byte, rels []xmlRelForRead, zr *zi
 						prstName = attr.Value
 					}
 				}
-				c := presetColorToColor(prstName)
+				c := NewPresetColor(prstName)
 				if inFontRef {
 					fontRefColor = &c
 					lastColor = fontRefColor
@@ -3754,14 +4510,16 @@ func (r *PPTXReader) parseLayoutImages(data []byte, rels []xmlRelForRead, zr *zi
 				// <a:sysClr val="window" lastClr="FFFFFF"/> — system color
 				inSrgbClr = true
 				lastColor = nil
-				var sysLastClr string
+				var sysName, sysLastClr string
 				for _, attr := range t.Attr {
-					if attr.Name.Local == "lastClr" {
+					switch attr.Name.Local {
+					case "val":
+						sysName = attr.Value
+					case "lastClr":
 						sysLastClr = attr.Value
 					}
 				}
-				if sysLastClr != "" {
-					c := NewColor("FF" + sysLastClr)
+				if c, ok := systemColor(sysName, sysLastClr); ok {
 					if inFontRef {
 						fontRefColor = &c
 						lastColor = fontRefColor
@@ -3790,9 +4548,7 @@ func (r *PPTXReader) parseLayoutImages(data []byte, rels []xmlRelForRead, zr *zi
 								if v <= 0 && (inRunProps || inDefRPr) {
 									continue
 								}
-								alpha := uint8(v * 255 / 100000)
-								alphaHex := fmt.Sprintf("%02X", alpha)
-								lastColor.ARGB = alphaHex + lastColor.ARGB[2:]
+								*lastColor = lastColor.WithTransform(ColorTransformAlpha, v)
 							}
 						}
 					}
@@ -3953,8 +4709,11 @@ func (r *PPTXReader) parseLayoutImages(data []byte, rels []xmlRelForRead, zr *zi
 								dir := strings.TrimSuffix(layoutPath, "/"+lastPathComponent(layoutPath))
 								imgPath = resolveRelativePath(dir, imgPath)
 							}
-							imgData, err := readFileFromZip(zr, imgPath)
-							if err == nil {
+							imgData, err := r.readMedia(zr, imgPath)
+							if err != nil {
+								r.missingMedia(layoutPath, imgPath, "", err)
+							}
+							if err == nil || r.options().MissingMedia == MissingMediaPlaceholder {
 								ds := NewDrawingShape()
 								ds.offsetX = offX
 								ds.offsetY = offY
@@ -3962,6 +4721,9 @@ func (r *PPTXReader) parseLayoutImages(data []byte, rels []xmlRelForRead, zr *zi
 								ds.height = extCY
 								ds.data = imgData
 								ds.mimeType = guessMimeType(imgPath)
+								if err != nil {
+									ds.missingMedia = imgPath
+								}
 								ds.alpha = picAlpha
 								ds.cropLeft = cropL
 								ds.cropTop = cropT
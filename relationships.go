@@ -0,0 +1,240 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"fmt"
+)
+
+// Registry keys of relationships that belong to a part rather than to an
+// object inside it.
+const (
+	relKeyLayout     = "layout"
+	relKeyMaster     = "master"
+	relKeyComments   = "comments"
+	relKeyNotes      = "notes"
+	relKeyBackground = "background"
+)
+
+// relRegistry holds the relationships of one package part. Every source of
+// a relationship (a picture, a chart, a hyperlinked run or one of the relKey
+// constants) is registered once under a key and gets an ID of its own, so
+// the part XML and its .rels file always agree. IDs are allocated in
+// registration order, which keeps the output stable from one save to the
+// next.
+type relRegistry struct {
+	rels  []xmlRelationship
+	byKey map[any]string
+	ids   map[string]bool
+	next  int
+}
+
+func newRelRegistry() *relRegistry {
+	return &relRegistry{byKey: make(map[any]string), ids: make(map[string]bool)}
+}
+
+// add registers an internal relationship for key and returns its ID.
+// Registering a key again returns the ID it already has.
+func (r *relRegistry) add(key any, relType, target string) string {
+	return r.addRel(key, xmlRelationship{Type: relType, Target: target})
+}
+
+// addExternal registers a relationship to an external target, such as a
+// web hyperlink, for key and returns its ID.
+func (r *relRegistry) addExternal(key any, relType, target string) string {
+	return r.addRel(key, xmlRelationship{Type: relType, Target: target, TargetMode: "External"})
+}
+
+func (r *relRegistry) addRel(key any, rel xmlRelationship) string {
+	if id, ok := r.byKey[key]; ok {
+		return id
+	}
+	for {
+		r.next++
+		rel.ID = fmt.Sprintf("rId%d", r.next)
+		if !r.ids[rel.ID] {
+			break
+		}
+	}
+	r.ids[rel.ID] = true
+	r.byKey[key] = rel.ID
+	r.rels = append(r.rels, rel)
+	return rel.ID
+}
+
+// reserve registers a relationship with a fixed ID, for parts whose IDs
+// are referenced from fixed markup. It fails when the ID or the key is
+// already taken.
+func (r *relRegistry) reserve(key any, id, relType, target string) error {
+	return r.reserveRel(key, xmlRelationship{ID: id, Type: relType, Target: target})
+}
+
+func (r *relRegistry) reserveRel(key any, rel xmlRelationship) error {
+	if r.ids[rel.ID] {
+		return fmt.Errorf("relationship ID %s is already in use", rel.ID)
+	}
+	if old, ok := r.byKey[key]; ok {
+		return fmt.Errorf("relationship %v already registered as %s", key, old)
+	}
+	r.ids[rel.ID] = true
+	r.byKey[key] = rel.ID
+	r.rels = append(r.rels, rel)
+	return nil
+}
+
+// id returns the ID registered for key, or "" when there is none. A nil
+// registry has no relationships.
+func (r *relRegistry) id(key any) string {
+	if r == nil {
+		return ""
+	}
+	return r.byKey[key]
+}
+
+// writeRels writes the relationships of rels as the .rels part name.
+func (w *PPTXWriter) writeRels(zw *zip.Writer, name string, rels *relRegistry) error {
+	return w.writeXMLPart(zw, name, ctRels, xmlRelationships{
+		Xmlns:         nsRelationships,
+		Relationships: rels.rels,
+	})
+}
+
+// buildSlideRels registers the relationships of a slide: its layout, then
+// the tags, pictures, charts and hyperlinks of its shapes in document order
+// (including shapes nested in groups), then its tags, comments, notes and
+// background picture.
+func (w *PPTXWriter) buildSlideRels(slide *Slide, slideNum int) (*relRegistry, error) {
+	rels := newRelRegistry()
+	if err := rels.reserve(relKeyLayout, "rId1", relTypeSlideLayout, "../slideLayouts/slideLayout1.xml"); err != nil {
+		return nil, err
+	}
+	source := fmt.Sprintf("ppt/slides/slide%d.xml", slideNum)
+	if err := w.pkg.reserveCustomRels(rels, source); err != nil {
+		return nil, err
+	}
+	w.addShapeRels(rels, slide, slide.shapes)
+	w.addTagsRel(rels, slide, slide.tags)
+
+	if len(slide.comments) > 0 {
+		rels.add(relKeyComments, relTypeComment, fmt.Sprintf("../comments/comment%d.xml", slideNum))
+	}
+	if slide.HasNotes() {
+		rels.add(relKeyNotes, relTypeNotesSlide, fmt.Sprintf("../notesSlides/notesSlide%d.xml", slideNum))
+	}
+	if hasBackgroundPicture(slide) {
+		rels.add(relKeyBackground, relTypeImage,
+			fmt.Sprintf("../media/background%d.%s", slideNum, w.getPictureFillExtension(slide.background)))
+	}
+	w.pkg.addCustomRels(rels, source)
+	return rels, nil
+}
+
+// buildMasterRels registers the relationships of the slide master: its
+// layout and theme, then the pictures of its shapes, and their tags and
+// hyperlinks.
+func (w *PPTXWriter) buildMasterRels() (*relRegistry, error) {
+	rels := newRelRegistry()
+	if err := rels.reserve(relKeyLayout, "rId1", relTypeSlideLayout, "../slideLayouts/slideLayout1.xml"); err != nil {
+		return nil, err
+	}
+	rels.add(relTypeTheme, relTypeTheme, "../theme/theme1.xml")
+	shapes := w.presentation.masterShapes()
+	for i, ds := range collectDrawingShapes(shapes) {
+		rels.add(ds, relTypeImage, fmt.Sprintf("../media/master%d.%s", i+1, w.getImageExtension(ds)))
+	}
+	var addRels func(shapes []Shape)
+	addRels = func(shapes []Shape) {
+		for _, shape := range shapes {
+			w.addTagsRel(rels, shape.base(), shape.base().tags)
+			if g, ok := shape.(*GroupShape); ok {
+				addRels(g.shapes)
+			}
+			for _, para := range shapeParagraphs(shape) {
+				for _, elem := range para.elements {
+					if tr, ok := elem.(*TextRun); ok {
+						w.addHyperlinkRel(rels, tr, "../slides/")
+					}
+				}
+			}
+		}
+	}
+	addRels(shapes)
+	return rels, nil
+}
+
+// addShapeRels registers the relationships of shapes and their children.
+func (w *PPTXWriter) addShapeRels(rels *relRegistry, slide *Slide, shapes []Shape) {
+	for _, shape := range shapes {
+		w.addTagsRel(rels, shape.base(), shape.base().tags)
+		switch s := shape.(type) {
+		case *DrawingShape:
+			if hasImagePart(s) {
+				rels.add(s, relTypeImage,
+					fmt.Sprintf("../media/image%d.%s", w.getImageIndex(slide, s), w.getImageExtension(s)))
+			}
+		case *ChartShape:
+			relType := relTypeChart
+			if isChartExType(s.plotArea.chartType) {
+				relType = relTypeChartEx
+			}
+			rels.add(s, relType, "../charts/"+chartPartName(s.plotArea.chartType, w.getChartIndex(s)))
+		case *TableShape:
+			for _, cell := range collectPictureCells([]Shape{s}) {
+				rels.add(cell, relTypeImage,
+					fmt.Sprintf("../media/cell%d.%s", w.getCellPictureIndex(cell), w.getPictureFillExtension(cell.fill)))
+			}
+		case *GroupShape:
+			w.addShapeRels(rels, slide, s.shapes)
+		}
+		for _, para := range shapeParagraphs(shape) {
+			for _, elem := range para.elements {
+				if tr, ok := elem.(*TextRun); ok {
+					w.addHyperlinkRel(rels, tr, "")
+				}
+			}
+		}
+	}
+}
+
+// buildRootRels registers the relationships of the package itself.
+func (w *PPTXWriter) buildRootRels() (*relRegistry, error) {
+	rels := newRelRegistry()
+	if err := w.pkg.reserveCustomRels(rels, ""); err != nil {
+		return nil, err
+	}
+	rels.add(relTypeOfficeDoc, relTypeOfficeDoc, "ppt/presentation.xml")
+	rels.add(relTypeCoreProps, relTypeCoreProps, "docProps/core.xml")
+	rels.add(relTypeExtProps, relTypeExtProps, "docProps/app.xml")
+	w.pkg.addCustomRels(rels, "")
+	return rels, nil
+}
+
+// buildPresentationRels registers the relationships of the presentation
+// part: the slide master, the slides in order, then the document-wide
+// parts, the notes master and the VBA project.
+func (w *PPTXWriter) buildPresentationRels() (*relRegistry, error) {
+	rels := newRelRegistry()
+	if err := rels.reserve(relKeyMaster, "rId1", relTypeSlideMaster, "slideMasters/slideMaster1.xml"); err != nil {
+		return nil, err
+	}
+	if err := w.pkg.reserveCustomRels(rels, "ppt/presentation.xml"); err != nil {
+		return nil, err
+	}
+	for i, slide := range w.presentation.slides {
+		rels.add(slide, relTypeSlide, fmt.Sprintf("slides/slide%d.xml", i+1))
+	}
+	rels.add(relTypePresProps, relTypePresProps, "presProps.xml")
+	rels.add(relTypeViewProps, relTypeViewProps, "viewProps.xml")
+	rels.add(relTypeTableStyles, relTypeTableStyles, "tableStyles.xml")
+	rels.add(relTypeTheme, relTypeTheme, "theme/theme1.xml")
+	if w.presentation.hasNotes() {
+		rels.add(relTypeNotesMaster, relTypeNotesMaster, "notesMasters/notesMaster1.xml")
+	}
+	if w.hasComments() {
+		rels.add(relTypeCommentAuth, relTypeCommentAuth, "commentAuthors.xml")
+	}
+	if w.writesVBAProject() {
+		rels.add(relTypeVBAProject, relTypeVBAProject, "vbaProject.bin")
+	}
+	w.pkg.addCustomRels(rels, "ppt/presentation.xml")
+	return rels, nil
+}
@@ -0,0 +1,66 @@
+package gopresentation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAutoShapeRunHyperlinkRoundTrip(t *testing.T) {
+	p := New()
+	p.CreateSlide()
+	shape := NewAutoShape()
+	shape.CreateTextRun("visit ")
+	shape.CreateTextRun("example").SetHyperlink(NewHyperlink("https://example.com/").SetTooltip("Example"))
+	shape.CreateTextRun(" or ")
+	shape.CreateTextRun("slide 2").SetHyperlink(NewInternalHyperlink(2))
+	p.GetActiveSlide().AddShape(shape)
+
+	data := writePackage(t, p)
+	slideXML := packageParts(t, data, "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	if n := bytes.Count(slideXML, []byte("<a:hlinkClick")); n != 2 {
+		t.Errorf("slide1.xml has %d a:hlinkClick elements, want 2", n)
+	}
+	if problems, err := ValidatePackage(bytes.NewReader(data), int64(len(data))); err != nil || len(problems) > 0 {
+		t.Errorf("ValidatePackage = %v, %v", problems, err)
+	}
+
+	read := readPackage(t, data)
+	slide, err := read.GetSlide(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var links []*Hyperlink
+	forEachHyperlink(slide.shapes, func(h *Hyperlink) { links = append(links, h) })
+	if len(links) != 2 {
+		t.Fatalf("read %d hyperlinks, want 2", len(links))
+	}
+	if h := links[0]; h.IsInternal || h.URL != "https://example.com/" || h.Tooltip != "Example" {
+		t.Errorf("external link read as %+v", *h)
+	}
+	if h := links[1]; !h.IsInternal || h.SlideIndex() != 1 {
+		t.Errorf("internal link read as %+v", *h)
+	}
+}
+
+func TestHyperlinkToMissingSlide(t *testing.T) {
+	p := New()
+	shape := NewAutoShape()
+	shape.CreateTextRun("gone").SetHyperlink(NewInternalHyperlink(9))
+	p.GetActiveSlide().AddShape(shape)
+
+	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "hyperlink to missing slide 9") {
+		t.Errorf("Validate() = %v, want the missing slide reported", err)
+	}
+	data := writePackage(t, p)
+	if problems, err := ValidatePackage(bytes.NewReader(data), int64(len(data))); err != nil || len(problems) > 0 {
+		t.Errorf("ValidatePackage = %v, %v", problems, err)
+	}
+	slideXML := packageParts(t, data, "ppt/slides/slide1.xml")["ppt/slides/slide1.xml"]
+	if bytes.Contains(slideXML, []byte("hlinkClick")) {
+		t.Error("link to a missing slide written with a jump")
+	}
+	if !bytes.Contains(slideXML, []byte("gone")) {
+		t.Error("text of the link to a missing slide dropped")
+	}
+}
@@ -0,0 +1,132 @@
+package gopresentation
+
+import (
+	"image"
+	"runtime"
+	"sync"
+)
+
+// RendererPool renders slides for long-running services such as thumbnail
+// servers. It keeps a bounded set of font caches, one per concurrent render,
+// so faces are built once and reused across calls, and recycles the output
+// images returned through Release. A RendererPool is safe for concurrent use.
+type RendererPool struct {
+	opts    RenderOptions
+	fonts   *FontCache
+	workers chan *FontCache
+	images  sync.Pool
+}
+
+// NewRendererPool creates a pool rendering with opts (nil uses
+// DefaultRenderOptions). Parsed fonts are loaded once from opts.FontCache,
+// or from opts.FontDirs when it is nil, and shared by every render.
+// Up to GOMAXPROCS face caches are kept between calls. Decoded pictures are
+// shared through opts.ImageCache, or a cache of the pool's own when it is
+// nil.
+func NewRendererPool(opts *RenderOptions) *RendererPool {
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	rp := &RendererPool{opts: *opts}
+	rp.fonts = opts.FontCache
+	if rp.fonts == nil {
+		rp.fonts = NewFontCache(opts.FontDirs...)
+	}
+	if rp.opts.ImageCache == nil {
+		rp.opts.ImageCache = NewImageCache()
+	}
+	rp.workers = make(chan *FontCache, runtime.GOMAXPROCS(0))
+	return rp
+}
+
+// Render renders the slide at slideIndex of p. The image may reuse the
+// buffer of an image previously passed to Release; pass it to Release once
+// it is no longer needed.
+func (rp *RendererPool) Render(p *Presentation, slideIndex int) (*image.RGBA, error) {
+	var fc *FontCache
+	select {
+	case fc = <-rp.workers:
+	default:
+		fc = rp.fonts.fork()
+	}
+	defer func() {
+		select {
+		case rp.workers <- fc:
+		default:
+		}
+	}()
+
+	opts := rp.opts
+	opts.FontCache = fc
+	var dst *image.RGBA
+	if w, h := p.renderSize(&opts); w > 0 && h > 0 {
+		dst = rp.image(w, h)
+	}
+	return p.SlideToImageInto(dst, slideIndex, &opts)
+}
+
+// Release returns an image obtained from Render to the pool. The image must
+// not be used after it is released.
+func (rp *RendererPool) Release(img *image.RGBA) {
+	if img != nil {
+		rp.images.Put(img)
+	}
+}
+
+// image returns a released image resized to w×h when one has room, or a new
+// image otherwise.
+func (rp *RendererPool) image(w, h int) *image.RGBA {
+	if img, ok := rp.images.Get().(*image.RGBA); ok {
+		if reused := reuseRGBA(img, w, h); reused != nil {
+			return reused
+		}
+	}
+	return image.NewRGBA(image.Rect(0, 0, w, h))
+}
+
+// renderSize returns the pixel size SlideToImage renders slides at for opts.
+func (p *Presentation) renderSize(opts *RenderOptions) (int, int) {
+	if p.layout == nil || p.layout.CX <= 0 || p.layout.CY <= 0 {
+		return 0, 0
+	}
+	w := opts.Width
+	if w <= 0 {
+		w = 960
+	}
+	return w, int(float64(w) * float64(p.layout.CY) / float64(p.layout.CX))
+}
+
+// scratchPool recycles the temporary buffers used to rotate and composite
+// text and shadows.
+var scratchPool sync.Pool
+
+// newScratchRGBA returns a cleared w×h image, reusing a pooled buffer when
+// one is large enough. Release it with releaseScratchRGBA when done.
+func newScratchRGBA(w, h int) *image.RGBA {
+	if img, ok := scratchPool.Get().(*image.RGBA); ok {
+		if reused := reuseRGBA(img, w, h); reused != nil {
+			clear(reused.Pix)
+			return reused
+		}
+	}
+	return image.NewRGBA(image.Rect(0, 0, w, h))
+}
+
+// releaseScratchRGBA returns img to the scratch pool.
+func releaseScratchRGBA(img *image.RGBA) {
+	scratchPool.Put(img)
+}
+
+// reuseRGBA reslices the pixels of img as a w×h image, or returns nil when
+// its buffer is too small. The pixel contents are left as they were.
+func reuseRGBA(img *image.RGBA, w, h int) *image.RGBA {
+	n := 4 * w * h
+	if cap(img.Pix) < n {
+		return nil
+	}
+	return &image.RGBA{
+		Pix:    img.Pix[:n],
+		Stride: 4 * w,
+		Rect:   image.Rect(0, 0, w, h),
+	}
+}
@@ -0,0 +1,114 @@
+package gopresentation
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+)
+
+// RenderRegion renders the part of the slide at slideIndex inside region,
+// given in EMU, for exporting a diagram or callout from a slide as an image.
+// The image is opts.Width pixels wide, with the height following the
+// region's aspect ratio, and shows the slide as SlideToImage does,
+// background and master shapes included. A nil opts uses
+// DefaultRenderOptions.
+func (p *Presentation) RenderRegion(slideIndex int, region Rect, opts *RenderOptions) (image.Image, error) {
+	if slideIndex < 0 || slideIndex >= len(p.slides) {
+		return nil, fmt.Errorf("slide index %d out of range (0-%d)", slideIndex, len(p.slides)-1)
+	}
+	if region.Empty() {
+		return nil, errors.New("region is empty")
+	}
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	img, scale := regionImage(region, opts.Width)
+	r := p.newRenderer(img, scale, scale, slideIndex, opts)
+	slideRect := image.Rect(0, 0,
+		int(math.Round(float64(p.layout.CX)*scale)),
+		int(math.Round(float64(p.layout.CY)*scale)))
+	p.drawSlide(r, p.slides[slideIndex], slideRect, opts)
+	return atOrigin(img), nil
+}
+
+// RenderShape renders a single shape of one of the slides (or of the slide
+// master) on its own. The image covers the shape's bounding box and its
+// outline, opts.Width pixels wide, and is transparent around the shape
+// unless opts.BackgroundColor is set. A nil opts uses DefaultRenderOptions.
+func (p *Presentation) RenderShape(shape Shape, opts *RenderOptions) (image.Image, error) {
+	slideIndex := p.shapeSlideIndex(shape)
+	if slideIndex < 0 {
+		return nil, errors.New("shape not found in presentation")
+	}
+	region := shape.base().BoundingBox()
+	pad := shapeRenderPadding(shape)
+	region = Rect{X: region.X - pad, Y: region.Y - pad, Width: region.Width + 2*pad, Height: region.Height + 2*pad}
+	if region.Empty() {
+		return nil, errors.New("shape has no size")
+	}
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	img, scale := regionImage(region, opts.Width)
+	r := p.newRenderer(img, scale, scale, slideIndex, opts)
+	if opts.BackgroundColor != nil {
+		r.fillRectFast(img.Bounds(), *opts.BackgroundColor)
+	}
+	r.renderShape(shape)
+	return atOrigin(img), nil
+}
+
+// shapeSlideIndex returns the index of the slide holding shape, 0 for a
+// shape of the slide master, or -1 when the presentation has no such shape.
+func (p *Presentation) shapeSlideIndex(shape Shape) int {
+	for i, slide := range p.slides {
+		for _, s := range slide.shapes {
+			if s == shape {
+				return i
+			}
+		}
+	}
+	for _, s := range p.masterShapes() {
+		if s == shape {
+			return 0
+		}
+	}
+	return -1
+}
+
+// shapeRenderPadding returns how far in EMU shape may draw outside its
+// bounding box: half its outline, or for lines their arrowheads.
+func shapeRenderPadding(shape Shape) int64 {
+	if l, ok := shape.(*LineShape); ok {
+		w := int64(l.GetLineWidthEMU())
+		if l.headEnd != nil || l.tailEnd != nil {
+			return 3 * w
+		}
+		return w
+	}
+	if b := shape.base().border; b != nil && b.Style != BorderNone {
+		return int64(b.Width) * 12700 / 2
+	}
+	return 0
+}
+
+// regionImage allocates the image for rendering region, in EMU, width
+// pixels wide. Its bounds are the region in slide pixels so that shapes are
+// drawn at their place on the slide. It returns the image and the scale.
+func regionImage(region Rect, width int) (*image.RGBA, float64) {
+	if width <= 0 {
+		width = 960
+	}
+	scale := float64(width) / float64(region.Width)
+	x := int(math.Round(float64(region.X) * scale))
+	y := int(math.Round(float64(region.Y) * scale))
+	h := max(int(math.Round(float64(region.Height)*scale)), 1)
+	return image.NewRGBA(image.Rect(x, y, x+width, y+h)), scale
+}
+
+// atOrigin moves the bounds of img to the origin, keeping its pixels.
+func atOrigin(img *image.RGBA) *image.RGBA {
+	img.Rect = img.Rect.Sub(img.Rect.Min)
+	return img
+}
@@ -9,13 +9,18 @@ import (
 	_ "image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"log/slog"
 	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
+	"golang.org/x/image/bmp"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/fixed"
@@ -29,6 +34,8 @@ type ImageFormat int
 const (
 	ImageFormatPNG ImageFormat = iota
 	ImageFormatJPEG
+	ImageFormatBMP
+	ImageFormatWebP // lossless
 )
 
 // RenderOptions configures slide-to-image rendering.
@@ -36,12 +43,22 @@ type RenderOptions struct {
 	// Width is the output image width in pixels. Height is calculated from slide aspect ratio.
 	// Default: 960
 	Width int
-	// Format is the output image format (PNG or JPEG).
+	// Format is the output image format (PNG, JPEG, BMP or WebP).
 	Format ImageFormat
 	// JPEGQuality is the JPEG quality (1-100). Default: 90.
 	JPEGQuality int
 	// BackgroundColor overrides the slide background. Nil means use slide background or white.
 	BackgroundColor *color.RGBA
+	// TransparentBackground leaves the image transparent where the slide has
+	// no background of its own, instead of white, so that renders can be
+	// composited over other content such as video. It needs a format with
+	// alpha, PNG or WebP. BackgroundColor takes precedence.
+	TransparentBackground bool
+	// PremultipliedAlpha writes the color of PNG and WebP images
+	// premultiplied by alpha, as some compositors expect. By default it is
+	// straight, as the formats specify. The images returned by SlideToImage
+	// are always premultiplied, as image.RGBA is.
+	PremultipliedAlpha bool
 	// DPI is the rendering DPI for font sizing. Default: 96.
 	DPI float64
 	// FontDirs specifies additional directories to search for TrueType/OpenType fonts.
@@ -50,10 +67,45 @@ type RenderOptions struct {
 	// FontCache allows sharing a pre-configured FontCache across multiple renders.
 	// If nil, a new FontCache is created using FontDirs.
 	FontCache *FontCache
+	// ImageCache allows sharing decoded pictures across multiple renders.
+	// If nil, pictures are decoded once per render.
+	ImageCache *ImageCache
+	// FastRotation draws rotated shapes, text and pictures by taking the
+	// nearest pixel, which is faster but leaves jagged edges. By default
+	// they are sampled bilinearly with antialiased edges.
+	FastRotation bool
 	// OverlayOpacityScale scales the opacity of semi-transparent shape fills.
 	// Value between 0.0 and 1.0. Default 0 means use 1.0 (no change).
 	// Set to e.g. 0.5 to halve the opacity of overlays, making dark backgrounds brighter.
 	OverlayOpacityScale float64
+	// FontSubstitutions maps font names used in the presentation to fonts to
+	// render them with when the original is not installed, e.g. "Calibri" to
+	// "Carlito". Names are matched case-insensitively and substitutes are
+	// tried before the built-in fallback fonts.
+	FontSubstitutions map[string]string
+	// DebugOverlay draws, over the rendered slide, the frame, origin and
+	// index and name of each shape and the baselines of unrotated text,
+	// to help diagnose layout differences.
+	DebugOverlay bool
+	// Locale is the BCP 47 language tag, such as "de-DE" or "ar-EG", whose
+	// decimal and thousands separators and digits are used for slide
+	// numbers and chart axis values and data labels. Default: numbers are
+	// shown as their number formats write them.
+	Locale string
+	// Logger receives the time each slide render takes and the fonts text
+	// falls back to; nil logs nothing.
+	Logger *slog.Logger
+	// Metrics counts the slides rendered and the pictures decoded and times
+	// each slide render; nil reports nothing.
+	Metrics Metrics
+	// GammaCorrectText blends the antialiased edges of text in linear light
+	// rather than directly in sRGB, so that text keeps its weight and
+	// contrast. It matters most for thumbnails, whose text is a few pixels
+	// high, and makes drawing text slower.
+	GammaCorrectText bool
+	// TextHinting sets how glyphs are fitted to the pixel grid. Default:
+	// TextHintingFull.
+	TextHinting TextHinting
 }
 
 // DefaultRenderOptions returns default rendering options.
@@ -68,6 +120,22 @@ func DefaultRenderOptions() *RenderOptions {
 
 // SlideToImage renders a single slide to an image.
 func (p *Presentation) SlideToImage(slideIndex int, opts *RenderOptions) (image.Image, error) {
+	return p.SlideToImageInto(nil, slideIndex, opts)
+}
+
+// SlideToImageInto renders a single slide into dst, reusing its pixel buffer
+// when dst already has the output size; otherwise a new image is allocated.
+// It returns the image rendered into. Callers producing many images of the
+// same size can pass the previous result back in to avoid reallocating it.
+func (p *Presentation) SlideToImageInto(dst *image.RGBA, slideIndex int, opts *RenderOptions) (*image.RGBA, error) {
+	return p.renderSlideInto(dst, slideIndex, opts, nil, nil)
+}
+
+// renderSlideInto implements SlideToImageInto. When drawing is not nil,
+// what the renderer can draw as EMF records is collected into it instead.
+// When links is not nil, the rectangles of the unrotated runs with
+// hyperlinks are recorded in it.
+func (p *Presentation) renderSlideInto(dst *image.RGBA, slideIndex int, opts *RenderOptions, drawing *vectorDrawing, links runRects) (*image.RGBA, error) {
 	if slideIndex < 0 || slideIndex >= len(p.slides) {
 		return nil, fmt.Errorf("slide index %d out of range (0-%d)", slideIndex, len(p.slides)-1)
 	}
@@ -89,17 +157,40 @@ func (p *Presentation) SlideToImage(slideIndex int, opts *RenderOptions) (image.
 	scaleX := float64(imgW) / slideW
 	scaleY := float64(imgH) / slideH
 
-	img := image.NewRGBA(image.Rect(0, 0, imgW, imgH))
+	img := dst
+	if img == nil || img.Bounds() != image.Rect(0, 0, imgW, imgH) {
+		img = image.NewRGBA(image.Rect(0, 0, imgW, imgH))
+	} else {
+		clear(img.Pix)
+	}
 
+	r := p.newRenderer(img, scaleX, scaleY, slideIndex, opts)
+	if drawing != nil {
+		drawing.img = img
+		r.vector = drawing
+	}
+	r.links = links
+	p.drawSlide(r, slide, img.Bounds(), opts)
+	return img, nil
+}
+
+// newRenderer returns a renderer drawing the shapes of slide slideIndex
+// into img at the given scale.
+func (p *Presentation) newRenderer(img *image.RGBA, scaleX, scaleY float64, slideIndex int, opts *RenderOptions) *renderer {
 	fc := opts.FontCache
 	if fc == nil {
 		fc = NewFontCache(opts.FontDirs...)
 	}
+	ic := opts.ImageCache
+	if ic == nil {
+		ic = NewImageCache()
+	}
 	dpi := opts.DPI
 	if dpi <= 0 {
 		dpi = 96
 	}
 
+	locale := lookupNumberLocale(opts.Locale)
 	r := &renderer{
 		img:                 img,
 		scaleX:              scaleX,
@@ -107,28 +198,72 @@ func (p *Presentation) SlideToImage(slideIndex int, opts *RenderOptions) (image.
 		fontCache:           fc,
 		dpi:                 dpi,
 		overlayOpacityScale: opts.OverlayOpacityScale,
-	}
+		fontSubs:            fontSubstitutionMap(opts.FontSubstitutions),
+		slideNumber:         locale.localize(p.slideNumberText(slideIndex)),
+		themeColors:         p.themeColors,
+		defaultFont:         p.defaultFont,
+		imageCache:          ic,
+		fastRotation:        opts.FastRotation,
+		locale:              locale,
+		log:                 newRenderLog(opts.Logger, slideIndex),
+		metrics:             opts.Metrics,
+		gammaText:           opts.GammaCorrectText,
+		textHinting:         opts.TextHinting,
+	}
+	if opts.DebugOverlay {
+		r.debug = &debugOverlay{}
+	}
+	return r
+}
+
+// drawSlide draws the background, master shapes and shapes of slide with r.
+// slideRect is the whole slide in pixels, which r.img may cover only part of.
+func (p *Presentation) drawSlide(r *renderer, slide *Slide, slideRect image.Rectangle, opts *RenderOptions) {
+	start := time.Now()
+	timer := r.log.timer()
 
 	// Fill background
 	bgColor := color.RGBA{R: 255, G: 255, B: 255, A: 255}
 	drawn := false
+	background := slide.background
+	if background == nil && slide.backgroundRef != nil {
+		ref := *slide.backgroundRef
+		if argb, ok := p.themeColors[ref.SchemeColor]; ok && argb != "" {
+			ref.Color = NewColor(argb)
+		}
+		background = p.resolveBackgroundRef(&ref)
+	}
+	if background == nil && slide.backgroundRef == nil {
+		background = slide.inheritedBackground
+	}
 	if opts.BackgroundColor != nil {
 		bgColor = *opts.BackgroundColor
-	} else if slide.background != nil {
-		switch slide.background.Type {
+	} else if background != nil {
+		switch background.Type {
 		case FillSolid:
-			bgColor = argbToRGBA(slide.background.Color)
+			bgColor = r.colorRGBA(background.Color)
 		case FillGradientLinear:
-			r.fillGradientLinear(img.Bounds(), slide.background)
+			r.fillGradientLinear(slideRect, background)
 			drawn = true
 		case FillGradientPath:
-			r.fillGradientPath(img.Bounds(), slide.background)
+			r.fillGradientPath(slideRect, background)
+			drawn = true
+		case FillPicture:
+			r.fillRectFast(slideRect, bgColor)
+			r.fillPicture(slideRect, background)
 			drawn = true
 		}
 	}
-	if !drawn {
-		r.fillRectFast(img.Bounds(), bgColor)
+	if !drawn && (background != nil || opts.BackgroundColor != nil || !opts.TransparentBackground) {
+		r.fillRectFast(slideRect, bgColor)
 	}
+	timer.stage("background")
+
+	// Shapes added to the slide master are behind those of the slide.
+	for _, shape := range p.masterShapes() {
+		r.renderShape(shape)
+	}
+	timer.stage("master shapes")
 
 	// Render shapes in their original XML order (z-order).
 	// Shapes that appear earlier in the spTree are behind shapes that appear later,
@@ -136,8 +271,14 @@ func (p *Presentation) SlideToImage(slideIndex int, opts *RenderOptions) (image.
 	for _, shape := range slide.shapes {
 		r.renderShape(shape)
 	}
+	timer.stage("shapes")
 
-	return img, nil
+	if r.debug != nil {
+		r.drawDebugOverlay(slide.shapes)
+	}
+	timer.done("rendered slide", "shapes", len(slide.shapes))
+	countMetric(r.metrics, MetricSlidesRendered, 1)
+	observeMetric(r.metrics, MetricSlideRender, start)
 }
 
 // SlidesToImages renders all slides to images.
@@ -148,6 +289,9 @@ func (p *Presentation) SlidesToImages(opts *RenderOptions) ([]image.Image, error
 	if opts.FontCache == nil {
 		opts.FontCache = NewFontCache(opts.FontDirs...)
 	}
+	if opts.ImageCache == nil {
+		opts.ImageCache = NewImageCache()
+	}
 	images := make([]image.Image, len(p.slides))
 	for i := range p.slides {
 		img, err := p.SlideToImage(i, opts)
@@ -180,6 +324,53 @@ func (p *Presentation) SaveSlidesAsImages(pattern string, opts *RenderOptions) e
 	return nil
 }
 
+// Thumbnail renders just the chart to an image opts.Width pixels wide, with
+// the height following the chart's aspect ratio, for chart previews without
+// rendering the whole slide. The chart is drawn as on a slide but without
+// the presentation's theme. A nil opts uses DefaultRenderOptions.
+func (s *ChartShape) Thumbnail(opts *RenderOptions) (image.Image, error) {
+	if s.width <= 0 || s.height <= 0 {
+		return nil, fmt.Errorf("chart has no size")
+	}
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	imgW := opts.Width
+	if imgW <= 0 {
+		imgW = 960
+	}
+	imgH := max(int(float64(imgW)*float64(s.height)/float64(s.width)), 1)
+
+	fc := opts.FontCache
+	if fc == nil {
+		fc = NewFontCache(opts.FontDirs...)
+	}
+	dpi := opts.DPI
+	if dpi <= 0 {
+		dpi = 96
+	}
+	img := image.NewRGBA(image.Rect(0, 0, imgW, imgH))
+	r := &renderer{
+		img:          img,
+		scaleX:       float64(imgW) / float64(s.width),
+		scaleY:       float64(imgH) / float64(s.height),
+		fontCache:    fc,
+		dpi:          dpi,
+		fontSubs:     fontSubstitutionMap(opts.FontSubstitutions),
+		imageCache:   opts.ImageCache,
+		fastRotation: opts.FastRotation,
+		locale:       lookupNumberLocale(opts.Locale),
+		gammaText:    opts.GammaCorrectText,
+		textHinting:  opts.TextHinting,
+	}
+
+	// Draw the chart at the origin
+	c := *s
+	c.offsetX, c.offsetY = 0, 0
+	r.renderChart(&c)
+	return img, nil
+}
+
 func saveImage(img image.Image, path string, opts *RenderOptions) error {
 	if opts == nil {
 		opts = DefaultRenderOptions()
@@ -194,17 +385,7 @@ func saveImage(img image.Image, path string, opts *RenderOptions) error {
 	if err != nil {
 		return fmt.Errorf("create file: %w", err)
 	}
-	var encodeErr error
-	switch opts.Format {
-	case ImageFormatJPEG:
-		quality := opts.JPEGQuality
-		if quality <= 0 || quality > 100 {
-			quality = 90
-		}
-		encodeErr = jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
-	default:
-		encodeErr = png.Encode(f, img)
-	}
+	encodeErr := encodeImage(f, alphaImage(img, opts), opts.Format, opts.JPEGQuality)
 	closeErr := f.Close()
 	if encodeErr != nil {
 		return encodeErr
@@ -212,6 +393,52 @@ func saveImage(img image.Image, path string, opts *RenderOptions) error {
 	return closeErr
 }
 
+// RenderSlide renders a slide and writes it to w in opts.Format, so that
+// images can be streamed without temporary files.
+func (p *Presentation) RenderSlide(slideIndex int, w io.Writer, opts *RenderOptions) error {
+	img, err := p.SlideToImage(slideIndex, opts)
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	return encodeImage(w, alphaImage(img, opts), opts.Format, opts.JPEGQuality)
+}
+
+// alphaImage returns img as it should be encoded with opts: with
+// PremultipliedAlpha, the pixels of img as they are, marked as straight
+// alpha so that the encoders do not convert them.
+func alphaImage(img image.Image, opts *RenderOptions) image.Image {
+	rgba, ok := img.(*image.RGBA)
+	if !ok || !opts.PremultipliedAlpha {
+		return img
+	}
+	return &image.NRGBA{Pix: rgba.Pix, Stride: rgba.Stride, Rect: rgba.Rect}
+}
+
+// EncodeImage writes img to w in the given format. JPEG images use quality
+// 90 and WebP images are lossless.
+func EncodeImage(img image.Image, format ImageFormat, w io.Writer) error {
+	return encodeImage(w, img, format, 90)
+}
+
+func encodeImage(w io.Writer, img image.Image, format ImageFormat, jpegQuality int) error {
+	switch format {
+	case ImageFormatJPEG:
+		if jpegQuality <= 0 || jpegQuality > 100 {
+			jpegQuality = 90
+		}
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case ImageFormatBMP:
+		return bmp.Encode(w, img)
+	case ImageFormatWebP:
+		return encodeWebP(w, img)
+	default:
+		return png.Encode(w, img)
+	}
+}
+
 // --- renderer core ---
 
 type renderer struct {
@@ -222,6 +449,38 @@ type renderer struct {
 	dpi                 float64
 	overlayOpacityScale float64 // 0 means 1.0 (no change)
 	fontScale           float64 // normAutofit font scale factor (0 or 1.0 = no scaling)
+
+	// fontSubs maps lowercase font names to RenderOptions.FontSubstitutions.
+	fontSubs map[string]string
+	// slideNumber is the text of slide number fields on the slide.
+	slideNumber string
+	// themeColors maps theme color slots to the presentation's ARGB values.
+	themeColors map[string]string
+	// defaultFont is the presentation's default font, or nil.
+	defaultFont *Font
+	// debug collects what RenderOptions.DebugOverlay draws, or is nil.
+	debug *debugOverlay
+	// vector, when set, collects the text runs and the opaque fills and
+	// outlines it can write as EMF records instead of drawing them.
+	vector *vectorDrawing
+	// links, when set, collects where the runs with hyperlinks are drawn.
+	links runRects
+	// imageCache keeps the pictures decoded; nil decodes them each time.
+	imageCache *ImageCache
+	// fastRotation samples rotated content at the nearest pixel instead of
+	// bilinearly.
+	fastRotation bool
+	// locale shows numbers in the symbols of RenderOptions.Locale, or is
+	// nil.
+	locale *numberLocale
+	// log logs to RenderOptions.Logger, or is nil.
+	log *renderLog
+	// metrics reports to RenderOptions.Metrics, or is nil.
+	metrics Metrics
+	// gammaText and textHinting are the GammaCorrectText and TextHinting
+	// options.
+	gammaText   bool
+	textHinting TextHinting
 }
 
 func (r *renderer) renderShape(shape Shape) {
@@ -248,6 +507,14 @@ func (r *renderer) renderShape(shape Shape) {
 func (r *renderer) emuToPixelX(emu int64) int { return int(math.Round(float64(emu) * r.scaleX)) }
 func (r *renderer) emuToPixelY(emu int64) int { return int(math.Round(float64(emu) * r.scaleY)) }
 
+// emuBox converts a shape frame from EMU to pixels. The edges are rounded
+// rather than the size, so that shapes sharing an edge in EMU share it in
+// pixels, and fills, borders and adjacent shapes line up at any resolution.
+func (r *renderer) emuBox(offX, offY, cx, cy int64) (x, y, w, h int) {
+	x, y = r.emuToPixelX(offX), r.emuToPixelY(offY)
+	return x, y, r.emuToPixelX(offX+cx) - x, r.emuToPixelY(offY+cy) - y
+}
+
 // hundredthPtToPixelY converts hundredths of a point (from spcPts) to pixels.
 // spcPts values are in 1/100 of a point, e.g. 1200 = 12pt.
 // 1 point = 12700 EMU, so 1/100 point = 127 EMU.
@@ -256,7 +523,19 @@ func (r *renderer) hundredthPtToPixelY(val int) int {
 	return int(emu * r.scaleY)
 }
 
+// colorRGBA converts c to RGBA. Theme colors take their value from the
+// presentation's theme.
+func (r *renderer) colorRGBA(c Color) color.RGBA {
+	if c.Scheme != "" {
+		if argb, ok := r.themeColors[c.Scheme]; ok && isValidARGB(argb) && len(c.ARGB) == 8 {
+			c.ARGB = c.ARGB[:2] + argb[2:]
+		}
+	}
+	return argbToRGBA(c)
+}
+
 func argbToRGBA(c Color) color.RGBA {
+	c = c.Resolve()
 	return color.RGBA{R: c.GetRed(), G: c.GetGreen(), B: c.GetBlue(), A: c.GetAlpha()}
 }
 
@@ -289,6 +568,22 @@ func (r *renderer) blendPixel(x, y int, c color.RGBA) {
 	pix[off+3] = uint8(uint32(pix[off+3]) + (255-uint32(pix[off+3]))*a/255)
 }
 
+// blendPixelPremul composites the premultiplied color c, such as a pixel
+// of a scratch image, over the pixel at (x, y).
+func (r *renderer) blendPixelPremul(x, y int, c color.RGBA) {
+	b := r.img.Bounds()
+	if x < b.Min.X || x >= b.Max.X || y < b.Min.Y || y >= b.Max.Y || c.A == 0 {
+		return
+	}
+	off := (y-b.Min.Y)*r.img.Stride + (x-b.Min.X)*4
+	pix := r.img.Pix
+	ia := 255 - uint32(c.A)
+	pix[off] = uint8(uint32(c.R) + uint32(pix[off])*ia/255)
+	pix[off+1] = uint8(uint32(c.G) + uint32(pix[off+1])*ia/255)
+	pix[off+2] = uint8(uint32(c.B) + uint32(pix[off+2])*ia/255)
+	pix[off+3] = uint8(uint32(c.A) + uint32(pix[off+3])*ia/255)
+}
+
 // blendPixelF blends with fractional coverage (0.0–1.0) for anti-aliasing.
 func (r *renderer) blendPixelF(x, y int, c color.RGBA, coverage float64) {
 	if coverage <= 0 {
@@ -303,6 +598,12 @@ func (r *renderer) blendPixelF(x, y int, c color.RGBA, coverage float64) {
 
 // fillRectFast fills a rectangle with an opaque color using draw.Draw.
 func (r *renderer) fillRectFast(rect image.Rectangle, c color.RGBA) {
+	if rect = rect.Intersect(r.img.Bounds()); rect.Empty() {
+		return
+	}
+	if r.vector.fillShape(&vectorShape{kind: vectorRect, box: rect}, c) {
+		return
+	}
 	draw.Draw(r.img, rect, &image.Uniform{c}, image.Point{}, draw.Over)
 }
 
@@ -355,6 +656,19 @@ func rotatedBounds(cx, cy float64, w, h int, angleDeg int) image.Rectangle {
 	)
 }
 
+// vertTextRotation returns the clockwise rotation in degrees applied to text
+// laid out horizontally for the given bodyPr/tcPr vert value: vert text
+// reads top to bottom and vert270 text bottom to top.
+func vertTextRotation(dir string) int {
+	switch dir {
+	case "vert", "eaVert", "wordArtVert":
+		return 90
+	case "vert270":
+		return 270
+	}
+	return 0
+}
+
 // rotateAndComposite rotates src (sw x sh) by angleDeg and composites it into
 // dst at (dx, dy) fitting into a dw x dh area. Used for vertical text where
 // the text is drawn into a buffer with swapped dimensions then rotated back.
@@ -394,7 +708,7 @@ func rotateAndComposite(dst *image.RGBA, src *image.RGBA, dx, dy, dw, dh, angleD
 				if a == 0 {
 					continue
 				}
-				dOff := py*dst.Stride + px*4
+				dOff := dst.PixOffset(px, py)
 				if a == 255 || dst.Pix[dOff+3] == 0 {
 					copy(dst.Pix[dOff:dOff+4], src.Pix[sOff:sOff+4])
 				} else {
@@ -414,6 +728,42 @@ func rotateAndComposite(dst *image.RGBA, src *image.RGBA, dx, dy, dw, dh, angleD
 	}
 }
 
+// sampleBilinear returns the color of src at (x, y), interpolated between
+// the four nearest pixel centers. Pixels outside src count as transparent,
+// which antialiases the edges of rotated content. src holds premultiplied
+// colors, so they interpolate without fringes.
+func sampleBilinear(src *image.RGBA, x, y float64) color.RGBA {
+	x -= 0.5
+	y -= 0.5
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if x0 < -1 || y0 < -1 || x0 >= w || y0 >= h {
+		return color.RGBA{}
+	}
+
+	var sum [4]float64
+	add := func(px, py int, weight float64) {
+		if px < 0 || py < 0 || px >= w || py >= h || weight == 0 {
+			return
+		}
+		off := py*src.Stride + px*4
+		for ch := range 4 {
+			sum[ch] += float64(src.Pix[off+ch]) * weight
+		}
+	}
+	add(x0, y0, (1-fx)*(1-fy))
+	add(x0+1, y0, fx*(1-fy))
+	add(x0, y0+1, (1-fx)*fy)
+	add(x0+1, y0+1, fx*fy)
+	return color.RGBA{
+		R: uint8(sum[0] + 0.5),
+		G: uint8(sum[1] + 0.5),
+		B: uint8(sum[2] + 0.5),
+		A: uint8(sum[3] + 0.5),
+	}
+}
+
 func (r *renderer) renderRotated(x, y, w, h, rotation int, flipH, flipV bool, drawFn func(tmp *renderer)) {
 	r.renderRotatedExpanded(x, y, w, h, h, rotation, flipH, flipV, drawFn)
 }
@@ -428,8 +778,9 @@ func (r *renderer) renderRotatedExpanded(x, y, w, h, bufH, rotation int, flipH,
 	if bufH < h {
 		bufH = h
 	}
-	tmp := image.NewRGBA(image.Rect(0, 0, w, bufH))
-	tmpR := &renderer{img: tmp, scaleX: r.scaleX, scaleY: r.scaleY, fontCache: r.fontCache, dpi: r.dpi, fontScale: r.fontScale}
+	tmp := newScratchRGBA(w, bufH)
+	defer releaseScratchRGBA(tmp)
+	tmpR := &renderer{img: tmp, scaleX: r.scaleX, scaleY: r.scaleY, fontCache: r.fontCache, dpi: r.dpi, fontScale: r.fontScale, fontSubs: r.fontSubs, themeColors: r.themeColors, defaultFont: r.defaultFont, imageCache: r.imageCache, fastRotation: r.fastRotation, locale: r.locale, log: r.log, metrics: r.metrics, gammaText: r.gammaText, textHinting: r.textHinting}
 	drawFn(tmpR)
 
 	if rotation == 0 && !flipH && !flipV {
@@ -451,7 +802,7 @@ func (r *renderer) renderRotatedExpanded(x, y, w, h, bufH, rotation int, flipH,
 				}
 				sOff := sy*tmp.Stride + sx*4
 				if tmp.Pix[sOff+3] > 0 {
-					r.blendPixel(x+px, y+py, color.RGBA{
+					r.blendPixelPremul(x+px, y+py, color.RGBA{
 						R: tmp.Pix[sOff], G: tmp.Pix[sOff+1],
 						B: tmp.Pix[sOff+2], A: tmp.Pix[sOff+3],
 					})
@@ -477,16 +828,25 @@ func (r *renderer) renderRotatedExpanded(x, y, w, h, bufH, rotation int, flipH,
 	destCY := float64(y) + cy
 
 	bounds := rotatedBounds(destCX, destCY, w, bufH, rotation)
+	if !r.fastRotation {
+		// Bilinear samples fade out over the pixel beyond each edge
+		bounds = bounds.Inset(-1)
+	}
 	imgBounds := r.img.Bounds()
 	minDY := maxInt(bounds.Min.Y, imgBounds.Min.Y)
 	maxDY := minInt(bounds.Max.Y, imgBounds.Max.Y)
 	minDX := maxInt(bounds.Min.X, imgBounds.Min.X)
 	maxDX := minInt(bounds.Max.X, imgBounds.Max.X)
 
+	// Bilinear sampling maps pixel centers rather than corners
+	center := 0.5
+	if r.fastRotation {
+		center = 0
+	}
 	for dy := minDY; dy < maxDY; dy++ {
-		ry := float64(dy) - destCY
+		ry := float64(dy) + center - destCY
 		for dx := minDX; dx < maxDX; dx++ {
-			rx := float64(dx) - destCX
+			rx := float64(dx) + center - destCX
 			// OOXML forward transform order: flip first, then rotate.
 			// Inverse: un-rotate first, then un-flip.
 			// Step 1: un-rotate (inverse rotation)
@@ -501,11 +861,15 @@ func (r *renderer) renderRotatedExpanded(x, y, w, h, bufH, rotation int, flipH,
 			}
 			sx := ux + cx
 			sy := uy + cy
+			if !r.fastRotation {
+				r.blendPixelPremul(dx, dy, sampleBilinear(tmp, sx, sy))
+				continue
+			}
 			ix, iy := int(sx), int(sy)
 			if ix >= 0 && ix < w && iy >= 0 && iy < bufH {
 				sOff := iy*tmp.Stride + ix*4
 				if tmp.Pix[sOff+3] > 0 {
-					r.blendPixel(dx, dy, color.RGBA{
+					r.blendPixelPremul(dx, dy, color.RGBA{
 						R: tmp.Pix[sOff], G: tmp.Pix[sOff+1],
 						B: tmp.Pix[sOff+2], A: tmp.Pix[sOff+3],
 					})
@@ -548,10 +912,7 @@ func (r *renderer) renderGroup(g *GroupShape) {
 		}
 		return
 	}
-	x := r.emuToPixelX(g.offsetX)
-	y := r.emuToPixelY(g.offsetY)
-	w := r.emuToPixelX(g.width)
-	h := r.emuToPixelY(g.height)
+	x, y, w, h := r.emuBox(g.offsetX, g.offsetY, g.width, g.height)
 	r.renderRotated(x, y, w, h, rotation, flipH, flipV, func(tmp *renderer) {
 		// Shift children to render relative to (0,0) in the temp buffer.
 		// Children have absolute slide coordinates; subtract group origin.
@@ -576,10 +937,13 @@ func (r *renderer) renderGroup(g *GroupShape) {
 // --- Shape rendering ---
 
 func (r *renderer) renderRichText(s *RichTextShape) {
-	x := r.emuToPixelX(s.offsetX)
-	y := r.emuToPixelY(s.offsetY)
-	w := r.emuToPixelX(s.width)
-	h := r.emuToPixelY(s.height)
+	// Apply lstStyle level defaults to a copy so the shape itself is untouched
+	if hasListLevels(&s.listStyle) {
+		c := *s
+		c.paragraphs = resolveListStyle(&s.listStyle, s.paragraphs, false)
+		s = &c
+	}
+	x, y, w, h := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 	rotation := s.GetRotation()
 	flipH := s.GetFlipHorizontal()
 	flipV := s.GetFlipVertical()
@@ -620,12 +984,7 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 	}
 
 	// Vertical text direction adds implicit rotation
-	vertRotation := 0
-	if s.textDirection == "vert" || s.textDirection == "eaVert" || s.textDirection == "wordArtVert" {
-		vertRotation = 270
-	} else if s.textDirection == "vert270" {
-		vertRotation = 90
-	}
+	vertRotation := vertTextRotation(s.textDirection)
 
 	// Estimate total text height to detect overflow.
 	// PowerPoint does not clip text to the text box boundary, so we must
@@ -807,13 +1166,13 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 			if s.customPath != nil {
 				// Draw border along the custom geometry path
 				pts := tr.customPathToPixelPoints(s.customPath, ox, oy, w, h)
-				bc := argbToRGBA(s.border.Color)
+				bc := r.colorRGBA(s.border.Color)
 				if len(pts) >= 2 {
 					if s.border.Style == BorderDash || s.border.Style == BorderDot {
 						tr.drawDashedPolylineAA(pts, bc, pw, s.border.Style)
 					} else {
 						for i := 1; i < len(pts); i++ {
-							tr.drawLineAA(int(pts[i-1].x), int(pts[i-1].y), int(pts[i].x), int(pts[i].y), bc, pw)
+							tr.drawLineAAf(pts[i-1].x, pts[i-1].y, pts[i].x, pts[i].y, bc, pw)
 						}
 					}
 					// Draw arrowheads at the ends of the custom path
@@ -830,7 +1189,7 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 					}
 				}
 			} else {
-				tr.drawRectBorder(rect, argbToRGBA(s.border.Color), pw, s.border.Style)
+				tr.drawRectBorder(rect, r.colorRGBA(s.border.Color), pw, s.border.Style)
 			}
 		} else if s.customPath != nil && (s.headEnd != nil || s.tailEnd != nil) {
 			// No visible border but has arrowheads — still need to draw them along the path
@@ -839,7 +1198,7 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 				pw := maxInt(int(tr.scaleX*12700.0), 1)
 				bc := color.RGBA{A: 255} // default black
 				if s.border != nil {
-					bc = argbToRGBA(s.border.Color)
+					bc = r.colorRGBA(s.border.Color)
 				}
 				intPts := make([][2]int, len(pts))
 				for i, p := range pts {
@@ -883,10 +1242,11 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 				// For vertical text, draw into a rotated buffer with swapped dimensions.
 				vtw, vth := drawTH, tw // text area: width=drawTH, height=tw (before rotation)
 				if vtw > 0 && vth > 0 {
-					tmp := image.NewRGBA(image.Rect(0, 0, vtw, vth))
-					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale}
+					tmp := newScratchRGBA(vtw, vth)
+					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale, fontSubs: tr.fontSubs, themeColors: tr.themeColors, defaultFont: tr.defaultFont, imageCache: tr.imageCache, fastRotation: tr.fastRotation, locale: tr.locale, log: tr.log, metrics: tr.metrics, gammaText: tr.gammaText, textHinting: tr.textHinting}
 					tmpR.drawParagraphs(s.paragraphs, 0, 0, vtw, vth, s.textAnchor, wordWrap)
 					rotateAndComposite(tr.img, tmp, tx, ty, tw, drawTH, vertRotation)
+					releaseScratchRGBA(tmp)
 				}
 			} else {
 				tr.drawParagraphs(s.paragraphs, tx, ty, tw, drawTH, s.textAnchor, wordWrap)
@@ -924,10 +1284,11 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 			if vertRotation != 0 {
 				vtw, vth := drawTH, tw
 				if vtw > 0 && vth > 0 {
-					tmp := image.NewRGBA(image.Rect(0, 0, vtw, vth))
-					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale}
+					tmp := newScratchRGBA(vtw, vth)
+					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale, fontSubs: tr.fontSubs, themeColors: tr.themeColors, defaultFont: tr.defaultFont, imageCache: tr.imageCache, fastRotation: tr.fastRotation, locale: tr.locale, log: tr.log, metrics: tr.metrics, gammaText: tr.gammaText, textHinting: tr.textHinting}
 					tmpR.drawParagraphs(s.paragraphs, 0, 0, vtw, vth, s.textAnchor, wordWrap)
 					rotateAndComposite(tr.img, tmp, tx, ty, tw, drawTH, vertRotation)
+					releaseScratchRGBA(tmp)
 				}
 			} else {
 				tr.drawParagraphs(s.paragraphs, tx, ty, tw, drawTH, s.textAnchor, wordWrap)
@@ -946,10 +1307,7 @@ func (r *renderer) renderRichText(s *RichTextShape) {
 }
 
 func (r *renderer) renderDrawing(s *DrawingShape) {
-	x := r.emuToPixelX(s.offsetX)
-	y := r.emuToPixelY(s.offsetY)
-	w := r.emuToPixelX(s.width)
-	h := r.emuToPixelY(s.height)
+	x, y, w, h := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 
 	imgData := s.data
 	if len(imgData) == 0 && s.path != "" {
@@ -957,22 +1315,28 @@ func (r *renderer) renderDrawing(s *DrawingShape) {
 			imgData = data
 		}
 	}
+	if len(imgData) == 0 && s.missingMedia != "" {
+		r.renderMissingPicture(s, x, y, w, h)
+		return
+	}
 	if len(imgData) == 0 {
 		return
 	}
 
-	srcImg, _, err := image.Decode(bytes.NewReader(imgData))
-	if err != nil {
-		// Try to extract bitmap from WMF/EMF metafiles
-		if extracted := decodeMetafileBitmap(imgData, r.fontCache); extracted != nil {
-			srcImg = extracted
-			err = nil
-		}
+	// Decode for the size the visible part of the picture is drawn at
+	decodeW, decodeH := w, h
+	if visible := 100000 - s.cropLeft - s.cropRight; visible > 0 && visible < 100000 {
+		decodeW = int(int64(w) * 100000 / int64(visible))
 	}
-	if err != nil {
+	if visible := 100000 - s.cropTop - s.cropBottom; visible > 0 && visible < 100000 {
+		decodeH = int(int64(h) * 100000 / int64(visible))
+	}
+	decoded := r.imageCache.decode(imgData, decodeW, decodeH, r.fontCache, r.metrics)
+	if decoded == nil {
 		r.drawRect(image.Rect(x, y, x+w, y+h), color.RGBA{R: 200, G: 200, B: 200, A: 255}, 1)
 		return
 	}
+	var srcImg image.Image = decoded
 
 	// Apply srcRect crop if set (values are in 1/1000 of a percent)
 	if s.cropLeft > 0 || s.cropTop > 0 || s.cropRight > 0 || s.cropBottom > 0 {
@@ -1026,11 +1390,33 @@ func (r *renderer) renderDrawing(s *DrawingShape) {
 	}
 }
 
+// renderMissingPicture draws a picture read without its media as a gray
+// placeholder showing its alt text, or its name when it has none.
+func (r *renderer) renderMissingPicture(s *DrawingShape, x, y, w, h int) {
+	text := s.description
+	if text == "" {
+		text = s.name
+	}
+	drawPlaceholder := func(tr *renderer) {
+		ox, oy := x, y
+		if tr != r {
+			ox, oy = 0, 0
+		}
+		rect := image.Rect(ox, oy, ox+w, oy+h)
+		tr.fillRectBlend(rect, color.RGBA{R: 235, G: 235, B: 235, A: 255})
+		tr.drawRect(rect, color.RGBA{R: 160, G: 160, B: 160, A: 255}, 1)
+		tr.drawFontStringCentered(text, NewFont(), color.RGBA{R: 90, G: 90, B: 90, A: 255}, rect)
+	}
+	// The text is not mirrored with the picture
+	if rotation := s.GetRotation(); rotation != 0 {
+		r.renderRotated(x, y, w, h, rotation, false, false, drawPlaceholder)
+	} else {
+		drawPlaceholder(r)
+	}
+}
+
 func (r *renderer) renderAutoShape(s *AutoShape) {
-	x := r.emuToPixelX(s.offsetX)
-	y := r.emuToPixelY(s.offsetY)
-	w := r.emuToPixelX(s.width)
-	h := r.emuToPixelY(s.height)
+	x, y, w, h := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 	rotation := s.GetRotation()
 	flipH := s.GetFlipHorizontal()
 	flipV := s.GetFlipVertical()
@@ -1043,12 +1429,7 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 	defer func() { r.fontScale = prevFontScale }()
 
 	// Vertical text direction
-	vertRotation := 0
-	if s.textDirection == "vert" || s.textDirection == "eaVert" || s.textDirection == "wordArtVert" {
-		vertRotation = 270
-	} else if s.textDirection == "vert270" {
-		vertRotation = 90
-	}
+	vertRotation := vertTextRotation(s.textDirection)
 
 	drawContent := func(tr *renderer) {
 		ox, oy := x, y
@@ -1149,7 +1530,7 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			// When default insets are used and text overflows, reduce insets
 			// to make room. This handles font metric differences between systems.
 			if !s.insetsSet {
-				textH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, true)
+				textH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, s.wordWrap)
 				if textH > th && th > 0 && (pxT+pxB) > 0 {
 					needed := textH - th
 					avail := pxT + pxB
@@ -1174,13 +1555,13 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			// CJK font metrics in Go are often larger than PowerPoint's.
 			// Use a conservative floor to avoid making text too small.
 			if (s.fontScale == 0 || s.fontScale == 100000) {
-				atextH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, true)
+				atextH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, s.wordWrap)
 				if atextH > h && h > 0 && atextH > th && th > 0 {
 					lo, hi := 0.65, 1.0
 					for i := 0; i < 10; i++ {
 						mid := (lo + hi) / 2
 						r.fontScale = mid
-						mh := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, true)
+						mh := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, s.wordWrap)
 						if mh > th {
 							hi = mid
 						} else {
@@ -1194,9 +1575,9 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			// Horizontal overflow: shrink font when wrapped lines still
 			// exceed the text area width due to font metric differences.
 			// Apply the same 3% tolerance used by wrapRunLine.
-			if tw > 0 && (s.fontScale == 0 || s.fontScale == 100000) {
+			if s.wordWrap && tw > 0 && (s.fontScale == 0 || s.fontScale == 100000) {
 				hTol := tw * 103 / 100
-				maxLW := r.measureMaxLineWidth(s.paragraphs, tw, true)
+				maxLW := r.measureMaxLineWidth(s.paragraphs, tw, s.wordWrap)
 				if maxLW > hTol {
 					lo, hi := 0.5, r.fontScale
 					if hi <= 0 {
@@ -1205,7 +1586,7 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 					for i := 0; i < 12; i++ {
 						mid := (lo + hi) / 2
 						r.fontScale = mid
-						mw := r.measureMaxLineWidth(s.paragraphs, tw, true)
+						mw := r.measureMaxLineWidth(s.paragraphs, tw, s.wordWrap)
 						if mw > hTol {
 							hi = mid
 						} else {
@@ -1219,16 +1600,17 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			if vertRotation != 0 {
 				vtw, vth := th, tw
 				if vtw > 0 && vth > 0 {
-					tmp := image.NewRGBA(image.Rect(0, 0, vtw, vth))
-					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale}
-					tmpR.drawParagraphs(s.paragraphs, 0, 0, vtw, vth, s.textAnchor, true)
+					tmp := newScratchRGBA(vtw, vth)
+					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale, fontSubs: tr.fontSubs, themeColors: tr.themeColors, defaultFont: tr.defaultFont, imageCache: tr.imageCache, fastRotation: tr.fastRotation, locale: tr.locale, log: tr.log, metrics: tr.metrics, gammaText: tr.gammaText, textHinting: tr.textHinting}
+					tmpR.drawParagraphs(s.paragraphs, 0, 0, vtw, vth, s.textAnchor, s.wordWrap)
 					rotateAndComposite(tr.img, tmp, tx, ty, tw, th, vertRotation)
+					releaseScratchRGBA(tmp)
 				}
 			} else {
-				tr.drawParagraphs(s.paragraphs, tx, ty, tw, th, s.textAnchor, true)
+				tr.drawParagraphs(s.paragraphs, tx, ty, tw, th, s.textAnchor, s.wordWrap)
 			}
 		} else if s.text != "" {
-			tr.drawStringCentered(s.text, tr.getFace(NewFont()), color.RGBA{A: 255}, rect)
+			tr.drawFontStringCentered(s.text, NewFont(), color.RGBA{A: 255}, rect)
 		}
 	}
 
@@ -1243,7 +1625,7 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 	if needsRtTriSwap {
 		drawSwapped := func(tr *renderer) {
 			if s.fill != nil && s.fill.Type != FillNone {
-				fc := argbToRGBA(s.fill.Color)
+				fc := r.colorRGBA(s.fill.Color)
 				fc = tr.scaleAlpha(fc)
 				// Draw mirror-image triangle that, after correct clockwise
 				// rotation, produces the expected right-triangle orientation.
@@ -1337,7 +1719,7 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			if tw < 1 { tw = w }
 			if th < 1 { th = h }
 			if !s.insetsSet {
-				textH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, true)
+				textH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, s.wordWrap)
 				if textH > th && th > 0 && (pxT+pxB) > 0 {
 					needed := textH - th
 					avail := pxT + pxB
@@ -1357,13 +1739,13 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			}
 			// Auto-shrink when text overflows
 			if s.fontScale == 0 || s.fontScale == 100000 {
-				atextH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, true)
+				atextH := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, s.wordWrap)
 				if atextH > h && h > 0 && atextH > th && th > 0 {
 					lo, hi := 0.65, 1.0
 					for i := 0; i < 10; i++ {
 						mid := (lo + hi) / 2
 						r.fontScale = mid
-						mh := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, true)
+						mh := r.measureParagraphsHeight(s.paragraphs, tw, th, s.textAnchor, s.wordWrap)
 						if mh > th {
 							hi = mid
 						} else {
@@ -1374,8 +1756,8 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 				}
 				// Horizontal overflow — apply 3% tolerance matching wrapRunLine
 				hTol := tw * 103 / 100
-				maxLW := r.measureMaxLineWidth(s.paragraphs, tw, true)
-				if maxLW > hTol && tw > 0 {
+				maxLW := r.measureMaxLineWidth(s.paragraphs, tw, s.wordWrap)
+				if s.wordWrap && maxLW > hTol && tw > 0 {
 					lo, hi := 0.5, r.fontScale
 					if hi <= 0 {
 						hi = 1.0
@@ -1383,7 +1765,7 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 					for i := 0; i < 12; i++ {
 						mid := (lo + hi) / 2
 						r.fontScale = mid
-						mw := r.measureMaxLineWidth(s.paragraphs, tw, true)
+						mw := r.measureMaxLineWidth(s.paragraphs, tw, s.wordWrap)
 						if mw > hTol {
 							hi = mid
 						} else {
@@ -1396,13 +1778,14 @@ func (r *renderer) renderAutoShape(s *AutoShape) {
 			if vertRotation != 0 {
 				vtw, vth := th, tw
 				if vtw > 0 && vth > 0 {
-					tmp := image.NewRGBA(image.Rect(0, 0, vtw, vth))
-					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale}
-					tmpR.drawParagraphs(s.paragraphs, 0, 0, vtw, vth, s.textAnchor, true)
+					tmp := newScratchRGBA(vtw, vth)
+					tmpR := &renderer{img: tmp, scaleX: tr.scaleX, scaleY: tr.scaleY, fontCache: tr.fontCache, dpi: tr.dpi, fontScale: tr.fontScale, fontSubs: tr.fontSubs, themeColors: tr.themeColors, defaultFont: tr.defaultFont, imageCache: tr.imageCache, fastRotation: tr.fastRotation, locale: tr.locale, log: tr.log, metrics: tr.metrics, gammaText: tr.gammaText, textHinting: tr.textHinting}
+					tmpR.drawParagraphs(s.paragraphs, 0, 0, vtw, vth, s.textAnchor, s.wordWrap)
 					rotateAndComposite(tr.img, tmp, tx, ty, tw, th, vertRotation)
+					releaseScratchRGBA(tmp)
 				}
 			} else {
-				tr.drawParagraphs(s.paragraphs, tx, ty, tw, th, s.textAnchor, true)
+				tr.drawParagraphs(s.paragraphs, tx, ty, tw, th, s.textAnchor, s.wordWrap)
 			}
 		}
 		if rotation != 0 {
@@ -1421,7 +1804,7 @@ func (r *renderer) renderAutoShapeFill(s *AutoShape, x, y, w, h int) {
 	if s.fill == nil || s.fill.Type == FillNone {
 		return
 	}
-	fc := argbToRGBA(s.fill.Color)
+	fc := r.colorRGBA(s.fill.Color)
 	fc = r.scaleAlpha(fc)
 	rect := image.Rect(x, y, x+w, y+h)
 
@@ -1500,7 +1883,7 @@ func (r *renderer) renderAutoShapeBorder(s *AutoShape, x, y, w, h int) {
 	if s.border == nil || s.border.Style == BorderNone {
 		return
 	}
-	bc := argbToRGBA(s.border.Color)
+	bc := r.colorRGBA(s.border.Color)
 	pw := maxInt(int(float64(maxInt(s.border.Width, 1))*12700.0*r.scaleX), 1)
 
 	switch s.shapeType {
@@ -1678,7 +2061,7 @@ func (r *renderer) renderArcBorder(s *AutoShape, x, y, w, h int, bc color.RGBA,
 		r.drawDashedPolylineAA(pts, bc, pw, ls)
 	} else {
 		for i := 1; i < len(pts); i++ {
-			r.drawLineAA(int(pts[i-1].x), int(pts[i-1].y), int(pts[i].x), int(pts[i].y), bc, pw)
+			r.drawLineAAf(pts[i-1].x, pts[i-1].y, pts[i].x, pts[i].y, bc, pw)
 		}
 	}
 
@@ -1697,6 +2080,9 @@ func (r *renderer) renderArcBorder(s *AutoShape, x, y, w, h int, bc color.RGBA,
 }
 
 func (r *renderer) renderLine(s *LineShape) {
+	if s.lineStyle == BorderNone {
+		return
+	}
 	rotation := s.GetRotation()
 	if rotation != 0 {
 		// For rotated connectors, compute the path in local coordinates,
@@ -1704,8 +2090,7 @@ func (r *renderer) renderLine(s *LineShape) {
 		r.renderLineRotated(s)
 		return
 	}
-	ox := r.emuToPixelX(s.offsetX)
-	oy := r.emuToPixelY(s.offsetY)
+	ox, oy, _, _ := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 	r.renderLineAt(s, ox, oy)
 }
 
@@ -1723,10 +2108,7 @@ func (r *renderer) renderLineRotated(s *LineShape) {
 	// Custom geometry path with rotation — convert path to pixel coords,
 	// then rotate around the bounding box center.
 	if s.customPath != nil && len(s.customPath.Commands) > 0 {
-		ox := r.emuToPixelX(s.offsetX)
-		oy := r.emuToPixelY(s.offsetY)
-		w := r.emuToPixelX(s.width)
-		h := r.emuToPixelY(s.height)
+		ox, oy, w, h := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 		pts := r.customPathToPixelPoints(s.customPath, ox, oy, w, h)
 		if len(pts) >= 2 {
 			// Rotate around bounding box center
@@ -1743,13 +2125,13 @@ func (r *renderer) renderLineRotated(s *LineShape) {
 			}
 
 			pw := maxInt(int(float64(s.GetLineWidthEMU())*r.scaleX), 1)
-			c := argbToRGBA(s.lineColor)
+			c := r.colorRGBA(s.lineColor)
 			ls := s.lineStyle
 			if ls == BorderDash || ls == BorderDot {
 				r.drawDashedPolylineAA(pts, c, pw, ls)
 			} else {
 				for i := 1; i < len(pts); i++ {
-					r.drawLineAA(int(pts[i-1].x), int(pts[i-1].y), int(pts[i].x), int(pts[i].y), c, pw)
+					r.drawLineAAf(pts[i-1].x, pts[i-1].y, pts[i].x, pts[i].y, c, pw)
 				}
 			}
 			intPts := make([][2]int, len(pts))
@@ -1844,7 +2226,7 @@ func (r *renderer) renderLineRotated(s *LineShape) {
 		py2 := int(math.Round(rey * r.scaleY))
 
 		pw := maxInt(int(float64(s.GetLineWidthEMU())*r.scaleX), 1)
-		c := argbToRGBA(s.lineColor)
+		c := r.colorRGBA(s.lineColor)
 		r.renderCurvedConnector(s.connectorType, px1, py1, px2, py2, s.adjustValues, c, pw, s.lineStyle, s.headEnd, s.tailEnd)
 		return
 
@@ -1887,7 +2269,7 @@ func (r *renderer) renderLineRotated(s *LineShape) {
 	}
 
 	pw := maxInt(int(float64(s.GetLineWidthEMU())*r.scaleX), 1)
-	c := argbToRGBA(s.lineColor)
+	c := r.colorRGBA(s.lineColor)
 	ls := s.lineStyle
 
 	drawSeg := func(ax, ay, bx, by int) {
@@ -1915,8 +2297,7 @@ func (r *renderer) renderLineRotated(s *LineShape) {
 // renderLineAt draws a line/connector with the bounding box top-left at (ox, oy).
 // Flip and adjust values are applied relative to this origin.
 func (r *renderer) renderLineAt(s *LineShape, ox, oy int) {
-	w := r.emuToPixelX(s.width)
-	h := r.emuToPixelY(s.height)
+	_, _, w, h := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 
 	// Visual start/end (after flip) — headEnd is at visual start (x1,y1),
 	// tailEnd is at visual end (x2,y2). Flip attributes determine which
@@ -1935,7 +2316,7 @@ func (r *renderer) renderLineAt(s *LineShape, ox, oy int) {
 	}
 	// lineWidth in EMU, convert to pixels
 	pw := maxInt(int(float64(s.GetLineWidthEMU())*r.scaleX), 1)
-	c := argbToRGBA(s.lineColor)
+	c := r.colorRGBA(s.lineColor)
 	ls := s.lineStyle
 
 	// Custom geometry path (freeform curved arrows, etc.)
@@ -1946,7 +2327,7 @@ func (r *renderer) renderLineAt(s *LineShape, ox, oy int) {
 				r.drawDashedPolylineAA(pts, c, pw, ls)
 			} else {
 				for i := 1; i < len(pts); i++ {
-					r.drawLineAA(int(pts[i-1].x), int(pts[i-1].y), int(pts[i].x), int(pts[i].y), c, pw)
+					r.drawLineAAf(pts[i-1].x, pts[i-1].y, pts[i].x, pts[i].y, c, pw)
 				}
 			}
 			intPts := make([][2]int, len(pts))
@@ -2325,8 +2706,8 @@ func (r *renderer) drawArrowHead(x1, y1, x2, y2 int, c color.RGBA, lineWidth int
 		p2 := fpoint{baseX + perpX*halfW, baseY + perpY*halfW}
 		p3 := fpoint{baseX - perpX*halfW, baseY - perpY*halfW}
 		lw := maxInt(lineWidth, 1)
-		r.drawLineAA(int(p2.x), int(p2.y), int(tipX), int(tipY), c, lw)
-		r.drawLineAA(int(tipX), int(tipY), int(p3.x), int(p3.y), c, lw)
+		r.drawLineAAf(p2.x, p2.y, tipX, tipY, c, lw)
+		r.drawLineAAf(tipX, tipY, p3.x, p3.y, c, lw)
 	case ArrowDiamond:
 		// Diamond shape
 		midX := tipX - dx*baseLen/2
@@ -2347,25 +2728,25 @@ func (r *renderer) drawArrowHead(x1, y1, x2, y2 int, c color.RGBA, lineWidth int
 }
 
 func (r *renderer) renderTable(s *TableShape) {
-	x := r.emuToPixelX(s.offsetX)
-	y := r.emuToPixelY(s.offsetY)
-	w := r.emuToPixelX(s.width)
-	h := r.emuToPixelY(s.height)
+	x, y, _, _ := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 	if s.numRows == 0 || s.numCols == 0 {
 		return
 	}
 
-	// Compute column positions using individual widths if available
+	// Compute column positions using individual widths if available. Each
+	// edge is rounded from its EMU position, so rounding does not add up
+	// across the columns.
 	colX := make([]int, s.numCols+1)
 	colX[0] = x
 	if len(s.colWidths) == s.numCols {
+		edge := s.offsetX
 		for i, cw := range s.colWidths {
-			colX[i+1] = colX[i] + r.emuToPixelX(cw)
+			edge += cw
+			colX[i+1] = r.emuToPixelX(edge)
 		}
 	} else {
-		cellW := w / s.numCols
 		for i := 0; i <= s.numCols; i++ {
-			colX[i] = x + i*cellW
+			colX[i] = r.emuToPixelX(s.offsetX + s.width*int64(i)/int64(s.numCols))
 		}
 	}
 
@@ -2373,18 +2754,17 @@ func (r *renderer) renderTable(s *TableShape) {
 	rowY := make([]int, s.numRows+1)
 	rowY[0] = y
 	if len(s.rowHeights) == s.numRows {
+		edge := s.offsetY
 		for i, rh := range s.rowHeights {
-			rowY[i+1] = rowY[i] + r.emuToPixelY(rh)
+			edge += rh
+			rowY[i+1] = r.emuToPixelY(edge)
 		}
 	} else {
-		cellH := h / s.numRows
 		for i := 0; i <= s.numRows; i++ {
-			rowY[i] = y + i*cellH
+			rowY[i] = r.emuToPixelY(s.offsetY + s.height*int64(i)/int64(s.numRows))
 		}
 	}
 
-	pad := 3
-
 	for row := 0; row < s.numRows; row++ {
 		if row >= len(s.rows) {
 			break
@@ -2419,7 +2799,27 @@ func (r *renderer) renderTable(s *TableShape) {
 			} else {
 				r.drawRect(cellRect, color.RGBA{A: 255}, 1)
 			}
-			r.drawParagraphs(cell.paragraphs, cx+pad, cy+pad, cellW-2*pad, cellH-2*pad, TextAnchorNone, true)
+			// Text wraps within the cell margins and is clipped at the cell
+			// edges, as PowerPoint does when a fixed row height is too small.
+			marL, marT, marR, marB := cell.GetMargins()
+			padL, padT := r.emuToPixelX(marL), r.emuToPixelY(marT)
+			tw := cellW - padL - r.emuToPixelX(marR)
+			th := cellH - padT - r.emuToPixelY(marB)
+			if tw <= 0 || th <= 0 {
+				continue
+			}
+			if vertRotation := vertTextRotation(cell.textDirection); vertRotation != 0 {
+				// Draw into a buffer with swapped dimensions, then rotate into the cell.
+				tmp := newScratchRGBA(th, tw)
+				tmpR := &renderer{img: tmp, scaleX: r.scaleX, scaleY: r.scaleY, fontCache: r.fontCache, dpi: r.dpi, fontScale: r.fontScale, fontSubs: r.fontSubs, themeColors: r.themeColors, defaultFont: r.defaultFont, imageCache: r.imageCache, fastRotation: r.fastRotation, locale: r.locale, log: r.log, metrics: r.metrics, gammaText: r.gammaText, textHinting: r.textHinting}
+				tmpR.drawParagraphs(cell.paragraphs, 0, 0, th, tw, cell.anchor, true)
+				rotateAndComposite(r.img, tmp, cx+padL, cy+padT, tw, th, vertRotation)
+				releaseScratchRGBA(tmp)
+			} else {
+				cr := *r
+				cr.img = r.img.SubImage(cellRect).(*image.RGBA)
+				cr.drawParagraphs(cell.paragraphs, cx+padL, cy+padT, tw, th, cell.anchor, true)
+			}
 		}
 	}
 }
@@ -2430,7 +2830,7 @@ func (r *renderer) renderCellBorders(cb *CellBorders, rect image.Rectangle) {
 			return
 		}
 		pw := maxInt(int(float64(b.Width)*12700.0*r.scaleX), 1)
-		r.drawLineThick(x1, y1, x2, y2, argbToRGBA(b.Color), pw)
+		r.drawLineThick(x1, y1, x2, y2, r.colorRGBA(b.Color), pw)
 	}
 	drawBorder(cb.Top, rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y)
 	drawBorder(cb.Bottom, rect.Min.X, rect.Max.Y-1, rect.Max.X, rect.Max.Y-1)
@@ -2446,13 +2846,113 @@ func (r *renderer) renderFill(fill *Fill, rect image.Rectangle) {
 	}
 	switch fill.Type {
 	case FillSolid:
-		fc := argbToRGBA(fill.Color)
+		fc := r.colorRGBA(fill.Color)
 		fc = r.scaleAlpha(fc)
 		r.fillRectBlend(rect, fc)
 	case FillGradientLinear:
 		r.fillGradientLinear(rect, fill)
 	case FillGradientPath:
 		r.fillGradientPath(rect, fill)
+	case FillPicture:
+		r.fillPicture(rect, fill)
+	}
+}
+
+// fillPicture draws a picture fill into rect, either stretched or tiled.
+func (r *renderer) fillPicture(rect image.Rectangle, fill *Fill) {
+	if len(fill.ImageData) == 0 || rect.Empty() {
+		return
+	}
+	// Tiles are drawn at the picture's own size, so only stretched
+	// pictures can be decoded reduced
+	decodeW, decodeH := 0, 0
+	if fill.Tile == nil {
+		decodeW, decodeH = rect.Dx(), rect.Dy()
+	}
+	src := r.imageCache.decode(fill.ImageData, decodeW, decodeH, r.fontCache, r.metrics)
+	if src == nil {
+		return
+	}
+	if fill.Tile == nil {
+		scaled := scaleImageBilinear(src, rect.Dx(), rect.Dy())
+		draw.Draw(r.img, rect, scaled, image.Point{}, draw.Over)
+		return
+	}
+
+	// Tile size: the picture's native size (assumed 96 DPI) scaled by sx/sy.
+	t := fill.Tile
+	sx, sy := t.ScaleX, t.ScaleY
+	if sx <= 0 {
+		sx = 100000
+	}
+	if sy <= 0 {
+		sy = 100000
+	}
+	b := src.Bounds()
+	tw := r.emuToPixelX(int64(b.Dx()) * 9525 * int64(sx) / 100000)
+	th := r.emuToPixelY(int64(b.Dy()) * 9525 * int64(sy) / 100000)
+	if tw <= 0 || th <= 0 {
+		return
+	}
+	tile := scaleImageBilinear(src, tw, th)
+	flipX := t.Flip == "x" || t.Flip == "xy"
+	flipY := t.Flip == "y" || t.Flip == "xy"
+	var variants [2][2]*image.RGBA
+	variants[0][0] = tile
+	mirrored := func(fx, fy bool) *image.RGBA {
+		i, j := 0, 0
+		if fx {
+			i = 1
+		}
+		if fy {
+			j = 1
+		}
+		if variants[i][j] != nil {
+			return variants[i][j]
+		}
+		m := image.NewRGBA(tile.Bounds())
+		for y := 0; y < th; y++ {
+			for x := 0; x < tw; x++ {
+				dx, dy := x, y
+				if fx {
+					dx = tw - 1 - x
+				}
+				if fy {
+					dy = th - 1 - y
+				}
+				m.SetRGBA(dx, dy, tile.RGBAAt(x, y))
+			}
+		}
+		variants[i][j] = m
+		return m
+	}
+
+	// Anchor the tile grid at the alignment point, then apply the offset.
+	ox := rect.Min.X + r.emuToPixelX(t.OffsetX)
+	oy := rect.Min.Y + r.emuToPixelY(t.OffsetY)
+	switch t.Alignment {
+	case "t", "ctr", "b":
+		ox += (rect.Dx() - tw) / 2
+	case "tr", "r", "br":
+		ox += rect.Dx() - tw
+	}
+	switch t.Alignment {
+	case "l", "ctr", "r":
+		oy += (rect.Dy() - th) / 2
+	case "bl", "b", "br":
+		oy += rect.Dy() - th
+	}
+	// Step back so the first tile covers rect.Min.
+	col0 := int(math.Floor(float64(rect.Min.X-ox) / float64(tw)))
+	row0 := int(math.Floor(float64(rect.Min.Y-oy) / float64(th)))
+
+	for row := row0; oy+row*th < rect.Max.Y; row++ {
+		for col := col0; ox+col*tw < rect.Max.X; col++ {
+			img := mirrored(flipX && col%2 != 0, flipY && row%2 != 0)
+			dst := image.Rect(ox+col*tw, oy+row*th, ox+(col+1)*tw, oy+(row+1)*th)
+			clip := dst.Intersect(rect)
+			draw.Draw(r.img, clip, img, clip.Min.Sub(dst.Min), draw.Over)
+		}
 	}
 }
 
@@ -2466,7 +2966,7 @@ func (r *renderer) renderCustomPathFill(cp *CustomGeomPath, fill *Fill, ox, oy,
 	if len(pts) < 3 {
 		return
 	}
-	fc := argbToRGBA(fill.Color)
+	fc := r.colorRGBA(fill.Color)
 	fc = r.scaleAlpha(fc)
 	r.fillPolygon(pts, fc)
 }
@@ -2569,8 +3069,8 @@ func (r *renderer) scaleAlpha(c color.RGBA) color.RGBA {
 }
 
 func (r *renderer) fillGradientLinear(rect image.Rectangle, fill *Fill) {
-	startC := argbToRGBA(fill.Color)
-	endC := argbToRGBA(fill.EndColor)
+	startC := r.colorRGBA(fill.Color)
+	endC := r.colorRGBA(fill.EndColor)
 	w := rect.Dx()
 	h := rect.Dy()
 	if w <= 0 || h <= 0 {
@@ -2618,8 +3118,8 @@ func (r *renderer) fillGradientLinear(rect image.Rectangle, fill *Fill) {
 }
 
 func (r *renderer) fillGradientPath(rect image.Rectangle, fill *Fill) {
-	startC := argbToRGBA(fill.Color)
-	endC := argbToRGBA(fill.EndColor)
+	startC := r.colorRGBA(fill.Color)
+	endC := r.colorRGBA(fill.EndColor)
 	w := rect.Dx()
 	h := rect.Dy()
 	if w <= 0 || h <= 0 {
@@ -2680,7 +3180,7 @@ func (r *renderer) renderShadow(shadow *Shadow, rect image.Rectangle) {
 	dist := float64(shadow.Distance) * r.scaleX
 	dx := int(dist * math.Cos(rad))
 	dy := int(dist * math.Sin(rad))
-	shadowColor := argbToRGBA(shadow.Color)
+	shadowColor := r.colorRGBA(shadow.Color)
 	shadowColor.A = uint8(float64(shadow.Alpha) * 255 / 100)
 	shadowRect := rect.Add(image.Pt(dx, dy))
 
@@ -2725,7 +3225,7 @@ func (r *renderer) renderShadowRounded(shadow *Shadow, rect image.Rectangle, rad
 	dist := float64(shadow.Distance) * r.scaleX
 	dx := int(dist * math.Cos(rad))
 	dy := int(dist * math.Sin(rad))
-	shadowColor := argbToRGBA(shadow.Color)
+	shadowColor := r.colorRGBA(shadow.Color)
 	shadowColor.A = uint8(float64(shadow.Alpha) * 255 / 100)
 	shadowRect := rect.Add(image.Pt(dx, dy))
 
@@ -2744,7 +3244,8 @@ func (r *renderer) renderShadowRounded(shadow *Shadow, rect image.Rectangle, rad
 	if tmpW <= 0 || tmpH <= 0 {
 		return
 	}
-	tmp := image.NewRGBA(image.Rect(0, 0, tmpW, tmpH))
+	tmp := newScratchRGBA(tmpW, tmpH)
+	defer releaseScratchRGBA(tmp)
 	tmpR := &renderer{img: tmp, scaleX: r.scaleX, scaleY: r.scaleY}
 
 	for i := steps; i >= 0; i-- {
@@ -2783,6 +3284,9 @@ func (r *renderer) renderShadowRounded(shadow *Shadow, rect image.Rectangle, rad
 // --- Drawing primitives ---
 
 func (r *renderer) drawRect(rect image.Rectangle, c color.RGBA, width int) {
+	if r.vector.strokeShape(&vectorShape{kind: vectorRect, box: rect, inside: true}, c, width) {
+		return
+	}
 	for i := 0; i < width; i++ {
 		// Top and bottom horizontal lines
 		r.fillRectBlend(image.Rect(rect.Min.X, rect.Min.Y+i, rect.Max.X, rect.Min.Y+i+1), c)
@@ -2831,6 +3335,9 @@ func (r *renderer) drawDashedVLine(x, y1, y2 int, c color.RGBA, dashLen, gapLen
 }
 
 func (r *renderer) drawLineThick(x1, y1, x2, y2 int, c color.RGBA, width int) {
+	if r.vector.strokeShape(vectorLine(float64(x1), float64(y1), float64(x2), float64(y2)), c, width) {
+		return
+	}
 	if width <= 1 {
 		r.drawLine(x1, y1, x2, y2, c)
 		return
@@ -2852,6 +3359,9 @@ func (r *renderer) drawLineThick(x1, y1, x2, y2 int, c color.RGBA, width int) {
 }
 
 func (r *renderer) drawLine(x1, y1, x2, y2 int, c color.RGBA) {
+	if r.vector.strokeShape(vectorLine(float64(x1), float64(y1), float64(x2), float64(y2)), c, 1) {
+		return
+	}
 	dx := abs(x2 - x1)
 	dy := abs(y2 - y1)
 	sx, sy := 1, 1
@@ -2880,15 +3390,25 @@ func (r *renderer) drawLine(x1, y1, x2, y2 int, c color.RGBA) {
 }
 
 func (r *renderer) drawLineAA(x1, y1, x2, y2 int, c color.RGBA, width int) {
+	r.drawLineAAf(float64(x1), float64(y1), float64(x2), float64(y2), c, width)
+}
+
+// drawLineAAf draws an anti-aliased line between fractional pixel
+// positions, such as the points of a path, without snapping them to whole
+// pixels first.
+func (r *renderer) drawLineAAf(x1, y1, x2, y2 float64, c color.RGBA, width int) {
+	if r.vector.strokeShape(vectorLine(x1, y1, x2, y2), c, width) {
+		return
+	}
 	if width <= 1 {
-		r.drawLineWu(float64(x1), float64(y1), float64(x2), float64(y2), c)
+		r.drawLineWu(x1, y1, x2, y2, c)
 		return
 	}
-	dx := float64(x2 - x1)
-	dy := float64(y2 - y1)
+	dx := x2 - x1
+	dy := y2 - y1
 	length := math.Sqrt(dx*dx + dy*dy)
 	if length < 0.5 {
-		r.blendPixel(x1, y1, c)
+		r.blendPixel(int(x1), int(y1), c)
 		return
 	}
 	nx := -dy / length
@@ -2898,7 +3418,7 @@ func (r *renderer) drawLineAA(x1, y1, x2, y2 int, c color.RGBA, width int) {
 		offset := -hw + float64(i) + 0.5
 		ox := offset * nx
 		oy := offset * ny
-		r.drawLineWu(float64(x1)+ox, float64(y1)+oy, float64(x2)+ox, float64(y2)+oy, c)
+		r.drawLineWu(x1+ox, y1+oy, x2+ox, y2+oy, c)
 	}
 }
 
@@ -3017,7 +3537,7 @@ func (r *renderer) drawCubicBezierAA(x0, y0, x1, y1, x2, y2, x3, y3 float64, c c
 	pts = append([]fpoint{{x0, y0}}, pts...)
 	pts = append(pts, fpoint{x3, y3})
 	for i := 1; i < len(pts); i++ {
-		r.drawLineAA(int(pts[i-1].x), int(pts[i-1].y), int(pts[i].x), int(pts[i].y), c, width)
+		r.drawLineAAf(pts[i-1].x, pts[i-1].y, pts[i].x, pts[i].y, c, width)
 	}
 }
 
@@ -3142,6 +3662,9 @@ func (r *renderer) fillEllipseAA(cx, cy, w, h int, c color.RGBA) {
 	if w <= 0 || h <= 0 {
 		return
 	}
+	if r.vector.fillShape(&vectorShape{kind: vectorEllipse, box: image.Rect(cx, cy, cx+w, cy+h)}, c) {
+		return
+	}
 	rx := float64(w) / 2
 	ry := float64(h) / 2
 	centerX := float64(cx) + rx
@@ -3201,6 +3724,9 @@ func (r *renderer) drawEllipseAA(cx, cy, w, h int, c color.RGBA, lineWidth int)
 	if w <= 0 || h <= 0 {
 		return
 	}
+	if r.vector.strokeShape(&vectorShape{kind: vectorEllipse, box: image.Rect(cx, cy, cx+w, cy+h)}, c, lineWidth) {
+		return
+	}
 	rx := float64(w) / 2
 	ry := float64(h) / 2
 	centerX := float64(cx) + rx
@@ -3248,6 +3774,9 @@ func (r *renderer) fillRoundedRect(x, y, w, h, radius int, c color.RGBA) {
 		return
 	}
 	radius = minInt(radius, minInt(w/2, h/2))
+	if r.vector.fillShape(&vectorShape{kind: vectorRoundRect, box: image.Rect(x, y, x+w, y+h), radius: radius}, c) {
+		return
+	}
 	r2 := float64(radius * radius)
 
 	// Fill center rectangle (no corner checks needed)
@@ -3285,6 +3814,9 @@ func (r *renderer) fillRoundedRect(x, y, w, h, radius int, c color.RGBA) {
 }
 
 func (r *renderer) drawRoundedRect(x, y, w, h, radius int, c color.RGBA, lineWidth int) {
+	if r.vector.strokeShape(&vectorShape{kind: vectorRoundRect, box: image.Rect(x, y, x+w, y+h), radius: radius}, c, lineWidth) {
+		return
+	}
 	r.drawLineThick(x+radius, y, x+w-radius, y, c, lineWidth)
 	r.drawLineThick(x+radius, y+h-1, x+w-radius, y+h-1, c, lineWidth)
 	r.drawLineThick(x, y+radius, x, y+h-radius, c, lineWidth)
@@ -3326,6 +3858,9 @@ func (r *renderer) fillPolygon(pts []fpoint, c color.RGBA) {
 	if len(pts) < 3 {
 		return
 	}
+	if r.vector.fillShape(vectorPoints(vectorPolygon, pts), c) {
+		return
+	}
 	minY, maxY := pts[0].y, pts[0].y
 	for _, p := range pts[1:] {
 		if p.y < minY {
@@ -3378,8 +3913,8 @@ func (r *renderer) fillPolygonGradient(pts []fpoint, fill *Fill) {
 	if len(pts) < 3 || fill == nil {
 		return
 	}
-	startC := argbToRGBA(fill.Color)
-	endC := argbToRGBA(fill.EndColor)
+	startC := r.colorRGBA(fill.Color)
+	endC := r.colorRGBA(fill.EndColor)
 
 	// Compute bounding box
 	minX, minY, maxX, maxY := pts[0].x, pts[0].y, pts[0].x, pts[0].y
@@ -3480,10 +4015,13 @@ func (r *renderer) fillPolygonGradient(pts []fpoint, fill *Fill) {
 }
 
 func (r *renderer) drawPolygon(pts []fpoint, c color.RGBA, width int) {
+	if len(pts) >= 3 && r.vector.strokeShape(vectorPoints(vectorPolygon, pts), c, width) {
+		return
+	}
 	n := len(pts)
 	for i := 0; i < n; i++ {
 		j := (i + 1) % n
-		r.drawLineAA(int(pts[i].x), int(pts[i].y), int(pts[j].x), int(pts[j].y), c, width)
+		r.drawLineAAf(pts[i].x, pts[i].y, pts[j].x, pts[j].y, c, width)
 	}
 }
 
@@ -3889,8 +4427,8 @@ func (r *renderer) drawWedgeRoundRectCalloutBorder(x, y, w, h int, bc color.RGBA
 	r.drawRoundedRect(x, y, w, h, radius, bc, pw)
 
 	// Draw wedge lines from base points to tip
-	r.drawLineAA(int(wi.bx1), int(wi.by1), int(tipX), int(tipY), bc, pw)
-	r.drawLineAA(int(tipX), int(tipY), int(wi.bx2), int(wi.by2), bc, pw)
+	r.drawLineAAf(wi.bx1, wi.by1, tipX, tipY, bc, pw)
+	r.drawLineAAf(tipX, tipY, wi.bx2, wi.by2, bc, pw)
 }
 
 
@@ -4230,14 +4768,21 @@ func (r *renderer) getFace(f *Font) font.Face {
 	// 1pt = 12700 EMU; scaleX converts EMU to pixels.
 	sizePixels := sizePt * 12700.0 * r.scaleX
 
-	face := r.fontCache.GetFace(f.Name, sizePixels, f.Bold, f.Italic)
+	// Unhinted text is drawn with the faces text is measured with
+	if r.textHinting == TextHintingNone {
+		if face := r.getMeasureFace(f); face != nil {
+			return face
+		}
+	}
+	face := r.faceByName(f.Name, sizePixels, f.Bold, f.Italic, false)
 	if face != nil {
 		return face
 	}
 	// Try East Asian font name if specified
 	if f.NameEA != "" {
-		face = r.fontCache.GetFace(f.NameEA, sizePixels, f.Bold, f.Italic)
+		face = r.faceByName(f.NameEA, sizePixels, f.Bold, f.Italic, false)
 		if face != nil {
+			r.log.fontFallback(f.Name, f.NameEA)
 			return face
 		}
 	}
@@ -4251,12 +4796,45 @@ func (r *renderer) getFace(f *Font) font.Face {
 	} {
 		face = r.fontCache.GetFace(fallback, sizePixels, f.Bold, f.Italic)
 		if face != nil {
+			r.log.fontFallback(f.Name, fallback)
 			return face
 		}
 	}
+	r.log.fontFallback(f.Name, "basicfont")
 	return basicfont.Face7x13
 }
 
+// faceByName returns the render face (or, when measure is set, the measure
+// face) for the named font, trying its configured substitute when the font
+// itself is not available.
+func (r *renderer) faceByName(name string, sizePx float64, bold, italic, measure bool) font.Face {
+	get := r.fontCache.GetFace
+	if measure {
+		get = r.fontCache.GetMeasureFace
+	}
+	if face := get(name, sizePx, bold, italic); face != nil {
+		return face
+	}
+	if sub, ok := r.fontSubs[strings.ToLower(name)]; ok {
+		return get(sub, sizePx, bold, italic)
+	}
+	return nil
+}
+
+// fontSubstitutionMap returns subs keyed by lowercase font name.
+func fontSubstitutionMap(subs map[string]string) map[string]string {
+	if len(subs) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(subs))
+	for from, to := range subs {
+		if to != "" {
+			m[strings.ToLower(from)] = to
+		}
+	}
+	return m
+}
+
 // getCJKFace returns a font face suitable for CJK characters.
 // It tries NameEA first, then common CJK fonts.
 func (r *renderer) getCJKFace(f *Font) font.Face {
@@ -4275,7 +4853,7 @@ func (r *renderer) getCJKFace(f *Font) font.Face {
 
 	// Try East Asian font name first
 	if f.NameEA != "" {
-		face := r.fontCache.GetFace(f.NameEA, sizePixels, f.Bold, f.Italic)
+		face := r.faceByName(f.NameEA, sizePixels, f.Bold, f.Italic, false)
 		if face != nil {
 			return face
 		}
@@ -4311,12 +4889,12 @@ func (r *renderer) getMeasureFace(f *Font) font.Face {
 	}
 	sizePixels := sizePt * 12700.0 * r.scaleX
 
-	face := r.fontCache.GetMeasureFace(f.Name, sizePixels, f.Bold, f.Italic)
+	face := r.faceByName(f.Name, sizePixels, f.Bold, f.Italic, true)
 	if face != nil {
 		return face
 	}
 	if f.NameEA != "" {
-		face = r.fontCache.GetMeasureFace(f.NameEA, sizePixels, f.Bold, f.Italic)
+		face = r.faceByName(f.NameEA, sizePixels, f.Bold, f.Italic, true)
 		if face != nil {
 			return face
 		}
@@ -4351,7 +4929,7 @@ func (r *renderer) getCJKMeasureFace(f *Font) font.Face {
 	sizePixels := sizePt * 12700.0 * r.scaleX
 
 	if f.NameEA != "" {
-		face := r.fontCache.GetMeasureFace(f.NameEA, sizePixels, f.Bold, f.Italic)
+		face := r.faceByName(f.NameEA, sizePixels, f.Bold, f.Italic, true)
 		if face != nil {
 			return face
 		}
@@ -4388,15 +4966,16 @@ func containsCJK(s string) bool {
 func (r *renderer) buildParaTextRuns(elements []ParagraphElement) []textRun {
 	var runs []textRun
 	for _, elem := range elements {
+		if f, ok := elem.(*SlideNumberField); ok {
+			elem = &TextRun{text: r.slideNumber, font: f.font}
+		}
 		switch e := elem.(type) {
 		case *TextRun:
 			if e.text == "" {
 				continue
 			}
-			f := e.font
-			if f == nil {
-				f = NewFont()
-			}
+			start := len(runs)
+			f := runFont(e.font, r.defaultFont)
 			if containsCJK(e.text) && r.fontCache != nil {
 				sizePt := float64(f.Size)
 				if sizePt <= 0 {
@@ -4406,7 +4985,7 @@ func (r *renderer) buildParaTextRuns(elements []ParagraphElement) []textRun {
 					sizePt *= r.fontScale
 				}
 				scaledPt := sizePt * 12700.0 * r.scaleX
-				latinFace := r.fontCache.GetFace(f.Name, scaledPt, f.Bold, f.Italic)
+				latinFace := r.faceByName(f.Name, scaledPt, f.Bold, f.Italic, false)
 				if latinFace == nil {
 					latinFace = r.getFace(f)
 				}
@@ -4426,6 +5005,11 @@ func (r *renderer) buildParaTextRuns(elements []ParagraphElement) []textRun {
 					width:       measureStringWithKern(face, e.text).Ceil(),
 				})
 			}
+			if e.hyperlink != nil {
+				for i := start; i < len(runs); i++ {
+					runs[i].link = e
+				}
+			}
 		case *BreakElement:
 			runs = append(runs, textRun{text: "\n"})
 		}
@@ -4514,6 +5098,7 @@ type textRun struct {
 	face        font.Face // render face (HintingFull) for drawing
 	measureFace font.Face // measure face (HintingNone) for layout; nil falls back to face
 	width       int
+	link        *TextRun // the run the text is from when it has a hyperlink
 }
 
 // mface returns the face to use for measurement. If a dedicated measure face
@@ -4592,8 +5177,15 @@ func (r *renderer) buildTextLine(runs []textRun) textLine {
 // the given paragraphs within the specified width, replicating the same line
 // building and spacing logic used by drawParagraphs.
 func (r *renderer) measureParagraphsHeight(paragraphs []*Paragraph, w, h int, anchor TextAnchorType, wordWrap bool) int {
+	_, height := r.measureParagraphs(paragraphs, w, wordWrap)
+	return height
+}
+
+// measureParagraphs returns the number of laid-out lines and the total
+// height in pixels of paragraphs wrapped to width w.
+func (r *renderer) measureParagraphs(paragraphs []*Paragraph, w int, wordWrap bool) (int, int) {
 	if len(paragraphs) == 0 {
-		return 0
+		return 0, 0
 	}
 	type lineInfo struct {
 		lineHeight  int
@@ -4668,7 +5260,7 @@ func (r *renderer) measureParagraphsHeight(paragraphs []*Paragraph, w, h int, an
 		totalH += lh
 		totalH += li.spaceAfter
 	}
-	return totalH
+	return len(allLines), totalH
 }
 
 // measureMaxLineWidth returns the maximum line width across all paragraphs
@@ -4889,7 +5481,7 @@ func (r *renderer) drawParagraphs(paragraphs []*Paragraph, x, y, w, h int, ancho
 			}
 			fc := color.RGBA{A: 255}
 			if run.font != nil {
-				fc = argbToRGBA(run.font.Color)
+				fc = r.colorRGBA(run.font.Color)
 			}
 
 			runBaseline := baseline
@@ -4901,25 +5493,17 @@ func (r *renderer) drawParagraphs(paragraphs []*Paragraph, x, y, w, h int, ancho
 				}
 			}
 
-			d := &font.Drawer{
-				Dst:  r.img,
-				Src:  image.NewUniform(fc),
-				Face: run.face,
-				Dot:  fixed.P(drawX, runBaseline),
+			if r.vector != nil {
+				r.vector.addText(r, run, drawX, runBaseline, fc)
+			} else {
+				r.drawText(run.face, drawX, runBaseline, run.text, fc)
 			}
-			d.DrawString(run.text)
 
 			// Synthetic bold: if bold was requested but the font face is the
 			// regular weight (no bold variant found), re-draw with a 1px
 			// horizontal offset to embolden the glyphs.
-			if run.font != nil && run.font.Bold {
-				d2 := &font.Drawer{
-					Dst:  r.img,
-					Src:  image.NewUniform(fc),
-					Face: run.face,
-					Dot:  fixed.P(drawX+1, runBaseline),
-				}
-				d2.DrawString(run.text)
+			if run.font != nil && run.font.Bold && r.vector == nil {
+				r.drawText(run.face, drawX+1, runBaseline, run.text, fc)
 			}
 
 			// Underline
@@ -4934,8 +5518,10 @@ func (r *renderer) drawParagraphs(paragraphs []*Paragraph, x, y, w, h int, ancho
 				r.drawLine(drawX, sy, drawX+run.width, sy, fc)
 			}
 
+			r.links.add(run.link, image.Rect(drawX, runBaseline-li.line.ascent, drawX+run.width, runBaseline+li.line.descent))
 			drawX += run.width
 		}
+		r.debug.addBaseline(lineX, drawX, baseline)
 
 		curY += lh
 		curY += li.spaceAfter
@@ -4978,8 +5564,9 @@ func (r *renderer) buildBulletRun(b *Bullet, para *Paragraph) textRun {
 	// Try to get size from first text run
 	for _, elem := range para.elements {
 		if tr, ok := elem.(*TextRun); ok && tr.font != nil {
-			bulletFont.Size = tr.font.Size
-			bulletFont.Color = tr.font.Color
+			f := runFont(tr.font, r.defaultFont)
+			bulletFont.Size = f.Size
+			bulletFont.Color = f.Color
 			break
 		}
 	}
@@ -5025,8 +5612,9 @@ func (r *renderer) buildBulletRun(b *Bullet, para *Paragraph) textRun {
 			bulletFont.Name = ""
 			for _, elem := range para.elements {
 				if tr, ok := elem.(*TextRun); ok && tr.font != nil {
-					bulletFont.Name = tr.font.Name
-					bulletFont.NameEA = tr.font.NameEA
+					f := runFont(tr.font, r.defaultFont)
+					bulletFont.Name = f.Name
+					bulletFont.NameEA = f.NameEA
 					break
 				}
 			}
@@ -5037,6 +5625,9 @@ func (r *renderer) buildBulletRun(b *Bullet, para *Paragraph) textRun {
 	}
 
 	face := r.getFace(bulletFont)
+	if b.Type == BulletTypeChar {
+		text, bulletFont, face = r.bulletGlyphFace(text, bulletFont, face)
+	}
 	w := font.MeasureString(face, text).Ceil()
 	// For symbol fonts rendered via PUA (no trailing space in text),
 	// add a small gap so the bullet doesn't touch the text.
@@ -5079,83 +5670,123 @@ func symbolToPUA(ch string) string {
 
 // mapSymbolChar maps a character from a symbol font to a Unicode equivalent.
 // Symbol fonts like Wingdings encode characters at code points that don't
-// correspond to their visual appearance in Unicode.
+// correspond to their visual appearance in Unicode. Characters given at
+// their Private Use Area code point (U+F000 + byte value) are mapped as the
+// byte value.
 func mapSymbolChar(fontName, ch string) string {
 	if len(ch) == 0 {
 		return "•"
 	}
 	r := []rune(ch)[0]
-	n := strings.ToLower(fontName)
-
-	if n == "wingdings" {
-		// Wingdings character map (code point → Unicode equivalent)
-		switch r {
-		case 0xD8: // bowtie (two triangles forming a butterfly/wing shape)
-			return "\u22C8"
-		case 0xA8: // filled circle
-			return "●"
-		case 0x6C: // bullet
-			return "●"
-		case 0x6E: // filled square
-			return "■"
-		case 0x71: // open circle
-			return "○"
-		case 0x75, 0xA7: // diamond
-			return "◆"
-		case 0x76: // open diamond
-			return "◇"
-		case 0x77: // filled triangle right
-			return "▶"
-		case 0xFC: // check mark
-			return "✓"
-		case 0xFB: // cross mark
-			return "✗"
-		case 0xE0: // right arrow
-			return "→"
-		case 0xDF: // left arrow
-			return "←"
-		case 0xE1: // up arrow
-			return "↑"
-		case 0xE2: // down arrow
-			return "↓"
-		case 0xF0: // right pointing triangle
-			return "►"
-		case 0x9F: // star
-			return "★"
-		case 0xAB: // dash
-			return "–"
-		default:
-			return "•" // fallback to standard bullet
-		}
-	}
-
-	if n == "wingdings 2" {
-		return "•"
+	if r >= 0xF000 && r <= 0xF0FF {
+		r -= 0xF000
 	}
 
-	if n == "wingdings 3" {
-		switch r {
-		case 0x75: // triangle right
-			return "▶"
-		case 0x76: // triangle left
-			return "◀"
-		default:
-			return "•"
+	var table map[rune]string
+	switch strings.ToLower(fontName) {
+	case "wingdings":
+		table = wingdingsChars
+	case "wingdings 2":
+		table = wingdings2Chars
+	case "wingdings 3":
+		table = wingdings3Chars
+	case "symbol":
+		if r >= 'A' && r <= 'Z' {
+			return string([]rune(symbolGreekUpper)[r-'A'])
+		}
+		if r >= 'a' && r <= 'z' {
+			return string([]rune(symbolGreekLower)[r-'a'])
+		}
+		if s, ok := symbolChars[r]; ok {
+			return s
+		}
+		if r < 0x80 {
+			return string(r) // Symbol font maps the other ASCII characters directly
 		}
 	}
+	if s, ok := table[r]; ok {
+		return s
+	}
+	return "•" // fallback to standard bullet
+}
+
+// wingdingsChars maps the Wingdings characters used as bullets and marks
+// to Unicode equivalents.
+var wingdingsChars = map[rune]string{
+	0x45: "☜", 0x46: "☞", 0x4A: "☺", 0x4C: "☹",
+	0x6C: "●", 0x6D: "❍", 0x6E: "■", 0x6F: "□", 0x70: "◻",
+	0x71: "❑", 0x72: "❒", 0x73: "⬧", 0x74: "⧫", 0x75: "◆",
+	0x76: "❖", 0x77: "⬥", 0x78: "⌧", 0x7A: "⌘", 0x7B: "❀", 0x7C: "✿",
+	0x81: "①", 0x82: "②", 0x83: "③", 0x84: "④", 0x85: "⑤",
+	0x86: "⑥", 0x87: "⑦", 0x88: "⑧", 0x89: "⑨", 0x8A: "⑩",
+	0x9E: "·", 0x9F: "•", 0xA0: "▪", 0xA1: "○", 0xA4: "◉", 0xA5: "◎",
+	0xA7: "▪", 0xA8: "◻", 0xAA: "✦", 0xAB: "★", 0xAC: "✶", 0xAD: "✴",
+	0xAE: "✹", 0xAF: "✵",
+	0xD8: "➢", 0xDF: "←", 0xE0: "→", 0xE1: "↑", 0xE2: "↓",
+	0xE8: "➔", 0xEF: "⇦", 0xF0: "⇨", 0xF1: "⇧", 0xF2: "⇩",
+	0xFB: "✗", 0xFC: "✔", 0xFD: "☒", 0xFE: "☑",
+}
 
-	if n == "symbol" {
-		switch r {
-		case 0xB7: // middle dot
-			return "·"
-		case 0xD8: // empty set
-			return "∅"
-		default:
-			return string(r) // Symbol font mostly maps to Unicode directly
+// wingdings2Chars maps the Wingdings 2 check marks and boxes to Unicode
+// equivalents.
+var wingdings2Chars = map[rune]string{
+	0x4F: "✗", 0x50: "✓", 0x52: "☑", 0x54: "☒",
+}
+
+// wingdings3Chars maps the Wingdings 3 triangles to Unicode equivalents.
+var wingdings3Chars = map[rune]string{
+	0x75: "▶", 0x76: "◀",
+}
+
+// symbolGreekUpper and symbolGreekLower are the Greek letters the Symbol
+// font has at A-Z and a-z.
+const (
+	symbolGreekUpper = "ΑΒΧΔΕΦΓΗΙϑΚΛΜΝΟΠΘΡΣΤΥςΩΞΨΖ"
+	symbolGreekLower = "αβχδεφγηιϕκλμνοπθρστυϖωξψζ"
+)
+
+// symbolChars maps the other Symbol characters used as bullets and marks
+// to Unicode equivalents.
+var symbolChars = map[rune]string{
+	0x2D: "−", 0xA7: "♣", 0xA8: "♦", 0xA9: "♥", 0xAA: "♠",
+	0xAC: "←", 0xAD: "↑", 0xAE: "→", 0xAF: "↓", 0xB0: "°", 0xB1: "±",
+	0xB7: "•", 0xC6: "∅", 0xD8: "¬", 0xDC: "⇐", 0xDE: "⇒", 0xE0: "◊",
+}
+
+// bulletFallbackFonts are the fonts tried, in order, for a bullet glyph
+// that the bullet font does not have.
+var bulletFallbackFonts = []string{
+	"Segoe UI Symbol", "Arial Unicode MS", "Noto Sans Symbols 2", "Noto Sans Symbols",
+	"DejaVu Sans", "Symbola", "Arial",
+}
+
+// bulletGlyphFace returns the text, font and face to draw the bullet text
+// with: f and face when face has the bullet glyph, or else the first of the
+// bulletFallbackFonts that has it. When none has it, the bullet is drawn
+// as "•" in f.
+func (r *renderer) bulletGlyphFace(text string, f *Font, face font.Face) (string, *Font, font.Face) {
+	ch, _ := utf8.DecodeRuneInString(text)
+	if r.fontCache == nil || hasGlyph(face, ch) {
+		return text, f, face
+	}
+	for _, name := range bulletFallbackFonts {
+		if r.fontCache.GetFace(name, 12, false, false) == nil {
+			continue
+		}
+		fb := *f
+		fb.Name, fb.NameEA = name, ""
+		if fbFace := r.getFace(&fb); hasGlyph(fbFace, ch) {
+			r.log.fontFallback(f.Name, name)
+			return text, &fb, fbFace
 		}
 	}
+	return "• ", f, face
+}
 
-	return "•" // fallback
+// hasGlyph reports whether face has a glyph for ch.
+func hasGlyph(face font.Face, ch rune) bool {
+	_, ok := face.GlyphAdvance(ch)
+	return ok
 }
 
 // formatBulletNumber formats a number according to the bullet format.
@@ -5460,6 +6091,7 @@ func (r *renderer) wrapRunLine(runs []textRun, maxWidth int) []textLine {
 						face:        run.face,
 						measureFace: run.measureFace,
 						width:       measureStringWithKern(run.face, pText).Ceil(),
+						link:        run.link,
 					})
 				}
 				lines = append(lines, r.buildTextLine(currentRuns))
@@ -5485,6 +6117,7 @@ func (r *renderer) wrapRunLine(runs []textRun, maxWidth int) []textLine {
 				face:        run.face,
 				measureFace: run.measureFace,
 				width:       measureStringWithKern(run.face, pText).Ceil(),
+				link:        run.link,
 			}
 			currentRuns = append(currentRuns, wr)
 			currentWidth += pw
@@ -5596,6 +6229,7 @@ func (r *renderer) wrapRunLineWithIndent(runs []textRun, firstLineWidth, contLin
 						face:        run.face,
 						measureFace: run.measureFace,
 						width:       measureStringWithKern(run.face, pText).Ceil(),
+						link:        run.link,
 					})
 				}
 				lines = append(lines, r.buildTextLine(currentRuns))
@@ -5622,6 +6256,7 @@ func (r *renderer) wrapRunLineWithIndent(runs []textRun, firstLineWidth, contLin
 				face:        run.face,
 				measureFace: run.measureFace,
 				width:       measureStringWithKern(run.face, pText).Ceil(),
+				link:        run.link,
 			})
 			currentWidth += pw
 		}
@@ -5634,23 +6269,36 @@ func (r *renderer) wrapRunLineWithIndent(runs []textRun, firstLineWidth, contLin
 	return lines
 }
 
-// drawStringCentered draws a string centered in the given rectangle.
-func (r *renderer) drawStringCentered(text string, face font.Face, c color.RGBA, rect image.Rectangle) {
+// drawFontStringCentered draws text in font f centered in rect, or
+// collects it when the renderer collects text runs.
+func (r *renderer) drawFontStringCentered(text string, f *Font, c color.RGBA, rect image.Rectangle) {
+	face := r.getFace(f)
 	if text == "" || face == nil {
 		return
 	}
+	cx, cy := centeredTextOrigin(text, face, rect)
+	r.drawFontText(text, f, face, cx, cy, c)
+}
+
+// drawFontText draws text in font f, laid out with face, from the baseline
+// origin (x, y), or collects it when the renderer collects text runs.
+func (r *renderer) drawFontText(text string, f *Font, face font.Face, x, y int, c color.RGBA) {
+	if r.vector != nil {
+		r.vector.addText(r, textRun{text: text, font: f, face: face}, x, y, c)
+		return
+	}
+	r.drawText(face, x, y, text, c)
+}
+
+// centeredTextOrigin returns the baseline origin at which text drawn with
+// face is centered in rect.
+func centeredTextOrigin(text string, face font.Face, rect image.Rectangle) (int, int) {
 	tw := font.MeasureString(face, text).Ceil()
 	metrics := face.Metrics()
 	th := (metrics.Ascent + metrics.Descent).Ceil()
 	cx := rect.Min.X + (rect.Dx()-tw)/2
 	cy := rect.Min.Y + (rect.Dy()-th)/2 + metrics.Ascent.Ceil()
-	d := &font.Drawer{
-		Dst:  r.img,
-		Src:  image.NewUniform(c),
-		Face: face,
-		Dot:  fixed.P(cx, cy),
-	}
-	d.DrawString(text)
+	return cx, cy
 }
 
 // --- Chart rendering ---
@@ -5673,18 +6321,15 @@ func chartColors() []color.RGBA {
 }
 
 // getSeriesColor returns the color for a series, using its FillColor if set, otherwise a palette color.
-func getSeriesColor(s *ChartSeries, idx int, palette []color.RGBA) color.RGBA {
+func (r *renderer) getSeriesColor(s *ChartSeries, idx int, palette []color.RGBA) color.RGBA {
 	if s.FillColor.ARGB != "" && s.FillColor.ARGB != "00000000" {
-		return argbToRGBA(s.FillColor)
+		return r.colorRGBA(s.FillColor)
 	}
 	return palette[idx%len(palette)]
 }
 
 func (r *renderer) renderChart(s *ChartShape) {
-	x := r.emuToPixelX(s.offsetX)
-	y := r.emuToPixelY(s.offsetY)
-	w := r.emuToPixelX(s.width)
-	h := r.emuToPixelY(s.height)
+	x, y, w, h := r.emuBox(s.offsetX, s.offsetY, s.width, s.height)
 
 	// Background
 	r.fillRectFast(image.Rect(x, y, x+w, y+h), color.RGBA{R: 255, G: 255, B: 255, A: 255})
@@ -5692,11 +6337,20 @@ func (r *renderer) renderChart(s *ChartShape) {
 
 	// Title
 	titleH := 0
-	if s.title != nil && s.title.Visible && s.title.Text != "" {
+	if s.title != nil && s.title.Visible && len(s.title.paragraphs) > 0 {
+		// Rich text title: centered paragraphs wrapped to the chart width
+		paras := s.title.centeredParagraphs()
+		tw := w - 8
+		titleH = r.measureParagraphsHeight(paras, tw, h/2, TextAnchorTop, true) + 4
+		if titleH > h/2 {
+			titleH = h / 2
+		}
+		r.drawParagraphs(paras, x+4, y+2, tw, titleH-2, TextAnchorTop, true)
+	} else if s.title != nil && s.title.Visible && s.title.Text != "" {
 		face := r.getFace(s.title.Font)
-		fc := argbToRGBA(s.title.Font.Color)
+		fc := r.colorRGBA(s.title.Font.Color)
 		titleH = face.Metrics().Height.Ceil() + 4
-		r.drawStringCentered(s.title.Text, face, fc, image.Rect(x, y, x+w, y+titleH))
+		r.drawFontStringCentered(s.title.Text, s.title.Font, fc, image.Rect(x, y, x+w, y+titleH))
 	}
 
 	// Legend height
@@ -5722,13 +6376,43 @@ func (r *renderer) renderChart(s *ChartShape) {
 		return
 	}
 
+	// The data table takes the bottom of the plot area, and its series
+	// names may need a wider left margin than the value axis labels.
+	tableSeries := chartDataTableSeries(s)
+	tableNameW := 0
+	if len(tableSeries) > 0 {
+		var tableH int
+		tableH, tableNameW = r.measureChartDataTable(s, tableSeries)
+		plotH = maxInt(plotH-tableH, 10)
+		if shift := tableNameW + 4 - (plotX - x); shift > 0 {
+			plotX += shift
+			plotW = maxInt(plotW-shift, 10)
+		}
+	}
+
+	scale, hasValueAxis := valueAxisScale(ct, s.plotArea.axisY)
+	if isHorizontalBar(ct) {
+		// Leave room for the value axis labels below the bars
+		plotH = maxInt(plotH-16, 10)
+		if hasValueAxis {
+			r.drawHorizontalValueAxisTicks(scale, s.plotArea.axisY, plotX, plotY, plotW, plotH)
+		}
+	} else {
+		switch ct.(type) {
+		case *BarChart, *Bar3DChart, *LineChart, *AreaChart, *WaterfallChart:
+			if hasValueAxis {
+				r.drawValueAxisTicks(scale, s.plotArea.axisY, plotX, plotY, plotH)
+			}
+		}
+	}
+
 	switch c := ct.(type) {
 	case *BarChart:
-		r.renderBarChart(c, plotX, plotY, plotW, plotH)
+		r.renderBarChart(c, scale, plotX, plotY, plotW, plotH)
 	case *Bar3DChart:
-		r.renderBarChart(&c.BarChart, plotX, plotY, plotW, plotH)
+		r.renderBarChart(&c.BarChart, scale, plotX, plotY, plotW, plotH)
 	case *LineChart:
-		r.renderLineChart(c, plotX, plotY, plotW, plotH)
+		r.renderLineChart(c, scale, plotX, plotY, plotW, plotH)
 	case *PieChart:
 		r.renderPieChart(c.Series, plotX, plotY, plotW, plotH)
 	case *Pie3DChart:
@@ -5736,11 +6420,19 @@ func (r *renderer) renderChart(s *ChartShape) {
 	case *DoughnutChart:
 		r.renderDoughnutChart(c, plotX, plotY, plotW, plotH)
 	case *AreaChart:
-		r.renderAreaChart(c, plotX, plotY, plotW, plotH)
+		r.renderAreaChart(c, scale, plotX, plotY, plotW, plotH)
 	case *ScatterChart:
 		r.renderScatterChart(c, plotX, plotY, plotW, plotH)
 	case *RadarChart:
 		r.renderRadarChart(c, plotX, plotY, plotW, plotH)
+	case *WaterfallChart:
+		r.renderWaterfallChart(c, scale, plotX, plotY, plotW, plotH)
+	case *FunnelChart:
+		r.renderFunnelChart(c, plotX, plotY, plotW, plotH)
+	}
+
+	if len(tableSeries) > 0 {
+		r.renderChartDataTable(s, tableSeries, plotX-tableNameW, plotY+plotH, plotW, tableNameW)
 	}
 
 	// Legend
@@ -5749,41 +6441,24 @@ func (r *renderer) renderChart(s *ChartShape) {
 	}
 }
 
-func (r *renderer) renderBarChart(c *BarChart, px, py, pw, ph int) {
+func (r *renderer) renderBarChart(c *BarChart, scale AxisScale, px, py, pw, ph int) {
 	if len(c.Series) == 0 {
 		return
 	}
 	palette := chartColors()
 
-	// Collect all categories and find value range
 	cats := c.Series[0].Categories
-	minVal := 0.0
-	maxVal := 0.0
-	first := true
-	for _, s := range c.Series {
-		for _, cat := range s.Categories {
-			v := s.Values[cat]
-			if first {
-				minVal = v
-				maxVal = v
-				first = false
-			} else {
-				if v < minVal {
-					minVal = v
-				}
-				if v > maxVal {
-					maxVal = v
-				}
-			}
-		}
-	}
-	if minVal > 0 {
-		minVal = 0
-	}
-	if maxVal <= minVal {
-		maxVal = minVal + 1
+	horizontal := c.BarDirection == BarDirectionHorizontal
+	stacked := c.BarGrouping == BarGroupingStacked || c.BarGrouping == BarGroupingPercentStacked
+	percent := c.BarGrouping == BarGroupingPercentStacked
+	minVal, maxVal := scale.Min, scale.Max
+	if percent {
+		minVal, maxVal = 0, 1
 	}
 	valRange := maxVal - minVal
+	if valRange <= 0 {
+		valRange = 1
+	}
 
 	// Draw axes
 	axisColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
@@ -5791,54 +6466,197 @@ func (r *renderer) renderBarChart(c *BarChart, px, py, pw, ph int) {
 	r.drawLine(px, py, px, py+ph, axisColor)
 
 	nCats := len(cats)
-	nSeries := len(c.Series)
 	if nCats == 0 {
 		return
 	}
-	catW := pw / nCats
-	barW := catW / (nSeries + 1)
-	if barW < 1 {
-		barW = 1
+
+	// Lay out the bars of a category as PowerPoint does: the gap between
+	// categories is gapWidth percent of a bar, and neighbouring series
+	// overlap by overlap percent of a bar. Stacked bars overlap fully.
+	catLen := pw
+	if horizontal {
+		catLen = ph
+	}
+	catW := float64(catLen) / float64(nCats)
+	slots, overlap := float64(len(c.Series)), float64(c.OverlapPercent)/100
+	if stacked {
+		slots, overlap = 1, 1
+	}
+	gap := float64(c.GapWidthPercent) / 100
+	barW := catW / (slots - (slots-1)*overlap + gap)
+
+	// valPos maps a value to a pixel along the value axis.
+	valPos := func(v float64) int {
+		v = math.Max(minVal, math.Min(maxVal, v))
+		if horizontal {
+			return px + int(float64(pw)*(v-minVal)/valRange)
+		}
+		return py + ph - int(float64(ph)*(v-minVal)/valRange)
 	}
 
 	for ci, cat := range cats {
+		var total, posSum, negSum float64
+		if percent {
+			for _, s := range c.Series {
+				total += math.Abs(s.Values[cat])
+			}
+		}
 		for si, s := range c.Series {
 			v := s.Values[cat]
-			barH := int(float64(ph) * (v - minVal) / valRange)
-			bx := px + ci*catW + (si+1)*barW - barW/2
-			by := py + ph - barH
-			sc := getSeriesColor(s, si, palette)
-			r.fillRectBlend(image.Rect(bx, by, bx+barW-1, py+ph), sc)
+			from, to, slot := 0.0, v, si
+			if stacked {
+				sv := v
+				if percent && total > 0 {
+					sv = v / total
+				}
+				if sv >= 0 {
+					from, to = posSum, posSum+sv
+					posSum += sv
+				} else {
+					from, to = negSum, negSum+sv
+					negSum += sv
+				}
+				slot = 0
+			}
+			start := float64(ci)*catW + barW*gap/2 + float64(slot)*barW*(1-overlap)
+			a, b := valPos(from), valPos(to)
+			lo, hi := minInt(a, b), maxInt(a, b)
+			sc := r.getSeriesColor(s, si, palette)
+			if horizontal {
+				// The first category is at the bottom, as in PowerPoint.
+				y0, y1 := py+ph-int(start+barW), py+ph-int(start)
+				r.fillRectBlend(image.Rect(lo, y0, hi, y1), sc)
+				switch {
+				case s.ShowValue && stacked:
+					// Stacked labels sit inside their segment
+					r.drawDataLabel(s, v, (lo+hi)/2, (y0+y1)/2+6)
+				case s.ShowValue:
+					r.drawDataLabelBeside(s, v, hi+3, (y0+y1)/2)
+				}
+				continue
+			}
+			x0, x1 := px+int(start), px+int(start+barW)
+			r.fillRectBlend(image.Rect(x0, lo, x1, hi), sc)
+			switch {
+			case s.ShowValue && stacked:
+				r.drawDataLabel(s, v, (x0+x1)/2, (lo+hi)/2+6)
+			case s.ShowValue:
+				r.drawDataLabel(s, v, (x0+x1)/2, lo-2)
+			}
 		}
 	}
 }
 
-func (r *renderer) renderLineChart(c *LineChart, px, py, pw, ph int) {
-	if len(c.Series) == 0 {
+// drawValueAxisTicks draws the tick marks and labels of a value axis to the
+// left of the plot area.
+func (r *renderer) drawValueAxisTicks(scale AxisScale, axis *ChartAxis, px, py, ph int) {
+	if axis != nil && !axis.Visible {
 		return
 	}
-	palette := chartColors()
+	ticks := scale.Ticks()
+	if len(ticks) < 2 || ticks[len(ticks)-1] <= ticks[0] {
+		return
+	}
+	f := NewFont()
+	f.Size = 9
+	if axis != nil && axis.Font != nil {
+		f = axis.Font
+	}
+	face := r.getFace(f)
+	if face == nil {
+		return
+	}
+	c := r.colorRGBA(f.Color)
+	axisColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	h := face.Metrics().Height.Ceil()
+	for _, v := range ticks {
+		y := py + ph - int(float64(ph)*(v-scale.Min)/(scale.Max-scale.Min))
+		r.drawLine(px-3, y, px, y, axisColor)
+		text := r.formatNumber(v, "General")
+		tw := font.MeasureString(face, text).Ceil()
+		r.drawFontStringCentered(text, f, c, image.Rect(px-5-tw, y-h/2, px-5, y+h-h/2))
+	}
+}
+
+// drawDataLabel draws the value label of a series point, formatted with the
+// series number format, centered on cx with its bottom at bottom.
+// drawHorizontalValueAxisTicks draws the value axis of a horizontal bar
+// chart below the plot area.
+func (r *renderer) drawHorizontalValueAxisTicks(scale AxisScale, axis *ChartAxis, px, py, pw, ph int) {
+	if axis != nil && !axis.Visible {
+		return
+	}
+	ticks := scale.Ticks()
+	if len(ticks) < 2 || ticks[len(ticks)-1] <= ticks[0] {
+		return
+	}
+	f := NewFont()
+	f.Size = 9
+	if axis != nil && axis.Font != nil {
+		f = axis.Font
+	}
+	face := r.getFace(f)
+	if face == nil {
+		return
+	}
+	c := r.colorRGBA(f.Color)
+	axisColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	h := face.Metrics().Height.Ceil()
+	bottom := py + ph
+	for _, v := range ticks {
+		x := px + int(float64(pw)*(v-scale.Min)/(scale.Max-scale.Min))
+		r.drawLine(x, bottom, x, bottom+3, axisColor)
+		text := r.formatNumber(v, "General")
+		tw := font.MeasureString(face, text).Ceil()
+		r.drawFontStringCentered(text, f, c, image.Rect(x-tw/2-1, bottom+4, x+tw/2+1, bottom+4+h))
+	}
+}
+
+// drawDataLabelBeside draws the data label of a horizontal bar, starting at
+// left and vertically centred on cy.
+func (r *renderer) drawDataLabelBeside(s *ChartSeries, v float64, left, cy int) {
+	f := s.Font
+	if f == nil {
+		f = NewFont()
+		f.Size = 9
+	}
+	face := r.getFace(f)
+	if face == nil {
+		return
+	}
+	text := r.formatNumber(v, s.NumberFormat)
+	tw := font.MeasureString(face, text).Ceil()
+	h := face.Metrics().Height.Ceil()
+	r.drawFontStringCentered(text, f, r.colorRGBA(f.Color), image.Rect(left, cy-h/2, left+tw+2, cy+h-h/2))
+}
 
-	// Find value range
-	minVal := math.MaxFloat64
-	maxVal := -math.MaxFloat64
-	for _, s := range c.Series {
-		for _, v := range s.Values {
-			if v < minVal {
-				minVal = v
-			}
-			if v > maxVal {
-				maxVal = v
-			}
-		}
+func (r *renderer) drawDataLabel(s *ChartSeries, v float64, cx, bottom int) {
+	f := s.Font
+	if f == nil {
+		f = NewFont()
+		f.Size = 9
 	}
-	if minVal > 0 {
-		minVal = 0
+	face := r.getFace(f)
+	if face == nil {
+		return
 	}
-	if maxVal <= minVal {
-		maxVal = minVal + 1
+	text := r.formatNumber(v, s.NumberFormat)
+	tw := font.MeasureString(face, text).Ceil()
+	h := face.Metrics().Height.Ceil()
+	r.drawFontStringCentered(text, f, r.colorRGBA(f.Color), image.Rect(cx-tw/2-1, bottom-h, cx+tw/2+1, bottom))
+}
+
+func (r *renderer) renderLineChart(c *LineChart, scale AxisScale, px, py, pw, ph int) {
+	if len(c.Series) == 0 {
+		return
+	}
+	palette := chartColors()
+
+	minVal := scale.Min
+	valRange := scale.Max - scale.Min
+	if valRange <= 0 {
+		valRange = 1
 	}
-	valRange := maxVal - minVal
 
 	// Draw axes
 	axisColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
@@ -5846,30 +6664,105 @@ func (r *renderer) renderLineChart(c *LineChart, px, py, pw, ph int) {
 	r.drawLine(px, py, px, py+ph, axisColor)
 
 	for si, s := range c.Series {
-		sc := getSeriesColor(s, si, palette)
+		sc := r.getSeriesColor(s, si, palette)
 		cats := s.Categories
 		nPts := len(cats)
 		if nPts == 0 {
 			continue
 		}
-		prevX, prevY := 0, 0
+		pts := make([]fpoint, nPts)
 		for i, cat := range cats {
-			v := s.Values[cat]
 			ptX := px
 			if nPts > 1 {
 				ptX = px + i*pw/(nPts-1)
 			}
-			ptY := py + ph - int(float64(ph)*(v-minVal)/valRange)
-			if i > 0 {
-				r.drawLineAA(prevX, prevY, ptX, ptY, sc, 2)
+			pts[i] = fpoint{float64(ptX), float64(py + ph - int(float64(ph)*(s.Values[cat]-minVal)/valRange))}
+		}
+		lc, lw, ls := r.seriesLineStyle(s, sc, 2)
+		r.drawSeriesLine(pts, lc, lw, ls, s.isSmooth(c.IsSmooth))
+		for i, cat := range cats {
+			ptX, ptY := int(pts[i].x), int(pts[i].y)
+			r.drawSeriesMarker(s, ptX, ptY, sc, 5)
+			if s.ShowValue {
+				r.drawDataLabel(s, s.Values[cat], ptX, ptY-4)
 			}
-			// Draw marker
-			r.fillEllipseAA(ptX-2, ptY-2, 5, 5, sc)
-			prevX, prevY = ptX, ptY
 		}
 	}
 }
 
+// seriesLineStyle returns the color, width in pixels and dash style of the
+// line of a series drawn in color sc, defaultWidth pixels wide unless the
+// series sets its own line.
+func (r *renderer) seriesLineStyle(s *ChartSeries, sc color.RGBA, defaultWidth int) (color.RGBA, int, BorderStyle) {
+	width, style := defaultWidth, BorderSolid
+	if o := s.Outline; o != nil {
+		if o.Color.ARGB != "" {
+			sc = r.colorRGBA(o.Color)
+		}
+		if o.Width > 0 {
+			width = maxInt(int(float64(o.Width)*12700.0*r.scaleX), 1)
+		}
+		if o.Dash != "" {
+			style = o.Dash
+		}
+	}
+	return sc, width, style
+}
+
+// drawSeriesLine draws a series line through pts, smoothed with a
+// Catmull-Rom spline as PowerPoint does when smooth is set.
+func (r *renderer) drawSeriesLine(pts []fpoint, c color.RGBA, width int, style BorderStyle, smooth bool) {
+	if style == BorderNone || len(pts) < 2 {
+		return
+	}
+	if smooth && len(pts) > 2 {
+		// Flatten the whole spline first so dashes run on across points.
+		curve := []fpoint{pts[0]}
+		for i := 0; i+1 < len(pts); i++ {
+			p0, p1, p2 := pts[maxInt(i-1, 0)], pts[i], pts[i+1]
+			p3 := pts[minInt(i+2, len(pts)-1)]
+			curve = append(curve, r.flattenCubicBezier(p1.x, p1.y,
+				p1.x+(p2.x-p0.x)/6, p1.y+(p2.y-p0.y)/6,
+				p2.x-(p3.x-p1.x)/6, p2.y-(p3.y-p1.y)/6,
+				p2.x, p2.y, 0)...)
+			curve = append(curve, p2)
+		}
+		pts = curve
+	}
+	if style == BorderDash || style == BorderDot {
+		r.drawDashedPolylineAA(pts, c, width, style)
+		return
+	}
+	for i := 1; i < len(pts); i++ {
+		r.drawLineAAf(pts[i-1].x, pts[i-1].y, pts[i].x, pts[i].y, c, width)
+	}
+}
+
+// drawSeriesMarker draws the marker of a series point centred on x, y:
+// defaultSize pixels in the series color unless the series sets its own.
+func (r *renderer) drawSeriesMarker(s *ChartSeries, x, y int, sc color.RGBA, defaultSize int) {
+	size, fill, border := defaultSize, sc, sc
+	if m := s.Marker; m != nil {
+		if m.Symbol == MarkerNone {
+			return
+		}
+		if m.Size > 0 {
+			size = maxInt(int(float64(m.Size)*12700.0*r.scaleX), 2)
+		}
+		if m.FillColor.ARGB != "" {
+			fill = r.colorRGBA(m.FillColor)
+		}
+		if m.BorderColor.ARGB != "" {
+			border = r.colorRGBA(m.BorderColor)
+		}
+	}
+	x0, y0 := x-size/2, y-size/2
+	r.fillEllipseAA(x0, y0, size, size, fill)
+	if border != fill {
+		r.drawEllipseAA(x0, y0, size, size, border, 1)
+	}
+}
+
 func (r *renderer) renderPieChart(series []*ChartSeries, px, py, pw, ph int) {
 	if len(series) == 0 || len(series[0].Categories) == 0 {
 		return
@@ -6013,31 +6906,17 @@ func (r *renderer) fillDoughnutSlice(cx, cy, innerR, outerR int, startAngle, end
 	}
 }
 
-func (r *renderer) renderAreaChart(c *AreaChart, px, py, pw, ph int) {
+func (r *renderer) renderAreaChart(c *AreaChart, scale AxisScale, px, py, pw, ph int) {
 	if len(c.Series) == 0 {
 		return
 	}
 	palette := chartColors()
 
-	minVal := math.MaxFloat64
-	maxVal := -math.MaxFloat64
-	for _, s := range c.Series {
-		for _, v := range s.Values {
-			if v < minVal {
-				minVal = v
-			}
-			if v > maxVal {
-				maxVal = v
-			}
-		}
-	}
-	if minVal > 0 {
-		minVal = 0
-	}
-	if maxVal <= minVal {
-		maxVal = minVal + 1
+	minVal := scale.Min
+	valRange := scale.Max - scale.Min
+	if valRange <= 0 {
+		valRange = 1
 	}
-	valRange := maxVal - minVal
 
 	// Axes
 	axisColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
@@ -6045,7 +6924,7 @@ func (r *renderer) renderAreaChart(c *AreaChart, px, py, pw, ph int) {
 	r.drawLine(px, py, px, py+ph, axisColor)
 
 	for si, s := range c.Series {
-		sc := getSeriesColor(s, si, palette)
+		sc := r.getSeriesColor(s, si, palette)
 		// Semi-transparent fill
 		fillC := color.RGBA{R: sc.R, G: sc.G, B: sc.B, A: 128}
 		cats := s.Categories
@@ -6071,7 +6950,7 @@ func (r *renderer) renderAreaChart(c *AreaChart, px, py, pw, ph int) {
 
 		// Draw line on top
 		for i := 0; i < nPts-1; i++ {
-			r.drawLineAA(int(pts[i].x), int(pts[i].y), int(pts[i+1].x), int(pts[i+1].y), sc, 2)
+			r.drawLineAAf(pts[i].x, pts[i].y, pts[i+1].x, pts[i+1].y, sc, 2)
 		}
 	}
 }
@@ -6108,17 +6987,24 @@ func (r *renderer) renderScatterChart(c *ScatterChart, px, py, pw, ph int) {
 	r.drawLine(px, py, px, py+ph, axisColor)
 
 	for si, s := range c.Series {
-		sc := getSeriesColor(s, si, palette)
+		sc := r.getSeriesColor(s, si, palette)
 		cats := s.Categories
 		nPts := len(cats)
 		if nPts == 0 {
 			continue
 		}
+		pts := make([]fpoint, nPts)
 		for i, cat := range cats {
 			v := s.Values[cat]
-			ptX := px + (i * pw / maxInt(nPts-1, 1))
-			ptY := py + ph - int(float64(ph)*(v-minVal)/valRange)
-			r.fillEllipseAA(ptX-3, ptY-3, 7, 7, sc)
+			pts[i] = fpoint{float64(px + (i * pw / maxInt(nPts-1, 1))), float64(py + ph - int(float64(ph)*(v-minVal)/valRange))}
+		}
+		// Only series given a line of their own are drawn with one.
+		if s.Outline != nil {
+			lc, lw, ls := r.seriesLineStyle(s, sc, 2)
+			r.drawSeriesLine(pts, lc, lw, ls, s.isSmooth(c.IsSmooth))
+		}
+		for _, pt := range pts {
+			r.drawSeriesMarker(s, int(pt.x), int(pt.y), sc, 7)
 		}
 	}
 }
@@ -6161,7 +7047,7 @@ func (r *renderer) renderRadarChart(c *RadarChart, px, py, pw, ph int) {
 
 	// Draw series
 	for si, s := range c.Series {
-		sc := getSeriesColor(s, si, palette)
+		sc := r.getSeriesColor(s, si, palette)
 		cats := s.Categories
 		nPts := len(cats)
 		if nPts == 0 {
@@ -6180,7 +7066,7 @@ func (r *renderer) renderRadarChart(c *RadarChart, px, py, pw, ph int) {
 		// Draw polygon
 		for i := 0; i < nPts; i++ {
 			j := (i + 1) % nPts
-			r.drawLineAA(int(pts[i].x), int(pts[i].y), int(pts[j].x), int(pts[j].y), sc, 2)
+			r.drawLineAAf(pts[i].x, pts[i].y, pts[j].x, pts[j].y, sc, 2)
 		}
 		// Fill with semi-transparent
 		fillC := color.RGBA{R: sc.R, G: sc.G, B: sc.B, A: 64}
@@ -6188,13 +7074,200 @@ func (r *renderer) renderRadarChart(c *RadarChart, px, py, pw, ph int) {
 	}
 }
 
+func (r *renderer) renderWaterfallChart(c *WaterfallChart, scale AxisScale, px, py, pw, ph int) {
+	if len(c.Series) == 0 {
+		return
+	}
+	palette := chartColors()
+	s := c.Series[0]
+	steps := c.steps(s)
+	if len(steps) == 0 {
+		return
+	}
+	valRange := scale.Max - scale.Min
+	if valRange <= 0 {
+		valRange = 1
+	}
+	toY := func(v float64) int {
+		return py + ph - int(float64(ph)*(v-scale.Min)/valRange)
+	}
+
+	// Draw axes along zero and the left edge
+	axisColor := color.RGBA{R: 128, G: 128, B: 128, A: 255}
+	zeroY := toY(math.Max(scale.Min, math.Min(0, scale.Max)))
+	r.drawLine(px, zeroY, px+pw, zeroY, axisColor)
+	r.drawLine(px, py, px, py+ph, axisColor)
+
+	catW := pw / len(steps)
+	barW := catW * 2 / 3
+	if barW < 1 {
+		barW = 1
+	}
+	for i, st := range steps {
+		bc := palette[0]
+		switch {
+		case st.Total:
+			bc = palette[2]
+		case st.End < st.Start:
+			bc = palette[1]
+		}
+		bx := px + i*catW + (catW-barW)/2
+		top, bottom := toY(math.Max(st.Start, st.End)), toY(math.Min(st.Start, st.End))
+		if bottom-top < 1 {
+			bottom = top + 1
+		}
+		r.fillRectBlend(image.Rect(bx, top, bx+barW, bottom), bc)
+		if c.ConnectorLines && i+1 < len(steps) {
+			ny := toY(st.End)
+			r.drawLine(bx+barW, ny, bx+catW, ny, axisColor)
+		}
+		if s.ShowValue {
+			r.drawDataLabel(s, s.Values[s.Categories[i]], bx+barW/2, top-2)
+		}
+	}
+}
+
+func (r *renderer) renderFunnelChart(c *FunnelChart, px, py, pw, ph int) {
+	if len(c.Series) == 0 {
+		return
+	}
+	palette := chartColors()
+	s := c.Series[0]
+	n := len(s.Categories)
+	if n == 0 {
+		return
+	}
+	// Funnels have no value axis: use the margin kept for its labels
+	px -= 30
+	pw += 30
+	maxVal := 0.0
+	for _, cat := range s.Categories {
+		maxVal = math.Max(maxVal, s.Values[cat])
+	}
+	if maxVal <= 0 {
+		maxVal = 1
+	}
+
+	// Each bar's gap is a percentage of the bar height
+	slotH := float64(ph) / float64(n)
+	barH := slotH / (1 + float64(c.GapWidthPercent)/100)
+	sc := r.getSeriesColor(s, 0, palette)
+	cx := px + pw/2
+	for i, cat := range s.Categories {
+		v := math.Max(s.Values[cat], 0)
+		bw := int(float64(pw) * v / maxVal)
+		by := py + int(float64(i)*slotH+(slotH-barH)/2)
+		bh := int(barH)
+		if bh < 1 {
+			bh = 1
+		}
+		r.fillRectBlend(image.Rect(cx-bw/2, by, cx-bw/2+bw, by+bh), sc)
+		if s.ShowValue {
+			r.drawDataLabel(s, s.Values[cat], cx, by+bh/2+6)
+		}
+	}
+}
+
+// chartDataTableSeries returns the series shown in the data table of a
+// chart: none unless the chart shows one and plots categories along a
+// horizontal axis.
+func chartDataTableSeries(s *ChartShape) []*ChartSeries {
+	ct := s.plotArea.GetType()
+	if !s.dataTable || isHorizontalBar(ct) {
+		return nil
+	}
+	switch ct.(type) {
+	case *BarChart, *Bar3DChart, *LineChart, *AreaChart:
+	default:
+		return nil
+	}
+	series := getChartSeries(ct)
+	if len(series) == 0 || len(series[0].Categories) == 0 {
+		return nil
+	}
+	return series
+}
+
+// chartDataTableFace returns the font and face of data table text and the
+// height of a table row.
+func (r *renderer) chartDataTableFace() (*Font, font.Face, int) {
+	f := NewFont()
+	f.Size = 9
+	face := r.getFace(f)
+	if face == nil {
+		return nil, nil, 0
+	}
+	return f, face, face.Metrics().Height.Ceil() + 4
+}
+
+// measureChartDataTable returns the height of a chart's data table and the
+// width of its series name column.
+func (r *renderer) measureChartDataTable(s *ChartShape, series []*ChartSeries) (height, nameW int) {
+	_, face, rowH := r.chartDataTableFace()
+	if face == nil {
+		return 0, 0
+	}
+	for _, ser := range series {
+		nameW = maxInt(nameW, font.MeasureString(face, ser.Title).Ceil())
+	}
+	nameW += 8
+	if s.dataTableKeys {
+		nameW += rowH / 2
+	}
+	return rowH * (len(series) + 1), nameW
+}
+
+// renderChartDataTable draws the data table of a chart below its plot
+// area: a header row of categories aligned with the plot, then one row of
+// values per series.
+func (r *renderer) renderChartDataTable(s *ChartShape, series []*ChartSeries, left, top, pw, nameW int) {
+	f, face, rowH := r.chartDataTableFace()
+	if face == nil {
+		return
+	}
+	palette := chartColors()
+	textColor := r.colorRGBA(NewFont().Color)
+	border := color.RGBA{R: 191, G: 191, B: 191, A: 255}
+	cats := series[0].Categories
+	px := left + nameW
+	colX := func(ci int) int { return px + ci*pw/len(cats) }
+	bottom := top + rowH*(len(series)+1)
+
+	for ci, cat := range cats {
+		r.drawFontStringCentered(cat, f, textColor, image.Rect(colX(ci), top, colX(ci+1), top+rowH))
+	}
+	for si, ser := range series {
+		y := top + rowH*(si+1)
+		nx := left + 4
+		if s.dataTableKeys {
+			k := rowH / 2
+			r.fillRectFast(image.Rect(nx, y+(rowH-k)/2, nx+k, y+(rowH-k)/2+k), r.getSeriesColor(ser, si, palette))
+			nx += k + 3
+		}
+		tw := font.MeasureString(face, ser.Title).Ceil()
+		r.drawFontStringCentered(ser.Title, f, textColor, image.Rect(nx, y, nx+tw, y+rowH))
+		for ci, cat := range cats {
+			r.drawFontStringCentered(r.formatNumber(ser.Values[cat], ser.NumberFormat), f, textColor,
+				image.Rect(colX(ci), y, colX(ci+1), y+rowH))
+		}
+		r.drawLine(left, y, px+pw, y, border)
+	}
+
+	// Borders: the header row has no name cell, as in PowerPoint
+	r.drawLine(px, top, px+pw, top, border)
+	r.drawLine(left, bottom, px+pw, bottom, border)
+	r.drawLine(left, top+rowH, left, bottom, border)
+	for ci := 0; ci <= len(cats); ci++ {
+		r.drawLine(colX(ci), top, colX(ci), bottom, border)
+	}
+}
+
 func (r *renderer) renderChartLegend(s *ChartShape, lx, ly, lw, lh int) {
 	ct := s.plotArea.GetType()
 	if ct == nil {
 		return
 	}
 	palette := chartColors()
-	face := r.getFace(s.legend.Font)
 
 	var names []string
 	var colors []color.RGBA
@@ -6203,17 +7276,18 @@ func (r *renderer) renderChartLegend(s *ChartShape, lx, ly, lw, lh int) {
 	case *BarChart:
 		for i, ser := range c.Series {
 			names = append(names, ser.Title)
-			colors = append(colors, getSeriesColor(ser, i, palette))
+			colors = append(colors, r.getSeriesColor(ser, i, palette))
 		}
 	case *Bar3DChart:
 		for i, ser := range c.Series {
 			names = append(names, ser.Title)
-			colors = append(colors, getSeriesColor(ser, i, palette))
+			colors = append(colors, r.getSeriesColor(ser, i, palette))
 		}
 	case *LineChart:
 		for i, ser := range c.Series {
 			names = append(names, ser.Title)
-			colors = append(colors, getSeriesColor(ser, i, palette))
+			lc, _, _ := r.seriesLineStyle(ser, r.getSeriesColor(ser, i, palette), 0)
+			colors = append(colors, lc)
 		}
 	case *PieChart:
 		if len(c.Series) > 0 {
@@ -6239,17 +7313,26 @@ func (r *renderer) renderChartLegend(s *ChartShape, lx, ly, lw, lh int) {
 	case *AreaChart:
 		for i, ser := range c.Series {
 			names = append(names, ser.Title)
-			colors = append(colors, getSeriesColor(ser, i, palette))
+			colors = append(colors, r.getSeriesColor(ser, i, palette))
 		}
 	case *ScatterChart:
 		for i, ser := range c.Series {
 			names = append(names, ser.Title)
-			colors = append(colors, getSeriesColor(ser, i, palette))
+			colors = append(colors, r.getSeriesColor(ser, i, palette))
 		}
 	case *RadarChart:
 		for i, ser := range c.Series {
 			names = append(names, ser.Title)
-			colors = append(colors, getSeriesColor(ser, i, palette))
+			colors = append(colors, r.getSeriesColor(ser, i, palette))
+		}
+	case *WaterfallChart:
+		// As in PowerPoint, the legend explains the column colors
+		names = []string{"Increase", "Decrease", "Total"}
+		colors = palette[:3]
+	case *FunnelChart:
+		for i, ser := range c.Series {
+			names = append(names, ser.Title)
+			colors = append(colors, r.getSeriesColor(ser, i, palette))
 		}
 	}
 
@@ -6260,6 +7343,8 @@ func (r *renderer) renderChartLegend(s *ChartShape, lx, ly, lw, lh int) {
 	// Draw legend entries horizontally centered
 	entryW := lw / len(names)
 	for i, name := range names {
+		entryFont := s.legend.GetEntryFont(i)
+		face := r.getFace(entryFont)
 		ex := lx + i*entryW
 		// Color box
 		boxSize := 10
@@ -6267,13 +7352,7 @@ func (r *renderer) renderChartLegend(s *ChartShape, lx, ly, lw, lh int) {
 		by := ly + (lh-boxSize)/2
 		r.fillRectFast(image.Rect(bx, by, bx+boxSize, by+boxSize), colors[i])
 		// Text
-		d := &font.Drawer{
-			Dst:  r.img,
-			Src:  image.NewUniform(color.RGBA{A: 255}),
-			Face: face,
-			Dot:  fixed.P(bx+boxSize+4, ly+lh/2+4),
-		}
-		d.DrawString(name)
+		r.drawFontText(name, entryFont, face, bx+boxSize+4, ly+lh/2+4, r.colorRGBA(entryFont.Color))
 	}
 }
 
@@ -0,0 +1,58 @@
+package gopresentation
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// TestVerticalTextRotation checks that vert text reads top to bottom,
+// turned a quarter clockwise, and vert270 text bottom to top: the start of
+// the first line lies at the top right of the box for vert and at the
+// bottom left for vert270, as in PowerPoint.
+func TestVerticalTextRotation(t *testing.T) {
+	for _, tc := range []struct {
+		dir        string
+		top, right bool
+	}{
+		{"vert", true, true},
+		{"eaVert", true, true},
+		{"vert270", false, false},
+	} {
+		p := New()
+		p.GetLayout().CX, p.GetLayout().CY = 9144000, 9144000
+		box := p.GetActiveSlide().CreateRichTextShape()
+		box.SetPosition(0, 0).SetSize(9144000, 9144000)
+		box.CreateTextRun("Vertical")
+		box.textDirection = tc.dir
+
+		var buf bytes.Buffer
+		if err := p.RenderSlide(0, &buf, &RenderOptions{Width: 600, Format: ImageFormatPNG}); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ink := image.Rectangle{}
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if r, _, _, _ := img.At(x, y).RGBA(); r < 0x8000 {
+					ink = ink.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		if ink.Empty() {
+			t.Fatalf("%s: no text drawn", tc.dir)
+		}
+		mid := b.Dx() / 2
+		if top := ink.Max.Y <= mid; top != tc.top {
+			t.Errorf("%s: text at rows %d-%d of %d", tc.dir, ink.Min.Y, ink.Max.Y, b.Dy())
+		}
+		if right := ink.Min.X >= mid; right != tc.right {
+			t.Errorf("%s: text at columns %d-%d of %d", tc.dir, ink.Min.X, ink.Max.X, b.Dx())
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package gopresentation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SetHTML replaces the text of the shape with paragraphs built from an HTML
+// snippet. A safe subset is understood: b/strong, i/em, u, s/strike/del,
+// sup, sub, code, br, p, div, h1 to h6, ul/ol with li, a with an http,
+// https, mailto, ftp or tel href, font with a color or face, and span with
+// a style setting color, font-weight, font-style, text-decoration,
+// font-size or font-family. Other tags are dropped but their text is kept,
+// except for the content of script and style. Whitespace is collapsed as
+// in a browser. Malformed markup is read as far as possible and the rest
+// is added as plain text.
+func (r *RichTextShape) SetHTML(html string) *RichTextShape {
+	b := &markupBuilder{}
+	b.html(html)
+	r.setMarkupParagraphs(b)
+	return r
+}
+
+// SetMarkdown replaces the text of the shape with paragraphs built from a
+// Markdown snippet. Understood are paragraphs (separated by blank lines;
+// lines ending in two spaces or a backslash break the line), # headings,
+// bulleted (-, * or +) and numbered (1. or 1)) lists nested by indentation,
+// **bold**, *italic*, ~~strike-through~~, `code`, [links](url) and
+// backslash escapes.
+func (r *RichTextShape) SetMarkdown(markdown string) *RichTextShape {
+	b := &markupBuilder{}
+	b.markdown(markdown)
+	r.setMarkupParagraphs(b)
+	return r
+}
+
+// setMarkupParagraphs replaces the paragraphs of the shape with those of b.
+func (r *RichTextShape) setMarkupParagraphs(b *markupBuilder) {
+	if len(b.paras) == 0 {
+		b.paras = []*Paragraph{NewParagraph()}
+	}
+	r.paragraphs = b.paras
+	r.activeParagraph = len(b.paras) - 1
+}
+
+// markupStyle is the formatting of text in an HTML or Markdown snippet.
+type markupStyle struct {
+	bold, italic, underline, strike bool
+	sup, sub                        bool
+	color                           string // ARGB, or "" for the default
+	size                            int    // points, or 0 for the default
+	fontName                        string
+	link                            string
+}
+
+// markupHeadingSizes are the font sizes of headings of levels 1 to 6.
+var markupHeadingSizes = [6]int{28, 24, 20, 18, 16, 14}
+
+// markupCodeFont is the font of code spans.
+const markupCodeFont = "Courier New"
+
+// markupIndent is the indentation of a list level, in EMU.
+const markupIndent = 342900
+
+// markupBuilder builds paragraphs from the text and structure of a snippet.
+type markupBuilder struct {
+	paras []*Paragraph
+	// para is the paragraph text is added to; nil starts a new one.
+	para *Paragraph
+	// last is the run text of style lastStyle is appended to, if any.
+	last      *TextRun
+	lastStyle markupStyle
+	// space is a collapsed space waiting for the next text.
+	space bool
+}
+
+// start begins a new paragraph.
+func (b *markupBuilder) start() *Paragraph {
+	b.para = NewParagraph()
+	b.paras = append(b.paras, b.para)
+	b.last = nil
+	b.space = false
+	return b.para
+}
+
+// markupList is an open list of a snippet.
+type markupList struct {
+	ordered bool
+	items   int // items started so far
+	indent  int // indentation of the items, in Markdown
+}
+
+// startListItem begins a paragraph for the next item of the innermost of
+// lists; without lists, it begins a bulleted paragraph.
+func (b *markupBuilder) startListItem(lists []markupList) {
+	level := max(len(lists)-1, 0)
+	var l markupList
+	if len(lists) > 0 {
+		lists[level].items++
+		l = lists[level]
+	}
+	p := b.start()
+	bullet := NewBullet()
+	if l.ordered {
+		// Numbering each item explicitly keeps the numbers when items of a
+		// list are separated by nested lists.
+		bullet.SetNumericBullet(NumFormatArabicPeriod, l.items)
+	} else {
+		bullet.SetCharBullet("•")
+	}
+	p.SetBullet(bullet)
+	p.alignment.Level = level
+	p.alignment.MarginLeft = int64(level+1) * markupIndent
+	p.alignment.Indent = -markupIndent
+}
+
+// end ends the current paragraph.
+func (b *markupBuilder) end() {
+	b.para = nil
+	b.last = nil
+	b.space = false
+}
+
+// lineBreak adds a line break to the current paragraph.
+func (b *markupBuilder) lineBreak() {
+	if b.para == nil {
+		b.start()
+	}
+	b.para.CreateBreak()
+	b.last = nil
+	b.space = false
+}
+
+// text adds text in style st, collapsing its whitespace to single spaces
+// and dropping it at the start of a paragraph.
+func (b *markupBuilder) text(s string, st markupStyle) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" {
+			b.space = true
+		}
+		return
+	}
+	out := strings.Join(words, " ")
+	if (b.space || unicode.IsSpace(rune(s[0]))) && b.para != nil && len(b.para.elements) > 0 {
+		// The space goes with the text on the side that is not underlined
+		// or struck through, preferring the text before it.
+		lined := func(st markupStyle) bool { return st.underline || st.strike || st.link != "" }
+		if b.last != nil && (!lined(b.lastStyle) || lined(st)) {
+			b.last.text += " "
+		} else {
+			out = " " + out
+		}
+	}
+	b.run(out, st)
+	b.space = unicode.IsSpace(rune(s[len(s)-1]))
+}
+
+// run adds text in style st as is.
+func (b *markupBuilder) run(s string, st markupStyle) {
+	if s == "" {
+		return
+	}
+	if b.para == nil {
+		b.start()
+	}
+	if b.last != nil && b.lastStyle == st {
+		b.last.text += s
+		return
+	}
+	tr := b.para.CreateTextRun(s)
+	f := tr.font
+	f.Bold = st.bold
+	f.Italic = st.italic
+	if st.underline {
+		f.Underline = UnderlineSingle
+	}
+	f.Strikethrough = st.strike
+	f.Superscript = st.sup
+	f.Subscript = st.sub && !st.sup
+	if st.color != "" {
+		f.Color = NewColor(st.color)
+	}
+	if st.size > 0 {
+		f.Size = st.size
+	}
+	if st.fontName != "" {
+		f.Name = st.fontName
+	}
+	if st.link != "" {
+		tr.hyperlink = NewHyperlink(st.link)
+		if st.color == "" {
+			f.Color = NewColor("FF0563C1")
+		}
+		f.Underline = UnderlineSingle
+	}
+	b.last = tr
+	b.lastStyle = st
+}
+
+// --- HTML ---
+
+// html adds the content of an HTML snippet.
+func (b *markupBuilder) html(s string) {
+	d := xml.NewDecoder(strings.NewReader("<html>" + s + "</html>"))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	stack := []markupStyle{{}} // styles of the open elements
+	style := func() markupStyle { return stack[len(stack)-1] }
+	var lists []markupList
+	skip := 0 // depth inside script or style
+
+	for {
+		offset := d.InputOffset()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Keep the text of what could not be parsed.
+			rest := s[max(0, min(int(offset)-len("<html>"), len(s))):]
+			b.text(stripTags(rest), style())
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			st := style()
+			switch name {
+			case "script", "style":
+				skip++
+			case "b", "strong":
+				st.bold = true
+			case "i", "em":
+				st.italic = true
+			case "u", "ins":
+				st.underline = true
+			case "s", "strike", "del":
+				st.strike = true
+			case "sup":
+				st.sup = true
+			case "sub":
+				st.sub = true
+			case "code", "tt", "kbd":
+				st.fontName = markupCodeFont
+			case "br":
+				b.lineBreak()
+			case "p", "div", "blockquote":
+				b.end()
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				b.end()
+				st.bold = true
+				st.size = markupHeadingSizes[name[1]-'1']
+			case "ul", "ol":
+				b.end()
+				lists = append(lists, markupList{ordered: name == "ol"})
+			case "li":
+				b.startListItem(lists)
+			case "a":
+				if href := safeLink(attrValue(t.Attr, "href")); href != "" {
+					st.link = href
+				}
+			case "font":
+				if c := cssColor(attrValue(t.Attr, "color")); c != "" {
+					st.color = c
+				}
+				if face := attrValue(t.Attr, "face"); face != "" {
+					st.fontName = firstFontFamily(face)
+				}
+			}
+			if css := attrValue(t.Attr, "style"); css != "" {
+				st = applyCSS(st, css)
+			}
+			stack = append(stack, st)
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			switch name {
+			case "script", "style":
+				skip = max(skip-1, 0)
+			case "p", "div", "blockquote", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				b.end()
+			case "ul", "ol":
+				b.end()
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+			}
+		case xml.CharData:
+			if skip == 0 {
+				b.text(string(t), style())
+			}
+		}
+	}
+}
+
+// stripTags returns s without anything that looks like a tag, with
+// character references left as written.
+func stripTags(s string) string {
+	return htmlTagPattern.ReplaceAllString(s, " ")
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// safeLink returns href if it is a relative link or uses a scheme that
+// cannot run code, and "" otherwise.
+func safeLink(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+	scheme, _, found := strings.Cut(href, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return href
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto", "ftp", "tel":
+		return href
+	}
+	return ""
+}
+
+// applyCSS returns st changed by the declarations of an inline style.
+func applyCSS(st markupStyle, css string) markupStyle {
+	for _, decl := range strings.Split(css, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch strings.ToLower(strings.TrimSpace(prop)) {
+		case "color":
+			if c := cssColor(value); c != "" {
+				st.color = c
+			}
+		case "font-weight":
+			n, err := strconv.Atoi(value)
+			st.bold = value == "bold" || value == "bolder" || (err == nil && n >= 600)
+		case "font-style":
+			st.italic = value == "italic" || value == "oblique"
+		case "text-decoration", "text-decoration-line":
+			st.underline = strings.Contains(value, "underline")
+			st.strike = strings.Contains(value, "line-through")
+		case "font-size":
+			if size := cssFontSize(value); size > 0 {
+				st.size = size
+			}
+		case "font-family":
+			st.fontName = firstFontFamily(value)
+		}
+	}
+	return st
+}
+
+// cssColor returns the ARGB value of a CSS color given as #rgb, #rrggbb,
+// rgb(r, g, b) or a color name, or "" if it is none of these.
+func cssColor(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if _, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return "FF" + strings.ToUpper(hex)
+		}
+		return ""
+	}
+	if args, ok := strings.CutPrefix(s, "rgb("); ok {
+		parts := strings.Split(strings.TrimSuffix(args, ")"), ",")
+		if len(parts) != 3 {
+			return ""
+		}
+		argb := "FF"
+		for _, part := range parts {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 0 || n > 255 {
+				return ""
+			}
+			argb += fmt.Sprintf("%02X", n)
+		}
+		return argb
+	}
+	if rgb, ok := presetColors[s]; ok {
+		return "FF" + rgb
+	}
+	return ""
+}
+
+// cssFontSize returns the size in points of a CSS font size in pt or px,
+// or 0.
+func cssFontSize(s string) int {
+	unit := 1.0
+	num, ok := strings.CutSuffix(s, "pt")
+	if !ok {
+		if num, ok = strings.CutSuffix(s, "px"); !ok {
+			return 0
+		}
+		unit = 0.75
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	return int(math.Round(v * unit))
+}
+
+// firstFontFamily returns the first family of a CSS font-family list.
+func firstFontFamily(s string) string {
+	name, _, _ := strings.Cut(s, ",")
+	return strings.Trim(strings.TrimSpace(name), `"'`)
+}
+
+// --- Markdown ---
+
+var (
+	mdHeading  = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListItem = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	mdRule     = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
+
+// markdown adds the content of a Markdown snippet.
+func (b *markupBuilder) markdown(s string) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var lists []markupList
+	hardBreak := false
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.end()
+			lists = lists[:0]
+			hardBreak = false
+			continue
+		}
+		if mdRule.MatchString(line) {
+			b.end()
+			continue
+		}
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			b.end()
+			b.start()
+			b.markdownInline(m[2], markupStyle{bold: true, size: markupHeadingSizes[len(m[1])-1]})
+			b.end()
+			hardBreak = false
+			continue
+		}
+		if m := mdListItem.FindStringSubmatch(line); m != nil {
+			indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+			ordered := m[2][0] >= '0' && m[2][0] <= '9'
+			for len(lists) > 0 && lists[len(lists)-1].indent > indent {
+				lists = lists[:len(lists)-1]
+			}
+			if n := len(lists); n == 0 || lists[n-1].indent < indent {
+				lists = append(lists, markupList{ordered: ordered, indent: indent})
+			} else if lists[n-1].ordered != ordered {
+				lists[n-1] = markupList{ordered: ordered, indent: indent}
+			}
+			b.startListItem(lists)
+			line = m[3]
+		} else if b.para != nil {
+			// A continuation line: a hard break or a space.
+			if hardBreak {
+				b.lineBreak()
+			} else {
+				b.space = true
+			}
+		}
+		hardBreak = strings.HasSuffix(line, "  ") || strings.HasSuffix(line, `\`)
+		if strings.HasSuffix(line, `\`) {
+			line = line[:len(line)-1]
+		}
+		b.markdownInline(line, markupStyle{})
+	}
+}
+
+// markdownInline adds a line of Markdown text in style base.
+func (b *markupBuilder) markdownInline(s string, base markupStyle) {
+	st := base
+	var buf strings.Builder
+	flush := func() {
+		b.text(buf.String(), st)
+		buf.Reset()
+	}
+	isWord := func(i int) bool {
+		return i >= 0 && i < len(s) && (unicode.IsLetter(rune(s[i])) || unicode.IsDigit(rune(s[i])))
+	}
+	// opens reports whether the marker at i can open a span: it is followed
+	// by text and closed later in s.
+	opens := func(i int, marker string) bool {
+		j := i + len(marker)
+		return j < len(s) && s[j] != ' ' && strings.Contains(s[j+1:], marker)
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && unicode.IsPunct(rune(s[i+1])):
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				flush()
+				code := st
+				code.fontName = markupCodeFont
+				b.text(s[i+1:i+1+end], code)
+				i += end + 2
+				continue
+			}
+		case c == '[':
+			if m := mdLink.FindStringSubmatch(s[i:]); m != nil {
+				flush()
+				link := st
+				if href := safeLink(m[2]); href != "" {
+					link.link = href
+				}
+				b.markdownInline(m[1], link)
+				i += len(m[0])
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__"):
+			marker := s[i : i+2]
+			if st.bold != base.bold || opens(i, marker) {
+				flush()
+				if st.bold != base.bold {
+					st.bold = base.bold
+				} else {
+					st.bold = true
+				}
+				i += 2
+				continue
+			}
+		case strings.HasPrefix(s[i:], "~~"):
+			if st.strike != base.strike || opens(i, "~~") {
+				flush()
+				if st.strike != base.strike {
+					st.strike = base.strike
+				} else {
+					st.strike = true
+				}
+				i += 2
+				continue
+			}
+		case c == '*' || c == '_':
+			marker := s[i : i+1]
+			closing := st.italic != base.italic
+			if c == '_' && (closing && isWord(i+1) || !closing && isWord(i-1)) {
+				break // intraword underscore, as in snake_case
+			}
+			if closing || opens(i, marker) {
+				flush()
+				st.italic = !closing || base.italic
+				i++
+				continue
+			}
+		}
+		buf.WriteByte(c)
+		i++
+	}
+	flush()
+}
+
+var mdLink = regexp.MustCompile(`^\[([^\]]*)\]\(\s*([^)\s]*)(?:\s+"[^"]*")?\s*\)`)
@@ -0,0 +1,194 @@
+package gopresentation
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// RoundTripReport is the result of VerifyRoundTrip for one deck.
+type RoundTripReport struct {
+	Path   string
+	Slides int
+	// PackageIssues are the OPC problems found in the package written
+	// back, as reported by ValidatePackage.
+	PackageIssues []string
+	// Differences lists what changed between the deck as read and as read
+	// back after writing, one entry per property.
+	Differences []string
+	// Err is set when the deck could not be read, written or read back.
+	Err error
+}
+
+// OK reports whether the deck survived the round trip unchanged.
+func (r RoundTripReport) OK() bool {
+	return r.Err == nil && len(r.PackageIssues) == 0 && len(r.Differences) == 0
+}
+
+// String returns a human-readable summary of the report.
+func (r RoundTripReport) String() string {
+	var sb strings.Builder
+	switch {
+	case r.Err != nil:
+		fmt.Fprintf(&sb, "%s: %v\n", r.Path, r.Err)
+	case r.OK():
+		fmt.Fprintf(&sb, "%s: ok (%d slides)\n", r.Path, r.Slides)
+	default:
+		fmt.Fprintf(&sb, "%s: %d package issues, %d differences (%d slides)\n",
+			r.Path, len(r.PackageIssues), len(r.Differences), r.Slides)
+	}
+	for _, issue := range r.PackageIssues {
+		fmt.Fprintf(&sb, "  package: %s\n", issue)
+	}
+	for _, diff := range r.Differences {
+		fmt.Fprintf(&sb, "  %s\n", diff)
+	}
+	return sb.String()
+}
+
+// VerifyRoundTrip reads the deck at path, writes it back, validates the
+// written package and reads it again, then compares the two object models:
+// the layout, document properties, slides, notes, comments and each shape's
+// type, position, size, text and image data. It is meant for checking a
+// corpus of real decks, for example in CI:
+//
+//	if r := gopresentation.VerifyRoundTrip(path); !r.OK() {
+//		t.Error(r)
+//	}
+func VerifyRoundTrip(path string) RoundTripReport {
+	report := RoundTripReport{Path: path}
+	before, err := Open(path)
+	if err != nil {
+		report.Err = fmt.Errorf("read: %w", err)
+		return report
+	}
+	report.Slides = len(before.slides)
+
+	var buf bytes.Buffer
+	if err := before.WriteTo(&buf); err != nil {
+		report.Err = fmt.Errorf("write: %w", err)
+		return report
+	}
+	data := buf.Bytes()
+	issues, err := ValidatePackage(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		report.Err = fmt.Errorf("validate: %w", err)
+		return report
+	}
+	report.PackageIssues = issues
+
+	after, err := ReadFrom(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		report.Err = fmt.Errorf("read back: %w", err)
+		return report
+	}
+	report.Differences = compareModels(describeModel(before), describeModel(after))
+	return report
+}
+
+// modelEntry is one property of a presentation, named by where it lives,
+// such as "slide 2, shape 3: text".
+type modelEntry struct {
+	key   string
+	value string
+}
+
+// describeModel flattens the properties of p that must survive a round
+// trip into entries in document order.
+func describeModel(p *Presentation) []modelEntry {
+	var entries []modelEntry
+	add := func(key, format string, args ...any) {
+		entries = append(entries, modelEntry{key: key, value: fmt.Sprintf(format, args...)})
+	}
+	if p.layout != nil {
+		add("layout", "%dx%d", p.layout.CX, p.layout.CY)
+	}
+	if props := p.properties; props != nil {
+		add("title", "%q", props.Title)
+		add("creator", "%q", props.Creator)
+		add("subject", "%q", props.Subject)
+		add("description", "%q", props.Description)
+		add("keywords", "%q", props.Keywords)
+		add("category", "%q", props.Category)
+	}
+	add("slides", "%d", len(p.slides))
+	for i, slide := range p.slides {
+		prefix := fmt.Sprintf("slide %d", i+1)
+		add(prefix+": notes", "%q", slide.GetNotes())
+		add(prefix+": comments", "%d", len(slide.comments))
+		add(prefix+": background", "%t", slide.background != nil && slide.background.Type != FillNone || slide.backgroundRef != nil)
+		add(prefix+": shapes", "%d", len(slide.shapes))
+		entries = describeShapes(entries, prefix, slide.shapes)
+	}
+	return entries
+}
+
+// describeShapes appends the entries of shapes, and of the shapes nested in
+// groups, to entries.
+func describeShapes(entries []modelEntry, prefix string, shapes []Shape) []modelEntry {
+	add := func(key, format string, args ...any) {
+		entries = append(entries, modelEntry{key: key, value: fmt.Sprintf(format, args...)})
+	}
+	for i, shape := range shapes {
+		key := fmt.Sprintf("%s, shape %d", prefix, i+1)
+		b := shape.base()
+		add(key+": type", "%T", shape)
+		add(key+": name", "%q", b.name)
+		add(key+": position", "%d,%d", b.offsetX, b.offsetY)
+		add(key+": size", "%dx%d", b.width, b.height)
+		add(key+": rotation", "%d", b.rotation)
+		add(key+": flip", "%t,%t", b.flipHorizontal, b.flipVertical)
+
+		switch s := shape.(type) {
+		case *RichTextShape:
+			add(key+": text", "%q", extractParagraphsText(s.paragraphs))
+		case *PlaceholderShape:
+			add(key+": text", "%q", extractParagraphsText(s.paragraphs))
+		case *AutoShape:
+			add(key+": text", "%q", s.text)
+		case *DrawingShape:
+			add(key+": image", "%s, %d bytes", s.mimeType, len(s.data))
+		case *TableShape:
+			add(key+": table", "%dx%d", s.numRows, s.numCols)
+			for r, row := range s.rows {
+				for c, cell := range row {
+					if cell != nil {
+						add(fmt.Sprintf("%s: cell %d,%d", key, r+1, c+1), "%q", extractParagraphsText(cell.paragraphs))
+					}
+				}
+			}
+		case *GroupShape:
+			add(key+": shapes", "%d", len(s.shapes))
+			entries = describeShapes(entries, key, s.shapes)
+		}
+	}
+	return entries
+}
+
+// compareModels returns the differences between the entries of two
+// models. Entries present on one side only are reported as missing or
+// added.
+func compareModels(before, after []modelEntry) []string {
+	afterByKey := make(map[string]string, len(after))
+	for _, e := range after {
+		afterByKey[e.key] = e.value
+	}
+	seen := make(map[string]bool, len(before))
+	var diffs []string
+	for _, e := range before {
+		seen[e.key] = true
+		v, ok := afterByKey[e.key]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("%s: %s missing after round trip", e.key, e.value))
+		case v != e.value:
+			diffs = append(diffs, fmt.Sprintf("%s: %s, was %s", e.key, v, e.value))
+		}
+	}
+	for _, e := range after {
+		if !seen[e.key] {
+			diffs = append(diffs, fmt.Sprintf("%s: %s added by round trip", e.key, e.value))
+		}
+	}
+	return diffs
+}
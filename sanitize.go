@@ -0,0 +1,131 @@
+package gopresentation
+
+import (
+	"errors"
+	"time"
+)
+
+// SanitizeOptions selects what Sanitize removes.
+type SanitizeOptions struct {
+	// Notes removes the speaker notes of every slide.
+	Notes bool
+	// Comments removes the comments of every slide, and with them the
+	// comment authors.
+	Comments bool
+	// DocumentProperties clears the core, extended and custom document
+	// properties. The creation and modification times are set to now.
+	DocumentProperties bool
+	// HiddenSlides removes the slides hidden in the slide show. Links to
+	// removed slides are dropped and other slide links are renumbered.
+	HiddenSlides bool
+	// OffSlideContent removes the shapes that lie entirely outside the
+	// slide.
+	OffSlideContent bool
+}
+
+// DefaultSanitizeOptions returns options that remove everything Sanitize
+// can remove.
+func DefaultSanitizeOptions() *SanitizeOptions {
+	return &SanitizeOptions{
+		Notes:              true,
+		Comments:           true,
+		DocumentProperties: true,
+		HiddenSlides:       true,
+		OffSlideContent:    true,
+	}
+}
+
+// Sanitize removes content that should not leave the organisation, such as
+// speaker notes and review comments, before a deck is sent externally. A
+// nil opts uses DefaultSanitizeOptions. It fails without changing the
+// presentation when every slide is hidden and hidden slides are removed.
+func (p *Presentation) Sanitize(opts *SanitizeOptions) error {
+	if opts == nil {
+		opts = DefaultSanitizeOptions()
+	}
+	if opts.HiddenSlides {
+		if err := p.removeHiddenSlides(); err != nil {
+			return err
+		}
+	}
+	for _, slide := range p.slides {
+		if opts.Notes {
+			slide.notes = nil
+		}
+		if opts.Comments {
+			slide.comments = make([]*Comment, 0)
+		}
+		if opts.OffSlideContent && p.layout != nil {
+			slide.removeOffSlideShapes(p.layout.CX, p.layout.CY)
+		}
+	}
+	if opts.DocumentProperties {
+		now := time.Now()
+		p.properties = &DocumentProperties{
+			Created:     now,
+			Modified:    now,
+			customProps: make(map[string]*CustomProperty),
+		}
+	}
+	return nil
+}
+
+// removeHiddenSlides removes the hidden slides and updates the slide links
+// of the slides that remain.
+func (p *Presentation) removeHiddenSlides() error {
+	newIndex := make([]int, len(p.slides))
+	var kept []*Slide
+	for i, slide := range p.slides {
+		newIndex[i] = -1
+		if slide.visible {
+			newIndex[i] = len(kept)
+			kept = append(kept, slide)
+		}
+	}
+	if len(kept) == len(p.slides) {
+		return nil
+	}
+	if len(kept) == 0 {
+		return errors.New("cannot remove hidden slides: every slide is hidden")
+	}
+	remapSlideLinks(kept, newIndex)
+	p.slides = kept
+	if p.activeSlideIndex >= len(p.slides) {
+		p.activeSlideIndex = 0
+	}
+	return nil
+}
+
+// removeOffSlideShapes removes the shapes that lie entirely outside a
+// slide of the given size. Shapes touching the slide edge are kept.
+// Animations are updated to the new shape indexes.
+func (s *Slide) removeOffSlideShapes(slideW, slideH int64) {
+	if slideW <= 0 || slideH <= 0 {
+		return
+	}
+	newIndex := make([]int, len(s.shapes))
+	kept := make([]Shape, 0, len(s.shapes))
+	for i, shape := range s.shapes {
+		x, y := shape.GetOffsetX(), shape.GetOffsetY()
+		w, h := shape.GetWidth(), shape.GetHeight()
+		if x > slideW || y > slideH || x+w < 0 || y+h < 0 {
+			newIndex[i] = -1
+			continue
+		}
+		newIndex[i] = len(kept)
+		kept = append(kept, shape)
+	}
+	if len(kept) == len(s.shapes) {
+		return
+	}
+	s.shapes = kept
+	for _, anim := range s.animations {
+		indexes := anim.ShapeIndexes[:0]
+		for _, idx := range anim.ShapeIndexes {
+			if idx >= 0 && idx < len(newIndex) && newIndex[idx] >= 0 {
+				indexes = append(indexes, newIndex[idx])
+			}
+		}
+		anim.ShapeIndexes = indexes
+	}
+}
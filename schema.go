@@ -0,0 +1,304 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrNoSchemas is returned by the schema checks of a build without the
+// xsd build tag, which leaves the compiled ECMA-376 content models out.
+var ErrNoSchemas = errors.New("schema validation requires building with -tags xsd")
+
+// schemaModels are the content models of the PresentationML and DrawingML
+// elements checked by ValidateSchema, keyed by qualified name or, for
+// names whose type depends on their parent, by parent/name. They are
+// compiled from the ECMA-376 schemas by schema_xsd.go and nil without the
+// xsd build tag.
+var schemaModels map[string]*schemaModel
+
+// schemaNamespaces are the prefixes the content models use for the
+// namespaces of the elements and attributes they name.
+var schemaNamespaces = map[string]string{
+	"http://schemas.openxmlformats.org/presentationml/2006/main":          "p",
+	"http://schemas.openxmlformats.org/drawingml/2006/main":               "a",
+	"http://schemas.openxmlformats.org/drawingml/2006/chart":              "c",
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships": "r",
+	"http://schemas.openxmlformats.org/markup-compatibility/2006":         "mc",
+}
+
+// schemaModel is the content model of an element: the sequence its
+// children must follow and the attributes it requires.
+type schemaModel struct {
+	slots    []schemaSlot
+	required []string
+	any      bool // children are not checked
+}
+
+// schemaSlot is one particle of a sequence: a choice of element names
+// occurring between min and max times, max < 0 meaning unbounded.
+type schemaSlot struct {
+	names    map[string]bool
+	min, max int
+}
+
+// compileSchemaModel compiles a content model written as a sequence of
+// particles separated by spaces. A particle is an element name or a choice
+// of names joined by "|", optionally followed by "?", "*" or "+"; a name
+// starting with "%" stands for the choice in groups. Required attributes
+// follow an "@", as in "p:extLst? @id @name", and "..." allows any
+// children.
+func compileSchemaModel(model string, groups map[string]string) *schemaModel {
+	m := &schemaModel{}
+	for _, tok := range strings.Fields(model) {
+		if attr, ok := strings.CutPrefix(tok, "@"); ok {
+			m.required = append(m.required, attr)
+			continue
+		}
+		if tok == "..." {
+			m.any = true
+			continue
+		}
+		slot := schemaSlot{names: make(map[string]bool), min: 1, max: 1}
+		switch tok[len(tok)-1] {
+		case '?':
+			slot.min = 0
+		case '*':
+			slot.min, slot.max = 0, -1
+		case '+':
+			slot.max = -1
+		}
+		for _, name := range strings.Split(strings.TrimRight(tok, "?*+"), "|") {
+			if group, ok := groups[name]; ok {
+				for _, n := range strings.Split(group, "|") {
+					slot.names[n] = true
+				}
+				continue
+			}
+			slot.names[name] = true
+		}
+		m.slots = append(m.slots, slot)
+	}
+	return m
+}
+
+// ValidateSchema checks the XML parts of the PPTX package in r against
+// the content models of the ECMA-376 schemas: that the elements it knows
+// have the children the schema allows, in the schema's order, and their
+// required attributes. Elements of other namespaces, such as extensions,
+// are not checked, and markup compatibility blocks are checked as their
+// fallback. It returns the problems found, or ErrNoSchemas in a build
+// without the xsd build tag.
+func ValidateSchema(r io.ReaderAt, size int64) ([]string, error) {
+	if schemaModels == nil {
+		return nil, ErrNoSchemas
+	}
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip: %w", err)
+	}
+	files := make([]*zip.File, 0, len(zr.File))
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, ".xml") && f.Name != "[Content_Types].xml" {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	var issues []string
+	for _, f := range files {
+		root, err := readSchemaTree(f)
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %v", f.Name, err))
+			continue
+		}
+		if root != nil {
+			issues = validateSchemaNode(issues, f.Name, root, "", "")
+		}
+	}
+	return issues, nil
+}
+
+// ValidateAgainstSchema writes the presentation in memory and checks the
+// package with ValidateSchema, for use in CI. It returns ErrNoSchemas in a
+// build without the xsd build tag.
+func (w *PPTXWriter) ValidateAgainstSchema() ([]string, error) {
+	if schemaModels == nil {
+		return nil, ErrNoSchemas
+	}
+	var buf bytes.Buffer
+	if err := w.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return ValidateSchema(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+// schemaNode is an element of a part read for ValidateSchema. Its name is
+// qualified with the prefix of schemaNamespaces, or empty for other
+// namespaces.
+type schemaNode struct {
+	name     string
+	attrs    map[string]bool
+	children []*schemaNode
+}
+
+// readSchemaTree reads the elements of the part f.
+func readSchemaTree(f *zip.File) (*schemaNode, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	dec := xml.NewDecoder(io.LimitReader(rc, int64(maxZipEntrySize)))
+	var root *schemaNode
+	var stack []*schemaNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &schemaNode{name: schemaName(t.Name), attrs: make(map[string]bool, len(t.Attr))}
+			for _, a := range t.Attr {
+				if a.Name.Space != "xmlns" && a.Name.Local != "xmlns" {
+					n.attrs[schemaName(a.Name)] = true
+				}
+			}
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// schemaName returns the qualified name of an element or attribute, or ""
+// for a namespace the content models do not use.
+func schemaName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	prefix, ok := schemaNamespaces[name.Space]
+	if !ok {
+		return ""
+	}
+	return prefix + ":" + name.Local
+}
+
+// schemaChildren returns the children of n to check against its content
+// model: markup compatibility blocks are replaced with the children of
+// their fallback, and elements of other namespaces are left out.
+func schemaChildren(n *schemaNode) []*schemaNode {
+	var children []*schemaNode
+	for _, c := range n.children {
+		switch c.name {
+		case "":
+		case "mc:AlternateContent":
+			for _, alt := range c.children {
+				if alt.name == "mc:Fallback" {
+					children = append(children, schemaChildren(alt)...)
+				}
+			}
+		default:
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// validateSchemaNode checks n, a child of the element named parent at
+// path, and its descendants, adding the problems found to issues.
+func validateSchemaNode(issues []string, part string, n *schemaNode, parent, path string) []string {
+	if path == "" {
+		path = n.name
+	} else {
+		path += "/" + n.name
+	}
+	children := schemaChildren(n)
+	model := schemaModels[parent+"/"+n.name]
+	if model == nil {
+		model = schemaModels[n.name]
+	}
+	if model != nil {
+		for _, attr := range model.required {
+			if !n.attrs[attr] {
+				issues = append(issues, fmt.Sprintf("%s: %s: missing attribute %s", part, path, attr))
+			}
+		}
+		if !model.any {
+			issues = model.check(issues, part, path, children)
+		}
+	}
+	for _, c := range children {
+		issues = validateSchemaNode(issues, part, c, n.name, path)
+	}
+	return issues
+}
+
+// check matches children against the sequence of m. The schemas are
+// deterministic, so each child is taken by the first slot from the current
+// one that allows it.
+func (m *schemaModel) check(issues []string, part, path string, children []*schemaNode) []string {
+	slot, count := 0, 0
+	for _, c := range children {
+		next := slot
+		for next < len(m.slots) && !(m.slots[next].names[c.name] && (next > slot || m.slots[next].max < 0 || count < m.slots[next].max)) {
+			next++
+		}
+		if next == len(m.slots) {
+			if m.allows(c.name) {
+				return append(issues, fmt.Sprintf("%s: %s: %s out of order", part, path, c.name))
+			}
+			return append(issues, fmt.Sprintf("%s: %s: unexpected element %s", part, path, c.name))
+		}
+		if next > slot {
+			issues = m.checkMissing(issues, part, path, slot, next, count)
+			slot, count = next, 0
+		}
+		count++
+	}
+	return m.checkMissing(issues, part, path, slot, len(m.slots), count)
+}
+
+// checkMissing reports the required slots from first to end, first having
+// been taken count times, that children did not fill.
+func (m *schemaModel) checkMissing(issues []string, part, path string, first, end, count int) []string {
+	for i := first; i < end; i++ {
+		if i > first {
+			count = 0
+		}
+		if count < m.slots[i].min {
+			names := make([]string, 0, len(m.slots[i].names))
+			for name := range m.slots[i].names {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			issues = append(issues, fmt.Sprintf("%s: %s: missing element %s", part, path, strings.Join(names, " or ")))
+		}
+	}
+	return issues
+}
+
+// allows reports whether any slot of m allows the element name.
+func (m *schemaModel) allows(name string) bool {
+	for _, s := range m.slots {
+		if s.names[name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+//go:build xsd
+
+package gopresentation
+
+// The content models of ECMA-376 Part 1 (transitional) for the elements of
+// slides, layouts, masters, notes and the presentation part, compiled to
+// the syntax of compileSchemaModel. Model groups of the schemas, such as
+// EG_FillProperties, are the %groups.
+
+var schemaGroups = map[string]string{
+	"%color":    "a:scrgbClr|a:srgbClr|a:hslClr|a:sysClr|a:schemeClr|a:prstClr",
+	"%fill":     "a:noFill|a:solidFill|a:gradFill|a:blipFill|a:pattFill|a:grpFill",
+	"%lineFill": "a:noFill|a:solidFill|a:gradFill|a:pattFill",
+	"%effect":   "a:effectLst|a:effectDag",
+	"%geometry": "a:custGeom|a:prstGeom",
+	"%shapes":   "p:sp|p:grpSp|p:graphicFrame|p:cxnSp|p:pic|p:contentPart",
+	"%run":      "a:r|a:br|a:fld",
+	"%media":    "a:audioCd|a:wavAudioFile|a:audioFile|a:videoFile|a:quickTimeFile",
+	"%path":     "a:close|a:moveTo|a:lnTo|a:arcTo|a:quadBezTo|a:cubicBezTo",
+}
+
+const (
+	schemaTextParagraphProperties = "a:lnSpc? a:spcBef? a:spcAft? a:buClrTx|a:buClr? " +
+		"a:buSzTx|a:buSzPct|a:buSzPts? a:buFontTx|a:buFont? a:buNone|a:buAutoNum|a:buChar|a:buBlip? " +
+		"a:tabLst? a:defRPr? a:extLst?"
+	schemaTextCharacterProperties = "a:ln? %fill? %effect? a:highlight? a:uLnTx|a:uLn? a:uFillTx|a:uFill? " +
+		"a:latin? a:ea? a:cs? a:sym? a:hlinkClick? a:hlinkMouseOver? a:rtl? a:extLst?"
+	schemaLineProperties  = "%lineFill? a:prstDash|a:custDash? a:round|a:bevel|a:miter? a:headEnd? a:tailEnd? a:extLst?"
+	schemaShapeProperties = "a:xfrm? %geometry? %fill? a:ln? %effect? a:scene3d? a:sp3d? a:extLst?"
+	schemaTextBody        = "a:bodyPr a:lstStyle? a:p+"
+	schemaTransform       = "a:off? a:ext?"
+)
+
+var schemaSources = map[string]string{
+	// Presentation part
+	"p:presentation": "p:sldMasterIdLst? p:notesMasterIdLst? p:handoutMasterIdLst? p:sldIdLst? p:sldSz? p:notesSz " +
+		"p:smartTags? p:embeddedFontLst? p:custShowLst? p:photoAlbum? p:custDataLst? p:kinsoku? " +
+		"p:defaultTextStyle? p:modifyVerifier? p:extLst?",
+	"p:sldMasterIdLst":   "p:sldMasterId*",
+	"p:sldMasterId":      "p:extLst? @r:id",
+	"p:notesMasterIdLst": "p:notesMasterId?",
+	"p:notesMasterId":    "p:extLst? @r:id",
+	"p:sldIdLst":         "p:sldId*",
+	"p:sldId":            "p:extLst? @id @r:id",
+	"p:sldSz":            "@cx @cy",
+	"p:notesSz":          "@cx @cy",
+	"p:defaultTextStyle": "a:defPPr? a:lvl1pPr? a:lvl2pPr? a:lvl3pPr? a:lvl4pPr? a:lvl5pPr? a:lvl6pPr? " +
+		"a:lvl7pPr? a:lvl8pPr? a:lvl9pPr? a:extLst?",
+
+	// Slides, layouts, masters and notes
+	"p:sld":            "p:cSld p:clrMapOvr? p:transition? p:timing? p:extLst?",
+	"p:sldLayout":      "p:cSld p:clrMapOvr? p:transition? p:timing? p:hf? p:extLst?",
+	"p:sldMaster":      "p:cSld p:clrMap p:sldLayoutIdLst? p:transition? p:timing? p:hf? p:txStyles? p:extLst?",
+	"p:notes":          "p:cSld p:clrMapOvr? p:extLst?",
+	"p:notesMaster":    "p:cSld p:clrMap p:hf? p:notesStyle? p:extLst?",
+	"p:sldLayoutIdLst": "p:sldLayoutId*",
+	"p:sldLayoutId":    "p:extLst? @r:id",
+	"p:clrMapOvr":      "a:masterClrMapping|a:overrideClrMapping",
+	"p:txStyles":       "p:titleStyle? p:bodyStyle? p:otherStyle? p:extLst?",
+	"p:hf":             "p:extLst?",
+	"p:cSld":           "p:bg? p:spTree p:custDataLst? p:controls? p:extLst?",
+	"p:bg":             "p:bgPr|p:bgRef",
+	"p:bgPr":           "%fill %effect? p:extLst?",
+	"p:bgRef":          "%color? @idx",
+	"p:extLst":         "p:ext*",
+
+	// Shapes
+	"p:spTree":            "p:nvGrpSpPr p:grpSpPr %shapes* p:extLst?",
+	"p:grpSp":             "p:nvGrpSpPr p:grpSpPr %shapes* p:extLst?",
+	"p:nvGrpSpPr":         "p:cNvPr p:cNvGrpSpPr p:nvPr",
+	"p:grpSpPr":           "a:xfrm? %fill? %effect? a:scene3d? a:extLst?",
+	"p:grpSpPr/a:xfrm":    "a:off? a:ext? a:chOff? a:chExt?",
+	"p:cNvGrpSpPr":        "a:grpSpLocks? a:extLst?",
+	"p:sp":                "p:nvSpPr p:spPr p:style? p:txBody? p:extLst?",
+	"p:nvSpPr":            "p:cNvPr p:cNvSpPr p:nvPr",
+	"p:cNvPr":             "a:hlinkClick? a:hlinkHover? a:extLst? @id @name",
+	"p:cNvSpPr":           "a:spLocks? a:extLst?",
+	"p:nvPr":              "p:ph? %media? p:custDataLst? p:extLst?",
+	"p:spPr":              schemaShapeProperties,
+	"p:style":             "a:lnRef a:fillRef a:effectRef a:fontRef",
+	"p:txBody":            schemaTextBody,
+	"p:cxnSp":             "p:nvCxnSpPr p:spPr p:style? p:extLst?",
+	"p:nvCxnSpPr":         "p:cNvPr p:cNvCxnSpPr p:nvPr",
+	"p:cNvCxnSpPr":        "a:cxnSpLocks? a:stCxn? a:endCxn? a:extLst?",
+	"p:pic":               "p:nvPicPr p:blipFill p:spPr p:style? p:extLst?",
+	"p:nvPicPr":           "p:cNvPr p:cNvPicPr p:nvPr",
+	"p:cNvPicPr":          "a:picLocks? a:extLst?",
+	"p:blipFill":          "a:blip? a:srcRect? a:tile|a:stretch?",
+	"p:graphicFrame":      "p:nvGraphicFramePr p:xfrm a:graphic p:extLst?",
+	"p:nvGraphicFramePr":  "p:cNvPr p:cNvGraphicFramePr p:nvPr",
+	"p:cNvGraphicFramePr": "a:graphicFrameLocks? a:extLst?",
+	"p:xfrm":              schemaTransform,
+	"a:graphic":           "a:graphicData",
+	"a:graphicData":       "@uri ...",
+	"a:xfrm":              schemaTransform,
+	"a:off":               "@x @y",
+	"a:chOff":             "@x @y",
+	"a:xfrm/a:ext":        "@cx @cy",
+	"p:xfrm/a:ext":        "@cx @cy",
+	"a:chExt":             "@cx @cy",
+	"a:extLst":            "a:ext*",
+	"a:extLst/a:ext":      "@uri ...",
+	"a:spLocks":           "a:extLst?",
+	"a:cxnSpLocks":        "a:extLst?",
+	"a:picLocks":          "a:extLst?",
+	"a:grpSpLocks":        "a:extLst?",
+	"a:graphicFrameLocks": "a:extLst?",
+	"a:stCxn":             "@id @idx",
+	"a:endCxn":            "@id @idx",
+	"a:hlinkClick":        "a:snd? a:extLst?",
+	"a:hlinkHover":        "a:snd? a:extLst?",
+	"a:hlinkMouseOver":    "a:snd? a:extLst?",
+
+	// Geometry
+	"a:prstGeom":       "a:avLst? @prst",
+	"a:custGeom":       "a:avLst? a:gdLst? a:ahLst? a:cxnLst? a:rect? a:pathLst",
+	"a:avLst":          "a:gd*",
+	"a:gdLst":          "a:gd*",
+	"a:gd":             "@name @fmla",
+	"a:pathLst":        "a:path*",
+	"a:pathLst/a:path": "%path*",
+	"a:moveTo":         "a:pt",
+	"a:lnTo":           "a:pt",
+	"a:quadBezTo":      "a:pt a:pt",
+	"a:cubicBezTo":     "a:pt a:pt a:pt",
+	"a:arcTo":          "@wR @hR @stAng @swAng",
+	"a:pt":             "@x @y",
+
+	// Fills, lines and effects
+	"a:solidFill": "%color?",
+	"a:gradFill":  "a:gsLst? a:lin|a:path? a:tileRect?",
+	"a:gsLst":     "a:gs a:gs+",
+	"a:gs":        "%color @pos",
+	"a:blipFill":  "a:blip? a:srcRect? a:tile|a:stretch?",
+	"a:pattFill":  "a:fgClr? a:bgClr?",
+	"a:fgClr":     "%color",
+	"a:bgClr":     "%color",
+	"a:ln":        schemaLineProperties,
+	"a:lnL":       schemaLineProperties,
+	"a:lnR":       schemaLineProperties,
+	"a:lnT":       schemaLineProperties,
+	"a:lnB":       schemaLineProperties,
+	"a:lnTlToBr":  schemaLineProperties,
+	"a:lnBlToTr":  schemaLineProperties,
+	"a:prstDash":  "@val",
+	"a:effectLst": "a:blur? a:fillOverlay? a:glow? a:innerShdw? a:outerShdw? a:prstShdw? a:reflection? a:softEdge?",
+	"a:outerShdw": "%color",
+	"a:innerShdw": "%color",
+	"a:glow":      "%color",
+	"a:scene3d":   "a:camera a:lightRig a:backdrop? a:extLst?",
+	"a:camera":    "a:rot? @prst",
+	"a:lightRig":  "a:rot? @rig @dir",
+	"a:lnRef":     "%color? @idx",
+	"a:fillRef":   "%color? @idx",
+	"a:effectRef": "%color? @idx",
+	"a:fontRef":   "%color? @idx",
+	"a:srgbClr":   "@val ...",
+	"a:schemeClr": "@val ...",
+	"a:sysClr":    "@val ...",
+	"a:prstClr":   "@val ...",
+
+	// Text
+	"a:txBody":     schemaTextBody,
+	"a:bodyPr":     "a:prstTxWarp? a:noAutofit|a:normAutofit|a:spAutoFit? a:scene3d? a:sp3d? a:flatTx? a:extLst?",
+	"a:lstStyle":   "a:defPPr? a:lvl1pPr? a:lvl2pPr? a:lvl3pPr? a:lvl4pPr? a:lvl5pPr? a:lvl6pPr? a:lvl7pPr? a:lvl8pPr? a:lvl9pPr? a:extLst?",
+	"a:p":          "a:pPr? %run* a:endParaRPr?",
+	"a:r":          "a:rPr? a:t",
+	"a:br":         "a:rPr?",
+	"a:fld":        "a:rPr? a:pPr? a:t? @id",
+	"a:pPr":        schemaTextParagraphProperties,
+	"a:defPPr":     schemaTextParagraphProperties,
+	"a:lvl1pPr":    schemaTextParagraphProperties,
+	"a:lvl2pPr":    schemaTextParagraphProperties,
+	"a:lvl3pPr":    schemaTextParagraphProperties,
+	"a:lvl4pPr":    schemaTextParagraphProperties,
+	"a:lvl5pPr":    schemaTextParagraphProperties,
+	"a:lvl6pPr":    schemaTextParagraphProperties,
+	"a:lvl7pPr":    schemaTextParagraphProperties,
+	"a:lvl8pPr":    schemaTextParagraphProperties,
+	"a:lvl9pPr":    schemaTextParagraphProperties,
+	"a:rPr":        schemaTextCharacterProperties,
+	"a:endParaRPr": schemaTextCharacterProperties,
+	"a:defRPr":     schemaTextCharacterProperties,
+	"a:lnSpc":      "a:spcPct|a:spcPts",
+	"a:spcBef":     "a:spcPct|a:spcPts",
+	"a:spcAft":     "a:spcPct|a:spcPts",
+	"a:buClr":      "%color",
+	"a:highlight":  "%color",
+	"a:uFill":      "%fill",
+	"a:tabLst":     "a:tab*",
+	"a:buChar":     "@char",
+	"a:buAutoNum":  "@type",
+
+	// Tables
+	"a:tbl":     "a:tblPr? a:tblGrid a:tr*",
+	"a:tblPr":   "%fill? %effect? a:tableStyle|a:tableStyleId? a:extLst?",
+	"a:tblGrid": "a:gridCol*",
+	"a:gridCol": "a:extLst? @w",
+	"a:tr":      "a:tc* a:extLst? @h",
+	"a:tc":      "a:txBody? a:tcPr? a:extLst?",
+	"a:tcPr":    "a:lnL? a:lnR? a:lnT? a:lnB? a:lnTlToBr? a:lnBlToTr? a:cell3D? %fill? a:headers? a:extLst?",
+}
+
+func init() {
+	schemaModels = make(map[string]*schemaModel, len(schemaSources))
+	for name, model := range schemaSources {
+		schemaModels[name] = compileSchemaModel(model, schemaGroups)
+	}
+}
@@ -0,0 +1,102 @@
+package gopresentation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetadataReport lists what ScrubMetadata removed, for audit logs.
+type MetadataReport struct {
+	Removed []RemovedMetadata
+}
+
+// RemovedMetadata is one value removed by ScrubMetadata.
+type RemovedMetadata struct {
+	// Field names the value, such as "creator", "custom property version"
+	// or "slide 2, Picture 3: description".
+	Field string
+	Value string
+}
+
+// String returns the report one removed value per line.
+func (r *MetadataReport) String() string {
+	var sb strings.Builder
+	for _, m := range r.Removed {
+		fmt.Fprintf(&sb, "%s: %q\n", m.Field, m.Value)
+	}
+	return sb.String()
+}
+
+// ScrubMetadata clears the document properties that identify people and
+// organisations (creator, last modified by, company and revision), removes
+// the custom properties, and clears shape descriptions holding file paths,
+// which PowerPoint fills in with the source path of inserted pictures.
+// Titles, subjects and other descriptive properties are kept; use Sanitize
+// to remove them too.
+func (p *Presentation) ScrubMetadata() *MetadataReport {
+	report := &MetadataReport{}
+	if props := p.properties; props != nil {
+		for _, f := range []struct {
+			name  string
+			value *string
+		}{
+			{"creator", &props.Creator},
+			{"last modified by", &props.LastModifiedBy},
+			{"company", &props.Company},
+			{"revision", &props.Revision},
+		} {
+			if *f.value != "" {
+				report.add(f.name, *f.value)
+				*f.value = ""
+			}
+		}
+		names := make([]string, 0, len(props.customProps))
+		for name := range props.customProps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			report.add("custom property "+name, fmt.Sprint(props.customProps[name].Value))
+			delete(props.customProps, name)
+		}
+	}
+	for i, slide := range p.slides {
+		report.scrubShapes(fmt.Sprintf("slide %d", i+1), slide.shapes)
+	}
+	return report
+}
+
+func (r *MetadataReport) add(field, value string) {
+	r.Removed = append(r.Removed, RemovedMetadata{Field: field, Value: value})
+}
+
+// scrubShapes clears the descriptions of shapes, and of the shapes nested
+// in groups, that hold file paths.
+func (r *MetadataReport) scrubShapes(prefix string, shapes []Shape) {
+	for _, shape := range shapes {
+		label := prefix + ", " + shapeDisplayName(shape)
+		if b := shape.base(); isFilePath(b.description) {
+			r.add(label+": description", b.description)
+			b.description = ""
+		}
+		if g, ok := shape.(*GroupShape); ok {
+			r.scrubShapes(label, g.shapes)
+		}
+	}
+}
+
+// isFilePath reports whether s is an absolute Windows, UNC or Unix file
+// path or a file URL.
+func isFilePath(s string) bool {
+	switch {
+	case len(s) >= 3 && s[1] == ':' && (s[2] == '\\' || s[2] == '/') &&
+		(s[0] >= 'A' && s[0] <= 'Z' || s[0] >= 'a' && s[0] <= 'z'):
+		return true
+	case strings.HasPrefix(s, `\\`), strings.HasPrefix(strings.ToLower(s), "file:"):
+		return true
+	case strings.HasPrefix(s, "/") && strings.Count(s, "/") > 1 && !strings.ContainsAny(s, "\n"):
+		return true
+	}
+	return false
+}
@@ -1,7 +1,9 @@
 package gopresentation
 
 import (
+	"encoding/xml"
 	"fmt"
+	"math"
 	"os"
 	"strings"
 )
@@ -15,6 +17,7 @@ type Shape interface {
 	GetHeight() int64
 	GetName() string
 	GetRotation() int
+	GetUserData(key string) any
 	// base returns the underlying BaseShape (unexported, internal use only).
 	base() *BaseShape
 }
@@ -46,6 +49,19 @@ type BaseShape struct {
 	border         *Border
 	shadow         *Shadow
 	hyperlink      *Hyperlink
+
+	// locks restricts what users may do with the shape in PowerPoint.
+	locks *ShapeLocks
+	// scene3d is the 3D camera and lighting of the shape (a:scene3d).
+	scene3d *Scene3D
+	// userData holds values attached by the caller; it is not written.
+	userData map[string]any
+	// tags holds the shape's tags by upper-case name.
+	tags map[string]string
+	// extLst holds the extension lists read with the shape.
+	extLst extLsts
+	// creationID is the shape's a16:creationId, or "" until one is needed.
+	creationID string
 }
 
 func (b *BaseShape) GetOffsetX() int64 { return b.offsetX }
@@ -95,8 +111,8 @@ func (b *BaseShape) SetFlipVertical(flip bool) *BaseShape {
 // GetFlipVertical returns whether the shape is flipped vertically.
 func (b *BaseShape) GetFlipVertical() bool { return b.flipVertical }
 
-func (b *BaseShape) GetDescription() string  { return b.description }
-func (b *BaseShape) SetDescription(d string) { b.description = d }
+func (b *BaseShape) GetDescription() string             { return b.description }
+func (b *BaseShape) SetDescription(d string) *BaseShape { b.description = d; return b }
 
 func (b *BaseShape) GetFill() *Fill {
 	if b.fill == nil {
@@ -105,7 +121,7 @@ func (b *BaseShape) GetFill() *Fill {
 	return b.fill
 }
 
-func (b *BaseShape) SetFill(f *Fill) { b.fill = f }
+func (b *BaseShape) SetFill(f *Fill) *BaseShape { b.fill = f; return b }
 
 func (b *BaseShape) GetBorder() *Border {
 	if b.border == nil {
@@ -114,7 +130,7 @@ func (b *BaseShape) GetBorder() *Border {
 	return b.border
 }
 
-func (b *BaseShape) SetBorder(border *Border) { b.border = border }
+func (b *BaseShape) SetBorder(border *Border) *BaseShape { b.border = border; return b }
 
 func (b *BaseShape) GetShadow() *Shadow {
 	if b.shadow == nil {
@@ -123,10 +139,185 @@ func (b *BaseShape) GetShadow() *Shadow {
 	return b.shadow
 }
 
-func (b *BaseShape) SetShadow(s *Shadow) { b.shadow = s }
+func (b *BaseShape) SetShadow(s *Shadow) *BaseShape { b.shadow = s; return b }
+
+func (b *BaseShape) GetHyperlink() *Hyperlink             { return b.hyperlink }
+func (b *BaseShape) SetHyperlink(h *Hyperlink) *BaseShape { b.hyperlink = h; return b }
+
+// SetUserData attaches value to the shape under key, such as data an
+// ElementHandler extracted from a vendor extension. User data stays in
+// memory only and is not written to the file. A nil value removes key.
+func (b *BaseShape) SetUserData(key string, value any) *BaseShape {
+	if value == nil {
+		delete(b.userData, key)
+		return b
+	}
+	if b.userData == nil {
+		b.userData = make(map[string]any)
+	}
+	b.userData[key] = value
+	return b
+}
+
+// GetUserData returns the value attached to the shape under key, or nil.
+func (b *BaseShape) GetUserData(key string) any { return b.userData[key] }
+
+// SetShapeUserData attaches value to s under key, as the SetUserData method
+// of each shape type does, for code that holds a Shape rather than a
+// concrete shape, such as an ElementHandler.
+func SetShapeUserData(s Shape, key string, value any) {
+	s.base().SetUserData(key, value)
+}
+
+// ShapeLocks holds the protection flags of a shape, written as the
+// a:spLocks, a:picLocks or a:cxnSpLocks element of its non-visual
+// properties, or the a:graphicFrameLocks element of tables and charts.
+// PowerPoint honours them in the editor; they do not affect rendering.
+type ShapeLocks struct {
+	NoMove         bool // the shape cannot be moved
+	NoResize       bool // the shape cannot be resized
+	NoSelect       bool // the shape cannot be selected
+	NoTextEdit     bool // the text cannot be edited (text shapes only)
+	NoRot          bool // the shape cannot be rotated (not tables and charts)
+	NoChangeAspect bool // resizing keeps the aspect ratio
+}
+
+// GetLocks returns the shape locks, or nil when the shape is unlocked.
+func (b *BaseShape) GetLocks() *ShapeLocks { return b.locks }
+
+// SetLocks sets the shape locks. Nil removes all locks.
+func (b *BaseShape) SetLocks(l *ShapeLocks) *BaseShape {
+	b.locks = l
+	return b
+}
+
+// hasAny reports whether any lock is set.
+func (l *ShapeLocks) hasAny() bool {
+	return l != nil && (l.NoMove || l.NoResize || l.NoSelect || l.NoTextEdit || l.NoRot || l.NoChangeAspect)
+}
+
+// xmlAttrs returns the lock attributes in schema order. noTextEdit is only
+// valid on a:spLocks and is omitted unless textEdit is true.
+func (l *ShapeLocks) xmlAttrs(textEdit bool) string {
+	if l == nil {
+		return ""
+	}
+	var sb strings.Builder
+	if l.NoSelect {
+		sb.WriteString(` noSelect="1"`)
+	}
+	if l.NoRot {
+		sb.WriteString(` noRot="1"`)
+	}
+	if l.NoChangeAspect {
+		sb.WriteString(` noChangeAspect="1"`)
+	}
+	if l.NoMove {
+		sb.WriteString(` noMove="1"`)
+	}
+	if l.NoResize {
+		sb.WriteString(` noResize="1"`)
+	}
+	if textEdit && l.NoTextEdit {
+		sb.WriteString(` noTextEdit="1"`)
+	}
+	return sb.String()
+}
+
+// graphicFrameXMLAttrs returns the lock attributes of an
+// a:graphicFrameLocks element, which has no rotation or text locks, in
+// schema order.
+func (l *ShapeLocks) graphicFrameXMLAttrs() string {
+	if l == nil {
+		return ""
+	}
+	var sb strings.Builder
+	if l.NoSelect {
+		sb.WriteString(` noSelect="1"`)
+	}
+	if l.NoChangeAspect {
+		sb.WriteString(` noChangeAspect="1"`)
+	}
+	if l.NoMove {
+		sb.WriteString(` noMove="1"`)
+	}
+	if l.NoResize {
+		sb.WriteString(` noResize="1"`)
+	}
+	return sb.String()
+}
+
+// parseShapeLocks reads the lock attributes of an a:spLocks, a:picLocks,
+// a:cxnSpLocks or a:graphicFrameLocks element. It returns nil when no
+// supported lock is set.
+func parseShapeLocks(attrs []xml.Attr) *ShapeLocks {
+	l := &ShapeLocks{}
+	for _, attr := range attrs {
+		on := attr.Value == "1" || attr.Value == "true"
+		switch attr.Name.Local {
+		case "noMove":
+			l.NoMove = on
+		case "noResize":
+			l.NoResize = on
+		case "noSelect":
+			l.NoSelect = on
+		case "noTextEdit":
+			l.NoTextEdit = on
+		case "noRot":
+			l.NoRot = on
+		case "noChangeAspect":
+			l.NoChangeAspect = on
+		}
+	}
+	if !l.hasAny() {
+		return nil
+	}
+	return l
+}
+
+// CameraPreset is a preset 3D camera of a Scene3D.
+type CameraPreset string
 
-func (b *BaseShape) GetHyperlink() *Hyperlink  { return b.hyperlink }
-func (b *BaseShape) SetHyperlink(h *Hyperlink) { b.hyperlink = h }
+const (
+	CameraOrthographicFront CameraPreset = "orthographicFront"
+	CameraPerspectiveFront  CameraPreset = "perspectiveFront"
+	CameraPerspectiveLeft   CameraPreset = "perspectiveLeft"
+	CameraPerspectiveRight  CameraPreset = "perspectiveRight"
+	CameraPerspectiveAbove  CameraPreset = "perspectiveAbove"
+	CameraPerspectiveBelow  CameraPreset = "perspectiveBelow"
+	CameraIsometricTopUp    CameraPreset = "isometricTopUp"
+	CameraIsometricLeftDown CameraPreset = "isometricLeftDown"
+	CameraIsometricRightUp  CameraPreset = "isometricRightUp"
+	CameraObliqueTopLeft    CameraPreset = "obliqueTopLeft"
+)
+
+// Scene3D is the 3D scene of a shape (a:scene3d): the camera it is viewed
+// through and the light rig. Lat, Lon and Rev rotate the camera about the
+// X, Y and Z axes in degrees; when all are zero the preset's own rotation is
+// used. The scene is preserved on save; the renderer draws shapes flat.
+type Scene3D struct {
+	Camera   CameraPreset
+	Lat      int
+	Lon      int
+	Rev      int
+	LightRig string // light rig preset, e.g. "threePt" (default)
+	LightDir string // light direction, e.g. "t" (default)
+}
+
+// NewScene3D creates a scene with the given camera and the default
+// three-point light rig.
+func NewScene3D(camera CameraPreset) *Scene3D {
+	return &Scene3D{Camera: camera, LightRig: "threePt", LightDir: "t"}
+}
+
+// GetScene3D returns the 3D scene of the shape, or nil.
+func (b *BaseShape) GetScene3D() *Scene3D { return b.scene3d }
+
+// SetScene3D sets the 3D scene of the shape. Nil removes it.
+func (b *BaseShape) SetScene3D(s *Scene3D) *BaseShape {
+	b.scene3d = s
+	return b
+}
 
 // CustomGeomPath represents a custom geometry path for freeform shapes.
 type CustomGeomPath struct {
@@ -171,6 +362,9 @@ type RichTextShape struct {
 	customPath  *CustomGeomPath // non-nil for freeform/custGeom shapes
 	headEnd     *LineEnd        // arrow at start of custom path (from <a:ln><a:headEnd>)
 	tailEnd     *LineEnd        // arrow at end of custom path (from <a:ln><a:tailEnd>)
+
+	// listStyle holds the lstStyle levels 1-9 (index 0 = lvl1pPr).
+	listStyle [9]*ListLevelStyle
 }
 
 // TextAnchorType represents the text anchoring type within a shape.
@@ -260,8 +454,9 @@ func (r *RichTextShape) CreateBreak() *BreakElement {
 }
 
 // SetAutoFit sets the auto-fit type.
-func (r *RichTextShape) SetAutoFit(fit AutoFitType) {
+func (r *RichTextShape) SetAutoFit(fit AutoFitType) *RichTextShape {
 	r.autoFit = fit
+	return r
 }
 
 // GetAutoFit returns the auto-fit type.
@@ -270,8 +465,9 @@ func (r *RichTextShape) GetAutoFit() AutoFitType {
 }
 
 // SetWordWrap sets word wrap.
-func (r *RichTextShape) SetWordWrap(wrap bool) {
+func (r *RichTextShape) SetWordWrap(wrap bool) *RichTextShape {
 	r.wordWrap = wrap
+	return r
 }
 
 // GetWordWrap returns word wrap setting.
@@ -280,8 +476,9 @@ func (r *RichTextShape) GetWordWrap() bool {
 }
 
 // SetColumns sets the number of text columns.
-func (r *RichTextShape) SetColumns(cols int) {
+func (r *RichTextShape) SetColumns(cols int) *RichTextShape {
 	r.columns = cols
+	return r
 }
 
 // GetColumns returns the number of text columns.
@@ -289,9 +486,27 @@ func (r *RichTextShape) GetColumns() int {
 	return r.columns
 }
 
+// SetListLevelStyle sets the list style for level (1-9). Paragraphs whose
+// alignment Level is level-1 inherit its bullet, indent and font.
+func (r *RichTextShape) SetListLevelStyle(level int, style *ListLevelStyle) *RichTextShape {
+	if level >= 1 && level <= len(r.listStyle) {
+		r.listStyle[level-1] = style
+	}
+	return r
+}
+
+// GetListLevelStyle returns the list style for level (1-9), or nil if none is defined.
+func (r *RichTextShape) GetListLevelStyle(level int) *ListLevelStyle {
+	if level < 1 || level > len(r.listStyle) {
+		return nil
+	}
+	return r.listStyle[level-1]
+}
+
 // SetTextAnchor sets the text anchoring type (vertical position of text within the shape).
-func (r *RichTextShape) SetTextAnchor(anchor TextAnchorType) {
+func (r *RichTextShape) SetTextAnchor(anchor TextAnchorType) *RichTextShape {
 	r.textAnchor = anchor
+	return r
 }
 
 // GetTextAnchor returns the text anchoring type.
@@ -304,6 +519,13 @@ func (r *RichTextShape) GetCustomPath() *CustomGeomPath {
 	return r.customPath
 }
 
+// SetCustomPath sets a custom geometry path, turning the shape into a
+// freeform. Nil restores the rectangle.
+func (r *RichTextShape) SetCustomPath(p *CustomGeomPath) *RichTextShape {
+	r.customPath = p
+	return r
+}
+
 // Paragraph represents a text paragraph.
 type Paragraph struct {
 	elements    []ParagraphElement
@@ -436,6 +658,9 @@ type DrawingShape struct {
 	cropTop    int
 	cropRight  int
 	cropBottom int
+	// missingMedia is the media part the picture was read with when the
+	// package did not contain it.
+	missingMedia string
 }
 
 func (d *DrawingShape) GetType() ShapeType { return ShapeTypeDrawing }
@@ -450,6 +675,7 @@ func NewDrawingShape() *DrawingShape {
 // SetPath sets the image file path.
 func (d *DrawingShape) SetPath(path string) *DrawingShape {
 	d.path = path
+	d.missingMedia = ""
 	return d
 }
 
@@ -460,12 +686,20 @@ func (d *DrawingShape) GetPath() string { return d.path }
 func (d *DrawingShape) SetImageData(data []byte, mimeType string) *DrawingShape {
 	d.data = data
 	d.mimeType = mimeType
+	d.missingMedia = ""
 	return d
 }
 
-// GetImageData returns the raw image data.
+// GetImageData returns the raw image data. Pictures read with
+// ReaderOptions.LowMemory share the data of their media part with the
+// other pictures using it, so it must not be modified in place.
 func (d *DrawingShape) GetImageData() []byte { return d.data }
 
+// GetMissingMedia returns the media part the picture references when it
+// was read from a package that does not contain it, with the
+// MissingMediaPlaceholder policy, or "". Setting an image clears it.
+func (d *DrawingShape) GetMissingMedia() string { return d.missingMedia }
+
 // GetMimeType returns the image MIME type.
 func (d *DrawingShape) GetMimeType() string { return d.mimeType }
 
@@ -489,6 +723,7 @@ func (d *DrawingShape) SetImageFromFile(path string) error {
 	mime := guessMimeFromPath(path)
 	d.data = data
 	d.mimeType = mime
+	d.missingMedia = ""
 	return nil
 }
 
@@ -547,9 +782,23 @@ func (d *DrawingShape) GetCropRight() int { return d.cropRight }
 // GetCropBottom returns the bottom crop percentage (in 1/1000 of a percent).
 func (d *DrawingShape) GetCropBottom() int { return d.cropBottom }
 
+// SetCrop sets the share of the image cut off at each edge, in 1/1000 of a
+// percent (e.g. 25000 = 25%).
+func (d *DrawingShape) SetCrop(left, top, right, bottom int) *DrawingShape {
+	d.cropLeft, d.cropTop, d.cropRight, d.cropBottom = left, top, right, bottom
+	return d
+}
+
 // GetAlphaValue returns the alphaModFix amount (0-100000).
 func (d *DrawingShape) GetAlphaValue() int { return d.alpha }
 
+// SetAlphaValue sets the opacity of the picture as an alphaModFix amount
+// (0-100000, e.g. 30000 = 30%). 0 and 100000 both mean fully opaque.
+func (d *DrawingShape) SetAlphaValue(amt int) *DrawingShape {
+	d.alpha = max(0, min(100000, amt))
+	return d
+}
+
 // AutoShape represents a predefined shape (rectangle, ellipse, etc.).
 type AutoShape struct {
 	BaseShape
@@ -558,6 +807,7 @@ type AutoShape struct {
 	paragraphs    []*Paragraph
 	textAnchor    TextAnchorType
 	textDirection string
+	wordWrap      bool
 	adjustValues  map[string]int // avLst adjustment values (e.g. "adj1" -> 10690)
 	fontScale     int            // normAutofit fontScale in thousandths of a percent (e.g. 62500 = 62.5%), 0 means 100%
 	// Text insets (padding) in EMU.
@@ -636,7 +886,9 @@ func (a *AutoShape) GetType() ShapeType { return ShapeTypeAutoShape }
 // NewAutoShape creates a new auto shape.
 func NewAutoShape() *AutoShape {
 	return &AutoShape{
-		shapeType: AutoShapeRectangle,
+		shapeType:  AutoShapeRectangle,
+		textAnchor: TextAnchorMiddle,
+		wordWrap:   true,
 	}
 }
 
@@ -664,14 +916,20 @@ func (a *AutoShape) SetSolidFill(c Color) *AutoShape {
 	return a
 }
 
-// SetText sets the text content.
+// SetText sets the text as a single centered line in the default font,
+// replacing the paragraphs. Use the paragraphs for formatted text.
 func (a *AutoShape) SetText(text string) *AutoShape {
 	a.text = text
+	a.paragraphs = nil
 	return a
 }
 
-// GetText returns the text content.
+// GetText returns the text content; that of paragraphs is joined by
+// newlines.
 func (a *AutoShape) GetText() string {
+	if a.text == "" {
+		return joinNonEmpty(extractParagraphsText(a.paragraphs), "\n")
+	}
 	return a.text
 }
 
@@ -680,6 +938,78 @@ func (a *AutoShape) GetParagraphs() []*Paragraph {
 	return a.paragraphs
 }
 
+// GetActiveParagraph returns the last paragraph, creating one if the shape
+// has none. Text set with SetText becomes its first, centered, run.
+func (a *AutoShape) GetActiveParagraph() *Paragraph {
+	if len(a.paragraphs) == 0 {
+		p := NewParagraph()
+		if a.text != "" {
+			p.GetAlignment().SetHorizontal(HorizontalCenter)
+			p.CreateTextRun(a.text)
+			a.text = ""
+		}
+		a.paragraphs = append(a.paragraphs, p)
+	}
+	return a.paragraphs[len(a.paragraphs)-1]
+}
+
+// CreateParagraph creates a new paragraph and makes it active.
+func (a *AutoShape) CreateParagraph() *Paragraph {
+	a.GetActiveParagraph()
+	p := NewParagraph()
+	a.paragraphs = append(a.paragraphs, p)
+	return p
+}
+
+// CreateTextRun creates a text run in the active paragraph.
+func (a *AutoShape) CreateTextRun(text string) *TextRun {
+	return a.GetActiveParagraph().CreateTextRun(text)
+}
+
+// CreateBreak creates a line break in the active paragraph.
+func (a *AutoShape) CreateBreak() *BreakElement {
+	return a.GetActiveParagraph().CreateBreak()
+}
+
+// SetWordWrap sets whether the text wraps at the shape's width.
+func (a *AutoShape) SetWordWrap(wrap bool) *AutoShape {
+	a.wordWrap = wrap
+	return a
+}
+
+// GetWordWrap returns whether the text wraps at the shape's width.
+func (a *AutoShape) GetWordWrap() bool {
+	return a.wordWrap
+}
+
+// SetTextAnchor sets the vertical anchoring of the text. Default: middle.
+func (a *AutoShape) SetTextAnchor(anchor TextAnchorType) *AutoShape {
+	a.textAnchor = anchor
+	return a
+}
+
+// GetTextAnchor returns the vertical anchoring of the text.
+func (a *AutoShape) GetTextAnchor() TextAnchorType {
+	return a.textAnchor
+}
+
+// SetInsets sets the distances in EMU between the shape's edges and its
+// text. Defaults: 91440 left and right, 45720 top and bottom.
+func (a *AutoShape) SetInsets(left, top, right, bottom int64) *AutoShape {
+	a.insetLeft, a.insetTop, a.insetRight, a.insetBottom = left, top, right, bottom
+	a.insetsSet = true
+	return a
+}
+
+// GetInsets returns the distances in EMU between the shape's edges and its
+// text.
+func (a *AutoShape) GetInsets() (left, top, right, bottom int64) {
+	if !a.insetsSet {
+		return 91440, 45720, 91440, 45720
+	}
+	return a.insetLeft, a.insetTop, a.insetRight, a.insetBottom
+}
+
 // GetAdjustValues returns the adjustment values map.
 // GetHeadEnd returns the head end arrow.
 func (a *AutoShape) GetHeadEnd() *LineEnd { return a.headEnd }
@@ -697,6 +1027,7 @@ type LineShape struct {
 	lineWidth     int
 	lineWidthEMU  int             // raw line width in EMU for precision; 0 means use lineWidth*12700
 	lineColor     Color
+	lineCap       LineCap // "" leaves the cap to the application (flat)
 	headEnd       *LineEnd
 	tailEnd       *LineEnd
 	connectorType string          // prstGeom value: "line", "straightConnector1", "bentConnector3", etc.
@@ -751,6 +1082,15 @@ func (l *LineShape) SetLineColor(c Color) *LineShape {
 // GetLineColor returns the line color.
 func (l *LineShape) GetLineColor() Color { return l.lineColor }
 
+// SetLineCap sets how the ends of the line are drawn.
+func (l *LineShape) SetLineCap(c LineCap) *LineShape {
+	l.lineCap = c
+	return l
+}
+
+// GetLineCap returns the line cap, or "" when none was set.
+func (l *LineShape) GetLineCap() LineCap { return l.lineCap }
+
 // SetHeadEnd sets the head end (arrow at start of line).
 func (l *LineShape) SetHeadEnd(e *LineEnd) *LineShape {
 	l.headEnd = e
@@ -775,6 +1115,52 @@ func (l *LineShape) GetConnectorType() string { return l.connectorType }
 // GetAdjustValues returns the adjustment values for connector geometry.
 func (l *LineShape) GetAdjustValues() map[string]int { return l.adjustValues }
 
+// SetEndpoints places the line from (x1, y1) to (x2, y2) in EMU. The offset
+// and extent are set to the box spanned by the two points and the flips
+// record its direction: OOXML lines run from the top-left to the
+// bottom-right corner of their box, so a line going left needs flipH and a
+// line going up needs flipV. The head end is drawn at (x1, y1) and the tail
+// end at (x2, y2). Rotation is reset to zero.
+func (l *LineShape) SetEndpoints(x1, y1, x2, y2 int64) *LineShape {
+	l.offsetX, l.offsetY = min(x1, x2), min(y1, y2)
+	l.width, l.height = x2-x1, y2-y1
+	if l.width < 0 {
+		l.width = -l.width
+	}
+	if l.height < 0 {
+		l.height = -l.height
+	}
+	l.flipHorizontal = x2 < x1
+	l.flipVertical = y2 < y1
+	l.rotation = 0
+	return l
+}
+
+// GetEndpoints returns the start and end points of the line in EMU, taking
+// flips and rotation into account.
+func (l *LineShape) GetEndpoints() (x1, y1, x2, y2 int64) {
+	fx1, fy1 := float64(l.offsetX), float64(l.offsetY)
+	fx2, fy2 := fx1+float64(l.width), fy1+float64(l.height)
+	if l.flipHorizontal {
+		fx1, fx2 = fx2, fx1
+	}
+	if l.flipVertical {
+		fy1, fy2 = fy2, fy1
+	}
+	if l.rotation != 0 {
+		cx := float64(l.offsetX) + float64(l.width)/2
+		cy := float64(l.offsetY) + float64(l.height)/2
+		sin, cos := math.Sincos(float64(l.rotation) * math.Pi / 180)
+		rotate := func(x, y float64) (float64, float64) {
+			dx, dy := x-cx, y-cy
+			return cx + dx*cos - dy*sin, cy + dx*sin + dy*cos
+		}
+		fx1, fy1 = rotate(fx1, fy1)
+		fx2, fy2 = rotate(fx2, fy2)
+	}
+	return int64(math.Round(fx1)), int64(math.Round(fy1)), int64(math.Round(fx2)), int64(math.Round(fy2))
+}
+
 // TableShape represents a table shape.
 type TableShape struct {
 	BaseShape
@@ -783,6 +1169,10 @@ type TableShape struct {
 	numCols    int
 	colWidths  []int64 // individual column widths in EMU (from gridCol)
 	rowHeights []int64 // individual row heights in EMU (from tr)
+	// tblPr style flags
+	firstRow bool
+	lastRow  bool
+	bandRow  bool
 }
 
 func (t *TableShape) GetType() ShapeType { return ShapeTypeTable }
@@ -790,9 +1180,11 @@ func (t *TableShape) GetType() ShapeType { return ShapeTypeTable }
 // NewTableShape creates a new table shape.
 func NewTableShape(rows, cols int) *TableShape {
 	table := &TableShape{
-		numRows: rows,
-		numCols: cols,
-		rows:    make([][]*TableCell, rows),
+		numRows:  rows,
+		numCols:  cols,
+		rows:     make([][]*TableCell, rows),
+		firstRow: true,
+		bandRow:  true,
 	}
 	for i := 0; i < rows; i++ {
 		table.rows[i] = make([]*TableCell, cols)
@@ -822,6 +1214,33 @@ func (t *TableShape) GetNumRows() int { return t.numRows }
 // GetNumCols returns the number of columns.
 func (t *TableShape) GetNumCols() int { return t.numCols }
 
+// SetFirstRow marks the first row as a header row for the table style.
+func (t *TableShape) SetFirstRow(v bool) *TableShape {
+	t.firstRow = v
+	return t
+}
+
+// IsFirstRow returns whether the first row is a header row.
+func (t *TableShape) IsFirstRow() bool { return t.firstRow }
+
+// SetLastRow marks the last row as a total row for the table style.
+func (t *TableShape) SetLastRow(v bool) *TableShape {
+	t.lastRow = v
+	return t
+}
+
+// IsLastRow returns whether the last row is a total row.
+func (t *TableShape) IsLastRow() bool { return t.lastRow }
+
+// SetBandRow enables banded (alternating) row formatting.
+func (t *TableShape) SetBandRow(v bool) *TableShape {
+	t.bandRow = v
+	return t
+}
+
+// IsBandRow returns whether banded row formatting is enabled.
+func (t *TableShape) IsBandRow() bool { return t.bandRow }
+
 // SetHeight sets the height and returns for chaining.
 func (t *TableShape) SetHeight(h int64) *TableShape {
 	t.height = h
@@ -843,6 +1262,16 @@ type TableCell struct {
 	rowSpan    int
 	hMerge     bool // continuation of horizontal merge (skip rendering)
 	vMerge     bool // continuation of vertical merge (skip rendering)
+	// textDirection is the tcPr vert value ("horz", "vert", "vert270", "eaVert", etc.).
+	textDirection string
+	// Margins between the cell edges and its text in EMU, the tcPr marL,
+	// marR, marT and marB values, when marginsSet.
+	marginLeft   int64
+	marginRight  int64
+	marginTop    int64
+	marginBottom int64
+	marginsSet   bool
+	anchor       TextAnchorType
 }
 
 // CellBorders represents borders for a table cell.
@@ -878,6 +1307,12 @@ func (tc *TableCell) SetText(text string) *TableCell {
 	return tc
 }
 
+// SetNumber sets the cell text to v formatted with an Excel number format
+// code (see FormatNumber).
+func (tc *TableCell) SetNumber(v float64, code string) *TableCell {
+	return tc.SetText(FormatNumber(v, code))
+}
+
 // GetParagraphs returns the cell paragraphs.
 func (tc *TableCell) GetParagraphs() []*Paragraph {
 	return tc.paragraphs
@@ -903,3 +1338,59 @@ func (tc *TableCell) SetRowSpan(span int) { tc.rowSpan = span }
 
 // GetRowSpan returns the row span.
 func (tc *TableCell) GetRowSpan() int { return tc.rowSpan }
+
+// SetTextDirection sets the cell text direction ("horz", "vert", "vert270",
+// "eaVert", ...). Use "vert270" for bottom-to-top header cells.
+func (tc *TableCell) SetTextDirection(dir string) *TableCell {
+	tc.textDirection = dir
+	return tc
+}
+
+// GetTextDirection returns the cell text direction, or "" for the default.
+func (tc *TableCell) GetTextDirection() string { return tc.textDirection }
+
+// SetMargins sets the distances in EMU between the cell edges and its text.
+func (tc *TableCell) SetMargins(left, top, right, bottom int64) *TableCell {
+	tc.marginLeft, tc.marginTop, tc.marginRight, tc.marginBottom = left, top, right, bottom
+	tc.marginsSet = true
+	return tc
+}
+
+// GetMargins returns the distances in EMU between the cell edges and its
+// text. Defaults: 91440 left and right, 45720 top and bottom.
+func (tc *TableCell) GetMargins() (left, top, right, bottom int64) {
+	if !tc.marginsSet {
+		return 91440, 45720, 91440, 45720
+	}
+	return tc.marginLeft, tc.marginTop, tc.marginRight, tc.marginBottom
+}
+
+// SetTextAnchor sets the vertical anchoring of the cell text. Default: top.
+func (tc *TableCell) SetTextAnchor(anchor TextAnchorType) *TableCell {
+	tc.anchor = anchor
+	return tc
+}
+
+// GetTextAnchor returns the vertical anchoring of the cell text.
+func (tc *TableCell) GetTextAnchor() TextAnchorType { return tc.anchor }
+
+// SetHyperlink links every text run of the cell to h; nil removes the
+// links.
+func (tc *TableCell) SetHyperlink(h *Hyperlink) *TableCell {
+	for _, para := range tc.paragraphs {
+		for _, elem := range para.elements {
+			if tr, ok := elem.(*TextRun); ok {
+				tr.hyperlink = h
+			}
+		}
+	}
+	return tc
+}
+
+// SetImage fills the cell with a picture, stretched to the cell behind its
+// text, as PowerPoint places pictures in table cells. Use
+// GetFill().SetTile to repeat a small picture instead.
+func (tc *TableCell) SetImage(data []byte, mimeType string) *TableCell {
+	tc.fill = NewFill().SetPicture(data, mimeType)
+	return tc
+}
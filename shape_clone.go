@@ -0,0 +1,362 @@
+package gopresentation
+
+import (
+	"maps"
+	"slices"
+)
+
+// clone returns a deep copy of the common shape properties. The copy has
+// no creation ID, so that it gets one of its own when written; picture
+// fill data is shared, as it is never changed in place.
+func (b *BaseShape) clone() BaseShape {
+	c := *b
+	c.creationID = ""
+	c.fill = cloneFill(b.fill)
+	c.border = cloneBorder(b.border)
+	if b.shadow != nil {
+		shadow := *b.shadow
+		shadow.Color = *cloneColor(&b.shadow.Color)
+		c.shadow = &shadow
+	}
+	if b.hyperlink != nil {
+		h := *b.hyperlink
+		c.hyperlink = &h
+	}
+	if b.locks != nil {
+		locks := *b.locks
+		c.locks = &locks
+	}
+	if b.scene3d != nil {
+		scene := *b.scene3d
+		c.scene3d = &scene
+	}
+	c.userData = maps.Clone(b.userData)
+	c.tags = maps.Clone(b.tags)
+	return c
+}
+
+// clone returns a deep copy of the text shape, paragraphs included.
+func (r *RichTextShape) clone() *RichTextShape {
+	c := *r
+	c.BaseShape = r.BaseShape.clone()
+	c.paragraphs = cloneParagraphs(r.paragraphs)
+	c.customPath = cloneCustomPath(r.customPath)
+	c.headEnd = cloneLineEnd(r.headEnd)
+	c.tailEnd = cloneLineEnd(r.tailEnd)
+	for i, l := range r.listStyle {
+		if l == nil {
+			continue
+		}
+		level := &ListLevelStyle{Font: cloneFont(l.Font)}
+		if l.Alignment != nil {
+			a := *l.Alignment
+			level.Alignment = &a
+		}
+		if l.Bullet != nil {
+			b := *l.Bullet
+			if b.Color != nil {
+				b.Color = cloneColor(b.Color)
+			}
+			level.Bullet = &b
+		}
+		c.listStyle[i] = level
+	}
+	return &c
+}
+
+// clone returns a deep copy of the placeholder.
+func (p *PlaceholderShape) clone() *PlaceholderShape {
+	return &PlaceholderShape{RichTextShape: *p.RichTextShape.clone(), phType: p.phType, phIdx: p.phIdx}
+}
+
+// clone returns a deep copy of the auto shape, paragraphs included.
+func (a *AutoShape) clone() *AutoShape {
+	c := *a
+	c.BaseShape = a.BaseShape.clone()
+	c.paragraphs = cloneParagraphs(a.paragraphs)
+	c.adjustValues = maps.Clone(a.adjustValues)
+	c.headEnd = cloneLineEnd(a.headEnd)
+	c.tailEnd = cloneLineEnd(a.tailEnd)
+	return &c
+}
+
+// clone returns a copy of the picture. The image data is shared, as it is
+// never changed in place.
+func (d *DrawingShape) clone() *DrawingShape {
+	c := *d
+	c.BaseShape = d.BaseShape.clone()
+	return &c
+}
+
+// clone returns a deep copy of the line.
+func (l *LineShape) clone() *LineShape {
+	c := *l
+	c.BaseShape = l.BaseShape.clone()
+	c.lineColor = *cloneColor(&l.lineColor)
+	c.headEnd = cloneLineEnd(l.headEnd)
+	c.tailEnd = cloneLineEnd(l.tailEnd)
+	c.adjustValues = maps.Clone(l.adjustValues)
+	c.customPath = cloneCustomPath(l.customPath)
+	return &c
+}
+
+// clone returns a deep copy of the table, cells included.
+func (t *TableShape) clone() *TableShape {
+	c := *t
+	c.BaseShape = t.BaseShape.clone()
+	c.rows = make([][]*TableCell, len(t.rows))
+	for i, row := range t.rows {
+		c.rows[i] = make([]*TableCell, len(row))
+		for j, cell := range row {
+			c.rows[i][j] = cell.clone()
+		}
+	}
+	c.colWidths = slices.Clone(t.colWidths)
+	c.rowHeights = slices.Clone(t.rowHeights)
+	return &c
+}
+
+// clone returns a deep copy of the cell, paragraphs included.
+func (tc *TableCell) clone() *TableCell {
+	c := *tc
+	c.paragraphs = cloneParagraphs(tc.paragraphs)
+	c.fill = cloneFill(tc.fill)
+	if tc.border != nil {
+		c.border = &CellBorders{
+			Top:    cloneBorder(tc.border.Top),
+			Bottom: cloneBorder(tc.border.Bottom),
+			Left:   cloneBorder(tc.border.Left),
+			Right:  cloneBorder(tc.border.Right),
+		}
+	}
+	return &c
+}
+
+// clone returns a deep copy of the group and the shapes in it.
+func (g *GroupShape) clone() *GroupShape {
+	c := *g
+	c.BaseShape = g.BaseShape.clone()
+	c.shapes = make([]Shape, len(g.shapes))
+	for i, s := range g.shapes {
+		c.shapes[i] = cloneShape(s)
+	}
+	c.groupFill = cloneFill(g.groupFill)
+	return &c
+}
+
+// clone returns a deep copy of the chart, series data included.
+func (cs *ChartShape) clone() *ChartShape {
+	c := *cs
+	c.BaseShape = cs.BaseShape.clone()
+	if cs.title != nil {
+		title := *cs.title
+		title.Font = cloneFont(cs.title.Font)
+		title.paragraphs = cloneParagraphs(cs.title.paragraphs)
+		c.title = &title
+	}
+	if cs.legend != nil {
+		legend := *cs.legend
+		legend.Font = cloneFont(cs.legend.Font)
+		if cs.legend.entryFonts != nil {
+			legend.entryFonts = make(map[int]*Font, len(cs.legend.entryFonts))
+			for idx, f := range cs.legend.entryFonts {
+				legend.entryFonts[idx] = cloneFont(f)
+			}
+		}
+		c.legend = &legend
+	}
+	if cs.plotArea != nil {
+		c.plotArea = &PlotArea{
+			chartType: cloneChartType(cs.plotArea.chartType),
+			axisX:     cloneChartAxis(cs.plotArea.axisX),
+			axisY:     cloneChartAxis(cs.plotArea.axisY),
+		}
+	}
+	if cs.view3D != nil {
+		view := *cs.view3D
+		if cs.view3D.HeightPercent != nil {
+			hp := *cs.view3D.HeightPercent
+			view.HeightPercent = &hp
+		}
+		c.view3D = &view
+	}
+	return &c
+}
+
+// cloneShape returns a deep copy of s that gets a creation ID of its own
+// when written. Shapes of types defined outside the package are returned
+// as they are.
+func cloneShape(s Shape) Shape {
+	switch s := s.(type) {
+	case *RichTextShape:
+		return s.clone()
+	case *PlaceholderShape:
+		return s.clone()
+	case *AutoShape:
+		return s.clone()
+	case *DrawingShape:
+		return s.clone()
+	case *LineShape:
+		return s.clone()
+	case *TableShape:
+		return s.clone()
+	case *GroupShape:
+		return s.clone()
+	case *ChartShape:
+		return s.clone()
+	}
+	return s
+}
+
+// cloneChartType returns a deep copy of the chart type and its series.
+func cloneChartType(ct ChartType) ChartType {
+	switch t := ct.(type) {
+	case *BarChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *Bar3DChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *LineChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *AreaChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *PieChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *Pie3DChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *DoughnutChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *ScatterChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *RadarChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	case *WaterfallChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		c.Subtotals = slices.Clone(t.Subtotals)
+		return &c
+	case *FunnelChart:
+		c := *t
+		c.Series = cloneChartSeries(t.Series)
+		return &c
+	}
+	return ct
+}
+
+// cloneChartSeries returns deep copies of the series.
+func cloneChartSeries(series []*ChartSeries) []*ChartSeries {
+	if series == nil {
+		return nil
+	}
+	c := make([]*ChartSeries, len(series))
+	for i, s := range series {
+		cs := *s
+		cs.Values = maps.Clone(s.Values)
+		cs.Categories = slices.Clone(s.Categories)
+		cs.FillColor = *cloneColor(&s.FillColor)
+		cs.Font = cloneFont(s.Font)
+		if s.Outline != nil {
+			outline := *s.Outline
+			cs.Outline = &outline
+		}
+		if s.Marker != nil {
+			marker := *s.Marker
+			cs.Marker = &marker
+		}
+		if s.Smooth != nil {
+			smooth := *s.Smooth
+			cs.Smooth = &smooth
+		}
+		c[i] = &cs
+	}
+	return c
+}
+
+// cloneChartAxis returns a deep copy of a, or nil.
+func cloneChartAxis(a *ChartAxis) *ChartAxis {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	for _, b := range []**float64{&c.MinBounds, &c.MaxBounds, &c.MinorUnit, &c.MajorUnit} {
+		if *b != nil {
+			v := **b
+			*b = &v
+		}
+	}
+	c.Font = cloneFont(a.Font)
+	if a.MajorGridlines != nil {
+		g := *a.MajorGridlines
+		c.MajorGridlines = &g
+	}
+	if a.MinorGridlines != nil {
+		g := *a.MinorGridlines
+		c.MinorGridlines = &g
+	}
+	return &c
+}
+
+// cloneBorder returns a copy of b, or nil.
+func cloneBorder(b *Border) *Border {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	c.Color = *cloneColor(&b.Color)
+	return &c
+}
+
+// cloneLineEnd returns a copy of e, or nil.
+func cloneLineEnd(e *LineEnd) *LineEnd {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	return &c
+}
+
+// cloneCustomPath returns a deep copy of p, or nil.
+func cloneCustomPath(p *CustomGeomPath) *CustomGeomPath {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	c.Commands = make([]PathCommand, len(p.Commands))
+	for i, cmd := range p.Commands {
+		cmd.Pts = append([]PathPoint(nil), cmd.Pts...)
+		c.Commands[i] = cmd
+	}
+	return &c
+}
+
+// cloneFill returns a copy of f, or nil.
+func cloneFill(f *Fill) *Fill {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	c.Color = *cloneColor(&f.Color)
+	c.EndColor = *cloneColor(&f.EndColor)
+	if f.Tile != nil {
+		tile := *f.Tile
+		c.Tile = &tile
+	}
+	return &c
+}
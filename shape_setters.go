@@ -0,0 +1,857 @@
+package gopresentation
+
+// The setters of BaseShape return the BaseShape, which ends a chain of calls
+// on a concrete shape. Each shape type therefore has its own versions that
+// return the shape itself; the BaseShape versions stay reachable through
+// the embedded field, as in shape.BaseShape.SetName.
+
+// Chainable setters of RichTextShape.
+
+func (r *RichTextShape) SetPosition(x, y int64) *RichTextShape {
+	r.BaseShape.SetPosition(x, y)
+	return r
+}
+
+func (r *RichTextShape) SetSize(w, h int64) *RichTextShape {
+	r.BaseShape.SetSize(w, h)
+	return r
+}
+
+func (r *RichTextShape) SetName(name string) *RichTextShape {
+	r.BaseShape.SetName(name)
+	return r
+}
+
+func (r *RichTextShape) SetDescription(desc string) *RichTextShape {
+	r.BaseShape.SetDescription(desc)
+	return r
+}
+
+func (r *RichTextShape) SetRotation(degrees int) *RichTextShape {
+	r.BaseShape.SetRotation(degrees)
+	return r
+}
+
+func (r *RichTextShape) SetFlipHorizontal(flip bool) *RichTextShape {
+	r.BaseShape.SetFlipHorizontal(flip)
+	return r
+}
+
+func (r *RichTextShape) SetFlipVertical(flip bool) *RichTextShape {
+	r.BaseShape.SetFlipVertical(flip)
+	return r
+}
+
+func (r *RichTextShape) SetFill(fill *Fill) *RichTextShape {
+	r.BaseShape.SetFill(fill)
+	return r
+}
+
+func (r *RichTextShape) SetBorder(border *Border) *RichTextShape {
+	r.BaseShape.SetBorder(border)
+	return r
+}
+
+func (r *RichTextShape) SetShadow(shadow *Shadow) *RichTextShape {
+	r.BaseShape.SetShadow(shadow)
+	return r
+}
+
+func (r *RichTextShape) SetHyperlink(link *Hyperlink) *RichTextShape {
+	r.BaseShape.SetHyperlink(link)
+	return r
+}
+
+func (r *RichTextShape) SetLocks(locks *ShapeLocks) *RichTextShape {
+	r.BaseShape.SetLocks(locks)
+	return r
+}
+
+func (r *RichTextShape) SetScene3D(scene *Scene3D) *RichTextShape {
+	r.BaseShape.SetScene3D(scene)
+	return r
+}
+
+func (r *RichTextShape) SetTag(name, value string) *RichTextShape {
+	r.BaseShape.SetTag(name, value)
+	return r
+}
+
+func (r *RichTextShape) RemoveTag(name string) *RichTextShape {
+	r.BaseShape.RemoveTag(name)
+	return r
+}
+
+func (r *RichTextShape) SetUserData(key string, value any) *RichTextShape {
+	r.BaseShape.SetUserData(key, value)
+	return r
+}
+
+func (r *RichTextShape) ApplyStyle(style ShapeStyle) *RichTextShape {
+	r.BaseShape.ApplyStyle(style)
+	return r
+}
+
+// Chainable setters of PlaceholderShape.
+
+func (p *PlaceholderShape) SetOffsetX(x int64) *PlaceholderShape {
+	p.BaseShape.SetOffsetX(x)
+	return p
+}
+
+func (p *PlaceholderShape) SetOffsetY(y int64) *PlaceholderShape {
+	p.BaseShape.SetOffsetY(y)
+	return p
+}
+
+func (p *PlaceholderShape) SetWidth(w int64) *PlaceholderShape {
+	p.BaseShape.SetWidth(w)
+	return p
+}
+
+func (p *PlaceholderShape) SetHeight(h int64) *PlaceholderShape {
+	p.BaseShape.SetHeight(h)
+	return p
+}
+
+func (p *PlaceholderShape) SetPosition(x, y int64) *PlaceholderShape {
+	p.BaseShape.SetPosition(x, y)
+	return p
+}
+
+func (p *PlaceholderShape) SetSize(w, h int64) *PlaceholderShape {
+	p.BaseShape.SetSize(w, h)
+	return p
+}
+
+func (p *PlaceholderShape) SetName(name string) *PlaceholderShape {
+	p.BaseShape.SetName(name)
+	return p
+}
+
+func (p *PlaceholderShape) SetDescription(desc string) *PlaceholderShape {
+	p.BaseShape.SetDescription(desc)
+	return p
+}
+
+func (p *PlaceholderShape) SetRotation(degrees int) *PlaceholderShape {
+	p.BaseShape.SetRotation(degrees)
+	return p
+}
+
+func (p *PlaceholderShape) SetFlipHorizontal(flip bool) *PlaceholderShape {
+	p.BaseShape.SetFlipHorizontal(flip)
+	return p
+}
+
+func (p *PlaceholderShape) SetFlipVertical(flip bool) *PlaceholderShape {
+	p.BaseShape.SetFlipVertical(flip)
+	return p
+}
+
+func (p *PlaceholderShape) SetFill(fill *Fill) *PlaceholderShape {
+	p.BaseShape.SetFill(fill)
+	return p
+}
+
+func (p *PlaceholderShape) SetBorder(border *Border) *PlaceholderShape {
+	p.BaseShape.SetBorder(border)
+	return p
+}
+
+func (p *PlaceholderShape) SetShadow(shadow *Shadow) *PlaceholderShape {
+	p.BaseShape.SetShadow(shadow)
+	return p
+}
+
+func (p *PlaceholderShape) SetHyperlink(link *Hyperlink) *PlaceholderShape {
+	p.BaseShape.SetHyperlink(link)
+	return p
+}
+
+func (p *PlaceholderShape) SetLocks(locks *ShapeLocks) *PlaceholderShape {
+	p.BaseShape.SetLocks(locks)
+	return p
+}
+
+func (p *PlaceholderShape) SetScene3D(scene *Scene3D) *PlaceholderShape {
+	p.BaseShape.SetScene3D(scene)
+	return p
+}
+
+func (p *PlaceholderShape) SetTag(name, value string) *PlaceholderShape {
+	p.BaseShape.SetTag(name, value)
+	return p
+}
+
+func (p *PlaceholderShape) RemoveTag(name string) *PlaceholderShape {
+	p.BaseShape.RemoveTag(name)
+	return p
+}
+
+func (p *PlaceholderShape) SetUserData(key string, value any) *PlaceholderShape {
+	p.BaseShape.SetUserData(key, value)
+	return p
+}
+
+func (p *PlaceholderShape) ApplyStyle(style ShapeStyle) *PlaceholderShape {
+	p.BaseShape.ApplyStyle(style)
+	return p
+}
+
+func (p *PlaceholderShape) SetAutoFit(fit AutoFitType) *PlaceholderShape {
+	p.RichTextShape.SetAutoFit(fit)
+	return p
+}
+
+func (p *PlaceholderShape) SetWordWrap(wrap bool) *PlaceholderShape {
+	p.RichTextShape.SetWordWrap(wrap)
+	return p
+}
+
+func (p *PlaceholderShape) SetColumns(cols int) *PlaceholderShape {
+	p.RichTextShape.SetColumns(cols)
+	return p
+}
+
+func (p *PlaceholderShape) SetTextAnchor(anchor TextAnchorType) *PlaceholderShape {
+	p.RichTextShape.SetTextAnchor(anchor)
+	return p
+}
+
+func (p *PlaceholderShape) SetListLevelStyle(level int, style *ListLevelStyle) *PlaceholderShape {
+	p.RichTextShape.SetListLevelStyle(level, style)
+	return p
+}
+
+func (p *PlaceholderShape) SetCustomPath(path *CustomGeomPath) *PlaceholderShape {
+	p.RichTextShape.SetCustomPath(path)
+	return p
+}
+
+func (p *PlaceholderShape) ApplyTextStyle(style TextStyle) *PlaceholderShape {
+	p.RichTextShape.ApplyTextStyle(style)
+	return p
+}
+
+func (p *PlaceholderShape) SetHTML(html string) *PlaceholderShape {
+	p.RichTextShape.SetHTML(html)
+	return p
+}
+
+func (p *PlaceholderShape) SetMarkdown(markdown string) *PlaceholderShape {
+	p.RichTextShape.SetMarkdown(markdown)
+	return p
+}
+
+// Chainable setters of DrawingShape.
+
+func (d *DrawingShape) SetPosition(x, y int64) *DrawingShape {
+	d.BaseShape.SetPosition(x, y)
+	return d
+}
+
+func (d *DrawingShape) SetSize(w, h int64) *DrawingShape {
+	d.BaseShape.SetSize(w, h)
+	return d
+}
+
+func (d *DrawingShape) SetName(name string) *DrawingShape {
+	d.BaseShape.SetName(name)
+	return d
+}
+
+func (d *DrawingShape) SetDescription(desc string) *DrawingShape {
+	d.BaseShape.SetDescription(desc)
+	return d
+}
+
+func (d *DrawingShape) SetRotation(degrees int) *DrawingShape {
+	d.BaseShape.SetRotation(degrees)
+	return d
+}
+
+func (d *DrawingShape) SetFlipHorizontal(flip bool) *DrawingShape {
+	d.BaseShape.SetFlipHorizontal(flip)
+	return d
+}
+
+func (d *DrawingShape) SetFlipVertical(flip bool) *DrawingShape {
+	d.BaseShape.SetFlipVertical(flip)
+	return d
+}
+
+func (d *DrawingShape) SetFill(fill *Fill) *DrawingShape {
+	d.BaseShape.SetFill(fill)
+	return d
+}
+
+func (d *DrawingShape) SetBorder(border *Border) *DrawingShape {
+	d.BaseShape.SetBorder(border)
+	return d
+}
+
+func (d *DrawingShape) SetShadow(shadow *Shadow) *DrawingShape {
+	d.BaseShape.SetShadow(shadow)
+	return d
+}
+
+func (d *DrawingShape) SetHyperlink(link *Hyperlink) *DrawingShape {
+	d.BaseShape.SetHyperlink(link)
+	return d
+}
+
+func (d *DrawingShape) SetLocks(locks *ShapeLocks) *DrawingShape {
+	d.BaseShape.SetLocks(locks)
+	return d
+}
+
+func (d *DrawingShape) SetScene3D(scene *Scene3D) *DrawingShape {
+	d.BaseShape.SetScene3D(scene)
+	return d
+}
+
+func (d *DrawingShape) SetTag(name, value string) *DrawingShape {
+	d.BaseShape.SetTag(name, value)
+	return d
+}
+
+func (d *DrawingShape) RemoveTag(name string) *DrawingShape {
+	d.BaseShape.RemoveTag(name)
+	return d
+}
+
+func (d *DrawingShape) SetUserData(key string, value any) *DrawingShape {
+	d.BaseShape.SetUserData(key, value)
+	return d
+}
+
+func (d *DrawingShape) ApplyStyle(style ShapeStyle) *DrawingShape {
+	d.BaseShape.ApplyStyle(style)
+	return d
+}
+
+// Chainable setters of AutoShape.
+
+func (a *AutoShape) SetOffsetX(x int64) *AutoShape {
+	a.BaseShape.SetOffsetX(x)
+	return a
+}
+
+func (a *AutoShape) SetOffsetY(y int64) *AutoShape {
+	a.BaseShape.SetOffsetY(y)
+	return a
+}
+
+func (a *AutoShape) SetWidth(w int64) *AutoShape {
+	a.BaseShape.SetWidth(w)
+	return a
+}
+
+func (a *AutoShape) SetHeight(h int64) *AutoShape {
+	a.BaseShape.SetHeight(h)
+	return a
+}
+
+func (a *AutoShape) SetPosition(x, y int64) *AutoShape {
+	a.BaseShape.SetPosition(x, y)
+	return a
+}
+
+func (a *AutoShape) SetSize(w, h int64) *AutoShape {
+	a.BaseShape.SetSize(w, h)
+	return a
+}
+
+func (a *AutoShape) SetName(name string) *AutoShape {
+	a.BaseShape.SetName(name)
+	return a
+}
+
+func (a *AutoShape) SetDescription(desc string) *AutoShape {
+	a.BaseShape.SetDescription(desc)
+	return a
+}
+
+func (a *AutoShape) SetRotation(degrees int) *AutoShape {
+	a.BaseShape.SetRotation(degrees)
+	return a
+}
+
+func (a *AutoShape) SetFlipHorizontal(flip bool) *AutoShape {
+	a.BaseShape.SetFlipHorizontal(flip)
+	return a
+}
+
+func (a *AutoShape) SetFlipVertical(flip bool) *AutoShape {
+	a.BaseShape.SetFlipVertical(flip)
+	return a
+}
+
+func (a *AutoShape) SetFill(fill *Fill) *AutoShape {
+	a.BaseShape.SetFill(fill)
+	return a
+}
+
+func (a *AutoShape) SetBorder(border *Border) *AutoShape {
+	a.BaseShape.SetBorder(border)
+	return a
+}
+
+func (a *AutoShape) SetShadow(shadow *Shadow) *AutoShape {
+	a.BaseShape.SetShadow(shadow)
+	return a
+}
+
+func (a *AutoShape) SetHyperlink(link *Hyperlink) *AutoShape {
+	a.BaseShape.SetHyperlink(link)
+	return a
+}
+
+func (a *AutoShape) SetLocks(locks *ShapeLocks) *AutoShape {
+	a.BaseShape.SetLocks(locks)
+	return a
+}
+
+func (a *AutoShape) SetScene3D(scene *Scene3D) *AutoShape {
+	a.BaseShape.SetScene3D(scene)
+	return a
+}
+
+func (a *AutoShape) SetTag(name, value string) *AutoShape {
+	a.BaseShape.SetTag(name, value)
+	return a
+}
+
+func (a *AutoShape) RemoveTag(name string) *AutoShape {
+	a.BaseShape.RemoveTag(name)
+	return a
+}
+
+func (a *AutoShape) SetUserData(key string, value any) *AutoShape {
+	a.BaseShape.SetUserData(key, value)
+	return a
+}
+
+func (a *AutoShape) ApplyStyle(style ShapeStyle) *AutoShape {
+	a.BaseShape.ApplyStyle(style)
+	return a
+}
+
+// Chainable setters of LineShape.
+
+func (l *LineShape) SetOffsetX(x int64) *LineShape {
+	l.BaseShape.SetOffsetX(x)
+	return l
+}
+
+func (l *LineShape) SetOffsetY(y int64) *LineShape {
+	l.BaseShape.SetOffsetY(y)
+	return l
+}
+
+func (l *LineShape) SetWidth(w int64) *LineShape {
+	l.BaseShape.SetWidth(w)
+	return l
+}
+
+func (l *LineShape) SetHeight(h int64) *LineShape {
+	l.BaseShape.SetHeight(h)
+	return l
+}
+
+func (l *LineShape) SetPosition(x, y int64) *LineShape {
+	l.BaseShape.SetPosition(x, y)
+	return l
+}
+
+func (l *LineShape) SetSize(w, h int64) *LineShape {
+	l.BaseShape.SetSize(w, h)
+	return l
+}
+
+func (l *LineShape) SetName(name string) *LineShape {
+	l.BaseShape.SetName(name)
+	return l
+}
+
+func (l *LineShape) SetDescription(desc string) *LineShape {
+	l.BaseShape.SetDescription(desc)
+	return l
+}
+
+func (l *LineShape) SetRotation(degrees int) *LineShape {
+	l.BaseShape.SetRotation(degrees)
+	return l
+}
+
+func (l *LineShape) SetFlipHorizontal(flip bool) *LineShape {
+	l.BaseShape.SetFlipHorizontal(flip)
+	return l
+}
+
+func (l *LineShape) SetFlipVertical(flip bool) *LineShape {
+	l.BaseShape.SetFlipVertical(flip)
+	return l
+}
+
+func (l *LineShape) SetFill(fill *Fill) *LineShape {
+	l.BaseShape.SetFill(fill)
+	return l
+}
+
+func (l *LineShape) SetBorder(border *Border) *LineShape {
+	l.BaseShape.SetBorder(border)
+	return l
+}
+
+func (l *LineShape) SetShadow(shadow *Shadow) *LineShape {
+	l.BaseShape.SetShadow(shadow)
+	return l
+}
+
+func (l *LineShape) SetHyperlink(link *Hyperlink) *LineShape {
+	l.BaseShape.SetHyperlink(link)
+	return l
+}
+
+func (l *LineShape) SetLocks(locks *ShapeLocks) *LineShape {
+	l.BaseShape.SetLocks(locks)
+	return l
+}
+
+func (l *LineShape) SetScene3D(scene *Scene3D) *LineShape {
+	l.BaseShape.SetScene3D(scene)
+	return l
+}
+
+func (l *LineShape) SetTag(name, value string) *LineShape {
+	l.BaseShape.SetTag(name, value)
+	return l
+}
+
+func (l *LineShape) RemoveTag(name string) *LineShape {
+	l.BaseShape.RemoveTag(name)
+	return l
+}
+
+func (l *LineShape) SetUserData(key string, value any) *LineShape {
+	l.BaseShape.SetUserData(key, value)
+	return l
+}
+
+func (l *LineShape) ApplyStyle(style ShapeStyle) *LineShape {
+	l.BaseShape.ApplyStyle(style)
+	return l
+}
+
+// Chainable setters of TableShape.
+
+func (t *TableShape) SetOffsetX(x int64) *TableShape {
+	t.BaseShape.SetOffsetX(x)
+	return t
+}
+
+func (t *TableShape) SetOffsetY(y int64) *TableShape {
+	t.BaseShape.SetOffsetY(y)
+	return t
+}
+
+func (t *TableShape) SetPosition(x, y int64) *TableShape {
+	t.BaseShape.SetPosition(x, y)
+	return t
+}
+
+func (t *TableShape) SetSize(w, h int64) *TableShape {
+	t.BaseShape.SetSize(w, h)
+	return t
+}
+
+func (t *TableShape) SetName(name string) *TableShape {
+	t.BaseShape.SetName(name)
+	return t
+}
+
+func (t *TableShape) SetDescription(desc string) *TableShape {
+	t.BaseShape.SetDescription(desc)
+	return t
+}
+
+func (t *TableShape) SetRotation(degrees int) *TableShape {
+	t.BaseShape.SetRotation(degrees)
+	return t
+}
+
+func (t *TableShape) SetFlipHorizontal(flip bool) *TableShape {
+	t.BaseShape.SetFlipHorizontal(flip)
+	return t
+}
+
+func (t *TableShape) SetFlipVertical(flip bool) *TableShape {
+	t.BaseShape.SetFlipVertical(flip)
+	return t
+}
+
+func (t *TableShape) SetFill(fill *Fill) *TableShape {
+	t.BaseShape.SetFill(fill)
+	return t
+}
+
+func (t *TableShape) SetBorder(border *Border) *TableShape {
+	t.BaseShape.SetBorder(border)
+	return t
+}
+
+func (t *TableShape) SetShadow(shadow *Shadow) *TableShape {
+	t.BaseShape.SetShadow(shadow)
+	return t
+}
+
+func (t *TableShape) SetHyperlink(link *Hyperlink) *TableShape {
+	t.BaseShape.SetHyperlink(link)
+	return t
+}
+
+func (t *TableShape) SetLocks(locks *ShapeLocks) *TableShape {
+	t.BaseShape.SetLocks(locks)
+	return t
+}
+
+func (t *TableShape) SetScene3D(scene *Scene3D) *TableShape {
+	t.BaseShape.SetScene3D(scene)
+	return t
+}
+
+func (t *TableShape) SetTag(name, value string) *TableShape {
+	t.BaseShape.SetTag(name, value)
+	return t
+}
+
+func (t *TableShape) RemoveTag(name string) *TableShape {
+	t.BaseShape.RemoveTag(name)
+	return t
+}
+
+func (t *TableShape) SetUserData(key string, value any) *TableShape {
+	t.BaseShape.SetUserData(key, value)
+	return t
+}
+
+func (t *TableShape) ApplyStyle(style ShapeStyle) *TableShape {
+	t.BaseShape.ApplyStyle(style)
+	return t
+}
+
+// Chainable setters of ChartShape.
+
+func (c *ChartShape) SetOffsetX(x int64) *ChartShape {
+	c.BaseShape.SetOffsetX(x)
+	return c
+}
+
+func (c *ChartShape) SetOffsetY(y int64) *ChartShape {
+	c.BaseShape.SetOffsetY(y)
+	return c
+}
+
+func (c *ChartShape) SetWidth(w int64) *ChartShape {
+	c.BaseShape.SetWidth(w)
+	return c
+}
+
+func (c *ChartShape) SetHeight(h int64) *ChartShape {
+	c.BaseShape.SetHeight(h)
+	return c
+}
+
+func (c *ChartShape) SetPosition(x, y int64) *ChartShape {
+	c.BaseShape.SetPosition(x, y)
+	return c
+}
+
+func (c *ChartShape) SetSize(w, h int64) *ChartShape {
+	c.BaseShape.SetSize(w, h)
+	return c
+}
+
+func (c *ChartShape) SetName(name string) *ChartShape {
+	c.BaseShape.SetName(name)
+	return c
+}
+
+func (c *ChartShape) SetDescription(desc string) *ChartShape {
+	c.BaseShape.SetDescription(desc)
+	return c
+}
+
+func (c *ChartShape) SetRotation(degrees int) *ChartShape {
+	c.BaseShape.SetRotation(degrees)
+	return c
+}
+
+func (c *ChartShape) SetFlipHorizontal(flip bool) *ChartShape {
+	c.BaseShape.SetFlipHorizontal(flip)
+	return c
+}
+
+func (c *ChartShape) SetFlipVertical(flip bool) *ChartShape {
+	c.BaseShape.SetFlipVertical(flip)
+	return c
+}
+
+func (c *ChartShape) SetFill(fill *Fill) *ChartShape {
+	c.BaseShape.SetFill(fill)
+	return c
+}
+
+func (c *ChartShape) SetBorder(border *Border) *ChartShape {
+	c.BaseShape.SetBorder(border)
+	return c
+}
+
+func (c *ChartShape) SetShadow(shadow *Shadow) *ChartShape {
+	c.BaseShape.SetShadow(shadow)
+	return c
+}
+
+func (c *ChartShape) SetHyperlink(link *Hyperlink) *ChartShape {
+	c.BaseShape.SetHyperlink(link)
+	return c
+}
+
+func (c *ChartShape) SetLocks(locks *ShapeLocks) *ChartShape {
+	c.BaseShape.SetLocks(locks)
+	return c
+}
+
+func (c *ChartShape) SetScene3D(scene *Scene3D) *ChartShape {
+	c.BaseShape.SetScene3D(scene)
+	return c
+}
+
+func (c *ChartShape) SetTag(name, value string) *ChartShape {
+	c.BaseShape.SetTag(name, value)
+	return c
+}
+
+func (c *ChartShape) RemoveTag(name string) *ChartShape {
+	c.BaseShape.RemoveTag(name)
+	return c
+}
+
+func (c *ChartShape) SetUserData(key string, value any) *ChartShape {
+	c.BaseShape.SetUserData(key, value)
+	return c
+}
+
+func (c *ChartShape) ApplyStyle(style ShapeStyle) *ChartShape {
+	c.BaseShape.ApplyStyle(style)
+	return c
+}
+
+// Chainable setters of GroupShape.
+
+func (g *GroupShape) SetOffsetX(x int64) *GroupShape {
+	g.BaseShape.SetOffsetX(x)
+	return g
+}
+
+func (g *GroupShape) SetOffsetY(y int64) *GroupShape {
+	g.BaseShape.SetOffsetY(y)
+	return g
+}
+
+func (g *GroupShape) SetWidth(w int64) *GroupShape {
+	g.BaseShape.SetWidth(w)
+	return g
+}
+
+func (g *GroupShape) SetHeight(h int64) *GroupShape {
+	g.BaseShape.SetHeight(h)
+	return g
+}
+
+func (g *GroupShape) SetPosition(x, y int64) *GroupShape {
+	g.BaseShape.SetPosition(x, y)
+	return g
+}
+
+func (g *GroupShape) SetSize(w, h int64) *GroupShape {
+	g.BaseShape.SetSize(w, h)
+	return g
+}
+
+func (g *GroupShape) SetName(name string) *GroupShape {
+	g.BaseShape.SetName(name)
+	return g
+}
+
+func (g *GroupShape) SetDescription(desc string) *GroupShape {
+	g.BaseShape.SetDescription(desc)
+	return g
+}
+
+func (g *GroupShape) SetRotation(degrees int) *GroupShape {
+	g.BaseShape.SetRotation(degrees)
+	return g
+}
+
+func (g *GroupShape) SetFlipHorizontal(flip bool) *GroupShape {
+	g.BaseShape.SetFlipHorizontal(flip)
+	return g
+}
+
+func (g *GroupShape) SetFlipVertical(flip bool) *GroupShape {
+	g.BaseShape.SetFlipVertical(flip)
+	return g
+}
+
+func (g *GroupShape) SetFill(fill *Fill) *GroupShape {
+	g.BaseShape.SetFill(fill)
+	return g
+}
+
+func (g *GroupShape) SetBorder(border *Border) *GroupShape {
+	g.BaseShape.SetBorder(border)
+	return g
+}
+
+func (g *GroupShape) SetShadow(shadow *Shadow) *GroupShape {
+	g.BaseShape.SetShadow(shadow)
+	return g
+}
+
+func (g *GroupShape) SetHyperlink(link *Hyperlink) *GroupShape {
+	g.BaseShape.SetHyperlink(link)
+	return g
+}
+
+func (g *GroupShape) SetLocks(locks *ShapeLocks) *GroupShape {
+	g.BaseShape.SetLocks(locks)
+	return g
+}
+
+func (g *GroupShape) SetScene3D(scene *Scene3D) *GroupShape {
+	g.BaseShape.SetScene3D(scene)
+	return g
+}
+
+func (g *GroupShape) SetTag(name, value string) *GroupShape {
+	g.BaseShape.SetTag(name, value)
+	return g
+}
+
+func (g *GroupShape) RemoveTag(name string) *GroupShape {
+	g.BaseShape.RemoveTag(name)
+	return g
+}
+
+func (g *GroupShape) SetUserData(key string, value any) *GroupShape {
+	g.BaseShape.SetUserData(key, value)
+	return g
+}
+
+func (g *GroupShape) ApplyStyle(style ShapeStyle) *GroupShape {
+	g.BaseShape.ApplyStyle(style)
+	return g
+}
@@ -7,6 +7,10 @@ type Transition struct {
 	Type     TransitionType
 	Speed    TransitionSpeed
 	Duration int // in milliseconds
+	// AdvanceAfter is the time in milliseconds after which the slideshow
+	// moves to the next slide, such as a rehearsed timing; 0 advances on
+	// click only.
+	AdvanceAfter int
 }
 
 // TransitionType represents the type of slide transition.
@@ -34,14 +38,26 @@ const (
 
 // Slide represents a single slide in a presentation.
 type Slide struct {
-	shapes     []Shape
-	name       string
-	notes      string
-	transition *Transition
-	visible    bool
-	comments   []*Comment
-	animations []*Animation
-	background *Fill
+	shapes        []Shape
+	name          string
+	notes         []*Paragraph
+	transition    *Transition
+	visible       bool
+	comments      []*Comment
+	animations    []*Animation
+	background    *Fill
+	backgroundRef *BackgroundRef
+	// inheritedBackground is the background of the layout or master of a
+	// slide read without one of its own. It is drawn, not written, so that
+	// the slide keeps inheriting its background.
+	inheritedBackground *Fill
+	tags                map[string]string
+	// extLst is the raw p:extLst read with the slide.
+	extLst string
+	// notesSource is the notes slide read with the slide, or nil.
+	notesSource *notesSource
+	// creationID is the slide's p14:creationId, or 0 until one is needed.
+	creationID uint32
 }
 
 // newSlide creates a new empty slide.
@@ -64,16 +80,6 @@ func (s *Slide) SetName(name string) {
 	s.name = name
 }
 
-// GetNotes returns the slide notes.
-func (s *Slide) GetNotes() string {
-	return s.notes
-}
-
-// SetNotes sets the slide notes.
-func (s *Slide) SetNotes(notes string) {
-	s.notes = notes
-}
-
 // IsVisible returns whether the slide is visible.
 func (s *Slide) IsVisible() bool {
 	return s.visible
@@ -94,6 +100,28 @@ func (s *Slide) SetTransition(t *Transition) {
 	s.transition = t
 }
 
+// GetAdvanceAfter returns the time in milliseconds after which the
+// slideshow moves to the next slide, or 0 when it advances on click only.
+func (s *Slide) GetAdvanceAfter() int {
+	if s.transition == nil {
+		return 0
+	}
+	return s.transition.AdvanceAfter
+}
+
+// SetAdvanceAfter sets the time in milliseconds the slide is shown before
+// the slideshow moves on, as rehearsing timings records it. A slide without
+// a transition gets one without effect.
+func (s *Slide) SetAdvanceAfter(ms int) {
+	if s.transition == nil {
+		if ms <= 0 {
+			return
+		}
+		s.transition = &Transition{}
+	}
+	s.transition.AdvanceAfter = max(ms, 0)
+}
+
 // GetShapes returns all shapes on the slide.
 func (s *Slide) GetShapes() []Shape {
 	return s.shapes
@@ -237,16 +265,42 @@ func (s *Slide) GetAnimations() []*Animation {
 
 // --- Background ---
 
-// SetBackground sets the slide background fill.
+// SetBackground sets the slide background fill, replacing any background style reference.
 func (s *Slide) SetBackground(f *Fill) {
 	s.background = f
+	s.backgroundRef = nil
 }
 
 // GetBackground returns the slide background fill.
+// For slides read with a background style reference this is the resolved fill.
+// It is nil for slides that inherit the background of their layout or master.
 func (s *Slide) GetBackground() *Fill {
 	return s.background
 }
 
+// BackgroundRef references a background fill style of the theme (p:bgRef).
+type BackgroundRef struct {
+	// Index selects the theme style: 1001 and up are background fill styles
+	// (bgFillStyleLst), 1–999 are fill styles (fillStyleLst).
+	Index int
+	// SchemeColor is the scheme color (e.g. "bg1") substituted for the style's
+	// placeholder color. When empty, Color is used.
+	SchemeColor string
+	Color       Color
+}
+
+// SetBackgroundRef sets the slide background to a theme background style.
+// The scheme color is substituted into the style, e.g. SetBackgroundRef(1001, "bg1").
+func (s *Slide) SetBackgroundRef(index int, schemeColor string) {
+	s.backgroundRef = &BackgroundRef{Index: index, SchemeColor: schemeColor, Color: ColorWhite}
+	s.background = nil
+}
+
+// GetBackgroundRef returns the background style reference, or nil if none is set.
+func (s *Slide) GetBackgroundRef() *BackgroundRef {
+	return s.backgroundRef
+}
+
 // --- Placeholder access ---
 
 // GetPlaceholder returns the first placeholder of the given type.
@@ -286,6 +340,19 @@ func (s *Slide) GetPlaceholders() []*PlaceholderShape {
 	return phs
 }
 
+// GetTitle returns the text of the slide's title placeholder (title or
+// centered title), with paragraphs joined by spaces, or "" if it has none.
+func (s *Slide) GetTitle() string {
+	ph := s.GetPlaceholder(PlaceholderTitle)
+	if ph == nil {
+		ph = s.GetPlaceholder(PlaceholderCtrTitle)
+	}
+	if ph == nil {
+		return ""
+	}
+	return joinNonEmpty(extractParagraphsText(ph.paragraphs), " ")
+}
+
 // GetShapeCount returns the number of shapes on the slide.
 func (s *Slide) GetShapeCount() int {
 	return len(s.shapes)
@@ -345,3 +412,143 @@ func (s *Slide) RemoveShapeByPointer(target Shape) bool {
 	}
 	return false
 }
+
+// GroupShapes groups the shapes at the given indexes into a new group shape
+// placed, in z-order, where the topmost of them was. The group's extent is
+// the bounding box of the shapes and its child coordinate space equals it,
+// so the shapes keep their positions. It returns nil when no index is given,
+// an index is out of range or repeated, or a shape is a chart, which cannot
+// be written inside a group.
+func (s *Slide) GroupShapes(indexes ...int) *GroupShape {
+	if len(indexes) == 0 {
+		return nil
+	}
+	selected := make(map[int]bool, len(indexes))
+	for _, idx := range indexes {
+		if idx < 0 || idx >= len(s.shapes) || selected[idx] {
+			return nil
+		}
+		if _, ok := s.shapes[idx].(*ChartShape); ok {
+			return nil
+		}
+		selected[idx] = true
+	}
+
+	g := NewGroupShape()
+	var minX, minY, maxX, maxY int64
+	top := 0
+	kept := make([]Shape, 0, len(s.shapes)-len(selected)+1)
+	for i, shape := range s.shapes {
+		if !selected[i] {
+			kept = append(kept, shape)
+			continue
+		}
+		b := shape.base()
+		if len(g.shapes) == 0 || b.offsetX < minX {
+			minX = b.offsetX
+		}
+		if len(g.shapes) == 0 || b.offsetY < minY {
+			minY = b.offsetY
+		}
+		if len(g.shapes) == 0 || b.offsetX+b.width > maxX {
+			maxX = b.offsetX + b.width
+		}
+		if len(g.shapes) == 0 || b.offsetY+b.height > maxY {
+			maxY = b.offsetY + b.height
+		}
+		g.shapes = append(g.shapes, shape)
+		top = len(kept)
+	}
+	g.offsetX, g.offsetY = minX, minY
+	g.width, g.height = maxX-minX, maxY-minY
+	g.childOffX, g.childOffY = minX, minY
+	g.childExtX, g.childExtY = g.width, g.height
+
+	kept = append(kept, nil)
+	copy(kept[top+1:], kept[top:])
+	kept[top] = g
+	s.shapes = kept
+	return g
+}
+
+// Ungroup replaces the top-level group g with its shapes, converted to slide
+// coordinates by GroupShape.Ungroup, at the group's z-order position. It
+// returns the shapes, or nil when g is not on the slide.
+func (s *Slide) Ungroup(g *GroupShape) []Shape {
+	for i, shape := range s.shapes {
+		if shape != g {
+			continue
+		}
+		children := g.Ungroup()
+		shapes := make([]Shape, 0, len(s.shapes)-1+len(children))
+		shapes = append(shapes, s.shapes[:i]...)
+		shapes = append(shapes, children...)
+		shapes = append(shapes, s.shapes[i+1:]...)
+		s.shapes = shapes
+		return children
+	}
+	return nil
+}
+
+// shapeParagraphs returns the paragraphs of the text of shape, those of
+// all its cells for a table. The children of a group are not included.
+func shapeParagraphs(shape Shape) []*Paragraph {
+	switch s := shape.(type) {
+	case *RichTextShape:
+		return s.paragraphs
+	case *PlaceholderShape:
+		return s.paragraphs
+	case *AutoShape:
+		return s.paragraphs
+	case *TableShape:
+		var paras []*Paragraph
+		for _, row := range s.rows {
+			for _, cell := range row {
+				paras = append(paras, cell.paragraphs...)
+			}
+		}
+		return paras
+	}
+	return nil
+}
+
+// forEachHyperlink calls fn for every hyperlink on the given shapes: shape
+// click links and text run links, descending into groups and table cells.
+func forEachHyperlink(shapes []Shape, fn func(*Hyperlink)) {
+	for _, shape := range shapes {
+		if h := shape.base().hyperlink; h != nil {
+			fn(h)
+		}
+		for _, para := range shapeParagraphs(shape) {
+			for _, elem := range para.elements {
+				if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil {
+					fn(tr.hyperlink)
+				}
+			}
+		}
+		if g, ok := shape.(*GroupShape); ok {
+			forEachHyperlink(g.shapes, fn)
+		}
+	}
+}
+
+// remapSlideLinks renumbers the internal links on slides after slides were
+// added, moved or removed: newIndex maps each old slide index to the new
+// one, or to -1 for a removed slide, whose links lose their target. A link
+// shared by several shapes is renumbered once.
+func remapSlideLinks(slides []*Slide, newIndex []int) {
+	seen := make(map[*Hyperlink]bool)
+	for _, slide := range slides {
+		forEachHyperlink(slide.shapes, func(h *Hyperlink) {
+			if !h.IsInternal || seen[h] {
+				return
+			}
+			seen[h] = true
+			idx := -1
+			if old := h.SlideIndex(); old >= 0 && old < len(newIndex) {
+				idx = newIndex[old]
+			}
+			h.SlideNumber = idx + 1
+		})
+	}
+}
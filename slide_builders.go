@@ -0,0 +1,148 @@
+package gopresentation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// AddTitleSlide appends a title slide: a centered title placeholder and,
+// when subtitle is not empty, a subtitle placeholder below it.
+func (p *Presentation) AddTitleSlide(title, subtitle string) *Slide {
+	cx, cy := p.slideSizeOrDefault()
+	slide := p.CreateSlide()
+
+	t := slide.CreatePlaceholderShape(PlaceholderCtrTitle)
+	t.SetOffsetX(cx * 3 / 40).SetOffsetY(cy * 31 / 100).SetWidth(cx * 17 / 20).SetHeight(cy * 3 / 14)
+	t.ClearAll()
+	para := t.CreateParagraph()
+	para.GetAlignment().SetHorizontal(HorizontalCenter)
+	para.CreateTextRun(title).GetFont().SetSize(44)
+
+	if subtitle != "" {
+		sub := slide.CreatePlaceholderShape(PlaceholderSubTitle)
+		sub.SetPlaceholderIndex(1)
+		sub.SetOffsetX(cx * 3 / 20).SetOffsetY(cy * 17 / 30).SetWidth(cx * 7 / 10).SetHeight(cy / 4)
+		sub.ClearAll()
+		para := sub.CreateParagraph()
+		para.GetAlignment().SetHorizontal(HorizontalCenter)
+		para.CreateTextRun(subtitle).GetFont().SetSize(32)
+	}
+	return slide
+}
+
+// AddBulletSlide appends a title and content slide: a title placeholder
+// and a body placeholder with one bulleted paragraph per bullet.
+func (p *Presentation) AddBulletSlide(title string, bullets []string) *Slide {
+	cx, cy := p.slideSizeOrDefault()
+	slide := p.CreateSlide()
+	addTitlePlaceholder(slide, title, cx, cy)
+
+	area := titleContentArea(cx, cy)
+	body := slide.CreatePlaceholderShape(PlaceholderBody)
+	body.SetPlaceholderIndex(1)
+	body.SetOffsetX(area.X).SetOffsetY(area.Y).SetWidth(area.Width).SetHeight(area.Height)
+	body.ClearAll()
+	body.SetListLevelStyle(1, outlineLevelStyle(0))
+	for _, text := range bullets {
+		body.CreateParagraph().CreateTextRun(text)
+	}
+	return slide
+}
+
+// AddImageSlide appends a slide with a title placeholder and a picture
+// scaled to fit the content area below it without distortion. Without a
+// title the picture fits the whole slide inside the margins. On error no
+// slide is added.
+func (p *Presentation) AddImageSlide(title string, img ImageSource) (*Slide, error) {
+	d, err := img.drawingShape()
+	if err != nil {
+		return nil, fmt.Errorf("image slide: %w", err)
+	}
+	d.SetName("Picture 1")
+	if img.Caption != "" {
+		d.SetDescription(img.Caption)
+	}
+
+	cx, cy := p.slideSizeOrDefault()
+	area := titleContentArea(cx, cy)
+	if title == "" {
+		area = Rect{X: cx / 20, Y: cy / 20, Width: cx * 9 / 10, Height: cy * 9 / 10}
+	}
+	if err := d.FitWithin(area, nil); err != nil {
+		return nil, fmt.Errorf("image slide: %w", err)
+	}
+
+	slide := p.CreateSlide()
+	if title != "" {
+		addTitlePlaceholder(slide, title, cx, cy)
+	}
+	slide.AddShape(d)
+	return slide, nil
+}
+
+// AddChartSlide appends a slide with a title placeholder and chart, which
+// is placed in the content area below the title.
+func (p *Presentation) AddChartSlide(title string, chart *ChartShape) *Slide {
+	cx, cy := p.slideSizeOrDefault()
+	slide := p.CreateSlide()
+	addTitlePlaceholder(slide, title, cx, cy)
+	if chart != nil {
+		area := titleContentArea(cx, cy)
+		chart.SetOffsetX(area.X).SetOffsetY(area.Y).SetWidth(area.Width).SetHeight(area.Height)
+		slide.AddShape(chart)
+	}
+	return slide
+}
+
+// slideSizeOrDefault returns the slide size, or the 4:3 default size when
+// the layout has none.
+func (p *Presentation) slideSizeOrDefault() (cx, cy int64) {
+	if p.layout != nil && p.layout.CX > 0 && p.layout.CY > 0 {
+		return p.layout.CX, p.layout.CY
+	}
+	return 9144000, 6858000
+}
+
+// titleContentArea returns the area below the title of a title and content
+// slide of size cx by cy.
+func titleContentArea(cx, cy int64) Rect {
+	marginX := cx / 20
+	return Rect{X: marginX, Y: cy * 11 / 50, Width: cx - 2*marginX, Height: cy * 7 / 10}
+}
+
+// addTitlePlaceholder adds the title placeholder of a title and content
+// slide of size cx by cy.
+func addTitlePlaceholder(slide *Slide, text string, cx, cy int64) *PlaceholderShape {
+	marginX := cx / 20
+	title := slide.CreatePlaceholderShape(PlaceholderTitle)
+	title.SetOffsetX(marginX).SetOffsetY(cy / 25).SetWidth(cx - 2*marginX).SetHeight(cy * 3 / 20)
+	title.ClearAll()
+	title.CreateParagraph().CreateTextRun(text).GetFont().SetSize(40)
+	return title
+}
+
+// drawingShape returns a picture shape holding the image.
+func (src ImageSource) drawingShape() (*DrawingShape, error) {
+	d := NewDrawingShape()
+	switch {
+	case len(src.Data) > 0:
+		mime := src.MimeType
+		if mime == "" {
+			_, format, err := image.DecodeConfig(bytes.NewReader(src.Data))
+			if err != nil {
+				return nil, err
+			}
+			mime = "image/" + format
+		}
+		d.SetImageData(src.Data, mime)
+	case src.Path != "":
+		if err := d.SetImageFromFile(src.Path); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("no data or path")
+	}
+	return d, nil
+}
@@ -0,0 +1,103 @@
+package gopresentation
+
+import "errors"
+
+// GetSlideMaster returns the first slide master, the one slides are
+// written with, creating it when the presentation has none. Shapes added
+// to it appear behind the shapes of every slide.
+func (p *Presentation) GetSlideMaster() *SlideMaster {
+	if len(p.slideMasters) == 0 {
+		return p.CreateSlideMaster()
+	}
+	return p.slideMasters[0]
+}
+
+// masterShapes returns the shapes of the slide master slides are written
+// with.
+func (p *Presentation) masterShapes() []Shape {
+	if len(p.slideMasters) == 0 {
+		return nil
+	}
+	return p.slideMasters[0].shapes
+}
+
+// GetShapes returns the shapes added to the master. Shapes of masters read
+// from a file are not loaded.
+func (sm *SlideMaster) GetShapes() []Shape {
+	return sm.shapes
+}
+
+// AddShape adds a shape to the master. Charts are not supported on masters
+// and are not written.
+func (sm *SlideMaster) AddShape(shape Shape) {
+	sm.shapes = append(sm.shapes, shape)
+}
+
+// RemoveShape removes a shape by index.
+func (sm *SlideMaster) RemoveShape(index int) error {
+	if index < 0 || index >= len(sm.shapes) {
+		return errors.New("shape index out of range")
+	}
+	sm.shapes = append(sm.shapes[:index], sm.shapes[index+1:]...)
+	return nil
+}
+
+// CreateRichTextShape creates a new rich text shape and adds it to the master.
+func (sm *SlideMaster) CreateRichTextShape() *RichTextShape {
+	shape := NewRichTextShape()
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
+
+// CreateDrawingShape creates a new drawing (image) shape and adds it to the master.
+func (sm *SlideMaster) CreateDrawingShape() *DrawingShape {
+	shape := NewDrawingShape()
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
+
+// AddImage creates a drawing shape from a file path and adds it to the master.
+func (sm *SlideMaster) AddImage(path string) (*DrawingShape, error) {
+	shape := NewDrawingShape()
+	if err := shape.SetImageFromFile(path); err != nil {
+		return nil, err
+	}
+	sm.shapes = append(sm.shapes, shape)
+	return shape, nil
+}
+
+// AddImageData creates a drawing shape from raw image data and adds it to the master.
+func (sm *SlideMaster) AddImageData(data []byte, mimeType string) *DrawingShape {
+	shape := NewDrawingShape()
+	shape.SetImageData(data, mimeType)
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
+
+// CreateTableShape creates a new table shape and adds it to the master.
+func (sm *SlideMaster) CreateTableShape(rows, cols int) *TableShape {
+	shape := NewTableShape(rows, cols)
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
+
+// CreateAutoShape creates a new auto shape and adds it to the master.
+func (sm *SlideMaster) CreateAutoShape() *AutoShape {
+	shape := NewAutoShape()
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
+
+// CreateLineShape creates a new line shape and adds it to the master.
+func (sm *SlideMaster) CreateLineShape() *LineShape {
+	shape := NewLineShape()
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
+
+// CreateGroupShape creates a new group shape and adds it to the master.
+func (sm *SlideMaster) CreateGroupShape() *GroupShape {
+	shape := NewGroupShape()
+	sm.shapes = append(sm.shapes, shape)
+	return shape
+}
@@ -0,0 +1,96 @@
+package gopresentation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Slide number formats for SetSlideNumberFormat.
+const (
+	SlideNumberArabic     = "arabic"
+	SlideNumberRomanUpper = "romanUc"
+	SlideNumberRomanLower = "romanLc"
+	SlideNumberAlphaUpper = "alphaUc"
+	SlideNumberAlphaLower = "alphaLc"
+)
+
+// slideNumberFieldID is the field ID written for slide number fields.
+const slideNumberFieldID = "{B6F15528-21DE-4FAA-801E-634DDDAF4B2B}"
+
+// SlideNumberField is a paragraph element that shows the number of the
+// slide it is on, such as the content of a slide number placeholder.
+type SlideNumberField struct {
+	font *Font
+}
+
+func (f *SlideNumberField) GetElementType() string { return "slidenum" }
+
+// GetFont returns the font properties.
+func (f *SlideNumberField) GetFont() *Font { return f.font }
+
+// SetFont sets the font properties.
+func (f *SlideNumberField) SetFont(font *Font) { f.font = font }
+
+// CreateSlideNumber creates a slide number field.
+func (p *Paragraph) CreateSlideNumber() *SlideNumberField {
+	f := &SlideNumberField{font: NewFont()}
+	p.elements = append(p.elements, f)
+	return f
+}
+
+// GetFirstSlideNumber returns the number of the first slide (default 1).
+func (p *Presentation) GetFirstSlideNumber() int {
+	if p.firstSlideNumber <= 0 {
+		return 1
+	}
+	return p.firstSlideNumber
+}
+
+// SetFirstSlideNumber sets the number of the first slide, for example 57
+// for an appendix that continues another deck.
+func (p *Presentation) SetFirstSlideNumber(n int) {
+	p.firstSlideNumber = n
+}
+
+// GetSlideNumberFormat returns the format of slide numbers (default
+// SlideNumberArabic).
+func (p *Presentation) GetSlideNumberFormat() string {
+	if p.slideNumberFormat == "" {
+		return SlideNumberArabic
+	}
+	return p.slideNumberFormat
+}
+
+// SetSlideNumberFormat sets the format of slide numbers, such as
+// SlideNumberRomanUpper. PowerPoint only shows slide number fields in
+// arabic numerals, so in any other format the numbers are written as
+// plain text and are not renumbered when slides move.
+func (p *Presentation) SetSlideNumberFormat(format string) {
+	p.slideNumberFormat = format
+}
+
+// slideNumberText returns the slide number shown on the slide at index.
+func (p *Presentation) slideNumberText(index int) string {
+	return formatSlideNumber(p.GetFirstSlideNumber()+index, p.GetSlideNumberFormat())
+}
+
+// formatSlideNumber formats num in one of the slide number formats.
+// Letters repeat past Z, as in AA, BB.
+func formatSlideNumber(num int, format string) string {
+	switch format {
+	case SlideNumberRomanUpper:
+		return toRoman(num)
+	case SlideNumberRomanLower:
+		return strings.ToLower(toRoman(num))
+	case SlideNumberAlphaUpper, SlideNumberAlphaLower:
+		if num <= 0 {
+			break
+		}
+		first := 'A'
+		if format == SlideNumberAlphaLower {
+			first = 'a'
+		}
+		return strings.Repeat(string(first+rune((num-1)%26)), (num-1)/26+1)
+	}
+	return strconv.Itoa(num)
+}
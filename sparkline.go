@@ -0,0 +1,113 @@
+package gopresentation
+
+import "math"
+
+// Default sparkline colors: the first two colors of the chart palette.
+var (
+	sparklineColor         = NewColor("FF4F81BD")
+	sparklineNegativeColor = NewColor("FFC0504D")
+)
+
+// CreateSparkline adds a line micro-chart of values inside rect (in EMU),
+// drawn as a single freeform rather than a chart part so that a slide can
+// hold dozens of them cheaply. The line spans the full width of rect and is
+// scaled so that the smallest value touches its bottom and the largest its
+// top. It returns the group holding the line, which can be moved and sized
+// as one shape, or nil when values is empty.
+func (s *Slide) CreateSparkline(values []float64, rect Rect) *GroupShape {
+	if len(values) == 0 || rect.Empty() {
+		return nil
+	}
+	lo, hi := sparklineRange(values)
+	path := &CustomGeomPath{Width: rect.Width, Height: rect.Height}
+	toY := func(v float64) int64 {
+		if hi == lo {
+			return rect.Height / 2
+		}
+		return int64(math.Round(float64(rect.Height) * (hi - v) / (hi - lo)))
+	}
+	if len(values) == 1 {
+		// A single value is a flat line
+		y := toY(values[0])
+		path.Commands = []PathCommand{
+			{Type: "moveTo", Pts: []PathPoint{{X: 0, Y: y}}},
+			{Type: "lnTo", Pts: []PathPoint{{X: rect.Width, Y: y}}},
+		}
+	} else {
+		for i, v := range values {
+			cmd := "lnTo"
+			if i == 0 {
+				cmd = "moveTo"
+			}
+			x := int64(math.Round(float64(rect.Width) * float64(i) / float64(len(values)-1)))
+			path.Commands = append(path.Commands, PathCommand{Type: cmd, Pts: []PathPoint{{X: x, Y: toY(v)}}})
+		}
+	}
+
+	line := NewRichTextShape()
+	line.SetName("Sparkline")
+	line.SetCustomPath(path)
+	line.SetOffsetX(rect.X).SetOffsetY(rect.Y).SetWidth(rect.Width).SetHeight(rect.Height)
+	line.GetBorder().SetSolidFill(sparklineColor).SetWidth(1)
+
+	g := s.CreateGroupShape()
+	g.SetName("Sparkline")
+	g.SetOffsetX(rect.X).SetOffsetY(rect.Y).SetWidth(rect.Width).SetHeight(rect.Height)
+	g.AddShape(line)
+	return g
+}
+
+// CreateColumnSparkline adds a column micro-chart of values inside rect (in
+// EMU), one rectangle per value, like CreateSparkline. Columns grow from
+// zero, or from the bottom of rect when all values are positive, and
+// negative values are drawn in a second color. It returns the group holding
+// the columns, or nil when values is empty.
+func (s *Slide) CreateColumnSparkline(values []float64, rect Rect) *GroupShape {
+	if len(values) == 0 || rect.Empty() {
+		return nil
+	}
+	lo, hi := sparklineRange(values)
+	lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+	if hi == lo {
+		hi = lo + 1
+	}
+	toY := func(v float64) int64 {
+		return rect.Y + int64(math.Round(float64(rect.Height)*(hi-v)/(hi-lo)))
+	}
+	zeroY := toY(0)
+
+	// Leave a fifth of each slot as the gap between columns
+	slot := float64(rect.Width) / float64(len(values))
+	colW := int64(math.Max(math.Round(slot*4/5), 1))
+
+	g := s.CreateGroupShape()
+	g.SetName("Sparkline")
+	g.SetOffsetX(rect.X).SetOffsetY(rect.Y).SetWidth(rect.Width).SetHeight(rect.Height)
+	for i, v := range values {
+		top, bottom := toY(v), zeroY
+		c := sparklineColor
+		if v < 0 {
+			top, bottom = zeroY, toY(v)
+			c = sparklineNegativeColor
+		}
+		if bottom <= top {
+			continue
+		}
+		col := NewAutoShape()
+		col.SetName("Sparkline Column")
+		col.SetOffsetX(rect.X + int64(math.Round(slot*float64(i)+(slot-float64(colW))/2)))
+		col.SetOffsetY(top).SetWidth(colW).SetHeight(bottom - top)
+		col.SetSolidFill(c)
+		g.AddShape(col)
+	}
+	return g
+}
+
+// sparklineRange returns the smallest and largest of values.
+func sparklineRange(values []float64) (lo, hi float64) {
+	lo, hi = values[0], values[0]
+	for _, v := range values[1:] {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	return lo, hi
+}
@@ -0,0 +1,147 @@
+package gopresentation
+
+// StampPosition is the place of a stamp on the slide.
+type StampPosition int
+
+// Stamp positions.
+const (
+	StampBottomRight StampPosition = iota
+	StampBottomCenter
+	StampBottomLeft
+	StampTopRight
+	StampTopCenter
+	StampTopLeft
+)
+
+// stampTag is the tag that marks shapes added by the stamp helpers, with
+// the kind of stamp as its value, so that stamping again replaces them.
+const stampTag = "GOPPT_STAMP"
+
+// StampOptions controls the footers and slide numbers added to every slide.
+type StampOptions struct {
+	// Font is the font of the stamp. Default: 10 pt gray.
+	Font *Font
+	// Margin is the distance in EMU from the slide edges. Default: 0.25
+	// inch.
+	Margin int64
+	// Width is the width of the stamp in EMU. Default: a third of the slide
+	// width.
+	Width int64
+	// SkipFirst leaves the first slide, usually the title slide, unstamped.
+	SkipFirst bool
+	// SkipHidden leaves hidden slides unstamped.
+	SkipHidden bool
+}
+
+// DefaultStampOptions returns the default stamp options.
+func DefaultStampOptions() *StampOptions {
+	font := NewFont()
+	font.SetSize(10).SetColor(NewColor("FF808080"))
+	return &StampOptions{Font: font, Margin: 228600}
+}
+
+// StampAll calls stamp with the index and slide of every slide, to place
+// the same shapes on each in one call.
+func (p *Presentation) StampAll(stamp func(i int, s *Slide)) {
+	for i, s := range p.slides {
+		stamp(i, s)
+	}
+}
+
+// AddConfidentialityFooter adds text at the bottom center of every slide,
+// replacing a footer added before. A nil opts uses DefaultStampOptions.
+// Empty text removes the footers.
+func (p *Presentation) AddConfidentialityFooter(text string, opts *StampOptions) {
+	p.stamp("footer", StampBottomCenter, opts, func(para *Paragraph, f *Font) bool {
+		if text == "" {
+			return false
+		}
+		para.CreateTextRun(text).SetFont(f)
+		return true
+	})
+}
+
+// AddSlideNumbers adds the slide number at position on every slide,
+// replacing slide numbers added before. The numbers are fields, so they
+// stay correct when slides are reordered, and follow SetFirstSlideNumber and
+// SetSlideNumberFormat. A nil opts uses DefaultStampOptions.
+func (p *Presentation) AddSlideNumbers(position StampPosition, opts *StampOptions) {
+	p.stamp("slidenumber", position, opts, func(para *Paragraph, f *Font) bool {
+		para.CreateSlideNumber().SetFont(f)
+		return true
+	})
+}
+
+// stamp removes the stamps of kind from every slide and, unless fill
+// returns false, adds a new one at position whose paragraph fill builds
+// with the font of opts.
+func (p *Presentation) stamp(kind string, position StampPosition, opts *StampOptions, fill func(para *Paragraph, f *Font) bool) {
+	if opts == nil {
+		opts = DefaultStampOptions()
+	}
+	cx, cy := p.slideSizeOrDefault()
+	width := opts.Width
+	if width <= 0 {
+		width = cx / 3
+	}
+	f := NewFont()
+	if opts.Font != nil {
+		*f = *opts.Font
+	}
+	r := measureRenderer()
+	probe := NewParagraph()
+	probe.CreateTextRun("0").SetFont(f)
+	_, h := r.measureParagraphs([]*Paragraph{probe}, 0, false)
+	// One line and the default insets
+	height := pixelToEMU(h) + 2*45720
+
+	x := opts.Margin
+	align := HorizontalLeft
+	switch position {
+	case StampBottomCenter, StampTopCenter:
+		x, align = (cx-width)/2, HorizontalCenter
+	case StampBottomRight, StampTopRight:
+		x, align = cx-opts.Margin-width, HorizontalRight
+	}
+	y := cy - opts.Margin - height
+	anchor := TextAnchorBottom
+	if position >= StampTopRight {
+		y, anchor = opts.Margin, TextAnchorTop
+	}
+
+	p.StampAll(func(i int, s *Slide) {
+		shapes := s.shapes[:0]
+		for _, shape := range s.shapes {
+			if shape.base().GetTag(stampTag) != kind {
+				shapes = append(shapes, shape)
+			}
+		}
+		clear(s.shapes[len(shapes):])
+		s.shapes = shapes
+		if (opts.SkipFirst && i == 0) || (opts.SkipHidden && !s.IsVisible()) {
+			return
+		}
+
+		t := NewRichTextShape()
+		para := t.GetActiveParagraph()
+		font := *f
+		if !fill(para, &font) {
+			return
+		}
+		para.GetAlignment().SetHorizontal(align)
+		t.SetTextAnchor(anchor)
+		t.SetOffsetX(x).SetOffsetY(y).SetWidth(width).SetHeight(height)
+		t.SetName(stampName(kind))
+		t.SetTag(stampTag, kind)
+		t.SetLocks(&ShapeLocks{NoMove: true, NoResize: true, NoRot: true})
+		s.AddShape(t)
+	})
+}
+
+// stampName returns the shape name of stamps of kind.
+func stampName(kind string) string {
+	if kind == "slidenumber" {
+		return "Slide Number"
+	}
+	return "Footer"
+}
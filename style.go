@@ -1,12 +1,52 @@
 package gopresentation
 
 import (
+	"math"
+	"slices"
 	"strings"
 )
 
 // Color represents an ARGB color.
+// A color may carry DrawingML color transforms, added with WithTransform and
+// applied in order to its base value, e.g. the theme color "Accent 1, Lighter
+// 40%" is the accent color with lumMod 60000 and lumOff 40000. ARGB always
+// holds the transformed color; the base value and transforms are written
+// back as long as ARGB is not changed. Colors are comparable.
 type Color struct {
-	ARGB string // 8-character hex string, e.g., "FF000000" for black
+	ARGB   string // 8-character hex string, e.g., "FF000000" for black
+	System string // system color name written as a:sysClr (e.g. "windowText"); ARGB holds its RGB value
+	Preset string // preset color name written as a:prstClr (e.g. "coral"); ARGB holds its RGB value
+	Scheme string // theme color slot written as a:schemeClr (e.g. "accent1"); ARGB holds its default theme value
+
+	// base is the ARGB value the transforms apply to.
+	base string
+	// transforms holds the first nTransforms transforms, in an array so
+	// that Color stays comparable.
+	transforms  [maxColorTransforms]ColorTransform
+	nTransforms int
+}
+
+// maxColorTransforms is the most transforms a Color keeps. DrawingML colors
+// rarely have more than two.
+const maxColorTransforms = 8
+
+// ColorTransformType identifies a DrawingML color transform.
+type ColorTransformType string
+
+const (
+	ColorTransformTint   ColorTransformType = "tint"   // keep Value of the color, blend the rest toward white
+	ColorTransformShade  ColorTransformType = "shade"  // keep Value of the color, blend the rest toward black
+	ColorTransformSatMod ColorTransformType = "satMod" // multiply saturation by Value
+	ColorTransformLumMod ColorTransformType = "lumMod" // multiply luminance by Value
+	ColorTransformLumOff ColorTransformType = "lumOff" // add Value to luminance
+	ColorTransformAlpha  ColorTransformType = "alpha"  // set opacity to Value
+)
+
+// ColorTransform is a single color transform.
+// Value is in 1/1000 of a percent (100000 = 100%).
+type ColorTransform struct {
+	Type  ColorTransformType
+	Value int
 }
 
 // Predefined colors.
@@ -34,6 +74,72 @@ func NewColor(argb string) Color {
 	return Color{ARGB: argb}
 }
 
+// NewSystemColor creates a system color such as "windowText" or "window".
+// Unknown names resolve to black.
+func NewSystemColor(name string) Color {
+	c, _ := systemColor(name, "")
+	return c
+}
+
+// NewPresetColor creates a preset color such as "coral" or "dkBlue".
+// Unknown names resolve to black.
+func NewPresetColor(name string) Color {
+	c := presetColorToColor(name)
+	c.Preset = name
+	return c
+}
+
+// systemColor returns the system color for name. lastClr, when set, is the
+// RGB value last computed by the application and takes precedence over the
+// built-in defaults. ok is false if neither is known.
+func systemColor(name, lastClr string) (c Color, ok bool) {
+	if lastClr != "" {
+		c = NewColor("FF" + lastClr)
+		ok = true
+	} else if rgb, found := systemColors[name]; found {
+		c = NewColor("FF" + rgb)
+		ok = true
+	} else {
+		c = ColorBlack
+	}
+	c.System = name
+	return c, ok
+}
+
+// systemColors holds default RGB values for the ST_SystemColorVal names.
+var systemColors = map[string]string{
+	"scrollBar":               "C8C8C8",
+	"background":              "000000",
+	"activeCaption":           "99B4D1",
+	"inactiveCaption":         "BFCDDB",
+	"menu":                    "F0F0F0",
+	"window":                  "FFFFFF",
+	"windowFrame":             "646464",
+	"menuText":                "000000",
+	"windowText":              "000000",
+	"captionText":             "000000",
+	"activeBorder":            "B4B4B4",
+	"inactiveBorder":          "F4F7FC",
+	"appWorkspace":            "ABABAB",
+	"highlight":               "3399FF",
+	"highlightText":           "FFFFFF",
+	"btnFace":                 "F0F0F0",
+	"btnShadow":               "A0A0A0",
+	"grayText":                "6D6D6D",
+	"btnText":                 "000000",
+	"inactiveCaptionText":     "434E54",
+	"btnHighlight":            "FFFFFF",
+	"3dDkShadow":              "696969",
+	"3dLight":                 "E3E3E3",
+	"infoText":                "000000",
+	"infoBk":                  "FFFFE1",
+	"hotLight":                "0066CC",
+	"gradientActiveCaption":   "B9D1EA",
+	"gradientInactiveCaption": "D7E4F2",
+	"menuHighlight":           "3399FF",
+	"menuBar":                 "F0F0F0",
+}
+
 // isValidARGB checks that s is exactly 8 hex characters.
 func isValidARGB(s string) bool {
 	if len(s) != 8 {
@@ -67,6 +173,90 @@ func (c Color) GetAlpha() uint8 {
 	return parseHexByte(c.ARGB, 0)
 }
 
+// WithTransform returns a copy of the color with the transform appended and
+// applied to ARGB. Value is in 1/1000 of a percent, e.g.
+// c.WithTransform(ColorTransformLumMod, 75000). Past maxColorTransforms (8)
+// transforms, the color keeps only its transformed ARGB, without the
+// transforms or its scheme, system or preset name.
+func (c Color) WithTransform(t ColorTransformType, value int) Color {
+	if c.nTransforms == 0 {
+		c.base = c.ARGB
+	}
+	if !isValidARGB(c.ARGB) {
+		c.ARGB = "FF000000"
+	}
+	ct := ColorTransform{Type: t, Value: value}
+	applyColorTransform(&c, ct)
+	if c.nTransforms == maxColorTransforms {
+		return Color{ARGB: c.ARGB}
+	}
+	c.transforms[c.nTransforms] = ct
+	c.nTransforms++
+	return c
+}
+
+// Transforms returns the transforms of the color, in the order they apply.
+func (c Color) Transforms() []ColorTransform {
+	return slices.Clone(c.transforms[:c.nTransforms])
+}
+
+// BaseARGB returns the ARGB value the transforms of the color apply to, or
+// ARGB when it has none.
+func (c Color) BaseARGB() string {
+	if c.nTransforms == 0 {
+		return c.ARGB
+	}
+	return c.base
+}
+
+// Resolve returns the color without its transforms, keeping the
+// transformed ARGB, as a plain RGB color.
+func (c Color) Resolve() Color {
+	if c.nTransforms == 0 {
+		return c
+	}
+	return Color{ARGB: c.ARGB}
+}
+
+// transformed returns the base value and transforms to write for the color,
+// or ARGB and no transforms once ARGB no longer is the transformed base.
+func (c Color) transformed() (argb string, transforms []ColorTransform) {
+	if c.nTransforms == 0 {
+		return c.ARGB, nil
+	}
+	out := Color{ARGB: c.base}
+	if !isValidARGB(out.ARGB) {
+		out.ARGB = "FF000000"
+	}
+	for _, t := range c.transforms[:c.nTransforms] {
+		applyColorTransform(&out, t)
+	}
+	if out.ARGB != c.ARGB {
+		return c.ARGB, nil
+	}
+	return c.base, c.transforms[:c.nTransforms]
+}
+
+// applyColorTransform applies t to c, whose ARGB must be valid.
+func applyColorTransform(c *Color, t ColorTransform) {
+	v := float64(t.Value) / 100000.0
+	switch t.Type {
+	case ColorTransformTint:
+		applyTint(c, 1-v)
+	case ColorTransformShade:
+		applyShade(c, v)
+	case ColorTransformSatMod:
+		applySatMod(c, v)
+	case ColorTransformLumMod:
+		applyLumMod(c, v)
+	case ColorTransformLumOff:
+		applyLumOff(c, v)
+	case ColorTransformAlpha:
+		v = max(0, min(1, v))
+		c.ARGB = colorHex(uint8(v*255+0.5)) + c.ARGB[2:]
+	}
+}
+
 // parseHexByte parses two hex characters at offset into a uint8.
 // Returns 0 on any error (out of range, invalid chars).
 func parseHexByte(s string, offset int) uint8 {
@@ -180,6 +370,24 @@ func (f *Font) SetStrikethrough(s bool) *Font {
 	return f
 }
 
+// SetSuperscript raises the text above the baseline (clears Subscript).
+func (f *Font) SetSuperscript(v bool) *Font {
+	f.Superscript = v
+	if v {
+		f.Subscript = false
+	}
+	return f
+}
+
+// SetSubscript lowers the text below the baseline (clears Superscript).
+func (f *Font) SetSubscript(v bool) *Font {
+	f.Subscript = v
+	if v {
+		f.Superscript = false
+	}
+	return f
+}
+
 // Alignment represents text alignment properties.
 type Alignment struct {
 	Horizontal HorizontalAlignment
@@ -236,8 +444,26 @@ func (a *Alignment) SetVertical(v VerticalAlignment) *Alignment {
 type Fill struct {
 	Type      FillType
 	Color     Color
-	EndColor  Color // for gradient fills
-	Rotation  int   // gradient rotation in degrees
+	EndColor  Color     // for gradient fills
+	Rotation  int       // gradient rotation in degrees
+	ImageData []byte    // for picture fills; shared between fills read with ReaderOptions.LowMemory, so not to be modified in place
+	MimeType  string    // for picture fills
+	Tile      *FillTile // picture is tiled instead of stretched when non-nil
+}
+
+// FillTile describes how a picture fill is tiled across the filled area.
+type FillTile struct {
+	OffsetX   int64  // horizontal offset of the first tile in EMU
+	OffsetY   int64  // vertical offset of the first tile in EMU
+	ScaleX    int    // horizontal scale in 1/1000 of a percent (100000 = 100%)
+	ScaleY    int    // vertical scale in 1/1000 of a percent (100000 = 100%)
+	Flip      string // "none", "x", "y" or "xy"; "" is "none"
+	Alignment string // tile anchor: "tl", "t", "tr", "l", "ctr", "r", "bl", "b", "br"; "" is "tl"
+}
+
+// NewFillTile creates a tile description at 100% scale anchored top-left.
+func NewFillTile() *FillTile {
+	return &FillTile{ScaleX: 100000, ScaleY: 100000, Flip: "none", Alignment: "tl"}
 }
 
 // FillType represents the type of fill.
@@ -248,6 +474,7 @@ const (
 	FillSolid
 	FillGradientLinear
 	FillGradientPath
+	FillPicture
 )
 
 // NewFill creates a new Fill with no fill.
@@ -277,6 +504,21 @@ func (f *Fill) SetGradientLinear(startColor, endColor Color, rotation int) *Fill
 	return f
 }
 
+// SetPicture sets a picture fill stretched over the filled area.
+func (f *Fill) SetPicture(data []byte, mimeType string) *Fill {
+	f.Type = FillPicture
+	f.ImageData = data
+	f.MimeType = mimeType
+	f.Tile = nil
+	return f
+}
+
+// SetTile tiles a picture fill using the given settings. Nil restores stretching.
+func (f *Fill) SetTile(tile *FillTile) *Fill {
+	f.Tile = tile
+	return f
+}
+
 // Border represents a shape border.
 type Border struct {
 	Style BorderStyle
@@ -315,6 +557,15 @@ const (
 	ArrowSizeLg  ArrowSize = "lg"
 )
 
+// LineCap represents how the ends of a line are drawn.
+type LineCap string
+
+const (
+	LineCapFlat   LineCap = "flat" // ends exactly at the end points
+	LineCapRound  LineCap = "rnd"  // rounded beyond the end points
+	LineCapSquare LineCap = "sq"   // squared off beyond the end points
+)
+
 // LineEnd represents the arrow head or tail of a line.
 type LineEnd struct {
 	Type   ArrowType
@@ -390,10 +641,14 @@ func (s *Shadow) SetDistance(d int) *Shadow {
 
 // Hyperlink represents a hyperlink.
 type Hyperlink struct {
-	URL     string
-	Tooltip string
-	IsInternal bool
-	SlideNumber int
+	URL         string
+	Tooltip     string
+	TargetFrame string // tgtFrame, e.g. "_blank"
+	IsInternal  bool
+	SlideNumber int // 1-based target slide of an internal link, 0 when it is missing
+	// targetPart is the slide part an internal link points to while reading;
+	// it is resolved to SlideNumber once all slides are loaded.
+	targetPart string
 }
 
 // allowedHyperlinkSchemes defines the URL schemes permitted in hyperlinks.
@@ -421,7 +676,7 @@ func NewHyperlink(url string) *Hyperlink {
 	return &Hyperlink{URL: url}
 }
 
-// NewInternalHyperlink creates a hyperlink to another slide.
+// NewInternalHyperlink creates a hyperlink to another slide (1-based).
 func NewInternalHyperlink(slideNumber int) *Hyperlink {
 	return &Hyperlink{
 		IsInternal:  true,
@@ -429,6 +684,27 @@ func NewInternalHyperlink(slideNumber int) *Hyperlink {
 	}
 }
 
+// SetTooltip sets the text shown when hovering over the link.
+func (h *Hyperlink) SetTooltip(tooltip string) *Hyperlink {
+	h.Tooltip = tooltip
+	return h
+}
+
+// SetTargetFrame sets the frame the link opens in (e.g. "_blank").
+func (h *Hyperlink) SetTargetFrame(frame string) *Hyperlink {
+	h.TargetFrame = frame
+	return h
+}
+
+// SlideIndex returns the 0-based slide an internal link jumps to, or -1
+// for external links and links whose slide is missing.
+func (h *Hyperlink) SlideIndex() int {
+	if !h.IsInternal || h.SlideNumber <= 0 {
+		return -1
+	}
+	return h.SlideNumber - 1
+}
+
 // --- Color modification helpers for OOXML color transforms ---
 
 // rgbToHSL converts RGB (0-255) to HSL (h: 0-360, s: 0-1, l: 0-1).
@@ -566,6 +842,90 @@ func applyLumOff(c *Color, offset float64) {
 	c.ARGB = c.ARGB[:2] + colorHex(nr) + colorHex(ng) + colorHex(nb)
 }
 
+// --- Palette helpers ---
+
+// ColorFromRGB creates an opaque color from red, green and blue components.
+func ColorFromRGB(r, g, b uint8) Color {
+	return Color{ARGB: "FF" + colorHex(r) + colorHex(g) + colorHex(b)}
+}
+
+// ColorFromHSL creates an opaque color from hue (0-360 degrees), saturation
+// and lightness (0-1). Out-of-range values are wrapped or clamped.
+func ColorFromHSL(h, s, l float64) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s = math.Max(0, math.Min(1, s))
+	l = math.Max(0, math.Min(1, l))
+	return ColorFromRGB(hslToRGB(h, s, l))
+}
+
+// HSL returns the hue (0-360 degrees), saturation and lightness (0-1) of the color.
+func (c Color) HSL() (h, s, l float64) {
+	return rgbToHSL(c.GetRed(), c.GetGreen(), c.GetBlue())
+}
+
+// Lighten returns the color moved pct percent (0-100) of the way toward white
+// in lightness, like PowerPoint's "Lighter 40%" theme variants.
+func (c Color) Lighten(pct float64) Color {
+	h, s, l := c.HSL()
+	p := math.Max(0, math.Min(100, pct)) / 100
+	return c.withHSL(h, s, l*(1-p)+p)
+}
+
+// Darken returns the color with its lightness reduced by pct percent (0-100),
+// like PowerPoint's "Darker 25%" theme variants.
+func (c Color) Darken(pct float64) Color {
+	h, s, l := c.HSL()
+	p := math.Max(0, math.Min(100, pct)) / 100
+	return c.withHSL(h, s, l*(1-p))
+}
+
+// withHSL returns an untransformed color with the given HSL values and the alpha of c.
+func (c Color) withHSL(h, s, l float64) Color {
+	out := ColorFromHSL(h, s, l)
+	out.ARGB = colorHex(c.GetAlpha()) + out.ARGB[2:]
+	return out
+}
+
+// RelativeLuminance returns the WCAG 2 relative luminance of the color (0-1).
+func (c Color) RelativeLuminance() float64 {
+	channel := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.GetRed()) + 0.7152*channel(c.GetGreen()) + 0.0722*channel(c.GetBlue())
+}
+
+// ContrastRatio returns the WCAG 2 contrast ratio between two colors (1-21).
+// Text generally needs 4.5 or more against its background, large text 3 or more.
+func (c Color) ContrastRatio(other Color) float64 {
+	l1, l2 := c.RelativeLuminance(), other.RelativeLuminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// applySatMod multiplies the saturation by factor.
+func applySatMod(c *Color, factor float64) {
+	r, g, b := c.GetRed(), c.GetGreen(), c.GetBlue()
+	h, s, l := rgbToHSL(r, g, b)
+	s *= factor
+	if s > 1 {
+		s = 1
+	}
+	if s < 0 {
+		s = 0
+	}
+	nr, ng, nb := hslToRGB(h, s, l)
+	c.ARGB = c.ARGB[:2] + colorHex(nr) + colorHex(ng) + colorHex(nb)
+}
+
 // applyTint blends the color toward white by the given amount (0-1).
 func applyTint(c *Color, amount float64) {
 	r, g, b := c.GetRed(), c.GetGreen(), c.GetBlue()
@@ -590,46 +950,60 @@ func colorHex(v uint8) string {
 }
 
 // presetColorToColor converts an OOXML preset color name to a Color.
-// See ECMA-376 §20.1.10.47 for the full list.
+// See ECMA-376 §20.1.10.47 for the full list. The abbreviated "dk", "lt" and
+// "med" prefixes are accepted alongside "dark", "light" and "medium".
 func presetColorToColor(name string) Color {
-	switch name {
-	case "black":
-		return ColorBlack
-	case "white":
-		return ColorWhite
-	case "red":
-		return ColorRed
-	case "green":
-		return ColorGreen
-	case "blue":
-		return ColorBlue
-	case "yellow":
-		return ColorYellow
-	case "cyan", "aqua":
-		return NewColor("FF00FFFF")
-	case "magenta", "fuchsia":
-		return NewColor("FFFF00FF")
-	case "gray", "grey":
-		return NewColor("FF808080")
-	case "dkGray", "darkGray", "darkGrey":
-		return NewColor("FFA9A9A9")
-	case "ltGray", "lightGray", "lightGrey":
-		return NewColor("FFD3D3D3")
-	case "maroon":
-		return NewColor("FF800000")
-	case "olive":
-		return NewColor("FF808000")
-	case "navy":
-		return NewColor("FF000080")
-	case "purple":
-		return NewColor("FF800080")
-	case "teal":
-		return NewColor("FF008080")
-	case "silver":
-		return NewColor("FFC0C0C0")
-	case "orange":
-		return NewColor("FFFFA500")
-	default:
-		return ColorBlack
-	}
+	key := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(key, "dk"):
+		key = "dark" + key[2:]
+	case strings.HasPrefix(key, "lt"):
+		key = "light" + key[2:]
+	case strings.HasPrefix(key, "med") && !strings.HasPrefix(key, "medium"):
+		key = "medium" + key[3:]
+	}
+	key = strings.ReplaceAll(key, "grey", "gray")
+	if rgb, ok := presetColors[key]; ok {
+		return NewColor("FF" + rgb)
+	}
+	return ColorBlack
+}
+
+// presetColors maps lower-cased ST_PresetColorVal names to RGB hex values.
+var presetColors = map[string]string{
+	"aliceblue": "F0F8FF", "antiquewhite": "FAEBD7", "aqua": "00FFFF", "aquamarine": "7FFFD4",
+	"azure": "F0FFFF", "beige": "F5F5DC", "bisque": "FFE4C4", "black": "000000",
+	"blanchedalmond": "FFEBCD", "blue": "0000FF", "blueviolet": "8A2BE2", "brown": "A52A2A",
+	"burlywood": "DEB887", "cadetblue": "5F9EA0", "chartreuse": "7FFF00", "chocolate": "D2691E",
+	"coral": "FF7F50", "cornflowerblue": "6495ED", "cornsilk": "FFF8DC", "crimson": "DC143C",
+	"cyan": "00FFFF", "darkblue": "00008B", "darkcyan": "008B8B", "darkgoldenrod": "B8860B",
+	"darkgray": "A9A9A9", "darkgreen": "006400", "darkkhaki": "BDB76B", "darkmagenta": "8B008B",
+	"darkolivegreen": "556B2F", "darkorange": "FF8C00", "darkorchid": "9932CC", "darkred": "8B0000",
+	"darksalmon": "E9967A", "darkseagreen": "8FBC8F", "darkslateblue": "483D8B", "darkslategray": "2F4F4F",
+	"darkturquoise": "00CED1", "darkviolet": "9400D3", "deeppink": "FF1493", "deepskyblue": "00BFFF",
+	"dimgray": "696969", "dodgerblue": "1E90FF", "firebrick": "B22222", "floralwhite": "FFFAF0",
+	"forestgreen": "228B22", "fuchsia": "FF00FF", "gainsboro": "DCDCDC", "ghostwhite": "F8F8FF",
+	"gold": "FFD700", "goldenrod": "DAA520", "gray": "808080", "green": "008000",
+	"greenyellow": "ADFF2F", "honeydew": "F0FFF0", "hotpink": "FF69B4", "indianred": "CD5C5C",
+	"indigo": "4B0082", "ivory": "FFFFF0", "khaki": "F0E68C", "lavender": "E6E6FA",
+	"lavenderblush": "FFF0F5", "lawngreen": "7CFC00", "lemonchiffon": "FFFACD", "lightblue": "ADD8E6",
+	"lightcoral": "F08080", "lightcyan": "E0FFFF", "lightgoldenrodyellow": "FAFAD2", "lightgray": "D3D3D3",
+	"lightgreen": "90EE90", "lightpink": "FFB6C1", "lightsalmon": "FFA07A", "lightseagreen": "20B2AA",
+	"lightskyblue": "87CEFA", "lightslategray": "778899", "lightsteelblue": "B0C4DE", "lightyellow": "FFFFE0",
+	"lime": "00FF00", "limegreen": "32CD32", "linen": "FAF0E6", "magenta": "FF00FF",
+	"maroon": "800000", "mediumaquamarine": "66CDAA", "mediumblue": "0000CD", "mediumorchid": "BA55D3",
+	"mediumpurple": "9370DB", "mediumseagreen": "3CB371", "mediumslateblue": "7B68EE", "mediumspringgreen": "00FA9A",
+	"mediumturquoise": "48D1CC", "mediumvioletred": "C71585", "midnightblue": "191970", "mintcream": "F5FFFA",
+	"mistyrose": "FFE4E1", "moccasin": "FFE4B5", "navajowhite": "FFDEAD", "navy": "000080",
+	"oldlace": "FDF5E6", "olive": "808000", "olivedrab": "6B8E23", "orange": "FFA500",
+	"orangered": "FF4500", "orchid": "DA70D6", "palegoldenrod": "EEE8AA", "palegreen": "98FB98",
+	"paleturquoise": "AFEEEE", "palevioletred": "DB7093", "papayawhip": "FFEFD5", "peachpuff": "FFDAB9",
+	"peru": "CD853F", "pink": "FFC0CB", "plum": "DDA0DD", "powderblue": "B0E0E6",
+	"purple": "800080", "red": "FF0000", "rosybrown": "BC8F8F", "royalblue": "4169E1",
+	"saddlebrown": "8B4513", "salmon": "FA8072", "sandybrown": "F4A460", "seagreen": "2E8B57",
+	"seashell": "FFF5EE", "sienna": "A0522D", "silver": "C0C0C0", "skyblue": "87CEEB",
+	"slateblue": "6A5ACD", "slategray": "708090", "snow": "FFFAFA", "springgreen": "00FF7F",
+	"steelblue": "4682B4", "tan": "D2B48C", "teal": "008080", "thistle": "D8BFD8",
+	"tomato": "FF6347", "turquoise": "40E0D0", "violet": "EE82EE", "wheat": "F5DEB3",
+	"white": "FFFFFF", "whitesmoke": "F5F5F5", "yellow": "FFFF00", "yellowgreen": "9ACD32",
 }
@@ -0,0 +1,107 @@
+package gopresentation
+
+// TextStyle is reusable text formatting: a font, including its color, for
+// text runs and an alignment for paragraphs. Nil fields leave the
+// formatting they would set unchanged.
+type TextStyle struct {
+	Font      *Font
+	Alignment *Alignment
+}
+
+// ShapeStyle is reusable shape formatting. Nil fields leave the
+// formatting they would set unchanged.
+type ShapeStyle struct {
+	Fill   *Fill
+	Border *Border
+	Shadow *Shadow
+}
+
+// ApplyStyle sets the font of the run to a copy of the style's font.
+func (tr *TextRun) ApplyStyle(s TextStyle) *TextRun {
+	if s.Font != nil {
+		f := *s.Font
+		tr.font = &f
+	}
+	return tr
+}
+
+// ApplyStyle sets the alignment of the paragraph to a copy of the style's
+// alignment, keeping the paragraph's list level, and the font of its text
+// runs and fields to a copy of the style's font.
+func (p *Paragraph) ApplyStyle(s TextStyle) *Paragraph {
+	if s.Alignment != nil {
+		a := *s.Alignment
+		if p.alignment != nil {
+			a.Level = p.alignment.Level
+		}
+		p.alignment = &a
+	}
+	if s.Font != nil {
+		for _, elem := range p.elements {
+			switch e := elem.(type) {
+			case *TextRun:
+				e.ApplyStyle(s)
+			case *SlideNumberField:
+				f := *s.Font
+				e.font = &f
+			}
+		}
+	}
+	return p
+}
+
+// ApplyTextStyle applies the text style to every paragraph of the shape.
+func (r *RichTextShape) ApplyTextStyle(s TextStyle) *RichTextShape {
+	for _, p := range r.paragraphs {
+		p.ApplyStyle(s)
+	}
+	return r
+}
+
+// ApplyStyle sets the fill, border and shadow of the shape to copies of
+// those of the style.
+func (b *BaseShape) ApplyStyle(s ShapeStyle) *BaseShape {
+	if s.Fill != nil {
+		f := *s.Fill
+		b.fill = &f
+	}
+	if s.Border != nil {
+		border := *s.Border
+		b.border = &border
+	}
+	if s.Shadow != nil {
+		shadow := *s.Shadow
+		b.shadow = &shadow
+	}
+	return b
+}
+
+// RegisterTextStyle registers a text style under name, replacing any
+// style registered under that name before.
+func (p *Presentation) RegisterTextStyle(name string, s TextStyle) {
+	if p.textStyles == nil {
+		p.textStyles = make(map[string]TextStyle)
+	}
+	p.textStyles[name] = s
+}
+
+// GetTextStyle returns the text style registered under name.
+func (p *Presentation) GetTextStyle(name string) (TextStyle, bool) {
+	s, ok := p.textStyles[name]
+	return s, ok
+}
+
+// RegisterShapeStyle registers a shape style under name, replacing any
+// style registered under that name before.
+func (p *Presentation) RegisterShapeStyle(name string, s ShapeStyle) {
+	if p.shapeStyles == nil {
+		p.shapeStyles = make(map[string]ShapeStyle)
+	}
+	p.shapeStyles[name] = s
+}
+
+// GetShapeStyle returns the shape style registered under name.
+func (p *Presentation) GetShapeStyle(name string) (ShapeStyle, bool) {
+	s, ok := p.shapeStyles[name]
+	return s, ok
+}
@@ -0,0 +1,235 @@
+package gopresentation
+
+import "errors"
+
+// SplitRows breaks the table into consecutive tables of at most maxRows rows
+// each. When the first row is a header row (see SetFirstRow), it is repeated
+// at the top of every resulting table and counts toward maxRows. Body cells
+// are shared with t; the repeated header cells and the other table
+// properties are deep copies, and the tables get creation IDs of their own.
+// Row spans crossing a split boundary are truncated. The receiver is not
+// modified.
+func (t *TableShape) SplitRows(maxRows int) []*TableShape {
+	header := 0
+	if t.firstRow && t.numRows > 0 {
+		header = 1
+	}
+	perPart := maxRows - header
+	if maxRows <= 0 || perPart <= 0 || t.numRows <= maxRows {
+		return []*TableShape{t}
+	}
+
+	var parts []*TableShape
+	for start := header; start < t.numRows; start += perPart {
+		end := start + perPart
+		if end > t.numRows {
+			end = t.numRows
+		}
+		part := &TableShape{
+			BaseShape: t.BaseShape.clone(),
+			numCols:   t.numCols,
+			colWidths: append([]int64(nil), t.colWidths...),
+			firstRow:  t.firstRow,
+			lastRow:   t.lastRow && end == t.numRows,
+			bandRow:   t.bandRow,
+		}
+		var srcRows []int
+		if header > 0 {
+			srcRows = append(srcRows, 0)
+		}
+		for i := start; i < end; i++ {
+			srcRows = append(srcRows, i)
+		}
+		var height int64
+		for i, src := range srcRows {
+			row := make([]*TableCell, len(t.rows[src]))
+			for j, cell := range t.rows[src] {
+				remaining := len(srcRows) - i
+				switch {
+				case header > 0 && i == 0 && len(parts) > 0:
+					row[j] = cell.clone()
+				case cell.rowSpan > remaining || (i == header && cell.vMerge):
+					c := *cell
+					if c.rowSpan > remaining {
+						c.rowSpan = remaining
+					}
+					if i == header {
+						c.vMerge = false
+					}
+					row[j] = &c
+				default:
+					row[j] = cell
+				}
+			}
+			part.rows = append(part.rows, row)
+			h := t.rowHeight(src)
+			part.rowHeights = append(part.rowHeights, h)
+			height += h
+		}
+		part.numRows = len(part.rows)
+		part.height = height
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// padRows gives every row numCols cells, adding empty ones to the rows read
+// with fewer a:tc elements than the table grid has columns.
+func (t *TableShape) padRows() {
+	for i, row := range t.rows {
+		for len(row) < t.numCols {
+			row = append(row, NewTableCell())
+		}
+		t.rows[i] = row
+	}
+}
+
+// rowHeight returns the height of row i in EMU, falling back to an even
+// share of the table height when individual row heights are unknown.
+func (t *TableShape) rowHeight(i int) int64 {
+	if len(t.rowHeights) == t.numRows && i < len(t.rowHeights) {
+		return t.rowHeights[i]
+	}
+	if t.numRows == 0 {
+		return 0
+	}
+	return t.height / int64(t.numRows)
+}
+
+// colWidth returns the width of column j in EMU, falling back to an even
+// share of the table width when individual column widths are unknown.
+func (t *TableShape) colWidth(j int) int64 {
+	if len(t.colWidths) == t.numCols && j < len(t.colWidths) {
+		return t.colWidths[j]
+	}
+	if t.numCols == 0 {
+		return 0
+	}
+	return t.width / int64(t.numCols)
+}
+
+// AutoFitRows grows the rows whose cell text does not fit, measured with
+// the layout the renderer uses: text wrapped within the cell margins. Rows
+// are never made shorter, as PowerPoint treats row heights as minimums.
+// The table height becomes the sum of the row heights. Cells with vertical
+// text are not measured.
+func (t *TableShape) AutoFitRows() *TableShape {
+	if t.numRows == 0 {
+		return t
+	}
+	heights := make([]int64, t.numRows)
+	for i := range heights {
+		heights[i] = t.rowHeight(i)
+	}
+	r := measureRenderer()
+	for i, row := range t.rows {
+		if i >= t.numRows {
+			break
+		}
+		for j, cell := range row {
+			if j >= t.numCols || cell.hMerge || cell.vMerge || vertTextRotation(cell.textDirection) != 0 {
+				continue
+			}
+			var width int64
+			for k := j; k < min(j+max(cell.colSpan, 1), t.numCols); k++ {
+				width += t.colWidth(k)
+			}
+			marL, marT, marR, marB := cell.GetMargins()
+			tw := r.emuToPixelX(width - marL - marR)
+			if tw <= 0 || len(cell.paragraphs) == 0 {
+				continue
+			}
+			_, h := r.measureParagraphs(cell.paragraphs, tw, true)
+			need := pixelToEMU(h) + marT + marB
+
+			// A spanning cell grows the last row it spans
+			last := min(i+max(cell.rowSpan, 1), t.numRows) - 1
+			var have int64
+			for k := i; k <= last; k++ {
+				have += heights[k]
+			}
+			if need > have {
+				heights[last] += need - have
+			}
+		}
+	}
+	t.rowHeights = heights
+	t.height = 0
+	for _, h := range heights {
+		t.height += h
+	}
+	return t
+}
+
+// SplitTableAcrossSlides splits table (which must be on slide) into parts of
+// at most maxRows rows, repeating the header row. The first part replaces the
+// table on slide and keeps its creation ID; each further part is placed on a
+// continuation slide inserted after it that carries deep copies of the
+// slide's other shapes. Internal links to the slides after slide are
+// renumbered. It returns the continuation slides.
+func (p *Presentation) SplitTableAcrossSlides(slide *Slide, table *TableShape, maxRows int) ([]*Slide, error) {
+	slideIdx := -1
+	for i, s := range p.slides {
+		if s == slide {
+			slideIdx = i
+			break
+		}
+	}
+	if slideIdx < 0 {
+		return nil, errors.New("slide not found in presentation")
+	}
+	shapeIdx := -1
+	for i, s := range slide.shapes {
+		if s == table {
+			shapeIdx = i
+			break
+		}
+	}
+	if shapeIdx < 0 {
+		return nil, errors.New("table not found on slide")
+	}
+
+	parts := table.SplitRows(maxRows)
+	if len(parts) > 1 {
+		parts[0].creationID = table.creationID
+	}
+	slide.shapes[shapeIdx] = parts[0]
+
+	added := make([]*Slide, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		dst := newSlide()
+		dst.name = slide.name
+		dst.visible = slide.visible
+		if slide.background != nil {
+			bg := *slide.background
+			dst.background = &bg
+		}
+		if slide.backgroundRef != nil {
+			ref := *slide.backgroundRef
+			dst.backgroundRef = &ref
+		}
+		dst.inheritedBackground = slide.inheritedBackground
+		dst.shapes = make([]Shape, len(slide.shapes))
+		for i, s := range slide.shapes {
+			if i == shapeIdx {
+				dst.shapes[i] = part
+			} else {
+				dst.shapes[i] = cloneShape(s)
+			}
+		}
+		added = append(added, dst)
+	}
+
+	// Insert the continuation slides after the source slide
+	pos := slideIdx + 1
+	newIndex := make([]int, len(p.slides))
+	for i := range newIndex {
+		newIndex[i] = i
+		if i >= pos {
+			newIndex[i] += len(added)
+		}
+	}
+	p.slides = append(p.slides[:pos], append(added, p.slides[pos:]...)...)
+	remapSlideLinks(p.slides, newIndex)
+	return added, nil
+}
@@ -0,0 +1,177 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tags are named string values attached to slides and shapes, stored in
+// ppt/tags parts. Add-ins and automation use them to recognize slides and
+// shapes across saves. As in PowerPoint, tag names are case-insensitive and
+// stored in upper case.
+
+const (
+	relTypeTags = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/tags"
+	ctTags      = "application/vnd.openxmlformats-officedocument.presentationml.tags+xml"
+)
+
+// setTag sets or, for an empty name, ignores tag name in *tags.
+func setTag(tags *map[string]string, name, value string) {
+	name = strings.ToUpper(name)
+	if name == "" {
+		return
+	}
+	if *tags == nil {
+		*tags = make(map[string]string)
+	}
+	(*tags)[name] = value
+}
+
+// copyTags returns a copy of tags, or nil when there are none.
+func copyTags(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for k, v := range tags {
+		out[k] = v
+	}
+	return out
+}
+
+// SetTag sets the value of tag name on the shape.
+func (b *BaseShape) SetTag(name, value string) *BaseShape {
+	setTag(&b.tags, name, value)
+	return b
+}
+
+// GetTag returns the value of tag name on the shape, or "" when it is not
+// set.
+func (b *BaseShape) GetTag(name string) string { return b.tags[strings.ToUpper(name)] }
+
+// RemoveTag removes tag name from the shape.
+func (b *BaseShape) RemoveTag(name string) *BaseShape {
+	delete(b.tags, strings.ToUpper(name))
+	return b
+}
+
+// GetTags returns a copy of the tags of the shape.
+func (b *BaseShape) GetTags() map[string]string { return copyTags(b.tags) }
+
+// SetTag sets the value of tag name on the slide.
+func (s *Slide) SetTag(name, value string) {
+	setTag(&s.tags, name, value)
+}
+
+// GetTag returns the value of tag name on the slide, or "" when it is not
+// set.
+func (s *Slide) GetTag(name string) string { return s.tags[strings.ToUpper(name)] }
+
+// RemoveTag removes tag name from the slide.
+func (s *Slide) RemoveTag(name string) {
+	delete(s.tags, strings.ToUpper(name))
+}
+
+// GetTags returns a copy of the tags of the slide.
+func (s *Slide) GetTags() map[string]string { return copyTags(s.tags) }
+
+// tagsKey is the relationship registry key of the tags of a slide or shape.
+type tagsKey struct{ owner any }
+
+// tagListXML returns the content of a tags part, with the tags sorted by
+// name.
+func tagListXML(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:tagLst xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
+`, nsDrawingML, nsOfficeDocRels, nsPresentationML)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "  <p:tag name=\"%s\" val=\"%s\"/>\n", xmlEscape(name), xmlEscape(tags[name]))
+	}
+	sb.WriteString("</p:tagLst>")
+	return sb.String()
+}
+
+// addTagsRel registers a tags part holding the tags of owner in rels, if
+// it has any. Parts are numbered across the package in registration order.
+func (w *PPTXWriter) addTagsRel(rels *relRegistry, owner any, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	w.tagParts = append(w.tagParts, tagListXML(tags))
+	rels.add(tagsKey{owner}, relTypeTags, fmt.Sprintf("../tags/tag%d.xml", len(w.tagParts)))
+}
+
+// custDataXML returns the custDataLst referring to the tags of owner, or ""
+// when it has none.
+func (w *PPTXWriter) custDataXML(owner any) string {
+	rid := w.slideRels.id(tagsKey{owner})
+	if rid == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<p:custDataLst><p:tags r:id="%s"/></p:custDataLst>`, rid)
+}
+
+// nvPrXML returns the p:nvPr element of a shape, holding the shape's tags
+// and extension list.
+func (w *PPTXWriter) nvPrXML(b *BaseShape) string {
+	if content := w.custDataXML(b) + b.extLst.nvPr; content != "" {
+		return "<p:nvPr>" + content + "</p:nvPr>"
+	}
+	return "<p:nvPr/>"
+}
+
+// writeTagParts writes the tags parts registered during the write.
+func (w *PPTXWriter) writeTagParts(zw *zip.Writer) error {
+	for i, content := range w.tagParts {
+		if err := w.writeRawPart(zw, fmt.Sprintf("ppt/tags/tag%d.xml", i+1), ctTags, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTagList returns the tags of a tags part.
+func parseTagList(data []byte) map[string]string {
+	var tags map[string]string
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		if t, ok := token.(xml.StartElement); ok && t.Name.Local == "tag" {
+			setTag(&tags, attrValue(t.Attr, "name"), attrValue(t.Attr, "val"))
+		}
+	}
+	return tags
+}
+
+// readTags returns the tags of the tags part that relationship rid of the
+// part at partPath refers to.
+func (r *PPTXReader) readTags(zr *zip.Reader, rels []xmlRelForRead, partPath, rid string) map[string]string {
+	for _, rel := range rels {
+		if rel.ID != rid || rel.Type != relTypeTags {
+			continue
+		}
+		target := rel.Target
+		if !strings.HasPrefix(target, "ppt/") {
+			dir := strings.TrimSuffix(partPath, "/"+lastPathComponent(partPath))
+			target = resolveRelativePath(dir, target)
+		}
+		data, err := readFileFromZip(zr, target)
+		if err != nil {
+			return nil
+		}
+		return parseTagList(data)
+	}
+	return nil
+}
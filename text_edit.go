@@ -0,0 +1,134 @@
+package gopresentation
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// Clone returns a deep copy of the paragraph: its text runs, fonts,
+// hyperlinks, alignment and bullet are copied, so that changing the copy
+// leaves the paragraph unchanged.
+func (p *Paragraph) Clone() *Paragraph {
+	c := *p
+	c.elements = make([]ParagraphElement, len(p.elements))
+	for i, e := range p.elements {
+		c.elements[i] = cloneElement(e)
+	}
+	if p.alignment != nil {
+		a := *p.alignment
+		c.alignment = &a
+	}
+	if p.bullet != nil {
+		b := *p.bullet
+		if b.Color != nil {
+			b.Color = cloneColor(b.Color)
+		}
+		c.bullet = &b
+	}
+	return &c
+}
+
+// cloneElement returns a deep copy of a paragraph element.
+func cloneElement(e ParagraphElement) ParagraphElement {
+	switch e := e.(type) {
+	case *TextRun:
+		return e.Clone()
+	case *BreakElement:
+		return &BreakElement{}
+	case *SlideNumberField:
+		return &SlideNumberField{font: cloneFont(e.font)}
+	}
+	return e
+}
+
+// Clone returns a copy of the text run with its own font and hyperlink.
+func (tr *TextRun) Clone() *TextRun {
+	c := &TextRun{text: tr.text, font: cloneFont(tr.font)}
+	if tr.hyperlink != nil {
+		h := *tr.hyperlink
+		c.hyperlink = &h
+	}
+	return c
+}
+
+// SplitAt splits the text run at the character (not byte) index idx: the
+// run keeps the text before idx and the returned run, which has a copy of
+// its font and hyperlink, holds the rest. idx is clamped to the length of
+// the text. The returned run is not part of any paragraph; see
+// Paragraph.InsertElementAt.
+func (tr *TextRun) SplitAt(idx int) *TextRun {
+	offset := len(tr.text)
+	if idx <= 0 {
+		offset = 0
+	} else if idx < utf8.RuneCountInString(tr.text) {
+		offset = 0
+		for range idx {
+			_, size := utf8.DecodeRuneInString(tr.text[offset:])
+			offset += size
+		}
+	}
+	tail := tr.Clone()
+	tail.text = tr.text[offset:]
+	tr.text = tr.text[:offset]
+	return tail
+}
+
+// InsertElementAt inserts e before the element at index i, or appends it
+// when i is at least the number of elements.
+func (p *Paragraph) InsertElementAt(i int, e ParagraphElement) {
+	i = max(0, min(i, len(p.elements)))
+	p.elements = slices.Insert(p.elements, i, e)
+}
+
+// RemoveElementAt removes the element at index i. Out of range indexes are
+// ignored.
+func (p *Paragraph) RemoveElementAt(i int) {
+	if i >= 0 && i < len(p.elements) {
+		p.elements = slices.Delete(p.elements, i, i+1)
+	}
+}
+
+// InsertParagraphAt inserts a new paragraph before the paragraph at index
+// i, or appends it when i is at least the number of paragraphs, and makes
+// it active.
+func (r *RichTextShape) InsertParagraphAt(i int) *Paragraph {
+	p := NewParagraph()
+	r.InsertParagraph(i, p)
+	return p
+}
+
+// InsertParagraph inserts p, for example a paragraph copied with
+// Paragraph.Clone, before the paragraph at index i, or appends it when i is
+// at least the number of paragraphs, and makes it active.
+func (r *RichTextShape) InsertParagraph(i int, p *Paragraph) {
+	i = max(0, min(i, len(r.paragraphs)))
+	r.paragraphs = slices.Insert(r.paragraphs, i, p)
+	r.activeParagraph = i
+}
+
+// RemoveParagraphAt removes the paragraph at index i. Out of range indexes
+// are ignored.
+func (r *RichTextShape) RemoveParagraphAt(i int) {
+	if i < 0 || i >= len(r.paragraphs) {
+		return
+	}
+	r.paragraphs = slices.Delete(r.paragraphs, i, i+1)
+	if r.activeParagraph >= len(r.paragraphs) {
+		r.activeParagraph = max(len(r.paragraphs)-1, 0)
+	}
+}
+
+// cloneFont returns a copy of f, or nil.
+func cloneFont(f *Font) *Font {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	return &c
+}
+
+// cloneColor returns a copy of c.
+func cloneColor(c *Color) *Color {
+	d := *c
+	return &d
+}
@@ -0,0 +1,101 @@
+package gopresentation
+
+import (
+	"image"
+	"math"
+	"strings"
+	"sync"
+)
+
+// measureScale is the pixels-per-EMU resolution text is laid out at when
+// measuring without rendering (384 DPI), fine enough that rounding to whole
+// pixels does not affect fit decisions.
+const measureScale = 384.0 / emuPerInch
+
+var (
+	measureFontsMu sync.Mutex
+	measureFonts   *FontCache
+)
+
+// SetMeasureFontCache sets the font cache used by MeasureText and
+// RichTextShape.FitsIn. By default a FontCache over the system font
+// directories is created on first use.
+func SetMeasureFontCache(fc *FontCache) {
+	measureFontsMu.Lock()
+	measureFonts = fc
+	measureFontsMu.Unlock()
+}
+
+// measureRenderer returns a renderer that lays out text at measureScale
+// using the measurement font cache.
+func measureRenderer() *renderer {
+	measureFontsMu.Lock()
+	if measureFonts == nil {
+		measureFonts = NewFontCache()
+	}
+	fc := measureFonts
+	measureFontsMu.Unlock()
+	return &renderer{
+		img:       image.NewRGBA(image.Rect(0, 0, 1, 1)),
+		scaleX:    measureScale,
+		scaleY:    measureScale,
+		fontCache: fc,
+		dpi:       96,
+	}
+}
+
+// MeasureText lays out text in font f wrapped to width (in EMU) the way
+// slides are rendered and returns the number of lines and the total height
+// in EMU. Newlines in text start new paragraphs. A width of zero or less
+// disables wrapping. A nil font uses the default font.
+func MeasureText(text string, f *Font, width int64) (lines int, height int64) {
+	if f == nil {
+		f = NewFont()
+	}
+	var paras []*Paragraph
+	for _, line := range strings.Split(text, "\n") {
+		para := NewParagraph()
+		para.CreateTextRun(strings.TrimSuffix(line, "\r")).SetFont(f)
+		paras = append(paras, para)
+	}
+	r := measureRenderer()
+	n, h := r.measureParagraphs(paras, r.emuToPixelX(width), width > 0)
+	return n, pixelToEMU(h)
+}
+
+// FitsIn reports whether the text of the shape fits, without overflowing
+// vertically, in a box of the given size in EMU. The shape's insets, word
+// wrap, autofit font scale and list styles are taken into account, so
+// generation code can pick box sizes or font sizes before rendering.
+func (s *RichTextShape) FitsIn(width, height int64) bool {
+	lIns, rIns, tIns, bIns := int64(91440), int64(91440), int64(45720), int64(45720)
+	if s.insetsSet {
+		lIns, rIns, tIns, bIns = s.insetLeft, s.insetRight, s.insetTop, s.insetBottom
+	}
+	r := measureRenderer()
+	tw := r.emuToPixelX(width - lIns - rIns)
+	th := r.emuToPixelY(height - tIns - bIns)
+	if len(s.paragraphs) == 0 {
+		return th >= 0
+	}
+	if tw <= 0 || th <= 0 {
+		return false
+	}
+	if s.fontScale > 0 && s.fontScale != 100000 {
+		r.fontScale = float64(s.fontScale) / 100000.0
+	}
+	paras := resolveListStyle(&s.listStyle, s.paragraphs, false)
+	_, h := r.measureParagraphs(paras, tw, s.wordWrap)
+	if h > th {
+		return false
+	}
+	if !s.wordWrap {
+		return r.measureMaxLineWidth(paras, tw, false) <= tw
+	}
+	return true
+}
+
+// pixelToEMU converts a length measured at measureScale back to EMU.
+func pixelToEMU(px int) int64 {
+	return int64(math.Round(float64(px) / measureScale))
+}
@@ -0,0 +1,120 @@
+package gopresentation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TextNormalization selects the typographic clean-ups applied to the text
+// of shapes, tables and notes as the presentation is written. The
+// presentation itself is not changed.
+type TextNormalization struct {
+	// SmartQuotes turns straight quotes into curly quotes: opening quotes
+	// at the start of a paragraph or after a space or opening bracket,
+	// closing quotes elsewhere, so that "don't" gets an apostrophe (’).
+	SmartQuotes bool
+	// CollapseSpaces replaces runs of spaces with a single space.
+	CollapseSpaces bool
+	// UnitSpaces replaces the space between a number and a unit, as in
+	// "10 km" or "25 %", with a non-breaking space so that they are not
+	// split across lines.
+	UnitSpaces bool
+	// Ellipses replaces three dots with an ellipsis (…).
+	Ellipses bool
+}
+
+// DefaultTextNormalization returns a TextNormalization with every
+// clean-up enabled.
+func DefaultTextNormalization() *TextNormalization {
+	return &TextNormalization{
+		SmartQuotes:    true,
+		CollapseSpaces: true,
+		UnitSpaces:     true,
+		Ellipses:       true,
+	}
+}
+
+// normalizationUnits are the units UnitSpaces keeps on the line of their
+// number.
+var normalizationUnits = map[string]bool{
+	"%": true, "‰": true, "°": true, "°C": true, "°F": true, "K": true,
+	"mm": true, "cm": true, "m": true, "km": true, "ft": true, "mi": true,
+	"mg": true, "g": true, "kg": true, "t": true, "lb": true, "oz": true,
+	"ml": true, "l": true, "L": true,
+	"ms": true, "s": true, "min": true, "h": true,
+	"Hz": true, "kHz": true, "MHz": true, "GHz": true,
+	"W": true, "kW": true, "MW": true, "kWh": true, "V": true, "A": true,
+	"B": true, "KB": true, "kB": true, "MB": true, "GB": true, "TB": true,
+	"px": true, "pt": true,
+	"€": true, "£": true, "¥": true,
+}
+
+// normalizeText applies the writer's TextNormalization to s, the text of
+// the next run of the paragraph being written. Quotes and spaces at the
+// start of s depend on the end of the previous run, which is kept until
+// resetNormalization starts a new paragraph.
+func (w *PPTXWriter) normalizeText(s string) string {
+	n := w.options().TextNormalization
+	if n == nil || s == "" {
+		return s
+	}
+	if n.Ellipses {
+		s = strings.ReplaceAll(s, "...", "…")
+	}
+
+	runes := []rune(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	prev := w.normPrev
+	for i, r := range runes {
+		switch {
+		case r == ' ' && n.CollapseSpaces && prev == ' ':
+			continue
+		case r == ' ' && n.UnitSpaces && unicode.IsDigit(prev) && isUnitAt(runes, i+1):
+			r = '\u00a0' // no-break space
+		case r == '"' && n.SmartQuotes:
+			r = '”'
+			if opensQuote(prev) {
+				r = '“'
+			}
+		case r == '\'' && n.SmartQuotes:
+			r = '’'
+			if opensQuote(prev) {
+				r = '‘'
+			}
+		}
+		sb.WriteRune(r)
+		prev = r
+	}
+	w.normPrev = prev
+	return sb.String()
+}
+
+// resetNormalization starts a new paragraph for normalizeText.
+func (w *PPTXWriter) resetNormalization() {
+	w.normPrev = 0
+}
+
+// opensQuote reports whether a quote after prev is an opening quote.
+func opensQuote(prev rune) bool {
+	if prev == 0 || unicode.IsSpace(prev) {
+		return true
+	}
+	switch prev {
+	case '(', '[', '{', '“', '‘', '—', '–', '/':
+		return true
+	}
+	return false
+}
+
+// isUnitAt reports whether runes[i:] starts with one of the
+// normalizationUnits followed by the end of the text or a character that
+// cannot continue a word.
+func isUnitAt(runes []rune, i int) bool {
+	end := i
+	for end < len(runes) && !unicode.IsSpace(runes[end]) && !unicode.IsDigit(runes[end]) &&
+		(end == i || unicode.IsLetter(runes[end])) {
+		end++
+	}
+	return end > i && normalizationUnits[string(runes[i:end])]
+}
@@ -0,0 +1,157 @@
+package gopresentation
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextHinting is how the renderer fits the outlines of glyphs to the pixel
+// grid.
+type TextHinting int
+
+const (
+	// TextHintingFull rounds glyph outlines and advances to whole pixels,
+	// which keeps small text sharp. It is the default.
+	TextHintingFull TextHinting = iota
+	// TextHintingNone draws glyphs unhinted, at the positions the line
+	// layout measures, which keeps the shapes and spacing of text closer to
+	// PowerPoint's at the cost of softer small text.
+	TextHintingNone
+)
+
+// srgbToLinear maps an 8-bit sRGB value to linear light, and linearToSRGB
+// maps linear light, in 1/4095 steps, back to 8-bit sRGB.
+var (
+	srgbToLinear [256]float32
+	linearToSRGB [4096]uint8
+)
+
+func init() {
+	for i := range srgbToLinear {
+		v := float64(i) / 255
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		srgbToLinear[i] = float32(v)
+	}
+	for i := range linearToSRGB {
+		v := float64(i) / 4095
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+		linearToSRGB[i] = uint8(math.Round(v * 255))
+	}
+}
+
+// toSRGB returns the 8-bit sRGB value of the linear light v.
+func toSRGB(v float32) uint8 {
+	return linearToSRGB[int(min(max(v, 0), 1)*4095+0.5)]
+}
+
+// drawText draws text in face and color c with its baseline origin at
+// (x, y), kerning each pair of glyphs as font.Drawer does. With the
+// GammaCorrectText option the glyphs are blended in linear light.
+func (r *renderer) drawText(face font.Face, x, y int, text string, c color.RGBA) {
+	if !r.gammaText {
+		d := &font.Drawer{Dst: r.img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+		d.DrawString(text)
+		return
+	}
+	dot := fixed.P(x, y)
+	prev := rune(-1)
+	for _, ch := range text {
+		if prev >= 0 {
+			dot.X += face.Kern(prev, ch)
+		}
+		dr, mask, maskp, advance, _ := face.Glyph(dot, ch)
+		if !dr.Empty() {
+			r.blendGlyphLinear(dr, mask, maskp, c)
+		}
+		dot.X += advance
+		prev = ch
+	}
+}
+
+// blendGlyphLinear composites the color c over the rectangle dr of r.img
+// through the coverage of mask, starting at maskp, in linear light. c is
+// premultiplied, as color.RGBA is.
+func (r *renderer) blendGlyphLinear(dr image.Rectangle, mask image.Image, maskp image.Point, c color.RGBA) {
+	if c.A == 0 {
+		return
+	}
+	clipped := dr.Intersect(r.img.Bounds())
+	if clipped.Empty() {
+		return
+	}
+	maskp = maskp.Add(clipped.Min.Sub(dr.Min))
+	alpha, _ := mask.(*image.Alpha)
+
+	// The straight color of the text in linear light
+	srcA := float32(c.A) / 255
+	sr := srgbToLinear[uint8(min(255, int(c.R)*255/int(c.A)))]
+	sg := srgbToLinear[uint8(min(255, int(c.G)*255/int(c.A)))]
+	sb := srgbToLinear[uint8(min(255, int(c.B)*255/int(c.A)))]
+	k := textContrast(sr, sg, sb)
+
+	img := r.img
+	for y := clipped.Min.Y; y < clipped.Max.Y; y++ {
+		my := maskp.Y + y - clipped.Min.Y
+		for x := clipped.Min.X; x < clipped.Max.X; x++ {
+			mx := maskp.X + x - clipped.Min.X
+			var cov float32
+			if alpha != nil {
+				cov = float32(alpha.Pix[alpha.PixOffset(mx, my)]) / 255
+			} else {
+				_, _, _, ma := mask.At(mx, my).RGBA()
+				cov = float32(ma) / 0xffff
+			}
+			a := cov * (k + 1) / (cov*k + 1) * srcA
+			if a <= 0 {
+				continue
+			}
+			i := img.PixOffset(x, y)
+			p := img.Pix[i : i+4 : i+4]
+			outA := a + float32(p[3])/255*(1-a)
+			if outA <= 0 {
+				continue
+			}
+			p[0] = blendLinear(sr, p[0], p[3], a, outA)
+			p[1] = blendLinear(sg, p[1], p[3], a, outA)
+			p[2] = blendLinear(sb, p[2], p[3], a, outA)
+			p[3] = uint8(outA*255 + 0.5)
+		}
+	}
+}
+
+// darkTextContrast is how much the coverage of the edges of black text is
+// enhanced. Blended in linear light, dark text on a light background looks
+// thinner than light text on a dark one; enhancing its coverage, as system
+// text renderers do, gives both the same weight.
+const darkTextContrast = 2.0
+
+// textContrast returns the contrast enhancement of the coverage of text of
+// the linear color (r, g, b): darkTextContrast for black text, falling to
+// none for text lighter than mid-gray.
+func textContrast(r, g, b float32) float32 {
+	brightness := toSRGB(0.30*r + 0.59*g + 0.11*b)
+	return darkTextContrast * min(max(4*(0.75-float32(brightness)/255), 0), 1)
+}
+
+// blendLinear returns the premultiplied channel of the destination
+// dst, of alpha dstA, with the linear channel src of alpha a over it, the
+// result having alpha outA.
+func blendLinear(src float32, dst, dstA uint8, a, outA float32) uint8 {
+	var d float32
+	if dstA > 0 {
+		d = srgbToLinear[uint8(min(255, int(dst)*255/int(dstA)))] * float32(dstA) / 255
+	}
+	return uint8(float32(toSRGB((src*a+d*(1-a))/outA))*outA + 0.5)
+}
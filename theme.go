@@ -0,0 +1,127 @@
+package gopresentation
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Theme represents a presentation theme (a:theme): its color scheme and
+// font scheme.
+type Theme struct {
+	Name string
+
+	// ColorSchemeName is the name of the color scheme. Colors maps scheme
+	// slots (dk1, lt1, dk2, lt2, accent1..accent6, hlink, folHlink) to colors.
+	ColorSchemeName string
+	Colors          map[string]Color
+
+	// FontSchemeName is the name of the font scheme. MajorFont is used for
+	// headings and MinorFont for body text.
+	FontSchemeName string
+	MajorFont      ThemeFont
+	MinorFont      ThemeFont
+}
+
+// ThemeFont holds the typefaces of a theme font for each script.
+type ThemeFont struct {
+	Latin         string
+	EastAsian     string
+	ComplexScript string
+}
+
+// GetColor returns the color of a scheme slot such as "accent1". The
+// aliases tx1, bg1, tx2 and bg2 resolve to dk1, lt1, dk2 and lt2.
+func (t *Theme) GetColor(slot string) (Color, bool) {
+	switch slot {
+	case "tx1":
+		slot = "dk1"
+	case "bg1":
+		slot = "lt1"
+	case "tx2":
+		slot = "dk2"
+	case "bg2":
+		slot = "lt2"
+	}
+	c, ok := t.Colors[slot]
+	return c, ok
+}
+
+// parseThemeXML reads the name, color scheme and font scheme of a theme part.
+func parseThemeXML(data []byte) *Theme {
+	theme := &Theme{Colors: make(map[string]Color)}
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+
+	var currentSlot string
+	var currentFont *ThemeFont
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "theme":
+				theme.Name = attrValue(t.Attr, "name")
+			case "clrScheme":
+				theme.ColorSchemeName = attrValue(t.Attr, "name")
+			case "fontScheme":
+				theme.FontSchemeName = attrValue(t.Attr, "name")
+			case "dk1", "dk2", "lt1", "lt2",
+				"accent1", "accent2", "accent3", "accent4", "accent5", "accent6",
+				"hlink", "folHlink":
+				currentSlot = t.Name.Local
+			case "srgbClr":
+				if currentSlot != "" {
+					theme.Colors[currentSlot] = NewColor("FF" + strings.ToUpper(attrValue(t.Attr, "val")))
+				}
+			case "sysClr":
+				if currentSlot != "" {
+					if c, ok := systemColor(attrValue(t.Attr, "val"), attrValue(t.Attr, "lastClr")); ok {
+						theme.Colors[currentSlot] = c
+					}
+				}
+			case "majorFont":
+				currentFont = &theme.MajorFont
+			case "minorFont":
+				currentFont = &theme.MinorFont
+			case "latin":
+				if currentFont != nil {
+					currentFont.Latin = attrValue(t.Attr, "typeface")
+				}
+			case "ea":
+				if currentFont != nil {
+					currentFont.EastAsian = attrValue(t.Attr, "typeface")
+				}
+			case "cs":
+				if currentFont != nil {
+					currentFont.ComplexScript = attrValue(t.Attr, "typeface")
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "dk1", "dk2", "lt1", "lt2",
+				"accent1", "accent2", "accent3", "accent4", "accent5", "accent6",
+				"hlink", "folHlink":
+				currentSlot = ""
+			case "majorFont", "minorFont":
+				currentFont = nil
+			case "fontScheme":
+				// The format scheme that follows holds no scheme data
+				return theme
+			}
+		}
+	}
+	return theme
+}
+
+// attrValue returns the value of the attribute with the given local name.
+func attrValue(attrs []xml.Attr, local string) string {
+	for _, attr := range attrs {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
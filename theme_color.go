@@ -0,0 +1,164 @@
+package gopresentation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThemeColor is a slot of the theme color scheme. Colors that use a slot
+// are written as references to it, so changing the theme recolors them.
+type ThemeColor string
+
+const (
+	ThemeColorDark1             ThemeColor = "dk1"
+	ThemeColorLight1            ThemeColor = "lt1"
+	ThemeColorDark2             ThemeColor = "dk2"
+	ThemeColorLight2            ThemeColor = "lt2"
+	ThemeColorAccent1           ThemeColor = "accent1"
+	ThemeColorAccent2           ThemeColor = "accent2"
+	ThemeColorAccent3           ThemeColor = "accent3"
+	ThemeColorAccent4           ThemeColor = "accent4"
+	ThemeColorAccent5           ThemeColor = "accent5"
+	ThemeColorAccent6           ThemeColor = "accent6"
+	ThemeColorHyperlink         ThemeColor = "hlink"
+	ThemeColorFollowedHyperlink ThemeColor = "folHlink"
+
+	// Text and background aliases of the dark and light slots.
+	ThemeColorText1       ThemeColor = "tx1"
+	ThemeColorBackground1 ThemeColor = "bg1"
+	ThemeColorText2       ThemeColor = "tx2"
+	ThemeColorBackground2 ThemeColor = "bg2"
+)
+
+// themeColorSlots lists the slots of a color scheme in schema order.
+var themeColorSlots = []ThemeColor{
+	ThemeColorDark1, ThemeColorLight1, ThemeColorDark2, ThemeColorLight2,
+	ThemeColorAccent1, ThemeColorAccent2, ThemeColorAccent3,
+	ThemeColorAccent4, ThemeColorAccent5, ThemeColorAccent6,
+	ThemeColorHyperlink, ThemeColorFollowedHyperlink,
+}
+
+// defaultThemeColorXML holds the colors of the default Office theme as
+// written in its color scheme.
+var defaultThemeColorXML = map[ThemeColor]string{
+	ThemeColorDark1:             `<a:sysClr val="windowText" lastClr="000000"/>`,
+	ThemeColorLight1:            `<a:sysClr val="window" lastClr="FFFFFF"/>`,
+	ThemeColorDark2:             `<a:srgbClr val="44546A"/>`,
+	ThemeColorLight2:            `<a:srgbClr val="E7E6E6"/>`,
+	ThemeColorAccent1:           `<a:srgbClr val="4472C4"/>`,
+	ThemeColorAccent2:           `<a:srgbClr val="ED7D31"/>`,
+	ThemeColorAccent3:           `<a:srgbClr val="A5A5A5"/>`,
+	ThemeColorAccent4:           `<a:srgbClr val="FFC000"/>`,
+	ThemeColorAccent5:           `<a:srgbClr val="5B9BD5"/>`,
+	ThemeColorAccent6:           `<a:srgbClr val="70AD47"/>`,
+	ThemeColorHyperlink:         `<a:srgbClr val="0563C1"/>`,
+	ThemeColorFollowedHyperlink: `<a:srgbClr val="954F72"/>`,
+}
+
+// defaultThemeColors holds the ARGB values of the default Office theme.
+var defaultThemeColors = map[ThemeColor]string{
+	ThemeColorDark1:             "FF000000",
+	ThemeColorLight1:            "FFFFFFFF",
+	ThemeColorDark2:             "FF44546A",
+	ThemeColorLight2:            "FFE7E6E6",
+	ThemeColorAccent1:           "FF4472C4",
+	ThemeColorAccent2:           "FFED7D31",
+	ThemeColorAccent3:           "FFA5A5A5",
+	ThemeColorAccent4:           "FFFFC000",
+	ThemeColorAccent5:           "FF5B9BD5",
+	ThemeColorAccent6:           "FF70AD47",
+	ThemeColorHyperlink:         "FF0563C1",
+	ThemeColorFollowedHyperlink: "FF954F72",
+}
+
+// baseSlot returns the dark or light slot a text or background alias
+// stands for, and other slots unchanged.
+func (t ThemeColor) baseSlot() ThemeColor {
+	switch t {
+	case ThemeColorText1:
+		return ThemeColorDark1
+	case ThemeColorBackground1:
+		return ThemeColorLight1
+	case ThemeColorText2:
+		return ThemeColorDark2
+	case ThemeColorBackground2:
+		return ThemeColorLight2
+	}
+	return t
+}
+
+// NewThemeColor creates a color that refers to a theme slot. It is
+// written as a:schemeClr and rendered with the presentation's theme; its
+// ARGB holds the slot's value in the default theme. Unknown slots resolve
+// to black.
+func NewThemeColor(slot ThemeColor) Color {
+	argb, ok := defaultThemeColors[slot.baseSlot()]
+	if !ok {
+		argb = "FF000000"
+	}
+	return Color{ARGB: argb, Scheme: string(slot)}
+}
+
+// UseThemeColor sets the font color to a theme slot.
+func (f *Font) UseThemeColor(slot ThemeColor) *Font {
+	f.Color = NewThemeColor(slot)
+	return f
+}
+
+// UseThemeColor sets the fill to a solid fill of a theme slot.
+func (f *Fill) UseThemeColor(slot ThemeColor) *Fill {
+	return f.SetSolid(NewThemeColor(slot))
+}
+
+// UseThemeColor sets the border color to a theme slot.
+func (b *Border) UseThemeColor(slot ThemeColor) *Border {
+	b.Color = NewThemeColor(slot)
+	return b
+}
+
+// SetThemeColor sets the color of a theme slot. The theme written with the
+// presentation uses it, and colors referring to the slot render with it.
+func (p *Presentation) SetThemeColor(slot ThemeColor, c Color) {
+	if p.themeColors == nil {
+		p.themeColors = make(map[string]string)
+	}
+	argb := c.Resolve().ARGB
+	base := slot.baseSlot()
+	p.themeColors[string(base)] = argb
+	switch base {
+	case ThemeColorDark1:
+		p.themeColors[string(ThemeColorText1)] = argb
+	case ThemeColorLight1:
+		p.themeColors[string(ThemeColorBackground1)] = argb
+	case ThemeColorDark2:
+		p.themeColors[string(ThemeColorText2)] = argb
+	case ThemeColorLight2:
+		p.themeColors[string(ThemeColorBackground2)] = argb
+	}
+}
+
+// GetThemeColor returns the color of a theme slot: the color set with
+// SetThemeColor or read from the presentation's theme, or else the default
+// Office theme color.
+func (p *Presentation) GetThemeColor(slot ThemeColor) Color {
+	if argb, ok := p.themeColors[string(slot.baseSlot())]; ok && argb != "" {
+		return NewColor(argb)
+	}
+	return Color{ARGB: NewThemeColor(slot).ARGB}
+}
+
+// themeColorSchemeXML returns the slots of the written color scheme: the
+// presentation's theme colors, and the default Office theme for the rest
+// and for the colors that are the default ones, so that a default theme
+// read back is written unchanged.
+func (p *Presentation) themeColorSchemeXML() string {
+	var sb strings.Builder
+	for _, slot := range themeColorSlots {
+		clr := defaultThemeColorXML[slot]
+		if argb, ok := p.themeColors[string(slot)]; ok && isValidARGB(argb) && argb != defaultThemeColors[slot] {
+			clr = fmt.Sprintf(`<a:srgbClr val="%s"/>`, argb[2:])
+		}
+		fmt.Fprintf(&sb, "      <a:%s>%s</a:%s>\n", slot, clr, slot)
+	}
+	return sb.String()
+}
@@ -38,6 +38,11 @@ func (p *Presentation) Validate() error {
 				errs = append(errs, prefix+": "+e)
 			}
 		}
+		forEachHyperlink(slide.shapes, func(h *Hyperlink) {
+			if h.IsInternal && (h.SlideNumber <= 0 || h.SlideNumber > len(p.slides)) {
+				errs = append(errs, fmt.Sprintf("%s: hyperlink to missing slide %d", prefix, h.SlideNumber))
+			}
+		})
 	}
 
 	if len(errs) == 0 {
@@ -63,7 +68,9 @@ func validateSlide(s *Slide) []string {
 
 		switch sh := shape.(type) {
 		case *DrawingShape:
-			if sh.data == nil && sh.path == "" {
+			if sh.missingMedia != "" {
+				errs = append(errs, prefix+": media part "+sh.missingMedia+" was missing when read")
+			} else if sh.data == nil && sh.path == "" {
 				errs = append(errs, prefix+": drawing shape has no image data or path")
 			}
 			if sh.mimeType != "" && !isValidImageMime(sh.mimeType) {
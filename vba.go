@@ -0,0 +1,55 @@
+package gopresentation
+
+// VBAProject is the VBA project of a macro-enabled presentation. It is
+// kept as stored, together with the parts it relates to such as its
+// digital signature, so that macros survive reading and writing.
+type VBAProject struct {
+	data  []byte
+	parts []vbaPart
+}
+
+// vbaPart is a part related from the VBA project.
+type vbaPart struct {
+	name        string // part name, such as "ppt/vbaProjectSignature.bin"
+	target      string // relationship target, relative to the project
+	relType     string
+	contentType string
+	data        []byte
+}
+
+// NewVBAProject creates a VBA project from the content of a
+// vbaProject.bin part, for example one taken from another presentation.
+func NewVBAProject(data []byte) *VBAProject {
+	return &VBAProject{data: data}
+}
+
+// GetData returns the content of the vbaProject.bin part.
+func (v *VBAProject) GetData() []byte { return v.data }
+
+// IsSigned reports whether the project carries a digital signature.
+func (v *VBAProject) IsSigned() bool {
+	for _, part := range v.parts {
+		switch part.relType {
+		case relTypeVBASignature, relTypeVBASignatureAgile, relTypeVBASignatureV3:
+			return true
+		}
+	}
+	return false
+}
+
+// HasMacros reports whether the presentation has a VBA project.
+func (p *Presentation) HasMacros() bool {
+	return p.vbaProject != nil && len(p.vbaProject.data) > 0
+}
+
+// GetVBAProject returns the VBA project, or nil when there is none.
+func (p *Presentation) GetVBAProject() *VBAProject {
+	return p.vbaProject
+}
+
+// SetVBAProject sets the VBA project; nil removes the macros. The project
+// is only written for macro-enabled document types such as
+// DocumentTypeMacroEnabled.
+func (p *Presentation) SetVBAProject(v *VBAProject) {
+	p.vbaProject = v
+}
@@ -0,0 +1,185 @@
+package gopresentation
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"os"
+)
+
+// WatermarkOptions controls how a watermark is placed.
+type WatermarkOptions struct {
+	// Rotation is the clockwise rotation in degrees. Default: -45, rising
+	// from the bottom left to the top right.
+	Rotation int
+	// Opacity is the opacity from 0 (invisible) to 1 (opaque).
+	// Default: 0.2.
+	Opacity float64
+	// Tile repeats the watermark in a grid covering the slide instead of
+	// placing it once in the center.
+	Tile bool
+	// OnMaster adds the watermark once to the slide master, behind the
+	// content of every slide including slides added later. Otherwise it is
+	// added on top of the content of every existing slide.
+	OnMaster bool
+	// Font is the font of text watermarks; the opacity applies to its
+	// color. Default: bold 60 pt gray.
+	Font *Font
+	// Width is the width of an image watermark in EMU; its height follows
+	// from the image's aspect ratio. Default: half the slide width, or a
+	// fifth of it when tiled.
+	Width int64
+}
+
+// DefaultWatermarkOptions returns the default watermark options.
+func DefaultWatermarkOptions() *WatermarkOptions {
+	font := NewFont()
+	font.SetSize(60).SetBold(true).SetColor(NewColor("FF808080"))
+	return &WatermarkOptions{
+		Rotation: -45,
+		Opacity:  0.2,
+		Font:     font,
+	}
+}
+
+// AddTextWatermark adds text as a rotated, semi-transparent watermark. A
+// nil opts uses DefaultWatermarkOptions. Empty text adds nothing.
+func (p *Presentation) AddTextWatermark(text string, opts *WatermarkOptions) {
+	if text == "" {
+		return
+	}
+	if opts == nil {
+		opts = DefaultWatermarkOptions()
+	}
+	f := NewFont()
+	if opts.Font != nil {
+		*f = *opts.Font
+	}
+	f.Color = watermarkColor(f.Color, opts.Opacity)
+
+	para := NewParagraph()
+	para.CreateTextRun(text).SetFont(f)
+	r := measureRenderer()
+	_, h := r.measureParagraphs([]*Paragraph{para}, 0, false)
+	tw := r.measureMaxLineWidth([]*Paragraph{para}, 0, false)
+	// The default insets, and a little room so the line does not wrap
+	width := pixelToEMU(tw) + 2*91440 + 12700
+	height := pixelToEMU(h) + 2*45720
+
+	p.placeWatermark(opts, width, height, func() Shape {
+		s := NewRichTextShape()
+		s.SetWordWrap(false)
+		s.SetName("Watermark")
+		para := s.CreateParagraph()
+		para.GetAlignment().SetHorizontal(HorizontalCenter)
+		font := *f
+		para.CreateTextRun(text).SetFont(&font)
+		return s
+	})
+}
+
+// AddImageWatermark adds a picture as a rotated, semi-transparent
+// watermark. A nil opts uses DefaultWatermarkOptions. On error nothing is
+// added.
+func (p *Presentation) AddImageWatermark(img ImageSource, opts *WatermarkOptions) error {
+	if opts == nil {
+		opts = DefaultWatermarkOptions()
+	}
+	src, err := img.drawingShape()
+	if err != nil {
+		return fmt.Errorf("image watermark: %w", err)
+	}
+	data := src.data
+	if data == nil {
+		if data, err = os.ReadFile(src.path); err != nil {
+			return fmt.Errorf("image watermark: %w", err)
+		}
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("image watermark: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return fmt.Errorf("image watermark: empty image")
+	}
+
+	cx, _ := p.slideSizeOrDefault()
+	width := opts.Width
+	if width <= 0 {
+		width = cx / 2
+		if opts.Tile {
+			width = cx / 5
+		}
+	}
+	height := width * int64(cfg.Height) / int64(cfg.Width)
+	// An alphaModFix amount of 0 would mean opaque
+	alpha := max(1, int(math.Round(clampUnit(opts.Opacity)*100000)))
+
+	p.placeWatermark(opts, width, height, func() Shape {
+		d := NewDrawingShape()
+		d.SetImageData(data, src.mimeType)
+		d.SetName("Watermark")
+		d.SetAlphaValue(alpha)
+		return d
+	})
+	return nil
+}
+
+// placeWatermark adds the shapes made by newShape, each width by height
+// EMU, to the master or to every slide: once in the center of the slide,
+// or tiled in a grid that covers it.
+func (p *Presentation) placeWatermark(opts *WatermarkOptions, width, height int64, newShape func() Shape) {
+	cx, cy := p.slideSizeOrDefault()
+	type center struct{ x, y int64 }
+	centers := []center{{cx / 2, cy / 2}}
+	if opts.Tile {
+		// Space the tiles by the bounding box of the rotated watermark
+		sin, cos := math.Sincos(float64(opts.Rotation) * math.Pi / 180)
+		w, h := float64(width), float64(height)
+		gap := float64(cx) / 20
+		stepX := math.Abs(w*cos) + math.Abs(h*sin) + gap
+		stepY := math.Abs(w*sin) + math.Abs(h*cos) + gap
+		cols := int(math.Ceil(float64(cx) / stepX))
+		rows := int(math.Ceil(float64(cy) / stepY))
+		x0 := (float64(cx) - float64(cols-1)*stepX) / 2
+		y0 := (float64(cy) - float64(rows-1)*stepY) / 2
+		centers = centers[:0]
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				centers = append(centers, center{int64(x0 + float64(col)*stepX), int64(y0 + float64(row)*stepY)})
+			}
+		}
+	}
+
+	add := func(addShape func(Shape)) {
+		for _, c := range centers {
+			s := newShape()
+			b := s.base()
+			b.SetOffsetX(c.x - width/2).SetOffsetY(c.y - height/2).SetWidth(width).SetHeight(height)
+			b.SetRotation(opts.Rotation)
+			b.SetLocks(&ShapeLocks{NoMove: true, NoResize: true, NoSelect: true, NoRot: true})
+			addShape(s)
+		}
+	}
+	if opts.OnMaster {
+		add(p.GetSlideMaster().AddShape)
+		return
+	}
+	for _, slide := range p.slides {
+		add(slide.AddShape)
+	}
+}
+
+// watermarkColor returns c with its opacity multiplied by opacity.
+func watermarkColor(c Color, opacity float64) Color {
+	c = c.Resolve()
+	a := float64(c.GetAlpha()) * clampUnit(opacity)
+	c.ARGB = fmt.Sprintf("%02X", int(math.Round(a))) + colorRGB(c)
+	return c
+}
+
+// clampUnit returns v limited to the range 0 to 1.
+func clampUnit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,381 @@
+package gopresentation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"sort"
+)
+
+// maxWebPSize is the largest width or height a WebP image can have.
+const maxWebPSize = 1 << 14
+
+// encodeWebP writes img as a lossless WebP (VP8L) image. Pixels are coded
+// with the subtract-green transform, runs copied from the left or above
+// pixels and one set of Huffman codes built from the image's histograms.
+// There is no general LZ77 search or color cache, which keeps the encoder
+// small at some cost in size for photographs.
+func encodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width <= 0 || height <= 0 || width > maxWebPSize || height > maxWebPSize {
+		return fmt.Errorf("webp: unsupported image size %dx%d", width, height)
+	}
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || nrgba.Rect.Min != (image.Point{}) {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	}
+
+	// Apply the subtract-green transform
+	n := width * height
+	pix := make([]uint32, n)
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		row := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+4*width]
+		for x := 0; x < width; x++ {
+			r, g, bl, a := row[4*x], row[4*x+1], row[4*x+2], row[4*x+3]
+			pix[y*width+x] = uint32(a)<<24 | uint32(r-g)<<16 | uint32(g)<<8 | uint32(bl-g)
+			if a != 0xff {
+				hasAlpha = true
+			}
+		}
+	}
+
+	// Code runs that repeat the pixel to the left or above as backward
+	// references and everything else as literals
+	type token struct {
+		pixel   uint32
+		length  int // 0 for a literal
+		distSym int // 0 copies from the row above, 1 from the left pixel
+	}
+	var tokens []token
+	var hist [5][]int
+	hist[0] = make([]int, 256+24)
+	for i := 1; i < 4; i++ {
+		hist[i] = make([]int, 256)
+	}
+	hist[4] = make([]int, 40)
+	for i := 0; i < n; {
+		best, bestSym := 0, 0
+		if i >= width {
+			best = matchRun(pix, i, width)
+		}
+		if i >= 1 {
+			if l := matchRun(pix, i, 1); l > best {
+				best, bestSym = l, 1
+			}
+		}
+		if best >= 3 {
+			code, _, _ := prefixEncode(best)
+			hist[0][256+code]++
+			hist[4][bestSym]++
+			tokens = append(tokens, token{length: best, distSym: bestSym})
+			i += best
+			continue
+		}
+		p := pix[i]
+		hist[0][p>>8&0xff]++
+		hist[1][p>>16&0xff]++
+		hist[2][p&0xff]++
+		hist[3][p>>24]++
+		tokens = append(tokens, token{pixel: p})
+		i++
+	}
+	var codes [5]*huffmanCode
+	for i := range codes {
+		codes[i] = newHuffmanCode(hist[i], 15)
+	}
+
+	bw := &bitWriter{}
+	bw.writeBits(0x2f, 8) // VP8L signature
+	bw.writeBits(uint64(width-1), 14)
+	bw.writeBits(uint64(height-1), 14)
+	if hasAlpha {
+		bw.writeBits(1, 1)
+	} else {
+		bw.writeBits(0, 1)
+	}
+	bw.writeBits(0, 3) // version
+	bw.writeBits(1, 1) // transform present
+	bw.writeBits(2, 2) // subtract green
+	bw.writeBits(0, 1) // no more transforms
+	bw.writeBits(0, 1) // no color cache
+	bw.writeBits(0, 1) // no meta prefix codes
+
+	// Green with the length codes, red, blue, alpha and distance
+	for i, size := range []int{256 + 24, 256, 256, 256, 40} {
+		codes[i].write(bw, size)
+	}
+
+	for _, t := range tokens {
+		if t.length > 0 {
+			code, extraBits, extra := prefixEncode(t.length)
+			codes[0].writeSymbol(bw, 256+code)
+			bw.writeBits(uint64(extra), extraBits)
+			codes[4].writeSymbol(bw, t.distSym)
+			continue
+		}
+		codes[0].writeSymbol(bw, int(t.pixel>>8&0xff))
+		codes[1].writeSymbol(bw, int(t.pixel>>16&0xff))
+		codes[2].writeSymbol(bw, int(t.pixel&0xff))
+		codes[3].writeSymbol(bw, int(t.pixel>>24))
+	}
+	data := bw.bytes()
+
+	chunkSize := len(data)
+	padded := chunkSize + chunkSize&1
+	out := bufio.NewWriter(w)
+	var hdr [20]byte
+	copy(hdr[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:8], uint32(12+padded))
+	copy(hdr[8:12], "WEBP")
+	copy(hdr[12:16], "VP8L")
+	binary.LittleEndian.PutUint32(hdr[16:20], uint32(chunkSize))
+	out.Write(hdr[:])
+	out.Write(data)
+	if padded != chunkSize {
+		out.WriteByte(0)
+	}
+	return out.Flush()
+}
+
+// maxWebPCopy is the longest backward reference VP8L can code.
+const maxWebPCopy = 4096
+
+// matchRun returns how many pixels from i on repeat the pixels dist
+// earlier, up to maxWebPCopy.
+func matchRun(pix []uint32, i, dist int) int {
+	n := 0
+	for i+n < len(pix) && n < maxWebPCopy && pix[i+n] == pix[i+n-dist] {
+		n++
+	}
+	return n
+}
+
+// prefixEncode splits a length or distance v >= 1 into its prefix code and
+// the extra bits that follow it.
+func prefixEncode(v int) (code int, extraBits uint, extra int) {
+	d := v - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	high := 0
+	for d>>(high+1) != 0 {
+		high++
+	}
+	second := d >> (high - 1) & 1
+	extraBits = uint(high - 1)
+	return 2*high + second, extraBits, d & (1<<extraBits - 1)
+}
+
+// bitWriter writes bits least significant first, as VP8L expects.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (bw *bitWriter) writeBits(v uint64, n uint) {
+	bw.acc |= v << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+func (bw *bitWriter) bytes() []byte {
+	if bw.nbits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf
+}
+
+// huffmanCode is a canonical prefix code. codes hold the bit-reversed code
+// of each symbol, ready to be written least significant bit first.
+type huffmanCode struct {
+	lengths []uint8
+	codes   []uint16
+	single  bool // only one symbol is used: it is coded with zero bits
+}
+
+// newHuffmanCode builds a prefix code for the symbol counts with no code
+// longer than maxLen bits. Counts are halved until the limit is met.
+func newHuffmanCode(counts []int, maxLen int) *huffmanCode {
+	h := &huffmanCode{lengths: make([]uint8, len(counts)), codes: make([]uint16, len(counts))}
+	var used []int
+	for s, c := range counts {
+		if c > 0 {
+			used = append(used, s)
+		}
+	}
+	switch len(used) {
+	case 0:
+		h.single = true
+		return h
+	case 1:
+		h.lengths[used[0]] = 1
+		h.single = true
+		return h
+	}
+
+	weights := make([]int, len(counts))
+	copy(weights, counts)
+	for {
+		if huffmanLengths(weights, used, h.lengths) <= maxLen {
+			break
+		}
+		for _, s := range used {
+			weights[s] = (weights[s] + 1) / 2
+		}
+	}
+
+	// Assign canonical codes: shorter codes first, then by symbol
+	var blCount [16]int
+	for _, s := range used {
+		blCount[h.lengths[s]]++
+	}
+	var next [16]int
+	code := 0
+	for l := 1; l < 16; l++ {
+		code = (code + blCount[l-1]) << 1
+		next[l] = code
+	}
+	for s := range counts {
+		l := h.lengths[s]
+		if l == 0 {
+			continue
+		}
+		h.codes[s] = reverseBits(uint16(next[l]), l)
+		next[l]++
+	}
+	return h
+}
+
+// huffmanLengths sets the Huffman code length of each used symbol and
+// returns the longest.
+func huffmanLengths(weights []int, used []int, lengths []uint8) int {
+	type node struct {
+		weight      int
+		symbol      int // -1 for internal nodes
+		left, right int
+	}
+	nodes := make([]node, 0, 2*len(used))
+	for _, s := range used {
+		nodes = append(nodes, node{weight: weights[s], symbol: s, left: -1, right: -1})
+	}
+	// Two queues: sorted leaves and internal nodes in creation order
+	leaves := make([]int, len(used))
+	for i := range leaves {
+		leaves[i] = i
+	}
+	sort.SliceStable(leaves, func(a, b int) bool { return nodes[leaves[a]].weight < nodes[leaves[b]].weight })
+	var internal []int
+	pop := func() int {
+		if len(internal) == 0 || (len(leaves) > 0 && nodes[leaves[0]].weight <= nodes[internal[0]].weight) {
+			i := leaves[0]
+			leaves = leaves[1:]
+			return i
+		}
+		i := internal[0]
+		internal = internal[1:]
+		return i
+	}
+	for len(leaves)+len(internal) > 1 {
+		a, b := pop(), pop()
+		nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight, symbol: -1, left: a, right: b})
+		internal = append(internal, len(nodes)-1)
+	}
+
+	maxLen := 0
+	var walk func(i, depth int)
+	walk = func(i, depth int) {
+		if nodes[i].symbol >= 0 {
+			lengths[nodes[i].symbol] = uint8(depth)
+			maxLen = max(maxLen, depth)
+			return
+		}
+		walk(nodes[i].left, depth+1)
+		walk(nodes[i].right, depth+1)
+	}
+	walk(len(nodes)-1, 0)
+	return maxLen
+}
+
+func reverseBits(v uint16, n uint8) uint16 {
+	var r uint16
+	for i := uint8(0); i < n; i++ {
+		r = r<<1 | v&1
+		v >>= 1
+	}
+	return r
+}
+
+func (h *huffmanCode) writeSymbol(bw *bitWriter, s int) {
+	if !h.single {
+		bw.writeBits(uint64(h.codes[s]), uint(h.lengths[s]))
+	}
+}
+
+// codeLengthOrder is the order in which the code length code lengths are
+// stored.
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// write writes the code for an alphabet of the given size.
+func (h *huffmanCode) write(bw *bitWriter, alphabet int) {
+	if h.single {
+		sym := 0
+		for s, l := range h.lengths {
+			if l > 0 {
+				sym = s
+			}
+		}
+		writeSimpleCode(bw, sym)
+		return
+	}
+
+	// Normal code: the code lengths, themselves coded with a prefix code
+	lengths := make([]uint8, alphabet)
+	copy(lengths, h.lengths)
+	var counts [19]int
+	for _, l := range lengths {
+		counts[l]++
+	}
+	lc := newHuffmanCode(counts[:], 7)
+	num := 4
+	for i := len(codeLengthOrder) - 1; i >= 4; i-- {
+		if lc.lengths[codeLengthOrder[i]] > 0 {
+			num = i + 1
+			break
+		}
+	}
+	bw.writeBits(0, 1) // normal code
+	bw.writeBits(uint64(num-4), 4)
+	for i := 0; i < num; i++ {
+		bw.writeBits(uint64(lc.lengths[codeLengthOrder[i]]), 3)
+	}
+	bw.writeBits(0, 1) // code lengths for the whole alphabet follow
+	for _, l := range lengths {
+		lc.writeSymbol(bw, int(l))
+	}
+}
+
+// writeSimpleCode writes a prefix code with the single symbol sym (< 256),
+// which takes no bits to code.
+func writeSimpleCode(bw *bitWriter, sym int) {
+	bw.writeBits(1, 1) // simple code
+	bw.writeBits(0, 1) // one symbol
+	if sym < 2 {
+		bw.writeBits(0, 1)
+		bw.writeBits(uint64(sym), 1)
+	} else {
+		bw.writeBits(1, 1)
+		bw.writeBits(uint64(sym), 8)
+	}
+}
@@ -2,10 +2,13 @@ package gopresentation
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // Writer is the interface for presentation writers.
@@ -22,10 +25,18 @@ const (
 )
 
 // NewWriter creates a writer for the given format.
-func NewWriter(p *Presentation, format WriterType) (Writer, error) {
+func NewWriter(p *Presentation, format WriterType, opts ...WriterOption) (Writer, error) {
 	switch format {
 	case WriterPowerPoint2007:
-		return &PPTXWriter{presentation: p}, nil
+		w := &PPTXWriter{presentation: p}
+		if len(opts) > 0 {
+			o := DefaultWriterOptions()
+			for _, opt := range opts {
+				opt(o)
+			}
+			w.opts = o
+		}
+		return w, nil
 	default:
 		return nil, fmt.Errorf("unsupported writer format: %s", format)
 	}
@@ -34,16 +45,67 @@ func NewWriter(p *Presentation, format WriterType) (Writer, error) {
 // PPTXWriter writes presentations in PPTX format.
 type PPTXWriter struct {
 	presentation *Presentation
-	relID        int
-}
+	// opts holds the writer options; nil means the defaults.
+	opts *WriterOptions
+
+	// presRels holds the relationships of the presentation part while the
+	// package is written.
+	presRels *relRegistry
+
+	// slideRels holds the relationships of the slide being written.
+	slideRels *relRegistry
+	// slideIndex is the index of the slide being written, for slide
+	// number fields.
+	slideIndex int
+	// tagParts holds the content of the tags parts registered so far.
+	tagParts []string
+	// shapePart is the part whose shapes are being written.
+	shapePart string
+
+	// contentTypes collects the content type of each part written.
+	contentTypes *contentTypeRegistry
 
-func (w *PPTXWriter) nextRelID() string {
-	w.relID++
-	return fmt.Sprintf("rId%d", w.relID)
+	// parts and hooks are added by AddPart and OnBeforeWrite; pkg is the
+	// package they build for the write in progress.
+	parts []customPart
+	hooks []func(pkg *Package)
+	pkg   *Package
+
+	// normPrev is the last character of the paragraph written so far by
+	// normalizeText.
+	normPrev rune
+
+	// docType overrides the presentation's document type while Save
+	// writes a file whose extension names another type.
+	docType DocumentType
 }
 
-// Save writes the presentation to a file.
+// Save writes the presentation to a file. Files named .potx, .ppsx, .pptm
+// and so on are written as that document type.
 func (w *PPTXWriter) Save(path string) error {
+	t, _ := documentTypeForPath(path)
+	return w.saveAs(path, t)
+}
+
+// SaveAsSlideshow writes the presentation to a file as a slideshow, which
+// PowerPoint opens straight into the show, whatever the extension of path.
+// Presentations with a VBA project are written as macro-enabled slideshows
+// and keep it. PowerPoint expects the path to end in .ppsx (or .ppsm).
+func (w *PPTXWriter) SaveAsSlideshow(path string) error {
+	t := DocumentTypeSlideshow
+	if w.presentation != nil && w.presentation.HasMacros() {
+		t = DocumentTypeMacroEnabledSlideshow
+	}
+	return w.saveAs(path, t)
+}
+
+// saveAs writes the presentation to a file as document type t; an empty t
+// uses the document type of the presentation.
+func (w *PPTXWriter) saveAs(path string, t DocumentType) error {
+	if t != "" {
+		w.docType = t
+		defer func() { w.docType = "" }()
+	}
 	dir := filepath.Dir(path)
 	if dir != "" && dir != "." {
 		if err := os.MkdirAll(dir, 0750); err != nil {
@@ -72,14 +134,40 @@ func (w *PPTXWriter) WriteTo(writer io.Writer) error {
 		return fmt.Errorf("presentation is nil")
 	}
 
-	zw := zip.NewWriter(writer)
+	return w.writeOutput(writer)
+}
 
-	w.relID = 0
+// documentType returns the document type being written.
+func (w *PPTXWriter) documentType() DocumentType {
+	if w.docType != "" {
+		return w.docType
+	}
+	return w.presentation.GetDocumentType()
+}
 
-	// Write [Content_Types].xml
-	if err := w.writeContentTypes(zw); err != nil {
+// writesVBAProject reports whether the package written includes the VBA
+// project of the presentation.
+func (w *PPTXWriter) writesVBAProject() bool {
+	return w.presentation.HasMacros() && w.documentType().IsMacroEnabled()
+}
+
+// writePackage writes every part of the presentation package to zw.
+func (w *PPTXWriter) writePackage(zw *zip.Writer) error {
+	pkg, err := w.newPackage()
+	if err != nil {
 		return err
 	}
+	w.pkg = pkg
+	w.contentTypes = newContentTypeRegistry()
+	w.tagParts = nil
+	defer func() { w.pkg, w.contentTypes, w.tagParts = nil, nil, nil }()
+
+	presRels, err := w.buildPresentationRels()
+	if err != nil {
+		return err
+	}
+	w.presRels = presRels
+	defer func() { w.presRels = nil }()
 
 	// Write _rels/.rels
 	if err := w.writeRootRels(zw); err != nil {
@@ -102,7 +190,7 @@ func (w *PPTXWriter) WriteTo(writer io.Writer) error {
 	}
 
 	// Write ppt/_rels/presentation.xml.rels
-	if err := w.writePresentationRels(zw); err != nil {
+	if err := w.writeRels(zw, "ppt/_rels/presentation.xml.rels", w.presRels); err != nil {
 		return err
 	}
 
@@ -121,6 +209,13 @@ func (w *PPTXWriter) WriteTo(writer io.Writer) error {
 		return err
 	}
 
+	// Write ppt/vbaProject.bin and the parts it relates to
+	if w.writesVBAProject() {
+		if err := w.writeVBAProject(zw); err != nil {
+			return err
+		}
+	}
+
 	// Write slide master and layout
 	if err := w.writeSlideMaster(zw); err != nil {
 		return err
@@ -137,11 +232,14 @@ func (w *PPTXWriter) WriteTo(writer io.Writer) error {
 
 	// Write slides
 	for i, slide := range w.presentation.slides {
-		hlinkRelMap := w.buildHyperlinkRelMap(slide)
-		if err := w.writeSlide(zw, slide, i+1, hlinkRelMap); err != nil {
+		rels, err := w.buildSlideRels(slide, i+1)
+		if err != nil {
+			return err
+		}
+		if err := w.writeSlide(zw, slide, i+1, rels); err != nil {
 			return err
 		}
-		if err := w.writeSlideRels(zw, slide, i+1, hlinkRelMap); err != nil {
+		if err := w.writeRels(zw, fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", i+1), rels); err != nil {
 			return err
 		}
 	}
@@ -151,14 +249,32 @@ func (w *PPTXWriter) WriteTo(writer io.Writer) error {
 		return err
 	}
 
+	// Write the tags of slides and shapes
+	if err := w.writeTagParts(zw); err != nil {
+		return err
+	}
+
 	// Write charts
 	chartIdx := 1
 	for _, slide := range w.presentation.slides {
 		for _, shape := range slide.shapes {
 			if cs, ok := shape.(*ChartShape); ok {
-				if err := w.writeChartPart(zw, cs, chartIdx); err != nil {
+				rels := chartRels(cs, chartIdx)
+				if err := w.writeChartPart(zw, cs, chartIdx, rels); err != nil {
+					return err
+				}
+				if err := w.writeChartStyleParts(zw, cs, chartIdx); err != nil {
 					return err
 				}
+				if err := w.writeChartWorkbook(zw, cs, chartIdx); err != nil {
+					return err
+				}
+				if len(rels.rels) > 0 {
+					name := "ppt/charts/_rels/" + chartPartName(cs.plotArea.chartType, chartIdx) + ".rels"
+					if err := w.writeRels(zw, name, rels); err != nil {
+						return err
+					}
+				}
 				chartIdx++
 			}
 		}
@@ -176,14 +292,143 @@ func (w *PPTXWriter) WriteTo(writer io.Writer) error {
 		}
 	}
 
-	// Write notes slides
+	// Write the notes master and notes slides
+	if w.presentation.hasNotes() {
+		if err := w.writeNotesMaster(zw); err != nil {
+			return err
+		}
+	}
 	for i, slide := range w.presentation.slides {
-		if slide.notes != "" {
+		if slide.HasNotes() {
 			if err := w.writeNotesSlide(zw, slide, i+1); err != nil {
 				return err
 			}
 		}
 	}
 
+	// Write the parts added by the caller
+	for _, part := range pkg.parts {
+		if err := w.writePartBytes(zw, part.name, part.contentType, part.data); err != nil {
+			return err
+		}
+	}
+
+	// Write [Content_Types].xml, now that every part has declared its type
+	if err := w.writeContentTypes(zw); err != nil {
+		return err
+	}
+	if err := pkg.checkTransformsUsed(); err != nil {
+		return err
+	}
+
+	return zw.Flush()
+}
+
+// SaveWithCompressionCache writes the presentation to dstPath, using the
+// package at srcPath as a cache of compressed parts. Every part is written
+// again, in memory and uncompressed; the parts whose size and CRC-32 match
+// those of the source entry of the same name are then copied from srcPath
+// still compressed, and only the others are compressed. Parts of srcPath
+// the presentation does not hold are not kept. srcPath and dstPath may be
+// the same file; the output is written to a temporary file and renamed
+// into place.
+func (w *PPTXWriter) SaveWithCompressionCache(srcPath, dstPath string) error {
+	if w.presentation == nil {
+		return fmt.Errorf("presentation is nil")
+	}
+	if t, ok := documentTypeForPath(dstPath); ok {
+		w.docType = t
+		defer func() { w.docType = "" }()
+	}
+	src, err := zip.OpenReader(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open source package: %w", err)
+	}
+	defer src.Close()
+	srcFiles := make(map[string]*zip.File, len(src.File))
+	for _, f := range src.File {
+		srcFiles[f.Name] = f
+	}
+
+	// Stage the new package uncompressed: the staging writer stores Deflate
+	// entries as-is, so their raw data is the part content.
+	var staged bytes.Buffer
+	sw := zip.NewWriter(&staged)
+	sw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return nopWriteCloser{out}, nil
+	})
+	if err := w.writePackage(sw); err != nil {
+		return err
+	}
+	if err := sw.Close(); err != nil {
+		return err
+	}
+	stagedReader, err := zip.NewReader(bytes.NewReader(staged.Bytes()), int64(staged.Len()))
+	if err != nil {
+		return fmt.Errorf("failed to read staged package: %w", err)
+	}
+
+	dir := filepath.Dir(dstPath)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	tmp, err := os.CreateTemp(dir, ".gopresentation-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	writeErr := copyCompressionCache(tmp, stagedReader, srcFiles)
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		// Release the source before replacing it when patching in place
+		src.Close()
+		writeErr = os.Rename(tmpPath, dstPath)
+	}
+	if writeErr != nil {
+		os.Remove(tmpPath)
+		return writeErr
+	}
+	return nil
+}
+
+// copyCompressionCache writes the staged parts to out, copying the raw source
+// entry for every part whose size and CRC-32 match and compressing the rest.
+func copyCompressionCache(out io.Writer, staged *zip.Reader, srcFiles map[string]*zip.File) error {
+	zw := zip.NewWriter(out)
+	now := time.Now()
+	for _, f := range staged.File {
+		raw, err := f.OpenRaw()
+		if err != nil {
+			return fmt.Errorf("failed to read staged %s: %w", f.Name, err)
+		}
+		if sf, ok := srcFiles[f.Name]; ok && sf.CRC32 == f.CRC32 && sf.UncompressedSize64 == f.UncompressedSize64 {
+			if err := zw.Copy(sf); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", f.Name, err)
+			}
+			continue
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return fmt.Errorf("failed to create %s in zip: %w", f.Name, err)
+		}
+		h := crc32.NewIEEE()
+		if _, err := io.Copy(io.MultiWriter(fw, h), raw); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+		if h.Sum32() != f.CRC32 {
+			return fmt.Errorf("staged part %s is corrupt", f.Name)
+		}
+	}
 	return zw.Close()
 }
+
+// nopWriteCloser adapts an io.Writer to io.WriteCloser with a no-op Close.
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -3,6 +3,7 @@ package gopresentation
 import (
 	"archive/zip"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -27,6 +28,10 @@ func getChartSeries(ct ChartType) []*ChartSeries {
 		return c.Series
 	case *RadarChart:
 		return c.Series
+	case *WaterfallChart:
+		return c.Series
+	case *FunnelChart:
+		return c.Series
 	default:
 		return nil
 	}
@@ -40,11 +45,14 @@ func getCategories(series []*ChartSeries) []string {
 	return series[0].Categories
 }
 
-func (w *PPTXWriter) writeChartPart(zw *zip.Writer, chart *ChartShape, chartIdx int) error {
+func (w *PPTXWriter) writeChartPart(zw *zip.Writer, chart *ChartShape, chartIdx int, rels *relRegistry) error {
 	ct := chart.plotArea.chartType
 	if ct == nil {
 		return nil
 	}
+	if isChartExType(ct) {
+		return w.writeChartExPart(zw, chart, chartIdx, rels)
+	}
 
 	series := getChartSeries(ct)
 	categories := getCategories(series)
@@ -76,7 +84,18 @@ func (w *PPTXWriter) writeChartPart(zw *zip.Writer, chart *ChartShape, chartIdx
 
 	// Title XML
 	titleXML := ""
-	if chart.title.Visible && chart.title.Text != "" {
+	if chart.title.Visible && len(chart.title.paragraphs) > 0 {
+		titleXML = fmt.Sprintf(`  <c:title>
+    <c:tx>
+      <c:rich>
+        <a:bodyPr/>
+        <a:lstStyle/>
+%s      </c:rich>
+    </c:tx>
+    <c:overlay val="0"/>
+  </c:title>
+`, w.writeChartParagraphsXML(chart.title.centeredParagraphs()))
+	} else if chart.title.Visible && chart.title.Text != "" {
 		titleXML = fmt.Sprintf(`  <c:title>
     <c:tx>
       <c:rich>
@@ -101,36 +120,79 @@ func (w *PPTXWriter) writeChartPart(zw *zip.Writer, chart *ChartShape, chartIdx
 	// Legend XML
 	legendXML := ""
 	if chart.legend.Visible {
+		var entriesXML strings.Builder
+		idxs := make([]int, 0, len(chart.legend.entryFonts))
+		for idx, f := range chart.legend.entryFonts {
+			if f != nil {
+				idxs = append(idxs, idx)
+			}
+		}
+		sort.Ints(idxs)
+		for _, idx := range idxs {
+			fmt.Fprintf(&entriesXML, `    <c:legendEntry>
+      <c:idx val="%d"/>
+%s    </c:legendEntry>
+`, idx, chartTxPrXML(chart.legend.entryFonts[idx]))
+		}
+		txPr := ""
+		if chart.legend.Font != nil && !isDefaultFont(chart.legend.Font) {
+			txPr = chartTxPrXML(chart.legend.Font)
+		}
 		legendXML = fmt.Sprintf(`  <c:legend>
     <c:legendPos val="%s"/>
-    <c:overlay val="0"/>
-  </c:legend>
-`, chart.legend.Position)
+%s    <c:overlay val="0"/>
+%s  </c:legend>
+`, xmlEscape(string(chart.legend.Position)), entriesXML.String(), txPr)
 	}
 
 	// Axis XML
 	axisXML := ""
 	if !isPieType(ct) {
 		axisXML = w.writeAxesXML(chart)
+		if chart.dataTable {
+			axisXML += fmt.Sprintf(`      <c:dTable>
+        <c:showHorzBorder val="1"/>
+        <c:showVertBorder val="1"/>
+        <c:showOutline val="1"/>
+        <c:showKeys val="%s"/>
+      </c:dTable>
+`, boolToXML(chart.dataTableKeys))
+		}
+	}
+
+	// Styled charts get square corners and no chart area border, as
+	// PowerPoint draws new charts.
+	cornersXML, chartSpPrXML := "", ""
+	if chart.style > 0 {
+		cornersXML = "  <c:roundedCorners val=\"0\"/>\n"
+		chartSpPrXML = "  <c:spPr>" + chartStyleNoFill + "</c:spPr>\n"
+	}
+
+	// The embedded workbook that the series formulas reference
+	externalDataXML := ""
+	if id := rels.id(relKeyWorkbook); id != "" {
+		externalDataXML = fmt.Sprintf("  <c:externalData r:id=\"%s\"><c:autoUpdate val=\"0\"/></c:externalData>\n", id)
 	}
 
 	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="%s" xmlns:r="%s">
-  <c:chart>
+%s  <c:chart>
 %s%s    <c:plotArea>
       <c:layout/>
 %s%s    </c:plotArea>
 %s    <c:plotVisOnly val="1"/>
     <c:dispBlanksAs val="%s"/>
   </c:chart>
-</c:chartSpace>`,
+%s%s</c:chartSpace>`,
 		nsDrawingML, nsOfficeDocRels,
+		cornersXML,
 		titleXML, "",
 		chartTypeXML.String(), axisXML,
 		legendXML,
-		chart.displayBlankAs)
+		xmlEscape(chart.displayBlankAs),
+		chartSpPrXML, externalDataXML)
 
-	return writeRawXMLToZip(zw, fmt.Sprintf("ppt/charts/chart%d.xml", chartIdx), content)
+	return w.writeRawPart(zw, "ppt/charts/"+chartPartName(ct, chartIdx), ctChart, content)
 }
 
 func boolToXML(v bool) string {
@@ -152,15 +214,21 @@ func (w *PPTXWriter) writeAxesXML(chart *ChartShape) string {
 	axX := chart.plotArea.axisX
 	axY := chart.plotArea.axisY
 
+	// Horizontal bars swap the sides of the category and value axes
+	catPos, valPos := "b", "l"
+	if isHorizontalBar(chart.plotArea.chartType) {
+		catPos, valPos = "l", "b"
+	}
+
 	catAxisXML := fmt.Sprintf(`      <c:catAx>
         <c:axId val="1"/>
         <c:scaling><c:orientation val="%s"/></c:scaling>
         <c:delete val="%s"/>
-        <c:axPos val="b"/>
+        <c:axPos val="%s"/>
         <c:crossAx val="2"/>
         <c:crosses val="%s"/>
         <c:tickLblPos val="%s"/>
-`, w.axisOrientation(axX), boolToXML(!axX.Visible), axX.CrossesAt, axX.TickLabelPos)
+`, w.axisOrientation(axX), boolToXML(!axX.Visible), catPos, xmlEscape(axX.CrossesAt), xmlEscape(axX.TickLabelPos))
 
 	if axX.Title != "" {
 		catAxisXML += fmt.Sprintf(`        <c:title><c:tx><c:rich><a:bodyPr/><a:lstStyle/><a:p><a:r><a:rPr lang="en-US"/><a:t>%s</a:t></a:r></a:p></c:rich></c:tx></c:title>
@@ -176,11 +244,11 @@ func (w *PPTXWriter) writeAxesXML(chart *ChartShape) string {
         <c:scaling>
           <c:orientation val="%s"/>`, w.axisOrientation(axY))
 
-	if axY.MinBounds != nil {
+	if axY.MinBounds != nil && isFinite(*axY.MinBounds) {
 		valAxisXML += fmt.Sprintf(`
           <c:min val="%g"/>`, *axY.MinBounds)
 	}
-	if axY.MaxBounds != nil {
+	if axY.MaxBounds != nil && isFinite(*axY.MaxBounds) {
 		valAxisXML += fmt.Sprintf(`
           <c:max val="%g"/>`, *axY.MaxBounds)
 	}
@@ -188,17 +256,21 @@ func (w *PPTXWriter) writeAxesXML(chart *ChartShape) string {
         </c:scaling>
 `
 	valAxisXML += fmt.Sprintf(`        <c:delete val="%s"/>
-        <c:axPos val="l"/>
+        <c:axPos val="%s"/>
         <c:crossAx val="1"/>
         <c:crosses val="%s"/>
         <c:tickLblPos val="%s"/>
-`, boolToXML(!axY.Visible), axY.CrossesAt, axY.TickLabelPos)
+`, boolToXML(!axY.Visible), valPos, xmlEscape(axY.CrossesAt), xmlEscape(axY.TickLabelPos))
 
-	if axY.MajorUnit != nil {
+	if axY.MajorUnit != nil && isFinite(*axY.MajorUnit) {
 		valAxisXML += fmt.Sprintf(`        <c:majorUnit val="%g"/>
 `, *axY.MajorUnit)
+	} else if scale, ok := valueAxisScale(chart.plotArea.chartType, axY); ok && isFinite(scale.MajorUnit) {
+		// Pin the automatic unit so PowerPoint shows the ticks we render
+		valAxisXML += fmt.Sprintf(`        <c:majorUnit val="%g"/>
+`, scale.MajorUnit)
 	}
-	if axY.MinorUnit != nil {
+	if axY.MinorUnit != nil && isFinite(*axY.MinorUnit) {
 		valAxisXML += fmt.Sprintf(`        <c:minorUnit val="%g"/>
 `, *axY.MinorUnit)
 	}
@@ -228,31 +300,101 @@ func (w *PPTXWriter) writeGridlinesXML(tag string, gl *Gridlines) string {
 	return fmt.Sprintf(`        <%s>
           <c:spPr>
             <a:ln w="%d">
-              <a:solidFill><a:srgbClr val="%s"/></a:solidFill>
+              <a:solidFill>%s</a:solidFill>
             </a:ln>
           </c:spPr>
         </%s>
-`, tag, gl.Width*12700, colorRGB(gl.Color), tag)
+`, tag, gl.Width*12700, colorXML(gl.Color), tag)
+}
+
+// seriesFormatCode returns the escaped format code of the series values.
+func seriesFormatCode(s *ChartSeries) string {
+	if s.NumberFormat == "" {
+		return "General"
+	}
+	return xmlEscape(s.NumberFormat)
 }
 
-func (w *PPTXWriter) writeSeriesXML(series []*ChartSeries, categories []string, withMarker bool) string {
+// seriesSpPrXML returns the shape properties of a series: its fill and its
+// line, or outline for bars and slices.
+func seriesSpPrXML(s *ChartSeries) string {
 	var sb strings.Builder
-	for idx, s := range series {
-		fillXML := ""
-		if s.FillColor.ARGB != "" {
-			fillXML = fmt.Sprintf(`          <c:spPr><a:solidFill><a:srgbClr val="%s"/></a:solidFill></c:spPr>
-`, colorRGB(s.FillColor))
-		}
+	if s.FillColor.ARGB != "" {
+		sb.WriteString("<a:solidFill>" + colorXML(s.FillColor) + "</a:solidFill>")
+	}
+	if o := s.Outline; o != nil {
+		sb.WriteString(seriesLineXML(o.Width, o.Color, o.Dash))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "          <c:spPr>" + sb.String() + "</c:spPr>\n"
+}
+
+// seriesLineXML returns an a:ln of width points; an empty color and zero
+// width leave those to the chart style.
+func seriesLineXML(width int, c Color, dash BorderStyle) string {
+	if dash == BorderNone {
+		return "<a:ln><a:noFill/></a:ln>"
+	}
+	var sb strings.Builder
+	sb.WriteString("<a:ln")
+	if width > 0 {
+		fmt.Fprintf(&sb, ` w="%d"`, width*12700)
+	}
+	sb.WriteString(">")
+	if c.ARGB != "" {
+		sb.WriteString("<a:solidFill>" + colorXML(c) + "</a:solidFill>")
+	}
+	switch dash {
+	case BorderDash:
+		sb.WriteString(`<a:prstDash val="dash"/>`)
+	case BorderDot:
+		sb.WriteString(`<a:prstDash val="dot"/>`)
+	}
+	sb.WriteString("</a:ln>")
+	return sb.String()
+}
+
+// seriesMarkerXML returns the c:marker of a series.
+func seriesMarkerXML(m *SeriesMarker) string {
+	var spPr string
+	if m.FillColor.ARGB != "" {
+		spPr += "<a:solidFill>" + colorXML(m.FillColor) + "</a:solidFill>"
+	}
+	if m.BorderColor.ARGB != "" {
+		spPr += seriesLineXML(0, m.BorderColor, BorderSolid)
+	}
+	if spPr != "" {
+		spPr = "<c:spPr>" + spPr + "</c:spPr>"
+	}
+	return fmt.Sprintf("          <c:marker><c:symbol val=\"%s\"/><c:size val=\"%d\"/>%s</c:marker>\n",
+		xmlEscape(m.Symbol), m.Size, spPr)
+}
 
+// writeSeriesXML writes the series of a chart. withMarker writes the
+// series markers; smooth, when not nil, is the chart's smoothing and
+// writes c:smooth for each series.
+func (w *PPTXWriter) writeSeriesXML(series []*ChartSeries, categories []string, withMarker bool, smooth *bool) string {
+	var sb strings.Builder
+	for idx, s := range series {
+		titleRef, catRef, valRef := chartSeriesRefs(idx, len(categories))
 		sb.WriteString(fmt.Sprintf(`        <c:ser>
           <c:idx val="%d"/>
           <c:order val="%d"/>
-          <c:tx><c:strRef><c:f>Sheet1!$B$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>%s</c:v></c:pt></c:strCache></c:strRef></c:tx>
-%s`, idx, idx, xmlEscape(s.Title), fillXML))
+          <c:tx><c:strRef><c:f>%s</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>%s</c:v></c:pt></c:strCache></c:strRef></c:tx>
+%s`, idx, idx, titleRef, xmlEscape(s.Title), seriesSpPrXML(s)))
+
+		if withMarker && s.Marker != nil {
+			sb.WriteString(seriesMarkerXML(s.Marker))
+		}
 
 		// Data labels
 		if s.ShowValue || s.ShowCategoryName || s.ShowPercentage || s.ShowSeriesName {
 			sb.WriteString("          <c:dLbls>\n")
+			if s.NumberFormat != "" {
+				sb.WriteString(fmt.Sprintf("            <c:numFmt formatCode=\"%s\" sourceLinked=\"0\"/>\n", xmlEscape(s.NumberFormat)))
+			}
 			if s.ShowValue {
 				sb.WriteString("            <c:showVal val=\"1\"/>\n")
 			}
@@ -269,14 +411,14 @@ func (w *PPTXWriter) writeSeriesXML(series []*ChartSeries, categories []string,
 				sb.WriteString(fmt.Sprintf("            <c:separator>%s</c:separator>\n", xmlEscape(s.Separator)))
 			}
 			if s.LabelPosition != "" {
-				sb.WriteString(fmt.Sprintf("            <c:dLblPos val=\"%s\"/>\n", s.LabelPosition))
+				sb.WriteString(fmt.Sprintf("            <c:dLblPos val=\"%s\"/>\n", xmlEscape(s.LabelPosition)))
 			}
 			sb.WriteString("          </c:dLbls>\n")
 		}
 
 		// Categories
 		if len(categories) > 0 {
-			sb.WriteString("          <c:cat>\n            <c:strRef><c:f>Sheet1!$A$2</c:f><c:strCache>\n")
+			sb.WriteString("          <c:cat>\n            <c:strRef><c:f>" + catRef + "</c:f><c:strCache>\n")
 			sb.WriteString(fmt.Sprintf("              <c:ptCount val=\"%d\"/>\n", len(categories)))
 			for i, cat := range categories {
 				sb.WriteString(fmt.Sprintf("              <c:pt idx=\"%d\"><c:v>%s</c:v></c:pt>\n", i, xmlEscape(cat)))
@@ -285,17 +427,16 @@ func (w *PPTXWriter) writeSeriesXML(series []*ChartSeries, categories []string,
 		}
 
 		// Values
-		sb.WriteString("          <c:val>\n            <c:numRef><c:f>Sheet1!$B$2</c:f><c:numCache>\n")
-		sb.WriteString(fmt.Sprintf("              <c:formatCode>General</c:formatCode>\n              <c:ptCount val=\"%d\"/>\n", len(categories)))
+		sb.WriteString("          <c:val>\n            <c:numRef><c:f>" + valRef + "</c:f><c:numCache>\n")
+		sb.WriteString(fmt.Sprintf("              <c:formatCode>%s</c:formatCode>\n              <c:ptCount val=\"%d\"/>\n", seriesFormatCode(s), len(categories)))
 		for i, cat := range categories {
 			val := s.Values[cat]
 			sb.WriteString(fmt.Sprintf("              <c:pt idx=\"%d\"><c:v>%g</c:v></c:pt>\n", i, val))
 		}
 		sb.WriteString("            </c:numCache></c:numRef>\n          </c:val>\n")
 
-		if withMarker && s.Marker != nil {
-			sb.WriteString(fmt.Sprintf("          <c:marker><c:symbol val=\"%s\"/><c:size val=\"%d\"/></c:marker>\n",
-				s.Marker.Symbol, s.Marker.Size))
+		if smooth != nil {
+			sb.WriteString(fmt.Sprintf("          <c:smooth val=\"%s\"/>\n", boolToXML(s.isSmooth(*smooth))))
 		}
 
 		sb.WriteString("        </c:ser>\n")
@@ -313,7 +454,7 @@ func (w *PPTXWriter) writeBarChartXML(c *BarChart, cats []string) string {
         <c:axId val="1"/>
         <c:axId val="2"/>
       </c:barChart>
-`, c.BarDirection, c.BarGrouping, w.writeSeriesXML(c.Series, cats, false),
+`, xmlEscape(c.BarDirection), xmlEscape(c.BarGrouping), w.writeSeriesXML(c.Series, cats, false, nil),
 		c.GapWidthPercent, c.OverlapPercent)
 }
 
@@ -326,19 +467,12 @@ func (w *PPTXWriter) writeBar3DChartXML(c *Bar3DChart, cats []string) string {
         <c:axId val="1"/>
         <c:axId val="2"/>
       </c:bar3DChart>
-`, c.BarDirection, c.BarGrouping, w.writeSeriesXML(c.Series, cats, false),
+`, xmlEscape(c.BarDirection), xmlEscape(c.BarGrouping), w.writeSeriesXML(c.Series, cats, false, nil),
 		c.GapWidthPercent)
 }
 
 func (w *PPTXWriter) writeLineChartXML(c *LineChart, cats []string) string {
-	smooth := "0"
-	if c.IsSmooth {
-		smooth = "1"
-	}
-	seriesXML := w.writeSeriesXML(c.Series, cats, true)
-	// Add smooth to each series
-	seriesXML = strings.ReplaceAll(seriesXML, "</c:ser>",
-		fmt.Sprintf("          <c:smooth val=\"%s\"/>\n        </c:ser>", smooth))
+	seriesXML := w.writeSeriesXML(c.Series, cats, true, &c.IsSmooth)
 
 	return fmt.Sprintf(`      <c:lineChart>
         <c:grouping val="standard"/>
@@ -356,21 +490,21 @@ func (w *PPTXWriter) writeAreaChartXML(c *AreaChart, cats []string) string {
 %s        <c:axId val="1"/>
         <c:axId val="2"/>
       </c:areaChart>
-`, w.writeSeriesXML(c.Series, cats, false))
+`, w.writeSeriesXML(c.Series, cats, false, nil))
 }
 
 func (w *PPTXWriter) writePieChartXML(c *PieChart, cats []string) string {
 	return fmt.Sprintf(`      <c:pieChart>
         <c:varyColors val="1"/>
 %s      </c:pieChart>
-`, w.writeSeriesXML(c.Series, cats, false))
+`, w.writeSeriesXML(c.Series, cats, false, nil))
 }
 
 func (w *PPTXWriter) writePie3DChartXML(c *Pie3DChart, cats []string) string {
 	return fmt.Sprintf(`      <c:pie3DChart>
         <c:varyColors val="1"/>
 %s      </c:pie3DChart>
-`, w.writeSeriesXML(c.Series, cats, false))
+`, w.writeSeriesXML(c.Series, cats, false, nil))
 }
 
 func (w *PPTXWriter) writeDoughnutChartXML(c *DoughnutChart, cats []string) string {
@@ -378,30 +512,24 @@ func (w *PPTXWriter) writeDoughnutChartXML(c *DoughnutChart, cats []string) stri
         <c:varyColors val="1"/>
 %s        <c:holeSize val="%d"/>
       </c:doughnutChart>
-`, w.writeSeriesXML(c.Series, cats, false), c.HoleSize)
+`, w.writeSeriesXML(c.Series, cats, false, nil), c.HoleSize)
 }
 
 func (w *PPTXWriter) writeScatterChartXML(c *ScatterChart, cats []string) string {
-	smooth := "0"
-	if c.IsSmooth {
-		smooth = "1"
-	}
-
 	var sb strings.Builder
 	for idx, s := range c.Series {
-		fillXML := ""
-		if s.FillColor.ARGB != "" {
-			fillXML = fmt.Sprintf(`          <c:spPr><a:solidFill><a:srgbClr val="%s"/></a:solidFill></c:spPr>
-`, colorRGB(s.FillColor))
-		}
+		titleRef, xRef, yRef := chartSeriesRefs(idx, len(cats))
 		sb.WriteString(fmt.Sprintf(`        <c:ser>
           <c:idx val="%d"/>
           <c:order val="%d"/>
-          <c:tx><c:strRef><c:f>Sheet1!$B$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>%s</c:v></c:pt></c:strCache></c:strRef></c:tx>
-%s`, idx, idx, xmlEscape(s.Title), fillXML))
+          <c:tx><c:strRef><c:f>%s</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>%s</c:v></c:pt></c:strCache></c:strRef></c:tx>
+%s`, idx, idx, titleRef, xmlEscape(s.Title), seriesSpPrXML(s)))
+		if s.Marker != nil {
+			sb.WriteString(seriesMarkerXML(s.Marker))
+		}
 
 		// X values
-		sb.WriteString("          <c:xVal>\n            <c:numRef><c:f>Sheet1!$A$2</c:f><c:numCache>\n")
+		sb.WriteString("          <c:xVal>\n            <c:numRef><c:f>" + xRef + "</c:f><c:numCache>\n")
 		sb.WriteString(fmt.Sprintf("              <c:formatCode>General</c:formatCode>\n              <c:ptCount val=\"%d\"/>\n", len(cats)))
 		for i, cat := range cats {
 			sb.WriteString(fmt.Sprintf("              <c:pt idx=\"%d\"><c:v>%s</c:v></c:pt>\n", i, xmlEscape(cat)))
@@ -409,15 +537,15 @@ func (w *PPTXWriter) writeScatterChartXML(c *ScatterChart, cats []string) string
 		sb.WriteString("            </c:numCache></c:numRef>\n          </c:xVal>\n")
 
 		// Y values
-		sb.WriteString("          <c:yVal>\n            <c:numRef><c:f>Sheet1!$B$2</c:f><c:numCache>\n")
-		sb.WriteString(fmt.Sprintf("              <c:formatCode>General</c:formatCode>\n              <c:ptCount val=\"%d\"/>\n", len(cats)))
+		sb.WriteString("          <c:yVal>\n            <c:numRef><c:f>" + yRef + "</c:f><c:numCache>\n")
+		sb.WriteString(fmt.Sprintf("              <c:formatCode>%s</c:formatCode>\n              <c:ptCount val=\"%d\"/>\n", seriesFormatCode(s), len(cats)))
 		for i, cat := range cats {
 			val := s.Values[cat]
 			sb.WriteString(fmt.Sprintf("              <c:pt idx=\"%d\"><c:v>%g</c:v></c:pt>\n", i, val))
 		}
 		sb.WriteString("            </c:numCache></c:numRef>\n          </c:yVal>\n")
 
-		sb.WriteString(fmt.Sprintf("          <c:smooth val=\"%s\"/>\n", smooth))
+		sb.WriteString(fmt.Sprintf("          <c:smooth val=\"%s\"/>\n", boolToXML(s.isSmooth(c.IsSmooth))))
 		sb.WriteString("        </c:ser>\n")
 	}
 
@@ -437,5 +565,44 @@ func (w *PPTXWriter) writeRadarChartXML(c *RadarChart, cats []string) string {
 %s        <c:axId val="1"/>
         <c:axId val="2"/>
       </c:radarChart>
-`, w.writeSeriesXML(c.Series, cats, true))
+`, w.writeSeriesXML(c.Series, cats, true, nil))
+}
+
+// writeChartParagraphsXML writes rich text paragraphs for a chart part.
+// Hyperlinks are dropped since chart parts carry no hyperlink relationships.
+func (w *PPTXWriter) writeChartParagraphsXML(paras []*Paragraph) string {
+	var sb strings.Builder
+	for _, para := range paras {
+		c := *para
+		c.elements = make([]ParagraphElement, len(para.elements))
+		for i, elem := range para.elements {
+			if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil {
+				r := *tr
+				r.hyperlink = nil
+				elem = &r
+			}
+			c.elements[i] = elem
+		}
+		sb.WriteString(w.writeParagraphXML(&c))
+	}
+	return sb.String()
+}
+
+// chartTxPrXML returns a <c:txPr> element applying f as the default run
+// properties of a chart text element.
+func chartTxPrXML(f *Font) string {
+	return fmt.Sprintf(`      <c:txPr>
+        <a:bodyPr/>
+        <a:lstStyle/>
+        <a:p><a:pPr>%s</a:pPr><a:endParaRPr lang="en-US"/></a:p>
+      </c:txPr>
+`, defRPrXML(f))
+}
+
+// isDefaultFont reports whether f still has the NewFont defaults.
+func isDefaultFont(f *Font) bool {
+	d := NewFont()
+	return f.Name == d.Name && f.NameEA == "" && f.Size == d.Size && !f.Bold && !f.Italic &&
+		f.Underline == d.Underline && !f.Strikethrough && !f.Superscript && !f.Subscript &&
+		f.Color.ARGB == d.Color.ARGB && f.Color.nTransforms == 0
 }
@@ -0,0 +1,147 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Chart data embedded as a workbook. The sheet holds the categories in
+// column A and one column per series, with the series title in row 1 and
+// its values from row 2, so that the formulas of the series match what
+// Edit Data shows.
+
+const (
+	relTypePackage = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/package"
+	ctSpreadsheet  = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	nsSpreadsheetML = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+
+	// relKeyWorkbook is the registry key of the embedded workbook in the
+	// relationships of a chart part.
+	relKeyWorkbook = "workbook"
+)
+
+// chartSheetColumn returns the name of the zero-based column col, such as
+// A or AB.
+func chartSheetColumn(col int) string {
+	name := ""
+	for n := col + 1; n > 0; n = (n - 1) / 26 {
+		name = string(rune('A'+(n-1)%26)) + name
+	}
+	return name
+}
+
+// chartSheetRef returns the formula of column col from row first to row
+// last, such as Sheet1!$B$2:$B$5.
+func chartSheetRef(col, first, last int) string {
+	c := chartSheetColumn(col)
+	if last <= first {
+		return fmt.Sprintf("Sheet1!$%s$%d", c, first)
+	}
+	return fmt.Sprintf("Sheet1!$%s$%d:$%s$%d", c, first, c, last)
+}
+
+// chartSeriesRefs returns the formulas of the title, the categories and
+// the values of the series at idx with n points.
+func chartSeriesRefs(idx, n int) (title, cat, val string) {
+	return chartSheetRef(idx+1, 1, 1), chartSheetRef(0, 2, n+1), chartSheetRef(idx+1, 2, n+1)
+}
+
+// chartRels registers the relationships of the chart part of chart: its
+// style parts and its embedded workbook.
+func chartRels(chart *ChartShape, chartIdx int) *relRegistry {
+	rels := newRelRegistry()
+	ct := chart.plotArea.chartType
+	if ct == nil {
+		return rels
+	}
+	if chart.style > 0 {
+		rels.add(relTypeChartStyle, relTypeChartStyle, fmt.Sprintf("style%d.xml", chartIdx))
+		rels.add(relTypeChartColors, relTypeChartColors, fmt.Sprintf("colors%d.xml", chartIdx))
+	}
+	if chart.dataSource == ChartDataWorkbook {
+		rels.add(relKeyWorkbook, relTypePackage, "../embeddings/"+chartWorkbookName(chartIdx))
+	}
+	return rels
+}
+
+func chartWorkbookName(chartIdx int) string {
+	return fmt.Sprintf("Microsoft_Excel_Worksheet%d.xlsx", chartIdx)
+}
+
+// writeChartWorkbook writes the workbook embedded in the chart, if any.
+func (w *PPTXWriter) writeChartWorkbook(zw *zip.Writer, chart *ChartShape, chartIdx int) error {
+	ct := chart.plotArea.chartType
+	if ct == nil || chart.dataSource != ChartDataWorkbook {
+		return nil
+	}
+	series := getChartSeries(ct)
+	_, scatter := ct.(*ScatterChart)
+	data, err := chartWorkbookXLSX(series, getCategories(series), scatter)
+	if err != nil {
+		return err
+	}
+	return w.writePartBytes(zw, "ppt/embeddings/"+chartWorkbookName(chartIdx), ctSpreadsheet, data)
+}
+
+// chartWorkbookXLSX returns a workbook with the data of series. Numeric
+// categories, the X values of scatter charts, are written as numbers.
+func chartWorkbookXLSX(series []*ChartSeries, categories []string, numericCats bool) ([]byte, error) {
+	var sheet strings.Builder
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="` + nsSpreadsheetML + `"><sheetData><row r="1">`)
+	for i, s := range series {
+		writeSheetStringCell(&sheet, chartSheetColumn(i+1)+"1", s.Title)
+	}
+	sheet.WriteString("</row>")
+	for j, cat := range categories {
+		row := strconv.Itoa(j + 2)
+		fmt.Fprintf(&sheet, `<row r="%s">`, row)
+		if v, err := strconv.ParseFloat(cat, 64); numericCats && err == nil {
+			fmt.Fprintf(&sheet, `<c r="A%s"><v>%g</v></c>`, row, v)
+		} else {
+			writeSheetStringCell(&sheet, "A"+row, cat)
+		}
+		for i, s := range series {
+			fmt.Fprintf(&sheet, `<c r="%s%s"><v>%g</v></c>`, chartSheetColumn(i+1), row, s.Values[cat])
+		}
+		sheet.WriteString("</row>")
+	}
+	sheet.WriteString("</sheetData></worksheet>")
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="` + nsContentTypes + `"><Default Extension="rels" ContentType="` + ctRels + `"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="` + nsRelationships + `"><Relationship Id="rId1" Type="` + relTypeOfficeDoc + `" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="` + nsSpreadsheetML + `" xmlns:r="` + nsOfficeDocRels + `"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="` + nsRelationships + `"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, p := range parts {
+		fw, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSheetStringCell writes an inline string cell, which needs no shared
+// strings part.
+func writeSheetStringCell(sb *strings.Builder, ref, s string) {
+	fmt.Fprintf(sb, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(s))
+}
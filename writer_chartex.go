@@ -0,0 +1,286 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+)
+
+// Chartex (Office 2016 chart) namespaces.
+const (
+	nsChartEx      = "http://schemas.microsoft.com/office/drawing/2014/chartex"
+	nsChartEx1     = "http://schemas.microsoft.com/office/drawing/2015/9/8/chartex"
+	nsChartEx2     = "http://schemas.microsoft.com/office/drawing/2015/10/21/chartex"
+	nsMarkupCompat = "http://schemas.openxmlformats.org/markup-compatibility/2006"
+)
+
+// isChartExType reports whether ct is written as a chartex part rather than
+// a DrawingML chart part.
+func isChartExType(ct ChartType) bool {
+	switch ct.(type) {
+	case *WaterfallChart, *FunnelChart:
+		return true
+	}
+	return false
+}
+
+// chartPartName returns the file name of the chart part with the given
+// index under ppt/charts.
+func chartPartName(ct ChartType, chartIdx int) string {
+	if isChartExType(ct) {
+		return fmt.Sprintf("chartEx%d.xml", chartIdx)
+	}
+	return fmt.Sprintf("chart%d.xml", chartIdx)
+}
+
+// chartExRequires returns the markup compatibility prefix and namespace a
+// consumer must understand to display the chartex type: funnels came after
+// the first set of chartex layouts.
+func chartExRequires(ct ChartType) (prefix, ns string) {
+	if _, ok := ct.(*FunnelChart); ok {
+		return "cx2", nsChartEx2
+	}
+	return "cx1", nsChartEx1
+}
+
+func (w *PPTXWriter) writeChartExPart(zw *zip.Writer, chart *ChartShape, chartIdx int, rels *relRegistry) error {
+	ct := chart.plotArea.chartType
+	series := getChartSeries(ct)
+
+	// Each series gets its own data block, referenced by index. With an
+	// embedded workbook the blocks and titles also carry their formulas.
+	var dataXML strings.Builder
+	workbookID := rels.id(relKeyWorkbook)
+	if workbookID != "" {
+		fmt.Fprintf(&dataXML, "    <cx:externalData r:id=\"%s\" cx:autoUpdate=\"0\"/>\n", workbookID)
+	}
+	formula := func(ref string) string {
+		if workbookID == "" {
+			return ""
+		}
+		return "<cx:f>" + ref + "</cx:f>"
+	}
+	for i, s := range series {
+		_, catRef, valRef := chartSeriesRefs(i, len(s.Categories))
+		fmt.Fprintf(&dataXML, `    <cx:data id="%d">
+      <cx:strDim type="cat">
+        %s<cx:lvl ptCount="%d">
+`, i, formula(catRef), len(s.Categories))
+		for j, cat := range s.Categories {
+			fmt.Fprintf(&dataXML, "          <cx:pt idx=\"%d\">%s</cx:pt>\n", j, xmlEscape(cat))
+		}
+		fmt.Fprintf(&dataXML, `        </cx:lvl>
+      </cx:strDim>
+      <cx:numDim type="val">
+        %s<cx:lvl ptCount="%d" formatCode="%s">
+`, formula(valRef), len(s.Categories), seriesFormatCode(s))
+		for j, cat := range s.Categories {
+			fmt.Fprintf(&dataXML, "          <cx:pt idx=\"%d\">%g</cx:pt>\n", j, s.Values[cat])
+		}
+		dataXML.WriteString(`        </cx:lvl>
+      </cx:numDim>
+    </cx:data>
+`)
+	}
+
+	layoutID := ct.GetChartTypeName()
+	var seriesXML strings.Builder
+	for i, s := range series {
+		titleRef, _, _ := chartSeriesRefs(i, len(s.Categories))
+		fmt.Fprintf(&seriesXML, `        <cx:series layoutId="%s">
+          <cx:tx><cx:txData>%s<cx:v>%s</cx:v></cx:txData></cx:tx>
+`, layoutID, formula(titleRef), xmlEscape(s.Title))
+		if s.FillColor.ARGB != "" {
+			fmt.Fprintf(&seriesXML, "          <cx:spPr><a:solidFill>%s</a:solidFill></cx:spPr>\n", colorXML(s.FillColor))
+		}
+		seriesXML.WriteString(chartExDataLabelsXML(s))
+		fmt.Fprintf(&seriesXML, "          <cx:dataId val=\"%d\"/>\n", i)
+		if wf, ok := ct.(*WaterfallChart); ok {
+			seriesXML.WriteString("          <cx:layoutPr>\n")
+			if !wf.ConnectorLines {
+				seriesXML.WriteString("            <cx:visibility connectorLines=\"0\"/>\n")
+			}
+			if len(wf.Subtotals) > 0 {
+				seriesXML.WriteString("            <cx:subtotals>\n")
+				for _, idx := range wf.Subtotals {
+					fmt.Fprintf(&seriesXML, "              <cx:idx val=\"%d\"/>\n", idx)
+				}
+				seriesXML.WriteString("            </cx:subtotals>\n")
+			}
+			seriesXML.WriteString("          </cx:layoutPr>\n")
+		}
+		seriesXML.WriteString("        </cx:series>\n")
+	}
+
+	var axisXML string
+	switch c := ct.(type) {
+	case *WaterfallChart:
+		axisXML = `      <cx:axis id="0">
+        <cx:catScaling gapWidth="0.5"/>
+        <cx:tickLabels/>
+      </cx:axis>
+` + w.chartExValueAxisXML(chart)
+	case *FunnelChart:
+		axisXML = fmt.Sprintf(`      <cx:axis id="0">
+        <cx:catScaling gapWidth="%g"/>
+        <cx:tickLabels/>
+      </cx:axis>
+`, float64(c.GapWidthPercent)/100)
+	}
+
+	// Title XML
+	titleXML := ""
+	if chart.title.Visible && len(chart.title.paragraphs) > 0 {
+		titleXML = fmt.Sprintf(`    <cx:title pos="t" align="ctr" overlay="0">
+      <cx:tx>
+        <cx:rich>
+          <a:bodyPr/>
+          <a:lstStyle/>
+%s        </cx:rich>
+      </cx:tx>
+    </cx:title>
+`, w.writeChartParagraphsXML(chart.title.centeredParagraphs()))
+	} else if chart.title.Visible && chart.title.Text != "" {
+		titleXML = fmt.Sprintf(`    <cx:title pos="t" align="ctr" overlay="0">
+      <cx:tx><cx:txData><cx:v>%s</cx:v></cx:txData></cx:tx>
+%s    </cx:title>
+`, xmlEscape(chart.title.Text), chartExTxPrXML(chart.title.Font))
+	}
+
+	// Legend XML: chartex has no top-right position
+	legendXML := ""
+	if chart.legend.Visible {
+		pos := chart.legend.Position
+		if pos == LegendTopRight {
+			pos = LegendRight
+		}
+		legendXML = fmt.Sprintf("    <cx:legend pos=\"%s\" align=\"ctr\" overlay=\"0\"/>\n", xmlEscape(string(pos)))
+	}
+
+	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cx:chartSpace xmlns:a="%s" xmlns:r="%s" xmlns:cx="%s">
+  <cx:chartData>
+%s  </cx:chartData>
+  <cx:chart>
+%s    <cx:plotArea>
+      <cx:plotAreaRegion>
+%s      </cx:plotAreaRegion>
+%s    </cx:plotArea>
+%s  </cx:chart>
+</cx:chartSpace>`,
+		nsDrawingML, nsOfficeDocRels, nsChartEx,
+		dataXML.String(),
+		titleXML,
+		seriesXML.String(),
+		axisXML,
+		legendXML)
+
+	return w.writeRawPart(zw, "ppt/charts/"+chartPartName(ct, chartIdx), ctChartEx, content)
+}
+
+// chartExValueAxisXML returns the value axis of a chartex chart, with the
+// bounds and unit of the chart's Y axis and the same automatic major unit
+// as the renderer.
+func (w *PPTXWriter) chartExValueAxisXML(chart *ChartShape) string {
+	axY := chart.plotArea.axisY
+	var attrs strings.Builder
+	if axY.MinBounds != nil && isFinite(*axY.MinBounds) {
+		fmt.Fprintf(&attrs, ` min="%g"`, *axY.MinBounds)
+	}
+	if axY.MaxBounds != nil && isFinite(*axY.MaxBounds) {
+		fmt.Fprintf(&attrs, ` max="%g"`, *axY.MaxBounds)
+	}
+	if axY.MajorUnit != nil && isFinite(*axY.MajorUnit) {
+		fmt.Fprintf(&attrs, ` majorUnit="%g"`, *axY.MajorUnit)
+	} else if scale, ok := valueAxisScale(chart.plotArea.chartType, axY); ok && isFinite(scale.MajorUnit) {
+		fmt.Fprintf(&attrs, ` majorUnit="%g"`, scale.MajorUnit)
+	}
+	if axY.MinorUnit != nil && isFinite(*axY.MinorUnit) {
+		fmt.Fprintf(&attrs, ` minorUnit="%g"`, *axY.MinorUnit)
+	}
+	hidden := ""
+	if !axY.Visible {
+		hidden = ` hidden="1"`
+	}
+	gridXML := ""
+	if axY.MajorGridlines != nil {
+		gridXML = "        <cx:majorGridlines/>\n"
+	}
+	return fmt.Sprintf(`      <cx:axis id="1"%s>
+        <cx:valScaling%s/>
+%s        <cx:tickLabels/>
+      </cx:axis>
+`, hidden, attrs.String(), gridXML)
+}
+
+// chartExDataLabelsXML returns the data labels of a chartex series, or "".
+func chartExDataLabelsXML(s *ChartSeries) string {
+	if !s.ShowValue && !s.ShowCategoryName && !s.ShowSeriesName {
+		return ""
+	}
+	pos := ""
+	if s.LabelPosition != "" {
+		pos = xmlAttr("pos", s.LabelPosition)
+	}
+	numFmt := ""
+	if s.NumberFormat != "" {
+		numFmt = fmt.Sprintf("            <cx:numFmt formatCode=\"%s\" sourceLinked=\"0\"/>\n", xmlEscape(s.NumberFormat))
+	}
+	return fmt.Sprintf(`          <cx:dataLabels%s>
+%s            <cx:visibility seriesName="%s" categoryName="%s" value="%s"/>
+          </cx:dataLabels>
+`, pos, numFmt, boolToXML(s.ShowSeriesName), boolToXML(s.ShowCategoryName), boolToXML(s.ShowValue))
+}
+
+// chartExTxPrXML returns the text properties of a chartex title, or "" for
+// the default font.
+func chartExTxPrXML(f *Font) string {
+	if f == nil || isDefaultFont(f) {
+		return ""
+	}
+	return strings.ReplaceAll(chartTxPrXML(f), "c:txPr", "cx:txPr")
+}
+
+// chartExFrameXML wraps the graphic frame of a chartex chart in markup
+// compatibility content so that consumers without chartex support show a
+// placeholder shape instead.
+func chartExFrameXML(ct ChartType, frameXML string, id int, name string, s *BaseShape) string {
+	prefix, ns := chartExRequires(ct)
+	frameXML = "    " + strings.ReplaceAll(strings.TrimSuffix(frameXML, "\n"), "\n", "\n    ") + "\n"
+	return fmt.Sprintf(`      <mc:AlternateContent xmlns:mc="%s">
+        <mc:Choice xmlns:%s="%s" Requires="%s">
+%s        </mc:Choice>
+        <mc:Fallback>
+          <p:sp>
+            <p:nvSpPr>
+              <p:cNvPr id="%d" name="%s"/>
+              <p:cNvSpPr>
+                <a:spLocks noTextEdit="1"/>
+              </p:cNvSpPr>
+              <p:nvPr/>
+            </p:nvSpPr>
+            <p:spPr>
+              <a:xfrm%s>
+                <a:off x="%d" y="%d"/>
+                <a:ext cx="%d" cy="%d"/>
+              </a:xfrm>
+              <a:prstGeom prst="rect">
+                <a:avLst/>
+              </a:prstGeom>
+            </p:spPr>
+            <p:txBody>
+              <a:bodyPr vertOverflow="clip" horzOverflow="clip"/>
+              <a:lstStyle/>
+              <a:p>
+                <a:r>
+                  <a:rPr lang="en-US" sz="1100"/>
+                  <a:t>This chart isn't available in your version of PowerPoint.</a:t>
+                </a:r>
+              </a:p>
+            </p:txBody>
+          </p:sp>
+        </mc:Fallback>
+      </mc:AlternateContent>
+`, nsMarkupCompat, prefix, ns, prefix, frameXML, id, xmlEscape(name),
+		xfrmAttrs(s), s.offsetX, s.offsetY, s.width, s.height)
+}
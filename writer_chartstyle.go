@@ -0,0 +1,150 @@
+package gopresentation
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+)
+
+// Chart style companion parts (Office 2013 and later).
+const (
+	nsChartStyle = "http://schemas.microsoft.com/office/drawing/2012/chartStyle"
+
+	relTypeChartStyle  = "http://schemas.microsoft.com/office/2011/relationships/chartStyle"
+	relTypeChartColors = "http://schemas.microsoft.com/office/2011/relationships/chartColorStyle"
+
+	ctChartStyle  = "application/vnd.ms-office.chartstyle+xml"
+	ctChartColors = "application/vnd.ms-office.chartcolorstyle+xml"
+
+	// chartColorStyleID is the colorful palette cycling through the theme
+	// accents, PowerPoint's default.
+	chartColorStyleID = 10
+)
+
+// chartStyleEntry describes one element of a chart style part.
+type chartStyleEntry struct {
+	name string
+	// lnRef, fillRef and effectRef are theme style matrix indexes; auto
+	// colors them with the series color.
+	lnRef, fillRef, effectRef int
+	auto                      bool
+	// fontSize is the default text size in hundredths of a point, 0 for
+	// elements without text of their own.
+	fontSize int
+	spPr     string
+}
+
+// Shape properties shared by several style entries.
+const (
+	chartStyleGridLine = `<a:ln w="9525" cap="flat" cmpd="sng" algn="ctr"><a:solidFill><a:schemeClr val="tx1"><a:lumMod val="15000"/><a:lumOff val="85000"/></a:schemeClr></a:solidFill><a:round/></a:ln>`
+	chartStyleAxisLine = `<a:ln w="9525" cap="flat" cmpd="sng" algn="ctr"><a:solidFill><a:schemeClr val="tx1"><a:lumMod val="25000"/><a:lumOff val="75000"/></a:schemeClr></a:solidFill><a:round/></a:ln>`
+	chartStyleNoFill   = `<a:noFill/><a:ln><a:noFill/></a:ln>`
+)
+
+// chartStyleEntries lists the elements of a chart style in schema order.
+var chartStyleEntries = []chartStyleEntry{
+	{name: "axisTitle", fontSize: 1330},
+	{name: "categoryAxis", fontSize: 1197, spPr: `<a:noFill/>` + chartStyleAxisLine},
+	{name: "chartArea", fontSize: 1330, spPr: `<a:solidFill><a:schemeClr val="bg1"/></a:solidFill><a:ln w="9525" cap="flat" cmpd="sng" algn="ctr"><a:solidFill><a:schemeClr val="tx1"><a:lumMod val="15000"/><a:lumOff val="85000"/></a:schemeClr></a:solidFill><a:round/></a:ln>`},
+	{name: "dataLabel", fontSize: 1197},
+	{name: "dataLabelCallout", fontSize: 1197, spPr: `<a:solidFill><a:schemeClr val="lt1"/></a:solidFill><a:ln><a:solidFill><a:schemeClr val="dk1"/></a:solidFill></a:ln>`},
+	{name: "dataPoint", fillRef: 1, auto: true, spPr: `<a:solidFill><a:schemeClr val="phClr"/></a:solidFill>`},
+	{name: "dataPoint3D", fillRef: 1, auto: true, spPr: `<a:solidFill><a:schemeClr val="phClr"/></a:solidFill>`},
+	{name: "dataPointLine", auto: true, spPr: `<a:ln w="28575" cap="rnd"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:round/></a:ln>`},
+	{name: "dataPointMarker", auto: true, spPr: `<a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:ln w="9525"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln>`},
+	{name: "dataPointMarkerLayout"},
+	{name: "dataPointWireframe", auto: true, spPr: `<a:ln w="9525" cap="rnd"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:round/></a:ln>`},
+	{name: "dataTable", fontSize: 1197, spPr: `<a:noFill/>` + chartStyleGridLine},
+	{name: "downBar", spPr: `<a:solidFill><a:schemeClr val="dk1"><a:lumMod val="65000"/><a:lumOff val="35000"/></a:schemeClr></a:solidFill><a:ln w="9525"><a:solidFill><a:schemeClr val="tx1"><a:lumMod val="65000"/><a:lumOff val="35000"/></a:schemeClr></a:solidFill></a:ln>`},
+	{name: "dropLine", spPr: chartStyleGridLine},
+	{name: "errorBar", spPr: chartStyleGridLine},
+	{name: "floor", spPr: chartStyleNoFill},
+	{name: "gridlineMajor", spPr: chartStyleGridLine},
+	{name: "gridlineMinor", spPr: chartStyleGridLine},
+	{name: "hiLoLine", spPr: chartStyleGridLine},
+	{name: "leaderLine", spPr: chartStyleGridLine},
+	{name: "legend", fontSize: 1197},
+	{name: "plotArea"},
+	{name: "plotArea3D"},
+	{name: "seriesAxis", fontSize: 1197},
+	{name: "seriesLine", spPr: chartStyleGridLine},
+	{name: "title", fontSize: 1862},
+	{name: "trendline", auto: true, spPr: `<a:ln w="19050" cap="rnd"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill><a:prstDash val="sysDot"/></a:ln>`},
+	{name: "trendlineLabel", fontSize: 1197},
+	{name: "upBar", spPr: `<a:solidFill><a:schemeClr val="lt1"/></a:solidFill><a:ln w="9525"><a:solidFill><a:schemeClr val="tx1"><a:lumMod val="15000"/><a:lumOff val="85000"/></a:schemeClr></a:solidFill></a:ln>`},
+	{name: "valueAxis", fontSize: 1197},
+	{name: "wall", spPr: chartStyleNoFill},
+}
+
+// chartStyleXML returns the chart style part for style id.
+func chartStyleXML(id int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cs:chartStyle xmlns:cs="%s" xmlns:a="%s" id="%d">
+`, nsChartStyle, nsDrawingML, id)
+	for _, e := range chartStyleEntries {
+		if e.name == "dataPointMarkerLayout" {
+			sb.WriteString("  <cs:dataPointMarkerLayout symbol=\"circle\" size=\"5\"/>\n")
+			continue
+		}
+		ref := func(name string, idx int) string {
+			if e.auto {
+				return fmt.Sprintf(`<cs:%s idx="%d"><cs:styleClr val="auto"/></cs:%s>`, name, idx, name)
+			}
+			return fmt.Sprintf(`<cs:%s idx="%d"/>`, name, idx)
+		}
+		fontClr := `<a:schemeClr val="tx1"/>`
+		if e.fontSize > 0 {
+			fontClr = `<a:schemeClr val="tx1"><a:lumMod val="65000"/><a:lumOff val="35000"/></a:schemeClr>`
+		}
+		fmt.Fprintf(&sb, "  <cs:%s>%s%s%s<cs:fontRef idx=\"minor\">%s</cs:fontRef>",
+			e.name, ref("lnRef", e.lnRef), ref("fillRef", e.fillRef), ref("effectRef", e.effectRef), fontClr)
+		if e.spPr != "" {
+			sb.WriteString("<cs:spPr>" + e.spPr + "</cs:spPr>")
+		}
+		if e.fontSize > 0 {
+			fmt.Fprintf(&sb, `<cs:defRPr sz="%d"/>`, e.fontSize)
+		}
+		fmt.Fprintf(&sb, "</cs:%s>\n", e.name)
+	}
+	sb.WriteString("</cs:chartStyle>")
+	return sb.String()
+}
+
+// chartColorsXML returns the chart colors part: the theme accents, then
+// darker and lighter variations of them for charts with more series.
+func chartColorsXML() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cs:colorStyle xmlns:cs="%s" xmlns:a="%s" meth="cycle" id="%d">
+`, nsChartStyle, nsDrawingML, chartColorStyleID)
+	for i := 1; i <= 6; i++ {
+		fmt.Fprintf(&sb, "  <a:schemeClr val=\"accent%d\"/>\n", i)
+	}
+	sb.WriteString(`  <cs:variation/>
+  <cs:variation><a:lumMod val="60000"/></cs:variation>
+  <cs:variation><a:lumMod val="80000"/><a:lumOff val="20000"/></cs:variation>
+  <cs:variation><a:lumMod val="80000"/></cs:variation>
+  <cs:variation><a:lumMod val="60000"/><a:lumOff val="40000"/></cs:variation>
+  <cs:variation><a:lumMod val="50000"/></cs:variation>
+  <cs:variation><a:lumMod val="70000"/><a:lumOff val="30000"/></cs:variation>
+  <cs:variation><a:lumMod val="70000"/></cs:variation>
+  <cs:variation><a:lumMod val="50000"/><a:lumOff val="50000"/></cs:variation>
+</cs:colorStyle>`)
+	return sb.String()
+}
+
+// writeChartStyleParts writes the style and colors parts of the chart with
+// the given index, which chartRels relates to the chart. Charts without a
+// style have neither.
+func (w *PPTXWriter) writeChartStyleParts(zw *zip.Writer, chart *ChartShape, chartIdx int) error {
+	if chart.style <= 0 || chart.plotArea.chartType == nil {
+		return nil
+	}
+	styleName := fmt.Sprintf("style%d.xml", chartIdx)
+	colorsName := fmt.Sprintf("colors%d.xml", chartIdx)
+	if err := w.writeRawPart(zw, "ppt/charts/"+styleName, ctChartStyle, chartStyleXML(chart.style)); err != nil {
+		return err
+	}
+	return w.writeRawPart(zw, "ppt/charts/"+colorsName, ctChartColors, chartColorsXML())
+}
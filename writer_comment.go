@@ -54,7 +54,7 @@ func (w *PPTXWriter) writeCommentAuthors(zw *zip.Writer) error {
 	content += `
 </p:cmAuthorLst>`
 
-	return writeRawXMLToZip(zw, "ppt/commentAuthors.xml", content)
+	return w.writeRawPart(zw, "ppt/commentAuthors.xml", ctCommentAuthors, content)
 }
 
 func (w *PPTXWriter) writeSlideComments(zw *zip.Writer, slide *Slide, slideNum int) error {
@@ -84,5 +84,5 @@ func (w *PPTXWriter) writeSlideComments(zw *zip.Writer, slide *Slide, slideNum i
 	content += `
 </p:cmLst>`
 
-	return writeRawXMLToZip(zw, fmt.Sprintf("ppt/comments/comment%d.xml", slideNum), content)
+	return w.writeRawPart(zw, fmt.Sprintf("ppt/comments/comment%d.xml", slideNum), ctComments, content)
 }
@@ -0,0 +1,194 @@
+package gopresentation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Package is the package a PPTXWriter is about to write, as seen by the
+// hooks registered with OnBeforeWrite. Hooks can add parts and
+// relationships and rewrite generated parts, for example to insert an
+// extension list, without changes to the writer itself.
+type Package struct {
+	presentation *Presentation
+	parts        []customPart
+	rels         []customRel
+	transforms   map[string][]func(data []byte) ([]byte, error)
+}
+
+// customPart is a part added by the caller rather than generated.
+type customPart struct {
+	name        string
+	contentType string
+	data        []byte
+}
+
+// customRel is a relationship added by the caller.
+type customRel struct {
+	source   string
+	id       string
+	relType  string
+	target   string
+	external bool
+}
+
+// Presentation returns the presentation being written.
+func (pkg *Package) Presentation() *Presentation {
+	return pkg.presentation
+}
+
+// AddPart adds a part to the package. name is the part's path in the
+// package, such as "customXml/item1.xml"; contentType is written to
+// [Content_Types].xml. Adding a part with the name of a generated part
+// makes the write fail.
+func (pkg *Package) AddPart(name, contentType string, data []byte) {
+	pkg.parts = append(pkg.parts, customPart{name: partPath(name), contentType: contentType, data: data})
+}
+
+// AddRelationship adds a relationship from the part source to target, which
+// is relative to source. source is "" for the package itself,
+// "ppt/presentation.xml" or a slide such as "ppt/slides/slide1.xml". With
+// an empty id an unused ID is allocated; otherwise the write fails if id is
+// already taken in that part.
+func (pkg *Package) AddRelationship(source, id, relType, target string) {
+	pkg.rels = append(pkg.rels, customRel{source: partPath(source), id: id, relType: relType, target: target})
+}
+
+// AddExternalRelationship is like AddRelationship for a target outside the
+// package, such as a URL.
+func (pkg *Package) AddExternalRelationship(source, id, relType, target string) {
+	pkg.rels = append(pkg.rels, customRel{source: partPath(source), id: id, relType: relType, target: target, external: true})
+}
+
+// TransformPart registers fn to rewrite the content of the generated part
+// name just before it is stored. Transforms of a part run in the order they
+// were registered. The write fails if no part of that name is written.
+func (pkg *Package) TransformPart(name string, fn func(data []byte) ([]byte, error)) {
+	if pkg.transforms == nil {
+		pkg.transforms = make(map[string][]func([]byte) ([]byte, error))
+	}
+	name = partPath(name)
+	pkg.transforms[name] = append(pkg.transforms[name], fn)
+}
+
+// partPath returns name without a leading slash, as parts are named in the
+// zip archive.
+func partPath(name string) string {
+	return strings.TrimPrefix(name, "/")
+}
+
+// AddPart adds a part to every package the writer writes. See
+// Package.AddPart.
+func (w *PPTXWriter) AddPart(name, contentType string, data []byte) {
+	w.parts = append(w.parts, customPart{name: partPath(name), contentType: contentType, data: data})
+}
+
+// OnBeforeWrite registers fn to be called at the start of every write,
+// before any part is generated. Hooks run in the order they were
+// registered.
+func (w *PPTXWriter) OnBeforeWrite(fn func(pkg *Package)) {
+	w.hooks = append(w.hooks, fn)
+}
+
+// slidePartPattern matches the part names of slides.
+var slidePartPattern = regexp.MustCompile(`^ppt/slides/slide([1-9][0-9]*)\.xml$`)
+
+// newPackage returns the package for one write, after running the hooks.
+// Relationships from parts whose relationships are not generated from a
+// registry are rejected.
+func (w *PPTXWriter) newPackage() (*Package, error) {
+	pkg := &Package{presentation: w.presentation}
+	pkg.parts = append(pkg.parts, w.parts...)
+	for _, fn := range w.hooks {
+		fn(pkg)
+	}
+	for _, r := range pkg.rels {
+		if r.source == "" || r.source == "ppt/presentation.xml" {
+			continue
+		}
+		m := slidePartPattern.FindStringSubmatch(r.source)
+		if m == nil {
+			return nil, fmt.Errorf("cannot add relationships to %s", r.source)
+		}
+		if n, _ := strconv.Atoi(m[1]); n > len(w.presentation.slides) {
+			return nil, fmt.Errorf("cannot add relationships to %s: no such slide", r.source)
+		}
+	}
+	return pkg, nil
+}
+
+// reserveCustomRels adds the caller's relationships from source that have
+// fixed IDs to rels. It runs before the generated relationships are added
+// so that allocated IDs cannot take them.
+func (pkg *Package) reserveCustomRels(rels *relRegistry, source string) error {
+	if pkg == nil {
+		return nil
+	}
+	for i := range pkg.rels {
+		r := &pkg.rels[i]
+		if r.source != source || r.id == "" {
+			continue
+		}
+		if err := rels.reserveRel(r, r.xml(r.id)); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	return nil
+}
+
+// addCustomRels adds the caller's relationships from source without fixed
+// IDs to rels, after the generated ones.
+func (pkg *Package) addCustomRels(rels *relRegistry, source string) {
+	if pkg == nil {
+		return
+	}
+	for i := range pkg.rels {
+		if r := &pkg.rels[i]; r.source == source && r.id == "" {
+			rels.addRel(r, r.xml(""))
+		}
+	}
+}
+
+func (r *customRel) xml(id string) xmlRelationship {
+	rel := xmlRelationship{ID: id, Type: r.relType, Target: r.target}
+	if r.external {
+		rel.TargetMode = "External"
+	}
+	return rel
+}
+
+// transform applies the transforms registered for the part name to data.
+func (pkg *Package) transform(name string, data []byte) ([]byte, error) {
+	if pkg == nil {
+		return data, nil
+	}
+	fns, ok := pkg.transforms[name]
+	if !ok {
+		return data, nil
+	}
+	delete(pkg.transforms, name)
+	for _, fn := range fns {
+		var err error
+		if data, err = fn(data); err != nil {
+			return nil, fmt.Errorf("transform %s: %w", name, err)
+		}
+	}
+	return data, nil
+}
+
+// checkTransformsUsed reports transforms registered for parts that were
+// never written, which usually means a misspelled part name.
+func (pkg *Package) checkTransformsUsed() error {
+	if len(pkg.transforms) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(pkg.transforms))
+	for name := range pkg.transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("transform %s: no such part", strings.Join(names, ", "))
+}
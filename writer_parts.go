@@ -10,35 +10,78 @@ import (
 func (w *PPTXWriter) writePresentation(zw *zip.Writer) error {
 	layout := w.presentation.layout
 
+	firstSlideNum := ""
+	if n := w.presentation.GetFirstSlideNumber(); n != 1 {
+		firstSlideNum = fmt.Sprintf(` firstSlideNum="%d"`, n)
+	}
+
+	sizeType := ""
+	if t := layout.slideSizeType(); t != "" {
+		sizeType = fmt.Sprintf(` type="%s"`, t)
+	}
+
 	slideList := ""
-	relIdx := 2 // rId1 is slideMaster
-	for i := range w.presentation.slides {
-		slideList += fmt.Sprintf(`    <p:sldId id="%d" r:id="rId%d"/>
-`, 256+i, relIdx)
-		relIdx++
+	for i, slide := range w.presentation.slides {
+		slideList += fmt.Sprintf(`    <p:sldId id="%d" r:id="%s"/>
+`, 256+i, w.presRels.id(slide))
+	}
+
+	notesMaster := ""
+	if id := w.presRels.id(relTypeNotesMaster); id != "" {
+		notesMaster = fmt.Sprintf(`
+  <p:notesMasterIdLst>
+    <p:notesMasterId r:id="%s"/>
+  </p:notesMasterIdLst>`, id)
 	}
 
 	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<p:presentation xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
+<p:presentation xmlns:a="%s" xmlns:r="%s" xmlns:p="%s"%s>
   <p:sldMasterIdLst>
-    <p:sldMasterId id="2147483648" r:id="rId1"/>
-  </p:sldMasterIdLst>
+    <p:sldMasterId id="2147483648" r:id="%s"/>
+  </p:sldMasterIdLst>%s
   <p:sldIdLst>
 %s  </p:sldIdLst>
-  <p:sldSz cx="%d" cy="%d" type="%s"/>
+  <p:sldSz cx="%d" cy="%d"%s/>
   <p:notesSz cx="%d" cy="%d"/>
   <p:defaultTextStyle/>
 </p:presentation>`,
-		nsDrawingML, nsOfficeDocRels, nsPresentationML,
+		nsDrawingML, nsOfficeDocRels, nsPresentationML, firstSlideNum,
+		w.presRels.id(relKeyMaster), notesMaster,
 		slideList,
-		layout.CX, layout.CY, layout.Name,
+		layout.CX, layout.CY, sizeType,
 		layout.CY, layout.CX, // notes are rotated
 	)
-	return writeRawXMLToZip(zw, "ppt/presentation.xml", content)
+	return w.writeRawPart(zw, "ppt/presentation.xml", w.documentType().contentType(), content)
+}
+
+// --- VBA Project ---
+
+// writeVBAProject writes the VBA project with its related parts, such as
+// its signature, and their relationships.
+func (w *PPTXWriter) writeVBAProject(zw *zip.Writer) error {
+	vba := w.presentation.vbaProject
+	if err := w.writePartBytes(zw, "ppt/vbaProject.bin", ctVBAProject, vba.data); err != nil {
+		return err
+	}
+	if len(vba.parts) == 0 {
+		return nil
+	}
+	rels := newRelRegistry()
+	for i := range vba.parts {
+		part := &vba.parts[i]
+		if err := w.writePartBytes(zw, part.name, part.contentType, part.data); err != nil {
+			return err
+		}
+		rels.add(part, part.relType, part.target)
+	}
+	return w.writeRels(zw, "ppt/_rels/vbaProject.bin.rels", rels)
 }
 
 // --- Presentation Properties ---
 
+// extURILaserColor is the extension of p:showPr holding p14:laserClr.
+const extURILaserColor = "{EC167BDD-8182-4AB7-AECC-EB403E3ABB37}"
+
 func (w *PPTXWriter) writePresProps(zw *zip.Writer) error {
 	pp := w.presentation.presentationProperties
 
@@ -52,13 +95,38 @@ func (w *PPTXWriter) writePresProps(zw *zip.Writer) error {
 		showType = `<p:kiosk/>`
 	}
 
+	attrs := fmt.Sprintf(` showNarration="%s" showAnimation="%s"`, boolToXML(pp.showNarration), boolToXML(pp.showAnimation))
+	if pp.loop {
+		attrs = ` loop="1"` + attrs
+	}
+	if !pp.useTimings {
+		attrs += ` useTimings="0"`
+	}
+
+	showRange := ""
+	if pp.rangeStart > 0 {
+		showRange = fmt.Sprintf(`
+    <p:sldRg st="%d" end="%d"/>`, pp.rangeStart, pp.rangeEnd)
+	}
+
+	penXML := ""
+	if pp.penColor != nil {
+		penXML = fmt.Sprintf(`
+    <p:penClr>%s</p:penClr>`, colorXML(*pp.penColor))
+	}
+	if pp.laserColor != nil {
+		penXML += fmt.Sprintf(`
+    <p:extLst><p:ext uri="%s"><p14:laserClr xmlns:p14="%s">%s</p14:laserClr></p:ext></p:extLst>`,
+			extURILaserColor, nsP14, colorXML(*pp.laserColor))
+	}
+
 	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <p:presentationPr xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
-  <p:showPr>
-    %s
+  <p:showPr%s>
+    %s%s%s
   </p:showPr>
-</p:presentationPr>`, nsDrawingML, nsOfficeDocRels, nsPresentationML, showType)
-	return writeRawXMLToZip(zw, "ppt/presProps.xml", content)
+</p:presentationPr>`, nsDrawingML, nsOfficeDocRels, nsPresentationML, attrs, showType, showRange, penXML)
+	return w.writeRawPart(zw, "ppt/presProps.xml", ctPresProps, content)
 }
 
 // --- View Properties ---
@@ -98,7 +166,7 @@ func (w *PPTXWriter) writeViewProps(zw *zip.Writer) error {
   </p:slideViewPr>
 </p:viewPr>`, nsDrawingML, nsOfficeDocRels, nsPresentationML, lastView,
 		int(pp.zoom*100), int(pp.zoom*100))
-	return writeRawXMLToZip(zw, "ppt/viewProps.xml", content)
+	return w.writeRawPart(zw, "ppt/viewProps.xml", ctViewProps, content)
 }
 
 // --- Table Styles ---
@@ -106,13 +174,20 @@ func (w *PPTXWriter) writeViewProps(zw *zip.Writer) error {
 func (w *PPTXWriter) writeTableStyles(zw *zip.Writer) error {
 	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <a:tblStyleLst xmlns:a="%s" def="{5C22544A-7EE6-4342-B048-85BDC9FD1C3A}"/>`, nsDrawingML)
-	return writeRawXMLToZip(zw, "ppt/tableStyles.xml", content)
+	return w.writeRawPart(zw, "ppt/tableStyles.xml", ctTableStyles, content)
 }
 
 // --- Slide Master ---
 
 func (w *PPTXWriter) writeSlideMaster(zw *zip.Writer) error {
-	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+	rels, err := w.buildMasterRels()
+	if err != nil {
+		return err
+	}
+	buf := getXMLBuffer()
+	defer putXMLBuffer(buf)
+
+	fmt.Fprintf(buf, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <p:sldMaster xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
   <p:cSld>
     <p:bg>
@@ -134,25 +209,33 @@ func (w *PPTXWriter) writeSlideMaster(zw *zip.Writer) error {
           <a:chExt cx="0" cy="0"/>
         </a:xfrm>
       </p:grpSpPr>
-    </p:spTree>
+`, nsDrawingML, nsOfficeDocRels, nsPresentationML)
+
+	// Shapes look up their relationship IDs while they are written
+	w.slideRels = rels
+	w.slideIndex = 0
+	w.shapePart = "ppt/slideMasters/slideMaster1.xml"
+	defer func() { w.slideRels = nil }()
+	var shapes []Shape
+	for _, shape := range w.presentation.masterShapes() {
+		if _, ok := shape.(*ChartShape); !ok {
+			shapes = append(shapes, shape)
+		}
+	}
+	w.writeShapeTreeXML(buf, shapes, 0)
+
+	buf.WriteString(`    </p:spTree>
   </p:cSld>
   <p:clrMap bg1="lt1" tx1="dk1" bg2="lt2" tx2="dk2" accent1="accent1" accent2="accent2" accent3="accent3" accent4="accent4" accent5="accent5" accent6="accent6" hlink="hlink" folHlink="folHlink"/>
   <p:sldLayoutIdLst>
     <p:sldLayoutId id="2147483649" r:id="rId1"/>
   </p:sldLayoutIdLst>
-</p:sldMaster>`, nsDrawingML, nsOfficeDocRels, nsPresentationML)
+</p:sldMaster>`)
 
-	if err := writeRawXMLToZip(zw, "ppt/slideMasters/slideMaster1.xml", content); err != nil {
+	if err := w.writePartBytes(zw, "ppt/slideMasters/slideMaster1.xml", ctSlideMaster, buf.Bytes()); err != nil {
 		return err
 	}
-
-	// Slide master rels
-	rels := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Relationships xmlns="%s">
-  <Relationship Id="rId1" Type="%s" Target="../slideLayouts/slideLayout1.xml"/>
-  <Relationship Id="rId2" Type="%s" Target="../theme/theme1.xml"/>
-</Relationships>`, nsRelationships, relTypeSlideLayout, relTypeTheme)
-	return writeRawXMLToZip(zw, "ppt/slideMasters/_rels/slideMaster1.xml.rels", rels)
+	return w.writeRels(zw, "ppt/slideMasters/_rels/slideMaster1.xml.rels", rels)
 }
 
 // --- Slide Layout ---
@@ -182,7 +265,7 @@ func (w *PPTXWriter) writeSlideLayout(zw *zip.Writer) error {
   </p:clrMapOvr>
 </p:sldLayout>`, nsDrawingML, nsOfficeDocRels, nsPresentationML)
 
-	if err := writeRawXMLToZip(zw, "ppt/slideLayouts/slideLayout1.xml", content); err != nil {
+	if err := w.writeRawPart(zw, "ppt/slideLayouts/slideLayout1.xml", ctSlideLayout, content); err != nil {
 		return err
 	}
 
@@ -191,40 +274,28 @@ func (w *PPTXWriter) writeSlideLayout(zw *zip.Writer) error {
 <Relationships xmlns="%s">
   <Relationship Id="rId1" Type="%s" Target="../slideMasters/slideMaster1.xml"/>
 </Relationships>`, nsRelationships, relTypeSlideMaster)
-	return writeRawXMLToZip(zw, "ppt/slideLayouts/_rels/slideLayout1.xml.rels", rels)
+	return w.writeRawPart(zw, "ppt/slideLayouts/_rels/slideLayout1.xml.rels", ctRels, rels)
 }
 
 // --- Theme ---
 
 func (w *PPTXWriter) writeTheme(zw *zip.Writer) error {
-	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+	return w.writeRawPart(zw, "ppt/theme/theme1.xml", ctTheme, w.themeXML())
+}
+
+// themeXML returns the theme of the presentation, with its colors and
+// fonts.
+func (w *PPTXWriter) themeXML() string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <a:theme xmlns:a="%s" name="Office Theme">
   <a:themeElements>
     <a:clrScheme name="Office">
-      <a:dk1><a:sysClr val="windowText" lastClr="000000"/></a:dk1>
-      <a:lt1><a:sysClr val="window" lastClr="FFFFFF"/></a:lt1>
-      <a:dk2><a:srgbClr val="44546A"/></a:dk2>
-      <a:lt2><a:srgbClr val="E7E6E6"/></a:lt2>
-      <a:accent1><a:srgbClr val="4472C4"/></a:accent1>
-      <a:accent2><a:srgbClr val="ED7D31"/></a:accent2>
-      <a:accent3><a:srgbClr val="A5A5A5"/></a:accent3>
-      <a:accent4><a:srgbClr val="FFC000"/></a:accent4>
-      <a:accent5><a:srgbClr val="5B9BD5"/></a:accent5>
-      <a:accent6><a:srgbClr val="70AD47"/></a:accent6>
-      <a:hlink><a:srgbClr val="0563C1"/></a:hlink>
-      <a:folHlink><a:srgbClr val="954F72"/></a:folHlink>
-    </a:clrScheme>
+%s    </a:clrScheme>
     <a:fontScheme name="Office">
       <a:majorFont>
-        <a:latin typeface="Calibri Light"/>
-        <a:ea typeface=""/>
-        <a:cs typeface=""/>
-      </a:majorFont>
+%s      </a:majorFont>
       <a:minorFont>
-        <a:latin typeface="Calibri"/>
-        <a:ea typeface=""/>
-        <a:cs typeface=""/>
-      </a:minorFont>
+%s      </a:minorFont>
     </a:fontScheme>
     <a:fmtScheme name="Office">
       <a:fillStyleLst>
@@ -263,6 +334,7 @@ func (w *PPTXWriter) writeTheme(zw *zip.Writer) error {
   </a:themeElements>
   <a:objectDefaults/>
   <a:extraClrSchemeLst/>
-</a:theme>`, nsDrawingML)
-	return writeRawXMLToZip(zw, "ppt/theme/theme1.xml", content)
+</a:theme>`, nsDrawingML, w.presentation.themeColorSchemeXML(),
+		themeFontXML(w.presentation.majorFont, "Calibri Light"),
+		themeFontXML(w.presentation.minorFont, "Calibri"))
 }
@@ -2,120 +2,69 @@ package gopresentation
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
-// buildHyperlinkRelMap pre-computes the relationship IDs for all hyperlinks in a slide.
-// This ensures the XML shape content and the .rels file use the same IDs.
-func (w *PPTXWriter) buildHyperlinkRelMap(slide *Slide) map[*TextRun]string {
-	m := make(map[*TextRun]string)
-	relIdx := 2 // rId1 is slideLayout
-	for _, shape := range slide.shapes {
-		relIdx += countShapeRels(shape)
-		for _, para := range shapeParagraphs(shape) {
-			for _, elem := range para.elements {
-				if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil && !tr.hyperlink.IsInternal {
-					m[tr] = fmt.Sprintf("rId%d", relIdx)
-					relIdx++
-				}
-			}
-		}
-	}
-	return m
+// hasHyperlinkRel reports whether a run hyperlink is written with a slide
+// relationship: external links, and internal links to a slide of the
+// presentation.
+func (w *PPTXWriter) hasHyperlinkRel(h *Hyperlink) bool {
+	return h != nil && (!h.IsInternal || h.SlideNumber > 0 && h.SlideNumber <= len(w.presentation.slides))
 }
 
-// countShapeRels returns the number of non-hyperlink relationship IDs consumed by a shape
-// (images and charts each consume one relId).
-func countShapeRels(shape Shape) int {
-	switch s := shape.(type) {
-	case *DrawingShape:
-		if s.data != nil || s.path != "" {
-			return 1
-		}
-	case *ChartShape:
-		return 1
-	}
-	return 0
-}
-
-// shapeParagraphs returns the paragraphs for shapes that can contain hyperlinks.
-func shapeParagraphs(shape Shape) []*Paragraph {
-	switch s := shape.(type) {
-	case *RichTextShape:
-		return s.paragraphs
-	case *PlaceholderShape:
-		return s.paragraphs
-	}
-	return nil
-}
-
-// countRelIdxBefore computes the relIdx for a target shape within a slide,
-// counting all rels (images, charts, hyperlinks) for shapes before it.
-func countRelIdxBefore(shapes []Shape, target Shape) int {
-	relIdx := 2 // rId1 is slideLayout
-	for _, shape := range shapes {
-		if shape == target {
-			break
-		}
-		relIdx += countShapeRels(shape)
-		for _, para := range shapeParagraphs(shape) {
-			for _, elem := range para.elements {
-				if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil && !tr.hyperlink.IsInternal {
-					relIdx++
-				}
-			}
+// addHyperlinkRel registers the relationship of the hyperlink of tr, if it
+// has one; slideDir is the path from the part to the slides. An internal
+// link to a slide the presentation does not have is written without its
+// jump and logged.
+func (w *PPTXWriter) addHyperlinkRel(rels *relRegistry, tr *TextRun, slideDir string) {
+	h := tr.hyperlink
+	switch {
+	case h == nil:
+	case !w.hasHyperlinkRel(h):
+		if h.IsInternal {
+			w.logger().Warn("dropped hyperlink to missing slide", "slide", h.SlideNumber, "text", tr.text)
 		}
+	case h.IsInternal:
+		rels.add(tr, relTypeSlide, fmt.Sprintf("%sslide%d.xml", slideDir, h.SlideNumber))
+	default:
+		rels.addExternal(tr, relTypeHyperlink, h.URL)
 	}
-	return relIdx
 }
 
-func (w *PPTXWriter) writeSlide(zw *zip.Writer, slide *Slide, slideNum int, hlinkRelMap map[*TextRun]string) error {
-
-	var shapesXML strings.Builder
-	shapeID := 2 // 1 is reserved for the group shape
-
-	for _, shape := range slide.shapes {
-		switch s := shape.(type) {
-		case *PlaceholderShape:
-			shapesXML.WriteString(w.writePlaceholderShapeXML(s, &shapeID))
-		case *RichTextShape:
-			shapesXML.WriteString(w.writeRichTextShapeXML(s, &shapeID))
-		case *DrawingShape:
-			shapesXML.WriteString(w.writeDrawingShapeXML(s, &shapeID, slideNum))
-		case *TableShape:
-			shapesXML.WriteString(w.writeTableShapeXML(s, &shapeID))
-		case *AutoShape:
-			shapesXML.WriteString(w.writeAutoShapeXML(s, &shapeID))
-		case *LineShape:
-			shapesXML.WriteString(w.writeLineShapeXML(s, &shapeID))
-		case *ChartShape:
-			shapesXML.WriteString(w.writeChartShapeXML(s, &shapeID, slideNum))
-		case *GroupShape:
-			shapesXML.WriteString(w.writeGroupShapeXML(s, &shapeID, slideNum))
-		}
-	}
+func (w *PPTXWriter) writeSlide(zw *zip.Writer, slide *Slide, slideNum int, rels *relRegistry) error {
+	buf := getXMLBuffer()
+	defer putXMLBuffer(buf)
 
-	// Replace hyperlink placeholders with actual relationship IDs
-	result := shapesXML.String()
-	for tr, relID := range hlinkRelMap {
-		placeholder := fmt.Sprintf("rId_hlink_%p", tr)
-		result = strings.Replace(result, placeholder, relID, 1)
-	}
+	fmt.Fprintf(buf, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sld xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
+  <p:cSld>
+`, nsDrawingML, nsOfficeDocRels, nsPresentationML)
 
 	// Background XML
-	bgXML := ""
-	if slide.background != nil && slide.background.Type != FillNone {
-		bgXML = "    <p:bg>\n      <p:bgPr>\n"
-		bgXML += w.writeFillXML(slide.background)
-		bgXML += "        <a:effectLst/>\n      </p:bgPr>\n    </p:bg>\n"
+	if ref := slide.backgroundRef; ref != nil {
+		clr := colorXML(ref.Color)
+		if ref.SchemeColor != "" {
+			clr = fmt.Sprintf(`<a:schemeClr val="%s"/>`, xmlEscape(ref.SchemeColor))
+		}
+		fmt.Fprintf(buf, "    <p:bg>\n      <p:bgRef idx=\"%d\">%s</p:bgRef>\n    </p:bg>\n", ref.Index, clr)
+	} else if slide.background != nil && slide.background.Type != FillNone &&
+		(slide.background.Type != FillPicture || hasBackgroundPicture(slide)) {
+		// A picture background without image data has nothing to refer
+		// to, so the slide keeps the background of its layout.
+		buf.WriteString("    <p:bg>\n      <p:bgPr>\n")
+		if slide.background.Type == FillPicture {
+			buf.WriteString(w.writePictureFillXML(slide.background, rels.id(relKeyBackground)))
+		} else {
+			buf.WriteString(w.writeFillXML(slide.background))
+		}
+		buf.WriteString("        <a:effectLst/>\n      </p:bgPr>\n    </p:bg>\n")
 	}
 
-	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<p:sld xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
-  <p:cSld>
-%s    <p:spTree>
+	buf.WriteString(`    <p:spTree>
       <p:nvGrpSpPr>
         <p:cNvPr id="1" name=""/>
         <p:cNvGrpSpPr/>
@@ -129,83 +78,98 @@ func (w *PPTXWriter) writeSlide(zw *zip.Writer, slide *Slide, slideNum int, hlin
           <a:chExt cx="0" cy="0"/>
         </a:xfrm>
       </p:grpSpPr>
-%s    </p:spTree>
-  </p:cSld>
+`)
+
+	// Shapes look up their relationship IDs while they are written
+	w.slideRels = rels
+	w.slideIndex = slideNum - 1
+	w.shapePart = fmt.Sprintf("ppt/slides/slide%d.xml", slideNum)
+	defer func() { w.slideRels = nil }()
+
+	w.writeShapeTreeXML(buf, slide.shapes, slideNum)
+
+	buf.WriteString("    </p:spTree>\n")
+	if custData := w.custDataXML(slide); custData != "" {
+		buf.WriteString("    " + custData + "\n")
+	}
+	buf.WriteString(`  </p:cSld>
   <p:clrMapOvr>
     <a:masterClrMapping/>
   </p:clrMapOvr>
-</p:sld>`, nsDrawingML, nsOfficeDocRels, nsPresentationML, bgXML, result)
+`)
+	buf.WriteString(slideTransitionXML(slide.transition))
+	buf.WriteString("  " + slideExtLstXML(slide, w.slideCreationID(slide, slideNum)) + "\n</p:sld>")
 
-	return writeRawXMLToZip(zw, fmt.Sprintf("ppt/slides/slide%d.xml", slideNum), content)
+	return w.writePartBytes(zw, fmt.Sprintf("ppt/slides/slide%d.xml", slideNum), ctSlide, buf.Bytes())
 }
 
-func (w *PPTXWriter) writeSlideRels(zw *zip.Writer, slide *Slide, slideNum int, hlinkRelMap map[*TextRun]string) error {
-	var rels strings.Builder
-	fmt.Fprintf(&rels, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Relationships xmlns="%s">
-  <Relationship Id="rId1" Type="%s" Target="../slideLayouts/slideLayout1.xml"/>`, nsRelationships, relTypeSlideLayout)
+// transitionElements maps transition types to their p:transition child.
+var transitionElements = map[TransitionType]string{
+	TransitionFade:     "fade",
+	TransitionPush:     "push",
+	TransitionWipe:     "wipe",
+	TransitionSplit:    "split",
+	TransitionCover:    "cover",
+	TransitionUncover:  "pull",
+	TransitionDissolve: "dissolve",
+}
 
-	relIdx := 2
-	for _, shape := range slide.shapes {
+// slideTransitionXML returns the p:transition element of t, or "" when t
+// has neither an effect nor a timing.
+func slideTransitionXML(t *Transition) string {
+	if t == nil {
+		return ""
+	}
+	elem := transitionElements[t.Type]
+	if elem == "" && t.AdvanceAfter <= 0 && t.Speed == "" {
+		return ""
+	}
+	attrs := ""
+	if t.Speed != "" {
+		attrs += fmt.Sprintf(` spd="%s"`, xmlEscape(string(t.Speed)))
+	}
+	if t.AdvanceAfter > 0 {
+		attrs += fmt.Sprintf(` advTm="%d"`, t.AdvanceAfter)
+	}
+	if elem == "" {
+		return fmt.Sprintf("  <p:transition%s/>\n", attrs)
+	}
+	return fmt.Sprintf("  <p:transition%s><p:%s/></p:transition>\n", attrs, elem)
+}
+
+// writeShapeTreeXML writes the shapes of a shape tree. Their relationship
+// IDs are looked up in w.slideRels.
+func (w *PPTXWriter) writeShapeTreeXML(buf *bytes.Buffer, shapes []Shape, slideNum int) {
+	shapeID := 2 // 1 is reserved for the group shape
+	for _, shape := range shapes {
 		switch s := shape.(type) {
+		case *PlaceholderShape:
+			buf.WriteString(w.writePlaceholderShapeXML(s, &shapeID))
+		case *RichTextShape:
+			buf.WriteString(w.writeRichTextShapeXML(s, &shapeID))
 		case *DrawingShape:
-			if s.data != nil || s.path != "" {
-				imgIdx := w.getImageIndex(slide, s)
-				ext := w.getImageExtension(s)
-				fmt.Fprintf(&rels, `
-  <Relationship Id="rId%d" Type="%s" Target="../media/image%d.%s"/>`,
-					relIdx, relTypeImage, imgIdx, ext)
-				relIdx++
-			}
+			buf.WriteString(w.writeDrawingShapeXML(s, &shapeID, slideNum))
+		case *TableShape:
+			buf.WriteString(w.writeTableShapeXML(s, &shapeID))
+		case *AutoShape:
+			buf.WriteString(w.writeAutoShapeXML(s, &shapeID))
+		case *LineShape:
+			buf.WriteString(w.writeLineShapeXML(s, &shapeID))
 		case *ChartShape:
-			chartIdx := w.getChartIndex(s)
-			fmt.Fprintf(&rels, `
-  <Relationship Id="rId%d" Type="%s" Target="../charts/chart%d.xml"/>`,
-				relIdx, relTypeChart, chartIdx)
-			relIdx++
-		}
-		// Handle hyperlinks in shapes with paragraphs
-		var paras []*Paragraph
-		switch s := shape.(type) {
-		case *RichTextShape:
-			paras = s.paragraphs
-		case *PlaceholderShape:
-			paras = s.paragraphs
-		}
-		for _, para := range paras {
-			for _, elem := range para.elements {
-				if tr, ok := elem.(*TextRun); ok && tr.hyperlink != nil {
-					if !tr.hyperlink.IsInternal {
-						rid := hlinkRelMap[tr]
-						fmt.Fprintf(&rels, `
-  <Relationship Id="%s" Type="%s" Target="%s" TargetMode="External"/>`,
-							rid, relTypeHyperlink, xmlEscape(tr.hyperlink.URL))
-						relIdx++
-					}
-				}
-			}
+			buf.WriteString(w.writeChartShapeXML(s, &shapeID, slideNum))
+		case *GroupShape:
+			buf.WriteString(w.writeGroupShapeXML(s, &shapeID, slideNum))
 		}
 	}
+}
 
-	// Comments relationship
-	if len(slide.comments) > 0 {
-		fmt.Fprintf(&rels, `
-  <Relationship Id="rId%d" Type="%s" Target="../comments/comment%d.xml"/>`,
-			relIdx, relTypeComment, slideNum)
-		relIdx++
-	}
-
-	// Notes slide relationship
-	if slide.notes != "" {
-		fmt.Fprintf(&rels, `
-  <Relationship Id="rId%d" Type="%s" Target="../notesSlides/notesSlide%d.xml"/>`,
-			relIdx, relTypeNotesSlide, slideNum)
-		relIdx++
-	}
+// hasBackgroundPicture reports whether the slide background is written as a picture fill.
+func hasBackgroundPicture(slide *Slide) bool {
+	return slide.backgroundRef == nil && slide.background != nil && slide.background.Type == FillPicture && len(slide.background.ImageData) > 0
+}
 
-	rels.WriteString(`
-</Relationships>`)
-	return writeRawXMLToZip(zw, fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", slideNum), rels.String())
+func (w *PPTXWriter) getPictureFillExtension(f *Fill) string {
+	return w.getImageExtension(&DrawingShape{mimeType: f.MimeType})
 }
 
 func (w *PPTXWriter) getImageIndex(slide *Slide, target *DrawingShape) int {
@@ -221,6 +185,13 @@ func (w *PPTXWriter) getImageIndex(slide *Slide, target *DrawingShape) int {
 	return idx
 }
 
+// hasImagePart reports whether ds is written with an image part: it has
+// image data or a file, or it was read without its media, in which case
+// the part is written empty.
+func hasImagePart(ds *DrawingShape) bool {
+	return ds.data != nil || ds.path != "" || ds.missingMedia != ""
+}
+
 // collectDrawingShapes returns all DrawingShapes from a shape list,
 // including those nested inside GroupShapes (recursively).
 func collectDrawingShapes(shapes []Shape) []*DrawingShape {
@@ -228,7 +199,7 @@ func collectDrawingShapes(shapes []Shape) []*DrawingShape {
 	for _, shape := range shapes {
 		switch s := shape.(type) {
 		case *DrawingShape:
-			if s.data != nil || s.path != "" {
+			if hasImagePart(s) {
 				result = append(result, s)
 			}
 		case *GroupShape:
@@ -238,6 +209,47 @@ func collectDrawingShapes(shapes []Shape) []*DrawingShape {
 	return result
 }
 
+// hasCellPicture reports whether a table cell is written with a picture fill.
+func hasCellPicture(cell *TableCell) bool {
+	return cell.fill != nil && cell.fill.Type == FillPicture && len(cell.fill.ImageData) > 0
+}
+
+// collectPictureCells returns the table cells with picture fills from a
+// shape list, including tables nested inside GroupShapes, in document order.
+func collectPictureCells(shapes []Shape) []*TableCell {
+	var result []*TableCell
+	for _, shape := range shapes {
+		switch s := shape.(type) {
+		case *TableShape:
+			for _, row := range s.rows {
+				for _, cell := range row {
+					if hasCellPicture(cell) {
+						result = append(result, cell)
+					}
+				}
+			}
+		case *GroupShape:
+			result = append(result, collectPictureCells(s.shapes)...)
+		}
+	}
+	return result
+}
+
+// getCellPictureIndex returns the 1-based number of the media part of a
+// cell picture across the slides.
+func (w *PPTXWriter) getCellPictureIndex(target *TableCell) int {
+	idx := 1
+	for _, sl := range w.presentation.slides {
+		for _, cell := range collectPictureCells(sl.shapes) {
+			if cell == target {
+				return idx
+			}
+			idx++
+		}
+	}
+	return idx
+}
+
 // --- Rich Text Shape XML ---
 
 // xfrmAttrs builds the attribute string for <a:xfrm> including rotation and flip.
@@ -255,6 +267,37 @@ func xfrmAttrs(b *BaseShape) string {
 	return sb.String()
 }
 
+// scene3DXML returns the <a:scene3d> element of a shape's spPr, with a
+// trailing newline, or "" when the shape has no 3D scene.
+func scene3DXML(s *Scene3D) string {
+	if s == nil {
+		return ""
+	}
+	camera := s.Camera
+	if camera == "" {
+		camera = CameraOrthographicFront
+	}
+	rig, dir := s.LightRig, s.LightDir
+	if rig == "" {
+		rig = "threePt"
+	}
+	if dir == "" {
+		dir = "t"
+	}
+	cameraXML := fmt.Sprintf(`<a:camera prst="%s"/>`, xmlEscape(string(camera)))
+	if s.Lat != 0 || s.Lon != 0 || s.Rev != 0 {
+		angle := func(deg int) int { return ((deg%360 + 360) % 360) * 60000 }
+		cameraXML = fmt.Sprintf(`<a:camera prst="%s">
+              <a:rot lat="%d" lon="%d" rev="%d"/>
+            </a:camera>`, xmlEscape(string(camera)), angle(s.Lat), angle(s.Lon), angle(s.Rev))
+	}
+	return fmt.Sprintf(`          <a:scene3d>
+            %s
+            <a:lightRig rig="%s" dir="%s"/>
+          </a:scene3d>
+`, cameraXML, xmlEscape(rig), xmlEscape(dir))
+}
+
 func (w *PPTXWriter) writeRichTextShapeXML(s *RichTextShape, shapeID *int) string {
 	id := *shapeID
 	*shapeID++
@@ -270,8 +313,8 @@ func (w *PPTXWriter) writeRichTextShapeXML(s *RichTextShape, shapeID *int) strin
 	borderXML := w.writeBorderXML(s.GetBorder())
 
 	var paragraphsXML strings.Builder
-	for _, para := range s.paragraphs {
-		paragraphsXML.WriteString(w.writeParagraphXML(para))
+	for _, para := range resolveListStyle(&s.listStyle, s.paragraphs, true) {
+		w.appendParagraphXML(&paragraphsXML, para)
 	}
 
 	descrAttr := ""
@@ -279,34 +322,79 @@ func (w *PPTXWriter) writeRichTextShapeXML(s *RichTextShape, shapeID *int) strin
 		descrAttr = fmt.Sprintf(` descr="%s"`, xmlEscape(s.description))
 	}
 
+	// Freeforms are plain shapes rather than text boxes
+	txBoxAttr := ` txBox="1"`
+	geomXML := `          <a:prstGeom prst="rect">
+            <a:avLst/>
+          </a:prstGeom>
+`
+	if s.customPath != nil {
+		txBoxAttr = ""
+		geomXML = custGeomXML(s.customPath)
+	}
+
 	return fmt.Sprintf(`      <p:sp>
         <p:nvSpPr>
-          <p:cNvPr id="%d" name="%s"%s/>
-          <p:cNvSpPr txBox="1"/>
-          <p:nvPr/>
+          <p:cNvPr id="%d" name="%s"%s%s
+          %s
+          %s
         </p:nvSpPr>
         <p:spPr>
           <a:xfrm%s>
             <a:off x="%d" y="%d"/>
             <a:ext cx="%d" cy="%d"/>
           </a:xfrm>
-          <a:prstGeom prst="rect">
-            <a:avLst/>
-          </a:prstGeom>
-%s%s        </p:spPr>
+%s%s%s%s        </p:spPr>
         <p:txBody>
           <a:bodyPr wrap="%s" numCol="%d"%s>%s</a:bodyPr>
-          <a:lstStyle/>
-%s        </p:txBody>
+%s%s        </p:txBody>
       </p:sp>
-`, id, xmlEscape(name), descrAttr, xfAttrs,
+`, id, xmlEscape(name), descrAttr, w.cNvPrEndXML(&s.BaseShape, id),
+		nvLocksXML("p:cNvSpPr", txBoxAttr, "a:spLocks", "", s.locks.xmlAttrs(true)),
+		w.nvPrXML(&s.BaseShape),
+		xfAttrs,
 		s.offsetX, s.offsetY, s.width, s.height,
-		fillXML, borderXML,
+		geomXML, fillXML, borderXML, scene3DXML(s.scene3d),
 		boolToWrap(s.wordWrap), s.columns, textAnchorAttr(s.textAnchor),
 		normAutofitXML(s.fontScale),
+		w.writeListStyleXML(&s.listStyle),
 		paragraphsXML.String())
 }
 
+// custGeomXML returns the <a:custGeom> element of a freeform path.
+func custGeomXML(p *CustomGeomPath) string {
+	var sb strings.Builder
+	sb.WriteString(`          <a:custGeom>
+            <a:avLst/>
+            <a:gdLst/>
+            <a:ahLst/>
+            <a:cxnLst/>
+            <a:rect l="0" t="0" r="r" b="b"/>
+            <a:pathLst>
+`)
+	fmt.Fprintf(&sb, "              <a:path w=\"%d\" h=\"%d\">\n", p.Width, p.Height)
+	for _, cmd := range p.Commands {
+		switch cmd.Type {
+		case "close":
+			sb.WriteString("                <a:close/>\n")
+		case "arcTo":
+			fmt.Fprintf(&sb, "                <a:arcTo wR=\"%d\" hR=\"%d\" stAng=\"%d\" swAng=\"%d\"/>\n",
+				cmd.WR, cmd.HR, cmd.StAng, cmd.SwAng)
+		case "moveTo", "lnTo", "cubicBezTo", "quadBezTo":
+			fmt.Fprintf(&sb, "                <a:%s>", cmd.Type)
+			for _, pt := range cmd.Pts {
+				fmt.Fprintf(&sb, `<a:pt x="%d" y="%d"/>`, pt.X, pt.Y)
+			}
+			fmt.Fprintf(&sb, "</a:%s>\n", cmd.Type)
+		}
+	}
+	sb.WriteString(`              </a:path>
+            </a:pathLst>
+          </a:custGeom>
+`)
+	return sb.String()
+}
+
 func boolToWrap(wrap bool) string {
 	if wrap {
 		return "square"
@@ -319,7 +407,16 @@ func textAnchorAttr(anchor TextAnchorType) string {
 	if anchor == "" || anchor == TextAnchorNone {
 		return ""
 	}
-	return fmt.Sprintf(` anchor="%s"`, string(anchor))
+	return xmlAttr("anchor", string(anchor))
+}
+
+// insetsAttrs returns the lIns, tIns, rIns and bIns attributes of
+// <a:bodyPr> when the insets are set.
+func insetsAttrs(set bool, left, top, right, bottom int64) string {
+	if !set {
+		return ""
+	}
+	return fmt.Sprintf(` lIns="%d" tIns="%d" rIns="%d" bIns="%d"`, left, top, right, bottom)
 }
 
 // normAutofitXML returns the <a:normAutofit> child element for <a:bodyPr> if fontScale is set.
@@ -331,109 +428,183 @@ func normAutofitXML(fontScale int) string {
 }
 
 func (w *PPTXWriter) writeParagraphXML(para *Paragraph) string {
+	var sb strings.Builder
+	w.appendParagraphXML(&sb, para)
+	return sb.String()
+}
+
+// appendParagraphXML writes the a:p element for para to sb.
+func (w *PPTXWriter) appendParagraphXML(sb *strings.Builder, para *Paragraph) {
+	w.appendParagraphRunsXML(sb, para, w.appendTextRunXML)
+}
+
+// appendParagraphRunsXML writes the a:p element for para to sb, writing
+// its text runs with appendRun.
+func (w *PPTXWriter) appendParagraphRunsXML(sb *strings.Builder, para *Paragraph, appendRun func(*strings.Builder, *TextRun)) {
+	w.resetNormalization()
+	sb.WriteString("          <a:p>\n            <a:pPr")
 	align := para.alignment
-	algn := ""
 	if align.Horizontal != "" {
-		algn = fmt.Sprintf(` algn="%s"`, align.Horizontal)
+		sb.WriteString(` algn="`)
+		writeXMLEscaped(sb, string(align.Horizontal))
+		sb.WriteByte('"')
 	}
 
 	// Indentation level
 	if align.Level > 0 {
-		algn += fmt.Sprintf(` lvl="%d"`, align.Level)
+		sb.WriteString(` lvl="`)
+		sb.WriteString(strconv.Itoa(align.Level))
+		sb.WriteByte('"')
 	}
+	sb.WriteByte('>')
 
-	var elementsXML strings.Builder
-	for _, elem := range para.elements {
-		switch e := elem.(type) {
-		case *TextRun:
-			elementsXML.WriteString(w.writeTextRunXML(e))
-		case *BreakElement:
-			elementsXML.WriteString("          <a:br/>\n")
-		}
-	}
-
-	spacing := ""
 	if para.lineSpacing < 0 {
 		// spcPct: stored as negative percentage * 1000
-		spacing = fmt.Sprintf(`
-            <a:lnSpc><a:spcPct val="%d"/></a:lnSpc>`, -para.lineSpacing)
+		sb.WriteString("\n            <a:lnSpc><a:spcPct val=\"")
+		sb.WriteString(strconv.Itoa(-para.lineSpacing))
+		sb.WriteString(`"/></a:lnSpc>`)
 	} else if para.lineSpacing > 0 {
-		spacing = fmt.Sprintf(`
-            <a:lnSpc><a:spcPts val="%d"/></a:lnSpc>`, para.lineSpacing)
+		sb.WriteString("\n            <a:lnSpc><a:spcPts val=\"")
+		sb.WriteString(strconv.Itoa(para.lineSpacing))
+		sb.WriteString(`"/></a:lnSpc>`)
 	}
 	if para.spaceBefore > 0 {
-		spacing += fmt.Sprintf(`
-            <a:spcBef><a:spcPts val="%d"/></a:spcBef>`, para.spaceBefore)
+		sb.WriteString("\n            <a:spcBef><a:spcPts val=\"")
+		sb.WriteString(strconv.Itoa(para.spaceBefore))
+		sb.WriteString(`"/></a:spcBef>`)
 	}
 	if para.spaceAfter > 0 {
-		spacing += fmt.Sprintf(`
-            <a:spcAft><a:spcPts val="%d"/></a:spcAft>`, para.spaceAfter)
+		sb.WriteString("\n            <a:spcAft><a:spcPts val=\"")
+		sb.WriteString(strconv.Itoa(para.spaceAfter))
+		sb.WriteString(`"/></a:spcAft>`)
 	}
 
 	// Bullet XML
-	bulletXML := ""
 	if para.bullet != nil {
-		bulletXML = w.writeBulletXML(para.bullet)
+		sb.WriteString(w.writeBulletXML(para.bullet))
 	}
+	sb.WriteString("\n            </a:pPr>\n")
 
-	return fmt.Sprintf(`          <a:p>
-            <a:pPr%s>%s%s
-            </a:pPr>
-%s          </a:p>
-`, algn, spacing, bulletXML, elementsXML.String())
+	for _, elem := range para.elements {
+		switch e := elem.(type) {
+		case *TextRun:
+			appendRun(sb, e)
+		case *SlideNumberField:
+			w.appendSlideNumberXML(sb, e)
+		case *BreakElement:
+			sb.WriteString("          <a:br/>\n")
+		}
+	}
+	sb.WriteString("          </a:p>\n")
 }
 
 func (w *PPTXWriter) writeTextRunXML(tr *TextRun) string {
-	font := tr.font
-	attrs := fmt.Sprintf(` lang="en-US" sz="%d" dirty="0"`, font.Size*100)
+	var sb strings.Builder
+	w.appendTextRunXML(&sb, tr)
+	return sb.String()
+}
+
+// appendTextRunXML writes the a:r element for tr to sb. Hyperlinks are
+// written when the run has a relationship ID in w.slideRels.
+func (w *PPTXWriter) appendTextRunXML(sb *strings.Builder, tr *TextRun) {
+	sb.WriteString("            <a:r>\n")
+	w.appendRunPropsXML(sb, tr)
+	sb.WriteString("              <a:t>")
+	writeXMLEscaped(sb, w.normalizeText(tr.text))
+	sb.WriteString("</a:t>\n            </a:r>\n")
+}
+
+// appendSlideNumberXML writes a slide number field, or its text as a plain
+// run when the presentation numbers slides in a format PowerPoint cannot
+// show in a field.
+func (w *PPTXWriter) appendSlideNumberXML(sb *strings.Builder, f *SlideNumberField) {
+	run := &TextRun{text: w.presentation.slideNumberText(w.slideIndex), font: f.font}
+	if run.font == nil {
+		run.font = NewFont()
+	}
+	if w.presentation.GetSlideNumberFormat() != SlideNumberArabic {
+		w.appendTextRunXML(sb, run)
+		return
+	}
+	sb.WriteString("            <a:fld id=\"" + slideNumberFieldID + "\" type=\"slidenum\">\n")
+	w.appendRunPropsXML(sb, run)
+	sb.WriteString("              <a:t>")
+	sb.WriteString(run.text)
+	sb.WriteString("</a:t>\n            </a:fld>\n")
+}
+
+// appendRunPropsXML writes the a:rPr element of tr to sb.
+func (w *PPTXWriter) appendRunPropsXML(sb *strings.Builder, tr *TextRun) {
+	font := runFont(tr.font, w.presentation.defaultFont)
+	sb.WriteString("              <a:rPr lang=\"en-US\" sz=\"")
+	sb.WriteString(strconv.Itoa(font.Size * 100))
+	sb.WriteString(`" dirty="0"`)
 
 	if font.Bold {
-		attrs += ` b="1"`
+		sb.WriteString(` b="1"`)
 	}
 	if font.Italic {
-		attrs += ` i="1"`
+		sb.WriteString(` i="1"`)
 	}
 	if font.Underline != UnderlineNone && font.Underline != "" {
-		attrs += fmt.Sprintf(` u="%s"`, font.Underline)
+		sb.WriteString(` u="`)
+		writeXMLEscaped(sb, string(font.Underline))
+		sb.WriteByte('"')
 	}
 	if font.Strikethrough {
-		attrs += ` strike="sngStrike"`
+		sb.WriteString(` strike="sngStrike"`)
 	}
+	if font.Superscript {
+		sb.WriteString(` baseline="30000"`)
+	} else if font.Subscript {
+		sb.WriteString(` baseline="-25000"`)
+	}
+	sb.WriteByte('>')
 
-	solidFill := ""
 	if font.Color.ARGB != "" {
-		solidFill = fmt.Sprintf(`
-              <a:solidFill><a:srgbClr val="%s"/></a:solidFill>`, colorRGB(font.Color))
+		sb.WriteString("\n              <a:solidFill>")
+		sb.WriteString(colorXML(font.Color))
+		sb.WriteString("</a:solidFill>")
 	}
-
-	latin := ""
 	if font.Name != "" {
-		latin = fmt.Sprintf(`
-              <a:latin typeface="%s"/>`, xmlEscape(font.Name))
+		sb.WriteString("\n              <a:latin typeface=\"")
+		writeXMLEscaped(sb, font.Name)
+		sb.WriteString(`"/>`)
 	}
-
-	ea := ""
 	if font.NameEA != "" {
-		ea = fmt.Sprintf(`
-              <a:ea typeface="%s"/>`, xmlEscape(font.NameEA))
+		sb.WriteString("\n              <a:ea typeface=\"")
+		writeXMLEscaped(sb, font.NameEA)
+		sb.WriteString(`"/>`)
 	}
 
-	hlinkStart := ""
-	hlinkEnd := ""
-	if tr.hyperlink != nil && !tr.hyperlink.IsInternal {
-		hlinkStart = fmt.Sprintf(`
-              <a:hlinkClick r:id="rId_hlink_%p"/>`, tr)
-	}
+	w.appendHyperlinkClickXML(sb, tr)
 
-	return fmt.Sprintf(`            <a:r>
-              <a:rPr%s>%s%s%s%s%s
-              </a:rPr>
-              <a:t>%s</a:t>
-            </a:r>
-`, attrs, solidFill, latin, ea, hlinkStart, hlinkEnd, xmlEscape(tr.text))
+	sb.WriteString("\n              </a:rPr>\n")
 }
 
-// --- Drawing Shape XML ---
+// appendHyperlinkClickXML writes the a:hlinkClick element of tr to sb when
+// the run has a relationship ID in w.slideRels.
+func (w *PPTXWriter) appendHyperlinkClickXML(sb *strings.Builder, tr *TextRun) {
+	if rid := w.slideRels.id(tr); rid != "" && w.hasHyperlinkRel(tr.hyperlink) {
+		sb.WriteString("\n              <a:hlinkClick r:id=\"")
+		sb.WriteString(rid)
+		sb.WriteByte('"')
+		if tr.hyperlink.IsInternal {
+			sb.WriteString(` action="ppaction://hlinksldjump"`)
+		}
+		if tr.hyperlink.Tooltip != "" {
+			sb.WriteString(` tooltip="`)
+			writeXMLEscaped(sb, tr.hyperlink.Tooltip)
+			sb.WriteByte('"')
+		}
+		if tr.hyperlink.TargetFrame != "" {
+			sb.WriteString(` tgtFrame="`)
+			writeXMLEscaped(sb, tr.hyperlink.TargetFrame)
+			sb.WriteByte('"')
+		}
+		sb.WriteString("/>")
+	}
+}
 
 func (w *PPTXWriter) writeDrawingShapeXML(s *DrawingShape, shapeID *int, slideNum int) string {
 	id := *shapeID
@@ -444,10 +615,6 @@ func (w *PPTXWriter) writeDrawingShapeXML(s *DrawingShape, shapeID *int, slideNu
 		name = fmt.Sprintf("Picture %d", id)
 	}
 
-	// Find the relationship ID for this image within the current slide.
-	// Must match the ordering in writeSlideRels exactly.
-	currentSlide := w.presentation.slides[slideNum-1]
-	relIdx := countRelIdxBefore(currentSlide.shapes, s)
 
 	shadowXML := ""
 	if s.shadow != nil && s.shadow.Visible {
@@ -468,14 +635,12 @@ func (w *PPTXWriter) writeDrawingShapeXML(s *DrawingShape, shapeID *int, slideNu
 
 	return fmt.Sprintf(`      <p:pic>
         <p:nvPicPr>
-          <p:cNvPr id="%d" name="%s" descr="%s"/>
-          <p:cNvPicPr>
-            <a:picLocks noChangeAspect="1"/>
-          </p:cNvPicPr>
-          <p:nvPr/>
+          <p:cNvPr id="%d" name="%s" descr="%s"%s
+          %s
+          %s
         </p:nvPicPr>
         <p:blipFill>
-          <a:blip r:embed="rId%d"/>
+          %s%s
           <a:stretch>
             <a:fillRect/>
           </a:stretch>
@@ -488,13 +653,55 @@ func (w *PPTXWriter) writeDrawingShapeXML(s *DrawingShape, shapeID *int, slideNu
           <a:prstGeom prst="rect">
             <a:avLst/>
           </a:prstGeom>%s
-        </p:spPr>
+%s        </p:spPr>
       </p:pic>
-`, id, xmlEscape(name), xmlEscape(s.description),
-		relIdx,
+`, id, xmlEscape(name), xmlEscape(s.description), w.cNvPrEndXML(&s.BaseShape, id),
+		nvLocksXML("p:cNvPicPr", "", "a:picLocks", pictureAspectLock(s.locks), s.locks.xmlAttrs(false)),
+		w.nvPrXML(&s.BaseShape),
+		blipXML(w.slideRels.id(s), s.alpha), srcRectXML(s),
 		xfrmAttrs(&s.BaseShape),
 		s.offsetX, s.offsetY, s.width, s.height,
-		shadowXML)
+		shadowXML, scene3DXML(s.scene3d))
+}
+
+// blipXML returns the <a:blip> of a picture with relationship ID rid and
+// its alphaModFix amount when it is partly transparent.
+func blipXML(rid string, alpha int) string {
+	if alpha <= 0 || alpha >= 100000 {
+		return fmt.Sprintf(`<a:blip r:embed="%s"/>`, rid)
+	}
+	return fmt.Sprintf(`<a:blip r:embed="%s"><a:alphaModFix amt="%d"/></a:blip>`, rid, alpha)
+}
+
+// srcRectXML returns the <a:srcRect> crop of a picture, preceded by a
+// newline, or "" when it is not cropped.
+func srcRectXML(d *DrawingShape) string {
+	if d.cropLeft == 0 && d.cropTop == 0 && d.cropRight == 0 && d.cropBottom == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+          <a:srcRect l="%d" t="%d" r="%d" b="%d"/>`, d.cropLeft, d.cropTop, d.cropRight, d.cropBottom)
+}
+
+// pictureAspectLock returns the noChangeAspect attribute pictures are
+// written with, unless locks already set it.
+func pictureAspectLock(locks *ShapeLocks) string {
+	if locks != nil && locks.NoChangeAspect {
+		return ""
+	}
+	return ` noChangeAspect="1"`
+}
+
+// nvLocksXML returns the non-visual drawing properties element elem with
+// attributes attrs, holding a lock element lockElem when the fixed lock
+// attributes or the shape's own lock attributes are non-empty.
+func nvLocksXML(elem, attrs, lockElem, fixed, locks string) string {
+	if fixed+locks == "" {
+		return "<" + elem + attrs + "/>"
+	}
+	return fmt.Sprintf(`<%s%s>
+            <%s%s%s/>
+          </%s>`, elem, attrs, lockElem, fixed, locks, elem)
 }
 
 // --- Auto Shape XML ---
@@ -512,18 +719,36 @@ func (w *PPTXWriter) writeAutoShapeXML(s *AutoShape, shapeID *int) string {
 	borderXML := w.writeBorderXML(s.GetBorder())
 
 	textXML := ""
-	if s.text != "" {
+	if len(s.paragraphs) > 0 {
+		var paragraphsXML strings.Builder
+		for _, para := range s.paragraphs {
+			w.appendParagraphXML(&paragraphsXML, para)
+		}
+		vertAttr := ""
+		if s.textDirection != "" {
+			vertAttr = fmt.Sprintf(` vert="%s"`, xmlEscape(s.textDirection))
+		}
 		textXML = fmt.Sprintf(`
         <p:txBody>
-          <a:bodyPr/>
+          <a:bodyPr wrap="%s" rtlCol="0"%s%s%s>%s</a:bodyPr>
+          <a:lstStyle/>
+%s        </p:txBody>`, boolToWrap(s.wordWrap), vertAttr, textAnchorAttr(s.textAnchor),
+			insetsAttrs(s.insetsSet, s.insetLeft, s.insetTop, s.insetRight, s.insetBottom),
+			normAutofitXML(s.fontScale), paragraphsXML.String())
+	} else if s.text != "" {
+		w.resetNormalization()
+		textXML = fmt.Sprintf(`
+        <p:txBody>
+          <a:bodyPr anchor="ctr"/>
           <a:lstStyle/>
           <a:p>
+            <a:pPr algn="ctr"/>
             <a:r>
               <a:rPr lang="en-US" dirty="0"/>
               <a:t>%s</a:t>
             </a:r>
           </a:p>
-        </p:txBody>`, xmlEscape(s.text))
+        </p:txBody>`, xmlEscape(w.normalizeText(s.text)))
 	}
 
 	descrAttr := ""
@@ -533,9 +758,9 @@ func (w *PPTXWriter) writeAutoShapeXML(s *AutoShape, shapeID *int) string {
 
 	return fmt.Sprintf(`      <p:sp>
         <p:nvSpPr>
-          <p:cNvPr id="%d" name="%s"%s/>
-          <p:cNvSpPr/>
-          <p:nvPr/>
+          <p:cNvPr id="%d" name="%s"%s%s
+          %s
+          %s
         </p:nvSpPr>
         <p:spPr>
           <a:xfrm%s>
@@ -545,13 +770,15 @@ func (w *PPTXWriter) writeAutoShapeXML(s *AutoShape, shapeID *int) string {
           <a:prstGeom prst="%s">
             <a:avLst/>
           </a:prstGeom>
-%s%s        </p:spPr>%s
+%s%s%s        </p:spPr>%s
       </p:sp>
-`, id, xmlEscape(name), descrAttr,
+`, id, xmlEscape(name), descrAttr, w.cNvPrEndXML(&s.BaseShape, id),
+		nvLocksXML("p:cNvSpPr", "", "a:spLocks", "", s.locks.xmlAttrs(true)),
+		w.nvPrXML(&s.BaseShape),
 		xfrmAttrs(&s.BaseShape),
 		s.offsetX, s.offsetY, s.width, s.height,
-		s.shapeType,
-		fillXML, borderXML, textXML)
+		xmlEscape(string(s.shapeType)),
+		fillXML, borderXML, scene3DXML(s.scene3d), textXML)
 }
 
 // --- Line Shape XML ---
@@ -569,11 +796,11 @@ func (w *PPTXWriter) writeLineShapeXML(s *LineShape, shapeID *int) string {
 	var headEndXML, tailEndXML string
 	if s.headEnd != nil && s.headEnd.Type != ArrowNone && s.headEnd.Type != "" {
 		headEndXML = fmt.Sprintf(`
-            <a:headEnd type="%s" w="%s" len="%s"/>`, s.headEnd.Type, s.headEnd.Width, s.headEnd.Length)
+            <a:headEnd%s%s%s/>`, xmlAttr("type", string(s.headEnd.Type)), xmlAttr("w", string(s.headEnd.Width)), xmlAttr("len", string(s.headEnd.Length)))
 	}
 	if s.tailEnd != nil && s.tailEnd.Type != ArrowNone && s.tailEnd.Type != "" {
 		tailEndXML = fmt.Sprintf(`
-            <a:tailEnd type="%s" w="%s" len="%s"/>`, s.tailEnd.Type, s.tailEnd.Width, s.tailEnd.Length)
+            <a:tailEnd%s%s%s/>`, xmlAttr("type", string(s.tailEnd.Type)), xmlAttr("w", string(s.tailEnd.Width)), xmlAttr("len", string(s.tailEnd.Length)))
 	}
 
 	prstGeom := "line"
@@ -590,11 +817,22 @@ func (w *PPTXWriter) writeLineShapeXML(s *LineShape, shapeID *int) string {
 		dashXML = "\n            <a:prstDash val=\"dot\"/>"
 	}
 
+	fillXML := fmt.Sprintf(`<a:solidFill>
+              %s
+            </a:solidFill>`, colorXML(s.lineColor))
+	if s.lineStyle == BorderNone {
+		fillXML = "<a:noFill/>"
+	}
+	var capAttr string
+	if s.lineCap != "" {
+		capAttr = xmlAttr("cap", string(s.lineCap))
+	}
+
 	return fmt.Sprintf(`      <p:cxnSp>
         <p:nvCxnSpPr>
-          <p:cNvPr id="%d" name="%s"/>
-          <p:cNvCxnSpPr/>
-          <p:nvPr/>
+          <p:cNvPr id="%d" name="%s"%s
+          %s
+          %s
         </p:nvCxnSpPr>
         <p:spPr>
           <a:xfrm%s>
@@ -604,20 +842,21 @@ func (w *PPTXWriter) writeLineShapeXML(s *LineShape, shapeID *int) string {
           <a:prstGeom prst="%s">
             <a:avLst/>
           </a:prstGeom>
-          <a:ln w="%d">
-            <a:solidFill>
-              <a:srgbClr val="%s"/>
-            </a:solidFill>%s%s%s
+          <a:ln w="%d"%s>
+            %s%s%s%s
           </a:ln>
-        </p:spPr>
+%s        </p:spPr>
       </p:cxnSp>
-`, id, xmlEscape(name),
+`, id, xmlEscape(name), w.cNvPrEndXML(&s.BaseShape, id),
+		nvLocksXML("p:cNvCxnSpPr", "", "a:cxnSpLocks", "", s.locks.xmlAttrs(false)),
+		w.nvPrXML(&s.BaseShape),
 		xfrmAttrs(&s.BaseShape),
 		s.offsetX, s.offsetY, s.width, s.height,
-		prstGeom,
-		int64(s.GetLineWidthEMU()),
-		colorRGB(s.lineColor),
-		dashXML, headEndXML, tailEndXML)
+		xmlEscape(prstGeom),
+		int64(s.GetLineWidthEMU()), capAttr,
+		fillXML,
+		dashXML, headEndXML, tailEndXML,
+		scene3DXML(s.scene3d))
 }
 
 // --- Table Shape XML ---
@@ -631,87 +870,123 @@ func (w *PPTXWriter) writeTableShapeXML(s *TableShape, shapeID *int) string {
 		name = fmt.Sprintf("Table %d", id)
 	}
 
-	colWidth := int64(0)
-	if s.numCols > 0 {
-		colWidth = s.width / int64(s.numCols)
-	}
-
 	var gridCols strings.Builder
 	for i := 0; i < s.numCols; i++ {
-		gridCols.WriteString(fmt.Sprintf(`            <a:gridCol w="%d"/>
-`, colWidth))
+		gridCols.WriteString(`            <a:gridCol w="`)
+		gridCols.WriteString(strconv.FormatInt(s.colWidth(i), 10))
+		gridCols.WriteString("\"/>\n")
 	}
 
 	var rowsXML strings.Builder
-	rowHeight := int64(0)
-	if s.numRows > 0 {
-		rowHeight = s.height / int64(s.numRows)
-	}
-
 	for i := 0; i < s.numRows; i++ {
-		rowsXML.WriteString(fmt.Sprintf(`            <a:tr h="%d">
-`, rowHeight))
+		rowsXML.WriteString(`            <a:tr h="`)
+		rowsXML.WriteString(strconv.FormatInt(s.rowHeight(i), 10))
+		rowsXML.WriteString("\">\n")
 		for j := 0; j < s.numCols; j++ {
-			cell := s.rows[i][j]
-			cellFill := ""
-			if cell.fill != nil && cell.fill.Type == FillSolid {
-				cellFill = fmt.Sprintf(`
-                  <a:solidFill><a:srgbClr val="%s"/></a:solidFill>`, colorRGB(cell.fill.Color))
+			cell := NewTableCell()
+			if i < len(s.rows) && j < len(s.rows[i]) && s.rows[i][j] != nil {
+				cell = s.rows[i][j]
 			}
-
-			var cellText strings.Builder
+			rowsXML.WriteString(`              <a:tc>
+                <a:txBody>
+                  <a:bodyPr/>
+                  <a:lstStyle/>
+`)
 			for _, para := range cell.paragraphs {
-				cellText.WriteString("                <a:p>\n")
+				rowsXML.WriteString("                <a:p>\n")
+				w.resetNormalization()
 				for _, elem := range para.elements {
 					if tr, ok := elem.(*TextRun); ok {
-						cellText.WriteString(fmt.Sprintf(`                  <a:r>
-                    <a:rPr lang="en-US" sz="%d" dirty="0"/>
-                    <a:t>%s</a:t>
-                  </a:r>
-`, tr.font.Size*100, xmlEscape(tr.text)))
+						rowsXML.WriteString("                  <a:r>\n                    <a:rPr lang=\"en-US\" sz=\"")
+						rowsXML.WriteString(strconv.Itoa(tr.font.Size * 100))
+						if w.slideRels.id(tr) != "" && w.hasHyperlinkRel(tr.hyperlink) {
+							rowsXML.WriteString("\" dirty=\"0\">")
+							w.appendHyperlinkClickXML(&rowsXML, tr)
+							rowsXML.WriteString("\n                    </a:rPr>\n                    <a:t>")
+						} else {
+							rowsXML.WriteString("\" dirty=\"0\"/>\n                    <a:t>")
+						}
+						writeXMLEscaped(&rowsXML, w.normalizeText(tr.text))
+						rowsXML.WriteString("</a:t>\n                  </a:r>\n")
 					}
 				}
-				cellText.WriteString("                </a:p>\n")
+				rowsXML.WriteString("                </a:p>\n")
 			}
 
-			rowsXML.WriteString(fmt.Sprintf(`              <a:tc>
-                <a:txBody>
-                  <a:bodyPr/>
-                  <a:lstStyle/>
-%s                </a:txBody>
-                <a:tcPr>%s
-                </a:tcPr>
-              </a:tc>
-`, cellText.String(), cellFill))
+			rowsXML.WriteString("                </a:txBody>\n                <a:tcPr")
+			if cell.textDirection != "" && cell.textDirection != "horz" {
+				rowsXML.WriteString(` vert="`)
+				writeXMLEscaped(&rowsXML, cell.textDirection)
+				rowsXML.WriteByte('"')
+			}
+			if cell.marginsSet {
+				fmt.Fprintf(&rowsXML, ` marL="%d" marR="%d" marT="%d" marB="%d"`,
+					cell.marginLeft, cell.marginRight, cell.marginTop, cell.marginBottom)
+			}
+			if cell.anchor != TextAnchorNone {
+				rowsXML.WriteString(xmlAttr("anchor", string(cell.anchor)))
+			}
+			rowsXML.WriteByte('>')
+			if cell.fill != nil && cell.fill.Type == FillSolid {
+				rowsXML.WriteString("\n                  <a:solidFill>")
+				rowsXML.WriteString(colorXML(cell.fill.Color))
+				rowsXML.WriteString("</a:solidFill>")
+			} else if cell.fill != nil && cell.fill.Type == FillGradientLinear {
+				fmt.Fprintf(&rowsXML, `
+                  <a:gradFill rotWithShape="1">
+                    <a:gsLst>
+                      <a:gs pos="0">%s</a:gs>
+                      <a:gs pos="100000">%s</a:gs>
+                    </a:gsLst>
+                    <a:lin ang="%d" scaled="1"/>
+                  </a:gradFill>`, colorXML(cell.fill.Color), colorXML(cell.fill.EndColor), cell.fill.Rotation*60000)
+			} else if rid := w.slideRels.id(cell); rid != "" && hasCellPicture(cell) {
+				rowsXML.WriteString("\n                  ")
+				rowsXML.WriteString(strings.TrimSpace(w.writePictureFillXML(cell.fill, rid)))
+			}
+			rowsXML.WriteString("\n                </a:tcPr>\n              </a:tc>\n")
 		}
 		rowsXML.WriteString("            </a:tr>\n")
 	}
 
+	tblPrAttrs := ""
+	if s.firstRow {
+		tblPrAttrs += ` firstRow="1"`
+	}
+	if s.lastRow {
+		tblPrAttrs += ` lastRow="1"`
+	}
+	if s.bandRow {
+		tblPrAttrs += ` bandRow="1"`
+	}
+
 	return fmt.Sprintf(`      <p:graphicFrame>
         <p:nvGraphicFramePr>
-          <p:cNvPr id="%d" name="%s"/>
+          <p:cNvPr id="%d" name="%s"%s
           <p:cNvGraphicFramePr>
-            <a:graphicFrameLocks noGrp="1"/>
+            <a:graphicFrameLocks noGrp="1"%s/>
           </p:cNvGraphicFramePr>
-          <p:nvPr/>
+          %s
         </p:nvGraphicFramePr>
-        <p:xfrm>
+        <p:xfrm%s>
           <a:off x="%d" y="%d"/>
           <a:ext cx="%d" cy="%d"/>
         </p:xfrm>
         <a:graphic>
           <a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/table">
             <a:tbl>
-              <a:tblPr firstRow="1" bandRow="1"/>
+              <a:tblPr%s/>
               <a:tblGrid>
 %s              </a:tblGrid>
 %s            </a:tbl>
           </a:graphicData>
         </a:graphic>
       </p:graphicFrame>
-`, id, xmlEscape(name),
+`, id, xmlEscape(name), w.cNvPrEndXML(&s.BaseShape, id), s.locks.graphicFrameXMLAttrs(),
+		w.nvPrXML(&s.BaseShape),
+		xfrmAttrs(&s.BaseShape),
 		s.offsetX, s.offsetY, s.width, s.height,
-		gridCols.String(), rowsXML.String())
+		tblPrAttrs, gridCols.String(), rowsXML.String())
 }
 
 // --- Fill and Border helpers ---
@@ -722,21 +997,40 @@ func (w *PPTXWriter) writeFillXML(f *Fill) string {
 	}
 	switch f.Type {
 	case FillSolid:
-		return fmt.Sprintf("          <a:solidFill><a:srgbClr val=\"%s\"/></a:solidFill>\n", colorRGB(f.Color))
+		return fmt.Sprintf("          <a:solidFill>%s</a:solidFill>\n", colorXML(f.Color))
 	case FillGradientLinear:
 		return fmt.Sprintf(`          <a:gradFill>
             <a:gsLst>
-              <a:gs pos="0"><a:srgbClr val="%s"/></a:gs>
-              <a:gs pos="100000"><a:srgbClr val="%s"/></a:gs>
+              <a:gs pos="0">%s</a:gs>
+              <a:gs pos="100000">%s</a:gs>
             </a:gsLst>
             <a:lin ang="%d" scaled="1"/>
           </a:gradFill>
-`, colorRGB(f.Color), colorRGB(f.EndColor), f.Rotation*60000)
+`, colorXML(f.Color), colorXML(f.EndColor), f.Rotation*60000)
 	default:
 		return ""
 	}
 }
 
+// writePictureFillXML writes a blipFill referencing relID, stretched or tiled.
+// An empty tile flip or alignment is omitted, leaving the defaults "none"
+// and "tl".
+func (w *PPTXWriter) writePictureFillXML(f *Fill, relID string) string {
+	mode := "<a:stretch><a:fillRect/></a:stretch>"
+	if t := f.Tile; t != nil {
+		attrs := ""
+		if t.Flip != "" {
+			attrs += xmlAttr("flip", t.Flip)
+		}
+		if t.Alignment != "" {
+			attrs += xmlAttr("algn", t.Alignment)
+		}
+		mode = fmt.Sprintf(`<a:tile tx="%d" ty="%d" sx="%d" sy="%d"%s/>`,
+			t.OffsetX, t.OffsetY, t.ScaleX, t.ScaleY, attrs)
+	}
+	return fmt.Sprintf("          <a:blipFill dpi=\"0\" rotWithShape=\"1\"><a:blip r:embed=\"%s\"/><a:srcRect/>%s</a:blipFill>\n", relID, mode)
+}
+
 func (w *PPTXWriter) writeBorderXML(b *Border) string {
 	if b == nil || b.Style == BorderNone {
 		return ""
@@ -749,11 +1043,11 @@ func (w *PPTXWriter) writeBorderXML(b *Border) string {
 		dashXML = "<a:prstDash val=\"dot\"/>"
 	}
 	if dashXML != "" {
-		return fmt.Sprintf("          <a:ln w=\"%d\"><a:solidFill><a:srgbClr val=\"%s\"/></a:solidFill>%s</a:ln>\n",
-			b.Width, colorRGB(b.Color), dashXML)
+		return fmt.Sprintf("          <a:ln w=\"%d\"><a:solidFill>%s</a:solidFill>%s</a:ln>\n",
+			b.Width, colorXML(b.Color), dashXML)
 	}
-	return fmt.Sprintf("          <a:ln w=\"%d\"><a:solidFill><a:srgbClr val=\"%s\"/></a:solidFill></a:ln>\n",
-		b.Width, colorRGB(b.Color))
+	return fmt.Sprintf("          <a:ln w=\"%d\"><a:solidFill>%s</a:solidFill></a:ln>\n",
+		b.Width, colorXML(b.Color))
 }
 
 // --- Media ---
@@ -762,43 +1056,67 @@ func (w *PPTXWriter) writeMedia(zw *zip.Writer) error {
 	imgIdx := 1
 	for _, slide := range w.presentation.slides {
 		for _, ds := range collectDrawingShapes(slide.shapes) {
-			if ds.data != nil {
-				ext := w.getImageExtension(ds)
-				fw, err := zw.Create(fmt.Sprintf("ppt/media/image%d.%s", imgIdx, ext))
-				if err != nil {
-					return err
-				}
-				if _, err := fw.Write(ds.data); err != nil {
-					return err
-				}
-				imgIdx++
-			} else if ds.path != "" {
-				info, err := os.Stat(ds.path)
-				if err != nil {
-					return fmt.Errorf("failed to stat image %s: %w", ds.path, err)
-				}
-				if info.Size() > maxImageFileSize {
-					return fmt.Errorf("image file %s too large: %d bytes (max %d)", ds.path, info.Size(), maxImageFileSize)
-				}
-				data, err := os.ReadFile(ds.path)
-				if err != nil {
-					return fmt.Errorf("failed to read image %s: %w", ds.path, err)
-				}
-				ext := w.getImageExtension(ds)
-				fw, err := zw.Create(fmt.Sprintf("ppt/media/image%d.%s", imgIdx, ext))
-				if err != nil {
-					return err
-				}
-				if _, err := fw.Write(data); err != nil {
-					return err
-				}
-				imgIdx++
+			name := fmt.Sprintf("ppt/media/image%d.%s", imgIdx, w.getImageExtension(ds))
+			if err := w.writeImagePart(zw, name, ds); err != nil {
+				return err
+			}
+			imgIdx++
+		}
+	}
+	for i, ds := range collectDrawingShapes(w.presentation.masterShapes()) {
+		name := fmt.Sprintf("ppt/media/master%d.%s", i+1, w.getImageExtension(ds))
+		if err := w.writeImagePart(zw, name, ds); err != nil {
+			return err
+		}
+	}
+	cellIdx := 1
+	for _, slide := range w.presentation.slides {
+		for _, cell := range collectPictureCells(slide.shapes) {
+			name := fmt.Sprintf("ppt/media/cell%d.%s", cellIdx, w.getPictureFillExtension(cell.fill))
+			contentType := w.getImageContentType(&DrawingShape{mimeType: cell.fill.MimeType})
+			if err := w.writePartBytes(zw, name, contentType, cell.fill.ImageData); err != nil {
+				return err
 			}
+			cellIdx++
+		}
+	}
+	for i, slide := range w.presentation.slides {
+		if !hasBackgroundPicture(slide) {
+			continue
+		}
+		name := fmt.Sprintf("ppt/media/background%d.%s", i+1, w.getPictureFillExtension(slide.background))
+		contentType := w.getImageContentType(&DrawingShape{mimeType: slide.background.MimeType})
+		if err := w.writePartBytes(zw, name, contentType, slide.background.ImageData); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// writeImagePart writes the image of ds, held in memory or read from its
+// file, as the part name. A picture read without its media is written with
+// an empty part.
+func (w *PPTXWriter) writeImagePart(zw *zip.Writer, name string, ds *DrawingShape) error {
+	data := ds.data
+	if data == nil && ds.missingMedia != "" {
+		data = []byte{}
+	}
+	if data == nil {
+		info, err := os.Stat(ds.path)
+		if err != nil {
+			return fmt.Errorf("failed to stat image %s: %w", ds.path, err)
+		}
+		if info.Size() > maxImageFileSize {
+			return fmt.Errorf("image file %s too large: %d bytes (max %d)", ds.path, info.Size(), maxImageFileSize)
+		}
+		data, err = os.ReadFile(ds.path)
+		if err != nil {
+			return fmt.Errorf("failed to read image %s: %w", ds.path, err)
+		}
+	}
+	return w.writePartBytes(zw, name, w.getImageContentType(ds), data)
+}
+
 func (w *PPTXWriter) getChartIndex(target *ChartShape) int {
 	idx := 1
 	for _, slide := range w.presentation.slides {
@@ -825,30 +1143,40 @@ func (w *PPTXWriter) writeChartShapeXML(s *ChartShape, shapeID *int, slideNum in
 		name = fmt.Sprintf("Chart %d", id)
 	}
 
-	// Find chart rel ID — must match ordering in writeSlideRels exactly.
-	relIdx := countRelIdxBefore(w.presentation.slides[slideNum-1].shapes, s)
+	relID := w.slideRels.id(s)
+	graphicData := fmt.Sprintf(`<a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/chart">
+            <c:chart xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" r:id="%s"/>`, relID)
+	if isChartExType(s.plotArea.chartType) {
+		graphicData = fmt.Sprintf(`<a:graphicData uri="%s">
+            <cx:chart xmlns:cx="%s" r:id="%s"/>`, nsChartEx, nsChartEx, relID)
+	}
 
-	return fmt.Sprintf(`      <p:graphicFrame>
+	frameXML := fmt.Sprintf(`      <p:graphicFrame>
         <p:nvGraphicFramePr>
-          <p:cNvPr id="%d" name="%s"/>
+          <p:cNvPr id="%d" name="%s"%s
           <p:cNvGraphicFramePr>
-            <a:graphicFrameLocks noGrp="1"/>
+            <a:graphicFrameLocks noGrp="1"%s/>
           </p:cNvGraphicFramePr>
-          <p:nvPr/>
+          %s
         </p:nvGraphicFramePr>
-        <p:xfrm>
+        <p:xfrm%s>
           <a:off x="%d" y="%d"/>
           <a:ext cx="%d" cy="%d"/>
         </p:xfrm>
         <a:graphic>
-          <a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/chart">
-            <c:chart xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" r:id="rId%d"/>
+          %s
           </a:graphicData>
         </a:graphic>
       </p:graphicFrame>
-`, id, xmlEscape(name),
+`, id, xmlEscape(name), w.cNvPrEndXML(&s.BaseShape, id), s.locks.graphicFrameXMLAttrs(),
+		w.nvPrXML(&s.BaseShape),
+		xfrmAttrs(&s.BaseShape),
 		s.offsetX, s.offsetY, s.width, s.height,
-		relIdx)
+		graphicData)
+	if isChartExType(s.plotArea.chartType) {
+		return chartExFrameXML(s.plotArea.chartType, frameXML, id, name, &s.BaseShape)
+	}
+	return frameXML
 }
 
 // --- Group Shape XML ---
@@ -877,14 +1205,17 @@ func (w *PPTXWriter) writeGroupShapeXML(g *GroupShape, shapeID *int, slideNum in
 			childXML.WriteString(w.writeDrawingShapeXML(s, shapeID, slideNum))
 		case *TableShape:
 			childXML.WriteString(w.writeTableShapeXML(s, shapeID))
+		case *GroupShape:
+			childXML.WriteString(w.writeGroupShapeXML(s, shapeID, slideNum))
 		}
 	}
 
+	chOffX, chOffY, chExtX, chExtY := g.childSpace()
 	return fmt.Sprintf(`      <p:grpSp>
         <p:nvGrpSpPr>
-          <p:cNvPr id="%d" name="%s"/>
+          <p:cNvPr id="%d" name="%s"%s
           <p:cNvGrpSpPr/>
-          <p:nvPr/>
+          %s
         </p:nvGrpSpPr>
         <p:grpSpPr>
           <a:xfrm%s>
@@ -895,10 +1226,11 @@ func (w *PPTXWriter) writeGroupShapeXML(g *GroupShape, shapeID *int, slideNum in
           </a:xfrm>
         </p:grpSpPr>
 %s      </p:grpSp>
-`, id, xmlEscape(name),
+`, id, xmlEscape(name), w.cNvPrEndXML(&g.BaseShape, id),
+		w.nvPrXML(&g.BaseShape),
 		xfrmAttrs(&g.BaseShape),
 		g.offsetX, g.offsetY, g.width, g.height,
-		g.offsetX, g.offsetY, g.width, g.height,
+		chOffX, chOffY, chExtX, chExtY,
 		childXML.String())
 }
 
@@ -914,18 +1246,16 @@ func (w *PPTXWriter) writePlaceholderShapeXML(s *PlaceholderShape, shapeID *int)
 	}
 
 	var paragraphsXML strings.Builder
-	for _, para := range s.paragraphs {
-		paragraphsXML.WriteString(w.writeParagraphXML(para))
+	for _, para := range resolveListStyle(&s.listStyle, s.paragraphs, true) {
+		w.appendParagraphXML(&paragraphsXML, para)
 	}
 
 	return fmt.Sprintf(`      <p:sp>
         <p:nvSpPr>
-          <p:cNvPr id="%d" name="%s"/>
-          <p:cNvSpPr>
-            <a:spLocks noGrp="1"/>
-          </p:cNvSpPr>
+          <p:cNvPr id="%d" name="%s"%s
+          %s
           <p:nvPr>
-            <p:ph type="%s" idx="%d"/>
+            <p:ph type="%s" idx="%d"/>%s
           </p:nvPr>
         </p:nvSpPr>
         <p:spPr>
@@ -933,22 +1263,128 @@ func (w *PPTXWriter) writePlaceholderShapeXML(s *PlaceholderShape, shapeID *int)
             <a:off x="%d" y="%d"/>
             <a:ext cx="%d" cy="%d"/>
           </a:xfrm>
-        </p:spPr>
+%s        </p:spPr>
         <p:txBody>
           <a:bodyPr/>
-          <a:lstStyle/>
-%s        </p:txBody>
+%s%s        </p:txBody>
       </p:sp>
-`, id, xmlEscape(name),
-		s.phType, s.phIdx,
+`, id, xmlEscape(name), w.cNvPrEndXML(&s.BaseShape, id),
+		nvLocksXML("p:cNvSpPr", "", "a:spLocks", ` noGrp="1"`, s.locks.xmlAttrs(true)),
+		xmlEscape(string(s.phType)), s.phIdx, w.custDataXML(&s.BaseShape)+s.extLst.nvPr,
 		xfrmAttrs(&s.BaseShape),
 		s.offsetX, s.offsetY, s.width, s.height,
-		paragraphsXML.String())
+		scene3DXML(s.scene3d),
+		w.writeListStyleXML(&s.listStyle), paragraphsXML.String())
+}
+
+// defRPrXML returns an <a:defRPr> element for the set (non-zero) properties of f.
+func defRPrXML(f *Font) string {
+	attrs := ""
+	if f.Size > 0 {
+		attrs += fmt.Sprintf(` sz="%d"`, f.Size*100)
+	}
+	if f.Bold {
+		attrs += ` b="1"`
+	}
+	if f.Italic {
+		attrs += ` i="1"`
+	}
+	children := ""
+	if f.Color.ARGB != "" {
+		children += fmt.Sprintf(`<a:solidFill>%s</a:solidFill>`, colorXML(f.Color))
+	}
+	if f.Name != "" {
+		children += fmt.Sprintf(`<a:latin typeface="%s"/>`, xmlEscape(f.Name))
+	}
+	return fmt.Sprintf(`<a:defRPr%s>%s</a:defRPr>`, attrs, children)
+}
+
+// writeListStyleXML returns the <a:lstStyle> element for a text body,
+// including one lvlNpPr per defined list level.
+func (w *PPTXWriter) writeListStyleXML(levels *[9]*ListLevelStyle) string {
+	var sb strings.Builder
+	for i, l := range levels {
+		if l == nil {
+			continue
+		}
+		attrs := ""
+		if l.Alignment != nil {
+			if l.Alignment.MarginLeft != 0 {
+				attrs += fmt.Sprintf(` marL="%d"`, l.Alignment.MarginLeft)
+			}
+			if l.Alignment.Indent != 0 {
+				attrs += fmt.Sprintf(` indent="%d"`, l.Alignment.Indent)
+			}
+			if l.Alignment.Horizontal != "" {
+				attrs += xmlAttr("algn", string(l.Alignment.Horizontal))
+			}
+		}
+		bulletXML := ""
+		if l.Bullet != nil {
+			bulletXML = w.writeBulletXML(l.Bullet)
+		}
+		defRPr := ""
+		if l.Font != nil {
+			defRPr = "\n              " + defRPrXML(l.Font)
+		}
+		sb.WriteString(fmt.Sprintf(`            <a:lvl%dpPr%s>%s%s
+            </a:lvl%dpPr>
+`, i+1, attrs, bulletXML, defRPr, i+1))
+	}
+	if sb.Len() == 0 {
+		return "          <a:lstStyle/>\n"
+	}
+	return "          <a:lstStyle>\n" + sb.String() + "          </a:lstStyle>\n"
 }
 
 // --- Notes Slide ---
 
+// writeNotesSlide writes the notes slide of a slide: the part read with it
+// when the notes are unchanged, otherwise a notes slide holding the notes.
 func (w *PPTXWriter) writeNotesSlide(zw *zip.Writer, slide *Slide, slideNum int) error {
+	name := fmt.Sprintf("ppt/notesSlides/notesSlide%d.xml", slideNum)
+	relsName := fmt.Sprintf("ppt/notesSlides/_rels/notesSlide%d.xml.rels", slideNum)
+	slideTarget := fmt.Sprintf("../slides/slide%d.xml", slideNum)
+	rels := newRelRegistry()
+
+	if src := slide.notesSource; src.unchanged(slide.notes) {
+		for _, rel := range src.rels {
+			out := xmlRelationship{ID: rel.ID, Type: rel.Type, Target: rel.Target, TargetMode: rel.TargetMode}
+			switch {
+			case rel.Type == relTypeNotesMaster:
+				out.Target = "../notesMasters/notesMaster1.xml"
+			case rel.ID == src.slideRelID:
+				out.Target = slideTarget
+			}
+			if err := rels.reserveRel(rel.ID, out); err != nil {
+				return err
+			}
+		}
+		if err := w.writePartBytes(zw, name, ctNotesSlide, src.xml); err != nil {
+			return err
+		}
+		return w.writeRels(zw, relsName, rels)
+	}
+
+	rels.add(relKeyMaster, relTypeNotesMaster, "../notesMasters/notesMaster1.xml")
+	rels.add(slide, relTypeSlide, slideTarget)
+	for _, para := range slide.notes {
+		for _, elem := range para.elements {
+			if tr, ok := elem.(*TextRun); ok {
+				w.addHyperlinkRel(rels, tr, "../slides/")
+			}
+		}
+	}
+
+	// Runs look up their hyperlinks, and slide number fields their slide
+	prevRels, prevIndex := w.slideRels, w.slideIndex
+	w.slideRels, w.slideIndex = rels, slideNum-1
+	var paras strings.Builder
+	for _, para := range slide.notes {
+		w.appendParagraphRunsXML(&paras, para, w.appendNotesRunXML)
+	}
+	w.slideRels, w.slideIndex = prevRels, prevIndex
+
 	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
 <p:notes xmlns:a="%s" xmlns:r="%s" xmlns:p="%s">
   <p:cSld>
@@ -968,7 +1404,19 @@ func (w *PPTXWriter) writeNotesSlide(zw *zip.Writer, slide *Slide, slideNum int)
       </p:grpSpPr>
       <p:sp>
         <p:nvSpPr>
-          <p:cNvPr id="2" name="Notes Placeholder"/>
+          <p:cNvPr id="2" name="Slide Image Placeholder 1"/>
+          <p:cNvSpPr>
+            <a:spLocks noGrp="1" noRot="1" noChangeAspect="1"/>
+          </p:cNvSpPr>
+          <p:nvPr>
+            <p:ph type="sldImg"/>
+          </p:nvPr>
+        </p:nvSpPr>
+        <p:spPr/>
+      </p:sp>
+      <p:sp>
+        <p:nvSpPr>
+          <p:cNvPr id="3" name="Notes Placeholder 2"/>
           <p:cNvSpPr>
             <a:spLocks noGrp="1"/>
           </p:cNvSpPr>
@@ -980,28 +1428,16 @@ func (w *PPTXWriter) writeNotesSlide(zw *zip.Writer, slide *Slide, slideNum int)
         <p:txBody>
           <a:bodyPr/>
           <a:lstStyle/>
-          <a:p>
-            <a:r>
-              <a:rPr lang="en-US" dirty="0"/>
-              <a:t>%s</a:t>
-            </a:r>
-          </a:p>
-        </p:txBody>
+%s        </p:txBody>
       </p:sp>
     </p:spTree>
   </p:cSld>
-</p:notes>`, nsDrawingML, nsOfficeDocRels, nsPresentationML, xmlEscape(slide.notes))
+</p:notes>`, nsDrawingML, nsOfficeDocRels, nsPresentationML, paras.String())
 
-	if err := writeRawXMLToZip(zw, fmt.Sprintf("ppt/notesSlides/notesSlide%d.xml", slideNum), content); err != nil {
+	if err := w.writeRawPart(zw, name, ctNotesSlide, content); err != nil {
 		return err
 	}
-
-	// Notes slide rels
-	rels := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
-<Relationships xmlns="%s">
-  <Relationship Id="rId1" Type="%s" Target="../slides/slide%d.xml"/>
-</Relationships>`, nsRelationships, relTypeSlide, slideNum)
-	return writeRawXMLToZip(zw, fmt.Sprintf("ppt/notesSlides/_rels/notesSlide%d.xml.rels", slideNum), rels)
+	return w.writeRels(zw, relsName, rels)
 }
 
 // --- Bullet XML ---
@@ -1015,7 +1451,7 @@ func (w *PPTXWriter) writeBulletXML(b *Bullet) string {
 
 	// Bullet color
 	if b.Color != nil {
-		sb.WriteString(fmt.Sprintf("\n              <a:buClr><a:srgbClr val=\"%s\"/></a:buClr>", colorRGB(*b.Color)))
+		sb.WriteString(fmt.Sprintf("\n              <a:buClr>%s</a:buClr>", colorXML(*b.Color)))
 	}
 
 	// Bullet size
@@ -1032,7 +1468,7 @@ func (w *PPTXWriter) writeBulletXML(b *Bullet) string {
 		sb.WriteString(fontAttr)
 		sb.WriteString(fmt.Sprintf("\n              <a:buChar char=\"%s\"/>", xmlEscape(b.Style)))
 	case BulletTypeNumeric:
-		sb.WriteString(fmt.Sprintf("\n              <a:buAutoNum type=\"%s\" startAt=\"%d\"/>", b.NumFormat, b.StartAt))
+		sb.WriteString(fmt.Sprintf("\n              <a:buAutoNum type=\"%s\" startAt=\"%d\"/>", xmlEscape(b.NumFormat), b.StartAt))
 	}
 
 	return sb.String()
@@ -2,10 +2,13 @@ package gopresentation
 
 import (
 	"archive/zip"
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // XML namespace constants
@@ -35,10 +38,16 @@ const (
 	relTypeImage       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
 	relTypeHyperlink   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
 	relTypeChart       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/chart"
+	relTypeChartEx     = "http://schemas.microsoft.com/office/2014/relationships/chartEx"
 	relTypeComment     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
 	relTypeCommentAuth = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/commentAuthors"
 	relTypeNotesSlide  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide"
 	relTypeNotesMaster = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesMaster"
+	relTypeVBAProject  = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
+
+	relTypeVBASignature      = "http://schemas.microsoft.com/office/2006/relationships/vbaProjectSignature"
+	relTypeVBASignatureAgile = "http://schemas.microsoft.com/office/2014/relationships/vbaProjectSignatureAgile"
+	relTypeVBASignatureV3    = "http://schemas.microsoft.com/office/2020/07/relationships/vbaProjectSignatureV3"
 
 	ctPresentation     = "application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"
 	ctSlide            = "application/vnd.openxmlformats-officedocument.presentationml.slide+xml"
@@ -52,36 +61,64 @@ const (
 	ctExtProps         = "application/vnd.openxmlformats-officedocument.extended-properties+xml"
 	ctRels             = "application/vnd.openxmlformats-package.relationships+xml"
 	ctChart            = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
+	ctChartEx          = "application/vnd.ms-office.chartex+xml"
 	ctComments         = "application/vnd.openxmlformats-officedocument.presentationml.comments+xml"
 	ctCommentAuthors   = "application/vnd.openxmlformats-officedocument.presentationml.commentAuthors+xml"
 	ctNotesSlide       = "application/vnd.openxmlformats-officedocument.presentationml.notesSlide+xml"
+	ctVBAProject       = "application/vnd.ms-office.vbaProject"
 )
 
-func writeXMLToZip(zw *zip.Writer, path string, v interface{}) error {
-	fw, err := zw.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create %s in zip: %w", path, err)
+// writeXMLPart marshals v as the part name with the given content type.
+func (w *PPTXWriter) writeXMLPart(zw *zip.Writer, name, contentType string, v interface{}) error {
+	var buf bytes.Buffer
+	if err := encodeXML(&buf, name, v); err != nil {
+		return err
 	}
+	return w.writePartBytes(zw, name, contentType, buf.Bytes())
+}
+
+// encodeXML writes the XML header and v, indented, to fw.
+func encodeXML(fw io.Writer, name string, v interface{}) error {
 	if _, err := fw.Write([]byte(xml.Header)); err != nil {
 		return err
 	}
 	enc := xml.NewEncoder(fw)
 	enc.Indent("", "  ")
 	if err := enc.Encode(v); err != nil {
-		return fmt.Errorf("failed to encode %s: %w", path, err)
+		return fmt.Errorf("failed to encode %s: %w", name, err)
 	}
 	return nil
 }
 
-func writeRawXMLToZip(zw *zip.Writer, path string, content string) error {
-	fw, err := zw.Create(path)
+// writeRawPart writes already assembled XML as the part name.
+func (w *PPTXWriter) writeRawPart(zw *zip.Writer, name, contentType, content string) error {
+	return w.writePartBytes(zw, name, contentType, []byte(content))
+}
+
+// writePartBytes writes already assembled content as the part name, after
+// the transforms registered for it by OnBeforeWrite hooks.
+func (w *PPTXWriter) writePartBytes(zw *zip.Writer, name, contentType string, content []byte) error {
+	content, err := w.pkg.transform(name, content)
 	if err != nil {
-		return fmt.Errorf("failed to create %s in zip: %w", path, err)
+		return err
 	}
-	_, err = fw.Write([]byte(content))
+	fw, err := w.createPart(zw, name, contentType)
+	if err != nil {
+		return err
+	}
+	w.partWritten(name, len(content))
+	_, err = fw.Write(content)
 	return err
 }
 
+// partWritten logs and counts the part name of n bytes.
+func (w *PPTXWriter) partWritten(name string, n int) {
+	w.logger().Debug("wrote part", "part", name, "bytes", n)
+	m := w.options().Metrics
+	countMetric(m, MetricPartsWritten, 1)
+	countMetric(m, MetricBytesWritten, int64(n))
+}
+
 // --- Content Types ---
 
 type xmlContentTypes struct {
@@ -101,97 +138,6 @@ type xmlOverride struct {
 	ContentType string `xml:"ContentType,attr"`
 }
 
-func (w *PPTXWriter) writeContentTypes(zw *zip.Writer) error {
-	ct := xmlContentTypes{
-		Xmlns: nsContentTypes,
-		Defaults: []xmlDefault{
-			{Extension: "rels", ContentType: ctRels},
-			{Extension: "xml", ContentType: "application/xml"},
-		},
-		Overrides: []xmlOverride{
-			{PartName: "/ppt/presentation.xml", ContentType: ctPresentation},
-			{PartName: "/ppt/presProps.xml", ContentType: ctPresProps},
-			{PartName: "/ppt/viewProps.xml", ContentType: ctViewProps},
-			{PartName: "/ppt/tableStyles.xml", ContentType: ctTableStyles},
-			{PartName: "/ppt/slideMasters/slideMaster1.xml", ContentType: ctSlideMaster},
-			{PartName: "/ppt/slideLayouts/slideLayout1.xml", ContentType: ctSlideLayout},
-			{PartName: "/ppt/theme/theme1.xml", ContentType: ctTheme},
-			{PartName: "/docProps/core.xml", ContentType: ctCoreProps},
-			{PartName: "/docProps/app.xml", ContentType: ctExtProps},
-		},
-	}
-
-	// Add slide content types
-	for i := range w.presentation.slides {
-		ct.Overrides = append(ct.Overrides, xmlOverride{
-			PartName:    fmt.Sprintf("/ppt/slides/slide%d.xml", i+1),
-			ContentType: ctSlide,
-		})
-	}
-
-	// Add image defaults
-	for _, slide := range w.presentation.slides {
-		for _, ds := range collectDrawingShapes(slide.shapes) {
-			ext := w.getImageExtension(ds)
-			found := false
-			for _, d := range ct.Defaults {
-				if d.Extension == ext {
-					found = true
-					break
-				}
-			}
-			if !found {
-				ct.Defaults = append(ct.Defaults, xmlDefault{
-					Extension:   ext,
-					ContentType: w.getImageContentType(ds),
-				})
-			}
-		}
-	}
-
-	// Add chart content types
-	chartIdx := 1
-	for _, slide := range w.presentation.slides {
-		for _, shape := range slide.shapes {
-			if _, ok := shape.(*ChartShape); ok {
-				ct.Overrides = append(ct.Overrides, xmlOverride{
-					PartName:    fmt.Sprintf("/ppt/charts/chart%d.xml", chartIdx),
-					ContentType: ctChart,
-				})
-				chartIdx++
-			}
-		}
-	}
-
-	// Add comment content types
-	if w.hasComments() {
-		ct.Overrides = append(ct.Overrides, xmlOverride{
-			PartName:    "/ppt/commentAuthors.xml",
-			ContentType: ctCommentAuthors,
-		})
-		for i, slide := range w.presentation.slides {
-			if len(slide.comments) > 0 {
-				ct.Overrides = append(ct.Overrides, xmlOverride{
-					PartName:    fmt.Sprintf("/ppt/comments/comment%d.xml", i+1),
-					ContentType: ctComments,
-				})
-			}
-		}
-	}
-
-	// Add notes slide content types
-	for i, slide := range w.presentation.slides {
-		if slide.notes != "" {
-			ct.Overrides = append(ct.Overrides, xmlOverride{
-				PartName:    fmt.Sprintf("/ppt/notesSlides/notesSlide%d.xml", i+1),
-				ContentType: ctNotesSlide,
-			})
-		}
-	}
-
-	return writeXMLToZip(zw, "[Content_Types].xml", ct)
-}
-
 func (w *PPTXWriter) getImageExtension(ds *DrawingShape) string {
 	if ds.mimeType != "" {
 		switch ds.mimeType {
@@ -254,83 +200,11 @@ type xmlRelationship struct {
 }
 
 func (w *PPTXWriter) writeRootRels(zw *zip.Writer) error {
-	rels := xmlRelationships{
-		Xmlns: nsRelationships,
-		Relationships: []xmlRelationship{
-			{ID: "rId1", Type: relTypeOfficeDoc, Target: "ppt/presentation.xml"},
-			{ID: "rId2", Type: relTypeCoreProps, Target: "docProps/core.xml"},
-			{ID: "rId3", Type: relTypeExtProps, Target: "docProps/app.xml"},
-		},
-	}
-	return writeXMLToZip(zw, "_rels/.rels", rels)
-}
-
-func (w *PPTXWriter) writePresentationRels(zw *zip.Writer) error {
-	rels := xmlRelationships{
-		Xmlns: nsRelationships,
-	}
-
-	relIdx := 1
-	// Slide master
-	rels.Relationships = append(rels.Relationships, xmlRelationship{
-		ID:   fmt.Sprintf("rId%d", relIdx),
-		Type: relTypeSlideMaster,
-		Target: "slideMasters/slideMaster1.xml",
-	})
-	relIdx++
-
-	// Slides
-	for i := range w.presentation.slides {
-		rels.Relationships = append(rels.Relationships, xmlRelationship{
-			ID:   fmt.Sprintf("rId%d", relIdx),
-			Type: relTypeSlide,
-			Target: fmt.Sprintf("slides/slide%d.xml", i+1),
-		})
-		relIdx++
-	}
-
-	// PresProps
-	rels.Relationships = append(rels.Relationships, xmlRelationship{
-		ID:   fmt.Sprintf("rId%d", relIdx),
-		Type: relTypePresProps,
-		Target: "presProps.xml",
-	})
-	relIdx++
-
-	// ViewProps
-	rels.Relationships = append(rels.Relationships, xmlRelationship{
-		ID:   fmt.Sprintf("rId%d", relIdx),
-		Type: relTypeViewProps,
-		Target: "viewProps.xml",
-	})
-	relIdx++
-
-	// TableStyles
-	rels.Relationships = append(rels.Relationships, xmlRelationship{
-		ID:   fmt.Sprintf("rId%d", relIdx),
-		Type: relTypeTableStyles,
-		Target: "tableStyles.xml",
-	})
-	relIdx++
-
-	// Theme
-	rels.Relationships = append(rels.Relationships, xmlRelationship{
-		ID:   fmt.Sprintf("rId%d", relIdx),
-		Type: relTypeTheme,
-		Target: "theme/theme1.xml",
-	})
-	relIdx++
-
-	// Comment authors
-	if w.hasComments() {
-		rels.Relationships = append(rels.Relationships, xmlRelationship{
-			ID:   fmt.Sprintf("rId%d", relIdx),
-			Type: relTypeCommentAuth,
-			Target: "commentAuthors.xml",
-		})
+	rels, err := w.buildRootRels()
+	if err != nil {
+		return err
 	}
-
-	return writeXMLToZip(zw, "ppt/_rels/presentation.xml.rels", rels)
+	return w.writeRels(zw, "_rels/.rels", rels)
 }
 
 // --- App Properties ---
@@ -344,7 +218,7 @@ func (w *PPTXWriter) writeAppProperties(zw *zip.Writer) error {
   <AppVersion>%s</AppVersion>
   <Slides>%d</Slides>
 </Properties>`, nsExtProperties, Version, xmlEscape(props.Company), Version, len(w.presentation.slides))
-	return writeRawXMLToZip(zw, "docProps/app.xml", content)
+	return w.writeRawPart(zw, "docProps/app.xml", ctExtProps, content)
 }
 
 // --- Core Properties ---
@@ -376,11 +250,19 @@ func (w *PPTXWriter) writeCoreProperties(zw *zip.Writer) error {
 		props.Created.UTC().Format("2006-01-02T15:04:05Z"),
 		props.Modified.UTC().Format("2006-01-02T15:04:05Z"),
 	)
-	return writeRawXMLToZip(zw, "docProps/core.xml", content)
+	return w.writeRawPart(zw, "docProps/core.xml", ctCoreProps, content)
 }
 
-// xmlEscape escapes special XML characters using the standard library.
+// xmlEscape escapes special XML characters using the standard library,
+// replacing characters XML does not allow with U+FFFD. Every string from
+// the caller or a read package, including values of enum-like string types
+// such as TextAnchorType, is written through it, xmlAttr or
+// writeXMLEscaped: none are validated, and a quote would end an attribute
+// early.
 func xmlEscape(s string) string {
+	if !needsXMLEscape(s) {
+		return s
+	}
 	var b strings.Builder
 	if err := xml.EscapeText(&b, []byte(s)); err != nil {
 		// EscapeText writing to strings.Builder never fails, but handle gracefully.
@@ -389,14 +271,128 @@ func xmlEscape(s string) string {
 	return b.String()
 }
 
+// xmlAttr returns the attribute name="value", preceded by a space, with
+// value escaped.
+func xmlAttr(name, value string) string {
+	return " " + name + `="` + xmlEscape(value) + `"`
+}
+
+// isXMLLetters reports whether s is a non-empty run of ASCII letters, as
+// the names of the elements written from caller values, such as color
+// transforms, must be: element names cannot be escaped.
+func isXMLLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// writeXMLEscaped writes s to sb with XML special characters escaped,
+// without an intermediate string when s needs no escaping.
+func writeXMLEscaped(sb *strings.Builder, s string) {
+	if !needsXMLEscape(s) {
+		sb.WriteString(s)
+		return
+	}
+	xml.EscapeText(sb, []byte(s))
+}
+
+// needsXMLEscape reports whether xml.EscapeText could change s. Non-ASCII
+// text is always passed to EscapeText so invalid characters are replaced.
+func needsXMLEscape(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c < 0x20, c >= 0x80, c == '"', c == '\'', c == '&', c == '<', c == '>':
+			return true
+		}
+	}
+	return false
+}
+
+// xmlBufferPool recycles the buffers large parts such as slides are
+// assembled in, so decks with many slides do not regrow one per part.
+var xmlBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getXMLBuffer returns an empty buffer from xmlBufferPool.
+func getXMLBuffer() *bytes.Buffer {
+	return xmlBufferPool.Get().(*bytes.Buffer)
+}
+
+// putXMLBuffer returns buf to xmlBufferPool. Very large buffers are dropped
+// rather than pinned in the pool.
+func putXMLBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > 16<<20 {
+		return
+	}
+	buf.Reset()
+	xmlBufferPool.Put(buf)
+}
+
+// colorXML returns the color element for c (a:srgbClr, a:schemeClr, a:sysClr or a:prstClr)
+// including its transforms.
+// An alpha byte below FF in the base color is written as an alpha transform.
+func colorXML(c Color) string {
+	var mods strings.Builder
+	argb, transforms := c.transformed()
+	if c.nTransforms > 0 && transforms == nil {
+		// ARGB was changed after the transforms were applied
+		c = Color{ARGB: argb}
+	} else {
+		c = Color{ARGB: argb, System: c.System, Preset: c.Preset, Scheme: c.Scheme}
+	}
+	if len(c.ARGB) == 8 && !strings.HasPrefix(c.ARGB, "FF") {
+		fmt.Fprintf(&mods, `<a:alpha val="%d"/>`, int(parseHexByte(c.ARGB, 0))*100000/255)
+	}
+	for _, t := range transforms {
+		if !isXMLLetters(string(t.Type)) {
+			continue
+		}
+		fmt.Fprintf(&mods, `<a:%s val="%d"/>`, t.Type, t.Value)
+	}
+	var open string
+	closeTag := "</a:srgbClr>"
+	switch {
+	case c.Scheme != "":
+		open = fmt.Sprintf(`<a:schemeClr val="%s"`, xmlEscape(c.Scheme))
+		closeTag = "</a:schemeClr>"
+	case c.System != "":
+		open = fmt.Sprintf(`<a:sysClr val="%s" lastClr="%s"`, xmlEscape(c.System), colorRGB(c))
+		closeTag = "</a:sysClr>"
+	case c.Preset != "":
+		open = fmt.Sprintf(`<a:prstClr val="%s"`, xmlEscape(c.Preset))
+		closeTag = "</a:prstClr>"
+	default:
+		open = fmt.Sprintf(`<a:srgbClr val="%s"`, colorRGB(c))
+	}
+	if mods.Len() == 0 {
+		return open + "/>"
+	}
+	return open + ">" + mods.String() + closeTag
+}
+
 // colorRGB safely extracts the 6-character RGB portion from an 8-character ARGB string.
 // Returns "000000" if the input is invalid.
 func colorRGB(c Color) string {
-	if len(c.ARGB) >= 8 {
-		return c.ARGB[2:]
+	var rgb string
+	switch {
+	case len(c.ARGB) >= 8:
+		rgb = c.ARGB[2:]
+	case len(c.ARGB) == 6:
+		rgb = c.ARGB
+	default:
+		return "000000"
 	}
-	if len(c.ARGB) == 6 {
-		return c.ARGB
+	for i := 0; i < len(rgb); i++ {
+		if hexVal(rgb[i]) < 0 {
+			return "000000"
+		}
 	}
-	return "000000"
+	return rgb
 }